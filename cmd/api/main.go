@@ -1,22 +1,32 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"net/http"
-	"os"
+	"time"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/broker"
 	"github.com/Temoojeen/sez-vision-backend/internal/config"
+	"github.com/Temoojeen/sez-vision-backend/internal/database"
+	"github.com/Temoojeen/sez-vision-backend/internal/gql"
 	"github.com/Temoojeen/sez-vision-backend/internal/handlers"
+	"github.com/Temoojeen/sez-vision-backend/internal/mailer"
 	"github.com/Temoojeen/sez-vision-backend/internal/middleware"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
+	"github.com/Temoojeen/sez-vision-backend/internal/realtime"
 	"github.com/Temoojeen/sez-vision-backend/internal/repository"
 	"github.com/Temoojeen/sez-vision-backend/internal/service"
+	"github.com/Temoojeen/sez-vision-backend/internal/sms"
+	"github.com/Temoojeen/sez-vision-backend/internal/storage"
+	"github.com/Temoojeen/sez-vision-backend/internal/weather"
+	"github.com/Temoojeen/sez-vision-backend/internal/webpush"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -29,185 +39,1154 @@ func main() {
 
 	// Загружаем конфигурацию
 	cfg := config.LoadConfig()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("❌ Invalid configuration:\n%v", err)
+	}
 
-	// Формируем строку подключения
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.SSLMode,
-	)
-
-	log.Printf("🔌 Connecting to database: %s@%s:%s/%s",
-		cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	// Права ролей на просмотр чувствительных полей
+	permissions.Configure(cfg.RolePermissions)
 
-	// Подключаемся к базе данных
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// Подключаемся к базе данных выбранного диалекта (DB_DRIVER)
+	db, dbDescription, err := database.Open(cfg)
 	if err != nil {
 		log.Fatal("❌ Failed to connect to database:", err)
 	}
 
-	log.Println("✅ Successfully connected to PostgreSQL!")
+	log.Printf("✅ Successfully connected to %s", dbDescription)
+	if cfg.DBPgBouncer {
+		log.Println("✅ PgBouncer mode: prepared statement cache disabled")
+	}
+
+	// Реплика только для чтения (DB_REPLICA_DSN) для публичной страницы подстанции
+	replicaDB, err := database.OpenReplica(cfg)
+	if err != nil {
+		log.Fatal("❌ Failed to connect to read replica:", err)
+	}
+	if replicaDB != nil {
+		log.Println("✅ Successfully connected to read replica")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("❌ Failed to get database handle:", err)
+	}
+
+	// Признак критичных ячеек заполняется по типу ячейки при первом появлении колонки
+	criticalColumnExists := db.Migrator().HasColumn(&models.Cell{}, "is_critical")
+	// Пары ячеек ВН/НН заполняются по номерам так же, один раз
+	pairedColumnExists := db.Migrator().HasColumn(&models.Cell{}, "paired_cell_id")
+	// Нумерация документов переводится на серии организаций один раз
+	numberingOrganizationExists := db.Migrator().HasColumn(&models.DocumentNumberSequence{}, "organization_id")
 
 	// Автомиграция для моделей
 	err = db.AutoMigrate(
 		&models.User{},
+		&models.EmailVerification{},
+		&models.Substation{},
 		&models.RUInfo{},
 		&models.Cell{},
 		&models.OperationRecord{},
+		&models.NotificationRule{},
+		&models.CalendarDay{},
+		&models.OutboxEvent{},
+		&models.BrokerDeliveryStats{},
+		&models.JobLock{},
+		&models.Alarm{},
+		&models.SavedAlarmFilter{},
+		&models.CellLock{},
+		&models.PollingPause{},
+		&models.StatusConfirmation{},
+		&models.Measurement{},
+		&models.MeasurementRollup{},
+		&models.CellInfoChange{},
+		&models.CellRevision{},
+		&models.Defect{},
+		&models.Photo{},
+		&models.ChecklistTemplate{},
+		&models.DocumentType{},
+		&models.DocumentNumberSequence{},
+		&models.IssuedDocumentNumber{},
+		&models.ChecklistItem{},
+		&models.Inspection{},
+		&models.InspectionResult{},
+		&models.Asset{},
+		&models.AssetEvent{},
+		&models.Warehouse{},
+		&models.InventoryItem{},
+		&models.StockLevel{},
+		&models.Reservation{},
+		&models.StockMovement{},
+		&models.FaultEvent{},
+		&models.ComtradeRecord{},
+		&models.ComtradeChannel{},
+		&models.ComtradeFile{},
+		&models.Device{},
+		&models.DeviceCell{},
+		&models.ControlCommand{},
+		&models.ControlCommandStep{},
+		&models.ReadOnlyMode{},
+		&models.Setting{},
+		&models.Organization{},
+		&models.AuditEntry{},
+		&models.HashChain{},
+		&models.OperationCorrection{},
+		&models.CorrectedField{},
+		&models.LoginEvent{},
+		&models.Notification{},
+		&models.Subscription{},
+		&models.UserSubstation{},
+		&models.AlarmEscalation{},
+		&models.WeatherObservation{},
+		&models.ForecastRun{},
+		&models.ForecastPoint{},
+		&models.CellBaseline{},
+		&models.SectionUtilization{},
+		&models.Section{},
+		&models.Transformer{},
+		&models.TapChange{},
+		&models.SwitchingOrder{},
+		&models.SwitchingStep{},
+		&models.MeterReading{},
+		&models.VoltageMeasurement{},
+		&models.Consumer{},
+		&models.ConsumerFeeder{},
+		&models.PlannedOutage{},
+		&models.OutageCell{},
+		&models.ConsumerNotification{},
+		&models.SmsMessage{},
+		&models.PushSubscription{},
+		&models.CalendarEntry{},
+		&models.CalendarFeedToken{},
+		&models.SyncItem{},
+		&models.VisionReading{},
+		&models.VisionIndication{},
+		&models.ThermalSnapshot{},
+		&models.ThermalHotspot{},
+		&models.SeedVersion{},
+		&models.DateFormatUsage{},
 	)
 	if err != nil {
 		log.Fatal("❌ Failed to auto migrate:", err)
 	}
 	log.Println("✅ Database tables migrated successfully!")
 
+	// Организация по умолчанию для данных, созданных до разделения на организации
+	if err := repository.EnsureDefaultOrganization(db); err != nil {
+		log.Fatal("❌ Failed to create default organization:", err)
+	}
+	if err := repository.BackfillAssetOrganizations(db); err != nil {
+		log.Printf("⚠️ Failed to backfill asset organizations: %v", err)
+	}
+	if !numberingOrganizationExists {
+		if err := repository.MigrateDocumentNumbering(db); err != nil {
+			log.Fatal("❌ Failed to migrate document numbering to organizations:", err)
+		}
+	}
+
 	// Проверяем существование тестовых данных
 	checkAndSeedTestData(db)
 
+	// Ссылочная целостность: уникальные номера ячеек и внешние ключи РУ
+	if err := repository.BackfillSubstations(db); err != nil {
+		log.Printf("⚠️ Failed to backfill substations: %v", err)
+	}
+	if err := repository.EnsureIntegrityConstraints(db); err != nil {
+		log.Printf("⚠️ Failed to ensure integrity constraints: %v", err)
+	}
+
+	// Заполняем типизированные даты у записей со строковыми датами
+	if err := repository.BackfillTimestamps(db); err != nil {
+		log.Printf("⚠️ Failed to backfill typed dates: %v", err)
+	}
+	// Справочник видов документов для записей журнала
+	if err := repository.SeedDocumentTypes(db); err != nil {
+		log.Printf("⚠️ Failed to seed document types: %v", err)
+	}
+	// Статусы РУ: прежний текст в коды, нештатный статус - ручная установка
+	if err := repository.BackfillRuStatus(db); err != nil {
+		log.Printf("⚠️ Failed to backfill RU status: %v", err)
+	}
+	// Секции шин по номерам секций ячеек
+	if err := repository.SyncSections(db, ""); err != nil {
+		log.Printf("⚠️ Failed to sync bus sections: %v", err)
+	}
+	// Трансформаторы по ячейкам трансформаторов
+	if err := repository.SyncTransformers(db, ""); err != nil {
+		log.Printf("⚠️ Failed to sync transformers: %v", err)
+	}
+	// Важность записей журнала: свободный текст старых записей в перечень
+	if err := repository.BackfillRecordSeverity(db); err != nil {
+		log.Printf("⚠️ Failed to backfill history severity: %v", err)
+	}
+	// Приоритет и звуковой сигнал аварий, зарегистрированных до их появления
+	if err := repository.BackfillAlarmAnnunciation(db); err != nil {
+		log.Printf("⚠️ Failed to backfill alarm priority: %v", err)
+	}
+	// Автор записей журнала, внесенных до появления user_id, - по тексту оператора
+	if err := repository.BackfillRecordUsers(db); err != nil {
+		log.Printf("⚠️ Failed to link history records to users: %v", err)
+	}
+	// Ответственный по нарядам, внесенным до появления responsible_user_id, - по имени
+	if err := repository.BackfillRecordResponsibleUsers(db); err != nil {
+		log.Printf("⚠️ Failed to link work permits to responsible users: %v", err)
+	}
+	// Допустимый ток шин в амперах из строковых полей паспорта РУ
+	if err := repository.BackfillCapacity(db); err != nil {
+		log.Printf("⚠️ Failed to backfill bus capacity: %v", err)
+	}
+
+	if !criticalColumnExists {
+		if err := repository.MarkDefaultCriticalCells(db); err != nil {
+			log.Printf("⚠️ Failed to mark critical cells: %v", err)
+		}
+	}
+	if !pairedColumnExists {
+		if err := repository.BackfillCellPairs(db); err != nil {
+			log.Printf("⚠️ Failed to pair HIGH/LOW cells: %v", err)
+		}
+	}
+
+	// Полнотекстовый поиск: конфигурация с русским стеммингом и GIN-индексы
+	if err := repository.EnsureSearchIndexes(db); err != nil {
+		log.Printf("⚠️ Failed to prepare full-text search: %v", err)
+	}
+
+	// Тайм-аут запросов включается после миграций и заполнения данных,
+	// которые на большой базе могут выполняться дольше
+	if err := db.Use(repository.QueryTimeout{Timeout: cfg.DBQueryTimeout}); err != nil {
+		log.Fatal("❌ Failed to register query timeout:", err)
+	}
+	if replicaDB != nil {
+		if err := replicaDB.Use(repository.QueryTimeout{Timeout: cfg.DBQueryTimeout}); err != nil {
+			log.Fatal("❌ Failed to register query timeout on read replica:", err)
+		}
+	}
+	// Журналы операций и аудита только дополняются
+	if err := db.Use(repository.AppendOnly{}); err != nil {
+		log.Fatal("❌ Failed to register append-only journals:", err)
+	}
+
 	// Инициализируем репозитории
 	userRepo := repository.NewUserRepository(db)
 	ruRepo := repository.NewRuRepository(db)
+	ruRepo.UseReplica(replicaDB)
+	notificationRepo := repository.NewNotificationRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	calendarRepo := repository.NewCalendarRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	jobLockRepo := repository.NewJobLockRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	searchRepo := repository.NewSearchRepository(db)
+	alarmRepo := repository.NewAlarmRepository(db)
+	lockRepo := repository.NewCellLockRepository(db)
+	pollingRepo := repository.NewPollingRepository(db)
+	confirmationRepo := repository.NewConfirmationRepository(db)
+	measurementRepo := repository.NewMeasurementRepository(db)
+	visionRepo := repository.NewVisionRepository(db)
+	thermalRepo := repository.NewThermalRepository(db)
+	weatherRepo := repository.NewWeatherRepository(db)
+	forecastRepo := repository.NewForecastRepository(db)
+	anomalyRepo := repository.NewAnomalyRepository(db)
+	capacityRepo := repository.NewCapacityRepository(db)
+	sectionRepo := repository.NewSectionRepository(db)
+	transformerRepo := repository.NewTransformerRepository(db)
+	runtimeRepo := repository.NewRuntimeRepository(db)
+	metricsRepo := repository.NewMetricsRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	slaRepo := repository.NewSLARepository(db)
+	energyRepo := repository.NewEnergyRepository(db)
+	voltageRepo := repository.NewVoltageRepository(db)
+	consumerRepo := repository.NewConsumerRepository(db)
+	outageRepo := repository.NewOutageRepository(db)
+	smsRepo := repository.NewSmsRepository(db)
+	pushRepo := repository.NewPushRepository(db)
+	syncRepo := repository.NewSyncRepository(db)
+	summaryRepo := repository.NewSummaryRepository(db)
+	changeRepo := repository.NewCellChangeRepository(db)
+	revisionRepo := repository.NewCellRevisionRepository(db)
+	defectRepo := repository.NewDefectRepository(db)
+	photoRepo := repository.NewPhotoRepository(db)
+	inspectionRepo := repository.NewInspectionRepository(db)
+	documentTypeRepo := repository.NewDocumentTypeRepository(db)
+	numberingRepo := repository.NewNumberingRepository(db)
+	assetRepo := repository.NewAssetRepository(db)
+	inventoryRepo := repository.NewInventoryRepository(db)
+	faultRepo := repository.NewFaultRepository(db)
+	comtradeRepo := repository.NewComtradeRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	commandRepo := repository.NewCommandRepository(db)
+	readOnlyRepo := repository.NewReadOnlyRepository(db)
+	settingRepo := repository.NewSettingRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	chainRepo := repository.NewChainRepository(db)
+	compatRepo := repository.NewCompatRepository(db)
 
 	// Инициализируем сервисы
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTTTL)
-	adminService := service.NewAdminService(userRepo, cfg.JWTSecret)
-	ruService := service.NewRuService(ruRepo)
+	settingsService := service.NewSettingsService(settingRepo)
+	settingsService.SetDefault(service.SettingCORSAllowedOrigins, cfg.CORSAllowedOrigins)
+	auditService := service.NewAuditService(auditRepo)
+	integrityService := service.NewIntegrityService(chainRepo)
+	adminService := service.NewAdminService(userRepo, orgRepo, settingsService, auditService, cfg.JWTSecret)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, userRepo, ruRepo)
+
+	// Web Push (VAPID) - опционально, без ключей уведомления только в центре уведомлений
+	pushSender, err := webpush.New(cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey, cfg.VAPIDSubject)
+	if err != nil {
+		log.Fatal("❌ Failed to configure web push:", err)
+	}
+	pushService := service.NewPushService(pushRepo, pushSender)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, ruRepo, subscriptionService, pushService)
+
+	// SMTP для писем подтверждения и писем потребителям - опционально: без него регистрация
+	// сразу ждет одобрения, а письма потребителям копятся в очереди
+	mailSender, err := mailer.New(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+	if err != nil {
+		log.Fatal("❌ Failed to configure mailer:", err)
+	}
+	authService := service.NewAuthService(userRepo, settingsService, auditService, notificationService, mailSender, cfg.PublicURL, cfg.JWTSecret, cfg.JWTTTL)
+	documentTypeService := service.NewDocumentTypeService(documentTypeRepo)
+	numberingService := service.NewNumberingService(numberingRepo, ruRepo, documentTypeService)
+	ruService := service.NewRuService(ruRepo, lockRepo, confirmationRepo, changeRepo, revisionRepo, commandRepo, documentTypeService, numberingService, settingsService, userRepo)
+	summaryService := service.NewSummaryService(ruService, ruRepo, summaryRepo)
+	cellTagService := service.NewCellTagService(ruRepo, defectRepo, cfg.PublicURL)
+	mapService := service.NewMapService(ruService, ruRepo)
+
+	// Провайдер погоды (Open-Meteo) - опционально, без него температура вносится вручную
+	weatherProvider, err := weather.New(cfg.WeatherProvider, cfg.WeatherURL)
+	if err != nil {
+		log.Fatal("❌ Failed to configure weather provider:", err)
+	}
+	weatherService := service.NewWeatherService(weatherRepo, ruRepo, measurementRepo, ruService, weatherProvider)
+	forecastService := service.NewForecastService(forecastRepo, measurementRepo, ruRepo)
+	transformerService := service.NewTransformerService(transformerRepo, ruRepo, measurementRepo, weatherRepo, settingsService)
+	runtimeService := service.NewRuntimeService(runtimeRepo, ruRepo, transformerRepo)
+	metricsService := service.NewMetricsService(metricsRepo)
+	statsService := service.NewStatsService(statsRepo)
+	slaService := service.NewSLAService(slaRepo, ruRepo, settingsService)
+	anomalyService := service.NewAnomalyService(anomalyRepo, measurementRepo, ruRepo, settingsService)
+	capacityService := service.NewCapacityService(capacityRepo, ruRepo, measurementRepo, ruService, settingsService)
+	sectionService := service.NewSectionService(sectionRepo, ruRepo, lockRepo, capacityRepo)
+	consumerService := service.NewConsumerService(consumerRepo, ruRepo)
+	energyService := service.NewEnergyService(energyRepo, ruRepo, ruService, consumerService)
+	voltageService := service.NewVoltageService(voltageRepo, ruRepo)
+	outageService := service.NewOutageService(outageRepo, ruRepo, consumerService, mailSender)
+
+	// SMS-шлюз (SMSC, Twilio) - опционально, без него SMS не отправляются
+	smsProvider, err := sms.New(cfg.SMSProvider, cfg.SMSURL)
+	if err != nil {
+		log.Fatal("❌ Failed to configure SMS provider:", err)
+	}
+	smsService := service.NewSmsService(smsRepo, ruRepo, notificationService, settingsService, smsProvider)
+	eventBus := service.NewEventBus(outboxRepo)
+	calendarService := service.NewCalendarService(calendarRepo)
+	taskService := service.NewTaskService(userRepo, ruRepo, calendarService)
+	calendarFeedService := service.NewCalendarFeedService(calendarRepo, userRepo, ruService, calendarService, outageService, settingsService)
+	compatService := service.NewCompatService(compatRepo)
+	maintenanceService := service.NewMaintenanceService(maintenanceRepo)
+	searchService := service.NewSearchService(searchRepo, cfg.SearchKazakhLatin)
+	alarmService := service.NewAlarmService(alarmRepo, userRepo, settingsService, notificationService, auditService, ruRepo)
+	pollingService := service.NewPollingService(pollingRepo)
+	measurementService := service.NewMeasurementService(measurementRepo, ruRepo)
+	visionService := service.NewVisionService(visionRepo, ruRepo, settingsService)
+	photoStore, err := storage.New(cfg.StorageType, cfg.StorageURL)
+	if err != nil {
+		log.Fatal("❌ Failed to configure photo storage:", err)
+	}
+	photoService := service.NewPhotoService(photoRepo, ruRepo, photoStore)
+	thermalService := service.NewThermalService(thermalRepo, ruRepo, photoService, settingsService)
+	defectService := service.NewDefectService(defectRepo, photoService, ruRepo, userRepo)
+	inspectionService := service.NewInspectionService(inspectionRepo, ruRepo, photoService)
+	syncService := service.NewSyncService(syncRepo, ruService, inspectionService)
+	assetService := service.NewAssetService(assetRepo, ruRepo)
+	inventoryService := service.NewInventoryService(inventoryRepo, ruRepo, defectRepo)
+	faultService := service.NewFaultService(faultRepo, ruRepo)
+	comtradeService := service.NewComtradeService(comtradeRepo, faultRepo)
+	deviceService := service.NewDeviceService(deviceRepo, ruRepo)
+	commandService := service.NewCommandService(commandRepo, ruRepo, lockRepo, deviceRepo)
+	readOnlyService := service.NewReadOnlyService(readOnlyRepo)
+	orgService := service.NewOrganizationService(orgRepo, ruService)
+
+	// Назначенные дефекты попадают во входящие исполнителя
+	taskService.AddSource(defectService.Tasks)
+	operationCounterService := service.NewOperationCounterService(ruRepo, settingsService)
+	taskService.AddSource(operationCounterService.Tasks)
+	// Одобрения (изменения ячеек, подтверждения переключений) и квитирование аварий
+	taskService.AddSource(ruService.CellChangeTasks)
+	taskService.AddSource(ruService.ConfirmationTasks)
+	taskService.AddSource(alarmService.AckTasks)
+
+	// Внешний брокер событий (Kafka/NATS) - опционально
+	brokerPublisher, err := broker.New(cfg.BrokerType, cfg.BrokerURL)
+	if err != nil {
+		log.Fatal("❌ Failed to configure event broker:", err)
+	}
+	eventPublisher, err := service.NewEventPublisher(brokerPublisher, outboxRepo, cfg.BrokerType, cfg.BrokerFormat, cfg.BrokerTopicPrefix)
+	if err != nil {
+		log.Fatal("❌ Failed to configure event broker:", err)
+	}
+
+	// Мост потока /stream между экземплярами API (LISTEN/NOTIFY) - опционально
+	realtimeBridge, err := realtime.New(cfg.RealtimeBridge, db, database.ListenDSN(cfg), cfg.RealtimeChannel)
+	if err != nil {
+		log.Fatal("❌ Failed to configure realtime bridge:", err)
+	}
+	realtimeService := service.NewRealtimeService(ruRepo, realtimeBridge)
+
+	// Подписчики доменных событий и диспетчер outbox
+	eventBus.Subscribe("notifications", notificationService.HandleEvent,
+		models.EventCellStatusChanged, models.EventAlarmRaised, models.EventPermitIssued, models.EventRuStatusChanged,
+		models.EventApprovalRequested, models.EventUserMentioned)
+	eventBus.Subscribe("alarms", alarmService.HandleEvent, models.EventAlarmRaised, models.EventFaultRecorded, models.EventDeviceOffline, models.EventStockLow, models.EventTelemetryAnomaly, models.EventCapacityOverload, models.EventVisionDiscrepancy, models.EventThermalHotspot, models.EventTapExcessive, models.EventVoltageDeviation)
+	eventBus.Subscribe("commands", commandService.HandleEvent, models.EventCellStatusChanged)
+	eventBus.Subscribe("realtime", realtimeService.HandleEvent,
+		models.EventCellStatusChanged, models.EventRuStatusChanged, models.EventAlarmRaised, models.EventFaultRecorded,
+		models.EventAlarmCreated)
+	go eventBus.Run(context.Background())
+	go realtimeService.Run(context.Background())
+	go compatService.Run(context.Background())
+
+	// Периодические фоновые задачи. При нескольких экземплярах API каждый запуск по
+	// расписанию и каждый непрерывный цикл выполняет один экземпляр (блокировки job_locks);
+	// outbox разбирается всеми экземплярами с SKIP LOCKED, поток /stream слушает каждый.
+	scheduler := service.NewScheduler()
+	scheduler.UseLocker(jobLockRepo, cfg.InstanceID)
+
+	// Прореживание телеметрии в агрегаты 1m/15m/1h
+	go scheduler.RunExclusive(context.Background(), "measurement-compaction", measurementService.Run)
+
+	// Контроль сроков этапов команд телеуправления
+	go scheduler.RunExclusive(context.Background(), "command-deadlines", commandService.Run)
+
+	// Публикация доменных событий во внешний брокер - отдельно от подписчиков outbox
+	if eventPublisher.Enabled() {
+		maintenanceService.KeepUnpublishedEvents()
+		go scheduler.RunExclusive(context.Background(), "event-broker", eventPublisher.Run)
+		log.Printf("📡 Publishing domain events to %s as %s (%s.*)", cfg.BrokerType, cfg.BrokerFormat, cfg.BrokerTopicPrefix)
+	}
+
+	scheduledJobs := []struct {
+		name, description string
+		run               service.JobFunc
+	}{
+		{service.JobMaintenanceDue, "Maintenance due/overdue reminders", service.MaintenanceDueJob(taskService, notificationService)},
+		{service.JobDataRetention, "Expire raw telemetry and fine-grained rollups", service.DataRetentionJob(measurementService)},
+		{service.JobOutboxRetention, "Prune delivered outbox events", service.OutboxRetentionJob(maintenanceService)},
+		{service.JobDeviceHealth, "RTU/IED communication health check", service.DeviceHealthJob(deviceService)},
+		{service.JobAlarmEscalation, "Escalate unacknowledged critical alarms", service.AlarmEscalationJob(alarmService)},
+		{service.JobAlarmShelving, "Unshelve expired alarms and release alarms of closed work permits", service.AlarmShelvingJob(alarmService)},
+		{service.JobWeatherPoll, "Poll ambient temperature at substations", service.WeatherPollJob(weatherService)},
+		{service.JobForecastAccuracy, "Score load forecasts against actual load", service.ForecastAccuracyJob(forecastService)},
+		{service.JobAnomalyDetection, "Flag unusual cell current/temperature (EWMA z-score)", service.AnomalyDetectionJob(anomalyService)},
+		{service.JobCapacityUtilization, "Bus section utilization and overload alarms", service.CapacityUtilizationJob(capacityService)},
+		{service.JobVisionReconciliation, "Reconcile panel indicator lamps with journal cell status", service.VisionReconciliationJob(visionService)},
+		{service.JobRuntimeHours, "Accumulate runtime hours of cells and energized RUs", service.RuntimeHoursJob(runtimeService)},
+		{service.JobConsumerNotifications, "Email consumers about planned outages", service.ConsumerNotificationJob(outageService)},
+		{service.JobSmsNotifications, "SMS about critical alarms and expiring work permits, delivery status", service.SmsNotificationJob(smsService)},
+	}
+	for _, job := range scheduledJobs {
+		spec := service.JobSchedule(cfg.JobSchedules, job.name)
+		// Без провайдера погоды опрашивать нечего: задача видна в списке как отключенная
+		if job.name == service.JobWeatherPoll && !weatherService.ProviderEnabled() {
+			spec = ""
+		}
+		if job.name == service.JobConsumerNotifications && mailSender == nil {
+			spec = ""
+		}
+		if job.name == service.JobSmsNotifications && !smsService.ProviderEnabled() {
+			spec = ""
+		}
+		if err := scheduler.Register(job.name, job.description, spec, job.run); err != nil {
+			log.Fatal("❌ Failed to schedule job:", err)
+		}
+	}
+	go scheduler.Run(context.Background())
 
 	// Инициализируем обработчики
 	authHandler := handlers.NewAuthHandler(authService)
 	adminHandler := handlers.NewAdminHandler(adminService)
-	ruHandler := handlers.NewRuHandler(ruService)
+	ruHandler := handlers.NewRuHandler(ruService, compatService, pollingService)
+	gqlSchema, err := gql.NewSchema(ruService)
+	if err != nil {
+		log.Fatal("Failed to parse GraphQL schema:", err)
+	}
+	graphqlHandler := handlers.NewGraphQLHandler(gqlSchema)
+	summaryHandler := handlers.NewSummaryHandler(summaryService)
+	cellTagHandler := handlers.NewCellTagHandler(cellTagService)
+	mapHandler := handlers.NewMapHandler(mapService)
+	capacityHandler := handlers.NewCapacityHandler(capacityService)
+	energyHandler := handlers.NewEnergyHandler(energyService)
+	voltageHandler := handlers.NewVoltageHandler(voltageService)
+	consumerHandler := handlers.NewConsumerHandler(consumerService)
+	outageHandler := handlers.NewOutageHandler(outageService)
+	weatherHandler := handlers.NewWeatherHandler(weatherService)
+	forecastHandler := handlers.NewForecastHandler(forecastService)
 	adminRuHandler := handlers.NewAdminRuHandler(ruService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	taskHandler := handlers.NewTaskHandler(taskService)
+	dictionaryHandler := handlers.NewDictionaryHandler()
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	calendarFeedHandler := handlers.NewCalendarFeedHandler(calendarFeedService)
+	compatHandler := handlers.NewCompatHandler(compatService)
+	eventHandler := handlers.NewEventHandler(eventBus, eventPublisher)
+	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceService)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	alarmHandler := handlers.NewAlarmHandler(alarmService)
+	pollingHandler := handlers.NewPollingHandler(pollingService)
+	measurementHandler := handlers.NewMeasurementHandler(measurementService, anomalyService)
+	visionHandler := handlers.NewVisionHandler(visionService)
+	jobHandler := handlers.NewJobHandler(scheduler)
+	defectHandler := handlers.NewDefectHandler(defectService)
+	operationCounterHandler := handlers.NewOperationCounterHandler(operationCounterService)
+	photoHandler := handlers.NewPhotoHandler(photoService)
+	thermalHandler := handlers.NewThermalHandler(thermalService)
+	inspectionHandler := handlers.NewInspectionHandler(inspectionService)
+	documentTypeHandler := handlers.NewDocumentTypeHandler(documentTypeService)
+	numberingHandler := handlers.NewNumberingHandler(numberingService)
+	sectionHandler := handlers.NewSectionHandler(sectionService)
+	transformerHandler := handlers.NewTransformerHandler(transformerService)
+	runtimeHandler := handlers.NewRuntimeHandler(runtimeService)
+	syncHandler := handlers.NewSyncHandler(syncService)
+	assetHandler := handlers.NewAssetHandler(assetService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	faultHandler := handlers.NewFaultHandler(faultService)
+	comtradeHandler := handlers.NewComtradeHandler(comtradeService)
+	deviceHandler := handlers.NewDeviceHandler(deviceService)
+	commandHandler := handlers.NewCommandHandler(commandService)
+	readOnlyHandler := handlers.NewReadOnlyHandler(readOnlyService)
+	settingsHandler := handlers.NewSettingsHandler(settingsService)
+	orgHandler := handlers.NewOrganizationHandler(orgService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	integrityHandler := handlers.NewIntegrityHandler(integrityService)
+
+	// Проверки готовности: без базы данных экземпляр не готов, без брокера, SMTP и SMS-шлюза - деградирует
+	healthService := service.NewHealthService()
+	healthService.AddCheck(service.HealthCheck{
+		Name:     "database",
+		Critical: true,
+		Check:    sqlDB.PingContext,
+		Details: func() map[string]interface{} {
+			stats := sqlDB.Stats()
+			return map[string]interface{}{
+				"maxOpen":   stats.MaxOpenConnections,
+				"open":      stats.OpenConnections,
+				"inUse":     stats.InUse,
+				"idle":      stats.Idle,
+				"waitCount": stats.WaitCount,
+			}
+		},
+	})
+	// Без реплики публичная страница читает из основной базы, поэтому проверка не критична
+	replicaCheck := service.HealthCheck{Name: "database-replica"}
+	if replicaDB != nil {
+		replicaSQL, err := replicaDB.DB()
+		if err != nil {
+			log.Fatal("❌ Failed to get read replica handle:", err)
+		}
+		replicaCheck.Check = replicaSQL.PingContext
+	}
+	healthService.AddCheck(replicaCheck)
+	brokerCheck := service.HealthCheck{Name: "broker"}
+	if brokerPublisher != nil {
+		brokerCheck.Check = brokerPublisher.Ping
+	}
+	healthService.AddCheck(brokerCheck)
+	smtpCheck := service.HealthCheck{Name: "smtp"}
+	if mailSender != nil {
+		smtpCheck.Check = mailSender.Ping
+	}
+	healthService.AddCheck(smtpCheck)
+	smsCheck := service.HealthCheck{Name: "sms"}
+	if smsService.ProviderEnabled() {
+		smsCheck.Check = smsService.Ping
+	}
+	healthService.AddCheck(smsCheck)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	metricsHandler := handlers.NewMetricsHandler(metricsService, cfg.MetricsToken)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	slaHandler := handlers.NewSLAHandler(slaService)
+	smsHandler := handlers.NewSmsHandler(smsService)
+	pushHandler := handlers.NewPushHandler(pushService)
+	realtimeHandler := handlers.NewRealtimeHandler(realtimeService)
 
 	// Настраиваем роутер
 	router := gin.Default()
 
-	// Настройка CORS
+	// Доверенные обратные прокси: только их X-Forwarded-For учитывается в ClientIP
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal("❌ Invalid TRUSTED_PROXIES:", err)
+	}
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.LocaleMiddleware())
+
+	// Сжатие JSON-ответов для медленного VPN-канала до подстанций
+	if cfg.CompressionMinSize > 0 {
+		router.Use(middleware.CompressionMiddleware(cfg.CompressionMinSize, cfg.CompressionLevel))
+	}
+
+	// Настройка CORS: разрешенные origins берутся из системных настроек,
+	// по умолчанию - из CORS_ALLOWED_ORIGINS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins: []string{"http://localhost:3001", "http://127.0.0.1:3001"},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+		AllowOriginFunc: settingsService.AllowsOrigin,
+		AllowMethods:    []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
 		AllowHeaders: []string{
 			"Origin",
 			"Content-Type",
 			"Content-Length",
 			"Accept-Encoding",
+			"Accept-Language",
 			"Authorization",
 			"Accept",
 			"Cache-Control",
+			"If-None-Match",
+			"If-Modified-Since",
 			"X-Requested-With",
+			handlers.ClientHeader,
+			middleware.RequestIDHeader,
+			middleware.APIVersionHeader,
+			"Accept-Version",
 		},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Authorization"},
-		AllowCredentials: true,
+		ExposeHeaders: []string{
+			"Content-Length",
+			"Content-Type",
+			"Authorization",
+			middleware.RequestIDHeader,
+			middleware.APIVersionHeader,
+			"Deprecation",
+			"Link",
+			"ETag",
+			"Last-Modified",
+		},
+		AllowCredentials: cfg.CORSAllowCredentials,
 		MaxAge:           12 * 3600,
 	}))
 
-	// ================ ПУБЛИЧНЫЕ ЭНДПОИНТЫ ================
-
-	// Публичный эндпоинт для получения данных подстанции
-	router.GET("/api/substations/:id", ruHandler.GetSubstationPublic)
-
-	// Public routes
-	public := router.Group("/api/auth")
-	{
-		public.POST("/register", authHandler.Register)
-		public.POST("/login", authHandler.Login)
-		public.GET("/health", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"status":   "ok",
-				"service":  "auth",
-				"database": "connected",
-			})
-		})
-	}
+	// Аварийный режим "только чтение": вход и сам переключатель доступны всегда, как и
+	// GraphQL - в схеме только запросы, POST там означает чтение.
+	// Подключается после CORS, чтобы отказ 503 нес заголовки CORS и был виден браузеру.
+	router.Use(middleware.ReadOnlyMiddleware(readOnlyService.State, "/auth/login", "/admin/read-only", "/graphql"))
 
-	// ================ ЗАЩИЩЕННЫЕ ЭНДПОИНТЫ ================
+	// registerAPIRoutes - регистрирует все маршруты API в группе.
+	// Одни и те же маршруты доступны по версионированному пути /api/v1
+	// и по старому пути /api (слой совместимости для развернутых клиентов).
+	registerAPIRoutes := func(api *gin.RouterGroup) {
+		// ================ ПУБЛИЧНЫЕ ЭНДПОИНТЫ ================
 
-	// Protected routes - require JWT
-	protected := router.Group("/api")
-	protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
-	{
-		// Auth routes
-		auth := protected.Group("/auth")
-		{
-			auth.GET("/me", authHandler.GetMe)
-		}
+		// Публичный эндпоинт для получения данных подстанции
+		api.GET("/substations/:id", ruHandler.GetSubstationPublic)
 
-		// RU routes - доступны всем авторизованным
-		rus := protected.Group("/rus")
-		{
-			rus.GET("/", ruHandler.GetAllRUs)                                // Получить все РУ
-			rus.GET("/:id", ruHandler.GetRu)                                 // Получить РУ по ID
-			rus.GET("/:id/history", ruHandler.GetHistory)                    // Получить историю операций
-			rus.PUT("/:id/cells/:cellId/status", ruHandler.UpdateCellStatus) // Обновить статус ячейки
-			rus.POST("/:id/history", ruHandler.AddHistory)                   // Добавить запись в историю
-			rus.PATCH("/:id/cells/:cellId/info", ruHandler.UpdateCellInfo)   // Обновить информацию ячейки
-			rus.PUT("/:id/status", ruHandler.UpdateRuStatus)                 // Обновить статус РУ
-
-			// Обновление РУ на подстанции - доступно всем авторизованным
-			rus.PUT("/substations/:id/rus", ruHandler.UpdateSubstationRUs)
-		}
+		// Справочники с названиями на языке Accept-Language
+		api.GET("/dictionaries/cell-statuses", dictionaryHandler.GetCellStatuses)
+		api.GET("/dictionaries/ru-statuses", dictionaryHandler.GetRuStatuses)
 
-		// Admin routes - только для админов
-		admin := protected.Group("/admin")
-		admin.Use(middleware.RoleMiddleware("admin"))
-		{
-			admin.GET("/users", adminHandler.GetUsers)
-			admin.POST("/users", adminHandler.CreateUser)
-			admin.PUT("/users/:id", adminHandler.UpdateUser)
-			admin.DELETE("/users/:id", adminHandler.DeleteUser)
-			admin.PUT("/users/:id/password", adminHandler.ChangePassword)
-
-			// Административные операции с РУ
-			admin.POST("/rus", adminRuHandler.CreateRU)
-			admin.POST("/rus/:id/cells", adminRuHandler.CreateCells)
-		}
+		// Режим "только чтение" для баннера в интерфейсе
+		api.GET("/system/read-only", readOnlyHandler.GetState)
+
+		// Лента iCal: клиенты календаря не передают JWT, доступ по ключу подписки (?token=)
+		api.GET("/calendar.ics", calendarFeedHandler.GetFeed)
 
-		// Engineer routes
-		engineer := protected.Group("/engineer")
-		engineer.Use(middleware.RoleMiddleware("engineer", "admin"))
+		// Public routes
+		public := api.Group("/auth")
 		{
-			engineer.GET("/test", func(c *gin.Context) {
+			public.POST("/register", authHandler.Register)
+			public.POST("/login", authHandler.Login)
+			public.POST("/verify-email", authHandler.VerifyEmail)
+			public.POST("/resend-verification", authHandler.ResendVerification)
+			public.GET("/health", func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{
-					"message": "Engineer access granted",
-					"user":    c.GetString("user_email"),
-					"role":    c.GetString("user_role"),
+					"status":   "ok",
+					"service":  "auth",
+					"database": "connected",
 				})
 			})
 		}
 
-		// Dispatcher routes
-		dispatcher := protected.Group("/dispatcher")
-		dispatcher.Use(middleware.RoleMiddleware("dispatcher", "engineer", "admin"))
+		// ================ ЗАЩИЩЕННЫЕ ЭНДПОИНТЫ ================
+
+		// Protected routes - require JWT
+		protected := api.Group("")
+		protected.Use(middleware.AuthMiddleware(cfg.JWTSecret))
+		protected.Use(middleware.AuditMiddleware(auditService.Record))
 		{
-			dispatcher.GET("/test", func(c *gin.Context) {
-				c.JSON(http.StatusOK, gin.H{
-					"message": "Dispatcher access granted",
-					"user":    c.GetString("user_email"),
-					"role":    c.GetString("user_role"),
+			// Auth routes
+			auth := protected.Group("/auth")
+			{
+				auth.GET("/me", authHandler.GetMe)
+			}
+
+			// Личная очередь задач пользователя
+			me := protected.Group("/me")
+			{
+				me.GET("/tasks", taskHandler.GetMyTasks)
+				me.GET("/notifications", notificationHandler.GetMyNotifications)
+				me.GET("/notifications/unread-count", notificationHandler.GetUnreadCount)
+				me.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+				me.POST("/notifications/:id/read", notificationHandler.MarkRead)
+				me.GET("/push/public-key", pushHandler.GetPublicKey)
+				me.GET("/push/subscriptions", pushHandler.GetSubscriptions)
+				me.POST("/push/subscriptions", pushHandler.Subscribe)
+				me.DELETE("/push/subscriptions/:id", pushHandler.Unsubscribe)
+				me.GET("/subscriptions", subscriptionHandler.GetMySubscriptions)
+				me.POST("/subscriptions", subscriptionHandler.Subscribe)
+				me.DELETE("/subscriptions/:id", subscriptionHandler.Unsubscribe)
+				me.POST("/calendar-feed", calendarFeedHandler.IssueToken)
+				me.DELETE("/calendar-feed", calendarFeedHandler.RevokeToken)
+			}
+
+			// Производственный календарь
+			protected.GET("/calendar", calendarHandler.GetYear)
+
+			// Аварии: список, массовое квитирование, сохраненные фильтры
+			alarms := protected.Group("/alarms")
+			{
+				alarms.GET("", alarmHandler.GetAlarms)
+				alarms.POST("/ack", alarmHandler.AcknowledgeAlarms)
+				alarms.GET("/stats", alarmHandler.GetAlarmStats)
+				alarms.POST("/:alarmId/shelve", alarmHandler.ShelveAlarm)
+				alarms.DELETE("/:alarmId/shelve", alarmHandler.UnshelveAlarm)
+				alarms.GET("/filters", alarmHandler.GetSavedFilters)
+				alarms.POST("/filters", alarmHandler.CreateSavedFilter)
+				alarms.DELETE("/filters/:filterId", alarmHandler.DeleteSavedFilter)
+			}
+
+			// Реестр потребителей и их подключение к отходящим ячейкам
+			consumers := protected.Group("/consumers")
+			{
+				consumers.GET("", consumerHandler.GetConsumers)
+				consumers.GET("/:consumerId", consumerHandler.GetConsumer)
+				consumers.POST("", middleware.RoleMiddleware("engineer", "admin", "org_admin"), consumerHandler.CreateConsumer)
+				consumers.PUT("/:consumerId", middleware.RoleMiddleware("engineer", "admin", "org_admin"), consumerHandler.UpdateConsumer)
+				consumers.DELETE("/:consumerId", middleware.RoleMiddleware("admin", "org_admin"), consumerHandler.DeleteConsumer)
+				consumers.POST("/:consumerId/feeders", middleware.RoleMiddleware("engineer", "admin", "org_admin"), consumerHandler.AssignFeeder)
+				consumers.DELETE("/:consumerId/feeders/:cellId", middleware.RoleMiddleware("engineer", "admin", "org_admin"), consumerHandler.RemoveFeeder)
+			}
+
+			// Плановые отключения: заявка, согласование, уведомление потребителей
+			outages := protected.Group("/outages")
+			{
+				outages.GET("", outageHandler.GetOutages)
+				outages.POST("", middleware.RoleMiddleware("dispatcher", "engineer", "admin"), outageHandler.CreateOutage)
+				outages.GET("/:outageId", outageHandler.GetOutage)
+				outages.POST("/:outageId/approve", middleware.RoleMiddleware("engineer", "admin"), outageHandler.ApproveOutage)
+				outages.POST("/:outageId/reject", middleware.RoleMiddleware("engineer", "admin"), outageHandler.RejectOutage)
+				outages.POST("/:outageId/cancel", outageHandler.CancelOutage)
+				outages.GET("/:outageId/notifications", outageHandler.GetNotifications)
+			}
+			protected.GET("/calendar/outages.ics", outageHandler.GetCalendar)
+
+			// Синхронизация планшетов, работающих без связи
+			sync := protected.Group("/sync")
+			{
+				sync.GET("/changes", syncHandler.GetChanges)
+				sync.POST("/upload", syncHandler.Upload)
+			}
+
+			// Поток событий (SSE) для экранов диспетчеров
+			protected.GET("/stream", realtimeHandler.Stream)
+
+			// Дефекты оборудования
+			defects := protected.Group("/defects")
+			{
+				defects.GET("", defectHandler.GetDefects)
+				defects.POST("", defectHandler.CreateDefect)
+				defects.GET("/:defectId", defectHandler.GetDefect)
+				defects.PATCH("/:defectId", defectHandler.UpdateDefect)
+				defects.POST("/:defectId/status", defectHandler.UpdateDefectStatus)
+				defects.DELETE("/:defectId", middleware.RoleMiddleware("engineer", "admin"), defectHandler.DeleteDefect)
+				defects.POST("/:defectId/photos", defectHandler.UploadPhoto)
+				defects.GET("/:defectId/photos/:photoId", defectHandler.GetPhoto)
+				defects.GET("/:defectId/photos/:photoId/thumbnail", defectHandler.GetPhotoThumbnail)
+			}
+
+			// SLA устранения дефектов и работ по нарядам-допускам
+			sla := protected.Group("/sla")
+			{
+				sla.GET("/summary", slaHandler.GetSummary)
+				sla.GET("/breaches", slaHandler.GetBreaches)
+			}
+
+			// Реестр оборудования: изменения - инженеры и администраторы
+			assets := protected.Group("/assets")
+			{
+				assets.GET("", assetHandler.GetAssets)
+				assets.GET("/:assetId", assetHandler.GetAsset)
+				assets.GET("/:assetId/history", assetHandler.GetAssetHistory)
+				assets.POST("", middleware.RoleMiddleware("engineer", "admin"), assetHandler.CreateAsset)
+				assets.PATCH("/:assetId", middleware.RoleMiddleware("engineer", "admin"), assetHandler.UpdateAsset)
+				assets.POST("/:assetId/move", middleware.RoleMiddleware("engineer", "admin"), assetHandler.MoveAsset)
+				assets.POST("/:assetId/state", middleware.RoleMiddleware("engineer", "admin"), assetHandler.ChangeAssetState)
+			}
+
+			// Склад запасных частей и резервирование под наряды/ТО
+			inventory := protected.Group("/inventory")
+			{
+				inventory.GET("/warehouses", inventoryHandler.GetWarehouses)
+				inventory.GET("/items", inventoryHandler.GetItems)
+				inventory.POST("/items", middleware.RoleMiddleware("engineer", "admin"), inventoryHandler.CreateItem)
+				inventory.GET("/stock", inventoryHandler.GetStock)
+				inventory.POST("/stock/receive", middleware.RoleMiddleware("engineer", "admin"), inventoryHandler.ReceiveStock)
+				inventory.POST("/stock/min", middleware.RoleMiddleware("engineer", "admin"), inventoryHandler.SetMinStock)
+				inventory.GET("/reservations", inventoryHandler.GetReservations)
+				inventory.POST("/reservations", inventoryHandler.Reserve)
+				inventory.POST("/reservations/:reservationId/consume", inventoryHandler.ConsumeReservation)
+				inventory.POST("/reservations/:reservationId/release", inventoryHandler.ReleaseReservation)
+			}
+
+			// Устройства телемеханики и РЗА, контроль связи
+			devices := protected.Group("/devices")
+			{
+				devices.GET("", deviceHandler.GetDevices)
+				devices.GET("/:deviceId", deviceHandler.GetDevice)
+				devices.POST("/:deviceId/check", middleware.RoleMiddleware("engineer", "admin"), deviceHandler.CheckDevice)
+			}
+
+			// Осмотры РУ по чек-листам
+			inspections := protected.Group("/inspections")
+			{
+				inspections.GET("/templates", inspectionHandler.GetTemplates)
+				inspections.GET("/:inspectionId", inspectionHandler.GetInspection)
+				inspections.POST("/:inspectionId/results/:resultId/photos", middleware.RoleMiddleware("engineer", "admin"), inspectionHandler.UploadResultPhoto)
+				inspections.GET("/:inspectionId/results/:resultId/photos/:photoId", inspectionHandler.GetResultPhoto)
+				inspections.GET("/:inspectionId/results/:resultId/photos/:photoId/thumbnail", inspectionHandler.GetResultPhotoThumbnail)
+			}
+
+			// Очередь изменений паспортных данных ячеек: одобряют инженеры и администраторы
+			cellChanges := protected.Group("/cell-changes")
+			cellChanges.Use(middleware.RoleMiddleware("engineer", "admin"))
+			{
+				cellChanges.GET("", ruHandler.GetCellChanges)
+				cellChanges.POST("/:changeId/approve", ruHandler.ApproveCellChange)
+				cellChanges.POST("/:changeId/reject", ruHandler.RejectCellChange)
+			}
+
+			// Прием телеметрии от шлюза: РУ, ячейки и устройства пакета - только организации пользователя
+			protected.POST("/telemetry/measurements", middleware.RoleMiddleware("engineer", "admin"), measurementHandler.RecordMeasurements)
+			protected.POST("/telemetry/weather", middleware.RoleMiddleware("engineer", "admin"), weatherHandler.RecordWeather)
+			protected.POST("/telemetry/vision-readings", middleware.RoleMiddleware("engineer", "admin"), visionHandler.RecordReadings)
+			protected.POST("/telemetry/vision-indications", middleware.RoleMiddleware("engineer", "admin"), visionHandler.RecordIndications)
+			protected.POST("/telemetry/meter-readings", middleware.RoleMiddleware("engineer", "admin"), energyHandler.RecordTelemetryReadings)
+			protected.POST("/telemetry/voltages", middleware.RoleMiddleware("engineer", "admin"), voltageHandler.RecordVoltages)
+			protected.POST("/telemetry/faults", middleware.RoleMiddleware("engineer", "admin"), faultHandler.RecordTelemetryFault)
+			protected.POST("/telemetry/tap-positions", middleware.RoleMiddleware("engineer", "admin"), transformerHandler.RecordTapPositions)
+			protected.POST("/telemetry/devices/:deviceId/heartbeat", middleware.RoleMiddleware("engineer", "admin"), deviceHandler.Heartbeat)
+			protected.GET("/telemetry/commands/pending", middleware.RoleMiddleware("engineer", "admin"), commandHandler.GetPendingCommands)
+			protected.POST("/telemetry/commands/:commandId/ack", middleware.RoleMiddleware("engineer", "admin"), commandHandler.AckCommand)
+
+			// Полнотекстовый поиск по ячейкам, журналу операций и РУ
+			protected.GET("/search", searchHandler.Search)
+
+			// Обзор подстанции: РУ, ячейки и последние операции за один запрос
+			protected.GET("/substations/:id/overview", ruHandler.GetSubstationOverview)
+			// GraphQL: подстанции, РУ, ячейки и операции с выбором полей
+			protected.POST("/graphql", graphqlHandler.Query)
+			protected.GET("/substations/:id/daily-summary", summaryHandler.GetDailySummary)
+			protected.GET("/substations/:id/weather", weatherHandler.GetWeather)
+			protected.GET("/cells/lookup", cellTagHandler.LookupCell)
+			protected.GET("/document-types", documentTypeHandler.GetDocumentTypes)
+			// Сквозная нумерация распоряжений и нарядов
+			protected.POST("/document-numbers", numberingHandler.IssueNumber)
+			protected.GET("/document-numbers/gaps", middleware.RoleMiddleware("engineer", "admin"), numberingHandler.GetGaps)
+			protected.GET("/map/geojson", mapHandler.GetGeoJSON)
+			protected.GET("/capacity/utilization", capacityHandler.GetUtilization)
+			protected.GET("/energy/consumption", energyHandler.GetConsumption)
+
+			// RU routes - доступны всем авторизованным
+			rus := protected.Group("/rus")
+			// Журнал операций - по правам матрицы (PERMISSIONS_<ROLE>): чтение, запись, выгрузка
+			historyRead := middleware.PermissionMiddleware(permissions.HistoryRead)
+			historyWrite := middleware.PermissionMiddleware(permissions.HistoryWrite)
+			historyExport := middleware.PermissionMiddleware(permissions.HistoryExport)
+			// РУ чужой организации недоступно по всем вложенным маршрутам
+			rus.Use(middleware.TenantMiddleware(ruService.RuOrganization))
+			{
+				rus.GET("/", ruHandler.GetAllRUs)                                                            // Получить все РУ
+				rus.GET("/:id", ruHandler.GetRu)                                                             // Получить РУ по ID
+				rus.GET("/:id/cells/pairs", ruHandler.GetPairedCells)                                        // Ячейки ВН и НН попарно
+				rus.GET("/:id/cells/:cellId", ruHandler.GetCell)                                             // Получить ячейку
+				rus.GET("/:id/cells/:cellId/qr", cellTagHandler.GetCellQR)                                   // QR-код для наклейки
+				rus.GET("/:id/history", historyRead, ruHandler.GetHistory)                                   // Получить историю операций
+				rus.GET("/:id/history/export", historyExport, ruHandler.ExportHistory)                       // Выгрузить журнал за период
+				rus.GET("/:id/history/:recordId", historyRead, ruHandler.GetHistoryRecord)                   // Получить запись истории
+				rus.POST("/:id/history/:recordId/corrections", historyWrite, ruHandler.CorrectHistoryRecord) // Исправить запись истории
+				rus.PUT("/:id/cells/:cellId/status", ruHandler.UpdateCellStatus)                             // Обновить статус ячейки
+				rus.POST("/:id/history", historyWrite, ruHandler.AddHistory)                                 // Добавить запись в историю
+				rus.PATCH("/:id/cells/:cellId/info", ruHandler.UpdateCellInfo)                               // Обновить информацию ячейки
+				rus.PUT("/:id/status", ruHandler.UpdateRuStatus)                                             // Установить статус РУ вручную
+				rus.DELETE("/:id/status", ruHandler.ClearRuStatus)                                           // Вернуть автоматический статус РУ
+
+				// Замки и плакаты (LOTO): снять замок может только инженер или администратор
+				rus.GET("/:id/cells/:cellId/lock", ruHandler.GetCellLock)
+				rus.POST("/:id/cells/:cellId/lock", ruHandler.PlaceCellLock)
+				rus.PUT("/:id/cells/:cellId/operation-counter", middleware.RoleMiddleware("engineer", "admin"), operationCounterHandler.UpdateOperationCounter)
+				rus.DELETE("/:id/cells/:cellId/lock", middleware.RoleMiddleware("engineer", "admin"), ruHandler.RemoveCellLock)
+
+				// Телеметрия ячейки: сырые данные или агрегаты в зависимости от периода
+				rus.GET("/:id/cells/:cellId/measurements", measurementHandler.GetMeasurements)
+				rus.GET("/:id/cells/:cellId/load-temperature", weatherHandler.GetLoadTemperature)
+				rus.GET("/:id/cells/:cellId/vision-readings", visionHandler.GetCellReadings)
+				rus.GET("/:id/cells/:cellId/vision-indications", visionHandler.GetCellIndications)
+
+				// Показания счетчиков электроэнергии отходящих ячеек
+				rus.GET("/:id/cells/:cellId/meter-readings", energyHandler.GetReadings)
+				rus.POST("/:id/cells/:cellId/meter-readings", energyHandler.RecordReading)
+				rus.GET("/:id/voltage-quality", voltageHandler.GetVoltageQuality)
+
+				// Потребители, теряющие питание при отключении ячеек
+				rus.GET("/:id/outage-impact", consumerHandler.GetOutageImpact)
+
+				// Прогноз нагрузки и его точность
+				rus.GET("/:id/forecast", forecastHandler.GetForecast)
+				rus.GET("/:id/forecast/runs", forecastHandler.GetRuns)
+
+				// Трансформаторы: загрузка и износ изоляции по IEC 60076-7
+				rus.GET("/:id/transformers", transformerHandler.GetTransformers)
+				rus.GET("/:id/runtime", runtimeHandler.GetRuntime)
+				rus.PUT("/:id/transformers/:transformerId", middleware.RoleMiddleware("engineer", "admin"), transformerHandler.UpdateTransformer)
+				rus.PUT("/:id/transformers/:transformerId/tap", middleware.RoleMiddleware("engineer", "admin"), transformerHandler.SetTapPosition)
+				rus.GET("/:id/transformers/:transformerId/tap-changes", transformerHandler.GetTapChanges)
+
+				// Переключение критичных ячеек диспетчером подтверждает второй сотрудник
+				rus.GET("/:id/cells/:cellId/status/confirmations", ruHandler.GetStatusConfirmations)
+				rus.POST("/:id/cells/:cellId/status/confirmations/:confirmationId", ruHandler.ConfirmCellStatus)
+
+				// История конфигурации ячейки и откат к ревизии
+				rus.GET("/:id/cells/:cellId/revisions", ruHandler.GetCellRevisions)
+				rus.POST("/:id/cells/:cellId/revisions/:revision/restore", middleware.RoleMiddleware("engineer", "admin"), ruHandler.RestoreCellRevision)
+
+				// Осмотры РУ: проводят инженеры
+				rus.GET("/:id/inspections", inspectionHandler.GetInspections)
+				rus.POST("/:id/inspections", middleware.RoleMiddleware("engineer", "admin"), inspectionHandler.SubmitInspection)
+
+				// Оборудование, установленное в ячейке
+				rus.GET("/:id/cells/:cellId/assets", assetHandler.GetCellAssets)
+
+				// Снимки оборудования ячейки (EXIF: время и координаты съемки)
+				rus.GET("/:id/cells/:cellId/photos", photoHandler.GetCellPhotos)
+				rus.POST("/:id/cells/:cellId/photos", photoHandler.UploadCellPhoto)
+				rus.GET("/:id/cells/:cellId/photos/:photoId", photoHandler.GetCellPhoto)
+				rus.GET("/:id/cells/:cellId/photos/:photoId/thumbnail", photoHandler.GetCellPhotoThumbnail)
+
+				// Тепловизионный контроль: снимки обходов, нагретые точки и тренды
+				rus.GET("/:id/cells/:cellId/thermal", thermalHandler.GetSnapshots)
+				rus.POST("/:id/cells/:cellId/thermal", middleware.RoleMiddleware("engineer", "admin"), thermalHandler.CreateSnapshot)
+				rus.GET("/:id/cells/:cellId/thermal/trend", thermalHandler.GetTrend)
+				rus.POST("/:id/cells/:cellId/thermal/:snapshotId/photos", middleware.RoleMiddleware("engineer", "admin"), thermalHandler.UploadPhoto)
+				rus.GET("/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId", thermalHandler.GetPhoto)
+				rus.GET("/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId/thumbnail", thermalHandler.GetPhotoThumbnail)
+
+				// Секции шин и бланки переключений для снятия/подачи напряжения на секцию
+				rus.GET("/:id/sections", sectionHandler.GetSections)
+				rus.GET("/:id/sections/:sectionId", sectionHandler.GetSection)
+				rus.PUT("/:id/sections/:sectionId", middleware.RoleMiddleware("engineer", "admin"), sectionHandler.UpdateSection)
+				rus.POST("/:id/sections/:sectionId/de-energize", sectionHandler.DeEnergizeSection)
+				rus.POST("/:id/sections/:sectionId/energize", sectionHandler.EnergizeSection)
+				rus.GET("/:id/switching-orders", sectionHandler.GetSwitchingOrders)
+				rus.GET("/:id/switching-orders/:orderId", sectionHandler.GetSwitchingOrder)
+
+				// Журнал отключений релейной защиты
+				rus.GET("/:id/faults", faultHandler.GetFaults)
+				rus.GET("/:id/faults/:faultId", faultHandler.GetFault)
+				rus.POST("/:id/faults", middleware.RoleMiddleware("engineer", "admin"), faultHandler.RecordFault)
+
+				rus.GET("/:id/devices", deviceHandler.GetRuDevices)
+
+				// Телеуправление через RTU: выбор -> подтверждение оператором -> исполнение
+				rus.GET("/:id/commands", commandHandler.GetCommands)
+				rus.GET("/:id/commands/:commandId", commandHandler.GetCommand)
+				rus.POST("/:id/cells/:cellId/commands", middleware.RoleMiddleware("dispatcher", "engineer", "admin"), commandHandler.SelectCommand)
+				rus.POST("/:id/commands/:commandId/operate", middleware.RoleMiddleware("dispatcher", "engineer", "admin"), commandHandler.OperateCommand)
+				rus.POST("/:id/commands/:commandId/cancel", middleware.RoleMiddleware("dispatcher", "engineer", "admin"), commandHandler.CancelCommand)
+
+				// Осциллограммы COMTRADE, прикрепленные к отключениям
+				rus.GET("/:id/comtrade", comtradeHandler.GetRecords)
+				rus.GET("/:id/comtrade/:recordId", comtradeHandler.GetRecord)
+				rus.GET("/:id/comtrade/:recordId/files/:kind", comtradeHandler.DownloadFile)
+				rus.GET("/:id/faults/:faultId/comtrade", comtradeHandler.GetFaultRecords)
+				rus.POST("/:id/faults/:faultId/comtrade", middleware.RoleMiddleware("engineer", "admin"), comtradeHandler.UploadRecord)
+
+				// Состав РУ подстанции: перечисленные РУ переносятся, остальные отвязываются
+				rus.PUT("/substations/:id/rus", middleware.RoleMiddleware("admin", "org_admin"), ruHandler.UpdateSubstationRUs)
+			}
+
+			// Admin routes - только для админов
+			// Пользователями управляют администратор установки и администраторы
+			// организаций (только в пределах своей организации)
+			users := protected.Group("/admin/users")
+			users.Use(middleware.RoleMiddleware("admin", "org_admin"))
+			{
+				users.GET("", adminHandler.GetUsers)
+				users.POST("", adminHandler.CreateUser)
+				users.GET("/pending", adminHandler.GetPendingUsers)
+				users.POST("/:id/approve", adminHandler.ApproveUser)
+				users.POST("/:id/reject", adminHandler.RejectUser)
+				users.PUT("/:id", adminHandler.UpdateUser)
+				users.DELETE("/:id", adminHandler.DeleteUser)
+				users.PUT("/:id/password", adminHandler.ChangePassword)
+				users.GET("/:id/activity", adminHandler.GetUserActivity)
+				users.GET("/:id/substations", subscriptionHandler.GetUserSubstations)
+				users.PUT("/:id/substations", subscriptionHandler.SetUserSubstations)
+			}
+
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RoleMiddleware("admin"))
+			{
+				// Работа от имени пользователя и журнал аудита
+				admin.POST("/users/:id/impersonate", adminHandler.Impersonate)
+				admin.GET("/audit", auditHandler.GetEntries)
+				admin.GET("/integrity/verify", integrityHandler.Verify)
+				admin.GET("/stats", statsHandler.GetStats)
+				admin.GET("/history/clock-skew", ruHandler.GetClockSkew)
+
+				// SMS-уведомления: журнал доставки и месячный расход
+				admin.GET("/sms/messages", smsHandler.GetMessages)
+				admin.GET("/sms/usage", smsHandler.GetUsage)
+
+				// Организации-арендаторы
+				admin.GET("/organizations", orgHandler.GetOrganizations)
+				admin.POST("/organizations", orgHandler.CreateOrganization)
+				admin.POST("/organizations/:id/substations", orgHandler.AssignSubstation)
+
+				// Административные операции с РУ
+				admin.POST("/rus", adminRuHandler.CreateRU)
+				admin.GET("/rus/archived", adminRuHandler.GetArchivedRUs)
+				admin.PUT("/rus/:id", adminRuHandler.UpdateRU)
+				admin.DELETE("/rus/:id", adminRuHandler.DeleteRU)
+				admin.POST("/rus/:id/restore", adminRuHandler.RestoreRU)
+				admin.POST("/rus/:id/cells", adminRuHandler.CreateCells)
+				admin.GET("/rus/:id/cells/next-number", adminRuHandler.NextCellNumber)
+				admin.PUT("/rus/:id/cells/:cellId/critical", adminRuHandler.SetCellCritical)
+				admin.PUT("/rus/:id/cells/:cellId/pair", adminRuHandler.PairCell)
+				admin.DELETE("/rus/:id/cells/:cellId/pair", adminRuHandler.UnpairCell)
+				admin.GET("/rus/:id/export", adminRuHandler.ExportRU)
+				admin.POST("/rus/import", adminRuHandler.ImportRU)
+				admin.PUT("/rus/:id/location", adminRuHandler.SetRuLocation)
+				admin.PUT("/substations/:id/location", adminRuHandler.SetSubstationLocation)
+				admin.PUT("/rus/:id/capacity", capacityHandler.SetCapacity)
+
+				// Правила маршрутизации уведомлений по РУ
+				admin.GET("/rus/:id/notification-rules", notificationHandler.GetRules)
+				admin.POST("/rus/:id/notification-rules", notificationHandler.CreateRule)
+				admin.DELETE("/rus/:id/notification-rules/:ruleId", notificationHandler.DeleteRule)
+
+				// Производственный календарь: праздники, сокращенные дни, переносы
+				admin.PUT("/calendar/:date", calendarHandler.UpsertDay)
+				admin.DELETE("/calendar/:date", calendarHandler.DeleteDay)
+
+				// Статистика клиентов, присылающих даты в старом формате
+				admin.GET("/compat/date-formats", compatHandler.GetDateFormatStats)
+
+				// Outbox доменных событий
+				admin.GET("/events", eventHandler.GetEvents)
+				admin.POST("/events/:eventId/requeue", eventHandler.RequeueEvent)
+				admin.GET("/events/broker", eventHandler.GetBrokerStats)
+
+				// Опрос телеметрии: приостановка адаптеров и РУ без перезапуска
+				admin.GET("/polling", pollingHandler.GetState)
+				admin.POST("/polling/pause", pollingHandler.Pause)
+				admin.POST("/polling/resume", pollingHandler.Resume)
+
+				// Обслуживание БД: REINDEX CONCURRENTLY, VACUUM ANALYZE, очистка outbox
+				admin.POST("/maintenance/jobs", maintenanceHandler.StartJob)
+				admin.GET("/maintenance/jobs", maintenanceHandler.GetJobs)
+				admin.GET("/maintenance/jobs/:jobId", maintenanceHandler.GetJob)
+				admin.GET("/maintenance/cells", maintenanceHandler.GetCellReport)
+				admin.POST("/maintenance/cells/cleanup", maintenanceHandler.CleanupCells)
+
+				// Планировщик фоновых задач
+				admin.GET("/jobs", jobHandler.GetJobs)
+				admin.POST("/jobs/:name/run", jobHandler.RunJob)
+
+				// Шаблоны чек-листов осмотра
+				admin.GET("/inspections/templates", inspectionHandler.GetAllTemplates)
+				admin.POST("/inspections/templates", inspectionHandler.CreateTemplate)
+				admin.PUT("/inspections/templates/:templateId", inspectionHandler.UpdateTemplate)
+				admin.DELETE("/inspections/templates/:templateId", inspectionHandler.DeleteTemplate)
+
+				// Справочник видов документов журнала операций
+				admin.GET("/document-types", documentTypeHandler.GetAllDocumentTypes)
+				admin.POST("/document-types", documentTypeHandler.CreateDocumentType)
+				admin.PUT("/document-types/:code", documentTypeHandler.UpdateDocumentType)
+				admin.DELETE("/document-types/:code", documentTypeHandler.DeleteDocumentType)
+
+				// Склады запасных частей
+				admin.POST("/inventory/warehouses", inventoryHandler.CreateWarehouse)
+
+				// Реестр устройств RTU/IED
+				admin.POST("/devices", deviceHandler.CreateDevice)
+				admin.PUT("/devices/:deviceId", deviceHandler.UpdateDevice)
+				admin.DELETE("/devices/:deviceId", deviceHandler.DeleteDevice)
+
+				// Аварийный режим "только чтение"
+				admin.PUT("/read-only", readOnlyHandler.SetState)
+
+				// Системные настройки
+				admin.GET("/settings", settingsHandler.GetSettings)
+				admin.PUT("/settings", settingsHandler.UpdateSettings)
+			}
+
+			// Engineer routes
+			engineer := protected.Group("/engineer")
+			engineer.Use(middleware.RoleMiddleware("engineer", "admin"))
+			{
+				engineer.GET("/test", func(c *gin.Context) {
+					c.JSON(http.StatusOK, gin.H{
+						"message": "Engineer access granted",
+						"user":    c.GetString("user_email"),
+						"role":    c.GetString("user_role"),
+					})
 				})
-			})
+			}
+
+			// Dispatcher routes
+			dispatcher := protected.Group("/dispatcher")
+			dispatcher.Use(middleware.RoleMiddleware("dispatcher", "engineer", "admin"))
+			{
+				dispatcher.GET("/test", func(c *gin.Context) {
+					c.JSON(http.StatusOK, gin.H{
+						"message": "Dispatcher access granted",
+						"user":    c.GetString("user_email"),
+						"role":    c.GetString("user_role"),
+					})
+				})
+			}
 		}
 	}
 
-	// Health check
+	registerAPIRoutes(router.Group("/api/v1", middleware.APIVersionMiddleware(1)))
+	registerAPIRoutes(router.Group("/api", middleware.APIVersionMiddleware(0)))
+
+	// Liveness и readiness для Kubernetes и балансировщика
+	router.GET("/healthz", healthHandler.Liveness)
+	router.GET("/readyz", healthHandler.Readiness)
+
+	// Предметные метрики для Prometheus
+	router.GET("/metrics", metricsHandler.GetMetrics)
+
+	// Health check (прежний формат)
 	router.GET("/health", func(c *gin.Context) {
-		var dbStatus string
-		sqlDB, err := db.DB()
-		if err != nil {
-			dbStatus = "error_getting_db"
-		} else {
-			err = sqlDB.Ping()
-			if err != nil {
-				dbStatus = "disconnected"
-			} else {
-				dbStatus = "connected"
-			}
+		dbStatus := "connected"
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+		if err := sqlDB.PingContext(ctx); err != nil {
+			dbStatus = "disconnected"
 		}
 
+		stats := sqlDB.Stats()
 		c.JSON(http.StatusOK, gin.H{
-			"status":      "ok",
-			"service":     "service-desk-api",
-			"version":     "1.0.0",
-			"database":    dbStatus,
-			"environment": getEnv("GIN_MODE", "debug"),
+			"status":   "ok",
+			"service":  "service-desk-api",
+			"version":  "1.0.0",
+			"database": dbStatus,
+			"pool": gin.H{
+				"maxOpen":      stats.MaxOpenConnections,
+				"open":         stats.OpenConnections,
+				"inUse":        stats.InUse,
+				"idle":         stats.Idle,
+				"waitCount":    stats.WaitCount,
+				"waitDuration": stats.WaitDuration.String(),
+			},
+			"environment": cfg.Environment,
 		})
 	})
 
@@ -216,29 +1195,306 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Service Desk API",
 			"version": "1.0.0",
+			"api": gin.H{
+				"current":    "/api/v1",
+				"legacy":     "/api (deprecated alias of /api/v1)",
+				"versioning": "X-API-Version / Accept-Version header or Accept: application/vnd.sez.v1+json",
+			},
 			"endpoints": gin.H{
 				"auth": gin.H{
-					"POST /api/auth/register": "Register new user",
-					"POST /api/auth/login":    "Login user",
+					"POST /api/auth/register":            "Register new user (email verification, then admin approval)",
+					"POST /api/auth/login":               "Login user",
+					"POST /api/auth/verify-email":        "Confirm email with token from verification letter",
+					"POST /api/auth/resend-verification": "Send verification letter again",
 				},
 				"public": gin.H{
-					"GET /api/substations/:id": "Get substation info (public)",
+					"GET /api/substations/:id":            "Get substation info (public)",
+					"GET /api/dictionaries/cell-statuses": "Get localized cell statuses (public)",
+					"GET /api/dictionaries/ru-statuses":   "Get localized RU statuses and operational states (public)",
+					"GET /api/system/read-only":           "Read-only mode state (public)",
+				},
+				"me": gin.H{
+					"GET  /api/me/tasks":                      "Get personal task inbox: maintenance, work permits by responsible user, assigned defects, breaker wear, approvals (cell changes, status confirmations) and critical alarms to acknowledge per escalation step",
+					"GET  /api/me/notifications":              "Notification center (?unread=&category=&before=&limit=)",
+					"GET  /api/me/notifications/unread-count": "Unread notification count",
+					"POST /api/me/notifications/:id/read":     "Mark notification read",
+					"POST /api/me/notifications/read-all":     "Mark all notifications read",
+					"GET  /api/me/push/public-key":            "VAPID public key for pushManager.subscribe",
+					"GET  /api/me/push/subscriptions":         "Browser push subscriptions of the user",
+					"POST /api/me/push/subscriptions":         "Save browser push subscription (PushSubscription.toJSON())",
+					"DELETE /api/me/push/subscriptions/:id":   "Remove browser push subscription",
+					"GET  /api/me/subscriptions":              "Followed RUs/cells (incl. assigned substations)",
+					"POST /api/me/subscriptions":              "Follow an RU or cell",
+					"DELETE /api/me/subscriptions/:id":        "Unfollow",
+					"POST /api/me/calendar-feed":              "Issue iCal subscription key (replaces the previous one); returns feed path",
+					"DELETE /api/me/calendar-feed":            "Revoke iCal subscription key",
+					"GET  /api/calendar.ics?token=":           "iCal feed: planned outages, maintenance and inspection deadlines (subscription key, no JWT)",
+				},
+				"alarms": gin.H{
+					"GET    /api/alarms":     "List alarms of the user's organization (ruId, kind, severity, priority, status, before, after, visibility=actionable|hidden|all) with console priority, audible class and recommended action; shelved and permit-suppressed alarms are hidden by default",
+					"POST   /api/alarms/ack": "Acknowledge alarms of the user's organization by filter or ids with comment",
+					"GET    /api/alarms/stats?from=&to=&ruId=&groupBy=cell|ru&limit=&format=json|csv": "Alarm statistics per cell or RU for a period: counts by severity and kind, mean time to acknowledge, chattering (alarms.chatter_count within alarms.chatter_window); noisiest sources first; only RUs of the user's organization",
+					"POST   /api/alarms/:alarmId/shelve":                                              "Shelve a nuisance alarm for a bounded time (minutes, reason; max alarms.shelve_max)",
+					"DELETE /api/alarms/:alarmId/shelve":                                              "Unshelve an alarm before its shelve expires",
+					"GET    /api/alarms/filters":                                                      "Get saved alarm filters",
+					"POST   /api/alarms/filters":                                                      "Save alarm filter",
+					"DELETE /api/alarms/filters/:filterId":                                            "Delete saved alarm filter",
+				},
+				"defects": gin.H{
+					"GET    /api/defects":                                     "List defects of the user's organization (ruId, cellId, status, severity, assigneeId)",
+					"POST   /api/defects":                                     "Record defect",
+					"GET    /api/defects/:defectId":                           "Get defect with photos",
+					"PATCH  /api/defects/:defectId":                           "Update defect",
+					"POST   /api/defects/:defectId/status":                    "Change defect status (open → in_work → fixed)",
+					"DELETE /api/defects/:defectId":                           "Delete defect (engineer/admin)",
+					"POST   /api/defects/:defectId/photos":                    "Upload defect photo (multipart, field photo)",
+					"GET    /api/defects/:defectId/photos/:photoId":           "Get defect photo",
+					"GET    /api/defects/:defectId/photos/:photoId/thumbnail": "Get defect photo thumbnail (JPEG, up to 320 px)",
+				},
+				"sla": gin.H{
+					"GET /api/sla/summary?from=&to=&ruId=":                                               "SLA of defects (open → fixed) and work permits (issued → closed) created in the period: totals, breaches, compliance and mean time to close, by severity (targets: sla.defect_targets, sla.permit_targets)",
+					"GET /api/sla/breaches?from=&to=&ruId=&kind=defect|permit&status=open|closed&limit=": "Defects and permits past their SLA deadline, most overdue first",
+				},
+				"assets": gin.H{
+					"GET  /api/assets":                                          "List assets of the user's organization (type, state, ruId, cellId, serial)",
+					"POST /api/assets":                                          "Register asset in the user's organization; organizationId only for platform admin (engineer/admin)",
+					"GET  /api/assets/:assetId":                                 "Get asset",
+					"PATCH /api/assets/:assetId":                                "Update asset nameplate data (engineer/admin)",
+					"POST /api/assets/:assetId/move":                            "Install asset in cell of an RU of the asset's organization, optionally replacing (engineer/admin)",
+					"POST /api/assets/:assetId/state":                           "Send to repair/stock or decommission (engineer/admin)",
+					"GET  /api/assets/:assetId/history":                         "Asset installation and repair history",
+					"GET  /api/rus/:id/cells/:cellId/assets":                    "Assets installed in cell",
+					"GET  /api/rus/:id/cells/:cellId/photos":                    "Equipment photos of cell (EXIF capture time and GPS)",
+					"POST /api/rus/:id/cells/:cellId/photos":                    "Upload equipment photo (multipart, field photo)",
+					"GET  /api/rus/:id/cells/:cellId/photos/:photoId":           "Get equipment photo",
+					"GET  /api/rus/:id/cells/:cellId/photos/:photoId/thumbnail": "Get equipment photo thumbnail (JPEG, up to 320 px)",
+				},
+				"thermography": gin.H{
+					"GET  /api/rus/:id/cells/:cellId/thermal?from=&to=":                             "Thermal camera snapshots of cell with hotspots and thermograms",
+					"POST /api/rus/:id/cells/:cellId/thermal":                                       "Record thermography round: ambient and hotspot temperatures; over-threshold rise raises alarm (engineer/admin)",
+					"GET  /api/rus/:id/cells/:cellId/thermal/trend?from=&to=":                       "Hottest point per snapshot and per-point series with °C/month trend",
+					"POST /api/rus/:id/cells/:cellId/thermal/:snapshotId/photos":                    "Upload thermogram (multipart, field photo) (engineer/admin)",
+					"GET  /api/rus/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId":           "Get thermogram",
+					"GET  /api/rus/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId/thumbnail": "Get thermogram thumbnail (JPEG, up to 320 px)",
+				},
+				"consumers": gin.H{
+					"GET    /api/consumers?ruId=&cellId=&q=":            "Consumer registry (SEZ residents with supply contracts)",
+					"POST   /api/consumers":                             "Create consumer (engineer/admin/org_admin)",
+					"GET    /api/consumers/:consumerId":                 "Get consumer with feeders",
+					"PUT    /api/consumers/:consumerId":                 "Update consumer (engineer/admin/org_admin)",
+					"DELETE /api/consumers/:consumerId":                 "Delete consumer (admin/org_admin)",
+					"POST   /api/consumers/:consumerId/feeders":         "Link consumer to output cell",
+					"DELETE /api/consumers/:consumerId/feeders/:cellId": "Unlink consumer from output cell",
+					"GET    /api/rus/:id/outage-impact?cells=":          "Feeders and consumers losing supply if cells are switched off",
+				},
+				"outages": gin.H{
+					"GET  /api/outages?ruId=&status=&from=&to=": "Planned outages",
+					"POST /api/outages":                         "Request planned outage; response lists conflicting work permits (dispatcher/engineer/admin)",
+					"GET  /api/outages/:outageId":               "Outage with permit conflicts, affected consumers and notifications",
+					"POST /api/outages/:outageId/approve":       "Approve outage, queue consumer emails and calendar entry (engineer/admin, not requester)",
+					"POST /api/outages/:outageId/reject":        "Reject outage (engineer/admin, not requester)",
+					"POST /api/outages/:outageId/cancel":        "Cancel outage; approved outages notify consumers again (requester/engineer/admin)",
+					"GET  /api/outages/:outageId/notifications": "Consumer notification delivery status",
+					"GET  /api/calendar/outages.ics":            "Approved and cancelled outages as iCalendar feed",
+				},
+				"sync": gin.H{
+					"GET  /api/sync/changes?since=&ruId=": "RUs, cells, history records and inspections changed since cursor (offline tablets)",
+					"POST /api/sync/upload":               "Apply offline operations and inspections; per-item report with conflicts (idempotent by clientId)",
+				},
+				"stream": gin.H{
+					"GET  /api/stream?ruId=": "Server-Sent Events: cell and RU status changes, protection trips and new alarms (alarm.created with priority, audible class and recommended action) of visible RUs",
+				},
+				"inventory": gin.H{
+					"GET  /api/inventory/warehouses":                          "Spare parts warehouses of the user's organization",
+					"GET  /api/inventory/items?category=":                     "Inventory items (breaker, fuse, insulator, other)",
+					"POST /api/inventory/items":                               "Create inventory item (engineer/admin)",
+					"GET  /api/inventory/stock?warehouseId=&itemId=&low=true": "Stock levels per warehouse of the user's organization",
+					"POST /api/inventory/stock/receive":                       "Receive stock (engineer/admin)",
+					"POST /api/inventory/stock/min":                           "Set minimum stock level for low-stock alarm (engineer/admin)",
+					"GET  /api/inventory/reservations":                        "Reservations for RUs of the user's organization (targetType, targetId, ruId, status)",
+					"POST /api/inventory/reservations":                        "Reserve items for work permit, maintenance or defect; warehouse and RU must belong to one organization",
+					"POST /api/inventory/reservations/:reservationId/consume": "Consume reserved items",
+					"POST /api/inventory/reservations/:reservationId/release": "Release reservation back to stock",
+				},
+				"devices": gin.H{
+					"GET  /api/devices?ruId=&type=&status=":           "RTU/IED registry of the user's organization with online/offline status and last contact",
+					"GET  /api/devices/:deviceId":                     "Device with mapped cells",
+					"POST /api/devices/:deviceId/check":               "Check device communication now (engineer/admin)",
+					"POST /api/telemetry/devices/:deviceId/heartbeat": "Heartbeat from device or gateway (engineer/admin)",
+					"GET  /api/rus/:id/devices":                       "Devices serving RU",
+				},
+				"commands": gin.H{
+					"POST /api/rus/:id/cells/:cellId/commands":         "Select cell breaker for open/close via RTU (dispatcher/engineer/admin)",
+					"POST /api/rus/:id/commands/:commandId/operate":    "Confirm selected command and operate",
+					"POST /api/rus/:id/commands/:commandId/cancel":     "Cancel selection",
+					"GET  /api/rus/:id/commands?cellId=&state=&limit=": "Control command log",
+					"GET  /api/rus/:id/commands/:commandId":            "Control command with audit steps",
+					"GET  /api/telemetry/commands/pending?deviceId=":   "Commands for RUs of the user's organization awaiting RTU exchange (gateway)",
+					"POST /api/telemetry/commands/:commandId/ack":      "Select/operate confirmation from gateway",
+				},
+				"inspections": gin.H{
+					"GET  /api/inspections/templates?ruType=":                                         "Active checklist templates for RU type",
+					"GET  /api/rus/:id/inspections":                                                   "RU inspections",
+					"POST /api/rus/:id/inspections":                                                   "Submit completed inspection (engineer/admin)",
+					"GET  /api/inspections/:inspectionId":                                             "Inspection of an RU of the user's organization with item results and photos",
+					"POST /api/inspections/:inspectionId/results/:resultId/photos":                    "Upload item photo (multipart, field photo)",
+					"GET  /api/inspections/:inspectionId/results/:resultId/photos/:photoId":           "Get item photo",
+					"GET  /api/inspections/:inspectionId/results/:resultId/photos/:photoId/thumbnail": "Get item photo thumbnail (JPEG, up to 320 px)",
+				},
+				"cell-changes": gin.H{
+					"GET  /api/cell-changes?state=&ruId=":      "Cell info change queue for RUs of the user's organization (engineer/admin)",
+					"POST /api/cell-changes/:changeId/approve": "Approve and apply cell info change",
+					"POST /api/cell-changes/:changeId/reject":  "Reject cell info change",
+				},
+				"calendar": gin.H{
+					"GET  /api/calendar?year=": "Get work calendar exceptions",
+				},
+				"document-types": gin.H{
+					"GET  /api/document-types":                            "Active document types for history records (documentType accepts code or name)",
+					"POST /api/document-numbers":                          "Issue next number of document type for the year in the RU's organization (2025-0147)",
+					"GET  /api/document-numbers/gaps?documentType=&year=": "Issued but unused numbers and numbering holes of the organization (engineer/admin)",
 				},
 				"rus": gin.H{
-					"GET  /api/rus":                          "Get all RUs",
-					"GET  /api/rus/:id":                      "Get RU by ID",
-					"GET  /api/rus/:id/history":              "Get operation history",
-					"PUT  /api/rus/:id/cells/:cellId/status": "Update cell status",
-					"POST /api/rus/:id/history":              "Add history record",
-					"PUT  /api/rus/substations/:id/rus":      "Update RUs on substation",
+					"GET  /api/substations/:id/overview":                 "Get substation with RUs, cells and latest operations",
+					"POST /api/graphql":                                  "GraphQL query over substations, RUs, cells and latest operations",
+					"GET  /api/substations/:id/daily-summary":            "Daily dispatcher summary (?date=YYYY-MM-DD&severity=info|warning|emergency)",
+					"GET  /api/substations/:id/weather":                  "Ambient temperature observations (?from=&to=)",
+					"GET  /api/cells/lookup":                             "Cell card by scanned QR code (?code=URL or ruId/cellId)",
+					"GET  /api/map/geojson":                              "Substations and RUs as GeoJSON with status colors",
+					"GET  /api/capacity/utilization":                     "RUs ranked by bus section utilization (?order=desc|asc)",
+					"GET  /api/energy/consumption":                       "Monthly energy per feeder for billing (?month=YYYY-MM&ruId=&format=json|csv)",
+					"GET  /api/search":                                   "Full-text search over cells, history and RUs of the user's organization",
+					"GET  /api/rus?include=stats&view=":                  "Get all RUs (stats: cell counts by status, active alarms; view=compact: id, name, status, type)",
+					"GET  /api/rus/:id?view=":                            "Get RU by ID (ETag, If-None-Match -> 304; view=compact: cells with status and key measurements)",
+					"GET  /api/rus/:id/cells/pairs":                      "HIGH and LOW side cells paired for the two-sided TP scheme",
+					"GET  /api/rus/:id/cells/:cellId?view=":              "Get cell (ETag, If-None-Match -> 304; view=compact for field tablets)",
+					"GET  /api/rus/:id/cells/:cellId/qr":                 "Cell QR code for sticker (?format=png|svg&size=64-1024)",
+					"GET  /api/rus/:id/history":                          "Get operation history (?limit=&severity=info|warning|emergency&userId=; permission history:read)",
+					"GET  /api/rus/:id/history/export?from=&to=&format=": "Export history for period as JSON or CSV (permission history:export)",
+					"GET  /api/rus/:id/history/:recordId":                "Get history record (op_<ULID> or legacy UUID)",
+					"POST /api/rus/:id/history/:recordId/corrections":    "Append correction to history record (reason, corrected fields); original is kept",
+					"GET  /api/rus/:id/cells/:cellId/lock":               "Get cell lock (LOTO) and lock history",
+					"POST /api/rus/:id/cells/:cellId/lock":               "Place lock and tag on cell",
+					"DELETE /api/rus/:id/cells/:cellId/lock":             "Remove cell lock (engineer/admin)",
+					"PUT  /api/rus/:id/cells/:cellId/operation-counter":  "Breaker operation limit, initial count or reset (engineer/admin); near-limit breakers appear in task inbox",
+					"PUT  /api/rus/:id/cells/:cellId/status":             "Update cell status",
+					"PUT  /api/rus/:id/status":                           "Set RU status manually (status, reason); overrides the status computed from cells and alarms",
+					"DELETE /api/rus/:id/status":                         "Clear manual RU status; status follows operationalState again",
+					"POST /api/rus/:id/history":                          "Add history record (severity: info, warning or emergency; documentType: code or name from /api/document-types; order/permit number issued by server when omitted; operator and userId taken from token; responsibleUserId - responsible user of the RU organization, fills responsiblePerson when omitted; time at most history.max_future_skew ahead, older than history.max_backdate needs history:backdate; clientTime - device clock for drift detection)",
+					"PUT  /api/rus/substations/:id/rus":                  "Replace RU list of substation; RUs not listed are unassigned (admin, org_admin)",
+
+					"GET  /api/rus/:id/cells/:cellId/status/confirmations":                        "Pending two-person confirmations",
+					"GET  /api/rus/:id/cells/:cellId/measurements":                                "Cell telemetry (auto raw/1m/15m/1h) with anomaly scores and baseline",
+					"POST /api/telemetry/measurements":                                            "Record telemetry batch for cells of RUs of the user's organization (engineer/admin)",
+					"POST /api/telemetry/weather":                                                 "Record ambient temperature at substations (engineer/admin)",
+					"POST /api/telemetry/vision-readings":                                         "Gauge readings recognized from photos; confident ones become telemetry (engineer/admin)",
+					"GET  /api/rus/:id/cells/:cellId/vision-readings?metric=&accepted=&from=&to=": "Recognized gauge readings with confidence and source image",
+					"POST /api/telemetry/vision-indications":                                      "Cell ON/OFF by indicator lamps on photos; reconciled with the journal, mismatches raise discrepancy alarms (engineer/admin)",
+					"GET  /api/rus/:id/cells/:cellId/vision-indications?result=&from=&to=":        "Recognized indicator states with reconciliation results",
+					"GET  /api/rus/:id/cells/:cellId/load-temperature":                            "Hourly load vs ambient temperature (?metric=load|current&from=&to=)",
+					"GET  /api/rus/:id/cells/:cellId/meter-readings":                              "Energy meter readings of an output cell (?from=&to=)",
+					"POST /api/rus/:id/cells/:cellId/meter-readings":                              "Record meter reading taken on site",
+					"POST /api/telemetry/meter-readings":                                          "Record energy meter readings batch (engineer/admin)",
+					"POST /api/telemetry/voltages":                                                "Record 0,4 kV phase voltages of low-side cells; leaving ±10 % raises alarms (engineer/admin)",
+					"GET  /api/rus/:id/voltage-quality?from=&to=&cellId=&format=json|csv":         "Daily voltage min/max/avg per phase with compliance; csv - power quality report",
+					"GET  /api/rus/:id/forecast":                                                  "Load forecast per transformer/section (?cellId=&horizon=24h|7d)",
+					"GET  /api/rus/:id/transformers?days=":                                        "Transformers with loading, hot-spot temperature and IEC 60076-7 loss of life (default 30 days)",
+					"GET  /api/rus/:id/runtime?minHours=":                                         "Runtime hours of RU (energized), transformers and cells (ON), for maintenance by hours",
+					"PUT  /api/rus/:id/transformers/:transformerId":                               "Update rated power, voltages, tap position and linked cells (engineer/admin)",
+					"PUT  /api/rus/:id/transformers/:transformerId/tap":                           "Record tap change manually with reason (engineer/admin)",
+					"GET  /api/rus/:id/transformers/:transformerId/tap-changes?source=&from=&to=": "Tap changer history",
+					"POST /api/telemetry/tap-positions":                                           "Tap positions from telemetry gateway by cell; changes are logged, excessive daily operations raise alarms (engineer/admin)",
+					"GET  /api/rus/:id/forecast/runs":                                             "Past forecasts with accuracy (MAE/MAPE)",
+					"POST /api/rus/:id/cells/:cellId/status/confirmations/:confirmationId":        "Confirm critical cell switching",
+					"GET  /api/rus/:id/cells/:cellId/revisions":                                   "Cell configuration history with diffs",
+					"POST /api/rus/:id/cells/:cellId/revisions/:revision/restore":                 "Restore cell configuration (engineer/admin)",
+					"GET  /api/rus/:id/sections":                                                  "Bus sections with status (energized/partial/de_energized/maintenance) and load",
+					"GET  /api/rus/:id/sections/:sectionId":                                       "Bus section with its cells",
+					"PUT  /api/rus/:id/sections/:sectionId":                                       "Rename bus section (engineer/admin)",
+					"POST /api/rus/:id/sections/:sectionId/de-energize":                           "Draft switching order to de-energize the section (reason); cells are not switched",
+					"POST /api/rus/:id/sections/:sectionId/energize":                              "Draft switching order to energize the section (reason); cells are not switched",
+					"GET  /api/rus/:id/switching-orders":                                          "Switching order drafts of the RU",
+					"GET  /api/rus/:id/switching-orders/:orderId":                                 "Switching order with ordered steps and skipped cells",
+					"GET  /api/rus/:id/faults?cellId=&tripType=&from=&to=":                        "Relay trip / fault log with linked alarms",
+					"GET  /api/rus/:id/faults/:faultId":                                           "Fault event",
+					"POST /api/rus/:id/faults":                                                    "Record fault manually (engineer/admin)",
+					"POST /api/telemetry/faults":                                                  "Record relay trip from telemetry gateway (engineer/admin)",
+					"POST /api/rus/:id/faults/:faultId/comtrade":                                  "Upload COMTRADE record (multipart cfg+dat[+hdr,inf] or cff; engineer/admin)",
+					"GET  /api/rus/:id/faults/:faultId/comtrade":                                  "COMTRADE records of fault",
+					"GET  /api/rus/:id/comtrade?faultId=&from=&to=":                               "Oscillography catalog",
+					"GET  /api/rus/:id/comtrade/:recordId":                                        "COMTRADE metadata with channels",
+					"GET  /api/rus/:id/comtrade/:recordId/files/:kind":                            "Download COMTRADE file (cfg, dat, hdr, inf, cff)",
 				},
 				"admin": gin.H{
-					"GET    /api/admin/users":         "Get all users",
-					"POST   /api/admin/users":         "Create user",
-					"PUT    /api/admin/users/:id":     "Update user",
-					"DELETE /api/admin/users/:id":     "Delete user",
-					"POST   /api/admin/rus":           "Create RU",
-					"POST   /api/admin/rus/:id/cells": "Create cells",
+					"GET    /api/admin/users":                                   "Get users (org admins: own organization only)",
+					"POST   /api/admin/users":                                   "Create user",
+					"GET    /api/admin/users/pending":                           "Registrations awaiting verification or approval",
+					"POST   /api/admin/users/:id/approve":                       "Approve registration and assign role",
+					"POST   /api/admin/users/:id/reject":                        "Reject registration with reason",
+					"PUT    /api/admin/users/:id":                               "Update user",
+					"DELETE /api/admin/users/:id":                               "Delete user",
+					"POST   /api/admin/users/:id/impersonate":                   "Short-lived token acting as user (reason required, audited)",
+					"GET    /api/admin/users/:id/activity":                      "User login history and recent changes from audit log",
+					"GET    /api/admin/users/:id/substations":                   "Substations assigned to user",
+					"PUT    /api/admin/users/:id/substations":                   "Assign substations (dispatchers follow their RUs)",
+					"GET    /api/admin/audit":                                   "Audit log (?userId=&action=&before=&limit=)",
+					"GET    /api/admin/integrity/verify?chain=":                 "Verify hash chain of operation_records, audit_entries or operation_corrections (tampering check)",
+					"GET    /api/admin/stats":                                   "System usage: users by role, operations per day (30 days), most active RUs, attachment storage",
+					"GET    /api/admin/history/clock-skew?days=":                "Device clock difference from server by history records (drift detection)",
+					"GET    /api/admin/sms/messages?status=&kind=&limit=":       "SMS about critical alarms and expiring permits with delivery status",
+					"GET    /api/admin/sms/usage?month=YYYY-MM":                 "Monthly SMS usage: messages and billed segments by status, kind and organization",
+					"GET    /api/admin/organizations":                           "List organizations (tenants)",
+					"POST   /api/admin/organizations":                           "Create organization",
+					"POST   /api/admin/organizations/:id/substations":           "Move substation with its RUs and history to organization",
+					"POST   /api/admin/rus":                                     "Create RU",
+					"GET    /api/admin/rus/archived":                            "Archived RUs",
+					"PUT    /api/admin/rus/:id":                                 "Replace RU passport data",
+					"DELETE /api/admin/rus/:id":                                 "Delete RU without history or records with its cells and configuration, otherwise archive (?archive=true forces archive)",
+					"POST   /api/admin/rus/:id/restore":                         "Restore archived RU",
+					"POST   /api/admin/rus/:id/cells":                           "Create cells in one transaction (all or nothing, per-row errors; numbers normalized to \"яч.N\", assigned when omitted)",
+					"GET    /api/admin/rus/:id/cells/next-number?voltageLevel=": "Next free \"яч.N\" cell number for RU side",
+					"PUT    /api/admin/rus/:id/cells/:cellId/critical":          "Set critical cell flag",
+					"PUT    /api/admin/rus/:id/cells/:cellId/pair":              "Pair cell with opposite side cell of the same RU",
+					"DELETE /api/admin/rus/:id/cells/:cellId/pair":              "Unpair cell",
+					"GET    /api/admin/rus/:id/export":                          "Export RU snapshot (RU + cells)",
+					"POST   /api/admin/rus/import":                              "Import RU snapshot (skip/overwrite/new-id)",
+					"PUT    /api/admin/rus/:id/location":                        "Set RU coordinates for map",
+					"PUT    /api/admin/substations/:id/location":                "Set substation coordinates for map",
+					"PUT    /api/admin/rus/:id/capacity":                        "Set RU bus rated current (A) per side",
+					"GET    /api/admin/rus/:id/notification-rules":              "Get notification rules",
+					"POST   /api/admin/rus/:id/notification-rules":              "Create notification rule",
+					"DELETE /api/admin/rus/:id/notification-rules/:ruleId":      "Delete notification rule",
+					"PUT    /api/admin/calendar/:date":                          "Set calendar day",
+					"DELETE /api/admin/calendar/:date":                          "Delete calendar day",
+					"GET    /api/admin/compat/date-formats":                     "Legacy date format usage by client",
+					"GET    /api/admin/events":                                  "Domain event outbox",
+					"POST   /api/admin/events/:eventId/requeue":                 "Requeue failed event",
+					"GET    /api/admin/events/broker":                           "Event broker delivery stats",
+					"GET    /api/admin/polling":                                 "Telemetry polling state",
+					"POST   /api/admin/polling/pause":                           "Pause polling for adapter and/or RU",
+					"POST   /api/admin/polling/resume":                          "Resume polling",
+					"POST   /api/admin/maintenance/jobs":                        "Start DB maintenance job",
+					"GET    /api/admin/maintenance/jobs":                        "List DB maintenance jobs",
+					"GET    /api/admin/maintenance/jobs/:jobId":                 "DB maintenance job progress",
+					"GET    /api/admin/maintenance/cells?ruId=":                 "Report duplicate cells (same number and voltage level in RU) and cells of missing RUs, with references",
+					"POST   /api/admin/maintenance/cells/cleanup":               "Merge or delete duplicate cells, delete unreferenced cells of missing RUs (dryRun for preview)",
+					"GET    /api/admin/jobs":                                    "Scheduled background jobs and last run status",
+					"POST   /api/admin/jobs/:name/run":                          "Run scheduled job now",
+					"GET    /api/admin/inspections/templates":                   "All checklist templates",
+					"POST   /api/admin/inspections/templates":                   "Create checklist template",
+					"PUT    /api/admin/inspections/templates/:templateId":       "Replace checklist template",
+					"DELETE /api/admin/inspections/templates/:templateId":       "Delete checklist template",
+					"GET    /api/admin/document-types":                          "All document types, including inactive",
+					"POST   /api/admin/document-types":                          "Create document type (code, name)",
+					"PUT    /api/admin/document-types/:code":                    "Rename or deactivate document type",
+					"DELETE /api/admin/document-types/:code":                    "Delete document type not used in history",
+					"POST   /api/admin/inventory/warehouses":                    "Create spare parts warehouse (organizationId, default - own organization)",
+					"POST   /api/admin/devices":                                 "Register RTU/IED device",
+					"PUT    /api/admin/devices/:deviceId":                       "Update device",
+					"DELETE /api/admin/devices/:deviceId":                       "Delete device",
+					"PUT    /api/admin/read-only":                               "Enable/disable read-only mode (mutations return 503)",
+					"GET    /api/admin/settings":                                "System settings with types and defaults",
+					"PUT    /api/admin/settings":                                "Update system settings (null resets to default)",
 				},
 			},
 		})
@@ -246,38 +1502,189 @@ func main() {
 
 	// 404 handler
 	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Not Found",
-			"message": "The requested endpoint does not exist",
-			"path":    c.Request.URL.Path,
-		})
+		apperrors.Respond(c, apperrors.New(apperrors.KindNotFound, "route_not_found", "The requested endpoint does not exist").
+			WithDetails(gin.H{"path": c.Request.URL.Path}))
 	})
 
 	log.Printf("\n🚀 Server starting on http://localhost%s", cfg.ServerPort)
-	log.Println("📋 Available endpoints:")
+	log.Println("📋 Available endpoints (also under /api/v1, /api is a deprecated alias):")
 	log.Println("")
 	log.Println("    🔓 Public endpoints:")
 	log.Println("        GET  /api/substations/:id              - Get substation info (public)")
+	log.Println("        GET  /api/dictionaries/cell-statuses   - Get localized cell statuses")
+	log.Println("        GET  /api/dictionaries/ru-statuses     - Get localized RU statuses")
 	log.Println("        POST /api/auth/register                - Register user")
 	log.Println("        POST /api/auth/login                   - Login user")
+	log.Println("        POST /api/auth/verify-email            - Confirm email address")
+	log.Println("        POST /api/auth/resend-verification     - Resend verification letter")
+	log.Println("        GET  /healthz                          - Liveness probe")
+	log.Println("        GET  /readyz                           - Readiness probe (503 if database is down)")
 	log.Println("        GET  /health                           - Health check")
+	log.Println("        GET  /metrics                          - Prometheus business gauges (METRICS_TOKEN bearer if set)")
 	log.Println("")
 	log.Println("    🔐 Protected endpoints (require JWT):")
 	log.Println("        GET  /api/auth/me                      - Get current user")
+	log.Println("        GET  /api/me/tasks                     - Get personal task inbox")
+	log.Println("        GET  /api/me/notifications             - Notification center")
+	log.Println("        GET  /api/me/notifications/unread-count - Unread notification count")
+	log.Println("        POST /api/me/notifications/:id/read    - Mark notification read")
+	log.Println("        POST /api/me/notifications/read-all    - Mark all notifications read")
+	log.Println("        GET  /api/me/push/public-key           - VAPID public key")
+	log.Println("        GET  /api/me/push/subscriptions        - Browser push subscriptions")
+	log.Println("        POST /api/me/push/subscriptions        - Subscribe browser to push")
+	log.Println("        DELETE /api/me/push/subscriptions/:id  - Unsubscribe browser")
+	log.Println("        GET  /api/me/subscriptions             - Followed RUs/cells")
+	log.Println("        POST /api/me/subscriptions             - Follow an RU or cell")
+	log.Println("        DELETE /api/me/subscriptions/:id       - Unfollow")
+	log.Println("        POST /api/me/calendar-feed             - Issue iCal subscription key")
+	log.Println("        GET  /api/calendar.ics?token=          - iCal feed for Outlook/Google Calendar")
+	log.Println("        GET  /api/calendar                     - Get work calendar")
+	log.Println("        GET  /api/alarms                       - List alarms")
+	log.Println("        POST /api/alarms/ack                   - Bulk acknowledge alarms")
+	log.Println("        GET  /api/alarms/stats                 - Alarm statistics and top bad actors")
+	log.Println("        POST /api/alarms/:alarmId/shelve       - Shelve alarm")
+	log.Println("        DELETE /api/alarms/:alarmId/shelve     - Unshelve alarm")
+	log.Println("        GET  /api/defects                      - List equipment defects")
+	log.Println("        POST /api/defects                      - Record equipment defect")
+	log.Println("        GET  /api/sla/summary                  - SLA metrics of defects and permits")
+	log.Println("        GET  /api/sla/breaches                 - SLA breaches of defects and permits")
+	log.Println("        POST /api/rus/:id/inspections          - Submit RU inspection (engineer/admin)")
+	log.Println("        GET  /api/assets                       - Asset registry")
+	log.Println("        POST /api/assets/:assetId/move         - Install asset in cell (engineer/admin)")
+	log.Println("        GET  /api/consumers                    - Consumer registry")
+	log.Println("        POST /api/consumers/:consumerId/feeders - Link consumer to output cell")
+	log.Println("        GET  /api/rus/:id/outage-impact        - Consumers affected by switching off cells")
+	log.Println("        POST /api/outages                      - Request planned outage")
+	log.Println("        POST /api/outages/:outageId/approve    - Approve outage and notify consumers")
+	log.Println("        GET  /api/calendar/outages.ics         - Planned outages iCal feed")
+	log.Println("        GET  /api/sync/changes                 - Offline sync change feed")
+	log.Println("        POST /api/sync/upload                  - Upload offline operations and inspections")
+	log.Println("        GET  /api/stream                       - Live event stream (SSE)")
+	log.Println("        GET  /api/inventory/stock              - Spare parts stock levels")
+	log.Println("        POST /api/inventory/reservations       - Reserve spare parts for permit/maintenance")
+	log.Println("        GET  /api/cell-changes                 - Cell info change queue (engineer/admin)")
+	log.Println("        GET  /api/search                       - Full-text search (cells, history, RUs)")
+	log.Println("        GET  /api/substations/:id/overview     - Get substation overview (RUs, cells, operations)")
+	log.Println("        POST /api/graphql                      - GraphQL query (substations, RUs, cells, operations)")
+	log.Println("        GET  /api/substations/:id/daily-summary - Daily dispatcher summary")
+	log.Println("        GET  /api/substations/:id/weather      - Ambient temperature at substation")
+	log.Println("        GET  /api/cells/lookup                 - Cell card by scanned QR code")
+	log.Println("        GET  /api/document-types               - Document types for history records")
+	log.Println("        POST /api/document-numbers             - Issue next order/permit number")
+	log.Println("        GET  /api/document-numbers/gaps        - Numbering gaps (engineer/admin)")
+	log.Println("        GET  /api/map/geojson                  - Grid map (GeoJSON)")
+	log.Println("        GET  /api/capacity/utilization         - RU utilization ranking")
+	log.Println("        GET  /api/energy/consumption           - Monthly energy per feeder (billing)")
 	log.Println("        GET  /api/rus                          - Get all RUs")
-	log.Println("        GET  /api/rus/:id                      - Get RU by ID")
+	log.Println("        GET  /api/rus/:id                      - Get RU by ID (?view=compact for tablets)")
+	log.Println("        GET  /api/rus/:id/cells/pairs          - HIGH/LOW cells paired for TP scheme")
+	log.Println("        GET  /api/rus/:id/cells/:cellId        - Get cell")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/qr     - Cell QR code (PNG/SVG)")
+	log.Println("        POST /api/rus/:id/cells/:cellId/photos - Upload equipment photo (EXIF, thumbnail)")
+	log.Println("        POST /api/rus/:id/cells/:cellId/thermal - Record thermography snapshot (hotspots)")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/thermal/trend - Hotspot temperature trend")
 	log.Println("        GET  /api/rus/:id/history              - Get history")
+	log.Println("        GET  /api/rus/:id/history/export       - Export history (JSON or CSV)")
+	log.Println("        GET  /api/rus/:id/history/:recordId    - Get history record")
+	log.Println("        POST /api/rus/:id/history/:recordId/corrections - Correct history record")
 	log.Println("        PUT  /api/rus/:id/cells/:cellId/status - Update cell status")
+	log.Println("        PUT  /api/rus/:id/status               - Set RU status manually (with reason)")
+	log.Println("        DELETE /api/rus/:id/status             - Clear manual RU status")
+	log.Println("        POST /api/rus/:id/cells/:cellId/lock   - Place cell lock (LOTO)")
+	log.Println("        DELETE /api/rus/:id/cells/:cellId/lock - Remove cell lock (engineer/admin)")
+	log.Println("        PUT  /api/rus/:id/cells/:cellId/operation-counter - Breaker operation counter (engineer/admin)")
+	log.Println("        POST /api/rus/:id/cells/:cellId/status/confirmations/:confirmationId - Confirm critical switching")
 	log.Println("        POST /api/rus/:id/history              - Add history record")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/measurements - Get cell telemetry")
+	log.Println("        POST /api/telemetry/measurements       - Record telemetry batch")
+	log.Println("        POST /api/telemetry/weather            - Record ambient temperature")
+	log.Println("        POST /api/telemetry/vision-readings    - Record gauge readings recognized from photos")
+	log.Println("        POST /api/telemetry/vision-indications - Record indicator lamp states recognized from photos")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/vision-indications - Indicator states and reconciliation results")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/load-temperature - Load vs ambient temperature")
+	log.Println("        GET  /api/rus/:id/cells/:cellId/meter-readings - Get meter readings")
+	log.Println("        POST /api/rus/:id/cells/:cellId/meter-readings - Record meter reading")
+	log.Println("        POST /api/telemetry/meter-readings     - Record meter readings batch")
+	log.Println("        POST /api/telemetry/voltages           - Record low-side phase voltages")
+	log.Println("        GET  /api/rus/:id/voltage-quality      - Daily voltage quality summaries and export")
+	log.Println("        GET  /api/rus/:id/forecast             - Load forecast (24h/7d)")
+	log.Println("        GET  /api/rus/:id/transformers         - Transformer loading and loss of life")
+	log.Println("        GET  /api/rus/:id/runtime              - Runtime hours of RU, transformers and cells")
+	log.Println("        PUT  /api/rus/:id/transformers/:transformerId - Update transformer data (engineer/admin)")
+	log.Println("        PUT  /api/rus/:id/transformers/:transformerId/tap - Record tap change (engineer/admin)")
+	log.Println("        GET  /api/rus/:id/transformers/:transformerId/tap-changes - Tap changer history")
+	log.Println("        POST /api/telemetry/tap-positions      - Record tap positions from telemetry")
+	log.Println("        GET  /api/rus/:id/forecast/runs        - Forecast accuracy history")
+	log.Println("        POST /api/telemetry/faults             - Record relay trip from telemetry")
+	log.Println("        GET  /api/rus/:id/sections             - Bus sections with status and load")
+	log.Println("        POST /api/rus/:id/sections/:sectionId/de-energize - Draft switching order for section")
+	log.Println("        POST /api/rus/:id/sections/:sectionId/energize - Draft switching order for section")
+	log.Println("        GET  /api/rus/:id/switching-orders     - Switching order drafts")
+	log.Println("        GET  /api/rus/:id/faults               - RU fault log")
+	log.Println("        GET  /api/rus/:id/comtrade             - Oscillography (COMTRADE) catalog")
+	log.Println("        GET  /api/devices                      - RTU/IED communication status")
+	log.Println("        POST /api/rus/:id/cells/:cellId/commands - Select-before-operate control command")
 	log.Println("        PUT  /api/rus/substations/:id/rus      - Update RUs on substation")
 	log.Println("")
 	log.Println("    👑 Admin endpoints:")
 	log.Println("        GET    /api/admin/users                - Get all users")
 	log.Println("        POST   /api/admin/users                - Create user")
+	log.Println("        GET    /api/admin/users/pending        - Registrations awaiting approval")
+	log.Println("        POST   /api/admin/users/:id/approve    - Approve registration")
+	log.Println("        POST   /api/admin/users/:id/reject     - Reject registration")
 	log.Println("        PUT    /api/admin/users/:id            - Update user")
 	log.Println("        DELETE /api/admin/users/:id            - Delete user")
+	log.Println("        POST   /api/admin/users/:id/impersonate - Act as user (audited)")
+	log.Println("        GET    /api/admin/audit                - Audit log")
+	log.Println("        GET    /api/admin/integrity/verify     - Verify journal hash chain")
+	log.Println("        GET    /api/admin/stats                - System usage statistics")
+	log.Println("        GET    /api/admin/history/clock-skew   - Device clock drift")
+	log.Println("        GET    /api/admin/sms/messages         - SMS delivery log")
+	log.Println("        GET    /api/admin/sms/usage            - Monthly SMS usage report")
+	log.Println("        GET    /api/admin/users/:id/activity   - User logins and recent changes")
+	log.Println("        GET    /api/admin/users/:id/substations - Assigned substations")
+	log.Println("        PUT    /api/admin/users/:id/substations - Assign substations")
+	log.Println("        GET    /api/admin/organizations        - List organizations")
+	log.Println("        POST   /api/admin/organizations        - Create organization")
 	log.Println("        POST   /api/admin/rus                  - Create RU")
+	log.Println("        GET    /api/admin/rus/archived         - Archived RUs")
+	log.Println("        PUT    /api/admin/rus/:id              - Update RU")
+	log.Println("        DELETE /api/admin/rus/:id              - Delete or archive RU")
+	log.Println("        POST   /api/admin/rus/:id/restore      - Restore archived RU")
 	log.Println("        POST   /api/admin/rus/:id/cells        - Create cells")
+	log.Println("        GET    /api/admin/rus/:id/cells/next-number - Next free cell number")
+	log.Println("        PUT    /api/admin/rus/:id/cells/:cellId/critical - Set critical cell flag")
+	log.Println("        PUT    /api/admin/rus/:id/cells/:cellId/pair - Pair HIGH/LOW cells")
+	log.Println("        DELETE /api/admin/rus/:id/cells/:cellId/pair - Unpair cell")
+	log.Println("        GET    /api/admin/rus/:id/export       - Export RU snapshot")
+	log.Println("        POST   /api/admin/rus/import           - Import RU snapshot")
+	log.Println("        PUT    /api/admin/rus/:id/location     - Set RU coordinates")
+	log.Println("        PUT    /api/admin/substations/:id/location - Set substation coordinates")
+	log.Println("        PUT    /api/admin/rus/:id/capacity     - Set RU bus rated current")
+	log.Println("        GET    /api/admin/rus/:id/notification-rules         - Get notification rules")
+	log.Println("        POST   /api/admin/rus/:id/notification-rules         - Create notification rule")
+	log.Println("        DELETE /api/admin/rus/:id/notification-rules/:ruleId - Delete notification rule")
+	log.Println("        PUT    /api/admin/calendar/:date       - Set calendar day")
+	log.Println("        DELETE /api/admin/calendar/:date       - Delete calendar day")
+	log.Println("        GET    /api/admin/compat/date-formats  - Legacy date format usage")
+	log.Println("        GET    /api/admin/events               - Domain event outbox")
+	log.Println("        POST   /api/admin/events/:eventId/requeue - Requeue failed event")
+	log.Println("        GET    /api/admin/events/broker        - Event broker delivery stats")
+	log.Println("        GET    /api/admin/polling              - Telemetry polling state")
+	log.Println("        POST   /api/admin/polling/pause        - Pause polling")
+	log.Println("        POST   /api/admin/polling/resume       - Resume polling")
+	log.Println("        POST   /api/admin/maintenance/jobs     - Start DB maintenance job")
+	log.Println("        GET    /api/admin/maintenance/jobs     - List DB maintenance jobs")
+	log.Println("        GET    /api/admin/maintenance/jobs/:jobId - DB maintenance job progress")
+	log.Println("        GET    /api/admin/maintenance/cells    - Duplicate and orphan cells report")
+	log.Println("        POST   /api/admin/maintenance/cells/cleanup - Merge or delete duplicate/orphan cells")
+	log.Println("        GET    /api/admin/jobs                 - Scheduled background jobs")
+	log.Println("        POST   /api/admin/jobs/:name/run       - Run scheduled job now")
+	log.Println("        POST   /api/admin/inspections/templates - Create checklist template")
+	log.Println("        POST   /api/admin/document-types       - Create document type")
+	log.Println("        PUT    /api/admin/read-only            - Enable/disable read-only mode")
+	log.Println("        GET    /api/admin/settings             - System settings")
+	log.Println("        PUT    /api/admin/settings             - Update system settings")
 	log.Println("")
 
 	// Запускаем сервер
@@ -286,14 +1693,6 @@ func main() {
 	}
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
-
 func checkAndSeedTestData(db *gorm.DB) {
 	// Проверяем существование тестового пользователя админа
 	var adminCount int64
@@ -317,56 +1716,76 @@ func checkAndSeedTestData(db *gorm.DB) {
 			log.Println("✅ Test admin user created")
 		}
 	}
-	// ================== ТП-1Л ==================
-	createTP1L(db)
-	// ================== ТП-1И ==================
-	createTP1I(db)
-	// ================== ТП-2И ==================
-	createTP2I(db)
-	// ================== ТП-2Л ==================
-	createTP2L(db)
-	// ================== ТП-3И ==================
-	createTP3I(db)
-	// ================== ТП-4И ==================
-	createTP4I(db)
-	// ================== ТП-5И ==================
-	createTP5I(db)
-	// ================== ТП-Общежитие ==================
-	createTPObshyaga(db)
-	// ================== ТП-Очистные ==================
-	createTPOchistnye(db)
-	// ================== ТП-Общежитие ==================
-	createTPVodazabor(db)
-	// ================== ТП-Общежитие ==================
-	createTPRazvyazka(db)
-
-	// ================== КРУ-БМ-1И ==================
-	createKRU_BM_1I(db)
-
-	// ================== КРУ-БМ-2И ==================
-	createKRU_BM_2I(db)
-
-	// ================== КРУ-БМ-3И ==================
-	createKRU_BM_3I(db)
-
-	// ================== КРУ-БМ-4И ==================
-	createKRU_BM_4I(db)
-
-	// ================== КРУ-БМ-5И ==================
-	createKRU_BM_5I(db)
-	// ================== КРУ-БМ-1Л ==================
-	createKRU_BM_1L(db)
+
+	// Наборы начальных данных применяются каждый своей транзакцией; неизменившиеся
+	// наборы пропускаются по контрольной сумме
+	for _, fixture := range seedFixtures() {
+		result, err := repository.ApplySeedFixture(db, fixture)
+		if err != nil {
+			log.Printf("⚠️ %v", err)
+			continue
+		}
+		if result.Outcome != models.SeedUnchanged {
+			log.Printf("✅ Seed fixture %s %s: %d created, %d updated", result.Fixture, result.Outcome, result.Created, result.Updated)
+		}
+	}
 
 	log.Println("🎉 Test data check completed!")
 }
-func createTP1I(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-1i").Count(&tp4iCount)
 
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-1И...")
+// seedFixtures - наборы начальных данных в порядке применения: подстанции должны
+// существовать до РУ, которые на них ссылаются
+func seedFixtures() []models.SeedFixture {
+	return []models.SeedFixture{
+		fixtureSubstations(),
+		fixtureTP1L(),
+		fixtureTP1I(),
+		fixtureTP2I(),
+		fixtureTP2L(),
+		fixtureTP3I(),
+		fixtureTP4I(),
+		fixtureTP5I(),
+		fixtureTPObshyaga(),
+		fixtureTPOchistnye(),
+		fixtureTPVodazabor(),
+		fixtureTPRazvyazka(),
+		fixtureKRU_BM_1I(),
+		fixtureKRU_BM_2I(),
+		fixtureKRU_BM_3I(),
+		fixtureKRU_BM_4I(),
+		fixtureKRU_BM_5I(),
+		fixtureKRU_BM_1L(),
+	}
+}
+
+func fixtureSubstations() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "substations",
+		Substations: []models.Substation{
+			{
+				ID:             "ps-164",
+				Name:           "ПС-164",
+				Location:       "Северная промзона Хоргос",
+				Description:    "Главная понизительная подстанция №164. Обслуживает северную часть промзоны.",
+				Voltage:        "110/10 кВ",
+				InstalledPower: "2 × 25 МВА",
+			},
+			{
+				ID:             "ps-64",
+				Name:           "ПС-64",
+				Location:       "Южная промзона Хоргос",
+				Description:    "Резервная понизительная подстанция №64. Обслуживает южную часть промзоны.",
+				Voltage:        "110/10 кВ",
+				InstalledPower: "2 × 25 МВА",
+			},
+		},
+	}
+}
 
-		tp4i := models.RUInfo{
+func fixtureTP1I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-1i",
+		RU: &models.RUInfo{
 			ID:               "tp-1i",
 			Name:             "ТП-1И",
 			Voltage:          "10/0,4 кВ",
@@ -379,7 +1798,7 @@ func createTP1I(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -395,29 +1814,15 @@ func createTP1I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-1И: %v", err)
-			return
-		}
-		log.Println("✅ ТП-1И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP1ICells()
-		createCells(db, cells, "ТП-1И")
-	} else {
-		log.Printf("✅ ТП-1И уже существует")
+		},
+		Cells: createTP1ICells(),
 	}
 }
-func createTP1L(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-1l").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-1Л...")
 
-		tp4i := models.RUInfo{
+func fixtureTP1L() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-1l",
+		RU: &models.RUInfo{
 			ID:               "tp-1l",
 			Name:             "ТП-1Л",
 			Voltage:          "10/0,4 кВ",
@@ -430,7 +1835,7 @@ func createTP1L(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -446,29 +1851,15 @@ func createTP1L(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-1Л: %v", err)
-			return
-		}
-		log.Println("✅ ТП-4И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP1LCells()
-		createCells(db, cells, "ТП-1Л")
-	} else {
-		log.Printf("✅ ТП-1Л уже существует")
+		},
+		Cells: createTP1LCells(),
 	}
 }
-func createTP2I(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-2i").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-2И...")
 
-		tp4i := models.RUInfo{
+func fixtureTP2I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-2i",
+		RU: &models.RUInfo{
 			ID:               "tp-2i",
 			Name:             "ТП-2И",
 			Voltage:          "10/0,4 кВ",
@@ -481,7 +1872,7 @@ func createTP2I(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -497,29 +1888,15 @@ func createTP2I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-2И: %v", err)
-			return
-		}
-		log.Println("✅ ТП-2И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP2ICells()
-		createCells(db, cells, "ТП-2И")
-	} else {
-		log.Printf("✅ ТП-2И уже существует")
+		},
+		Cells: createTP2ICells(),
 	}
 }
-func createTP2L(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-2l").Count(&tp4iCount)
 
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-2Л...")
-
-		tp4i := models.RUInfo{
+func fixtureTP2L() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-2l",
+		RU: &models.RUInfo{
 			ID:               "tp-2l",
 			Name:             "ТП-2Л",
 			Voltage:          "10/0,4 кВ",
@@ -532,7 +1909,7 @@ func createTP2L(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -548,29 +1925,15 @@ func createTP2L(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-2Л: %v", err)
-			return
-		}
-		log.Println("✅ ТП-2Л created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP2LCells()
-		createCells(db, cells, "ТП-2Л")
-	} else {
-		log.Printf("✅ ТП-2Л уже существует")
+		},
+		Cells: createTP2LCells(),
 	}
 }
-func createTP3I(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-3i").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-3И...")
 
-		tp4i := models.RUInfo{
+func fixtureTP3I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-3i",
+		RU: &models.RUInfo{
 			ID:               "tp-3i",
 			Name:             "ТП-3И",
 			Voltage:          "10/0,4 кВ",
@@ -583,7 +1946,7 @@ func createTP3I(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -599,29 +1962,15 @@ func createTP3I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-3И: %v", err)
-			return
-		}
-		log.Println("✅ ТП-3И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP3ICells()
-		createCells(db, cells, "ТП-3И")
-	} else {
-		log.Printf("✅ ТП-3И уже существует")
+		},
+		Cells: createTP3ICells(),
 	}
 }
-func createTP4I(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-4i").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-4И...")
 
-		tp4i := models.RUInfo{
+func fixtureTP4I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-4i",
+		RU: &models.RUInfo{
 			ID:               "tp-4i",
 			Name:             "ТП-4И",
 			Voltage:          "10/0,4 кВ",
@@ -634,7 +1983,7 @@ func createTP4I(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -650,30 +1999,15 @@ func createTP4I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-64",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-4И: %v", err)
-			return
-		}
-		log.Println("✅ ТП-4И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP4ICells()
-		createCells(db, cells, "ТП-4И")
-	} else {
-		log.Printf("✅ ТП-4И уже существует")
+		},
+		Cells: createTP4ICells(),
 	}
 }
 
-func createTP5I(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-5i").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-5И...")
-
-		tp4i := models.RUInfo{
+func fixtureTP5I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-5i",
+		RU: &models.RUInfo{
 			ID:               "tp-5i",
 			Name:             "ТП-5И",
 			Voltage:          "10/0,4 кВ",
@@ -686,7 +2020,7 @@ func createTP5I(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -702,29 +2036,15 @@ func createTP5I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-64",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-5И: %v", err)
-			return
-		}
-		log.Println("✅ ТП-5И created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTP5ICells()
-		createCells(db, cells, "ТП-5И")
-	} else {
-		log.Printf("✅ ТП-5И уже существует")
+		},
+		Cells: createTP5ICells(),
 	}
 }
-func createTPObshyaga(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-obshyaga").Count(&tp4iCount)
 
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-Общежитие...")
-
-		tp4i := models.RUInfo{
+func fixtureTPObshyaga() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-obshyaga",
+		RU: &models.RUInfo{
 			ID:               "tp-obshyaga",
 			Name:             "ТП-Общежитие",
 			Voltage:          "10/0,4 кВ",
@@ -737,7 +2057,7 @@ func createTPObshyaga(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -753,29 +2073,15 @@ func createTPObshyaga(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-Общежитие: %v", err)
-			return
-		}
-		log.Println("✅ ТП-Общежитие created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTPObshyagaCells()
-		createCells(db, cells, "ТП-Общежитие")
-	} else {
-		log.Printf("✅ ТП-Общежитие уже существует")
+		},
+		Cells: createTPObshyagaCells(),
 	}
 }
-func createTPOchistnye(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-ochistnye").Count(&tp4iCount)
 
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-Очистные...")
-
-		tp4i := models.RUInfo{
+func fixtureTPOchistnye() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-ochistnye",
+		RU: &models.RUInfo{
 			ID:               "tp-ochistnye",
 			Name:             "ТП-Очистные",
 			Voltage:          "10/0,4 кВ",
@@ -788,7 +2094,7 @@ func createTPOchistnye(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -804,29 +2110,15 @@ func createTPOchistnye(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-Очистные: %v", err)
-			return
-		}
-		log.Println("✅ ТП-Очистные created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTPOchistnyeCells()
-		createCells(db, cells, "ТП-Очистные")
-	} else {
-		log.Printf("✅ ТП-Очистные уже существует")
+		},
+		Cells: createTPOchistnyeCells(),
 	}
 }
-func createTPVodazabor(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-vodazabor").Count(&tp4iCount)
-
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-Водазабор...")
 
-		tp4i := models.RUInfo{
+func fixtureTPVodazabor() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-vodazabor",
+		RU: &models.RUInfo{
 			ID:               "tp-vodazabor",
 			Name:             "ТП-Водазабор",
 			Voltage:          "10/0,4 кВ",
@@ -839,7 +2131,7 @@ func createTPVodazabor(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -855,29 +2147,15 @@ func createTPVodazabor(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-Водазабор: %v", err)
-			return
-		}
-		log.Println("✅ ТП-Водазабор created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTPVodazaborCells()
-		createCells(db, cells, "ТП-Водазабор")
-	} else {
-		log.Printf("✅ ТП-Водазабор уже существует")
+		},
+		Cells: createTPVodazaborCells(),
 	}
 }
-func createTPRazvyazka(db *gorm.DB) {
-	var tp4iCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "tp-razvyazka").Count(&tp4iCount)
 
-	if tp4iCount == 0 {
-		log.Println("📝 Creating ТП-Развязка...")
-
-		tp4i := models.RUInfo{
+func fixtureTPRazvyazka() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:tp-razvyazka",
+		RU: &models.RUInfo{
 			ID:               "tp-razvyazka",
 			Name:             "ТП-Развязка",
 			Voltage:          "10/0,4 кВ",
@@ -890,7 +2168,7 @@ func createTPRazvyazka(db *gorm.DB) {
 			Manufacturer:     "Энерготехника",
 			LastMaintenance:  "2024-02-15",
 			NextMaintenance:  "2024-08-15",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин с секционированием",
 			TotalLoadHigh:    "430 А",
 			TotalLoadLow:     "635 А",
@@ -906,29 +2184,15 @@ func createTPRazvyazka(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  9,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&tp4i).Error; err != nil {
-			log.Printf("⚠️ Failed to create ТП-Развязка: %v", err)
-			return
-		}
-		log.Println("✅ ТП-Развязка created")
-
-		// Ячейки для ТП-4И (без изменений)
-		cells := createTPRazvyazkaCells()
-		createCells(db, cells, "ТП-Развязка")
-	} else {
-		log.Printf("✅ ТП-Развязка уже существует")
+		},
+		Cells: createTPRazvyazkaCells(),
 	}
 }
-func createKRU_BM_1L(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-1l").Count(&kruCount)
 
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-1Л...")
-
-		kru := models.RUInfo{
+func fixtureKRU_BM_1L() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-1l",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-1l",
 			Name:             "КРУ-БМ-1Л",
 			Voltage:          "10 кВ",
@@ -941,7 +2205,7 @@ func createKRU_BM_1L(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-01-20",
 			NextMaintenance:  "2024-07-20",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "850 А",
 			TotalPowerHigh:   "850 кВА",
@@ -954,29 +2218,15 @@ func createKRU_BM_1L(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-1Л: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-1Л created")
-
-		// Ячейки для КРУ-БМ-1И
-		cells := createKRUBM1LCells()
-		createCells(db, cells, "КРУ-БМ-1Л")
-	} else {
-		log.Printf("✅ КРУ-БМ-1Л уже существует")
+		},
+		Cells: createKRUBM1LCells(),
 	}
 }
-func createKRU_BM_1I(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-1i").Count(&kruCount)
-
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-1И...")
 
-		kru := models.RUInfo{
+func fixtureKRU_BM_1I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-1i",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-1i",
 			Name:             "КРУ-БМ-1И",
 			Voltage:          "10 кВ",
@@ -989,7 +2239,7 @@ func createKRU_BM_1I(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-01-20",
 			NextMaintenance:  "2024-07-20",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "850 А",
 			TotalPowerHigh:   "850 кВА",
@@ -1002,30 +2252,15 @@ func createKRU_BM_1I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-1И: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-1И created")
-
-		// Ячейки для КРУ-БМ-1И
-		cells := createKRUBM1ICells()
-		createCells(db, cells, "КРУ-БМ-1И")
-	} else {
-		log.Printf("✅ КРУ-БМ-1И уже существует")
+		},
+		Cells: createKRUBM1ICells(),
 	}
 }
 
-func createKRU_BM_2I(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-2i").Count(&kruCount)
-
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-2И...")
-
-		kru := models.RUInfo{
+func fixtureKRU_BM_2I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-2i",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-2i",
 			Name:             "КРУ-БМ-2И",
 			Voltage:          "10 кВ",
@@ -1038,7 +2273,7 @@ func createKRU_BM_2I(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-02-10",
 			NextMaintenance:  "2024-08-10",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "780 А",
 			TotalPowerHigh:   "780 кВА",
@@ -1051,30 +2286,15 @@ func createKRU_BM_2I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-164",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-2И: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-2И created")
-
-		// Ячейки для КРУ-БМ-2И
-		cells := createKRUBM2ICells()
-		createCells(db, cells, "КРУ-БМ-2И")
-	} else {
-		log.Printf("✅ КРУ-БМ-2И уже существует")
+		},
+		Cells: createKRUBM2ICells(),
 	}
 }
 
-func createKRU_BM_3I(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-3i").Count(&kruCount)
-
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-3И...")
-
-		kru := models.RUInfo{
+func fixtureKRU_BM_3I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-3i",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-3i",
 			Name:             "КРУ-БМ-3И",
 			Voltage:          "10 кВ",
@@ -1087,7 +2307,7 @@ func createKRU_BM_3I(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-03-05",
 			NextMaintenance:  "2024-09-05",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "720 А",
 			TotalPowerHigh:   "720 кВА",
@@ -1100,30 +2320,15 @@ func createKRU_BM_3I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-64",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-3И: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-3И created")
-
-		// Ячейки для КРУ-БМ-3И (аналогично 2И, с небольшими отличиями)
-		cells := createKRUBM3ICells()
-		createCells(db, cells, "КРУ-БМ-3И")
-	} else {
-		log.Printf("✅ КРУ-БМ-3И уже существует")
+		},
+		Cells: createKRUBM3ICells(),
 	}
 }
 
-func createKRU_BM_4I(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-4i").Count(&kruCount)
-
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-4И...")
-
-		kru := models.RUInfo{
+func fixtureKRU_BM_4I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-4i",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-4i",
 			Name:             "КРУ-БМ-4И",
 			Voltage:          "10 кВ",
@@ -1136,7 +2341,7 @@ func createKRU_BM_4I(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-03-20",
 			NextMaintenance:  "2024-09-20",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "690 А",
 			TotalPowerHigh:   "690 кВА",
@@ -1149,30 +2354,15 @@ func createKRU_BM_4I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-64",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-4И: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-4И created")
-
-		// Ячейки для КРУ-БМ-4И (аналогично 1И, с небольшими отличиями)
-		cells := createKRUBM4ICells()
-		createCells(db, cells, "КРУ-БМ-4И")
-	} else {
-		log.Printf("✅ КРУ-БМ-4И уже существует")
+		},
+		Cells: createKRUBM4ICells(),
 	}
 }
 
-func createKRU_BM_5I(db *gorm.DB) {
-	var kruCount int64
-	db.Model(&models.RUInfo{}).Where("id = ?", "kru-bm-5i").Count(&kruCount)
-
-	if kruCount == 0 {
-		log.Println("📝 Creating КРУ-БМ-5И...")
-
-		kru := models.RUInfo{
+func fixtureKRU_BM_5I() models.SeedFixture {
+	return models.SeedFixture{
+		Name: "ru:kru-bm-5i",
+		RU: &models.RUInfo{
 			ID:               "kru-bm-5i",
 			Name:             "КРУ-БМ-5И",
 			Voltage:          "10 кВ",
@@ -1185,7 +2375,7 @@ func createKRU_BM_5I(db *gorm.DB) {
 			Manufacturer:     "Электроаппарат",
 			LastMaintenance:  "2024-04-05",
 			NextMaintenance:  "2024-10-05",
-			Status:           "Работает в штатном режиме",
+			Status:           models.RuStatusNormal,
 			SchemeType:       "Две секции шин, 16 ячеек",
 			TotalLoadHigh:    "810 А",
 			TotalPowerHigh:   "810 кВА",
@@ -1198,32 +2388,9 @@ func createKRU_BM_5I(db *gorm.DB) {
 			BusSections:      2,
 			CellsPerSection:  8,
 			SubstationID:     "ps-64",
-		}
-
-		if err := db.Create(&kru).Error; err != nil {
-			log.Printf("⚠️ Failed to create КРУ-БМ-5И: %v", err)
-			return
-		}
-		log.Println("✅ КРУ-БМ-5И created")
-
-		// Ячейки для КРУ-БМ-5И (аналогично 2И, с небольшими отличиями)
-		cells := createKRUBM5ICells()
-		createCells(db, cells, "КРУ-БМ-5И")
-	} else {
-		log.Printf("✅ КРУ-БМ-5И уже существует")
-	}
-}
-
-func createCells(db *gorm.DB, cells []models.Cell, ruName string) {
-	createdCount := 0
-	for i := range cells {
-		if err := db.Create(&cells[i]).Error; err != nil {
-			log.Printf("⚠️ Failed to create cell %s in %s: %v", cells[i].Number, ruName, err)
-		} else {
-			createdCount++
-		}
+		},
+		Cells: createKRUBM5ICells(),
 	}
-	log.Printf("✅ Created %d test cells for %s", createdCount, ruName)
 }
 
 // Функции создания ячеек для каждого РУ
@@ -1413,7 +2580,7 @@ func createTP3ICells() []models.Cell {
 		{Number: "Н04-5", Name: "Т-2 Низ. сторона", Type: models.CellTypeTransformer, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Power: &[]string{"100 кВА"}[0], Current: &[]float64{130}[0], Temperature: &[]float64{42}[0], Load: &[]float64{80}[0], Description: "Низковольтная сторона Трансформатора №2", IsGrounded: false, TransformerNumber: &[]string{"Т-2"}[0], BusSection: &[]int{2}[0], RuID: "tp-3i"},
 		{Number: "яч.8", Name: "Ввод-0,4 кВ №2", Type: models.CellTypeBus, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Current: &[]float64{188}[0], Temperature: &[]float64{38}[0], Load: &[]float64{75}[0], Description: "Низковольтная секция шин №2", IsGrounded: false, BusSection: &[]int{2}[0], RuID: "tp-3i"},
 		{Number: "яч.5", Name: " ", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Power: &[]string{"30 кВт"}[0], Current: &[]float64{43}[0], Temperature: &[]float64{36}[0], Load: &[]float64{50}[0], Description: "Выходной фидер №3", IsGrounded: false, BusSection: &[]int{2}[0], RuID: "tp-3i"},
-		{Number: "яч.3", Name: " ", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Power: &[]string{"30 кВт"}[0], Current: &[]float64{43}[0], Temperature: &[]float64{36}[0], Load: &[]float64{50}[0], Description: "Выходной фидер №3", IsGrounded: false, BusSection: &[]int{2}[0], RuID: "tp-3i"},
+		{Number: "яч.6", Name: " ", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Power: &[]string{"30 кВт"}[0], Current: &[]float64{43}[0], Temperature: &[]float64{36}[0], Load: &[]float64{50}[0], Description: "Выходной фидер №3", IsGrounded: false, BusSection: &[]int{2}[0], RuID: "tp-3i"},
 		// {Number: "Н04-8", Name: "Фидер 4", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "0,4 кВ", VoltageLevel: "LOW", Power: &[]string{"25 кВт"}[0], Current: &[]float64{36}[0], Temperature: &[]float64{34}[0], Load: &[]float64{45}[0], Description: "Выходной фидер №4", IsGrounded: false, BusSection: &[]int{2}[0], RuID: "tp-3i"},
 	}
 }
@@ -1606,7 +2773,7 @@ func createKRUBM1LCells() []models.Cell {
 		{Number: "яч.15", Name: "Ввод 10 кВ №1", Type: models.CellTypeInput, Status: models.CellStatusON, Voltage: "10 кВ", VoltageLevel: "HIGH", Current: &[]float64{120}[0], Temperature: &[]float64{38}[0], Load: &[]float64{60}[0], Description: "Входное питание 10 кВ, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
 		// {Number: "№2", Name: "ТСН №1", Type: models.CellTypeTransformer, Status: models.CellStatusON, Voltage: "10 кВ", VoltageLevel: "HIGH", Power: &[]string{"ТСН 63 кВА"}[0], Current: &[]float64{55}[0], Temperature: &[]float64{52}[0], Load: &[]float64{45}[0], Description: "Трансформатор собственных нужд №1", TransformerNumber: &[]string{"ТСН-1"}[0], BusSection: &[]int{1}[0], RuID: "kru-bm-1i"},
 		{Number: "яч.13", Name: "ТСН №1", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "10 кВ", VoltageLevel: "HIGH", Power: &[]string{"400 кВА"}[0], Current: &[]float64{230}[0], Temperature: &[]float64{42}[0], Load: &[]float64{75}[0], Description: "Отходящая линия на ТП-10 кВ, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
-		{Number: "яч.12", Name: "ТН-10 кВ СШ-1", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "10 кВ", VoltageLevel: "HIGH", Power: &[]string{"400 кВА"}[0], Current: &[]float64{230}[0], Temperature: &[]float64{42}[0], Load: &[]float64{75}[0], Description: "Отходящая линия на ТП-10 кВ, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
+		{Number: "яч.11", Name: "ТН-10 кВ СШ-1", Type: models.CellTypeOutput, Status: models.CellStatusON, Voltage: "10 кВ", VoltageLevel: "HIGH", Power: &[]string{"400 кВА"}[0], Current: &[]float64{230}[0], Temperature: &[]float64{42}[0], Load: &[]float64{75}[0], Description: "Отходящая линия на ТП-10 кВ, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
 		{Number: "яч.9", Name: "Резерв", Type: models.CellTypeOutput, Status: models.CellStatusOFF, Voltage: "10 кВ", VoltageLevel: "HIGH", Description: "Резервная ячейка, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
 		{Number: "яч.7", Name: "Резерв", Type: models.CellTypeOutput, Status: models.CellStatusOFF, Voltage: "10 кВ", VoltageLevel: "HIGH", Description: "Резервная ячейка, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
 		{Number: "яч.5", Name: "Резерв", Type: models.CellTypeOutput, Status: models.CellStatusOFF, Voltage: "10 кВ", VoltageLevel: "HIGH", Description: "Резервная ячейка, секция 1", BusSection: &[]int{1}[0], RuID: "kru-bm-1l"},
@@ -0,0 +1,104 @@
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind - категория ошибки, определяющая HTTP-статус ответа
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnavailable
+)
+
+// statusByKind - централизованное соответствие категорий ошибок HTTP-статусам
+var statusByKind = map[Kind]int{
+	KindInternal:     http.StatusInternalServerError,
+	KindValidation:   http.StatusBadRequest,
+	KindUnauthorized: http.StatusUnauthorized,
+	KindForbidden:    http.StatusForbidden,
+	KindNotFound:     http.StatusNotFound,
+	KindConflict:     http.StatusConflict,
+	KindUnavailable:  http.StatusServiceUnavailable,
+}
+
+// Error - типизированная ошибка приложения с машиночитаемым кодом
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details interface{}
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is - ошибки считаются одинаковыми, если совпадает код.
+// Это позволяет сравнивать с sentinel-ошибками через errors.Is даже после WithDetails.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// New - создает ошибку заданной категории
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// WithDetails - возвращает копию ошибки с дополнительными подробностями
+func (e *Error) WithDetails(details interface{}) *Error {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// Wrap - возвращает копию ошибки с сохранением исходной причины
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.Err = err
+	return &clone
+}
+
+// Validation - ошибка валидации входных данных
+func Validation(message string, err error) *Error {
+	e := New(KindValidation, "validation_error", message)
+	if err != nil {
+		e.Details = err.Error()
+		e.Err = err
+	}
+	return e
+}
+
+// Internal - внутренняя ошибка сервера с исходной причиной в подробностях
+func Internal(message string, err error) *Error {
+	e := New(KindInternal, "internal_error", message)
+	if err != nil {
+		e.Details = err.Error()
+		e.Err = err
+	}
+	return e
+}
+
+// HTTPStatus - возвращает HTTP-статус для ошибки
+func HTTPStatus(err error) int {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		if status, ok := statusByKind[appErr.Kind]; ok {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
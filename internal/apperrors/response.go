@@ -0,0 +1,60 @@
+package apperrors
+
+import (
+	"errors"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDKey - ключ контекста gin, под которым хранится идентификатор запроса
+const RequestIDKey = "request_id"
+
+// ErrorResponse - единый формат ответа об ошибке
+type ErrorResponse struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"requestId,omitempty"`
+}
+
+// Build - формирует тело ответа для ошибки.
+// Ошибки, не являющиеся *Error, считаются внутренними.
+func Build(c *gin.Context, err error) ErrorResponse {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		appErr = Internal("errors.internal_error", err)
+	}
+
+	lang, _ := c.Get(i18n.ContextKey)
+
+	return ErrorResponse{
+		Code:      appErr.Code,
+		Message:   localize(i18n.FromValue(lang), appErr),
+		Details:   appErr.Details,
+		RequestID: c.GetString(RequestIDKey),
+	}
+}
+
+// Respond - отправляет ошибку клиенту в едином формате
+func Respond(c *gin.Context, err error) {
+	c.JSON(HTTPStatus(err), Build(c, err))
+}
+
+// Abort - отправляет ошибку и прерывает цепочку обработчиков
+func Abort(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(HTTPStatus(err), Build(c, err))
+}
+
+// localize - переводит сообщение ошибки: сначала как ключ каталога,
+// затем по коду ошибки; если перевода нет, возвращается исходный текст
+func localize(lang i18n.Lang, e *Error) string {
+	if msg, ok := i18n.Lookup(lang, e.Message); ok {
+		return msg
+	}
+	if msg, ok := i18n.Lookup(lang, "errors."+e.Code); ok {
+		return msg
+	}
+	return e.Message
+}
@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"encoding/binary"
+	"sync"
+)
+
+// Avro - двоичное кодирование Avro для простых записей (строки и long) без внешних
+// библиотек. Для транспортов без реестра схем сообщение оформляется в формате
+// single-object encoding: маркер C3 01, отпечаток схемы CRC-64-AVRO, тело записи.
+
+// avroFingerprintEmpty - начальное значение CRC-64-AVRO (Rabin) из спецификации Avro
+const avroFingerprintEmpty uint64 = 0xc15d213aa4d7a795
+
+var (
+	avroFingerprintTable [256]uint64
+	avroFingerprintOnce  sync.Once
+)
+
+// AvroFingerprint - отпечаток CRC-64-AVRO схемы в канонической форме (Parsing Canonical Form)
+func AvroFingerprint(canonicalSchema string) uint64 {
+	avroFingerprintOnce.Do(func() {
+		for i := range avroFingerprintTable {
+			fp := uint64(i)
+			for range 8 {
+				fp = (fp >> 1) ^ (avroFingerprintEmpty & -(fp & 1))
+			}
+			avroFingerprintTable[i] = fp
+		}
+	})
+	fp := avroFingerprintEmpty
+	for i := 0; i < len(canonicalSchema); i++ {
+		fp = (fp >> 8) ^ avroFingerprintTable[byte(fp)^canonicalSchema[i]]
+	}
+	return fp
+}
+
+// AppendAvroLong - long (и int) в зигзаг-кодировке переменной длины
+func AppendAvroLong(buf []byte, v int64) []byte {
+	return binary.AppendUvarint(buf, uint64((v<<1)^(v>>63)))
+}
+
+// AppendAvroString - строка: длина в виде long и байты UTF-8
+func AppendAvroString(buf []byte, s string) []byte {
+	buf = AppendAvroLong(buf, int64(len(s)))
+	return append(buf, s...)
+}
+
+// AvroSingleObject - заголовок single-object encoding для записи со схемой fingerprint
+func AvroSingleObject(fingerprint uint64, body []byte) []byte {
+	buf := make([]byte, 0, 10+len(body))
+	buf = append(buf, 0xc3, 0x01)
+	buf = binary.LittleEndian.AppendUint64(buf, fingerprint)
+	return append(buf, body...)
+}
@@ -0,0 +1,56 @@
+package broker
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAppendAvroLong(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  []byte
+	}{
+		{0, []byte{0x00}},
+		{-1, []byte{0x01}},
+		{1, []byte{0x02}},
+		{-64, []byte{0x7f}},
+		{64, []byte{0x80, 0x01}},
+		{1700000000000, []byte{0x80, 0xa0, 0xab, 0xfe, 0xf9, 0x62}},
+	}
+	for _, tt := range tests {
+		if got := AppendAvroLong(nil, tt.value); !bytes.Equal(got, tt.want) {
+			t.Errorf("AppendAvroLong(%d) = % x, want % x", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAppendAvroString(t *testing.T) {
+	if got, want := AppendAvroString(nil, "foo"), []byte{0x06, 'f', 'o', 'o'}; !bytes.Equal(got, want) {
+		t.Errorf("AppendAvroString = % x, want % x", got, want)
+	}
+}
+
+func TestAvroFingerprint(t *testing.T) {
+	// Значения из набора тестов спецификации Avro (share/test/data/schema-tests.txt)
+	tests := []struct {
+		schema string
+		want   int64
+	}{
+		{`"null"`, 7195948357588979594},
+		{`"boolean"`, -6970731678124411036},
+		{`"int"`, 8247732601305521295},
+	}
+	for _, tt := range tests {
+		if got := int64(AvroFingerprint(tt.schema)); got != tt.want {
+			t.Errorf("AvroFingerprint(%s) = %d, want %d", tt.schema, got, tt.want)
+		}
+	}
+}
+
+func TestAvroSingleObject(t *testing.T) {
+	got := AvroSingleObject(0x0102030405060708, []byte{0xaa})
+	want := []byte{0xc3, 0x01, 0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01, 0xaa}
+	if !bytes.Equal(got, want) {
+		t.Errorf("AvroSingleObject = % x, want % x", got, want)
+	}
+}
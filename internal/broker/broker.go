@@ -0,0 +1,61 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeNone  = ""
+	TypeNATS  = "nats"
+	TypeKafka = "kafka"
+)
+
+// Форматы сообщений
+const (
+	FormatAvro = "avro"
+	FormatJSON = "json"
+)
+
+// Message - сообщение для внешнего брокера
+type Message struct {
+	// Subject - тема NATS или топик Kafka
+	Subject string
+	// Key - ключ партиционирования (для Kafka), события одного РУ попадают в одну партицию
+	Key string
+	// Payload - готовое тело сообщения (JSON или Avro single-object encoding)
+	Payload []byte
+	// Avro - схема и значение для транспортов, которые кодируют Avro сами (Kafka REST
+	// Proxy со Schema Registry); nil - публикуется Payload
+	Avro *AvroRecord
+}
+
+// AvroRecord - значение записи Avro в JSON-представлении вместе с ее схемой
+type AvroRecord struct {
+	Schema string
+	Value  interface{}
+}
+
+// Publisher - публикация сообщений во внешний брокер
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	// Ping - проверка доступности брокера для /readyz
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// New - создает публикатор выбранного типа. Для пустого типа возвращает nil:
+// публикация во внешний брокер отключена.
+func New(brokerType, url string) (Publisher, error) {
+	switch strings.ToLower(brokerType) {
+	case TypeNone:
+		return nil, nil
+	case TypeNATS:
+		return NewNATSPublisher(url)
+	case TypeKafka:
+		return NewKafkaRESTPublisher(url), nil
+	default:
+		return nil, fmt.Errorf("unknown broker type %q", brokerType)
+	}
+}
@@ -0,0 +1,102 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+	kafkaAvroContentType = "application/vnd.kafka.avro.v2+json"
+	// kafkaKeySchema - схема Avro ключа записи
+	kafkaKeySchema = `"string"`
+)
+
+// KafkaRESTPublisher - публикация в Kafka через REST Proxy (Confluent REST API v2).
+// Записи Avro кодирует сам REST Proxy, регистрируя схему в Schema Registry, поэтому
+// потребители читают их обычным десериализатором Confluent.
+type KafkaRESTPublisher struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewKafkaRESTPublisher(baseURL string) *KafkaRESTPublisher {
+	return &KafkaRESTPublisher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type kafkaRecord struct {
+	Key   string      `json:"key,omitempty"`
+	Value interface{} `json:"value"`
+}
+
+func (p *KafkaRESTPublisher) Publish(ctx context.Context, msg Message) error {
+	contentType := kafkaJSONContentType
+	request := map[string]interface{}{
+		"records": []kafkaRecord{{Key: msg.Key, Value: json.RawMessage(msg.Payload)}},
+	}
+	if msg.Avro != nil {
+		contentType = kafkaAvroContentType
+		request = map[string]interface{}{
+			"key_schema":   kafkaKeySchema,
+			"value_schema": msg.Avro.Schema,
+			"records":      []map[string]interface{}{{"key": msg.Key, "value": msg.Avro.Value}},
+		}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode Kafka record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/topics/"+msg.Subject, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish to Kafka: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("kafka REST proxy returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// Ping - запрос списка топиков REST Proxy
+func (p *KafkaRESTPublisher) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/topics", nil)
+	if err != nil {
+		return fmt.Errorf("failed to build Kafka request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Kafka REST proxy: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka REST proxy returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *KafkaRESTPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package database - подключение к базе данных выбранного диалекта.
+// PostgreSQL используется в эксплуатации; SQLite - для локальной разработки
+// и интеграционных тестов без развернутого сервера БД.
+package database
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/config"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// dialector - открывает диалект GORM и описывает подключение для лога
+type dialector func(cfg *config.Config) (gorm.Dialector, string)
+
+var dialectors = map[string]dialector{}
+
+func register(driver string, open dialector) {
+	dialectors[driver] = open
+}
+
+// Open - подключение по DB_DRIVER с настройками пула соединений
+func Open(cfg *config.Config) (*gorm.DB, string, error) {
+	open, ok := dialectors[cfg.DBDriver]
+	if !ok {
+		if cfg.DBDriver == DriverSQLite {
+			return nil, "", fmt.Errorf("sqlite support is not compiled in: build with -tags sqlite")
+		}
+		return nil, "", fmt.Errorf("unknown DB_DRIVER %q", cfg.DBDriver)
+	}
+
+	dialect, description := open(cfg)
+	db, err := connect(cfg, dialect)
+	if err != nil {
+		return nil, description, err
+	}
+	return db, description, nil
+}
+
+// OpenReplica - подключение к реплике только для чтения (DB_REPLICA_DSN) с теми же
+// настройками пула; без DB_REPLICA_DSN возвращает nil
+func OpenReplica(cfg *config.Config) (*gorm.DB, error) {
+	if cfg.DBReplicaDSN == "" {
+		return nil, nil
+	}
+	if cfg.DBDriver != DriverPostgres {
+		return nil, fmt.Errorf("read replica is supported only for %s", DriverPostgres)
+	}
+	return connect(cfg, postgresDialector(cfg, cfg.DBReplicaDSN))
+}
+
+func connect(cfg *config.Config, dialect gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialect, &gorm.Config{
+		// Ошибки уникальности и внешних ключей SQLite приводятся к ошибкам GORM;
+		// ошибки PostgreSQL разбираются по кодам SQLSTATE (repository.IsDuplicate)
+		TranslateError: cfg.DBDriver != DriverPostgres,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	maxOpen := cfg.DBMaxOpenConns
+	if cfg.DBDriver == DriverSQLite {
+		// SQLite допускает одного писателя; несколько соединений к одной базе
+		// (и к общей базе в памяти) приводят к ошибкам блокировки
+		maxOpen = 1
+	}
+	sqlDB.SetMaxOpenConns(maxOpen)
+	sqlDB.SetMaxIdleConns(min(cfg.DBMaxIdleConns, maxOpen))
+	sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+	return db, nil
+}
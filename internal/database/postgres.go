@@ -0,0 +1,41 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(DriverPostgres, func(cfg *config.Config) (gorm.Dialector, string) {
+		return postgresDialector(cfg, postgresDSN(cfg)), fmt.Sprintf("PostgreSQL %s@%s:%s/%s", cfg.DBUser, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	})
+}
+
+func postgresDSN(cfg *config.Config) string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.SSLMode,
+	)
+}
+
+// ListenDSN - подключение для LISTEN: подписка живет в сессии сервера, поэтому за
+// PgBouncer нужен прямой адрес (DB_LISTEN_DSN)
+func ListenDSN(cfg *config.Config) string {
+	if cfg.DBListenDSN != "" {
+		return cfg.DBListenDSN
+	}
+	return postgresDSN(cfg)
+}
+
+// postgresDialector - за PgBouncer в режиме transaction подготовленные запросы одного
+// соединения сервера видны другим клиентам, поэтому pgx переводится на простой протокол
+func postgresDialector(cfg *config.Config, dsn string) gorm.Dialector {
+	return postgres.New(postgres.Config{
+		DSN:                  dsn,
+		PreferSimpleProtocol: cfg.DBPgBouncer,
+	})
+}
@@ -0,0 +1,33 @@
+//go:build sqlite
+
+package database
+
+import (
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// Драйвер SQLite без cgo подключается сборкой с тегом sqlite:
+//
+//	go get github.com/glebarez/sqlite
+//	DB_DRIVER=sqlite SQLITE_PATH=:memory: go run -tags sqlite ./cmd/api
+func init() {
+	register(DriverSQLite, func(cfg *config.Config) (gorm.Dialector, string) {
+		path := cfg.SQLitePath
+		if path == ":memory:" {
+			// Общая база в памяти живет, пока открыто хотя бы одно соединение
+			path = "file::memory:?cache=shared"
+		}
+
+		// Внешние ключи в SQLite включаются для каждого соединения
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		return sqlite.Open(path + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"), "SQLite " + cfg.SQLitePath
+	})
+}
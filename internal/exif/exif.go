@@ -0,0 +1,294 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNotFound - в изображении нет блока EXIF
+var ErrNotFound = errors.New("exif: no EXIF data")
+
+// Info - сведения о съемке из EXIF, которые нужны как подтверждение осмотра
+type Info struct {
+	// TakenAt - время съемки (DateTimeOriginal); без смещения часового пояса
+	// в EXIF время считается местным временем сервера
+	TakenAt   *time.Time
+	Latitude  *float64
+	Longitude *float64
+	Make      string
+	Model     string
+	// Orientation - поворот кадра (1-8); 0 - не указан
+	Orientation int
+}
+
+// Теги TIFF/EXIF
+const (
+	tagMake               = 0x010f
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+)
+
+// Типы значений TIFF
+const (
+	typeByte      = 1
+	typeASCII     = 2
+	typeShort     = 3
+	typeLong      = 4
+	typeRational  = 5
+	typeUndefined = 7
+)
+
+const dateTimeLayout = "2006:01:02 15:04:05"
+
+// Parse - EXIF из JPEG (APP1), PNG (eXIf) или WebP (чанк EXIF)
+func Parse(data []byte) (*Info, error) {
+	tiff, err := extract(data)
+	if err != nil {
+		return nil, err
+	}
+	return parseTIFF(tiff)
+}
+
+// extract - блок TIFF с данными EXIF внутри контейнера изображения
+func extract(data []byte) ([]byte, error) {
+	switch {
+	case len(data) > 2 && data[0] == 0xff && data[1] == 0xd8:
+		return extractJPEG(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return extractPNG(data)
+	case len(data) > 12 && bytes.Equal(data[0:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return extractWebP(data)
+	}
+	return nil, ErrNotFound
+}
+
+func extractJPEG(data []byte) ([]byte, error) {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xff {
+			return nil, ErrNotFound
+		}
+		marker := data[pos+1]
+		// Начало сжатых данных: дальше маркеров с метаданными нет
+		if marker == 0xda || marker == 0xd9 {
+			break
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if size < 2 || pos+2+size > len(data) {
+			return nil, ErrNotFound
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xe1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+		pos += 2 + size
+	}
+	return nil, ErrNotFound
+}
+
+func extractPNG(data []byte) ([]byte, error) {
+	pos := 8
+	for pos+8 <= len(data) {
+		size := int(binary.BigEndian.Uint32(data[pos:]))
+		kind := string(data[pos+4 : pos+8])
+		if size < 0 || pos+12+size > len(data) {
+			return nil, ErrNotFound
+		}
+		if kind == "eXIf" {
+			return data[pos+8 : pos+8+size], nil
+		}
+		if kind == "IDAT" || kind == "IEND" {
+			break
+		}
+		pos += 12 + size
+	}
+	return nil, ErrNotFound
+}
+
+func extractWebP(data []byte) ([]byte, error) {
+	pos := 12
+	for pos+8 <= len(data) {
+		kind := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if size < 0 || pos+8+size > len(data) {
+			return nil, ErrNotFound
+		}
+		if kind == "EXIF" {
+			chunk := data[pos+8 : pos+8+size]
+			// Некоторые программы записывают чанк вместе с заголовком JPEG APP1
+			return bytes.TrimPrefix(chunk, []byte("Exif\x00\x00")), nil
+		}
+		pos += 8 + size + size%2
+	}
+	return nil, ErrNotFound
+}
+
+// entry - запись каталога IFD
+type entry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+type reader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func parseTIFF(tiff []byte) (*Info, error) {
+	if len(tiff) < 8 {
+		return nil, ErrNotFound
+	}
+	r := &reader{data: tiff}
+	switch string(tiff[0:2]) {
+	case "II":
+		r.order = binary.LittleEndian
+	case "MM":
+		r.order = binary.BigEndian
+	default:
+		return nil, ErrNotFound
+	}
+	if r.order.Uint16(tiff[2:]) != 42 {
+		return nil, ErrNotFound
+	}
+
+	ifd0 := r.ifd(r.order.Uint32(tiff[4:]))
+	info := &Info{
+		Make:  r.ascii(ifd0[tagMake]),
+		Model: r.ascii(ifd0[tagModel]),
+	}
+	if orientation, ok := r.uint(ifd0[tagOrientation]); ok && orientation >= 1 && orientation <= 8 {
+		info.Orientation = int(orientation)
+	}
+
+	dateTime, offset := r.ascii(ifd0[tagDateTime]), ""
+	if pointer, ok := r.uint(ifd0[tagExifIFD]); ok {
+		exifIFD := r.ifd(pointer)
+		if original := r.ascii(exifIFD[tagDateTimeOriginal]); original != "" {
+			dateTime = original
+		}
+		offset = r.ascii(exifIFD[tagOffsetTimeOriginal])
+	}
+	info.TakenAt = parseDateTime(dateTime, offset)
+
+	if pointer, ok := r.uint(ifd0[tagGPSIFD]); ok {
+		gps := r.ifd(pointer)
+		info.Latitude = r.coordinate(gps[tagGPSLatitude], r.ascii(gps[tagGPSLatitudeRef]), "S", 90)
+		info.Longitude = r.coordinate(gps[tagGPSLongitude], r.ascii(gps[tagGPSLongitudeRef]), "W", 180)
+	}
+	return info, nil
+}
+
+// ifd - записи каталога по смещению; поврежденный каталог читается до первой ошибки
+func (r *reader) ifd(offset uint32) map[uint16]entry {
+	entries := map[uint16]entry{}
+	if uint64(offset)+2 > uint64(len(r.data)) {
+		return entries
+	}
+	count := int(r.order.Uint16(r.data[offset:]))
+	pos := int(offset) + 2
+	for i := 0; i < count && pos+12 <= len(r.data); i, pos = i+1, pos+12 {
+		tag := r.order.Uint16(r.data[pos:])
+		e := entry{typ: r.order.Uint16(r.data[pos+2:]), count: r.order.Uint32(r.data[pos+4:])}
+		size := uint64(typeSize(e.typ)) * uint64(e.count)
+		if size == 0 {
+			continue
+		}
+		if size <= 4 {
+			e.value = r.data[pos+8 : pos+8+int(size)]
+		} else {
+			valueOffset := uint64(r.order.Uint32(r.data[pos+8:]))
+			if valueOffset+size > uint64(len(r.data)) {
+				continue
+			}
+			e.value = r.data[valueOffset : valueOffset+size]
+		}
+		entries[tag] = e
+	}
+	return entries
+}
+
+func typeSize(typ uint16) int {
+	switch typ {
+	case typeByte, typeASCII, typeUndefined:
+		return 1
+	case typeShort:
+		return 2
+	case typeLong:
+		return 4
+	case typeRational:
+		return 8
+	}
+	return 0
+}
+
+func (r *reader) ascii(e entry) string {
+	if e.typ != typeASCII {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(e.value), "\x00"))
+}
+
+func (r *reader) uint(e entry) (uint32, bool) {
+	switch {
+	case e.typ == typeShort && len(e.value) >= 2:
+		return uint32(r.order.Uint16(e.value)), true
+	case e.typ == typeLong && len(e.value) >= 4:
+		return r.order.Uint32(e.value), true
+	}
+	return 0, false
+}
+
+// coordinate - градусы, минуты и секунды GPS в десятичные градусы
+func (r *reader) coordinate(e entry, ref, negativeRef string, limit float64) *float64 {
+	if e.typ != typeRational || len(e.value) < 24 {
+		return nil
+	}
+	var parts [3]float64
+	for i := range parts {
+		num := r.order.Uint32(e.value[i*8:])
+		den := r.order.Uint32(e.value[i*8+4:])
+		if den == 0 {
+			return nil
+		}
+		parts[i] = float64(num) / float64(den)
+	}
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if strings.EqualFold(ref, negativeRef) {
+		value = -value
+	}
+	if math.IsNaN(value) || math.Abs(value) > limit {
+		return nil
+	}
+	return &value
+}
+
+// parseDateTime - время съемки; нулевая дата ("0000:00:00 00:00:00") считается отсутствующей
+func parseDateTime(value, offset string) *time.Time {
+	if value == "" || strings.HasPrefix(value, "0000") {
+		return nil
+	}
+	if offset != "" {
+		if t, err := time.Parse(dateTimeLayout+"-07:00", value+offset); err == nil {
+			return &t
+		}
+	}
+	t, err := time.ParseInLocation(dateTimeLayout, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,128 @@
+package gql
+
+import (
+	"sync"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/masking"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+)
+
+// substationLoader - пакетная загрузка вложенных данных одной подстанции. Резолверы
+// полей cells и latestOperations вызываются для каждого РУ (и параллельно), а данные
+// загружаются один раз на подстанцию одним запросом на уровень, без 1+N. Загруженное
+// маскируется по правам роли так же, как ответы REST.
+type substationLoader struct {
+	ruService    *service.RuService
+	perms        permissions.Set
+	substationID string
+
+	rusOnce sync.Once
+	rus     []models.RUInfo
+	rusErr  error
+
+	cellsOnce sync.Once
+	cells     map[string][]models.Cell
+	cellsErr  error
+
+	mu         sync.Mutex
+	operations map[int]*operationsBatch
+}
+
+// operationsBatch - последние операции всех РУ подстанции для одного значения limit
+type operationsBatch struct {
+	once    sync.Once
+	records map[string][]models.OperationRecord
+	err     error
+}
+
+func newSubstationLoader(ruService *service.RuService, actor models.Actor, substationID string) *substationLoader {
+	return &substationLoader{
+		ruService:    ruService,
+		perms:        permissions.ForRole(string(actor.Role)),
+		substationID: substationID,
+		operations:   map[int]*operationsBatch{},
+	}
+}
+
+// RUs - РУ подстанции с вычисленным состоянием
+func (l *substationLoader) RUs() ([]models.RUInfo, error) {
+	l.rusOnce.Do(func() {
+		overview, err := l.ruService.GetSubstationOverview(l.substationID, models.OverviewOptions{})
+		if err != nil {
+			l.rusErr = err
+			return
+		}
+		l.rus = make([]models.RUInfo, len(overview))
+		for i := range overview {
+			l.rus[i] = masking.Apply(overview[i].RUInfo, l.perms).(models.RUInfo)
+		}
+	})
+	return l.rus, l.rusErr
+}
+
+// Cells - ячейки РУ; при первом вызове загружаются ячейки всех РУ подстанции
+func (l *substationLoader) Cells(ruID string) ([]models.Cell, error) {
+	l.cellsOnce.Do(func() {
+		ruIDs, err := l.ruIDs()
+		if err != nil {
+			l.cellsErr = err
+			return
+		}
+		cells, err := l.ruService.GetCellsOfRUs(ruIDs)
+		if err != nil {
+			l.cellsErr = err
+			return
+		}
+		cells = masking.Apply(cells, l.perms).([]models.Cell)
+		l.cells = make(map[string][]models.Cell, len(ruIDs))
+		for _, cell := range cells {
+			l.cells[cell.RuID] = append(l.cells[cell.RuID], cell)
+		}
+	})
+	return l.cells[ruID], l.cellsErr
+}
+
+// LatestOperations - последние limit операций РУ; при первом вызове с этим limit
+// загружаются операции всех РУ подстанции
+func (l *substationLoader) LatestOperations(ruID string, limit int) ([]models.OperationRecord, error) {
+	l.mu.Lock()
+	batch, ok := l.operations[limit]
+	if !ok {
+		batch = &operationsBatch{}
+		l.operations[limit] = batch
+	}
+	l.mu.Unlock()
+
+	batch.once.Do(func() {
+		ruIDs, err := l.ruIDs()
+		if err != nil {
+			batch.err = err
+			return
+		}
+		records, err := l.ruService.GetLatestOperationsOfRUs(ruIDs, limit)
+		if err != nil {
+			batch.err = err
+			return
+		}
+		records = masking.Apply(records, l.perms).([]models.OperationRecord)
+		batch.records = make(map[string][]models.OperationRecord, len(ruIDs))
+		for _, record := range records {
+			batch.records[record.RuID] = append(batch.records[record.RuID], record)
+		}
+	})
+	return batch.records[ruID], batch.err
+}
+
+func (l *substationLoader) ruIDs() ([]string, error) {
+	rus, err := l.RUs()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(rus))
+	for i, ru := range rus {
+		ids[i] = ru.ID
+	}
+	return ids, nil
+}
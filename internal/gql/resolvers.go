@@ -0,0 +1,199 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// maxLatestOperations - предел последних операций на РУ, как в обзоре подстанции REST
+const maxLatestOperations = 50
+
+// Resolver - корневой резолвер запросов
+type Resolver struct {
+	ruService *service.RuService
+}
+
+// Substation - подстанция организации пользователя; чужая подстанция не отличается
+// от несуществующей
+func (r *Resolver) Substation(ctx context.Context, args struct{ ID graphql.ID }) (*substationResolver, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	substation, err := r.ruService.GetSubstationFor(actor, string(args.ID))
+	if errors.Is(err, service.ErrSubstationNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &substationResolver{substation: *substation, loader: newSubstationLoader(r.ruService, actor, substation.ID)}, nil
+}
+
+// Substations - подстанции организации пользователя
+func (r *Resolver) Substations(ctx context.Context) ([]*substationResolver, error) {
+	actor, err := actorFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	substations, err := r.ruService.GetVisibleSubstations(actor)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*substationResolver, len(substations))
+	for i, substation := range substations {
+		resolvers[i] = &substationResolver{substation: substation, loader: newSubstationLoader(r.ruService, actor, substation.ID)}
+	}
+	return resolvers, nil
+}
+
+type substationResolver struct {
+	substation models.Substation
+	loader     *substationLoader
+}
+
+func (r *substationResolver) ID() graphql.ID         { return graphql.ID(r.substation.ID) }
+func (r *substationResolver) Name() string           { return r.substation.Name }
+func (r *substationResolver) Location() string       { return r.substation.Location }
+func (r *substationResolver) Description() string    { return r.substation.Description }
+func (r *substationResolver) Voltage() string        { return r.substation.Voltage }
+func (r *substationResolver) InstalledPower() string { return r.substation.InstalledPower }
+func (r *substationResolver) Latitude() *float64     { return r.substation.Latitude }
+func (r *substationResolver) Longitude() *float64    { return r.substation.Longitude }
+
+func (r *substationResolver) Rus() ([]*ruResolver, error) {
+	rus, err := r.loader.RUs()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*ruResolver, len(rus))
+	for i := range rus {
+		resolvers[i] = &ruResolver{ru: rus[i], loader: r.loader}
+	}
+	return resolvers, nil
+}
+
+type ruResolver struct {
+	ru     models.RUInfo
+	loader *substationLoader
+}
+
+func (r *ruResolver) ID() graphql.ID           { return graphql.ID(r.ru.ID) }
+func (r *ruResolver) Name() string             { return r.ru.Name }
+func (r *ruResolver) Type() string             { return string(r.ru.Type) }
+func (r *ruResolver) Voltage() string          { return r.ru.Voltage }
+func (r *ruResolver) Location() string         { return r.ru.Location }
+func (r *ruResolver) Manufacturer() string     { return r.ru.Manufacturer }
+func (r *ruResolver) Status() string           { return string(r.ru.Status) }
+func (r *ruResolver) StatusReason() *string    { return r.ru.StatusReason }
+func (r *ruResolver) CellsCount() int32        { return int32(r.ru.CellsCount) }
+func (r *ruResolver) TransformerPower() string { return r.ru.TransformerPower }
+
+func (r *ruResolver) OperationalState() *string {
+	if r.ru.OperationalState == "" {
+		return nil
+	}
+	state := string(r.ru.OperationalState)
+	return &state
+}
+
+func (r *ruResolver) LastMaintenanceAt() *graphql.Time { return optionalTime(r.ru.LastMaintenanceAt) }
+func (r *ruResolver) NextMaintenanceAt() *graphql.Time { return optionalTime(r.ru.NextMaintenanceAt) }
+
+func (r *ruResolver) Cells() ([]*cellResolver, error) {
+	cells, err := r.loader.Cells(r.ru.ID)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*cellResolver, len(cells))
+	for i := range cells {
+		resolvers[i] = &cellResolver{cell: cells[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *ruResolver) LatestOperations(args struct{ Limit int32 }) ([]*operationResolver, error) {
+	limit := min(max(int(args.Limit), 1), maxLatestOperations)
+	records, err := r.loader.LatestOperations(r.ru.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*operationResolver, len(records))
+	for i := range records {
+		resolvers[i] = &operationResolver{record: records[i]}
+	}
+	return resolvers, nil
+}
+
+type cellResolver struct {
+	cell models.Cell
+}
+
+func (r *cellResolver) ID() int32                      { return int32(r.cell.ID) }
+func (r *cellResolver) Number() string                 { return r.cell.Number }
+func (r *cellResolver) Name() string                   { return r.cell.Name }
+func (r *cellResolver) Type() string                   { return string(r.cell.Type) }
+func (r *cellResolver) Status() string                 { return string(r.cell.Status) }
+func (r *cellResolver) Voltage() string                { return r.cell.Voltage }
+func (r *cellResolver) VoltageLevel() string           { return string(r.cell.VoltageLevel) }
+func (r *cellResolver) Description() string            { return r.cell.Description }
+func (r *cellResolver) Power() *string                 { return r.cell.Power }
+func (r *cellResolver) IsGrounded() bool               { return r.cell.IsGrounded }
+func (r *cellResolver) IsCritical() bool               { return r.cell.IsCritical }
+func (r *cellResolver) Current() *float64              { return r.cell.Current }
+func (r *cellResolver) Temperature() *float64          { return r.cell.Temperature }
+func (r *cellResolver) Load() *float64                 { return r.cell.Load }
+func (r *cellResolver) LastOperationAt() *graphql.Time { return optionalTime(r.cell.LastOperationAt) }
+
+func (r *cellResolver) BusSection() *int32 {
+	if r.cell.BusSection == nil {
+		return nil
+	}
+	section := int32(*r.cell.BusSection)
+	return &section
+}
+
+type operationResolver struct {
+	record models.OperationRecord
+}
+
+func (r *operationResolver) ID() graphql.ID             { return graphql.ID(r.record.ID) }
+func (r *operationResolver) CellNumber() string         { return r.record.CellNumber }
+func (r *operationResolver) CellName() string           { return r.record.CellName }
+func (r *operationResolver) Action() string             { return r.record.Action }
+func (r *operationResolver) Operator() string           { return r.record.Operator }
+func (r *operationResolver) DocumentType() *string      { return r.record.DocumentType }
+func (r *operationResolver) OrderNumber() *string       { return r.record.OrderNumber }
+func (r *operationResolver) WorkOrderNumber() *string   { return r.record.WorkOrderNumber }
+func (r *operationResolver) ResponsiblePerson() *string { return r.record.ResponsiblePerson }
+func (r *operationResolver) Reason() *string            { return r.record.Reason }
+func (r *operationResolver) Comment() *string           { return r.record.Comment }
+
+// Timestamp - время операции; у записей без него - время внесения
+func (r *operationResolver) Timestamp() graphql.Time {
+	if r.record.TimestampAt != nil {
+		return graphql.Time{Time: *r.record.TimestampAt}
+	}
+	return graphql.Time{Time: r.record.CreatedAt}
+}
+
+func (r *operationResolver) Severity() *string {
+	if r.record.Severity == nil {
+		return nil
+	}
+	severity := string(*r.record.Severity)
+	return &severity
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
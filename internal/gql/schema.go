@@ -0,0 +1,51 @@
+// Package gql - GraphQL API для чтения вложенных данных подстанции (подстанция → РУ →
+// ячейки и последние операции) одним запросом с выбором полей.
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+var errNoActor = errors.New("request is not authenticated")
+
+// maxDepth - предельная вложенность запроса: схема неглубокая, глубже - ошибка клиента
+const maxDepth = 8
+
+// Request - тело запроса GraphQL
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// NewSchema - схема с резолверами поверх сервиса РУ
+func NewSchema(ruService *service.RuService) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schemaSDL, &Resolver{ruService: ruService}, graphql.MaxDepth(maxDepth))
+}
+
+type actorKey struct{}
+
+// WithActor - контекст запроса с пользователем, от имени которого читаются данные
+func WithActor(ctx context.Context, actor models.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom - пользователь запроса; без него данные не читаются, чтобы пустая
+// организация не означала доступ ко всем организациям
+func actorFrom(ctx context.Context) (models.Actor, error) {
+	actor, ok := ctx.Value(actorKey{}).(models.Actor)
+	if !ok || actor.UserID == "" {
+		return models.Actor{}, errNoActor
+	}
+	return actor, nil
+}
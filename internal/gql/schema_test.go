@@ -0,0 +1,36 @@
+package gql
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewSchemaMatchesResolvers(t *testing.T) {
+	// ParseSchema сверяет каждое поле схемы с методом резолвера
+	if _, err := NewSchema(nil); err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+}
+
+func TestQueryWithoutActorFails(t *testing.T) {
+	schema, err := NewSchema(nil)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	resp := schema.Exec(context.Background(), `{ substations { id } }`, "", nil)
+	if len(resp.Errors) == 0 {
+		t.Fatal("expected error for request without actor")
+	}
+}
+
+// Маршрут GraphQL доступен в режиме "только чтение"; изменения через него недопустимы
+func TestSchemaIsReadOnly(t *testing.T) {
+	schema, err := NewSchema(nil)
+	if err != nil {
+		t.Fatalf("NewSchema: %v", err)
+	}
+	inspected := schema.Inspect()
+	if inspected.MutationType() != nil || inspected.SubscriptionType() != nil {
+		t.Fatal("schema must declare queries only: POST /graphql is exempt from read-only mode")
+	}
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/service"
 
@@ -18,9 +20,9 @@ func NewAdminHandler(adminService *service.AdminService) *AdminHandler {
 }
 
 func (h *AdminHandler) GetUsers(c *gin.Context) {
-	users, err := h.adminService.GetAllUsers()
+	users, err := h.adminService.GetAllUsers(currentActor(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, "users.get_failed", err)
 		return
 	}
 
@@ -30,55 +32,78 @@ func (h *AdminHandler) GetUsers(c *gin.Context) {
 func (h *AdminHandler) CreateUser(c *gin.Context) {
 	var req models.AdminCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Invalid request data",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	user, err := h.adminService.CreateUser(&req)
+	user, err := h.adminService.CreateUser(currentActor(c), &req)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user with this email already exists" {
-			status = http.StatusConflict
-		}
-		c.JSON(status, gin.H{
-			"error":   "create_user_error",
-			"message": err.Error(),
-		})
+		respondError(c, "users.create_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, user)
 }
 
+// GetPendingUsers - регистрации, ожидающие подтверждения адреса или одобрения
+func (h *AdminHandler) GetPendingUsers(c *gin.Context) {
+	users, err := h.adminService.GetPendingUsers(currentActor(c))
+	if err != nil {
+		respondError(c, "users.pending_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, users)
+}
+
+// ApproveUser - одобрение регистрации с назначением роли
+func (h *AdminHandler) ApproveUser(c *gin.Context) {
+	var req models.ApproveUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+	}
+
+	user, err := h.adminService.ApproveUser(currentActor(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, "users.approve_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, user)
+}
+
+// RejectUser - отклонение регистрации с причиной
+func (h *AdminHandler) RejectUser(c *gin.Context) {
+	var req models.RejectUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	user, err := h.adminService.RejectUser(currentActor(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, "users.reject_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, user)
+}
+
 func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	userID := c.Param("id")
 
 	var req models.AdminUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Invalid request data",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	user, err := h.adminService.UpdateUser(userID, &req)
+	user, err := h.adminService.UpdateUser(currentActor(c), userID, &req)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		} else if err.Error() == "email already taken by another user" {
-			status = http.StatusConflict
-		}
-		c.JSON(status, gin.H{
-			"error":   "update_user_error",
-			"message": err.Error(),
-		})
+		respondError(c, "users.update_failed", err)
 		return
 	}
 
@@ -88,21 +113,14 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 func (h *AdminHandler) DeleteUser(c *gin.Context) {
 	userID := c.Param("id")
 
-	err := h.adminService.DeleteUser(userID)
+	err := h.adminService.DeleteUser(currentActor(c), userID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{
-			"error":   "delete_user_error",
-			"message": err.Error(),
-		})
+		respondError(c, "users.delete_failed", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "User deleted successfully",
+		"message": i18n.T(locale(c), "users.deleted"),
 		"user_id": userID,
 	})
 }
@@ -111,38 +129,53 @@ func (h *AdminHandler) ChangePassword(c *gin.Context) {
 
 	var req models.AdminChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Invalid request data",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	err := h.adminService.ChangeUserPassword(currentActor(c), userID, &req)
+	if err != nil {
+		respondError(c, "users.password_change_failed", err)
 		return
 	}
 
-	// Проверка длины пароля (дополнительная валидация)
-	if len(req.NewPassword) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Пароль должен содержать минимум 6 символов",
-		})
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "users.password_changed"),
+		"user_id": userID,
+	})
+}
+
+// Impersonate - POST /admin/users/:id/impersonate, короткоживущий токен от имени пользователя
+func (h *AdminHandler) Impersonate(c *gin.Context) {
+	var req models.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	err := h.adminService.ChangeUserPassword(userID, &req)
+	resp, err := h.adminService.Impersonate(currentActor(c), c.Param("id"), &req, requestAuditEntry(c), locale(c))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
+		respondError(c, "users.impersonate_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUserActivity - GET /admin/users/:id/activity?limit=, входы и изменения пользователя
+func (h *AdminHandler) GetUserActivity(c *gin.Context) {
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
 		}
-		c.JSON(status, gin.H{
-			"error":   "password_change_error",
-			"message": err.Error(),
-		})
+	}
+
+	activity, err := h.adminService.GetUserActivity(currentActor(c), c.Param("id"), limit)
+	if err != nil {
+		respondError(c, "users.activity_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Password changed successfully",
-		"user_id": userID,
-	})
+	respondJSON(c, http.StatusOK, activity)
 }
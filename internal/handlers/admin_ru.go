@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/service"
 
@@ -20,40 +21,186 @@ func NewAdminRuHandler(ruService *service.RuService) *AdminRuHandler {
 func (h *AdminRuHandler) CreateRU(c *gin.Context) {
 	var ruInfo models.RUInfo
 	if err := c.ShouldBindJSON(&ruInfo); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные РУ",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "ru.invalid_data", err)
 		return
 	}
 
 	// Здесь должна быть логика создания РУ в базе данных
 	// Для упрощения возвращаем успех
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "РУ создано успешно",
+	respondJSON(c, http.StatusCreated, gin.H{
+		"message": i18n.T(locale(c), "ru.created"),
 		"ru":      ruInfo,
 	})
 }
 
+// CreateCells - POST /admin/rus/:id/cells, пакет ячеек; создается весь пакет или ничего
 func (h *AdminRuHandler) CreateCells(c *gin.Context) {
 	ruID := c.Param("id")
 
-	var cells []models.Cell
-	if err := c.ShouldBindJSON(&cells); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные ячеек",
-			"details": err.Error(),
-		})
+	var inputs []models.CreateCellInput
+	if err := c.ShouldBindJSON(&inputs); err != nil {
+		respondValidationError(c, "cells.invalid_data", err)
 		return
 	}
 
-	// Здесь должна быть логика создания ячеек в базе данных
-	// Для упрощения возвращаем успех
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Ячейки созданы успешно",
+	cells, err := h.ruService.CreateCells(ruID, inputs)
+	if err != nil {
+		respondError(c, "cells.create_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, gin.H{
+		"message": i18n.T(locale(c), "cells.created"),
 		"count":   len(cells),
 		"ruId":    ruID,
+		"cells":   cells,
 	})
 }
+
+// NextCellNumber - GET /admin/rus/:id/cells/next-number, следующий свободный номер «яч.N»
+func (h *AdminRuHandler) NextCellNumber(c *gin.Context) {
+	var query models.NextCellNumberQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	next, err := h.ruService.NextCellNumber(c.Param("id"), query)
+	if err != nil {
+		respondError(c, "cells.next_number_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, next)
+}
+
+// UpdateRU - PUT /admin/rus/:id, полная замена паспортных данных РУ
+func (h *AdminRuHandler) UpdateRU(c *gin.Context) {
+	var req models.UpdateRuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "ru.invalid_data", err)
+		return
+	}
+
+	ruInfo, err := h.ruService.UpdateRu(c.Param("id"), &req)
+	if err != nil {
+		respondError(c, "ru.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "ru.updated"),
+		"ru":      ruInfo,
+	})
+}
+
+// DeleteRU - DELETE /admin/rus/:id?archive=true. РУ без журнала удаляется с ячейками,
+// с журналом - архивируется
+func (h *AdminRuHandler) DeleteRU(c *gin.Context) {
+	var query models.DeleteRuQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	result, err := h.ruService.DeleteRu(c.Param("id"), query.Archive, currentActor(c))
+	if err != nil {
+		respondError(c, "ru.delete_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "ru."+string(result.Outcome)),
+		"result":  result,
+	})
+}
+
+// RestoreRU - POST /admin/rus/:id/restore, возврат архивированного РУ
+func (h *AdminRuHandler) RestoreRU(c *gin.Context) {
+	ruInfo, err := h.ruService.RestoreRu(c.Param("id"))
+	if err != nil {
+		respondError(c, "ru.restore_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "ru.restored"),
+		"ru":      ruInfo,
+	})
+}
+
+// GetArchivedRUs - GET /admin/rus/archived
+func (h *AdminRuHandler) GetArchivedRUs(c *gin.Context) {
+	rus, err := h.ruService.GetArchivedRUs()
+	if err != nil {
+		respondError(c, "ru.list_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, rus)
+}
+
+// ExportRU - GET /admin/rus/:id/export, полный снимок РУ с ячейками
+func (h *AdminRuHandler) ExportRU(c *gin.Context) {
+	snapshot, err := h.ruService.ExportRu(c.Param("id"))
+	if err != nil {
+		respondError(c, "ru.export_failed", err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=\""+snapshot.RU.ID+".json\"")
+	respondJSON(c, http.StatusOK, snapshot)
+}
+
+// ImportRU - POST /admin/rus/import, восстановление или перенос РУ из снимка
+func (h *AdminRuHandler) ImportRU(c *gin.Context) {
+	var req models.ImportRuRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	result, err := h.ruService.ImportRu(&req)
+	if err != nil {
+		respondError(c, "ru.import_failed", err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Outcome == models.ImportCreated {
+		status = http.StatusCreated
+	}
+	respondJSON(c, status, result)
+}
+
+// SetRuLocation - PUT /admin/rus/:id/location, координаты РУ для карты
+func (h *AdminRuHandler) SetRuLocation(c *gin.Context) {
+	var req models.SetLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	if err := h.ruService.SetRuLocation(c.Param("id"), &req); err != nil {
+		respondError(c, "map.location_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": i18n.T(locale(c), "map.location_updated")})
+}
+
+// SetSubstationLocation - PUT /admin/substations/:id/location, координаты подстанции для карты
+func (h *AdminRuHandler) SetSubstationLocation(c *gin.Context) {
+	var req models.SetLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	if err := h.ruService.SetSubstationLocation(c.Param("id"), &req); err != nil {
+		respondError(c, "map.location_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"message": i18n.T(locale(c), "map.location_updated")})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// alarmStatsCSVHeader - колонки выгрузки статистики аварий: строка на ячейку или РУ
+var alarmStatsCSVHeader = []string{"ru_id", "ru_name", "cell_number", "count", "critical", "warning", "info",
+	"acknowledged", "mean_time_to_ack_s", "chattering_episodes", "chattering_kinds"}
+
+type AlarmHandler struct {
+	alarmService *service.AlarmService
+}
+
+func NewAlarmHandler(alarmService *service.AlarmService) *AlarmHandler {
+	return &AlarmHandler{alarmService: alarmService}
+}
+
+// GetAlarms - GET /alarms?ruId=&severity=&status=&before=&after=&visibility=&limit=
+func (h *AlarmHandler) GetAlarms(c *gin.Context) {
+	var filter models.AlarmFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	alarms, err := h.alarmService.GetAlarms(currentActor(c), filter, limit)
+	if err != nil {
+		respondError(c, "alarms.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alarms)
+}
+
+// AcknowledgeAlarms - POST /alarms/ack: массовое квитирование с обязательным комментарием
+func (h *AlarmHandler) AcknowledgeAlarms(c *gin.Context) {
+	var req models.AckAlarmsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	count, err := h.alarmService.Acknowledge(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "alarms.ack_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      i18n.T(locale(c), "alarms.acknowledged", count),
+		"acknowledged": count,
+	})
+}
+
+// GetAlarmStats - GET /alarms/stats?from=&to=&ruId=&groupBy=cell|ru&limit=&format=json|csv,
+// аварии по ячейкам или РУ за период для разбора надежности
+func (h *AlarmHandler) GetAlarmStats(c *gin.Context) {
+	var query models.AlarmStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.alarmService.GetAlarmStats(currentActor(c), query)
+	if err != nil {
+		respondError(c, "alarms.stats_failed", err)
+		return
+	}
+
+	if query.Format != "csv" {
+		respondJSON(c, http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"alarm-stats-%s-%s.csv\"", query.From, query.To))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(alarmStatsCSVHeader)
+	for _, s := range report.Items {
+		mean := ""
+		if s.MeanTimeToAckSeconds != nil {
+			mean = strconv.FormatFloat(*s.MeanTimeToAckSeconds, 'f', 1, 64)
+		}
+		kinds := make([]string, len(s.ChatteringKinds))
+		for i, kind := range s.ChatteringKinds {
+			kinds[i] = string(kind)
+		}
+		_ = w.Write([]string{
+			s.RuID, s.RuName, s.CellNumber, strconv.Itoa(s.Count), strconv.Itoa(s.Critical),
+			strconv.Itoa(s.Warning), strconv.Itoa(s.Info), strconv.Itoa(s.Acknowledged), mean,
+			strconv.Itoa(s.ChatteringEpisodes), strings.Join(kinds, " "),
+		})
+	}
+	w.Flush()
+}
+
+// ShelveAlarm - POST /alarms/:alarmId/shelve: отложить мешающую аварию на время с причиной
+func (h *AlarmHandler) ShelveAlarm(c *gin.Context) {
+	var req models.ShelveAlarmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	alarm, err := h.alarmService.Shelve(currentActor(c), c.Param("alarmId"), &req, requestAuditEntry(c))
+	if err != nil {
+		respondError(c, "alarms.shelve_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alarm)
+}
+
+// UnshelveAlarm - DELETE /alarms/:alarmId/shelve: досрочно вернуть аварию в рабочий список
+func (h *AlarmHandler) UnshelveAlarm(c *gin.Context) {
+	alarm, err := h.alarmService.Unshelve(currentActor(c), c.Param("alarmId"), requestAuditEntry(c))
+	if err != nil {
+		respondError(c, "alarms.unshelve_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alarm)
+}
+
+func (h *AlarmHandler) GetSavedFilters(c *gin.Context) {
+	filters, err := h.alarmService.GetSavedFilters(c.GetString("user_id"))
+	if err != nil {
+		respondError(c, "alarms.filters_get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, filters)
+}
+
+func (h *AlarmHandler) CreateSavedFilter(c *gin.Context) {
+	var req models.CreateSavedAlarmFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	filter, err := h.alarmService.CreateSavedFilter(c.GetString("user_id"), &req)
+	if err != nil {
+		respondError(c, "alarms.filter_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, filter)
+}
+
+func (h *AlarmHandler) DeleteSavedFilter(c *gin.Context) {
+	filterID := c.Param("filterId")
+
+	if err := h.alarmService.DeleteSavedFilter(c.GetString("user_id"), filterID); err != nil {
+		respondError(c, "alarms.filter_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   i18n.T(locale(c), "alarms.filter_deleted"),
+		"filter_id": filterID,
+	})
+}
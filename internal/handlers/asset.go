@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AssetHandler struct {
+	assetService *service.AssetService
+}
+
+func NewAssetHandler(assetService *service.AssetService) *AssetHandler {
+	return &AssetHandler{assetService: assetService}
+}
+
+// GetAssets - GET /assets?type=&state=&ruId=&cellId=&serial=
+func (h *AssetHandler) GetAssets(c *gin.Context) {
+	var filter models.AssetFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	assets, err := h.assetService.GetAssets(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "assets.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assets)
+}
+
+// GetCellAssets - GET /rus/:id/cells/:cellId/assets, оборудование, установленное в ячейке
+func (h *AssetHandler) GetCellAssets(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	assets, err := h.assetService.GetAssets(currentActor(c), models.AssetFilter{
+		RuID:   c.Param("id"),
+		CellID: &cellID,
+		State:  models.AssetInstalled,
+	})
+	if err != nil {
+		respondError(c, "assets.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assets)
+}
+
+func (h *AssetHandler) GetAsset(c *gin.Context) {
+	asset, err := h.assetService.GetAsset(currentActor(c), c.Param("assetId"))
+	if err != nil {
+		respondError(c, "assets.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+// GetAssetHistory - GET /assets/:assetId/history, установки, снятия и ремонты
+func (h *AssetHandler) GetAssetHistory(c *gin.Context) {
+	events, err := h.assetService.GetHistory(currentActor(c), c.Param("assetId"))
+	if err != nil {
+		respondError(c, "assets.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *AssetHandler) CreateAsset(c *gin.Context) {
+	var req models.CreateAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	asset, err := h.assetService.CreateAsset(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "assets.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, asset)
+}
+
+func (h *AssetHandler) UpdateAsset(c *gin.Context) {
+	var req models.UpdateAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	asset, err := h.assetService.UpdateAsset(c.Param("assetId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "assets.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+// MoveAsset - POST /assets/:assetId/move, установка в ячейку
+func (h *AssetHandler) MoveAsset(c *gin.Context) {
+	var req models.MoveAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	asset, err := h.assetService.MoveAsset(c.Param("assetId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "assets.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+// ChangeAssetState - POST /assets/:assetId/state, ремонт, склад или списание
+func (h *AssetHandler) ChangeAssetState(c *gin.Context) {
+	var req models.ChangeAssetStateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	asset, err := h.assetService.ChangeState(c.Param("assetId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "assets.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// GetEntries - GET /admin/audit?userId=&action=&before=&limit=
+func (h *AuditHandler) GetEntries(c *gin.Context) {
+	var filter models.AuditFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	entries, err := h.auditService.GetEntries(filter)
+	if err != nil {
+		respondError(c, "audit.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, entries)
+}
@@ -3,6 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/middleware"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/service"
 
@@ -20,65 +23,66 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Invalid request data",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	resp, err := h.authService.Register(&req)
+	resp, err := h.authService.Register(&req, locale(c))
 	if err != nil {
-		status := http.StatusInternalServerError
-		errorType := "internal_server_error"
-		message := "Failed to register user"
-
-		if err.Error() == "user with this email already exists" {
-			status = http.StatusConflict
-			errorType = "conflict"
-			message = "User with this email already exists"
-		}
-
-		c.JSON(status, gin.H{
-			"error":   errorType,
-			"message": message,
-			"details": err.Error(),
-		})
+		respondError(c, "auth.register_failed", err)
 		return
 	}
+	resp.Message = i18n.T(locale(c), resp.Message)
 
 	c.JSON(http.StatusCreated, resp)
 }
 
+// VerifyEmail - подтверждение адреса по ключу из письма
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(&req)
+	if err != nil {
+		respondError(c, "auth.verify_email_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.RegisterResponse{
+		User:    *user,
+		Message: i18n.T(locale(c), "auth.registered_pending_approval"),
+	})
+}
+
+// ResendVerification - повторное письмо с подтверждением; ответ всегда одинаковый
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req models.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	if err := h.authService.ResendVerification(&req, locale(c)); err != nil {
+		respondError(c, "auth.resend_verification_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": i18n.T(locale(c), "auth.verification_resent")})
+}
+
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Invalid request data",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	resp, err := h.authService.Login(&req)
+	resp, err := h.authService.Login(&req, models.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()})
 	if err != nil {
-		status := http.StatusInternalServerError
-		errorType := "internal_server_error"
-		message := "Failed to login"
-
-		if err.Error() == "invalid email or password" {
-			status = http.StatusUnauthorized
-			errorType = "unauthorized"
-			message = "Invalid email or password"
-		}
-
-		c.JSON(status, gin.H{
-			"error":   errorType,
-			"message": message,
-			"details": err.Error(),
-		})
+		respondError(c, "auth.login_failed", err)
 		return
 	}
 
@@ -88,34 +92,27 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) GetMe(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error":   "unauthorized",
-			"message": "User not authenticated",
-		})
+		apperrors.Respond(c, apperrors.New(apperrors.KindUnauthorized, "unauthorized", "User not authenticated"))
 		return
 	}
 
 	resp, err := h.authService.GetCurrentUser(userID.(string))
 	if err != nil {
-		status := http.StatusInternalServerError
-		errorType := "internal_server_error"
-		message := "Failed to get user data"
-
-		if err.Error() == "user not found" {
-			status = http.StatusNotFound
-			errorType = "not_found"
-			message = "User not found"
-		}
-
-		c.JSON(status, gin.H{
-			"error":   errorType,
-			"message": message,
-			"details": err.Error(),
-		})
+		respondError(c, "auth.me_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	body := gin.H{
 		"user": resp, // Возвращаем как {"user": {...}}
-	})
+	}
+	// Сессия администратора от имени пользователя: интерфейс показывает баннер
+	if impersonatorID := c.GetString(middleware.ImpersonatorIDKey); impersonatorID != "" {
+		body["impersonation"] = models.Impersonation{
+			ImpersonatorID:    impersonatorID,
+			ImpersonatorEmail: c.GetString(middleware.ImpersonatorEmailKey),
+			Banner:            c.GetString(middleware.ImpersonationBannerKey),
+		}
+	}
+
+	c.JSON(http.StatusOK, body)
 }
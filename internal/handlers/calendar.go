@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	calendarService *service.CalendarService
+}
+
+func NewCalendarHandler(calendarService *service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+func (h *CalendarHandler) GetYear(c *gin.Context) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		if y, err := strconv.Atoi(yearStr); err == nil && y >= 2000 && y <= 2100 {
+			year = y
+		}
+	}
+
+	days, err := h.calendarService.GetYear(year)
+	if err != nil {
+		respondError(c, "calendar.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year": year,
+		"days": days,
+	})
+}
+
+func (h *CalendarHandler) UpsertDay(c *gin.Context) {
+	date := c.Param("date")
+
+	var req models.UpsertCalendarDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	day, err := h.calendarService.UpsertDay(date, &req)
+	if err != nil {
+		respondError(c, "calendar.save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, day)
+}
+
+func (h *CalendarHandler) DeleteDay(c *gin.Context) {
+	date := c.Param("date")
+
+	if err := h.calendarService.DeleteDay(date); err != nil {
+		respondError(c, "calendar.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "calendar.day_deleted"),
+		"date":    date,
+	})
+}
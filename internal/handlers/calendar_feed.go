@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/ical"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarFeedHandler struct {
+	feedService *service.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(feedService *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{feedService: feedService}
+}
+
+// IssueToken - POST /me/calendar-feed: новый ключ подписки (прежний отзывается)
+func (h *CalendarFeedHandler) IssueToken(c *gin.Context) {
+	token, err := h.feedService.IssueToken(currentActor(c))
+	if err != nil {
+		respondError(c, "calendar_feed.issue_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *CalendarFeedHandler) RevokeToken(c *gin.Context) {
+	if err := h.feedService.RevokeToken(currentActor(c)); err != nil {
+		respondError(c, "calendar_feed.revoke_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale(c), "calendar_feed.revoked")})
+}
+
+// GetFeed - GET /calendar.ics?token=: лента для подписки из Outlook/Google Calendar
+func (h *CalendarFeedHandler) GetFeed(c *gin.Context) {
+	cal, err := h.feedService.Feed(c.Query("token"))
+	if err != nil {
+		respondError(c, "calendar_feed.get_failed", err)
+		return
+	}
+
+	respondICal(c, "calendar.ics", cal, "calendar_feed.get_failed")
+}
+
+// respondICal - календарь в формате iCalendar (text/calendar)
+func respondICal(c *gin.Context, fileName string, cal ical.Calendar, errorKey string) {
+	var buf bytes.Buffer
+	if err := ical.Write(&buf, cal); err != nil {
+		respondError(c, errorKey, err)
+		return
+	}
+	c.Header("Content-Disposition", "inline; filename=\""+fileName+"\"")
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
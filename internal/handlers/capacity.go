@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CapacityHandler struct {
+	capacityService *service.CapacityService
+}
+
+func NewCapacityHandler(capacityService *service.CapacityService) *CapacityHandler {
+	return &CapacityHandler{capacityService: capacityService}
+}
+
+// GetUtilization - GET /capacity/utilization, рейтинг РУ по загрузке секций шин
+func (h *CapacityHandler) GetUtilization(c *gin.Context) {
+	var query models.UtilizationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	ranking, err := h.capacityService.Ranking(currentActor(c), query)
+	if err != nil {
+		respondError(c, "capacity.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{"rus": ranking})
+}
+
+// SetCapacity - PUT /admin/rus/:id/capacity, допустимый ток шин сторон РУ
+func (h *CapacityHandler) SetCapacity(c *gin.Context) {
+	var req models.SetCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	ru, err := h.capacityService.SetCapacity(c.Param("id"), &req)
+	if err != nil {
+		respondError(c, "capacity.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "capacity.updated"),
+		"ru":      ru,
+	})
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCellChanges - GET /cell-changes?state=pending&ruId=
+func (h *RuHandler) GetCellChanges(c *gin.Context) {
+	changes, err := h.ruService.GetCellChanges(currentActor(c), c.DefaultQuery("state", string(models.ChangePending)), c.Query("ruId"))
+	if err != nil {
+		respondError(c, "cell_change.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+func (h *RuHandler) ApproveCellChange(c *gin.Context) {
+	var req models.ReviewCellChangeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+	}
+
+	change, err := h.ruService.ApproveCellChange(c.Param("changeId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "cell_change.review_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+func (h *RuHandler) RejectCellChange(c *gin.Context) {
+	var req models.ReviewCellChangeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+	}
+
+	change, err := h.ruService.RejectCellChange(c.Param("changeId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "cell_change.review_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
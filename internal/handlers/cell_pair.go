@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPairedCells - GET /rus/:id/cells/pairs, ячейки ВН и НН попарно для схемы ТП
+func (h *RuHandler) GetPairedCells(c *gin.Context) {
+	pairs, err := h.ruService.GetPairedCells(c.Param("id"))
+	if err != nil {
+		respondError(c, "cells.pairs_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, pairs)
+}
+
+// PairCell - PUT /admin/rus/:id/cells/:cellId/pair, связать ячейку с ячейкой другой стороны
+func (h *AdminRuHandler) PairCell(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.SetCellPairRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	cell, err := h.ruService.PairCells(c.Param("id"), cellID, &req)
+	if err != nil {
+		respondError(c, "cells.pair_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
+
+// UnpairCell - DELETE /admin/rus/:id/cells/:cellId/pair, разорвать пару ячейки
+func (h *AdminRuHandler) UnpairCell(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	cell, err := h.ruService.UnpairCell(c.Param("id"), cellID)
+	if err != nil {
+		respondError(c, "cells.pair_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
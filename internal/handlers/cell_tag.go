@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CellTagHandler struct {
+	cellTagService *service.CellTagService
+}
+
+func NewCellTagHandler(cellTagService *service.CellTagService) *CellTagHandler {
+	return &CellTagHandler{cellTagService: cellTagService}
+}
+
+// GetCellQR - GET /rus/:id/cells/:cellId/qr?format=png|svg&size=256, QR-код для наклейки на ячейку
+func (h *CellTagHandler) GetCellQR(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	size := 0
+	if sizeStr := c.Query("size"); sizeStr != "" {
+		if size, err = strconv.Atoi(sizeStr); err != nil {
+			apperrors.Respond(c, service.ErrQRSizeInvalid)
+			return
+		}
+	}
+
+	data, contentType, err := h.cellTagService.QRCode(currentActor(c), c.Param("id"), cellID, c.Query("format"), size)
+	if err != nil {
+		respondError(c, "cells.qr_failed", err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// LookupCell - GET /cells/lookup?code=..., карточка ячейки по отсканированному QR-коду
+func (h *CellTagHandler) LookupCell(c *gin.Context) {
+	card, err := h.cellTagService.Lookup(currentActor(c), c.Query("code"))
+	if err != nil {
+		respondError(c, "cells.lookup_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, card)
+}
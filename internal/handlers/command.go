@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommandHandler struct {
+	commandService *service.CommandService
+}
+
+func NewCommandHandler(commandService *service.CommandService) *CommandHandler {
+	return &CommandHandler{commandService: commandService}
+}
+
+// SelectCommand - POST /rus/:id/cells/:cellId/commands, выбор аппарата (select)
+func (h *CommandHandler) SelectCommand(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.SelectCommandRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	command, err := h.commandService.Select(c.Param("id"), cellID, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "commands.select_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, command)
+}
+
+// OperateCommand - POST /rus/:id/commands/:commandId/operate, подтверждение и исполнение
+func (h *CommandHandler) OperateCommand(c *gin.Context) {
+	command, err := h.commandService.Operate(c.Param("id"), c.Param("commandId"), currentActor(c))
+	if err != nil {
+		respondError(c, "commands.operate_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, command)
+}
+
+// CancelCommand - POST /rus/:id/commands/:commandId/cancel
+func (h *CommandHandler) CancelCommand(c *gin.Context) {
+	command, err := h.commandService.Cancel(c.Param("id"), c.Param("commandId"), currentActor(c))
+	if err != nil {
+		respondError(c, "commands.cancel_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
+
+// GetCommands - GET /rus/:id/commands?cellId=&state=&limit=
+func (h *CommandHandler) GetCommands(c *gin.Context) {
+	var filter models.CommandFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	commands, err := h.commandService.GetCommands(c.Param("id"), filter)
+	if err != nil {
+		respondError(c, "commands.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, commands)
+}
+
+// GetCommand - команда с журналом этапов
+func (h *CommandHandler) GetCommand(c *gin.Context) {
+	command, err := h.commandService.GetCommand(c.Param("id"), c.Param("commandId"))
+	if err != nil {
+		respondError(c, "commands.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
+
+// GetPendingCommands - GET /telemetry/commands/pending?deviceId=, опрос шлюзом
+func (h *CommandHandler) GetPendingCommands(c *gin.Context) {
+	commands, err := h.commandService.GetPending(currentActor(c), c.Query("deviceId"))
+	if err != nil {
+		respondError(c, "commands.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, commands)
+}
+
+// AckCommand - POST /telemetry/commands/:commandId/ack, квитанция RTU от шлюза
+func (h *CommandHandler) AckCommand(c *gin.Context) {
+	var req models.CommandAckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	command, err := h.commandService.Ack(c.Param("commandId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "commands.ack_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, command)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientHeader - заголовок, которым фронтенд и мобильное приложение сообщают свою версию
+const ClientHeader = "X-Client"
+
+type CompatHandler struct {
+	compatService *service.CompatService
+}
+
+func NewCompatHandler(compatService *service.CompatService) *CompatHandler {
+	return &CompatHandler{compatService: compatService}
+}
+
+// GetDateFormatStats - какие клиенты все еще присылают даты в старом строковом формате
+func (h *CompatHandler) GetDateFormatStats(c *gin.Context) {
+	stats, err := h.compatService.DateFormatStats()
+	if err != nil {
+		respondError(c, "compat.stats_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// clientName - идентификатор клиента для статистики совместимости
+func clientName(c *gin.Context) string {
+	if client := c.GetHeader(ClientHeader); client != "" {
+		return client
+	}
+	ua := c.Request.UserAgent()
+	if len(ua) > 100 {
+		ua = ua[:100]
+	}
+	return ua
+}
+
+// recordDateFormat - учитывает формат пары полей «строка / timestamp» из запроса
+func recordDateFormat(c *gin.Context, compat *service.CompatService, field string, legacy *string, typed *time.Time) {
+	switch {
+	case typed != nil:
+		compat.RecordDateFormat(clientName(c), field, false)
+	case legacy != nil && *legacy != "":
+		compat.RecordDateFormat(clientName(c), field, true)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// comtradeFields - multipart-поля загрузки осциллограммы
+var comtradeFields = []models.ComtradeFileKind{
+	models.ComtradeCFG, models.ComtradeDAT, models.ComtradeHDR, models.ComtradeINF, models.ComtradeCFF,
+}
+
+type ComtradeHandler struct {
+	comtradeService *service.ComtradeService
+}
+
+func NewComtradeHandler(comtradeService *service.ComtradeService) *ComtradeHandler {
+	return &ComtradeHandler{comtradeService: comtradeService}
+}
+
+// UploadRecord - POST /rus/:id/faults/:faultId/comtrade, multipart-поля cfg, dat, hdr, inf или cff
+func (h *ComtradeHandler) UploadRecord(c *gin.Context) {
+	var uploads []service.ComtradeUpload
+	for _, kind := range comtradeFields {
+		file, header, err := c.Request.FormFile(string(kind))
+		if err == http.ErrMissingFile {
+			continue
+		}
+		if err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+		data, err := io.ReadAll(io.LimitReader(file, service.MaxComtradeFileSize+1))
+		file.Close()
+		if err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+		uploads = append(uploads, service.ComtradeUpload{Kind: kind, FileName: header.Filename, Data: data})
+	}
+
+	record, err := h.comtradeService.Upload(c.Param("id"), c.Param("faultId"), uploads, currentActor(c))
+	if err != nil {
+		respondError(c, "comtrade.upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+// GetRecords - GET /rus/:id/comtrade?faultId=&from=&to=
+func (h *ComtradeHandler) GetRecords(c *gin.Context) {
+	var filter models.ComtradeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	records, err := h.comtradeService.GetRecords(c.Param("id"), filter)
+	if err != nil {
+		respondError(c, "comtrade.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// GetFaultRecords - GET /rus/:id/faults/:faultId/comtrade
+func (h *ComtradeHandler) GetFaultRecords(c *gin.Context) {
+	records, err := h.comtradeService.GetRecords(c.Param("id"), models.ComtradeFilter{FaultID: c.Param("faultId")})
+	if err != nil {
+		respondError(c, "comtrade.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+func (h *ComtradeHandler) GetRecord(c *gin.Context) {
+	record, err := h.comtradeService.GetRecord(c.Param("id"), c.Param("recordId"))
+	if err != nil {
+		respondError(c, "comtrade.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
+// DownloadFile - GET /rus/:id/comtrade/:recordId/files/:kind
+func (h *ComtradeHandler) DownloadFile(c *gin.Context) {
+	file, err := h.comtradeService.GetFile(c.Param("id"), c.Param("recordId"), models.ComtradeFileKind(c.Param("kind")))
+	if err != nil {
+		respondError(c, "comtrade.get_failed", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, "application/octet-stream", file.Data)
+}
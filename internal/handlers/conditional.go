@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/masking"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondConditional - успешный ответ с ETag и Last-Modified для опрашивающих клиентов.
+// ETag считается по телу после маскирования, поэтому роли с разными правами получают
+// разные теги. При совпадении If-None-Match (или, без него, If-Modified-Since)
+// отдается 304 без тела.
+func respondConditional(c *gin.Context, messageKey string, body interface{}, lastModified time.Time) {
+	data, err := json.Marshal(masking.Apply(body, currentPermissions(c)))
+	if err != nil {
+		apperrors.Respond(c, apperrors.Internal(messageKey, err))
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// notModified - проверка условных заголовков; If-None-Match имеет приоритет над If-Modified-Since
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(ims); err == nil {
+			return !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatusConfirmations - ожидающие подтверждения переключения критичной ячейки
+func (h *RuHandler) GetStatusConfirmations(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	confirmations, err := h.ruService.GetPendingConfirmations(ruID, cellID)
+	if err != nil {
+		respondError(c, "confirmation.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, confirmations)
+}
+
+// ConfirmCellStatus - подтверждение переключения вторым сотрудником
+func (h *RuHandler) ConfirmCellStatus(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	cell, err := h.ruService.ConfirmCellStatus(ruID, cellID, c.Param("confirmationId"), currentActor(c))
+	if err != nil {
+		respondError(c, "confirmation.confirm_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
+
+// SetCellCritical - признак критичной ячейки (только администратор)
+func (h *AdminRuHandler) SetCellCritical(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.SetCellCriticalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	cell, err := h.ruService.SetCellCritical(ruID, cellID, *req.IsCritical, currentActor(c))
+	if err != nil {
+		respondError(c, "cells.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsumerHandler struct {
+	consumerService *service.ConsumerService
+}
+
+func NewConsumerHandler(consumerService *service.ConsumerService) *ConsumerHandler {
+	return &ConsumerHandler{consumerService: consumerService}
+}
+
+// GetConsumers - GET /consumers?ruId=&cellId=&q=
+func (h *ConsumerHandler) GetConsumers(c *gin.Context) {
+	var filter models.ConsumerFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	consumers, err := h.consumerService.GetConsumers(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "consumers.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, consumers)
+}
+
+func (h *ConsumerHandler) GetConsumer(c *gin.Context) {
+	consumer, err := h.consumerService.GetConsumer(currentActor(c), c.Param("consumerId"))
+	if err != nil {
+		respondError(c, "consumers.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, consumer)
+}
+
+func (h *ConsumerHandler) CreateConsumer(c *gin.Context) {
+	var req models.ConsumerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	consumer, err := h.consumerService.CreateConsumer(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "consumers.save_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, consumer)
+}
+
+func (h *ConsumerHandler) UpdateConsumer(c *gin.Context) {
+	var req models.ConsumerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	consumer, err := h.consumerService.UpdateConsumer(currentActor(c), c.Param("consumerId"), &req)
+	if err != nil {
+		respondError(c, "consumers.save_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, consumer)
+}
+
+func (h *ConsumerHandler) DeleteConsumer(c *gin.Context) {
+	if err := h.consumerService.DeleteConsumer(currentActor(c), c.Param("consumerId")); err != nil {
+		respondError(c, "consumers.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(locale(c), "consumers.deleted")})
+}
+
+// AssignFeeder - POST /consumers/:consumerId/feeders, подключение к отходящей ячейке
+func (h *ConsumerHandler) AssignFeeder(c *gin.Context) {
+	var req models.AssignFeederRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	consumer, err := h.consumerService.AssignFeeder(currentActor(c), c.Param("consumerId"), &req)
+	if err != nil {
+		respondError(c, "consumers.feeder_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, consumer)
+}
+
+// RemoveFeeder - DELETE /consumers/:consumerId/feeders/:cellId
+func (h *ConsumerHandler) RemoveFeeder(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	consumer, err := h.consumerService.RemoveFeeder(currentActor(c), c.Param("consumerId"), cellID)
+	if err != nil {
+		respondError(c, "consumers.feeder_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, consumer)
+}
+
+// GetOutageImpact - GET /rus/:id/outage-impact?cells=12,15, потребители, теряющие
+// питание при отключении перечисленных ячеек
+func (h *ConsumerHandler) GetOutageImpact(c *gin.Context) {
+	var cellIDs []int
+	for _, value := range strings.Split(c.Query("cells"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		cellID, err := strconv.Atoi(value)
+		if err != nil {
+			apperrors.Respond(c, errInvalidCellID)
+			return
+		}
+		cellIDs = append(cellIDs, cellID)
+	}
+
+	impact, err := h.consumerService.Impact(c.Param("id"), cellIDs)
+	if err != nil {
+		respondError(c, "consumers.impact_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, impact)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DefectHandler struct {
+	defectService *service.DefectService
+}
+
+func NewDefectHandler(defectService *service.DefectService) *DefectHandler {
+	return &DefectHandler{defectService: defectService}
+}
+
+// GetDefects - GET /defects?ruId=&cellId=&status=&severity=&assigneeId=
+func (h *DefectHandler) GetDefects(c *gin.Context) {
+	var filter models.DefectFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	defects, err := h.defectService.GetDefects(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "defects.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defects)
+}
+
+func (h *DefectHandler) GetDefect(c *gin.Context) {
+	defect, err := h.defectService.GetDefect(currentActor(c), c.Param("defectId"))
+	if err != nil {
+		respondError(c, "defects.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defect)
+}
+
+func (h *DefectHandler) CreateDefect(c *gin.Context) {
+	var req models.CreateDefectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	defect, err := h.defectService.CreateDefect(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "defects.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, defect)
+}
+
+func (h *DefectHandler) UpdateDefect(c *gin.Context) {
+	var req models.UpdateDefectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	defect, err := h.defectService.UpdateDefect(currentActor(c), c.Param("defectId"), &req)
+	if err != nil {
+		respondError(c, "defects.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defect)
+}
+
+// UpdateDefectStatus - POST /defects/:defectId/status
+func (h *DefectHandler) UpdateDefectStatus(c *gin.Context) {
+	var req models.UpdateDefectStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	defect, err := h.defectService.UpdateStatus(c.Param("defectId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "defects.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, defect)
+}
+
+func (h *DefectHandler) DeleteDefect(c *gin.Context) {
+	defectID := c.Param("defectId")
+
+	if err := h.defectService.DeleteDefect(currentActor(c), defectID); err != nil {
+		respondError(c, "defects.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   i18n.T(locale(c), "defects.deleted"),
+		"defect_id": defectID,
+	})
+}
+
+// UploadPhoto - POST /defects/:defectId/photos, multipart-поле "photo"
+func (h *DefectHandler) UploadPhoto(c *gin.Context) {
+	fileName, data, ok := readUploadedPhoto(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.defectService.AddPhoto(c.Param("defectId"), fileName, data, currentActor(c))
+	if err != nil {
+		respondError(c, "photos.upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// GetPhoto - GET /defects/:defectId/photos/:photoId, содержимое фотографии
+func (h *DefectHandler) GetPhoto(c *gin.Context) {
+	h.sendPhoto(c, false)
+}
+
+// GetPhotoThumbnail - GET /defects/:defectId/photos/:photoId/thumbnail
+func (h *DefectHandler) GetPhotoThumbnail(c *gin.Context) {
+	h.sendPhoto(c, true)
+}
+
+func (h *DefectHandler) sendPhoto(c *gin.Context, thumb bool) {
+	photo, err := h.defectService.GetPhoto(currentActor(c), c.Param("defectId"), c.Param("photoId"), thumb)
+	if err != nil {
+		respondError(c, "photos.get_failed", err)
+		return
+	}
+
+	sendPhoto(c, photo)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+func NewDeviceHandler(deviceService *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{deviceService: deviceService}
+}
+
+// GetDevices - GET /devices?ruId=&type=&status=
+func (h *DeviceHandler) GetDevices(c *gin.Context) {
+	var filter models.DeviceFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	devices, err := h.deviceService.GetDevices(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "devices.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// GetRuDevices - GET /rus/:id/devices
+func (h *DeviceHandler) GetRuDevices(c *gin.Context) {
+	devices, err := h.deviceService.GetDevices(currentActor(c), models.DeviceFilter{RuID: c.Param("id")})
+	if err != nil {
+		respondError(c, "devices.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+func (h *DeviceHandler) GetDevice(c *gin.Context) {
+	device, err := h.deviceService.GetDevice(currentActor(c), c.Param("deviceId"))
+	if err != nil {
+		respondError(c, "devices.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+func (h *DeviceHandler) CreateDevice(c *gin.Context) {
+	var req models.DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	device, err := h.deviceService.CreateDevice(&req)
+	if err != nil {
+		respondError(c, "devices.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+func (h *DeviceHandler) UpdateDevice(c *gin.Context) {
+	var req models.DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	device, err := h.deviceService.UpdateDevice(c.Param("deviceId"), &req)
+	if err != nil {
+		respondError(c, "devices.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+func (h *DeviceHandler) DeleteDevice(c *gin.Context) {
+	deviceID := c.Param("deviceId")
+
+	if err := h.deviceService.DeleteDevice(deviceID); err != nil {
+		respondError(c, "devices.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   i18n.T(locale(c), "devices.deleted"),
+		"device_id": deviceID,
+	})
+}
+
+// CheckDevice - POST /devices/:deviceId/check, внеочередная проверка связи
+func (h *DeviceHandler) CheckDevice(c *gin.Context) {
+	device, err := h.deviceService.CheckDevice(c.Request.Context(), currentActor(c), c.Param("deviceId"))
+	if err != nil {
+		respondError(c, "devices.check_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+// Heartbeat - POST /telemetry/devices/:deviceId/heartbeat, отметка о связи от шлюза
+func (h *DeviceHandler) Heartbeat(c *gin.Context) {
+	if err := h.deviceService.Heartbeat(currentActor(c), c.Param("deviceId")); err != nil {
+		respondError(c, "devices.update_failed", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
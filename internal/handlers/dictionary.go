@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cellStatuses - статусы ячеек в порядке отображения
+var cellStatuses = []models.CellStatus{
+	models.CellStatusON,
+	models.CellStatusOFF,
+	models.CellStatusReserve,
+	models.CellStatusError,
+	models.CellStatusMaintenance,
+}
+
+type DictionaryHandler struct{}
+
+func NewDictionaryHandler() *DictionaryHandler {
+	return &DictionaryHandler{}
+}
+
+// GetCellStatuses - справочник статусов ячеек с названиями на языке запроса
+func (h *DictionaryHandler) GetCellStatuses(c *gin.Context) {
+	lang := locale(c)
+
+	items := make([]gin.H, 0, len(cellStatuses))
+	for _, status := range cellStatuses {
+		items = append(items, gin.H{
+			"value": status,
+			"label": i18n.T(lang, "status.cell."+string(status)),
+		})
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// operationalStates - вычисляемые состояния РУ в порядке отображения
+var operationalStates = []models.OperationalState{
+	models.OperationalNormal,
+	models.OperationalPartialOutage,
+	models.OperationalFullOutage,
+	models.OperationalMaintenance,
+}
+
+// GetRuStatuses - справочник статусов РУ и вычисляемых состояний с названиями на языке запроса
+func (h *DictionaryHandler) GetRuStatuses(c *gin.Context) {
+	lang := locale(c)
+
+	statuses := make([]gin.H, 0, len(models.RuStatuses))
+	for _, status := range models.RuStatuses {
+		statuses = append(statuses, gin.H{
+			"value": status,
+			"label": i18n.T(lang, "status.ru."+string(status)),
+		})
+	}
+	states := make([]gin.H, 0, len(operationalStates))
+	for _, state := range operationalStates {
+		states = append(states, gin.H{
+			"value": state,
+			"label": i18n.T(lang, "status.ru_state."+string(state)),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"statuses": statuses, "operationalStates": states})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocumentTypeHandler struct {
+	documentTypeService *service.DocumentTypeService
+}
+
+func NewDocumentTypeHandler(documentTypeService *service.DocumentTypeService) *DocumentTypeHandler {
+	return &DocumentTypeHandler{documentTypeService: documentTypeService}
+}
+
+// GetDocumentTypes - GET /document-types, виды документов, которые можно указать в записи журнала
+func (h *DocumentTypeHandler) GetDocumentTypes(c *gin.Context) {
+	types, err := h.documentTypeService.GetAll(true)
+	if err != nil {
+		respondError(c, "document_types.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+// GetAllDocumentTypes - GET /admin/document-types, включая отключенные
+func (h *DocumentTypeHandler) GetAllDocumentTypes(c *gin.Context) {
+	types, err := h.documentTypeService.GetAll(false)
+	if err != nil {
+		respondError(c, "document_types.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+func (h *DocumentTypeHandler) CreateDocumentType(c *gin.Context) {
+	var req models.CreateDocumentTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	documentType, err := h.documentTypeService.Create(&req)
+	if err != nil {
+		respondError(c, "document_types.save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, documentType)
+}
+
+func (h *DocumentTypeHandler) UpdateDocumentType(c *gin.Context) {
+	var req models.UpdateDocumentTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	documentType, err := h.documentTypeService.Update(c.Param("code"), &req)
+	if err != nil {
+		respondError(c, "document_types.save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documentType)
+}
+
+func (h *DocumentTypeHandler) DeleteDocumentType(c *gin.Context) {
+	code := c.Param("code")
+
+	if err := h.documentTypeService.Delete(code); err != nil {
+		respondError(c, "document_types.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "document_types.deleted"),
+		"code":    code,
+	})
+}
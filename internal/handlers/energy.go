@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// energyCSVHeader - колонки выгрузки расхода для биллинга
+var energyCSVHeader = []string{"month", "ru_id", "ru_name", "cell_id", "cell_number", "cell_name",
+	"consumer", "contract_number", "opening_kwh", "closing_kwh", "consumption_kwh", "readings", "meter_replaced", "complete"}
+
+type EnergyHandler struct {
+	energyService *service.EnergyService
+}
+
+func NewEnergyHandler(energyService *service.EnergyService) *EnergyHandler {
+	return &EnergyHandler{energyService: energyService}
+}
+
+// RecordReading - POST /rus/:id/cells/:cellId/meter-readings, ручной ввод показания счетчика
+func (h *EnergyHandler) RecordReading(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.RecordMeterReadingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	reading, err := h.energyService.RecordManual(currentActor(c), c.Param("id"), cellID, &req)
+	if err != nil {
+		respondError(c, "energy.record_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, reading)
+}
+
+// GetReadings - GET /rus/:id/cells/:cellId/meter-readings?from=&to=, по умолчанию за 31 день
+func (h *EnergyHandler) GetReadings(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	from, to := queryPeriod(c, 31*24*time.Hour)
+
+	readings, err := h.energyService.GetReadings(c.Param("id"), cellID, from, to)
+	if err != nil {
+		respondError(c, "energy.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"cellId":   cellID,
+		"from":     from,
+		"to":       to,
+		"readings": readings,
+	})
+}
+
+// RecordTelemetryReadings - POST /telemetry/meter-readings, показания счетчиков от шлюза
+func (h *EnergyHandler) RecordTelemetryReadings(c *gin.Context) {
+	var req models.RecordMeterReadingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	count, err := h.energyService.RecordTelemetry(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "energy.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"recorded": count})
+}
+
+// GetConsumption - GET /energy/consumption?month=YYYY-MM&ruId=&format=json|csv,
+// расход по отходящим ячейкам за месяц; format=csv - файл для загрузки в биллинг
+func (h *EnergyHandler) GetConsumption(c *gin.Context) {
+	var query models.EnergyQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	feeders, err := h.energyService.MonthlyConsumption(currentActor(c), query)
+	if err != nil {
+		respondError(c, "energy.get_failed", err)
+		return
+	}
+
+	if query.Format != "csv" {
+		respondJSON(c, http.StatusOK, gin.H{"month": query.Month, "feeders": feeders})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"energy-%s.csv\"", query.Month))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(energyCSVHeader)
+	for _, f := range feeders {
+		// Несколько потребителей одной линии перечисляются через точку с запятой
+		companies := make([]string, len(f.Consumers))
+		contracts := make([]string, len(f.Consumers))
+		for i, consumer := range f.Consumers {
+			companies[i], contracts[i] = consumer.Company, consumer.ContractNumber
+		}
+		_ = w.Write([]string{
+			f.Month, f.RuID, f.RuName, strconv.Itoa(f.CellID), f.CellNumber, f.CellName,
+			strings.Join(companies, "; "), strings.Join(contracts, "; "),
+			formatKWh(f.OpeningKWh), formatKWh(f.ClosingKWh), formatKWh(&f.ConsumptionKWh),
+			strconv.Itoa(f.Readings), strconv.FormatBool(f.MeterReplaced), strconv.FormatBool(f.Complete),
+		})
+	}
+	w.Flush()
+}
+
+// formatKWh - значение энергии для CSV; пустая строка, если показаний нет
+func formatKWh(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 3, 64)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventHandler struct {
+	eventBus       *service.EventBus
+	eventPublisher *service.EventPublisher
+}
+
+func NewEventHandler(eventBus *service.EventBus, eventPublisher *service.EventPublisher) *EventHandler {
+	return &EventHandler{eventBus: eventBus, eventPublisher: eventPublisher}
+}
+
+func (h *EventHandler) GetEvents(c *gin.Context) {
+	limit := 100
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 500 {
+			limit = l
+		}
+	}
+
+	events, err := h.eventBus.GetEvents(c.Query("status"), limit)
+	if err != nil {
+		respondError(c, "events.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+func (h *EventHandler) RequeueEvent(c *gin.Context) {
+	eventID := c.Param("eventId")
+
+	if err := h.eventBus.RequeueEvent(eventID); err != nil {
+		respondError(c, "events.requeue_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  i18n.T(locale(c), "events.requeued"),
+		"event_id": eventID,
+	})
+}
+
+// GetBrokerStats - статистика доставки доменных событий во внешний брокер
+func (h *EventHandler) GetBrokerStats(c *gin.Context) {
+	stats, err := h.eventPublisher.Stats()
+	if err != nil {
+		respondError(c, "events.broker_stats_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FaultHandler struct {
+	faultService *service.FaultService
+}
+
+func NewFaultHandler(faultService *service.FaultService) *FaultHandler {
+	return &FaultHandler{faultService: faultService}
+}
+
+// RecordTelemetryFault - POST /telemetry/faults, отключение от шлюза телеметрии
+func (h *FaultHandler) RecordTelemetryFault(c *gin.Context) {
+	var req models.TelemetryFaultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	h.record(c, req.RuID, &req.FaultInput, models.FaultSourceTelemetry)
+}
+
+// RecordFault - POST /rus/:id/faults, ручной ввод отключения
+func (h *FaultHandler) RecordFault(c *gin.Context) {
+	var req models.FaultInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	h.record(c, c.Param("id"), &req, models.FaultSourceManual)
+}
+
+func (h *FaultHandler) record(c *gin.Context, ruID string, input *models.FaultInput, source models.FaultSource) {
+	fault, created, err := h.faultService.RecordFault(ruID, input, source, currentActor(c))
+	if err != nil {
+		respondError(c, "faults.record_failed", err)
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, fault)
+}
+
+// GetFaults - GET /rus/:id/faults?cellId=&tripType=&from=&to=
+func (h *FaultHandler) GetFaults(c *gin.Context) {
+	var filter models.FaultFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	faults, err := h.faultService.GetFaults(c.Param("id"), filter)
+	if err != nil {
+		respondError(c, "faults.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, faults)
+}
+
+func (h *FaultHandler) GetFault(c *gin.Context) {
+	fault, err := h.faultService.GetFault(c.Param("id"), c.Param("faultId"))
+	if err != nil {
+		respondError(c, "faults.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fault)
+}
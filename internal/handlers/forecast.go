@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ForecastHandler struct {
+	forecastService *service.ForecastService
+}
+
+func NewForecastHandler(forecastService *service.ForecastService) *ForecastHandler {
+	return &ForecastHandler{forecastService: forecastService}
+}
+
+// GetForecast - GET /rus/:id/forecast?cellId=&horizon=24h|7d, почасовой прогноз нагрузки
+func (h *ForecastHandler) GetForecast(c *gin.Context) {
+	var query models.ForecastQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	forecast, err := h.forecastService.Forecast(c.Param("id"), query, time.Now())
+	if err != nil {
+		respondError(c, "forecast.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// GetRuns - GET /rus/:id/forecast/runs?cellId=, прошлые прогнозы с метриками точности
+func (h *ForecastHandler) GetRuns(c *gin.Context) {
+	var query models.ForecastQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	runs, err := h.forecastService.GetRuns(c.Param("id"), query.CellID)
+	if err != nil {
+		respondError(c, "forecast.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService *service.HealthService
+	startedAt     time.Time
+}
+
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService, startedAt: time.Now()}
+}
+
+// Liveness - GET /healthz, процесс жив и обрабатывает запросы; зависимости не проверяются,
+// чтобы недоступная база данных не приводила к перезапуску всех экземпляров
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":        models.HealthOK,
+		"uptimeSeconds": int64(time.Since(h.startedAt).Seconds()),
+	})
+}
+
+// Readiness - GET /readyz, состояние зависимостей; 503, пока недоступна критичная
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.healthService.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if report.Status == models.HealthUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/masking"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historyCSVFields - колонки выгрузки журнала: поля записи в JSON с учетом исправлений
+var historyCSVFields = []string{"timestamp", "cellNumber", "cellName", "action", "operator", "severity",
+	"documentType", "orderNumber", "workOrderNumber", "startDate", "endDate", "responsiblePerson", "reason", "comment"}
+
+// GetClockSkew - GET /admin/history/clock-skew, устройства с ушедшими часами
+func (h *RuHandler) GetClockSkew(c *gin.Context) {
+	var query models.ClockSkewQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.ruService.GetClockSkew(query)
+	if err != nil {
+		respondError(c, "history.clock_skew_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, report)
+}
+
+// ExportHistory - GET /rus/:id/history/export, журнал РУ за период в JSON или CSV.
+// Доступ - по праву history:export.
+func (h *RuHandler) ExportHistory(c *gin.Context) {
+	var query models.HistoryExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	records, err := h.ruService.ExportHistory(c.Param("id"), query)
+	if err != nil {
+		respondError(c, "history.export_failed", err)
+		return
+	}
+
+	if query.Format != "csv" {
+		respondJSON(c, http.StatusOK, gin.H{
+			"from":    query.From,
+			"to":      query.To,
+			"records": records,
+		})
+		return
+	}
+
+	perms := currentPermissions(c)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"history-%s-%s-%s.csv\"", c.Param("id"), query.From, query.To))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(append([]string{"id", "created_at"}, historyCSVFields...))
+	for i := range records {
+		record := &records[i]
+		row := []string{record.ID, record.CreatedAt.Format(time.RFC3339)}
+		for _, field := range historyCSVFields {
+			value := ""
+			if v := record.CurrentValue(field); v != nil {
+				value = *v
+			}
+			// Исправленные значения не проходят через теги mask, скрываем их явно
+			if field == "responsiblePerson" && value != "" && !perms.Has(permissions.ViewPersonalData) {
+				value = masking.Placeholder
+			}
+			row = append(row, value)
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InspectionHandler struct {
+	inspectionService *service.InspectionService
+}
+
+func NewInspectionHandler(inspectionService *service.InspectionService) *InspectionHandler {
+	return &InspectionHandler{inspectionService: inspectionService}
+}
+
+// GetTemplates - GET /inspections/templates?ruType=KRU, активные шаблоны для типа РУ
+func (h *InspectionHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.inspectionService.GetTemplates(c.Query("ruType"), true)
+	if err != nil {
+		respondError(c, "inspections.templates_get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetAllTemplates - GET /admin/inspections/templates, включая отключенные
+func (h *InspectionHandler) GetAllTemplates(c *gin.Context) {
+	templates, err := h.inspectionService.GetTemplates(c.Query("ruType"), false)
+	if err != nil {
+		respondError(c, "inspections.templates_get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *InspectionHandler) CreateTemplate(c *gin.Context) {
+	var req models.SaveChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	template, err := h.inspectionService.CreateTemplate(&req)
+	if err != nil {
+		respondError(c, "inspections.template_save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *InspectionHandler) UpdateTemplate(c *gin.Context) {
+	var req models.SaveChecklistTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	template, err := h.inspectionService.UpdateTemplate(c.Param("templateId"), &req)
+	if err != nil {
+		respondError(c, "inspections.template_save_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *InspectionHandler) DeleteTemplate(c *gin.Context) {
+	templateID := c.Param("templateId")
+
+	if err := h.inspectionService.DeleteTemplate(templateID); err != nil {
+		respondError(c, "inspections.template_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     i18n.T(locale(c), "inspections.template_deleted"),
+		"template_id": templateID,
+	})
+}
+
+// GetInspections - GET /rus/:id/inspections?limit=
+func (h *InspectionHandler) GetInspections(c *gin.Context) {
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	inspections, err := h.inspectionService.GetInspections(c.Param("id"), limit)
+	if err != nil {
+		respondError(c, "inspections.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspections)
+}
+
+func (h *InspectionHandler) GetInspection(c *gin.Context) {
+	inspection, err := h.inspectionService.GetInspection(currentActor(c), c.Param("inspectionId"))
+	if err != nil {
+		respondError(c, "inspections.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+// SubmitInspection - POST /rus/:id/inspections, результаты осмотра по шаблону
+func (h *InspectionHandler) SubmitInspection(c *gin.Context) {
+	var req models.SubmitInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	inspection, err := h.inspectionService.SubmitInspection(c.Param("id"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "inspections.submit_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, inspection)
+}
+
+// UploadResultPhoto - POST /inspections/:inspectionId/results/:resultId/photos
+func (h *InspectionHandler) UploadResultPhoto(c *gin.Context) {
+	fileName, data, ok := readUploadedPhoto(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.inspectionService.AddResultPhoto(c.Param("inspectionId"), c.Param("resultId"), fileName, data, currentActor(c))
+	if err != nil {
+		respondError(c, "photos.upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+func (h *InspectionHandler) GetResultPhoto(c *gin.Context) {
+	h.sendResultPhoto(c, false)
+}
+
+// GetResultPhotoThumbnail - GET /inspections/:inspectionId/results/:resultId/photos/:photoId/thumbnail
+func (h *InspectionHandler) GetResultPhotoThumbnail(c *gin.Context) {
+	h.sendResultPhoto(c, true)
+}
+
+func (h *InspectionHandler) sendResultPhoto(c *gin.Context, thumb bool) {
+	photo, err := h.inspectionService.GetResultPhoto(currentActor(c), c.Param("inspectionId"), c.Param("resultId"), c.Param("photoId"), thumb)
+	if err != nil {
+		respondError(c, "photos.get_failed", err)
+		return
+	}
+
+	sendPhoto(c, photo)
+}
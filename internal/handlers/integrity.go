@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IntegrityHandler struct {
+	integrityService *service.IntegrityService
+}
+
+func NewIntegrityHandler(integrityService *service.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{integrityService: integrityService}
+}
+
+// Verify - GET /admin/integrity/verify?chain=operation_records|audit_entries|operation_corrections
+func (h *IntegrityHandler) Verify(c *gin.Context) {
+	var req models.VerifyChainRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	result, err := h.integrityService.Verify(c.Request.Context(), req.Chain)
+	if err != nil {
+		respondError(c, "integrity.verify_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryService *service.InventoryService
+}
+
+func NewInventoryHandler(inventoryService *service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+func (h *InventoryHandler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.inventoryService.GetWarehouses(currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, warehouses)
+}
+
+// CreateWarehouse - POST /admin/inventory/warehouses
+func (h *InventoryHandler) CreateWarehouse(c *gin.Context) {
+	var req models.CreateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	warehouse, err := h.inventoryService.CreateWarehouse(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, warehouse)
+}
+
+// GetItems - GET /inventory/items?category=
+func (h *InventoryHandler) GetItems(c *gin.Context) {
+	items, err := h.inventoryService.GetItems(c.Query("category"))
+	if err != nil {
+		respondError(c, "inventory.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *InventoryHandler) CreateItem(c *gin.Context) {
+	var req models.CreateInventoryItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	item, err := h.inventoryService.CreateItem(&req)
+	if err != nil {
+		respondError(c, "inventory.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// GetStock - GET /inventory/stock?warehouseId=&itemId=&low=true
+func (h *InventoryHandler) GetStock(c *gin.Context) {
+	var filter models.StockFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	levels, err := h.inventoryService.GetStock(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "inventory.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, levels)
+}
+
+// ReceiveStock - POST /inventory/stock/receive
+func (h *InventoryHandler) ReceiveStock(c *gin.Context) {
+	var req models.ReceiveStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	level, err := h.inventoryService.Receive(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// SetMinStock - POST /inventory/stock/min
+func (h *InventoryHandler) SetMinStock(c *gin.Context) {
+	var req models.SetMinStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	level, err := h.inventoryService.SetMinQuantity(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, level)
+}
+
+// GetReservations - GET /inventory/reservations?targetType=&targetId=&ruId=&status=
+func (h *InventoryHandler) GetReservations(c *gin.Context) {
+	var filter models.ReservationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	reservations, err := h.inventoryService.GetReservations(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "inventory.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservations)
+}
+
+// Reserve - POST /inventory/reservations
+func (h *InventoryHandler) Reserve(c *gin.Context) {
+	var req models.ReserveStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	reservation, err := h.inventoryService.Reserve(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.reserve_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// ConsumeReservation - POST /inventory/reservations/:reservationId/consume
+func (h *InventoryHandler) ConsumeReservation(c *gin.Context) {
+	reservation, err := h.inventoryService.ConsumeReservation(c.Param("reservationId"), currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+// ReleaseReservation - POST /inventory/reservations/:reservationId/release
+func (h *InventoryHandler) ReleaseReservation(c *gin.Context) {
+	reservation, err := h.inventoryService.ReleaseReservation(c.Param("reservationId"), currentActor(c))
+	if err != nil {
+		respondError(c, "inventory.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
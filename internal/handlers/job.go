@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	scheduler *service.Scheduler
+}
+
+func NewJobHandler(scheduler *service.Scheduler) *JobHandler {
+	return &JobHandler{scheduler: scheduler}
+}
+
+// GetJobs - GET /admin/jobs, фоновые задачи и статус последнего запуска
+func (h *JobHandler) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, h.scheduler.GetJobs())
+}
+
+// RunJob - POST /admin/jobs/:name/run, внеплановый запуск задачи
+func (h *JobHandler) RunJob(c *gin.Context) {
+	job, err := h.scheduler.Trigger(c.Param("name"))
+	if err != nil {
+		respondError(c, "jobs.run_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
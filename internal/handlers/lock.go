@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCellLock - активный замок ячейки и история замков
+func (h *RuHandler) GetCellLock(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	active, history, err := h.ruService.GetCellLock(ruID, cellID)
+	if err != nil {
+		respondError(c, "loto.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, gin.H{
+		"locked":  active != nil,
+		"lock":    active,
+		"history": history,
+	})
+}
+
+// PlaceCellLock - установка замка и плаката на ячейку
+func (h *RuHandler) PlaceCellLock(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.PlaceCellLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	lock, err := h.ruService.PlaceCellLock(ruID, cellID, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "loto.place_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, lock)
+}
+
+// RemoveCellLock - снятие замка; маршрут доступен только инженерам и администраторам
+func (h *RuHandler) RemoveCellLock(c *gin.Context) {
+	ruID := c.Param("id")
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.RemoveCellLockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+	}
+
+	lock, err := h.ruService.RemoveCellLock(ruID, cellID, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "loto.remove_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, lock)
+}
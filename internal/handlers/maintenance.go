@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+// StartJob - запускает задачу обслуживания БД; ход выполнения доступен через GetJob
+func (h *MaintenanceHandler) StartJob(c *gin.Context) {
+	var req models.StartMaintenanceJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	job, err := h.maintenanceService.StartJob(&req, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, "maintenance.start_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *MaintenanceHandler) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, h.maintenanceService.GetJobs())
+}
+
+func (h *MaintenanceHandler) GetJob(c *gin.Context) {
+	job, err := h.maintenanceService.GetJob(c.Param("jobId"))
+	if err != nil {
+		respondError(c, "maintenance.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetCellReport - GET /admin/maintenance/cells?ruId=, дубликаты ячеек и ячейки без РУ
+func (h *MaintenanceHandler) GetCellReport(c *gin.Context) {
+	var query models.CellCleanupQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.maintenanceService.GetCellReport(query.RuID)
+	if err != nil {
+		respondError(c, "maintenance.cells_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CleanupCells - POST /admin/maintenance/cells/cleanup, слияние или удаление найденных ячеек
+func (h *MaintenanceHandler) CleanupCells(c *gin.Context) {
+	var req models.CellCleanupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.maintenanceService.CleanupCells(&req, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, "maintenance.cells_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MapHandler struct {
+	mapService *service.MapService
+}
+
+func NewMapHandler(mapService *service.MapService) *MapHandler {
+	return &MapHandler{mapService: mapService}
+}
+
+// GetGeoJSON - GET /map/geojson, подстанции и РУ с координатами и цветом состояния
+func (h *MapHandler) GetGeoJSON(c *gin.Context) {
+	collection, err := h.mapService.GeoJSON(currentActor(c))
+	if err != nil {
+		respondError(c, "map.get_failed", err)
+		return
+	}
+
+	c.Header("Content-Type", "application/geo+json; charset=utf-8")
+	respondJSON(c, http.StatusOK, collection)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MeasurementHandler struct {
+	measurementService *service.MeasurementService
+	anomalyService     *service.AnomalyService
+}
+
+func NewMeasurementHandler(measurementService *service.MeasurementService, anomalyService *service.AnomalyService) *MeasurementHandler {
+	return &MeasurementHandler{measurementService: measurementService, anomalyService: anomalyService}
+}
+
+// RecordMeasurements - прием пакета измерений от шлюза телеметрии
+func (h *MeasurementHandler) RecordMeasurements(c *gin.Context) {
+	var req models.RecordMeasurementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	count, err := h.measurementService.Record(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "measurements.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"recorded": count})
+}
+
+// GetMeasurements - GET /rus/:id/cells/:cellId/measurements?metric=load&from=&to=&resolution=auto.
+// Агрегаты тока и температуры содержат оценку аномальности score.
+func (h *MeasurementHandler) GetMeasurements(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	metric := models.MeasurementMetric(c.DefaultQuery("metric", string(models.MetricLoad)))
+
+	from, to := queryPeriod(c, 24*time.Hour)
+
+	resolution, series, err := h.measurementService.GetSeries(cellID, metric, from, to, c.Query("resolution"))
+	if err != nil {
+		respondError(c, "measurements.get_failed", err)
+		return
+	}
+
+	body := gin.H{
+		"cellId":     cellID,
+		"metric":     metric,
+		"from":       from,
+		"to":         to,
+		"resolution": resolution,
+		"points":     series,
+	}
+	// Базовая линия ячейки: обычный уровень, разброс и текущая оценка аномальности
+	if metric == models.MetricCurrent || metric == models.MetricTemperature {
+		baseline, err := h.anomalyService.Baseline(cellID, metric)
+		if err != nil {
+			respondError(c, "measurements.get_failed", err)
+			return
+		}
+		body["anomaly"] = baseline
+	}
+
+	c.JSON(http.StatusOK, body)
+}
+
+// optionalQuery - указатель на параметр запроса или nil, если он не задан
+func optionalQuery(c *gin.Context, key string) *string {
+	if value, ok := c.GetQuery(key); ok && value != "" {
+		return &value
+	}
+	return nil
+}
+
+// queryPeriod - период из параметров from/to; по умолчанию последние span до текущего момента
+func queryPeriod(c *gin.Context, span time.Duration) (time.Time, time.Time) {
+	to := time.Now()
+	if t := utils.ParseDatePtr(optionalQuery(c, "to")); t != nil {
+		to = *t
+	}
+	from := to.Add(-span)
+	if t := utils.ParseDatePtr(optionalQuery(c, "from")); t != nil {
+		from = *t
+	}
+	return from, to
+}
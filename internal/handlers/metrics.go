@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type MetricsHandler struct {
+	metricsService *service.MetricsService
+	token          string
+}
+
+// NewMetricsHandler - token (METRICS_TOKEN) требует заголовок Authorization: Bearer <token>;
+// пустой - метрики доступны без авторизации
+func NewMetricsHandler(metricsService *service.MetricsService, token string) *MetricsHandler {
+	return &MetricsHandler{metricsService: metricsService, token: token}
+}
+
+// GetMetrics - GET /metrics в текстовом формате Prometheus
+func (h *MetricsHandler) GetMetrics(c *gin.Context) {
+	if h.token != "" {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(h.token)) != 1 {
+			respondError(c, "metrics.failed", service.ErrMetricsUnauthorized)
+			return
+		}
+	}
+
+	families, err := h.metricsService.Collect()
+	if err != nil {
+		respondError(c, "metrics.failed", err)
+		return
+	}
+
+	var b strings.Builder
+	for _, family := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", family.Name, family.Help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", family.Name, family.Type)
+		for _, sample := range family.Samples {
+			b.WriteString(family.Name)
+			if len(sample.Labels) > 0 {
+				b.WriteByte('{')
+				for i, label := range sample.Labels {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					fmt.Fprintf(&b, "%s=\"%s\"", label.Name, escapeLabelValue(label.Value))
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(sample.Value, 'f', -1, 64))
+			b.WriteByte('\n')
+		}
+	}
+	c.Data(http.StatusOK, metricsContentType, []byte(b.String()))
+}
+
+// escapeLabelValue - экранирование значения метки по формату Prometheus
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
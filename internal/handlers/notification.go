@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+func (h *NotificationHandler) GetRules(c *gin.Context) {
+	ruID := c.Param("id")
+
+	rules, err := h.notificationService.GetRules(ruID)
+	if err != nil {
+		respondError(c, "notification.rules_get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *NotificationHandler) CreateRule(c *gin.Context) {
+	ruID := c.Param("id")
+
+	var req models.CreateNotificationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "notification.rule_invalid", err)
+		return
+	}
+
+	rule, err := h.notificationService.CreateRule(ruID, &req)
+	if err != nil {
+		respondError(c, "notification.rule_create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+func (h *NotificationHandler) DeleteRule(c *gin.Context) {
+	ruID := c.Param("id")
+	ruleID := c.Param("ruleId")
+
+	if err := h.notificationService.DeleteRule(ruID, ruleID); err != nil {
+		respondError(c, "notification.rule_delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "notification.rule_deleted"),
+		"rule_id": ruleID,
+	})
+}
+
+// GetMyNotifications - GET /me/notifications?unread=&category=&before=&limit=
+func (h *NotificationHandler) GetMyNotifications(c *gin.Context) {
+	var filter models.NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	list, err := h.notificationService.GetNotifications(currentActor(c).UserID, filter)
+	if err != nil {
+		respondError(c, "notification.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetUnreadCount - GET /me/notifications/unread-count, для значка колокольчика
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	count, err := h.notificationService.UnreadCount(currentActor(c).UserID)
+	if err != nil {
+		respondError(c, "notification.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unreadCount": count})
+}
+
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	notificationID := c.Param("id")
+
+	if err := h.notificationService.MarkRead(currentActor(c).UserID, notificationID); err != nil {
+		respondError(c, "notification.mark_read_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         i18n.T(locale(c), "notification.marked_read"),
+		"notification_id": notificationID,
+	})
+}
+
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	count, err := h.notificationService.MarkAllRead(currentActor(c).UserID)
+	if err != nil {
+		respondError(c, "notification.mark_read_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "notification.marked_read"),
+		"count":   count,
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NumberingHandler struct {
+	numberingService *service.NumberingService
+}
+
+func NewNumberingHandler(numberingService *service.NumberingService) *NumberingHandler {
+	return &NumberingHandler{numberingService: numberingService}
+}
+
+// IssueNumber - POST /document-numbers, следующий номер распоряжения или наряда
+func (h *NumberingHandler) IssueNumber(c *gin.Context) {
+	var req models.IssueDocumentNumberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	issued, err := h.numberingService.Issue(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "document_numbers.issue_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// GetGaps - GET /document-numbers/gaps?documentType=&year=, пропуски в нумерации
+func (h *NumberingHandler) GetGaps(c *gin.Context) {
+	var req models.DocumentNumberGapsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	gaps, err := h.numberingService.Gaps(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "document_numbers.gaps_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gaps)
+}
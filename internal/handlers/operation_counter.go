@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OperationCounterHandler struct {
+	counterService *service.OperationCounterService
+}
+
+func NewOperationCounterHandler(counterService *service.OperationCounterService) *OperationCounterHandler {
+	return &OperationCounterHandler{counterService: counterService}
+}
+
+// UpdateOperationCounter - PUT /rus/:id/cells/:cellId/operation-counter, ресурс
+// выключателя и счетчик коммутационных операций
+func (h *OperationCounterHandler) UpdateOperationCounter(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.UpdateOperationCounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	cell, err := h.counterService.UpdateCounter(c.Param("id"), cellID, &req)
+	if err != nil {
+		respondError(c, "operation_counter.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// GetOrganizations - GET /admin/organizations
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	orgs, err := h.orgService.GetAll()
+	if err != nil {
+		respondError(c, "organizations.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// CreateOrganization - POST /admin/organizations
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	org, err := h.orgService.Create(&req)
+	if err != nil {
+		respondError(c, "organizations.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// AssignSubstation - POST /admin/organizations/:id/substations, перенос подстанции
+// вместе с РУ и журналом операций в организацию
+func (h *OrganizationHandler) AssignSubstation(c *gin.Context) {
+	var req models.AssignSubstationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	moved, err := h.orgService.AssignSubstation(c.Param("id"), req.SubstationID)
+	if err != nil {
+		respondError(c, "organizations.assign_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizationId": c.Param("id"),
+		"substationId":   req.SubstationID,
+		"rusMoved":       moved,
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OutageHandler struct {
+	outageService *service.OutageService
+}
+
+func NewOutageHandler(outageService *service.OutageService) *OutageHandler {
+	return &OutageHandler{outageService: outageService}
+}
+
+// GetOutages - GET /outages?ruId=&status=&from=&to=
+func (h *OutageHandler) GetOutages(c *gin.Context) {
+	var filter models.OutageFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	outages, err := h.outageService.List(currentActor(c), filter)
+	if err != nil {
+		respondError(c, "outages.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, outages)
+}
+
+func (h *OutageHandler) GetOutage(c *gin.Context) {
+	details, err := h.outageService.Get(currentActor(c), c.Param("outageId"))
+	if err != nil {
+		respondError(c, "outages.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, details)
+}
+
+// CreateOutage - заявка на плановое отключение; в ответе предупреждения о нарядах-допусках
+func (h *OutageHandler) CreateOutage(c *gin.Context) {
+	var req models.CreateOutageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	details, err := h.outageService.Create(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "outages.create_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, details)
+}
+
+func (h *OutageHandler) ApproveOutage(c *gin.Context) {
+	h.review(c, h.outageService.Approve)
+}
+
+func (h *OutageHandler) RejectOutage(c *gin.Context) {
+	h.review(c, h.outageService.Reject)
+}
+
+func (h *OutageHandler) CancelOutage(c *gin.Context) {
+	h.review(c, h.outageService.Cancel)
+}
+
+func (h *OutageHandler) review(c *gin.Context, action func(models.Actor, string, *models.ReviewOutageRequest) (*models.OutageDetails, error)) {
+	var req models.ReviewOutageRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, "request.invalid", err)
+			return
+		}
+	}
+
+	details, err := action(currentActor(c), c.Param("outageId"), &req)
+	if err != nil {
+		respondError(c, "outages.review_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, details)
+}
+
+func (h *OutageHandler) GetNotifications(c *gin.Context) {
+	notifications, err := h.outageService.Notifications(currentActor(c), c.Param("outageId"))
+	if err != nil {
+		respondError(c, "outages.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, notifications)
+}
+
+// GetCalendar - GET /calendar/outages.ics: согласованные и отмененные отключения в формате iCalendar
+func (h *OutageHandler) GetCalendar(c *gin.Context) {
+	cal, err := h.outageService.CalendarFeed(currentActor(c))
+	if err != nil {
+		respondError(c, "outages.calendar_failed", err)
+		return
+	}
+
+	respondICal(c, "outages.ics", cal, "outages.calendar_failed")
+}
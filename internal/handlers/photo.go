@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readUploadedPhoto - читает multipart-поле "photo"; при ошибке ответ уже отправлен
+func readUploadedPhoto(c *gin.Context) (string, []byte, bool) {
+	file, header, err := c.Request.FormFile("photo")
+	if err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return "", nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, service.MaxPhotoSize+1))
+	if err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return "", nil, false
+	}
+	return header.Filename, data, true
+}
+
+// sendPhoto - отдает содержимое фотографии
+func sendPhoto(c *gin.Context, photo *models.Photo) {
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.Data(http.StatusOK, photo.ContentType, photo.Data)
+}
+
+// PhotoHandler - снимки оборудования ячеек
+type PhotoHandler struct {
+	photoService *service.PhotoService
+}
+
+func NewPhotoHandler(photoService *service.PhotoService) *PhotoHandler {
+	return &PhotoHandler{photoService: photoService}
+}
+
+// UploadCellPhoto - POST /rus/:id/cells/:cellId/photos, multipart-поле "photo"
+func (h *PhotoHandler) UploadCellPhoto(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+	fileName, data, ok := readUploadedPhoto(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.photoService.AddCellPhoto(c.Param("id"), cellID, fileName, data, currentActor(c))
+	if err != nil {
+		respondError(c, "photos.upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// GetCellPhotos - GET /rus/:id/cells/:cellId/photos, метаданные снимков ячейки
+func (h *PhotoHandler) GetCellPhotos(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	photos, err := h.photoService.GetCellPhotos(c.Param("id"), cellID)
+	if err != nil {
+		respondError(c, "photos.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+// GetCellPhoto - GET /rus/:id/cells/:cellId/photos/:photoId, оригинал снимка
+func (h *PhotoHandler) GetCellPhoto(c *gin.Context) {
+	h.sendCellPhoto(c, false)
+}
+
+// GetCellPhotoThumbnail - GET /rus/:id/cells/:cellId/photos/:photoId/thumbnail
+func (h *PhotoHandler) GetCellPhotoThumbnail(c *gin.Context) {
+	h.sendCellPhoto(c, true)
+}
+
+func (h *PhotoHandler) sendCellPhoto(c *gin.Context, thumb bool) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	photo, err := h.photoService.GetCellPhoto(c.Param("id"), cellID, c.Param("photoId"), thumb)
+	if err != nil {
+		respondError(c, "photos.get_failed", err)
+		return
+	}
+
+	sendPhoto(c, photo)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PollingHandler struct {
+	pollingService *service.PollingService
+}
+
+func NewPollingHandler(pollingService *service.PollingService) *PollingHandler {
+	return &PollingHandler{pollingService: pollingService}
+}
+
+func (h *PollingHandler) GetState(c *gin.Context) {
+	c.JSON(http.StatusOK, h.pollingService.GetState())
+}
+
+// Pause - приостанавливает опрос адаптера, РУ или адаптера на РУ
+func (h *PollingHandler) Pause(c *gin.Context) {
+	var req models.PausePollingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	pause, err := h.pollingService.Pause(&req, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, "polling.pause_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pause)
+}
+
+func (h *PollingHandler) Resume(c *gin.Context) {
+	var req models.ResumePollingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	if err := h.pollingService.Resume(&req); err != nil {
+		respondError(c, "polling.resume_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(locale(c), "polling.resumed"),
+		"adapter": req.Adapter,
+		"ruId":    req.RuID,
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PushHandler struct {
+	pushService *service.PushService
+}
+
+func NewPushHandler(pushService *service.PushService) *PushHandler {
+	return &PushHandler{pushService: pushService}
+}
+
+// GetPublicKey - GET /me/push/public-key: applicationServerKey для pushManager.subscribe
+func (h *PushHandler) GetPublicKey(c *gin.Context) {
+	key, err := h.pushService.PublicKey()
+	if err != nil {
+		respondError(c, "push.key_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+func (h *PushHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.pushService.GetSubscriptions(currentActor(c).UserID)
+	if err != nil {
+		respondError(c, "push.get_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// Subscribe - POST /me/push/subscriptions с PushSubscription.toJSON() браузера
+func (h *PushHandler) Subscribe(c *gin.Context) {
+	var req models.CreatePushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "push.subscription_invalid", err)
+		return
+	}
+
+	subscription, err := h.pushService.Subscribe(currentActor(c).UserID, &req, c.Request.UserAgent())
+	if err != nil {
+		respondError(c, "push.subscribe_failed", err)
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *PushHandler) Unsubscribe(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.pushService.Unsubscribe(currentActor(c).UserID, id); err != nil {
+		respondError(c, "push.unsubscribe_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":         i18n.T(locale(c), "push.unsubscribed"),
+		"subscription_id": id,
+	})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReadOnlyHandler struct {
+	readOnlyService *service.ReadOnlyService
+}
+
+func NewReadOnlyHandler(readOnlyService *service.ReadOnlyService) *ReadOnlyHandler {
+	return &ReadOnlyHandler{readOnlyService: readOnlyService}
+}
+
+// GetState - GET /system/read-only, текущий режим для баннера в интерфейсе
+func (h *ReadOnlyHandler) GetState(c *gin.Context) {
+	c.JSON(http.StatusOK, h.readOnlyService.State())
+}
+
+// SetState - PUT /admin/read-only, включение/выключение режима "только чтение"
+func (h *ReadOnlyHandler) SetState(c *gin.Context) {
+	var req models.SetReadOnlyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	mode, err := h.readOnlyService.Set(&req, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, "read_only.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, mode)
+}
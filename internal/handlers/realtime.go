@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// realtimeHeartbeat - комментарий SSE, не дающий прокси закрыть простаивающее соединение
+const realtimeHeartbeat = 25 * time.Second
+
+type RealtimeHandler struct {
+	realtimeService *service.RealtimeService
+}
+
+func NewRealtimeHandler(realtimeService *service.RealtimeService) *RealtimeHandler {
+	return &RealtimeHandler{realtimeService: realtimeService}
+}
+
+// Stream - GET /stream?ruId=, поток Server-Sent Events: смены статусов ячеек и РУ,
+// срабатывания защит и новые аварии (alarm.created - авария целиком, с приоритетом,
+// звуковым сигналом и рекомендуемым действием) доступных пользователю РУ. Токен передается заголовком
+// Authorization, поэтому браузерный клиент открывает поток через fetch, а не EventSource.
+// Пропущенные при обрыве события клиент получает из /sync/changes.
+func (h *RealtimeHandler) Stream(c *gin.Context) {
+	var query models.RealtimeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	events, unsubscribe, err := h.realtimeService.Subscribe(currentActor(c), query.RuID)
+	if err != nil {
+		respondError(c, "realtime.stream_failed", err)
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// nginx не буферизует поток
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(realtimeHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				return true
+			}
+			_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			return err == nil
+		}
+	})
+}
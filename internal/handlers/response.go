@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/masking"
+	"github.com/Temoojeen/sez-vision-backend/internal/middleware"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// locale - язык текущего запроса
+func locale(c *gin.Context) i18n.Lang {
+	lang, _ := c.Get(i18n.ContextKey)
+	return i18n.FromValue(lang)
+}
+
+// requestAuditEntry - сведения о запросе для записи аудита, которую дополняет сервисный слой
+func requestAuditEntry(c *gin.Context) models.AuditEntry {
+	return models.AuditEntry{
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+		Path:      c.Request.URL.Path,
+		IP:        c.ClientIP(),
+		RequestID: c.GetString(apperrors.RequestIDKey),
+	}
+}
+
+// currentActor - пользователь текущего запроса для проверок в сервисном слое
+func currentActor(c *gin.Context) models.Actor {
+	return models.Actor{
+		UserID: c.GetString("user_id"),
+		Email:  c.GetString("user_email"),
+		Role:   models.UserRole(c.GetString("user_role")),
+		Name:   c.GetString(middleware.UserNameKey),
+
+		OrganizationID: c.GetString(middleware.OrganizationKey),
+		ImpersonatorID: c.GetString(middleware.ImpersonatorIDKey),
+	}
+}
+
+// currentPermissions - права пользователя текущего запроса; анонимный запрос прав не имеет
+func currentPermissions(c *gin.Context) permissions.Set {
+	role, _ := c.Get("user_role")
+	roleStr, _ := role.(string)
+	return permissions.ForRole(roleStr)
+}
+
+// respondJSON - отправляет успешный ответ, скрывая поля, на просмотр которых у роли нет прав
+func respondJSON(c *gin.Context, status int, body interface{}) {
+	c.JSON(status, masking.Apply(body, currentPermissions(c)))
+}
+
+// respondError - отправляет ошибку сервиса в едином формате.
+// Типизированные ошибки отдаются как есть, остальные - как внутренние с сообщением по ключу messageKey.
+func respondError(c *gin.Context, messageKey string, err error) {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) {
+		err = apperrors.Internal(messageKey, err)
+	}
+	apperrors.Respond(c, err)
+}
+
+// respondValidationError - отправляет ошибку разбора или валидации тела запроса.
+// Ошибки валидатора раскладываются по полям с переведенными сообщениями.
+func respondValidationError(c *gin.Context, messageKey string, err error) {
+	appErr := apperrors.Validation(messageKey, err)
+
+	var fieldErrors validator.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		lang := locale(c)
+		details := make([]gin.H, 0, len(fieldErrors))
+		for _, fe := range fieldErrors {
+			details = append(details, gin.H{
+				"field":   fe.Field(),
+				"rule":    fe.Tag(),
+				"message": validationMessage(lang, fe),
+			})
+		}
+		appErr = appErr.WithDetails(details)
+	}
+
+	apperrors.Respond(c, appErr)
+}
+
+func validationMessage(lang i18n.Lang, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "email":
+		return i18n.T(lang, "validation."+fe.Tag(), fe.Field())
+	case "min", "max", "oneof":
+		return i18n.T(lang, "validation."+fe.Tag(), fe.Field(), fe.Param())
+	case "rustatus":
+		statuses := make([]string, len(models.RuStatuses))
+		for i, status := range models.RuStatuses {
+			statuses[i] = string(status)
+		}
+		return i18n.T(lang, "validation.oneof", fe.Field(), strings.Join(statuses, ", "))
+	default:
+		return i18n.T(lang, "validation.default", fe.Field())
+	}
+}
+
+// apiVersion - согласованная версия API текущего запроса
+func apiVersion(c *gin.Context) int {
+	if version, ok := c.Get(middleware.APIVersionKey); ok {
+		if v, ok := version.(int); ok {
+			return v
+		}
+	}
+	return middleware.DefaultAPIVersion
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidRevision = apperrors.New(apperrors.KindValidation, "invalid_revision", "Неверный номер ревизии")
+
+func (h *RuHandler) GetCellRevisions(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	revisions, err := h.ruService.GetCellRevisions(c.Param("id"), cellID)
+	if err != nil {
+		respondError(c, "revisions.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, revisions)
+}
+
+// RestoreCellRevision - POST /rus/:id/cells/:cellId/revisions/:revision/restore
+func (h *RuHandler) RestoreCellRevision(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision < 1 {
+		apperrors.Respond(c, errInvalidRevision)
+		return
+	}
+
+	cell, err := h.ruService.RestoreCellRevision(c.Param("id"), cellID, revision, currentActor(c))
+	if err != nil {
+		respondError(c, "revisions.restore_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, cell)
+}
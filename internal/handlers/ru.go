@@ -3,35 +3,89 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
+var errInvalidCellID = apperrors.New(apperrors.KindValidation, "invalid_cell_id", "Неверный ID ячейки")
+
+var errInvalidView = apperrors.New(apperrors.KindValidation, "invalid_view", "Неверное представление ответа (full или compact)")
+
+// compactView - запрошено ли сокращенное представление (?view=compact) для планшетов
+// на объектах с медленной связью
+func compactView(c *gin.Context) (bool, error) {
+	switch c.Query("view") {
+	case "", "full":
+		return false, nil
+	case "compact":
+		return true, nil
+	}
+	return false, errInvalidView
+}
+
 type RuHandler struct {
-	ruService *service.RuService
+	ruService      *service.RuService
+	compatService  *service.CompatService
+	pollingService *service.PollingService
 }
 
-func NewRuHandler(ruService *service.RuService) *RuHandler {
-	return &RuHandler{ruService: ruService}
+func NewRuHandler(ruService *service.RuService, compatService *service.CompatService, pollingService *service.PollingService) *RuHandler {
+	return &RuHandler{ruService: ruService, compatService: compatService, pollingService: pollingService}
 }
 
+// GetRu - GET /rus/:id?view=compact
 func (h *RuHandler) GetRu(c *gin.Context) {
 	ruID := c.Param("id")
+	compact, err := compactView(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
 
 	response, err := h.ruService.GetRuByID(ruID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "not_found",
-			"message": "РУ не найдено",
-			"details": err.Error(),
-		})
+		respondError(c, "ru.get_failed", err)
 		return
 	}
+	response.Polling = h.pollingService.PauseFor("", ruID)
 
-	c.JSON(http.StatusOK, response)
+	if compact {
+		respondConditional(c, "ru.get_failed", models.NewCompactRuResponse(response), response.LastModified())
+		return
+	}
+	respondConditional(c, "ru.get_failed", response, response.LastModified())
+}
+
+// GetCell - GET /rus/:id/cells/:cellId?view=compact с ETag для опроса отдельной ячейки
+func (h *RuHandler) GetCell(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+	compact, err := compactView(c)
+	if err != nil {
+		apperrors.Respond(c, err)
+		return
+	}
+
+	cell, err := h.ruService.GetCell(c.Param("id"), cellID)
+	if err != nil {
+		respondError(c, "cells.get_failed", err)
+		return
+	}
+
+	if compact {
+		respondConditional(c, "cells.get_failed", models.NewCompactCell(*cell), cell.UpdatedAt)
+		return
+	}
+	respondConditional(c, "cells.get_failed", cell, cell.UpdatedAt)
 }
 
 func (h *RuHandler) UpdateCellStatus(c *gin.Context) {
@@ -40,37 +94,32 @@ func (h *RuHandler) UpdateCellStatus(c *gin.Context) {
 
 	cellID, err := strconv.Atoi(cellIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_cell_id",
-			"message": "Неверный ID ячейки",
-		})
+		apperrors.Respond(c, errInvalidCellID)
 		return
 	}
 
 	var req models.UpdateCellStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные запроса",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	cell, err := h.ruService.UpdateCellStatus(ruID, cellID, &req)
+	cell, confirmation, err := h.ruService.UpdateCellStatus(ruID, cellID, &req, currentActor(c))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "cell not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{
-			"error":   "update_error",
-			"message": err.Error(),
+		respondError(c, "cells.update_failed", err)
+		return
+	}
+
+	// Критичная ячейка: переключение ждет подтверждения вторым сотрудником
+	if confirmation != nil {
+		respondJSON(c, http.StatusAccepted, gin.H{
+			"message":      i18n.T(locale(c), "confirmation.required"),
+			"confirmation": confirmation,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, cell)
+	respondJSON(c, http.StatusOK, cell)
 }
 
 func (h *RuHandler) UpdateCellInfo(c *gin.Context) {
@@ -79,88 +128,119 @@ func (h *RuHandler) UpdateCellInfo(c *gin.Context) {
 
 	cellID, err := strconv.Atoi(cellIDStr)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "invalid_cell_id",
-			"message": "Неверный ID ячейки",
-		})
+		apperrors.Respond(c, errInvalidCellID)
 		return
 	}
 
 	var req models.UpdateCellInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные запроса",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	cell, err := h.ruService.UpdateCellInfo(ruID, cellID, &req)
+	cell, change, err := h.ruService.UpdateCellInfo(ruID, cellID, &req, currentActor(c))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err.Error() == "cell not found" {
-			status = http.StatusNotFound
-		}
-		c.JSON(status, gin.H{
-			"error":   "update_error",
-			"message": err.Error(),
+		respondError(c, "cells.update_failed", err)
+		return
+	}
+
+	// Изменение диспетчера ожидает одобрения инженером
+	if change != nil {
+		respondJSON(c, http.StatusAccepted, gin.H{
+			"message": i18n.T(locale(c), "cell_change.pending"),
+			"change":  change,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, cell)
+	respondJSON(c, http.StatusOK, cell)
 }
 
 func (h *RuHandler) GetHistory(c *gin.Context) {
 	ruID := c.Param("id")
 
-	limit := 50
+	var filter models.HistoryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	limit := 0
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
 			limit = l
 		}
 	}
 
-	records, err := h.ruService.GetHistoryByRuID(ruID, limit)
+	records, err := h.ruService.GetHistoryByRuID(ruID, filter, limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "internal_error",
-			"message": "Ошибка получения истории",
-			"details": err.Error(),
-		})
+		respondError(c, "history.get_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, records)
+	respondJSON(c, http.StatusOK, records)
 }
 
-func (h *RuHandler) UpdateRuStatus(c *gin.Context) {
+// GetHistoryRecord - запись журнала по идентификатору (op_<ULID>, ULID или UUID старых записей)
+func (h *RuHandler) GetHistoryRecord(c *gin.Context) {
 	ruID := c.Param("id")
+	recordID := c.Param("recordId")
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
+	record, err := h.ruService.GetHistoryRecord(ruID, recordID)
+	if err != nil {
+		respondError(c, "history.get_failed", err)
+		return
 	}
 
+	respondJSON(c, http.StatusOK, record)
+}
+
+// CorrectHistoryRecord - POST /rus/:id/history/:recordId/corrections, исправление записи
+// журнала. Сама запись не меняется; исправление возвращается вместе с ней.
+func (h *RuHandler) CorrectHistoryRecord(c *gin.Context) {
+	var req models.CorrectHistoryRecordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные запроса",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	ru, err := h.ruService.UpdateRuStatus(ruID, req.Status)
+	record, err := h.ruService.CorrectHistoryRecord(c.Param("id"), c.Param("recordId"), &req, currentActor(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "update_error",
-			"message": err.Error(),
-		})
+		respondError(c, "history.correct_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, ru)
+	respondJSON(c, http.StatusCreated, record)
+}
+
+func (h *RuHandler) UpdateRuStatus(c *gin.Context) {
+	ruID := c.Param("id")
+
+	var req models.UpdateRuStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	ru, err := h.ruService.UpdateRuStatus(ruID, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "ru.status_update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, ru)
+}
+
+// ClearRuStatus - снимает ручную установку статуса РУ
+func (h *RuHandler) ClearRuStatus(c *gin.Context) {
+	ru, err := h.ruService.ClearRuStatus(c.Param("id"), currentActor(c))
+	if err != nil {
+		respondError(c, "ru.status_update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, ru)
 }
 
 func (h *RuHandler) AddHistory(c *gin.Context) {
@@ -168,170 +248,153 @@ func (h *RuHandler) AddHistory(c *gin.Context) {
 
 	var req models.AddHistoryRecordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные запроса",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	record, err := h.ruService.AddHistoryRecord(ruID, &req)
+	// Период перехода на timestamp: учитываем, кто еще присылает строковые даты
+	recordDateFormat(c, h.compatService, "timestamp", &req.Timestamp, req.TimestampAt)
+	recordDateFormat(c, h.compatService, "startDate", req.StartDate, req.StartDateAt)
+	recordDateFormat(c, h.compatService, "endDate", req.EndDate, req.EndDateAt)
+
+	req.Device = clientName(c)
+	record, err := h.ruService.AddHistoryRecord(ruID, &req, currentActor(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "internal_error",
-			"message": "Ошибка добавления записи в историю",
-			"details": err.Error(),
-		})
+		respondError(c, "history.add_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, record)
+	respondJSON(c, http.StatusCreated, record)
 }
 
+// GetAllRUs - GET /rus?include=stats&view=compact; stats добавляет к каждому РУ число ячеек
+// по статусам и активные аварии
 func (h *RuHandler) GetAllRUs(c *gin.Context) {
-	rus, err := h.ruService.GetAllRUs()
+	compact, err := compactView(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "internal_error",
-			"message": "Ошибка получения списка РУ",
-			"details": err.Error(),
-		})
+		apperrors.Respond(c, err)
+		return
+	}
+	withStats := false
+	for _, part := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(part) == "stats" {
+			withStats = true
+		}
+	}
+
+	rus, err := h.ruService.GetRUSummaries(currentActor(c), withStats)
+	if err != nil {
+		respondError(c, "ru.list_failed", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, rus)
+	if compact {
+		list := make([]models.CompactRU, len(rus))
+		for i, ru := range rus {
+			list[i] = models.NewCompactRU(ru)
+		}
+		respondJSON(c, http.StatusOK, list)
+		return
+	}
+	respondJSON(c, http.StatusOK, rus)
 }
 
 func (h *RuHandler) GetSubstationPublic(c *gin.Context) {
 	substationID := c.Param("id")
 
-	rus, err := h.ruService.GetAllRUs()
+	substation, filteredRUs, err := h.ruService.GetPublicSubstation(substationID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "internal_error",
-			"message": "Ошибка получения данных подстанции",
-		})
+		respondError(c, "substation.get_failed", err)
 		return
 	}
 
-	var filteredRUs []models.RUInfo
-	for _, ru := range rus {
-		if ru.SubstationID == substationID {
-			filteredRUs = append(filteredRUs, ru)
-		}
-	}
-
 	// Базовые данные подстанции
 	substationInfo := gin.H{
-		"id":             substationID,
-		"name":           getSubstationName(substationID),
-		"location":       getSubstationLocation(substationID),
-		"description":    getSubstationDescription(substationID),
-		"voltage":        getSubstationVoltage(),
-		"installedPower": getSubstationPower(),
+		"id":             substation.ID,
+		"name":           substation.Name,
+		"location":       substation.Location,
+		"description":    substation.Description,
+		"voltage":        substation.Voltage,
+		"installedPower": substation.InstalledPower,
 		"totalRUs":       len(filteredRUs),
 		"status":         "operational",
 		"rus":            filteredRUs,
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	respondJSON(c, http.StatusOK, gin.H{
 		"substation": substationInfo,
 	})
 }
 
-// Вспомогательные функции (без параметра id)
-func getSubstationName(id string) string {
-	switch id {
-	case "ps-164":
-		return "ПС-164"
-	case "ps-64":
-		return "ПС-64"
-	default:
-		return "Подстанция " + id
-	}
-}
+// maxOverviewOperations - предел последних операций на РУ в обзоре подстанции
+const maxOverviewOperations = 50
 
-func getSubstationLocation(id string) string {
-	// Можно дифференцировать по ID если нужно
-	switch id {
-	case "ps-164":
-		return "Северная промзона Хоргос"
-	case "ps-64":
-		return "Южная промзона Хоргос"
-	default:
-		return "Промзона Хоргос"
+// GetSubstationOverview - подстанция со всеми РУ, их ячейками и последними операциями
+// за один запрос. Вложенность выбирается параметром include=cells,operations.
+func (h *RuHandler) GetSubstationOverview(c *gin.Context) {
+	substationID := c.Param("id")
+
+	opts := models.OverviewOptions{OperationsLimit: 5}
+	for _, part := range strings.Split(c.DefaultQuery("include", "cells,operations"), ",") {
+		switch strings.TrimSpace(part) {
+		case "cells":
+			opts.IncludeCells = true
+		case "operations":
+			opts.IncludeOperations = true
+		}
+	}
+	if limitStr := c.Query("operationsLimit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			opts.OperationsLimit = min(l, maxOverviewOperations)
+		}
 	}
-}
 
-func getSubstationDescription(id string) string {
-	switch id {
-	case "ps-164":
-		return "Главная понизительная подстанция №164. Обслуживает северную часть промзоны."
-	case "ps-64":
-		return "Резервная понизительная подстанция №64. Обслуживает южную часть промзоны."
-	default:
-		return "Понизительная подстанция. Обслуживает промзону Хоргос."
+	substation, err := h.ruService.GetSubstationFor(currentActor(c), substationID)
+	if err != nil {
+		respondError(c, "substation.get_failed", err)
+		return
 	}
-}
 
-func getSubstationVoltage() string {
-	return "110/10 кВ"
-}
+	rus, err := h.ruService.GetSubstationOverview(substationID, opts)
+	if err != nil {
+		respondError(c, "substation.get_failed", err)
+		return
+	}
 
-func getSubstationPower() string {
-	return "2 × 25 МВА"
+	respondJSON(c, http.StatusOK, gin.H{
+		"substation": gin.H{
+			"id":             substation.ID,
+			"name":           substation.Name,
+			"location":       substation.Location,
+			"description":    substation.Description,
+			"voltage":        substation.Voltage,
+			"installedPower": substation.InstalledPower,
+			"totalRUs":       len(rus),
+			"status":         "operational",
+			"rus":            rus,
+		},
+	})
 }
 
-// UpdateSubstationRUs - обновление списка РУ на подстанции
+// UpdateSubstationRUs - PUT /rus/substations/:id/rus, полный список РУ подстанции;
+// РУ подстанции, которых нет в списке, от нее отвязываются
 func (h *RuHandler) UpdateSubstationRUs(c *gin.Context) {
-	substationID := c.Param("id")
-
-	var req struct {
-		RuIDs []string `json:"ruIds" binding:"required"`
-	}
-
+	var req models.UpdateSubstationRUsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "validation_error",
-			"message": "Неверные данные запроса",
-			"details": err.Error(),
-		})
+		respondValidationError(c, "request.invalid", err)
 		return
 	}
 
-	// Получаем все РУ
-	allRUs, err := h.ruService.GetAllRUs()
+	result, err := h.ruService.UpdateRUsSubstation(currentActor(c), c.Param("id"), req.RuIDs)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "internal_error",
-			"message": "Ошибка получения РУ",
-			"details": err.Error(),
-		})
+		respondError(c, "substation.rus_update_failed", err)
 		return
 	}
 
-	// Фильтруем РУ по ID и обновляем substationId
-	var updatedRUs []models.RUInfo
-	for _, ruID := range req.RuIDs {
-		// Находим РУ в списке всех РУ
-		for _, ru := range allRUs {
-			if ru.ID == ruID {
-				// Обновляем substationId
-				ru.SubstationID = substationID
-				// Здесь должна быть логика сохранения в БД
-				// Для начала просто добавим в ответ
-				updatedRUs = append(updatedRUs, ru)
-				break
-			}
-		}
-	}
-
-	// TODO: Добавить логику сохранения изменений в БД через сервис
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "РУ успешно обновлены",
-		"count":   len(updatedRUs),
-		"rus":     updatedRUs,
+	respondJSON(c, http.StatusOK, gin.H{
+		"message":    i18n.T(locale(c), "substation.rus_updated"),
+		"count":      len(result.RUs),
+		"rus":        result.RUs,
+		"unassigned": result.Unassigned,
 	})
 }
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RuntimeHandler struct {
+	runtimeService *service.RuntimeService
+}
+
+func NewRuntimeHandler(runtimeService *service.RuntimeService) *RuntimeHandler {
+	return &RuntimeHandler{runtimeService: runtimeService}
+}
+
+// GetRuntime - GET /rus/:id/runtime?minHours= - наработка РУ, трансформаторов и ячеек
+func (h *RuntimeHandler) GetRuntime(c *gin.Context) {
+	var query models.RuntimeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.runtimeService.GetRuntime(c.Param("id"), query)
+	if err != nil {
+		respondError(c, "runtime.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, report)
+}
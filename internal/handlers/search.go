@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: searchService}
+}
+
+// Search - GET /search?q=заземлен&types=cell,operation,ru&ruId=...&limit=20
+func (h *SearchHandler) Search(c *gin.Context) {
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	var types []string
+	if typesStr := c.Query("types"); typesStr != "" {
+		types = strings.Split(typesStr, ",")
+	}
+
+	results, err := h.searchService.Search(currentActor(c), c.Query("q"), types, c.Query("ruId"), limit)
+	if err != nil {
+		respondError(c, "search.failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   c.Query("q"),
+		"results": results,
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidSectionID = apperrors.New(apperrors.KindValidation, "invalid_section_id", "Неверный ID секции")
+
+type SectionHandler struct {
+	sectionService *service.SectionService
+}
+
+func NewSectionHandler(sectionService *service.SectionService) *SectionHandler {
+	return &SectionHandler{sectionService: sectionService}
+}
+
+// GetSections - секции шин РУ с состоянием и нагрузкой
+func (h *SectionHandler) GetSections(c *gin.Context) {
+	sections, err := h.sectionService.GetSections(c.Param("id"))
+	if err != nil {
+		respondError(c, "sections.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, sections)
+}
+
+func (h *SectionHandler) GetSection(c *gin.Context) {
+	sectionID, err := strconv.Atoi(c.Param("sectionId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidSectionID)
+		return
+	}
+
+	section, err := h.sectionService.GetSection(c.Param("id"), sectionID)
+	if err != nil {
+		respondError(c, "sections.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, section)
+}
+
+func (h *SectionHandler) UpdateSection(c *gin.Context) {
+	sectionID, err := strconv.Atoi(c.Param("sectionId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidSectionID)
+		return
+	}
+
+	var req models.UpdateSectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	section, err := h.sectionService.UpdateSection(c.Param("id"), sectionID, &req)
+	if err != nil {
+		respondError(c, "sections.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, section)
+}
+
+// DeEnergizeSection - проект бланка переключений для снятия напряжения с секции
+func (h *SectionHandler) DeEnergizeSection(c *gin.Context) {
+	h.switchSection(c, models.SwitchingDeEnergize)
+}
+
+// EnergizeSection - проект бланка переключений для подачи напряжения на секцию
+func (h *SectionHandler) EnergizeSection(c *gin.Context) {
+	h.switchSection(c, models.SwitchingEnergize)
+}
+
+func (h *SectionHandler) switchSection(c *gin.Context, operation models.SwitchingOperation) {
+	sectionID, err := strconv.Atoi(c.Param("sectionId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidSectionID)
+		return
+	}
+
+	var req models.SectionSwitchingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	order, err := h.sectionService.Switch(c.Param("id"), sectionID, operation, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "sections.switch_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, order)
+}
+
+// GetSwitchingOrders - бланки переключений РУ
+func (h *SectionHandler) GetSwitchingOrders(c *gin.Context) {
+	orders, err := h.sectionService.GetOrders(c.Param("id"))
+	if err != nil {
+		respondError(c, "switching_orders.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, orders)
+}
+
+func (h *SectionHandler) GetSwitchingOrder(c *gin.Context) {
+	order, err := h.sectionService.GetOrder(c.Param("id"), c.Param("orderId"))
+	if err != nil {
+		respondError(c, "switching_orders.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, order)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	settingsService *service.SettingsService
+}
+
+func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService}
+}
+
+// GetSettings - GET /admin/settings, все настройки с типами и значениями по умолчанию
+func (h *SettingsHandler) GetSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, h.settingsService.GetAll())
+}
+
+// UpdateSettings - PUT /admin/settings, {"values": {"password.min_length": 8, ...}}
+func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
+	var req models.UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	settings, err := h.settingsService.Update(&req, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, "settings.update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SLAHandler struct {
+	slaService *service.SLAService
+}
+
+func NewSLAHandler(slaService *service.SLAService) *SLAHandler {
+	return &SLAHandler{slaService: slaService}
+}
+
+// GetSummary - GET /sla/summary?from=&to=&ruId=: показатели SLA дефектов и нарядов
+func (h *SLAHandler) GetSummary(c *gin.Context) {
+	var query models.SLAQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	summary, err := h.slaService.GetSummary(currentActor(c), query)
+	if err != nil {
+		respondError(c, "sla.get_failed", err)
+		return
+	}
+	respondJSON(c, http.StatusOK, summary)
+}
+
+// GetBreaches - GET /sla/breaches?from=&to=&ruId=&kind=defect|permit&status=open|closed&limit=:
+// дефекты и наряды с нарушенным сроком
+func (h *SLAHandler) GetBreaches(c *gin.Context) {
+	var query models.SLAQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	breaches, err := h.slaService.GetBreaches(currentActor(c), query)
+	if err != nil {
+		respondError(c, "sla.get_failed", err)
+		return
+	}
+	respondJSON(c, http.StatusOK, breaches)
+}
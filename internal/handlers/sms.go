@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SmsHandler struct {
+	smsService *service.SmsService
+}
+
+func NewSmsHandler(smsService *service.SmsService) *SmsHandler {
+	return &SmsHandler{smsService: smsService}
+}
+
+// GetMessages - GET /admin/sms/messages?status=&kind=&limit=: журнал SMS со статусами доставки
+func (h *SmsHandler) GetMessages(c *gin.Context) {
+	var filter models.SmsFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	messages, err := h.smsService.GetMessages(filter)
+	if err != nil {
+		respondError(c, "sms.get_failed", err)
+		return
+	}
+	respondJSON(c, http.StatusOK, messages)
+}
+
+// GetUsage - GET /admin/sms/usage?month=YYYY-MM: месячный расход SMS
+func (h *SmsHandler) GetUsage(c *gin.Context) {
+	var query models.SmsUsageQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.smsService.GetUsage(query)
+	if err != nil {
+		respondError(c, "sms.usage_failed", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetStats - GET /admin/stats: статистика использования системы
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	stats, err := h.statsService.GetStats()
+	if err != nil {
+		respondError(c, "stats.failed", err)
+		return
+	}
+	respondJSON(c, http.StatusOK, stats)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+}
+
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionService: subscriptionService}
+}
+
+// GetMySubscriptions - GET /me/subscriptions, включая подписки по закрепленным подстанциям
+func (h *SubscriptionHandler) GetMySubscriptions(c *gin.Context) {
+	subscriptions, err := h.subscriptionService.GetSubscriptions(currentActor(c))
+	if err != nil {
+		respondError(c, "subscriptions.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	var req models.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	subscription, err := h.subscriptionService.Subscribe(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "subscriptions.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	subscriptionID := c.Param("id")
+
+	if err := h.subscriptionService.Unsubscribe(currentActor(c), subscriptionID); err != nil {
+		respondError(c, "subscriptions.delete_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         i18n.T(locale(c), "subscriptions.deleted"),
+		"subscription_id": subscriptionID,
+	})
+}
+
+// GetUserSubstations - GET /admin/users/:id/substations
+func (h *SubscriptionHandler) GetUserSubstations(c *gin.Context) {
+	assignments, err := h.subscriptionService.GetUserSubstations(currentActor(c), c.Param("id"))
+	if err != nil {
+		respondError(c, "users.substations_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// SetUserSubstations - PUT /admin/users/:id/substations, диспетчер подписывается на РУ этих подстанций
+func (h *SubscriptionHandler) SetUserSubstations(c *gin.Context) {
+	var req models.SetUserSubstationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	assignments, err := h.subscriptionService.SetUserSubstations(currentActor(c), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, "users.substations_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SummaryHandler struct {
+	summaryService *service.SummaryService
+}
+
+func NewSummaryHandler(summaryService *service.SummaryService) *SummaryHandler {
+	return &SummaryHandler{summaryService: summaryService}
+}
+
+// GetDailySummary - GET /substations/:id/daily-summary?date=YYYY-MM-DD&severity=, данные для утреннего отчета
+func (h *SummaryHandler) GetDailySummary(c *gin.Context) {
+	var req models.DailySummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	summary, err := h.summaryService.DailySummary(currentActor(c), c.Param("id"), req)
+	if err != nil {
+		respondError(c, "substation.summary_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, summary)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SyncHandler struct {
+	syncService *service.SyncService
+}
+
+func NewSyncHandler(syncService *service.SyncService) *SyncHandler {
+	return &SyncHandler{syncService: syncService}
+}
+
+// GetChanges - GET /sync/changes?since=&ruId=; since - курсор из предыдущего ответа
+func (h *SyncHandler) GetChanges(c *gin.Context) {
+	var query models.SyncChangesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	changes, err := h.syncService.Changes(currentActor(c), &query)
+	if err != nil {
+		respondError(c, "sync.changes_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, changes)
+}
+
+// Upload - POST /sync/upload, пакет операций и осмотров, выполненных без связи.
+// Ответ 200 с отчетом по каждому элементу, даже если часть элементов отклонена.
+func (h *SyncHandler) Upload(c *gin.Context) {
+	var req models.SyncUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.syncService.Upload(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "sync.upload_failed", err)
+		return
+	}
+
+	lang := locale(c)
+	for i := range report.Items {
+		if itemErr := report.Items[i].Error; itemErr != nil {
+			if msg, ok := i18n.Lookup(lang, "errors."+itemErr.Code); ok {
+				itemErr.Message = msg
+			}
+		}
+	}
+
+	respondJSON(c, http.StatusOK, report)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaskHandler struct {
+	taskService *service.TaskService
+}
+
+func NewTaskHandler(taskService *service.TaskService) *TaskHandler {
+	return &TaskHandler{taskService: taskService}
+}
+
+func (h *TaskHandler) GetMyTasks(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	tasks, err := h.taskService.GetTasksForUser(userID, locale(c))
+	if err != nil {
+		respondError(c, "tasks.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ThermalHandler struct {
+	thermalService *service.ThermalService
+}
+
+func NewThermalHandler(thermalService *service.ThermalService) *ThermalHandler {
+	return &ThermalHandler{thermalService: thermalService}
+}
+
+// CreateSnapshot - POST /rus/:id/cells/:cellId/thermal, результаты съемки тепловизором
+func (h *ThermalHandler) CreateSnapshot(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var req models.CreateThermalSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	snapshot, err := h.thermalService.CreateSnapshot(c.Param("id"), cellID, &req, currentActor(c))
+	if err != nil {
+		respondError(c, "thermal.create_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// GetSnapshots - GET /rus/:id/cells/:cellId/thermal?from=&to=
+func (h *ThermalHandler) GetSnapshots(c *gin.Context) {
+	cellID, filter, ok := bindThermalQuery(c)
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.thermalService.GetSnapshots(c.Param("id"), cellID, filter)
+	if err != nil {
+		respondError(c, "thermal.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// GetTrend - GET /rus/:id/cells/:cellId/thermal/trend?from=&to=
+func (h *ThermalHandler) GetTrend(c *gin.Context) {
+	cellID, filter, ok := bindThermalQuery(c)
+	if !ok {
+		return
+	}
+
+	trend, err := h.thermalService.GetTrend(c.Param("id"), cellID, filter)
+	if err != nil {
+		respondError(c, "thermal.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
+
+// UploadPhoto - POST /rus/:id/cells/:cellId/thermal/:snapshotId/photos, multipart-поле "photo"
+func (h *ThermalHandler) UploadPhoto(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+	fileName, data, ok := readUploadedPhoto(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.thermalService.AddPhoto(c.Param("id"), cellID, c.Param("snapshotId"), fileName, data, currentActor(c))
+	if err != nil {
+		respondError(c, "photos.upload_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// GetPhoto - GET /rus/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId
+func (h *ThermalHandler) GetPhoto(c *gin.Context) {
+	h.sendPhoto(c, false)
+}
+
+// GetPhotoThumbnail - GET /rus/:id/cells/:cellId/thermal/:snapshotId/photos/:photoId/thumbnail
+func (h *ThermalHandler) GetPhotoThumbnail(c *gin.Context) {
+	h.sendPhoto(c, true)
+}
+
+func (h *ThermalHandler) sendPhoto(c *gin.Context, thumb bool) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	photo, err := h.thermalService.GetPhoto(c.Param("id"), cellID, c.Param("snapshotId"), c.Param("photoId"), thumb)
+	if err != nil {
+		respondError(c, "photos.get_failed", err)
+		return
+	}
+
+	sendPhoto(c, photo)
+}
+
+// bindThermalQuery - ячейка из пути и период из query; при ошибке ответ уже отправлен
+func bindThermalQuery(c *gin.Context) (int, models.ThermalFilter, bool) {
+	var filter models.ThermalFilter
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return 0, filter, false
+	}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return 0, filter, false
+	}
+	return cellID, filter, true
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransformerHandler struct {
+	transformerService *service.TransformerService
+}
+
+func NewTransformerHandler(transformerService *service.TransformerService) *TransformerHandler {
+	return &TransformerHandler{transformerService: transformerService}
+}
+
+// GetTransformers - GET /rus/:id/transformers?days= - загрузка и износ изоляции
+func (h *TransformerHandler) GetTransformers(c *gin.Context) {
+	var query models.TransformerQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	transformers, err := h.transformerService.GetTransformers(c.Param("id"), query)
+	if err != nil {
+		respondError(c, "transformers.get_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, transformers)
+}
+
+func (h *TransformerHandler) UpdateTransformer(c *gin.Context) {
+	var req models.UpdateTransformerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	transformer, err := h.transformerService.UpdateTransformer(c.Param("id"), c.Param("transformerId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "transformers.update_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, transformer)
+}
+
+// SetTapPosition - PUT /rus/:id/transformers/:transformerId/tap, ручная запись переключения
+func (h *TransformerHandler) SetTapPosition(c *gin.Context) {
+	var req models.SetTapPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	change, err := h.transformerService.SetTapPosition(c.Param("id"), c.Param("transformerId"), &req, currentActor(c))
+	if err != nil {
+		respondError(c, "transformers.tap_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusCreated, change)
+}
+
+// GetTapChanges - GET /rus/:id/transformers/:transformerId/tap-changes?source=&from=&to=
+func (h *TransformerHandler) GetTapChanges(c *gin.Context) {
+	var filter models.TapChangeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	changes, err := h.transformerService.GetTapChanges(c.Param("id"), c.Param("transformerId"), filter)
+	if err != nil {
+		respondError(c, "transformers.tap_changes_failed", err)
+		return
+	}
+
+	respondJSON(c, http.StatusOK, changes)
+}
+
+// RecordTapPositions - POST /telemetry/tap-positions, положения переключателей от шлюза
+func (h *TransformerHandler) RecordTapPositions(c *gin.Context) {
+	var req models.RecordTapPositionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.transformerService.RecordTapPositions(&req, currentActor(c))
+	if err != nil {
+		respondError(c, "transformers.tap_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"log"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Собственные правила валидации запросов для тегов binding
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		log.Fatal("handlers: unexpected binding validator engine")
+	}
+	if err := v.RegisterValidation("rustatus", validateRuStatus); err != nil {
+		log.Fatalf("handlers: failed to register rustatus validator: %v", err)
+	}
+}
+
+// validateRuStatus - статус РУ из словаря models.RuStatuses
+func validateRuStatus(fl validator.FieldLevel) bool {
+	return models.RuStatus(fl.Field().String()).Valid()
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VisionHandler struct {
+	visionService *service.VisionService
+}
+
+func NewVisionHandler(visionService *service.VisionService) *VisionHandler {
+	return &VisionHandler{visionService: visionService}
+}
+
+// RecordReadings - POST /telemetry/vision-readings, пакет показаний от сервиса распознавания
+func (h *VisionHandler) RecordReadings(c *gin.Context) {
+	var req models.RecordVisionReadingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.visionService.Record(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "vision.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// RecordIndications - POST /telemetry/vision-indications, положения ячеек по индикаторам
+func (h *VisionHandler) RecordIndications(c *gin.Context) {
+	var req models.RecordVisionIndicationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	report, err := h.visionService.RecordIndications(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "vision.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetCellReadings - GET /rus/:id/cells/:cellId/vision-readings?metric=&accepted=&from=&to=
+func (h *VisionHandler) GetCellReadings(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var filter models.VisionReadingFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	readings, err := h.visionService.GetCellReadings(c.Param("id"), cellID, filter)
+	if err != nil {
+		respondError(c, "vision.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, readings)
+}
+
+// GetCellIndications - GET /rus/:id/cells/:cellId/vision-indications?result=&from=&to=
+func (h *VisionHandler) GetCellIndications(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	var filter models.VisionIndicationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	indications, err := h.visionService.GetCellIndications(c.Param("id"), cellID, filter)
+	if err != nil {
+		respondError(c, "vision.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, indications)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// voltageCSVHeader - колонки выгрузки качества напряжения: строка на фазу ячейки за сутки
+var voltageCSVHeader = []string{"date", "ru_id", "cell_id", "cell_number", "cell_name", "phase",
+	"samples", "min_v", "max_v", "avg_v", "out_of_range", "within_percent", "compliant"}
+
+type VoltageHandler struct {
+	voltageService *service.VoltageService
+}
+
+func NewVoltageHandler(voltageService *service.VoltageService) *VoltageHandler {
+	return &VoltageHandler{voltageService: voltageService}
+}
+
+// RecordVoltages - POST /telemetry/voltages, фазные напряжения ячеек стороны НН
+func (h *VoltageHandler) RecordVoltages(c *gin.Context) {
+	var req models.RecordVoltagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	count, err := h.voltageService.Record(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "voltage.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"recorded": count})
+}
+
+// GetVoltageQuality - GET /rus/:id/voltage-quality?from=&to=&cellId=&format=json|csv,
+// суточные сводки напряжения; format=csv - выгрузка для отчетности о качестве электроэнергии
+func (h *VoltageHandler) GetVoltageQuality(c *gin.Context) {
+	var query models.VoltageQualityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	summaries, err := h.voltageService.GetDailySummaries(c.Param("id"), query)
+	if err != nil {
+		respondError(c, "voltage.get_failed", err)
+		return
+	}
+
+	if query.Format != "csv" {
+		respondJSON(c, http.StatusOK, gin.H{
+			"from":             query.From,
+			"to":               query.To,
+			"nominalVoltage":   models.NominalPhaseVoltage,
+			"tolerancePercent": models.VoltageTolerancePercent,
+			"days":             summaries,
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"voltage-%s-%s-%s.csv\"", c.Param("id"), query.From, query.To))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(voltageCSVHeader)
+	for _, s := range summaries {
+		for _, phase := range s.Phases {
+			_ = w.Write([]string{
+				s.Date, s.RuID, strconv.Itoa(s.CellID), s.CellNumber, s.CellName, phase.Phase,
+				strconv.Itoa(s.Samples), formatVolts(phase.Min), formatVolts(phase.Max), formatVolts(phase.Avg),
+				strconv.Itoa(phase.OutOfRange), strconv.FormatFloat(phase.WithinPercent, 'f', 1, 64),
+				strconv.FormatBool(s.Compliant),
+			})
+		}
+	}
+	w.Flush()
+}
+
+// formatVolts - напряжение для CSV с одним знаком после запятой
+func formatVolts(value float64) string {
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
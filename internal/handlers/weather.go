@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WeatherHandler struct {
+	weatherService *service.WeatherService
+}
+
+func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
+	return &WeatherHandler{weatherService: weatherService}
+}
+
+// RecordWeather - POST /telemetry/weather, ручной ввод температуры у подстанций
+func (h *WeatherHandler) RecordWeather(c *gin.Context) {
+	var req models.RecordWeatherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, "request.invalid", err)
+		return
+	}
+
+	count, err := h.weatherService.Record(currentActor(c), &req)
+	if err != nil {
+		respondError(c, "weather.record_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"recorded": count})
+}
+
+// GetWeather - GET /substations/:id/weather?from=&to=, наблюдения за период (по умолчанию сутки)
+func (h *WeatherHandler) GetWeather(c *gin.Context) {
+	from, to := queryPeriod(c, 24*time.Hour)
+
+	observations, err := h.weatherService.GetObservations(currentActor(c), c.Param("id"), from, to)
+	if err != nil {
+		respondError(c, "weather.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"substationId": c.Param("id"),
+		"from":         from,
+		"to":           to,
+		"observations": observations,
+	})
+}
+
+// GetLoadTemperature - GET /rus/:id/cells/:cellId/load-temperature?metric=load&from=&to=,
+// часовая нагрузка ячейки и температура воздуха (по умолчанию неделя)
+func (h *WeatherHandler) GetLoadTemperature(c *gin.Context) {
+	cellID, err := strconv.Atoi(c.Param("cellId"))
+	if err != nil {
+		apperrors.Respond(c, errInvalidCellID)
+		return
+	}
+
+	metric := models.MeasurementMetric(c.DefaultQuery("metric", string(models.MetricLoad)))
+	from, to := queryPeriod(c, 7*24*time.Hour)
+
+	series, err := h.weatherService.LoadVsTemperature(c.Param("id"), cellID, metric, from, to)
+	if err != nil {
+		respondError(c, "weather.get_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
@@ -0,0 +1,140 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang - код языка интерфейса
+type Lang string
+
+const (
+	LangRU Lang = "ru"
+	LangKK Lang = "kk"
+	LangEN Lang = "en"
+
+	// Default - язык по умолчанию и язык-источник для недостающих переводов
+	Default = LangRU
+
+	// ContextKey - ключ контекста gin, под которым хранится язык запроса
+	ContextKey = "locale"
+)
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+var catalogs = map[Lang]map[string]string{}
+
+func init() {
+	for _, lang := range []Lang{LangRU, LangKK, LangEN} {
+		data, err := localesFS.ReadFile("locales/" + string(lang) + ".json")
+		if err != nil {
+			log.Fatalf("i18n: failed to read catalog %s: %v", lang, err)
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			log.Fatalf("i18n: failed to parse catalog %s: %v", lang, err)
+		}
+		catalogs[lang] = catalog
+	}
+}
+
+// Parse - приводит код языка к поддерживаемому (kz считается синонимом kk)
+func Parse(value string) (Lang, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if i := strings.IndexAny(value, "-_"); i >= 0 {
+		value = value[:i]
+	}
+	switch value {
+	case "ru":
+		return LangRU, true
+	case "kk", "kz":
+		return LangKK, true
+	case "en":
+		return LangEN, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage - выбирает язык по заголовку Accept-Language с учетом q-весов.
+// Язык с q=0 клиент не принимает, а язык с нечитаемым весом пропускается.
+func FromAcceptLanguage(header string) Lang {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q, ok := qualityValue(params)
+		if !ok || q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// qualityValue - вес q из параметров языка (1, если его нет); false - вес не число
+// от 0 до 1
+func qualityValue(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || !(q >= 0 && q <= 1) {
+			return 0, false
+		}
+		return q, true
+	}
+	return 1, true
+}
+
+// Lookup - ищет сообщение в каталоге языка, затем в каталоге по умолчанию
+func Lookup(lang Lang, key string) (string, bool) {
+	if msg, ok := catalogs[lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := catalogs[Default][key]
+	return msg, ok
+}
+
+// T - возвращает переведенное сообщение, подставляя аргументы в формате fmt.
+// Если ключ не найден, возвращается сам ключ.
+func T(lang Lang, key string, args ...interface{}) string {
+	msg, ok := Lookup(lang, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// FromValue - возвращает язык, сохраненный в контексте запроса, или язык по умолчанию
+func FromValue(value interface{}) Lang {
+	if lang, ok := value.(Lang); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
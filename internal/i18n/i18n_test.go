@@ -0,0 +1,30 @@
+package i18n
+
+import "testing"
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Lang
+	}{
+		{name: "empty header", header: "", want: Default},
+		{name: "single language", header: "en", want: LangEN},
+		{name: "highest weight wins", header: "ru;q=0.5, kk;q=0.8, en;q=0.3", want: LangKK},
+		{name: "region subtag", header: "en-US,en;q=0.9", want: LangEN},
+		{name: "unknown languages skipped", header: "de, fr;q=0.9, kk;q=0.1", want: LangKK},
+		// q=0 - язык не принимается
+		{name: "zero weight is refused", header: "en;q=0, kk;q=0.2", want: LangKK},
+		{name: "only refused language", header: "en;q=0", want: Default},
+		{name: "nonnumeric weight skipped", header: "en;q=abc, kk;q=0.5", want: LangKK},
+		{name: "weight above one skipped", header: "en;q=2, kk;q=0.5", want: LangKK},
+		{name: "other parameters before weight", header: "en;level=1;q=0.2, kk;q=0.5", want: LangKK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromAcceptLanguage(tt.header); got != tt.want {
+				t.Errorf("FromAcceptLanguage(%q) = %s, want %s", tt.header, got, tt.want)
+			}
+		})
+	}
+}
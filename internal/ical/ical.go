@@ -0,0 +1,115 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Статусы событий (RFC 5545, STATUS)
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event - событие календаря (VEVENT)
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	// AllDay - событие на весь день: даты без времени, End - следующий день после последнего
+	AllDay   bool
+	Status   string
+	Sequence int
+	Updated  time.Time
+}
+
+// Calendar - календарь для подписки из Outlook/Google Calendar (VCALENDAR)
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write - календарь в формате iCalendar: строки CRLF, длинные строки переносятся по 75 байт
+func Write(w io.Writer, cal Calendar) error {
+	b := &builder{}
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:-//SEZ Vision//Grid Calendar//RU")
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		b.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+	for _, event := range cal.Events {
+		b.line("BEGIN:VEVENT")
+		b.line("UID:" + escape(event.UID))
+		b.line("DTSTAMP:" + utc(event.Updated))
+		if event.AllDay {
+			b.line("DTSTART;VALUE=DATE:" + event.Start.Format("20060102"))
+			b.line("DTEND;VALUE=DATE:" + event.End.Format("20060102"))
+		} else {
+			b.line("DTSTART:" + utc(event.Start))
+			b.line("DTEND:" + utc(event.End))
+		}
+		b.line("SUMMARY:" + escape(event.Summary))
+		if event.Description != "" {
+			b.line("DESCRIPTION:" + escape(event.Description))
+		}
+		if event.Location != "" {
+			b.line("LOCATION:" + escape(event.Location))
+		}
+		if event.URL != "" {
+			b.line("URL:" + event.URL)
+		}
+		if event.Status != "" {
+			b.line("STATUS:" + event.Status)
+		}
+		b.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		b.line("END:VEVENT")
+	}
+	b.line("END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func utc(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format("20060102T150405Z")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(value string) string {
+	return escaper.Replace(value)
+}
+
+type builder struct {
+	strings.Builder
+}
+
+// line - строка содержимого с переносом по 75 байт (с учетом пробела в начале строки
+// продолжения) без разрыва символов UTF-8
+func (b *builder) line(content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
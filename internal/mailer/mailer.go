@@ -0,0 +1,94 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Sender - отправка писем внешним адресатам (потребителям, подрядчикам)
+type Sender interface {
+	Send(ctx context.Context, to, subject, body string) error
+	// Ping - проверка доступности сервера для /readyz
+	Ping(ctx context.Context) error
+}
+
+// New - SMTP-отправитель. Без адреса сервера возвращает nil: письма остаются в очереди.
+func New(addr, from, username, password string) (Sender, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP address %q: %w", addr, err)
+	}
+	if from == "" {
+		return nil, fmt.Errorf("SMTP sender address is required")
+	}
+	sender := &SMTPSender{addr: addr, from: from}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender, nil
+}
+
+// SMTPSender - письма через SMTP-сервер (STARTTLS, если сервер его поддерживает)
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func (s *SMTPSender) Send(ctx context.Context, to, subject, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+	msg := strings.Join([]string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+		"",
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	}, "\r\n")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(s.addr, s.auth, s.from, []string{to}, []byte(msg))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send mail to %s: %w", to, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping - соединение с SMTP-сервером до приветствия и QUIT, без отправки письма
+func (s *SMTPSender) Ping(ctx context.Context) error {
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(s.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("unexpected SMTP greeting: %w", err)
+	}
+	return client.Quit()
+}
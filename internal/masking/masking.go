@@ -0,0 +1,104 @@
+package masking
+
+import (
+	"reflect"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
+)
+
+// TagName - тег поля с правом, необходимым для его просмотра: `mask:"capacity:view"`
+const TagName = "mask"
+
+// Placeholder - значение, которым заменяются скрытые непустые строки
+const Placeholder = "***"
+
+// Apply - возвращает копию значения, в которой поля с тегом mask скрыты,
+// если у набора прав нет указанного права. Исходное значение не изменяется.
+func Apply(value interface{}, perms permissions.Set) interface{} {
+	if value == nil {
+		return nil
+	}
+	return mask(reflect.ValueOf(value), perms).Interface()
+}
+
+func mask(v reflect.Value, perms permissions.Set) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Elem().Type())
+		copied.Elem().Set(mask(v.Elem(), perms))
+		return copied
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		inner := mask(v.Elem(), perms)
+		out := reflect.New(v.Type()).Elem()
+		out.Set(inner)
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if perm, ok := field.Tag.Lookup(TagName); ok && !perms.Has(permissions.Permission(perm)) {
+				out.Field(i).Set(hidden(v.Field(i)))
+				continue
+			}
+			out.Field(i).Set(mask(v.Field(i), perms))
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(mask(v.Index(i), perms))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(mask(v.Index(i), perms))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), mask(iter.Value(), perms))
+		}
+		return out
+	}
+
+	return v
+}
+
+// hidden - значение скрытого поля: непустые строки заменяются заглушкой,
+// остальные типы обнуляются (указатели с omitempty исчезают из ответа)
+func hidden(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.String && v.Len() > 0 {
+		return reflect.ValueOf(Placeholder).Convert(v.Type())
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.String {
+		placeholder := reflect.New(v.Elem().Type())
+		placeholder.Elem().Set(reflect.ValueOf(Placeholder).Convert(v.Elem().Type()))
+		return placeholder
+	}
+	return reflect.Zero(v.Type())
+}
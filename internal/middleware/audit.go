@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditMiddleware - записывает в журнал аудита каждый изменяющий запрос пользователя
+// (включая отклоненные) с пометкой администратора, если запрос выполнен от чужого имени.
+// Подключается после AuthMiddleware.
+func AuditMiddleware(record func(models.AuditEntry)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+		userID := c.GetString("user_id")
+		if userID == "" {
+			return
+		}
+
+		entry := models.AuditEntry{
+			Action:    models.AuditActionRequest,
+			UserID:    userID,
+			UserEmail: c.GetString("user_email"),
+			Method:    c.Request.Method,
+			Route:     c.FullPath(),
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			IP:        c.ClientIP(),
+			RequestID: c.GetString(apperrors.RequestIDKey),
+		}
+		if impersonatorID := c.GetString(ImpersonatorIDKey); impersonatorID != "" {
+			impersonatorEmail := c.GetString(ImpersonatorEmailKey)
+			entry.ImpersonatorID = &impersonatorID
+			entry.ImpersonatorEmail = &impersonatorEmail
+		}
+		record(entry)
+	}
+}
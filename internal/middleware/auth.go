@@ -4,11 +4,33 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
 	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+var (
+	errAuthHeaderRequired      = apperrors.New(apperrors.KindUnauthorized, "auth_header_required", "authorization header is required")
+	errAuthHeaderFormat        = apperrors.New(apperrors.KindUnauthorized, "auth_header_invalid", "invalid authorization header format")
+	errInvalidToken            = apperrors.New(apperrors.KindUnauthorized, "invalid_token", "invalid or expired token")
+	errRoleNotFound            = apperrors.New(apperrors.KindUnauthorized, "role_not_found", "user role not found")
+	errRoleInvalid             = apperrors.New(apperrors.KindUnauthorized, "role_invalid", "invalid role type")
+	errInsufficientPermissions = apperrors.New(apperrors.KindForbidden, "forbidden", "insufficient permissions")
+)
+
+// UserNameKey - ключ контекста с отображаемым именем пользователя из токена
+const UserNameKey = "user_name"
+
+// Ключи контекста для токенов, выданных администратору от имени пользователя
+const (
+	ImpersonatorIDKey      = "impersonator_id"
+	ImpersonatorEmailKey   = "impersonator_email"
+	ImpersonationBannerKey = "impersonation_banner"
+)
+
 func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
@@ -20,28 +42,38 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header is required"})
-			c.Abort()
+			apperrors.Abort(c, errAuthHeaderRequired)
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
-			c.Abort()
+			apperrors.Abort(c, errAuthHeaderFormat)
 			return
 		}
 
 		claims, err := utils.ValidateToken(parts[1], jwtSecret)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
-			c.Abort()
+			apperrors.Abort(c, errInvalidToken)
 			return
 		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
+		c.Set(UserNameKey, claims.Name)
+
+		organizationID := claims.OrganizationID
+		if organizationID == "" {
+			organizationID = models.DefaultOrganizationID
+		}
+		c.Set(OrganizationKey, organizationID)
+
+		if claims.ImpersonatorID != "" {
+			c.Set(ImpersonatorIDKey, claims.ImpersonatorID)
+			c.Set(ImpersonatorEmailKey, claims.ImpersonatorEmail)
+			c.Set(ImpersonationBannerKey, claims.Banner)
+		}
 
 		c.Next()
 	}
@@ -51,32 +83,67 @@ func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "user role not found"})
-			c.Abort()
+			apperrors.Abort(c, errRoleNotFound)
 			return
 		}
 
 		roleStr, ok := userRole.(string)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid role type"})
-			c.Abort()
+			apperrors.Abort(c, errRoleInvalid)
 			return
 		}
 
 		hasAccess := false
 		for _, allowedRole := range allowedRoles {
-			if roleStr == allowedRole {
+			if roleStr == allowedRole || roleImplies(roleStr, allowedRole) {
 				hasAccess = true
 				break
 			}
 		}
 
 		if !hasAccess {
-			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
-			c.Abort()
+			apperrors.Abort(c, errInsufficientPermissions)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// PermissionMiddleware - доступ по праву из матрицы прав ролей (permissions), а не по
+// списку ролей: права ролей переопределяются конфигурацией
+func PermissionMiddleware(perm permissions.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("user_role")
+		if !exists {
+			apperrors.Abort(c, errRoleNotFound)
+			return
+		}
+		roleStr, ok := role.(string)
+		if !ok {
+			apperrors.Abort(c, errRoleInvalid)
+			return
+		}
+		if !permissions.ForRole(roleStr).Has(perm) {
+			apperrors.Abort(c, errInsufficientPermissions.WithDetails(gin.H{"permission": perm}))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// impliedRoles - роли, права которых включены в другую роль: администратор
+// организации выполняет инженерные операции в пределах своей организации
+var impliedRoles = map[string][]string{
+	string(models.RoleOrgAdmin): {string(models.RoleEngineer)},
+}
+
+func roleImplies(role, allowed string) bool {
+	for _, implied := range impliedRoles[role] {
+		if implied == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressibleTypes - типы содержимого, которые имеет смысл сжимать
+// (бинарные файлы осциллограмм и вложения передаются как есть)
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"application/javascript",
+	"text/",
+}
+
+// CompressionMiddleware - сжатие ответов gzip/deflate по Accept-Encoding.
+// Ответ буферизуется до minSize байт: меньшие ответы и несжимаемые типы уходят без
+// изменений, большие (РУ с ячейками, страницы журнала) сжимаются потоково.
+func CompressionMiddleware(minSize, level int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize, level: level}
+		c.Writer = cw
+		defer cw.finish()
+
+		c.Next()
+	}
+}
+
+// negotiateEncoding - gzip, затем deflate; кодировки с q=0 исключаются
+func negotiateEncoding(header string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[name] = true
+	}
+
+	switch {
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	}
+	return ""
+}
+
+type compressWriter struct {
+	gin.ResponseWriter
+
+	encoding string
+	minSize  int
+	level    int
+
+	buf         bytes.Buffer
+	encoder     io.WriteCloser
+	passthrough bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+
+	if !w.compressible() {
+		if err := w.pass(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.startEncoder(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush - потоковые ответы (SSE) не ждут заполнения буфера
+func (w *compressWriter) Flush() {
+	if w.encoder != nil {
+		if f, ok := w.encoder.(interface{ Flush() error }); ok {
+			_ = f.Flush()
+		}
+	} else if !w.passthrough {
+		_ = w.pass()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.passthrough = true
+	return w.ResponseWriter.Hijack()
+}
+
+// compressible - ответ еще не закодирован, имеет тело и подходящий тип
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	switch w.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(contentType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// pass - отказ от сжатия: накопленное уходит без изменений
+func (w *compressWriter) pass() error {
+	w.passthrough = true
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) startEncoder() error {
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+
+	var err error
+	if w.encoding == "gzip" {
+		w.encoder, err = gzip.NewWriterLevel(w.ResponseWriter, w.level)
+	} else {
+		w.encoder, err = flate.NewWriter(w.ResponseWriter, w.level)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.encoder.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish - дописывает сжатый поток или отдает короткий ответ целиком
+func (w *compressWriter) finish() {
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		return
+	}
+	_ = w.pass()
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleMiddleware - определяет язык ответа по заголовку Accept-Language
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(i18n.ContextKey, i18n.FromAcceptLanguage(c.GetHeader("Accept-Language")))
+		c.Next()
+	}
+}
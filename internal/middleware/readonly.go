@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errReadOnlyMode = apperrors.New(apperrors.KindUnavailable, "read_only_mode", "the system is in read-only mode")
+
+// ReadOnlyMiddleware - в режиме "только чтение" отклоняет изменяющие запросы с 503,
+// чтения продолжают работать. exempt - окончания маршрутов, доступных в любом режиме
+// (вход в систему, сам переключатель режима, чтение через POST), сравниваются с шаблоном
+// маршрута gin, поэтому одинаково работают для /api и /api/v1.
+func ReadOnlyMiddleware(state func() models.ReadOnlyMode, exempt ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		for _, suffix := range exempt {
+			if strings.HasSuffix(route, suffix) {
+				c.Next()
+				return
+			}
+		}
+
+		mode := state()
+		if mode.Enabled {
+			apperrors.Abort(c, errReadOnlyMode.WithDetails(gin.H{
+				"reason": mode.Reason,
+				"setBy":  mode.SetBy,
+				"setAt":  mode.SetAt,
+			}))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware - присваивает каждому запросу идентификатор
+// (берет из заголовка X-Request-ID или генерирует новый) и возвращает его в ответе
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(apperrors.RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationKey - ключ контекста с организацией пользователя из токена
+const OrganizationKey = "organization_id"
+
+var errTenantRuNotFound = apperrors.New(apperrors.KindNotFound, "ru_not_found", "ru not found")
+
+// TenantMiddleware - ограничивает маршруты вида /rus/:id организацией пользователя.
+// РУ чужой организации отвечает 404, как несуществующее, чтобы не раскрывать, что оно есть.
+// Администратор установки видит РУ всех организаций.
+func TenantMiddleware(ruOrganization func(ruID string) (string, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ruID := c.Param("id")
+		if !strings.Contains(c.FullPath(), "/rus/:id") || c.GetString("user_role") == string(models.RoleAdmin) {
+			c.Next()
+			return
+		}
+
+		organizationID, err := ruOrganization(ruID)
+		if err != nil {
+			apperrors.Abort(c, err)
+			return
+		}
+		if organizationID != c.GetString(OrganizationKey) {
+			apperrors.Abort(c, errTenantRuNotFound)
+			return
+		}
+
+		c.Next()
+	}
+}
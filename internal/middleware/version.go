@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIVersionKey - ключ контекста gin с согласованной версией API
+	APIVersionKey = "api_version"
+	// APIVersionHeader - заголовок запроса/ответа с версией API
+	APIVersionHeader = "X-API-Version"
+	// DefaultAPIVersion - версия для клиентов, не указавших версию явно
+	DefaultAPIVersion = 1
+)
+
+// SupportedAPIVersions - версии формата ответов, которые сервер умеет отдавать
+var SupportedAPIVersions = map[int]bool{
+	1: true,
+}
+
+// vendorMediaType - версия в заголовке Accept: application/vnd.sez.v1+json
+var vendorMediaType = regexp.MustCompile(`application/vnd\.sez\.v(\d+)\+json`)
+
+var (
+	errUnsupportedAPIVersion = apperrors.New(apperrors.KindValidation, "unsupported_api_version", "unsupported API version")
+	errAPIVersionMismatch    = apperrors.New(apperrors.KindValidation, "api_version_mismatch", "requested API version does not match the URL")
+)
+
+// requestedAPIVersion - версия, запрошенная клиентом через заголовки (0 - не указана)
+func requestedAPIVersion(c *gin.Context) (int, error) {
+	for _, header := range []string{APIVersionHeader, "Accept-Version"} {
+		if value := strings.TrimPrefix(strings.TrimSpace(c.GetHeader(header)), "v"); value != "" {
+			return strconv.Atoi(value)
+		}
+	}
+	if m := vendorMediaType.FindStringSubmatch(c.GetHeader("Accept")); m != nil {
+		return strconv.Atoi(m[1])
+	}
+	return 0, nil
+}
+
+// APIVersionMiddleware - согласование версии API.
+// pinned > 0 - версия зафиксирована путем (/api/v1), заголовок может лишь подтвердить ее.
+// pinned == 0 - слой совместимости /api: версия берется из заголовков или DefaultAPIVersion,
+// а ответ помечается как устаревший со ссылкой на версионированный путь.
+func APIVersionMiddleware(pinned int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requested, err := requestedAPIVersion(c)
+		if err != nil {
+			apperrors.Abort(c, errUnsupportedAPIVersion.WithDetails(err.Error()))
+			return
+		}
+
+		version := pinned
+		if pinned > 0 {
+			if requested != 0 && requested != pinned {
+				apperrors.Abort(c, errAPIVersionMismatch.WithDetails(gin.H{"url": pinned, "requested": requested}))
+				return
+			}
+		} else {
+			version = requested
+			if version == 0 {
+				version = DefaultAPIVersion
+			}
+			c.Header("Deprecation", "true")
+			c.Header("Link", fmt.Sprintf("</api/v%d%s>; rel=\"successor-version\"",
+				version, strings.TrimPrefix(c.Request.URL.Path, "/api")))
+		}
+
+		if !SupportedAPIVersions[version] {
+			apperrors.Abort(c, errUnsupportedAPIVersion.WithDetails(gin.H{"requested": version}))
+			return
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, strconv.Itoa(version))
+		c.Next()
+	}
+}
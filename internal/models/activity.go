@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+const IDPrefixLogin = "login"
+
+// Причины неудачного входа
+const (
+	LoginFailureUnknownEmail    = "unknown_email"
+	LoginFailureInvalidPassword = "invalid_password"
+	// LoginFailureInactive - учетная запись не подтверждена, ждет одобрения или отклонена
+	LoginFailureInactive = "inactive"
+)
+
+// LoginEvent - попытка входа: успешная или нет, с адресом и клиентом
+type LoginEvent struct {
+	ID            string    `json:"id" gorm:"primaryKey"`
+	UserID        *string   `json:"userId,omitempty" gorm:"index"`
+	Email         string    `json:"email" gorm:"index" mask:"personal_data:view"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failureReason,omitempty"`
+	IP            string    `json:"ip" mask:"personal_data:view"`
+	UserAgent     string    `json:"userAgent"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"index"`
+}
+
+func (LoginEvent) TableName() string {
+	return "login_events"
+}
+
+// ClientInfo - откуда пришел запрос на вход
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// UserActivity - ответ GET /admin/users/:id/activity: входы пользователя и его
+// последние изменения из журнала аудита (в том числе сделанные от его имени)
+type UserActivity struct {
+	User    UserResponse `json:"user"`
+	Logins  []LoginEvent `json:"logins"`
+	Actions []AuditEntry `json:"actions"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// Actor - пользователь, выполняющий действие, для проверок прав в сервисном слое
+type Actor struct {
+	UserID         string   `json:"userId"`
+	Email          string   `json:"email"`
+	Role           UserRole `json:"role"`
+	Name           string   `json:"name,omitempty"`
+	OrganizationID string   `json:"organizationId"`
+	// ImpersonatorID - администратор, работающий от имени пользователя
+	ImpersonatorID string `json:"impersonatorId,omitempty"`
+}
+
+// UserActor - пользователь как Actor для проверок вне HTTP-запроса (входящие, календарь);
+// пользователь без организации относится к организации по умолчанию
+func UserActor(user *User) Actor {
+	actor := Actor{UserID: user.ID, Email: user.Email, Role: user.Role, Name: user.Name, OrganizationID: user.OrganizationID}
+	if actor.OrganizationID == "" {
+		actor.OrganizationID = DefaultOrganizationID
+	}
+	return actor
+}
+
+// DisplayName - имя для журнала операций; для токенов без имени - адрес почты
+func (a Actor) DisplayName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Email
+}
+
+// IsElevated - инженер или администратор (в том числе администратор организации)
+func (a Actor) IsElevated() bool {
+	return a.Role == RoleEngineer || a.Role == RoleAdmin || a.Role == RoleOrgAdmin
+}
+
+// IsPlatformAdmin - администратор всей установки: видит данные всех организаций
+func (a Actor) IsPlatformAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+// OrganizationScope - организация для отбора данных в репозиториях; пустая строка -
+// без ограничения (администратор установки)
+func (a Actor) OrganizationScope() string {
+	if a.IsPlatformAdmin() {
+		return ""
+	}
+	return a.OrganizationID
+}
+
+// CanAccessOrganization - доступны ли пользователю данные организации
+func (a Actor) CanAccessOrganization(organizationID string) bool {
+	return a.IsPlatformAdmin() || organizationID == a.OrganizationID
+}
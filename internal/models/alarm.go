@@ -0,0 +1,270 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ ALARM MODELS ================
+
+type AlarmSeverity string
+
+const (
+	AlarmSeverityCritical AlarmSeverity = "critical"
+	AlarmSeverityWarning  AlarmSeverity = "warning"
+	AlarmSeverityInfo     AlarmSeverity = "info"
+)
+
+type AlarmStatus string
+
+const (
+	AlarmStatusActive       AlarmStatus = "active"
+	AlarmStatusAcknowledged AlarmStatus = "acknowledged"
+)
+
+// AlarmKind - источник аварии
+type AlarmKind string
+
+const (
+	AlarmKindCellStatus    AlarmKind = "cell_status"
+	AlarmKindFault         AlarmKind = "fault"
+	AlarmKindDeviceOffline AlarmKind = "device_offline"
+	AlarmKindAnomaly       AlarmKind = "anomaly"
+	AlarmKindCapacity      AlarmKind = "capacity"
+	AlarmKindStockLow      AlarmKind = "stock_low"
+	// AlarmKindDiscrepancy - журнал расходится с положением ячейки на панели
+	AlarmKindDiscrepancy AlarmKind = "discrepancy"
+	// AlarmKindThermal - перегрев точки по результатам тепловизионного обхода
+	AlarmKindThermal AlarmKind = "thermal"
+	// AlarmKindTapChanger - слишком частые переключения ответвлений трансформатора
+	AlarmKindTapChanger AlarmKind = "tap_changer"
+	// AlarmKindVoltage - напряжение стороны НН вне допустимого отклонения
+	AlarmKindVoltage AlarmKind = "voltage"
+)
+
+// AlarmPriority - приоритет аварии на пульте диспетчера, 1 - наивысший
+type AlarmPriority int
+
+const (
+	AlarmPriorityUrgent AlarmPriority = 1
+	AlarmPriorityHigh   AlarmPriority = 2
+	AlarmPriorityMedium AlarmPriority = 3
+	AlarmPriorityLow    AlarmPriority = 4
+)
+
+// AlarmAudibleClass - звуковой сигнал пульта при появлении аварии
+type AlarmAudibleClass string
+
+const (
+	// AlarmAudibleHorn - непрерывный сигнал до квитирования
+	AlarmAudibleHorn AlarmAudibleClass = "horn"
+	// AlarmAudibleChime - однократный сигнал
+	AlarmAudibleChime AlarmAudibleClass = "chime"
+	// AlarmAudibleSilent - только световая индикация
+	AlarmAudibleSilent AlarmAudibleClass = "silent"
+)
+
+// AlarmAnnunciation - приоритет и звуковой сигнал аварии по источнику и важности.
+// Наивысший приоритет - у отключений и смены статуса ячеек: они требуют действий
+// диспетчера сразу; предупреждения по обходам, складу и трендам звука не подают.
+func AlarmAnnunciation(kind AlarmKind, severity AlarmSeverity) (AlarmPriority, AlarmAudibleClass) {
+	switch severity {
+	case AlarmSeverityCritical:
+		if kind == AlarmKindCellStatus || kind == AlarmKindFault {
+			return AlarmPriorityUrgent, AlarmAudibleHorn
+		}
+		return AlarmPriorityHigh, AlarmAudibleHorn
+	case AlarmSeverityWarning:
+		switch kind {
+		case AlarmKindAnomaly, AlarmKindTapChanger, AlarmKindStockLow:
+			return AlarmPriorityLow, AlarmAudibleSilent
+		}
+		return AlarmPriorityMedium, AlarmAudibleChime
+	}
+	return AlarmPriorityLow, AlarmAudibleSilent
+}
+
+const (
+	IDPrefixAlarm           = "alarm"
+	IDPrefixAlarmFilter     = "afilter"
+	IDPrefixAlarmEscalation = "aesc"
+)
+
+// Alarm - авария, требующая квитирования диспетчером
+type Alarm struct {
+	ID         string        `json:"id" gorm:"primaryKey"`
+	RuID       string        `json:"ruId" gorm:"index"`
+	CellID     *int          `json:"cellId,omitempty"`
+	CellNumber string        `json:"cellNumber"`
+	Kind       AlarmKind     `json:"kind,omitempty" gorm:"index"`
+	Severity   AlarmSeverity `json:"severity" gorm:"index"`
+	Status     AlarmStatus   `json:"status" gorm:"index"`
+	Message    string        `json:"message"`
+	// Priority, AudibleClass и RecommendedAction - для пульта диспетчера, чтобы он
+	// подсвечивал и озвучивал аварию без обращения к справочникам
+	Priority          AlarmPriority     `json:"priority" gorm:"index"`
+	AudibleClass      AlarmAudibleClass `json:"audibleClass"`
+	RecommendedAction string            `json:"recommendedAction"`
+	EventID           string            `json:"eventId" gorm:"uniqueIndex"`
+	RaisedAt          time.Time         `json:"raisedAt" gorm:"index"`
+	AcknowledgedAt    *time.Time        `json:"acknowledgedAt,omitempty"`
+	AcknowledgedBy    *string           `json:"acknowledgedBy,omitempty"`
+	AckComment        *string           `json:"ackComment,omitempty"`
+	// Отложенная диспетчером авария скрыта из рабочего списка до ShelvedUntil
+	ShelvedUntil *time.Time `json:"shelvedUntil,omitempty" gorm:"index"`
+	ShelvedBy    *string    `json:"shelvedBy,omitempty"`
+	ShelveReason *string    `json:"shelveReason,omitempty"`
+	// Авария по ячейке под открытым нарядом-допуском подавлена до его закрытия:
+	// SuppressedBy - ID записи журнала с нарядом
+	SuppressedBy        *string   `json:"suppressedBy,omitempty" gorm:"index"`
+	SuppressedWorkOrder *string   `json:"suppressedWorkOrder,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Escalations - выполненные шаги эскалации, заполняются при выдаче списка
+	Escalations []AlarmEscalation `json:"escalations,omitempty" gorm:"-"`
+}
+
+func (Alarm) TableName() string {
+	return "alarms"
+}
+
+// EscalationStep - шаг цепочки эскалации: через Delay после аварии без квитирования
+// уведомляются пользователи роли Role
+type EscalationStep struct {
+	Role  UserRole      `json:"role"`
+	Delay time.Duration `json:"delay"`
+}
+
+// AlarmEscalation - выполненный шаг эскалации неквитированной аварии
+type AlarmEscalation struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	AlarmID     string    `json:"alarmId" gorm:"uniqueIndex:idx_alarm_escalations_step"`
+	Step        int       `json:"step" gorm:"uniqueIndex:idx_alarm_escalations_step"`
+	Role        UserRole  `json:"role"`
+	Delay       string    `json:"delay"`
+	EscalatedAt time.Time `json:"escalatedAt"`
+}
+
+func (AlarmEscalation) TableName() string {
+	return "alarm_escalations"
+}
+
+// AlarmFilter - критерии отбора аварий
+type AlarmFilter struct {
+	RuID     string        `json:"ruId,omitempty" form:"ruId"`
+	Kind     AlarmKind     `json:"kind,omitempty" form:"kind" binding:"omitempty,oneof=cell_status fault device_offline anomaly capacity stock_low discrepancy thermal tap_changer voltage"`
+	Severity AlarmSeverity `json:"severity,omitempty" form:"severity" binding:"omitempty,oneof=critical warning info"`
+	// Priority - аварии этого приоритета и выше (1 - только наивысшего)
+	Priority AlarmPriority `json:"priority,omitempty" form:"priority" binding:"omitempty,min=1,max=4"`
+	Status   AlarmStatus   `json:"status,omitempty" form:"status" binding:"omitempty,oneof=active acknowledged"`
+	Before   *time.Time    `json:"before,omitempty" form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+	After    *time.Time    `json:"after,omitempty" form:"after" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Visibility - по умолчанию только рабочий список, без отложенных и подавленных аварий
+	Visibility AlarmVisibility `json:"visibility,omitempty" form:"visibility" binding:"omitempty,oneof=actionable hidden all"`
+	// OrganizationID - только аварии РУ организации; задается сервисом по пользователю,
+	// пустая - аварии всех организаций
+	OrganizationID string `json:"-" form:"-"`
+}
+
+// AlarmVisibility - какие аварии попадают в выборку с учетом откладывания и подавления
+type AlarmVisibility string
+
+const (
+	AlarmVisibilityActionable AlarmVisibility = "actionable"
+	AlarmVisibilityHidden     AlarmVisibility = "hidden"
+	AlarmVisibilityAll        AlarmVisibility = "all"
+)
+
+// IsEmpty - не задано ни одного критерия
+func (f AlarmFilter) IsEmpty() bool {
+	return f.RuID == "" && f.Kind == "" && f.Severity == "" && f.Priority == 0 && f.Before == nil && f.After == nil
+}
+
+// AckAlarmsRequest - массовое квитирование по фильтру или списку идентификаторов
+type AckAlarmsRequest struct {
+	Filter  AlarmFilter `json:"filter"`
+	IDs     []string    `json:"ids,omitempty"`
+	Comment string      `json:"comment" binding:"required,min=3,max=500"`
+}
+
+// ShelveAlarmRequest - отложить мешающую аварию на ограниченное время
+type ShelveAlarmRequest struct {
+	Minutes int    `json:"minutes" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// SavedAlarmFilter - сохраненный пользователем фильтр аварий
+type SavedAlarmFilter struct {
+	ID        string        `json:"id" gorm:"primaryKey"`
+	UserID    string        `json:"userId" gorm:"index"`
+	Name      string        `json:"name"`
+	RuID      string        `json:"ruId,omitempty"`
+	Severity  AlarmSeverity `json:"severity,omitempty"`
+	Status    AlarmStatus   `json:"status,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (SavedAlarmFilter) TableName() string {
+	return "saved_alarm_filters"
+}
+
+// CreateSavedAlarmFilterRequest - запрос на сохранение фильтра
+type CreateSavedAlarmFilterRequest struct {
+	Name     string        `json:"name" binding:"required,min=1,max=100"`
+	RuID     string        `json:"ruId,omitempty"`
+	Severity AlarmSeverity `json:"severity,omitempty" binding:"omitempty,oneof=critical warning info"`
+	Status   AlarmStatus   `json:"status,omitempty" binding:"omitempty,oneof=active acknowledged"`
+}
+
+// AlarmStatsQuery - период отчета по авариям (даты YYYY-MM-DD включительно), РУ,
+// группировка (по ячейкам или по РУ), число строк и формат
+type AlarmStatsQuery struct {
+	From    string `form:"from" binding:"required"`
+	To      string `form:"to" binding:"required"`
+	RuID    string `form:"ruId"`
+	GroupBy string `form:"groupBy" binding:"omitempty,oneof=cell ru"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Format  string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// AlarmStatsRow - авария в выборке для статистики, только поля, нужные для расчета
+type AlarmStatsRow struct {
+	RuID           string
+	RuName         string
+	CellNumber     string
+	Kind           AlarmKind
+	Severity       AlarmSeverity
+	RaisedAt       time.Time
+	AcknowledgedAt *time.Time
+}
+
+// AlarmStats - аварии ячейки или РУ за период. MeanTimeToAckSeconds - среднее время
+// от аварии до квитирования по квитированным авариям; ChatteringEpisodes - сколько раз
+// источник выдал не меньше ChatterCount аварий одного вида за ChatterWindow
+type AlarmStats struct {
+	RuID                 string            `json:"ruId,omitempty"`
+	RuName               string            `json:"ruName,omitempty"`
+	CellNumber           string            `json:"cellNumber,omitempty"`
+	Count                int               `json:"count"`
+	Critical             int               `json:"critical"`
+	Warning              int               `json:"warning"`
+	Info                 int               `json:"info"`
+	ByKind               map[AlarmKind]int `json:"byKind"`
+	Acknowledged         int               `json:"acknowledged"`
+	MeanTimeToAckSeconds *float64          `json:"meanTimeToAckSeconds"`
+	ChatteringEpisodes   int               `json:"chatteringEpisodes"`
+	ChatteringKinds      []AlarmKind       `json:"chatteringKinds,omitempty"`
+}
+
+// AlarmStatsReport - статистика аварий за период: итог и источники, от самых
+// шумных к самым тихим
+type AlarmStatsReport struct {
+	From                 string       `json:"from"`
+	To                   string       `json:"to"`
+	GroupBy              string       `json:"groupBy"`
+	ChatterCount         int          `json:"chatterCount"`
+	ChatterWindowSeconds int          `json:"chatterWindowSeconds"`
+	Total                AlarmStats   `json:"total"`
+	Items                []AlarmStats `json:"items"`
+}
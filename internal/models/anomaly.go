@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ================ TELEMETRY ANOMALY MODELS ================
+
+// CellBaseline - скользящая базовая линия показаний ячейки (EWMA среднего и дисперсии
+// по минутным агрегатам). Отклонение от нее в стандартных отклонениях (z-score)
+// выявляет необычный ток или нагрев еще до срабатывания абсолютных уставок.
+type CellBaseline struct {
+	CellID    int               `json:"cellId" gorm:"primaryKey"`
+	Metric    MeasurementMetric `json:"metric" gorm:"primaryKey"`
+	RuID      string            `json:"ruId" gorm:"index"`
+	Mean      float64           `json:"mean"`
+	Variance  float64           `json:"-"`
+	StdDev    float64           `json:"stdDev" gorm:"-"`
+	Samples   int64             `json:"samples"`
+	LastValue float64           `json:"lastValue"`
+	LastScore float64           `json:"lastScore"`
+	LastAt    time.Time         `json:"lastAt"`
+	// AnomalySince - начало текущего эпизода аномалии; nil - показания в норме
+	AnomalySince *time.Time `json:"anomalySince,omitempty"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+func (CellBaseline) TableName() string {
+	return "cell_baselines"
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ ASSET MODELS ================
+
+type AssetType string
+
+const (
+	AssetTransformer  AssetType = "transformer"
+	AssetBreaker      AssetType = "breaker"
+	AssetDisconnector AssetType = "disconnector"
+	AssetCurrentTrafo AssetType = "current_transformer"
+	AssetVoltageTrafo AssetType = "voltage_transformer"
+	AssetRelay        AssetType = "relay"
+)
+
+// AssetState - стадия жизненного цикла оборудования
+type AssetState string
+
+const (
+	AssetInStock        AssetState = "in_stock"
+	AssetInstalled      AssetState = "installed"
+	AssetUnderRepair    AssetState = "under_repair"
+	AssetDecommissioned AssetState = "decommissioned"
+)
+
+type AssetEventType string
+
+const (
+	AssetEventRegistered   AssetEventType = "registered"
+	AssetEventInstalled    AssetEventType = "installed"
+	AssetEventRemoved      AssetEventType = "removed"
+	AssetEventStateChanged AssetEventType = "state_changed"
+	AssetEventUpdated      AssetEventType = "updated"
+)
+
+const (
+	IDPrefixAsset      = "asset"
+	IDPrefixAssetEvent = "assetev"
+)
+
+// Asset - единица оборудования (трансформатор, выключатель и т.д.), учитываемая
+// отдельно от ячейки: при замене или ремонте история следует за оборудованием
+type Asset struct {
+	ID           string     `json:"id" gorm:"primaryKey"`
+	SerialNumber string     `json:"serialNumber" gorm:"uniqueIndex"`
+	Type         AssetType  `json:"type" gorm:"index"`
+	Manufacturer string     `json:"manufacturer"`
+	Model        string     `json:"model"`
+	Year         *int       `json:"year,omitempty"`
+	State        AssetState `json:"state" gorm:"index"`
+	RuID         *string    `json:"ruId,omitempty" gorm:"index"`
+	CellID       *int       `json:"cellId,omitempty" gorm:"index"`
+	CellNumber   *string    `json:"cellNumber,omitempty"`
+	InstalledAt  *time.Time `json:"installedAt,omitempty"`
+	Notes        string     `json:"notes"`
+	// OrganizationID - владелец оборудования; на складе оборудование не привязано к РУ
+	OrganizationID string    `json:"organizationId" gorm:"index;not null;default:'default'"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Asset) TableName() string {
+	return "assets"
+}
+
+// AssetEvent - запись истории оборудования: установка, снятие, смена состояния
+type AssetEvent struct {
+	ID         string         `json:"id" gorm:"primaryKey"`
+	AssetID    string         `json:"assetId" gorm:"index"`
+	Type       AssetEventType `json:"type"`
+	FromState  AssetState     `json:"fromState,omitempty"`
+	ToState    AssetState     `json:"toState,omitempty"`
+	RuID       *string        `json:"ruId,omitempty" gorm:"index"`
+	CellID     *int           `json:"cellId,omitempty"`
+	CellNumber *string        `json:"cellNumber,omitempty"`
+	Comment    string         `json:"comment"`
+	By         string         `json:"by"`
+	At         time.Time      `json:"at" gorm:"index"`
+}
+
+func (AssetEvent) TableName() string {
+	return "asset_events"
+}
+
+// AssetFilter - критерии отбора оборудования
+type AssetFilter struct {
+	Type   AssetType  `form:"type"`
+	State  AssetState `form:"state" binding:"omitempty,oneof=in_stock installed under_repair decommissioned"`
+	RuID   string     `form:"ruId"`
+	CellID *int       `form:"cellId"`
+	Serial string     `form:"serial"`
+	// OrganizationID - только оборудование организации; задается сервисом по пользователю,
+	// пустая - оборудование всех организаций
+	OrganizationID string `form:"-"`
+}
+
+type CreateAssetRequest struct {
+	SerialNumber string    `json:"serialNumber" binding:"required,min=1,max=100"`
+	Type         AssetType `json:"type" binding:"required,oneof=transformer breaker disconnector current_transformer voltage_transformer relay"`
+	Manufacturer string    `json:"manufacturer" binding:"max=100"`
+	Model        string    `json:"model" binding:"max=100"`
+	Year         *int      `json:"year,omitempty" binding:"omitempty,min=1900,max=2100"`
+	Notes        string    `json:"notes" binding:"max=2000"`
+	// OrganizationID задает только администратор установки, иначе используется
+	// организация пользователя
+	OrganizationID string `json:"organizationId"`
+}
+
+// UpdateAssetRequest - изменение паспортных данных; nil-поля не меняются
+type UpdateAssetRequest struct {
+	SerialNumber *string `json:"serialNumber,omitempty" binding:"omitempty,min=1,max=100"`
+	Manufacturer *string `json:"manufacturer,omitempty" binding:"omitempty,max=100"`
+	Model        *string `json:"model,omitempty" binding:"omitempty,max=100"`
+	Year         *int    `json:"year,omitempty" binding:"omitempty,min=1900,max=2100"`
+	Notes        *string `json:"notes,omitempty" binding:"omitempty,max=2000"`
+}
+
+// MoveAssetRequest - установка оборудования в ячейку. Если в ячейке уже установлено
+// оборудование того же типа, оно снимается на склад только при Replace=true.
+type MoveAssetRequest struct {
+	RuID    string `json:"ruId" binding:"required"`
+	CellID  int    `json:"cellId" binding:"required"`
+	Replace bool   `json:"replace"`
+	Comment string `json:"comment" binding:"max=500"`
+}
+
+// ChangeAssetStateRequest - снятие в ремонт, на склад или списание
+type ChangeAssetStateRequest struct {
+	State   AssetState `json:"state" binding:"required,oneof=in_stock under_repair decommissioned"`
+	Comment string     `json:"comment" binding:"max=500"`
+}
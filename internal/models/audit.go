@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+const IDPrefixAudit = "aud"
+
+// Действия журнала аудита
+const (
+	// AuditActionRequest - изменяющий запрос к API
+	AuditActionRequest = "request"
+	// AuditActionImpersonationStart - администратор получил токен от имени пользователя
+	AuditActionImpersonationStart = "impersonation.start"
+	// AuditActionAlarmShelve - диспетчер отложил аварию
+	AuditActionAlarmShelve = "alarm.shelve"
+	// AuditActionAlarmUnshelve - авария вернулась в рабочий список вручную или по истечении срока
+	AuditActionAlarmUnshelve = "alarm.unshelve"
+	// AuditActionAlarmSuppress - авария подавлена открытым нарядом-допуском
+	AuditActionAlarmSuppress = "alarm.suppress"
+	// AuditActionAlarmUnsuppress - наряд-допуск закрыт, авария вернулась в рабочий список
+	AuditActionAlarmUnsuppress = "alarm.unsuppress"
+)
+
+// AuditEntry - запись журнала аудита: кто, от чьего имени и что изменил.
+// При работе от имени другого пользователя UserID - пользователь, за которого
+// выполнен запрос, ImpersonatorID - администратор, который его выполнил.
+type AuditEntry struct {
+	ID                string    `json:"id" gorm:"primaryKey"`
+	Action            string    `json:"action" gorm:"index"`
+	UserID            string    `json:"userId" gorm:"index"`
+	UserEmail         string    `json:"userEmail" mask:"personal_data:view"`
+	ImpersonatorID    *string   `json:"impersonatorId,omitempty" gorm:"index"`
+	ImpersonatorEmail *string   `json:"impersonatorEmail,omitempty" mask:"personal_data:view"`
+	Method            string    `json:"method,omitempty"`
+	Route             string    `json:"route,omitempty"`
+	Path              string    `json:"path,omitempty"`
+	Status            int       `json:"status,omitempty"`
+	Details           string    `json:"details,omitempty" gorm:"type:text"`
+	IP                string    `json:"ip,omitempty" mask:"personal_data:view"`
+	RequestID         string    `json:"requestId,omitempty"`
+	CreatedAt         time.Time `json:"createdAt" gorm:"index"`
+
+	ChainLink
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_entries"
+}
+
+// AuditFilter - параметры GET /admin/audit
+type AuditFilter struct {
+	UserID string     `form:"userId"`
+	Action string     `form:"action"`
+	Before *time.Time `form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit  int        `form:"limit"`
+}
+
+// ImpersonateRequest - причина работы от имени пользователя обязательна и попадает в аудит
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// ImpersonationResponse - короткоживущий токен от имени пользователя
+type ImpersonationResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expiresAt"`
+	User      UserResponse `json:"user"`
+	Banner    string       `json:"banner"`
+}
+
+// Impersonation - признак работы от имени другого пользователя для текущего токена
+type Impersonation struct {
+	ImpersonatorID    string `json:"impersonatorId"`
+	ImpersonatorEmail string `json:"impersonatorEmail"`
+	Banner            string `json:"banner"`
+}
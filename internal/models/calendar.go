@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ PRODUCTION CALENDAR MODELS ================
+
+type CalendarDayType string
+
+const (
+	// CalendarDayHoliday - праздничный (нерабочий) день
+	CalendarDayHoliday CalendarDayType = "holiday"
+	// CalendarDayShortened - предпраздничный сокращенный рабочий день
+	CalendarDayShortened CalendarDayType = "shortened"
+	// CalendarDayWorkday - рабочий день, перенесенный на выходной
+	CalendarDayWorkday CalendarDayType = "workday"
+)
+
+// CalendarDay - исключение из обычного графика (пн-пт рабочие, сб-вс выходные)
+type CalendarDay struct {
+	Date      string          `json:"date" gorm:"primaryKey"` // YYYY-MM-DD
+	Type      CalendarDayType `json:"type"`
+	Name      string          `json:"name"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (CalendarDay) TableName() string {
+	return "calendar_days"
+}
+
+// UpsertCalendarDayRequest - запрос на добавление или изменение дня календаря
+type UpsertCalendarDayRequest struct {
+	Type CalendarDayType `json:"type" binding:"required,oneof=holiday shortened workday"`
+	Name string          `json:"name" binding:"max=200"`
+}
+
+// CalendarEntryKind - источник записи календаря событий
+type CalendarEntryKind string
+
+const CalendarEntryPlannedOutage CalendarEntryKind = "planned_outage"
+
+const IDPrefixCalendarEntry = "cal"
+
+// CalendarEntryStatus - состояние записи; отмененные записи остаются в ленте iCal,
+// чтобы подписанные календари убрали событие
+type CalendarEntryStatus string
+
+const (
+	CalendarEntryConfirmed CalendarEntryStatus = "confirmed"
+	CalendarEntryCancelled CalendarEntryStatus = "cancelled"
+)
+
+// CalendarEntry - событие для ленты iCal (плановое отключение и т.п.). Sequence
+// увеличивается при каждом изменении, чтобы клиенты календаря обновили событие.
+type CalendarEntry struct {
+	ID             string              `json:"id" gorm:"primaryKey"`
+	Kind           CalendarEntryKind   `json:"kind" gorm:"uniqueIndex:idx_calendar_entries_ref,priority:1"`
+	RefID          string              `json:"refId" gorm:"uniqueIndex:idx_calendar_entries_ref,priority:2"`
+	RuID           string              `json:"ruId" gorm:"index"`
+	OrganizationID string              `json:"organizationId" gorm:"index;not null;default:'default'"`
+	Title          string              `json:"title"`
+	Description    string              `json:"description"`
+	Location       string              `json:"location"`
+	StartsAt       time.Time           `json:"startsAt" gorm:"index"`
+	EndsAt         time.Time           `json:"endsAt"`
+	Status         CalendarEntryStatus `json:"status"`
+	Sequence       int                 `json:"sequence"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+func (CalendarEntry) TableName() string {
+	return "calendar_entries"
+}
+
+// CalendarFeedToken - ключ подписки пользователя на ленту iCal. Клиенты календаря
+// (Outlook, Google Calendar) не передают заголовок Authorization, поэтому лента
+// открывается по ключу в URL; хранится только SHA-256 ключа.
+type CalendarFeedToken struct {
+	UserID     string     `json:"userId" gorm:"primaryKey"`
+	TokenHash  string     `json:"-" gorm:"uniqueIndex"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+func (CalendarFeedToken) TableName() string {
+	return "calendar_feed_tokens"
+}
+
+// CalendarFeedTokenResponse - новый ключ подписки; показывается один раз
+type CalendarFeedTokenResponse struct {
+	Token     string    `json:"token"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"createdAt"`
+}
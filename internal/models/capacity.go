@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// ================ CAPACITY UTILIZATION MODELS ================
+
+// UtilizationBand - полоса загрузки секции шин
+type UtilizationBand string
+
+const (
+	UtilizationNormal   UtilizationBand = "normal"
+	UtilizationWarning  UtilizationBand = "warning"
+	UtilizationCritical UtilizationBand = "critical"
+)
+
+// UtilizationBandRank - порядок полос по тяжести
+var UtilizationBandRank = map[UtilizationBand]int{
+	UtilizationNormal:   0,
+	UtilizationWarning:  1,
+	UtilizationCritical: 2,
+}
+
+// SectionUtilization - загрузка секции шин одной стороны РУ: ток питания секции
+// относительно допустимого тока шин. Пересчитывается задачей capacity-utilization.
+type SectionUtilization struct {
+	RuID         string          `json:"ruId" gorm:"primaryKey"`
+	VoltageLevel VoltageLevel    `json:"voltageLevel" gorm:"primaryKey"`
+	Section      int             `json:"section" gorm:"primaryKey"`
+	CurrentA     float64         `json:"currentA"`
+	CapacityA    float64         `json:"capacityA" mask:"capacity:view"`
+	HeadroomA    float64         `json:"headroomA" mask:"capacity:view"`
+	Percent      float64         `json:"percent"`
+	Band         UtilizationBand `json:"band"`
+	BandSince    time.Time       `json:"bandSince"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+func (SectionUtilization) TableName() string {
+	return "section_utilizations"
+}
+
+// RuUtilization - РУ в рейтинге загрузки: самая загруженная секция определяет место
+type RuUtilization struct {
+	RuID         string               `json:"ruId"`
+	RuName       string               `json:"ruName"`
+	SubstationID string               `json:"substationId"`
+	MaxPercent   float64              `json:"maxPercent"`
+	MinHeadroomA float64              `json:"minHeadroomA" mask:"capacity:view"`
+	Band         UtilizationBand      `json:"band"`
+	Sections     []SectionUtilization `json:"sections"`
+}
+
+// UtilizationQuery - порядок рейтинга: desc - сначала перегруженные, asc - сначала с запасом
+type UtilizationQuery struct {
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// SetCapacityRequest - допустимый ток шин сторон РУ в амперах
+type SetCapacityRequest struct {
+	MaxCapacityHighA *float64 `json:"maxCapacityHighA" binding:"omitempty,gt=0"`
+	MaxCapacityLowA  *float64 `json:"maxCapacityLowA" binding:"omitempty,gt=0"`
+}
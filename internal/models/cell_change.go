@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ CELL CHANGE APPROVAL MODELS ================
+
+type ChangeState string
+
+const (
+	ChangePending  ChangeState = "pending"
+	ChangeApproved ChangeState = "approved"
+	ChangeRejected ChangeState = "rejected"
+)
+
+const IDPrefixCellChange = "chg"
+
+// CellInfoChange - предложенное диспетчером изменение паспортных данных ячейки.
+// Применяется к ячейке только после одобрения инженером или администратором.
+type CellInfoChange struct {
+	ID     string `json:"id" gorm:"primaryKey"`
+	CellID int    `json:"cellId" gorm:"index"`
+	RuID   string `json:"ruId" gorm:"index"`
+
+	// Значения на момент запроса
+	PreviousName        string `json:"previousName"`
+	PreviousDescription string `json:"previousDescription"`
+	PreviousVoltage     string `json:"previousVoltage"`
+
+	// Предложенные значения
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Voltage     string `json:"voltage"`
+
+	State         ChangeState `json:"state" gorm:"index"`
+	RequestedBy   string      `json:"requestedBy"`
+	RequestedAt   time.Time   `json:"requestedAt"`
+	ReviewedBy    *string     `json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time  `json:"reviewedAt,omitempty"`
+	ReviewComment *string     `json:"reviewComment,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+
+	// Diff - измененные поля для отображения на клиенте
+	Diff []FieldDiff `json:"diff" gorm:"-"`
+}
+
+func (CellInfoChange) TableName() string {
+	return "cell_info_changes"
+}
+
+// FieldDiff - изменение одного поля
+type FieldDiff struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// BuildDiff - заполняет Diff по предыдущим и предложенным значениям
+func (c *CellInfoChange) BuildDiff() {
+	c.Diff = []FieldDiff{}
+	add := func(field, from, to string) {
+		if from != to {
+			c.Diff = append(c.Diff, FieldDiff{Field: field, From: from, To: to})
+		}
+	}
+	add("name", c.PreviousName, c.Name)
+	add("description", c.PreviousDescription, c.Description)
+	add("voltage", c.PreviousVoltage, c.Voltage)
+}
+
+// ReviewCellChangeRequest - решение по изменению
+type ReviewCellChangeRequest struct {
+	Comment *string `json:"comment,omitempty" binding:"omitempty,max=500"`
+}
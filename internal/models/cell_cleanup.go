@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ CELL CLEANUP MODELS ================
+
+// CellCleanupAction - что сделать с найденными ячейками
+type CellCleanupAction string
+
+const (
+	// CellCleanupMerge - ссылки дубликатов переносятся на оставляемую ячейку, дубликаты удаляются
+	CellCleanupMerge CellCleanupAction = "merge"
+	// CellCleanupDelete - удаляются только ячейки, на которые ничего не ссылается
+	CellCleanupDelete CellCleanupAction = "delete"
+)
+
+// CellCleanupOutcome - чем закончилась очистка для ячейки
+type CellCleanupOutcome string
+
+const (
+	CellCleanupKept    CellCleanupOutcome = "kept"
+	CellCleanupMerged  CellCleanupOutcome = "merged"
+	CellCleanupDeleted CellCleanupOutcome = "deleted"
+	CellCleanupSkipped CellCleanupOutcome = "skipped"
+)
+
+// Причины, по которым ячейка не очищена
+const (
+	CellSkipReferenced    = "referenced"
+	CellSkipActiveLock    = "active_lock"
+	CellSkipActiveCommand = "active_command"
+	CellSkipFailed        = "failed"
+)
+
+// CellCleanupQuery - проверка одного РУ (по умолчанию - всех)
+type CellCleanupQuery struct {
+	RuID string `form:"ruId"`
+}
+
+// CellCleanupRequest - очистка найденных ячеек. Пустое действие оставляет находки как
+// есть; для ячеек несуществующих РУ доступно только удаление. DryRun - только отчет о
+// том, что было бы сделано.
+type CellCleanupRequest struct {
+	RuID       string            `json:"ruId"`
+	Duplicates CellCleanupAction `json:"duplicates" binding:"omitempty,oneof=merge delete"`
+	Orphans    CellCleanupAction `json:"orphans" binding:"omitempty,oneof=delete"`
+	DryRun     bool              `json:"dryRun"`
+}
+
+// CellFinding - найденная ячейка; References - число ссылающихся строк по таблицам
+type CellFinding struct {
+	ID           int                `json:"id"`
+	RuID         string             `json:"ruId"`
+	Number       string             `json:"number"`
+	VoltageLevel VoltageLevel       `json:"voltageLevel"`
+	Status       CellStatus         `json:"status"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	References   map[string]int64   `json:"references"`
+	Outcome      CellCleanupOutcome `json:"outcome,omitempty"`
+	Reason       string             `json:"reason,omitempty"`
+}
+
+// DuplicateCellGroup - ячейки РУ с одинаковыми номером и уровнем напряжения. KeepID -
+// ячейка, которая остается: с наибольшим числом ссылок, при равенстве - самая старая.
+type DuplicateCellGroup struct {
+	RuID         string        `json:"ruId"`
+	Number       string        `json:"number"`
+	VoltageLevel VoltageLevel  `json:"voltageLevel"`
+	KeepID       int           `json:"keepId"`
+	Cells        []CellFinding `json:"cells"`
+}
+
+// CellCleanupReport - дубликаты и ячейки несуществующих РУ с итогом очистки
+type CellCleanupReport struct {
+	CheckedAt  time.Time            `json:"checkedAt"`
+	RuID       string               `json:"ruId,omitempty"`
+	DryRun     bool                 `json:"dryRun"`
+	Duplicates []DuplicateCellGroup `json:"duplicates"`
+	Orphans    []CellFinding        `json:"orphans"`
+	Merged     int                  `json:"merged"`
+	Deleted    int                  `json:"deleted"`
+	Skipped    int                  `json:"skipped"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// ================ CELL PAIR MODELS ================
+
+// SetCellPairRequest - ячейка противоположной стороны того же РУ, с которой
+// связывается ячейка из пути
+type SetCellPairRequest struct {
+	PairedCellID int `json:"pairedCellId" binding:"required"`
+}
+
+// CellPair - строка двусторонней схемы ТП: ячейка ВН и ячейка НН одного
+// присоединения. У ячейки без пары вторая сторона пустая.
+type CellPair struct {
+	High *Cell `json:"high"`
+	Low  *Cell `json:"low"`
+}
+
+// PairedCells - ячейки РУ попарно в порядке схемы
+type PairedCells struct {
+	RuID     string     `json:"ruId"`
+	Pairs    []CellPair `json:"pairs"`
+	Unpaired int        `json:"unpaired"`
+}
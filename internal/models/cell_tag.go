@@ -0,0 +1,15 @@
+package models
+
+// ================ CELL TAG (QR) MODELS ================
+
+// CellCard - карточка ячейки, открываемая по QR-коду наклейки на ячейке:
+// текущее состояние, последние операции и неустраненные дефекты
+type CellCard struct {
+	URL          string            `json:"url"`
+	RuID         string            `json:"ruId"`
+	RuName       string            `json:"ruName"`
+	SubstationID string            `json:"substationId"`
+	Cell         Cell              `json:"cell"`
+	History      []OperationRecord `json:"history"`
+	Defects      []Defect          `json:"defects"`
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ================ HASH CHAIN MODELS ================
+
+// Цепочки хешей журналов, защищенных от правки задним числом
+const (
+	ChainOperationRecords = "operation_records"
+	ChainAuditEntries     = "audit_entries"
+	ChainCorrections      = "operation_corrections"
+)
+
+// ChainGenesis - "хеш предыдущей записи" у первой записи цепочки
+var ChainGenesis = strings.Repeat("0", 64)
+
+// ChainLink - место записи в цепочке: номер, хеш предыдущей записи и собственный хеш.
+// Записи, созданные до включения цепочки, номера не имеют и не проверяются.
+type ChainLink struct {
+	ChainSeq *int64 `json:"chainSeq,omitempty" gorm:"uniqueIndex"`
+	PrevHash string `json:"prevHash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+}
+
+// Chained - запись журнала, входящая в цепочку хешей
+type Chained interface {
+	ChainID() string
+	Link() *ChainLink
+	// ChainHash - хеш записи по ее содержимому, номеру и хешу предыдущей записи
+	ChainHash() string
+}
+
+// HashChain - голова цепочки: номер и хеш последней записи. Строка блокируется на время
+// добавления записи, поэтому записи цепочки нумеруются строго последовательно.
+type HashChain struct {
+	Name      string    `json:"name" gorm:"primaryKey"`
+	Seq       int64     `json:"seq"`
+	LastHash  string    `json:"lastHash"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (HashChain) TableName() string {
+	return "hash_chains"
+}
+
+// ChainTime - время в хешируемом содержимом. PostgreSQL хранит микросекунды, поэтому
+// время записи усекается до них еще до вставки.
+func ChainTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// chainHash - SHA-256 от хеша предыдущей записи, номера и JSON содержимого
+func chainHash(link ChainLink, content interface{}) string {
+	data, err := json.Marshal(content)
+	if err != nil {
+		panic(fmt.Sprintf("chain content is not serializable: %v", err))
+	}
+	seq := ""
+	if link.ChainSeq != nil {
+		seq = strconv.FormatInt(*link.ChainSeq, 10)
+	}
+	sum := sha256.New()
+	sum.Write([]byte(link.PrevHash + "\n" + seq + "\n"))
+	sum.Write(data)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// ChainBreakKind - вид нарушения цепочки
+type ChainBreakKind string
+
+const (
+	// ChainBreakModified - содержимое записи не соответствует ее хешу
+	ChainBreakModified ChainBreakKind = "modified"
+	// ChainBreakRelinked - хеш предыдущей записи не совпадает с фактическим
+	ChainBreakRelinked ChainBreakKind = "relinked"
+	// ChainBreakMissing - пропуск в нумерации: записи удалены
+	ChainBreakMissing ChainBreakKind = "missing"
+	// ChainBreakTruncated - последних записей цепочки нет в таблице
+	ChainBreakTruncated ChainBreakKind = "truncated"
+)
+
+// ChainBreak - найденное нарушение цепочки
+type ChainBreak struct {
+	Kind     ChainBreakKind `json:"kind"`
+	Seq      int64          `json:"seq"`
+	RecordID string         `json:"recordId,omitempty"`
+	Expected string         `json:"expected,omitempty"`
+	Actual   string         `json:"actual,omitempty"`
+}
+
+// ChainVerification - результат проверки цепочки журнала
+type ChainVerification struct {
+	Chain    string `json:"chain"`
+	Valid    bool   `json:"valid"`
+	Verified int64  `json:"verified"`
+	// Unchained - записи, созданные до включения цепочки
+	Unchained  int64        `json:"unchained"`
+	HeadSeq    int64        `json:"headSeq"`
+	HeadHash   string       `json:"headHash"`
+	Breaks     []ChainBreak `json:"breaks"`
+	VerifiedAt time.Time    `json:"verifiedAt"`
+}
+
+// VerifyChainRequest - параметры GET /admin/integrity/verify
+type VerifyChainRequest struct {
+	Chain string `form:"chain" binding:"required,oneof=operation_records audit_entries operation_corrections"`
+}
+
+func (r *OperationRecord) ChainID() string  { return r.ID }
+func (r *OperationRecord) Link() *ChainLink { return &r.ChainLink }
+
+// ChainHash - в хеш входит содержимое записи, как его ввел оператор. Организация
+// (переносится вместе с подстанцией), типизированные даты (выводятся из строковых)
+// и сведения о часах устройства не входят.
+func (r *OperationRecord) ChainHash() string {
+	return chainHash(r.ChainLink, struct {
+		ID                string
+		RuID              string
+		CellNumber        string
+		CellName          string
+		Action            string
+		Operator          string
+		Timestamp         string
+		Reason            *string
+		DocumentType      *string
+		OrderNumber       *string
+		WorkOrderNumber   *string
+		StartDate         *string
+		EndDate           *string
+		ResponsiblePerson *string
+		Comment           *string
+		Severity          *RecordSeverity
+		CreatedAt         string
+	}{
+		r.ID, r.RuID, r.CellNumber, r.CellName, r.Action, r.Operator, r.Timestamp, r.Reason,
+		r.DocumentType, r.OrderNumber, r.WorkOrderNumber, r.StartDate, r.EndDate,
+		r.ResponsiblePerson, r.Comment, r.Severity, ChainTime(r.CreatedAt),
+	})
+}
+
+func (c *OperationCorrection) ChainID() string  { return c.ID }
+func (c *OperationCorrection) Link() *ChainLink { return &c.ChainLink }
+
+// ChainHash - исправленные поля входят в хеш исправления в порядке их сохранения
+func (c *OperationCorrection) ChainHash() string {
+	type field struct {
+		Field string
+		From  *string
+		To    *string
+	}
+	fields := make([]field, len(c.Fields))
+	for i, f := range c.Fields {
+		fields[i] = field{f.Field, f.From, f.To}
+	}
+	return chainHash(c.ChainLink, struct {
+		ID          string
+		RecordID    string
+		RuID        string
+		Reason      string
+		CorrectedBy string
+		Fields      []field
+		CreatedAt   string
+	}{c.ID, c.RecordID, c.RuID, c.Reason, c.CorrectedBy, fields, ChainTime(c.CreatedAt)})
+}
+
+func (e *AuditEntry) ChainID() string  { return e.ID }
+func (e *AuditEntry) Link() *ChainLink { return &e.ChainLink }
+
+func (e *AuditEntry) ChainHash() string {
+	return chainHash(e.ChainLink, struct {
+		ID                string
+		Action            string
+		UserID            string
+		UserEmail         string
+		ImpersonatorID    *string
+		ImpersonatorEmail *string
+		Method            string
+		Route             string
+		Path              string
+		Status            int
+		Details           string
+		IP                string
+		RequestID         string
+		CreatedAt         string
+	}{
+		e.ID, e.Action, e.UserID, e.UserEmail, e.ImpersonatorID, e.ImpersonatorEmail, e.Method,
+		e.Route, e.Path, e.Status, e.Details, e.IP, e.RequestID, ChainTime(e.CreatedAt),
+	})
+}
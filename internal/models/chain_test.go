@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOperationRecordChainHash(t *testing.T) {
+	seq := int64(7)
+	reason := "plan"
+	base := func() OperationRecord {
+		return OperationRecord{
+			ID:         "rec-1",
+			RuID:       "ru-1",
+			CellNumber: "5",
+			Action:     "on",
+			Operator:   "dispatcher",
+			Timestamp:  "01.03.2025 10:00",
+			Reason:     &reason,
+			CreatedAt:  time.Date(2025, 3, 1, 10, 0, 0, 123456000, time.UTC),
+			ChainLink:  ChainLink{ChainSeq: &seq, PrevHash: ChainGenesis},
+		}
+	}
+	baseHash := func() string { r := base(); return r.ChainHash() }()
+
+	otherSeq := int64(8)
+	otherReason := "emergency"
+	userID := "user-1"
+	tests := []struct {
+		name    string
+		mutate  func(r *OperationRecord)
+		changes bool
+	}{
+		{name: "same content", mutate: func(r *OperationRecord) {}, changes: false},
+		{name: "action", mutate: func(r *OperationRecord) { r.Action = "off" }, changes: true},
+		{name: "operator", mutate: func(r *OperationRecord) { r.Operator = "someone" }, changes: true},
+		{name: "reason", mutate: func(r *OperationRecord) { r.Reason = &otherReason }, changes: true},
+		{name: "reason removed", mutate: func(r *OperationRecord) { r.Reason = nil }, changes: true},
+		{name: "created at", mutate: func(r *OperationRecord) { r.CreatedAt = r.CreatedAt.Add(time.Microsecond) }, changes: true},
+		{name: "sequence", mutate: func(r *OperationRecord) { r.ChainSeq = &otherSeq }, changes: true},
+		{name: "previous hash", mutate: func(r *OperationRecord) { r.PrevHash = baseHash }, changes: true},
+		{name: "created at in other zone", mutate: func(r *OperationRecord) { r.CreatedAt = r.CreatedAt.In(time.FixedZone("UTC+5", 5*3600)) }, changes: false},
+		// Служебные поля вне хеша
+		{name: "organization", mutate: func(r *OperationRecord) { r.OrganizationID = "org-2" }, changes: false},
+		{name: "user", mutate: func(r *OperationRecord) { r.UserID = &userID }, changes: false},
+		{name: "own hash", mutate: func(r *OperationRecord) { r.Hash = "x" }, changes: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := base()
+			tt.mutate(&r)
+			if changed := r.ChainHash() != baseHash; changed != tt.changes {
+				t.Errorf("hash changed = %v, want %v", changed, tt.changes)
+			}
+		})
+	}
+}
+
+func TestCorrectionChainHashFieldOrder(t *testing.T) {
+	from, to := "a", "b"
+	fields := []CorrectedField{{Field: "reason", From: &from, To: &to}, {Field: "comment", To: &to}}
+	c1 := OperationCorrection{ID: "cor-1", RecordID: "rec-1", Fields: fields}
+	c2 := OperationCorrection{ID: "cor-1", RecordID: "rec-1", Fields: []CorrectedField{fields[1], fields[0]}}
+	if c1.ChainHash() == c2.ChainHash() {
+		t.Error("field order must be part of the correction hash")
+	}
+	if len(c1.ChainHash()) != len(ChainGenesis) {
+		t.Errorf("hash length = %d, want %d", len(c1.ChainHash()), len(ChainGenesis))
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ================ DEVICE CLOCK SKEW MODELS ================
+
+// ClockSkewQuery - период отчета о часах устройств, дней (по умолчанию 7)
+type ClockSkewQuery struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+// DeviceClockSkew - расхождение часов устройства с сервером по записям журнала, мс.
+// Drifted - среднее расхождение больше порога history.clock_skew_alert.
+type DeviceClockSkew struct {
+	Device    string `json:"device"`
+	Records   int    `json:"records"`
+	AvgSkewMs int64  `json:"avgSkewMs"`
+	MinSkewMs int64  `json:"minSkewMs"`
+	MaxSkewMs int64  `json:"maxSkewMs"`
+	Drifted   bool   `json:"drifted"`
+}
+
+// ClockSkewReport - устройства, приславшие время по своим часам, начиная с Since
+type ClockSkewReport struct {
+	Since   time.Time         `json:"since"`
+	AlertMs int64             `json:"alertMs"`
+	Devices []DeviceClockSkew `json:"devices"`
+}
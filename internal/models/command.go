@@ -0,0 +1,118 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ CONTROL COMMAND MODELS ================
+
+// CommandAction - телеуправление коммутационным аппаратом ячейки
+type CommandAction string
+
+const (
+	CommandClose CommandAction = "close"
+	CommandOpen  CommandAction = "open"
+)
+
+// TargetStatus - статус ячейки, который должен прийти обратно после выполнения команды
+func (a CommandAction) TargetStatus() CellStatus {
+	if a == CommandClose {
+		return CellStatusON
+	}
+	return CellStatusOFF
+}
+
+// CommandState - этап выбора-подтверждения-исполнения (select-before-operate)
+type CommandState string
+
+const (
+	// CommandSelecting - выбор отправлен в RTU, ждем подтверждения выбора
+	CommandSelecting CommandState = "selecting"
+	// CommandSelected - RTU подтвердило выбор, ждем подтверждения оператора
+	CommandSelected CommandState = "selected"
+	// CommandOperating - исполнение отправлено в RTU, ждем квитанции
+	CommandOperating CommandState = "operating"
+	// CommandExecuted - RTU исполнило команду, ждем изменения статуса ячейки
+	CommandExecuted  CommandState = "executed"
+	CommandCompleted CommandState = "completed"
+	CommandFailed    CommandState = "failed"
+	CommandCancelled CommandState = "cancelled"
+	CommandExpired   CommandState = "expired"
+)
+
+// Final - команда завершена и больше не меняется
+func (s CommandState) Final() bool {
+	return s == CommandCompleted || s == CommandFailed || s == CommandCancelled || s == CommandExpired
+}
+
+const IDPrefixCommand = "cmd"
+
+// ControlCommand - команда телеуправления ячейкой через RTU. Выполняется шлюзом телеметрии,
+// который получает события control.* и присылает квитанции этапов; результат проверяется
+// по фактическому изменению статуса ячейки. Deadline - срок текущего этапа.
+// CompletedAt заполняется в конечном состоянии; пока он пуст, ячейка занята командой.
+type ControlCommand struct {
+	ID           string        `json:"id" gorm:"primaryKey"`
+	RuID         string        `json:"ruId" gorm:"index"`
+	CellID       int           `json:"cellId" gorm:"uniqueIndex:idx_control_commands_active_cell,where:completed_at IS NULL;index"`
+	CellNumber   string        `json:"cellNumber"`
+	DeviceID     string        `json:"deviceId"`
+	Action       CommandAction `json:"action"`
+	TargetStatus CellStatus    `json:"targetStatus"`
+	Reason       string        `json:"reason"`
+	State        CommandState  `json:"state" gorm:"index"`
+	Deadline     *time.Time    `json:"deadline,omitempty" gorm:"index"`
+	SelectedBy   string        `json:"selectedBy"`
+	OperatedBy   *string       `json:"operatedBy,omitempty"`
+	Error        *string       `json:"error,omitempty"`
+	CompletedAt  *time.Time    `json:"completedAt,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+
+	Steps []ControlCommandStep `json:"steps,omitempty" gorm:"foreignKey:CommandID;constraint:OnDelete:CASCADE"`
+}
+
+func (ControlCommand) TableName() string {
+	return "control_commands"
+}
+
+// ControlCommandStep - запись аудита команды: кто и когда перевел ее в состояние
+type ControlCommandStep struct {
+	ID        uint         `json:"-" gorm:"primaryKey;autoIncrement"`
+	CommandID string       `json:"-" gorm:"index"`
+	State     CommandState `json:"state"`
+	By        string       `json:"by"`
+	Details   string       `json:"details,omitempty"`
+	At        time.Time    `json:"at"`
+}
+
+func (ControlCommandStep) TableName() string {
+	return "control_command_steps"
+}
+
+// SelectCommandRequest - выбор аппарата ячейки для телеуправления
+type SelectCommandRequest struct {
+	Action CommandAction `json:"action" binding:"required,oneof=close open"`
+	Reason string        `json:"reason" binding:"required,min=3,max=500"`
+}
+
+// CommandStage - этап, по которому шлюз присылает квитанцию
+type CommandStage string
+
+const (
+	StageSelect  CommandStage = "select"
+	StageOperate CommandStage = "operate"
+)
+
+// CommandAckRequest - квитанция шлюза телеметрии (ACT_CON / отказ RTU)
+type CommandAckRequest struct {
+	Stage   CommandStage `json:"stage" binding:"required,oneof=select operate"`
+	Success *bool        `json:"success" binding:"required"`
+	Error   string       `json:"error,omitempty" binding:"max=500"`
+}
+
+type CommandFilter struct {
+	CellID *int         `form:"cellId"`
+	State  CommandState `form:"state"`
+	Limit  int          `form:"limit"`
+}
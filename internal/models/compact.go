@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// ================ COMPACT VIEW MODELS ================
+
+// Проекции ответов для планшетов обходчиков (?view=compact): только то, что нужно
+// на объекте, - идентификация, состояние и ключевые измерения ячеек.
+
+// CompactCell - ячейка в сокращенном виде
+type CompactCell struct {
+	ID          int        `json:"id"`
+	Number      string     `json:"number"`
+	Name        string     `json:"name"`
+	Status      CellStatus `json:"status"`
+	IsGrounded  bool       `json:"isGrounded,omitempty"`
+	Current     *float64   `json:"current,omitempty"`
+	Temperature *float64   `json:"temperature,omitempty"`
+	Load        *float64   `json:"load,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func NewCompactCell(cell Cell) CompactCell {
+	return CompactCell{
+		ID:          cell.ID,
+		Number:      cell.Number,
+		Name:        cell.Name,
+		Status:      cell.Status,
+		IsGrounded:  cell.IsGrounded,
+		Current:     cell.Current,
+		Temperature: cell.Temperature,
+		Load:        cell.Load,
+		UpdatedAt:   cell.UpdatedAt,
+	}
+}
+
+// CompactRU - РУ в списке в сокращенном виде
+type CompactRU struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Status RuStatus `json:"status"`
+	Type   RUType   `json:"type"`
+	Stats  *RUStats `json:"stats,omitempty"`
+}
+
+func NewCompactRU(summary RUSummary) CompactRU {
+	return CompactRU{
+		ID:     summary.ID,
+		Name:   summary.Name,
+		Status: summary.Status,
+		Type:   summary.Type,
+		Stats:  summary.Stats,
+	}
+}
+
+// CompactRuResponse - РУ с ячейками в сокращенном виде
+type CompactRuResponse struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Status  RuStatus      `json:"status"`
+	Cells   []CompactCell `json:"cells"`
+	Polling *PollingPause `json:"polling,omitempty"`
+}
+
+func NewCompactRuResponse(response *GetRuResponse) CompactRuResponse {
+	cells := make([]CompactCell, len(response.Cells))
+	for i, cell := range response.Cells {
+		cells[i] = NewCompactCell(cell)
+	}
+	return CompactRuResponse{
+		ID:      response.RuInfo.ID,
+		Name:    response.RuInfo.Name,
+		Status:  response.RuInfo.Status,
+		Cells:   cells,
+		Polling: response.Polling,
+	}
+}
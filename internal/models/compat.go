@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ COMPATIBILITY MODELS ================
+
+// DateFormatUsage - сколько раз клиент прислал поле даты в старом строковом и в новом
+// формате. Счетчики накапливаются по всем экземплярам и переживают рестарт, чтобы по
+// ним можно было решить, когда отключать старый формат.
+type DateFormatUsage struct {
+	Client   string    `json:"client" gorm:"primaryKey"`
+	Field    string    `json:"field" gorm:"primaryKey"`
+	Legacy   int64     `json:"legacy"`
+	Modern   int64     `json:"modern"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+func (DateFormatUsage) TableName() string {
+	return "date_format_usages"
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ OSCILLOGRAPHY (COMTRADE) MODELS ================
+
+const IDPrefixComtrade = "osc"
+
+// ComtradeFileKind - файл записи COMTRADE
+type ComtradeFileKind string
+
+const (
+	ComtradeCFG ComtradeFileKind = "cfg"
+	ComtradeDAT ComtradeFileKind = "dat"
+	ComtradeHDR ComtradeFileKind = "hdr"
+	ComtradeINF ComtradeFileKind = "inf"
+	ComtradeCFF ComtradeFileKind = "cff"
+)
+
+// ComtradeRecord - осциллограмма аварийного процесса, прикрепленная к отключению.
+// Метаданные извлекаются из файла конфигурации при загрузке.
+type ComtradeRecord struct {
+	ID              string    `json:"id" gorm:"primaryKey"`
+	RuID            string    `json:"ruId" gorm:"index"`
+	FaultID         string    `json:"faultId" gorm:"index"`
+	StationName     string    `json:"stationName"`
+	DeviceID        string    `json:"deviceId"`
+	RevisionYear    int       `json:"revisionYear"`
+	AnalogChannels  int       `json:"analogChannels"`
+	DigitalChannels int       `json:"digitalChannels"`
+	LineFrequency   float64   `json:"lineFrequency"`
+	SamplingRate    float64   `json:"samplingRate"`
+	SampleCount     int64     `json:"sampleCount"`
+	DataFormat      string    `json:"dataFormat"`
+	StartTime       time.Time `json:"startTime"`
+	TriggerTime     time.Time `json:"triggerTime" gorm:"index"`
+	UploadedBy      string    `json:"uploadedBy"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	Channels []ComtradeChannel `json:"channels,omitempty" gorm:"foreignKey:RecordID;constraint:OnDelete:CASCADE"`
+	Files    []ComtradeFile    `json:"files,omitempty" gorm:"foreignKey:RecordID;constraint:OnDelete:CASCADE"`
+}
+
+func (ComtradeRecord) TableName() string {
+	return "comtrade_records"
+}
+
+type ComtradeChannelType string
+
+const (
+	ComtradeAnalog  ComtradeChannelType = "analog"
+	ComtradeDigital ComtradeChannelType = "digital"
+)
+
+// ComtradeChannel - канал осциллограммы
+type ComtradeChannel struct {
+	ID       uint                `json:"-" gorm:"primaryKey;autoIncrement"`
+	RecordID string              `json:"-" gorm:"index"`
+	Type     ComtradeChannelType `json:"type"`
+	Index    int                 `json:"index" gorm:"column:channel_index"`
+	Name     string              `json:"name"`
+	Phase    string              `json:"phase,omitempty"`
+	Unit     string              `json:"unit,omitempty"`
+}
+
+func (ComtradeChannel) TableName() string {
+	return "comtrade_channels"
+}
+
+// ComtradeFile - исходный файл записи; содержимое отдается отдельным запросом
+type ComtradeFile struct {
+	RecordID string           `json:"-" gorm:"primaryKey"`
+	Kind     ComtradeFileKind `json:"kind" gorm:"primaryKey"`
+	FileName string           `json:"fileName"`
+	Size     int64            `json:"size"`
+	Data     []byte           `json:"-"`
+}
+
+func (ComtradeFile) TableName() string {
+	return "comtrade_files"
+}
+
+// ComtradeFilter - отбор каталога осциллограмм РУ
+type ComtradeFilter struct {
+	FaultID string     `form:"faultId"`
+	From    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ TWO-PERSON CONFIRMATION MODELS ================
+
+type ConfirmationState string
+
+const (
+	ConfirmationPending   ConfirmationState = "pending"
+	ConfirmationConfirmed ConfirmationState = "confirmed"
+	ConfirmationCancelled ConfirmationState = "cancelled"
+)
+
+const IDPrefixStatusConfirmation = "confirm"
+
+// StatusConfirmation - запрошенное диспетчером переключение критичной ячейки,
+// ожидающее подтверждения вторым сотрудником
+type StatusConfirmation struct {
+	ID          string            `json:"id" gorm:"primaryKey"`
+	CellID      int               `json:"cellId" gorm:"index"`
+	RuID        string            `json:"ruId" gorm:"index"`
+	Status      CellStatus        `json:"status"`
+	IsGrounded  *bool             `json:"isGrounded,omitempty"`
+	State       ConfirmationState `json:"state" gorm:"index"`
+	RequestedBy string            `json:"requestedBy"`
+	RequestedAt time.Time         `json:"requestedAt"`
+	ExpiresAt   time.Time         `json:"expiresAt"`
+	ConfirmedBy *string           `json:"confirmedBy,omitempty"`
+	ConfirmedAt *time.Time        `json:"confirmedAt,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func (StatusConfirmation) TableName() string {
+	return "status_confirmations"
+}
+
+// Expired - истек ли срок подтверждения
+func (c StatusConfirmation) Expired(at time.Time) bool {
+	return !at.Before(c.ExpiresAt)
+}
+
+// SetCellCriticalRequest - запрос на изменение признака критичной ячейки
+type SetCellCriticalRequest struct {
+	IsCritical *bool `json:"isCritical" binding:"required"`
+}
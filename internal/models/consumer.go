@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// ================ CONSUMER MODELS ================
+
+const IDPrefixConsumer = "cons"
+
+// Consumer - потребитель (резидент ОЭЗ) с договором электроснабжения; питается
+// от одной или нескольких отходящих ячеек
+type Consumer struct {
+	ID                string           `json:"id" gorm:"primaryKey"`
+	OrganizationID    string           `json:"organizationId" gorm:"index;not null;default:'default'"`
+	Company           string           `json:"company"`
+	ContractNumber    string           `json:"contractNumber" gorm:"uniqueIndex"`
+	ContractedPowerKW *float64         `json:"contractedPowerKW,omitempty" gorm:"column:contracted_power_kw" mask:"capacity:view"`
+	ContactName       string           `json:"contactName" mask:"personal_data:view"`
+	ContactPhone      string           `json:"contactPhone" mask:"personal_data:view"`
+	ContactEmail      string           `json:"contactEmail" mask:"personal_data:view"`
+	Notes             string           `json:"notes"`
+	Feeders           []ConsumerFeeder `json:"feeders" gorm:"foreignKey:ConsumerID"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+func (Consumer) TableName() string {
+	return "consumers"
+}
+
+// ConsumerFeeder - отходящая ячейка, от которой питается потребитель
+type ConsumerFeeder struct {
+	ConsumerID string    `json:"consumerId" gorm:"primaryKey"`
+	CellID     int       `json:"cellId" gorm:"primaryKey;index"`
+	RuID       string    `json:"ruId" gorm:"index"`
+	CellNumber string    `json:"cellNumber"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (ConsumerFeeder) TableName() string {
+	return "consumer_feeders"
+}
+
+// ConsumerFilter - критерии отбора потребителей
+type ConsumerFilter struct {
+	RuID   string `form:"ruId"`
+	CellID *int   `form:"cellId"`
+	Search string `form:"q"`
+}
+
+// ConsumerRequest - создание и изменение потребителя. OrganizationID задает только
+// администратор установки, иначе используется организация пользователя.
+type ConsumerRequest struct {
+	Company           string   `json:"company" binding:"required,min=1,max=200"`
+	ContractNumber    string   `json:"contractNumber" binding:"required,min=1,max=100"`
+	ContractedPowerKW *float64 `json:"contractedPowerKW" binding:"omitempty,gte=0"`
+	ContactName       string   `json:"contactName" binding:"max=200"`
+	ContactPhone      string   `json:"contactPhone" binding:"max=50"`
+	ContactEmail      string   `json:"contactEmail" binding:"omitempty,email,max=200"`
+	Notes             string   `json:"notes" binding:"max=2000"`
+	OrganizationID    string   `json:"organizationId"`
+}
+
+// AssignFeederRequest - подключение потребителя к отходящей ячейке
+type AssignFeederRequest struct {
+	RuID   string `json:"ruId" binding:"required"`
+	CellID int    `json:"cellId" binding:"required"`
+}
+
+// ConsumerRef - потребитель в составе других ответов (анализ отключений, учет энергии)
+type ConsumerRef struct {
+	ID                string   `json:"id"`
+	Company           string   `json:"company"`
+	ContractNumber    string   `json:"contractNumber"`
+	ContractedPowerKW *float64 `json:"contractedPowerKW,omitempty" mask:"capacity:view"`
+}
+
+// ImpactedFeeder - отходящая ячейка, теряющая питание при отключении
+type ImpactedFeeder struct {
+	CellID     int           `json:"cellId"`
+	CellNumber string        `json:"cellNumber"`
+	CellName   string        `json:"cellName"`
+	Consumers  []ConsumerRef `json:"consumers"`
+}
+
+// OutageImpact - последствия отключения ячеек РУ: обесточиваемые отходящие ячейки,
+// потребители и их суммарная договорная мощность
+type OutageImpact struct {
+	RuID              string           `json:"ruId"`
+	CellIDs           []int            `json:"cellIds"`
+	Feeders           []ImpactedFeeder `json:"feeders"`
+	Consumers         []ConsumerRef    `json:"consumers"`
+	ContractedPowerKW float64          `json:"contractedPowerKW" mask:"capacity:view"`
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ HISTORY CORRECTION MODELS ================
+
+const IDPrefixCorrection = "corr"
+
+// OperationCorrection - исправление ошибочной записи журнала операций. Как в бумажном
+// журнале, исходная запись не переписывается: исправление добавляется отдельной записью
+// со ссылкой на нее, с причиной и тем, кто исправил. Исправления входят в свою цепочку
+// хешей и тоже не изменяются и не удаляются.
+type OperationCorrection struct {
+	ID          string           `json:"id" gorm:"primaryKey"`
+	RecordID    string           `json:"recordId" gorm:"index"`
+	RuID        string           `json:"ruId" gorm:"index"`
+	Reason      string           `json:"reason"`
+	CorrectedBy string           `json:"correctedBy"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Fields      []CorrectedField `json:"fields" gorm:"foreignKey:CorrectionID"`
+
+	ChainLink
+}
+
+func (OperationCorrection) TableName() string {
+	return "operation_corrections"
+}
+
+// CorrectedField - исправленное поле записи: значение до исправления (с учетом
+// предыдущих исправлений) и новое. nil - поле не заполнено.
+type CorrectedField struct {
+	ID           int64   `json:"-" gorm:"primaryKey;autoIncrement"`
+	CorrectionID string  `json:"-" gorm:"index"`
+	Field        string  `json:"field"`
+	From         *string `json:"from" gorm:"column:from_value"`
+	To           *string `json:"to" gorm:"column:to_value"`
+}
+
+func (CorrectedField) TableName() string {
+	return "operation_correction_fields"
+}
+
+// CorrectHistoryRecordRequest - исправление записи журнала. Fields - новые значения по
+// именам полей записи (cellNumber, action, reason, ...); null очищает необязательное поле.
+type CorrectHistoryRecordRequest struct {
+	Reason string             `json:"reason" binding:"required,min=3,max=1000"`
+	Fields map[string]*string `json:"fields" binding:"required,min=1,max=20"`
+}
+
+// CorrectableFields - поля записи журнала, которые можно исправить. Обязательные
+// поля нельзя очистить.
+var CorrectableFields = map[string]bool{
+	"cellNumber":        true,
+	"cellName":          false,
+	"action":            true,
+	"operator":          true,
+	"timestamp":         true,
+	"reason":            false,
+	"documentType":      false,
+	"orderNumber":       false,
+	"workOrderNumber":   false,
+	"startDate":         false,
+	"endDate":           false,
+	"responsiblePerson": false,
+	"comment":           false,
+	"severity":          true,
+}
+
+// FieldValue - значение поля записи по его имени в JSON, как его ввел оператор
+func (r *OperationRecord) FieldValue(field string) *string {
+	text := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+		return &value
+	}
+	switch field {
+	case "cellNumber":
+		return text(r.CellNumber)
+	case "cellName":
+		return text(r.CellName)
+	case "action":
+		return text(r.Action)
+	case "operator":
+		return text(r.Operator)
+	case "timestamp":
+		return text(r.Timestamp)
+	case "reason":
+		return r.Reason
+	case "documentType":
+		return r.DocumentType
+	case "orderNumber":
+		return r.OrderNumber
+	case "workOrderNumber":
+		return r.WorkOrderNumber
+	case "startDate":
+		return r.StartDate
+	case "endDate":
+		return r.EndDate
+	case "responsiblePerson":
+		return r.ResponsiblePerson
+	case "comment":
+		return r.Comment
+	case "severity":
+		return (*string)(r.Severity)
+	}
+	return nil
+}
+
+// ApplyCorrections - прикладывает исправления (в порядке создания) к записи: исходные
+// поля не меняются, в Corrected собираются значения с учетом всех исправлений
+func (r *OperationRecord) ApplyCorrections(corrections []OperationCorrection) {
+	r.Corrections = corrections
+	if len(corrections) == 0 {
+		r.Corrected = nil
+		return
+	}
+	r.Corrected = map[string]*string{}
+	for _, correction := range corrections {
+		for _, field := range correction.Fields {
+			r.Corrected[field.Field] = field.To
+		}
+	}
+}
+
+// CurrentValue - значение поля с учетом приложенных исправлений
+func (r *OperationRecord) CurrentValue(field string) *string {
+	if value, ok := r.Corrected[field]; ok {
+		return value
+	}
+	return r.FieldValue(field)
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ DEFECT MODELS ================
+
+type DefectSeverity string
+
+const (
+	DefectSeverityCritical DefectSeverity = "critical"
+	DefectSeverityMajor    DefectSeverity = "major"
+	DefectSeverityMinor    DefectSeverity = "minor"
+)
+
+type DefectStatus string
+
+const (
+	DefectStatusOpen   DefectStatus = "open"
+	DefectStatusInWork DefectStatus = "in_work"
+	DefectStatusFixed  DefectStatus = "fixed"
+)
+
+// DefectTransitions - допустимые переходы статуса дефекта
+var DefectTransitions = map[DefectStatus][]DefectStatus{
+	DefectStatusOpen:   {DefectStatusInWork},
+	DefectStatusInWork: {DefectStatusFixed, DefectStatusOpen},
+	DefectStatusFixed:  {DefectStatusOpen},
+}
+
+const IDPrefixDefect = "defect"
+
+// Defect - дефект оборудования РУ или ячейки
+type Defect struct {
+	ID           string         `json:"id" gorm:"primaryKey"`
+	RuID         string         `json:"ruId" gorm:"index"`
+	CellID       *int           `json:"cellId,omitempty" gorm:"index"`
+	CellNumber   string         `json:"cellNumber,omitempty"`
+	Title        string         `json:"title"`
+	Description  string         `json:"description"`
+	Severity     DefectSeverity `json:"severity" gorm:"index"`
+	Status       DefectStatus   `json:"status" gorm:"index"`
+	AssigneeID   *string        `json:"assigneeId,omitempty" gorm:"index"`
+	AssigneeName *string        `json:"assigneeName,omitempty"`
+	DueDate      *time.Time     `json:"dueDate,omitempty"`
+	// FixDuringMaintenance - устранить при ближайшем плановом ТО РУ
+	FixDuringMaintenance bool       `json:"fixDuringMaintenance"`
+	ReportedBy           string     `json:"reportedBy"`
+	FixedAt              *time.Time `json:"fixedAt,omitempty"`
+	FixedBy              *string    `json:"fixedBy,omitempty"`
+	Resolution           *string    `json:"resolution,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+
+	// InspectionID - осмотр, по результатам которого заведен дефект
+	InspectionID *string `json:"inspectionId,omitempty" gorm:"index"`
+
+	Photos []Photo `json:"photos" gorm:"-"`
+}
+
+func (Defect) TableName() string {
+	return "defects"
+}
+
+// DefectFilter - критерии отбора дефектов
+type DefectFilter struct {
+	RuID       string         `form:"ruId"`
+	CellID     *int           `form:"cellId"`
+	Status     DefectStatus   `form:"status" binding:"omitempty,oneof=open in_work fixed"`
+	Severity   DefectSeverity `form:"severity" binding:"omitempty,oneof=critical major minor"`
+	AssigneeID string         `form:"assigneeId"`
+	// OrganizationID - только дефекты РУ организации; задается сервисом по пользователю,
+	// пустая - дефекты всех организаций
+	OrganizationID string `form:"-"`
+}
+
+type CreateDefectRequest struct {
+	RuID                 string         `json:"ruId" binding:"required"`
+	CellID               *int           `json:"cellId,omitempty"`
+	Title                string         `json:"title" binding:"required,min=3,max=200"`
+	Description          string         `json:"description" binding:"max=4000"`
+	Severity             DefectSeverity `json:"severity" binding:"required,oneof=critical major minor"`
+	AssigneeID           *string        `json:"assigneeId,omitempty"`
+	DueDate              *time.Time     `json:"dueDate,omitempty"`
+	FixDuringMaintenance bool           `json:"fixDuringMaintenance"`
+}
+
+// UpdateDefectRequest - частичное изменение; nil-поля не меняются
+type UpdateDefectRequest struct {
+	Title                *string         `json:"title,omitempty" binding:"omitempty,min=3,max=200"`
+	Description          *string         `json:"description,omitempty" binding:"omitempty,max=4000"`
+	Severity             *DefectSeverity `json:"severity,omitempty" binding:"omitempty,oneof=critical major minor"`
+	AssigneeID           *string         `json:"assigneeId,omitempty"`
+	DueDate              *time.Time      `json:"dueDate,omitempty"`
+	FixDuringMaintenance *bool           `json:"fixDuringMaintenance,omitempty"`
+}
+
+type UpdateDefectStatusRequest struct {
+	Status     DefectStatus `json:"status" binding:"required,oneof=open in_work fixed"`
+	Resolution *string      `json:"resolution,omitempty" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ RTU / IED DEVICE MODELS ================
+
+type DeviceType string
+
+const (
+	DeviceRTU     DeviceType = "rtu"
+	DeviceIED     DeviceType = "ied"
+	DeviceGateway DeviceType = "gateway"
+	DeviceMeter   DeviceType = "meter"
+)
+
+type DeviceProtocol string
+
+const (
+	ProtocolIEC104    DeviceProtocol = "iec104"
+	ProtocolIEC61850  DeviceProtocol = "iec61850"
+	ProtocolModbusTCP DeviceProtocol = "modbus_tcp"
+	ProtocolDNP3      DeviceProtocol = "dnp3"
+	ProtocolOther     DeviceProtocol = "other"
+)
+
+// DeviceCheckMethod - способ проверки связи:
+// ping - ICMP echo, port - TCP-подключение к порту, protocol - тестовый кадр протокола
+// (IEC 104 TESTFR, Modbus read), heartbeat - только сигналы от самого устройства или шлюза.
+type DeviceCheckMethod string
+
+const (
+	CheckPing      DeviceCheckMethod = "ping"
+	CheckPort      DeviceCheckMethod = "port"
+	CheckProtocol  DeviceCheckMethod = "protocol"
+	CheckHeartbeat DeviceCheckMethod = "heartbeat"
+)
+
+type DeviceStatus string
+
+const (
+	DeviceStatusUnknown DeviceStatus = "unknown"
+	DeviceStatusOnline  DeviceStatus = "online"
+	DeviceStatusOffline DeviceStatus = "offline"
+)
+
+const IDPrefixDevice = "dev"
+
+// DefaultDeviceSilenceTimeout - через сколько секунд без связи устройство считается отключенным
+const DefaultDeviceSilenceTimeout = 180
+
+// Device - устройство телемеханики или релейной защиты, обслуживающее РУ
+type Device struct {
+	ID             string            `json:"id" gorm:"primaryKey"`
+	Name           string            `json:"name"`
+	Type           DeviceType        `json:"type" gorm:"index"`
+	Protocol       DeviceProtocol    `json:"protocol"`
+	Host           string            `json:"host"`
+	Port           int               `json:"port"`
+	RuID           string            `json:"ruId" gorm:"index"`
+	CheckMethod    DeviceCheckMethod `json:"checkMethod"`
+	SilenceTimeout int               `json:"silenceTimeout"`
+	Enabled        bool              `json:"enabled"`
+	Status         DeviceStatus      `json:"status" gorm:"index"`
+	LastContactAt  *time.Time        `json:"lastContactAt,omitempty"`
+	LastCheckAt    *time.Time        `json:"lastCheckAt,omitempty"`
+	LastError      *string           `json:"lastError,omitempty"`
+	StatusSince    *time.Time        `json:"statusSince,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+
+	Cells []DeviceCell `json:"cells" gorm:"foreignKey:DeviceID;constraint:OnDelete:CASCADE"`
+}
+
+func (Device) TableName() string {
+	return "devices"
+}
+
+// DeviceCell - ячейка, телеметрия или защита которой обслуживается устройством
+type DeviceCell struct {
+	DeviceID   string `json:"-" gorm:"primaryKey"`
+	CellID     int    `json:"cellId" gorm:"primaryKey;index"`
+	CellNumber string `json:"cellNumber"`
+}
+
+func (DeviceCell) TableName() string {
+	return "device_cells"
+}
+
+// DeviceRequest - регистрация или изменение устройства
+type DeviceRequest struct {
+	Name           string            `json:"name" binding:"required,min=2,max=100"`
+	Type           DeviceType        `json:"type" binding:"required,oneof=rtu ied gateway meter"`
+	Protocol       DeviceProtocol    `json:"protocol" binding:"required,oneof=iec104 iec61850 modbus_tcp dnp3 other"`
+	Host           string            `json:"host" binding:"max=255"`
+	Port           int               `json:"port" binding:"omitempty,min=1,max=65535"`
+	RuID           string            `json:"ruId" binding:"required"`
+	CellIDs        []int             `json:"cellIds"`
+	CheckMethod    DeviceCheckMethod `json:"checkMethod" binding:"required,oneof=ping port protocol heartbeat"`
+	SilenceTimeout int               `json:"silenceTimeout" binding:"omitempty,min=30,max=86400"`
+	Enabled        *bool             `json:"enabled"`
+}
+
+type DeviceFilter struct {
+	RuID   string       `form:"ruId"`
+	Type   DeviceType   `form:"type" binding:"omitempty,oneof=rtu ied gateway meter"`
+	Status DeviceStatus `form:"status" binding:"omitempty,oneof=unknown online offline"`
+	// OrganizationID - только устройства РУ организации; задается сервисом по пользователю,
+	// пустая - устройства всех организаций
+	OrganizationID string `form:"-"`
+}
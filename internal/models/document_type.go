@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ DOCUMENT TYPE CATALOG ================
+
+// DocumentTypeUnclassified - в отчетах: документ записи не найден в справочнике
+// (свободный текст старых записей или удаленный тип)
+const DocumentTypeUnclassified = "unclassified"
+
+// DocumentType - вид документа, на основании которого выполнена операция (распоряжение,
+// наряд-допуск, аварийная заявка). В записи журнала хранится код вида, поэтому отчеты
+// группируют операции по справочнику, а не по свободному тексту. Отключенный вид
+// нельзя указать в новой записи, но старые записи с ним остаются в отчетах.
+type DocumentType struct {
+	Code        string    `json:"code" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex"`
+	Description string    `json:"description,omitempty"`
+	Active      bool      `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (DocumentType) TableName() string {
+	return "document_types"
+}
+
+// DefaultDocumentTypes - справочник, которым заполняется пустая таблица
+var DefaultDocumentTypes = []DocumentType{
+	{Code: "order", Name: "Распоряжение"},
+	{Code: "permit", Name: "Наряд-допуск"},
+	{Code: "emergency_request", Name: "Аварийная заявка"},
+	{Code: "planned_request", Name: "Плановая заявка"},
+	{Code: "operational_request", Name: "Оперативная заявка"},
+}
+
+// CreateDocumentTypeRequest - новый вид документа. Код - латиница в нижнем регистре,
+// цифры и подчеркивание (order, emergency_request)
+type CreateDocumentTypeRequest struct {
+	Code        string `json:"code" binding:"required,min=2,max=50"`
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Active      *bool  `json:"active,omitempty"`
+}
+
+// UpdateDocumentTypeRequest - изменение вида документа; код не меняется
+type UpdateDocumentTypeRequest struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Active      *bool  `json:"active,omitempty"`
+}
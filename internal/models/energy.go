@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// ================ ENERGY ACCOUNTING MODELS ================
+
+// MeterReadingSource - откуда получено показание счетчика
+type MeterReadingSource string
+
+const (
+	MeterReadingManual    MeterReadingSource = "manual"
+	MeterReadingTelemetry MeterReadingSource = "telemetry"
+)
+
+// MeterReading - показание счетчика активной энергии отходящей ячейки (нарастающим итогом, кВт·ч).
+// MeterReplaced отмечает первое показание нового счетчика: расход до него не переносится.
+type MeterReading struct {
+	CellID        int                `json:"cellId" gorm:"primaryKey"`
+	ReadAt        time.Time          `json:"readAt" gorm:"primaryKey"`
+	RuID          string             `json:"ruId" gorm:"index"`
+	EnergyKWh     float64            `json:"energyKWh" gorm:"column:energy_kwh"`
+	Source        MeterReadingSource `json:"source"`
+	MeterReplaced bool               `json:"meterReplaced"`
+	RecordedBy    *string            `json:"recordedBy,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+}
+
+func (MeterReading) TableName() string {
+	return "meter_readings"
+}
+
+// RecordMeterReadingRequest - ручной ввод показания счетчика ячейки; без readAt - текущий момент
+type RecordMeterReadingRequest struct {
+	EnergyKWh     *float64   `json:"energyKWh" binding:"required,gte=0"`
+	ReadAt        *time.Time `json:"readAt"`
+	MeterReplaced bool       `json:"meterReplaced"`
+}
+
+// RecordMeterReadingsRequest - пакет показаний счетчиков от шлюза телеметрии
+type RecordMeterReadingsRequest struct {
+	Readings []MeterReadingInput `json:"readings" binding:"required,min=1,max=5000,dive"`
+}
+
+type MeterReadingInput struct {
+	RuID      string    `json:"ruId" binding:"required"`
+	CellID    int       `json:"cellId" binding:"required"`
+	EnergyKWh *float64  `json:"energyKWh" binding:"required,gte=0"`
+	ReadAt    time.Time `json:"readAt" binding:"required"`
+}
+
+// EnergyQuery - месяц учета (YYYY-MM), необязательный фильтр по РУ и формат выгрузки
+type EnergyQuery struct {
+	Month  string `form:"month" binding:"required"`
+	RuID   string `form:"ruId"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// FeederEnergy - расход энергии отходящей ячейки (потребителя) за месяц. Начальное показание -
+// последнее на начало месяца, конечное - последнее до конца месяца; Complete - оба показания
+// есть (конечное снято не раньше чем за сутки до конца месяца), иначе расход неполный.
+type FeederEnergy struct {
+	RuID           string        `json:"ruId"`
+	RuName         string        `json:"ruName"`
+	CellID         int           `json:"cellId"`
+	CellNumber     string        `json:"cellNumber"`
+	CellName       string        `json:"cellName"`
+	Consumers      []ConsumerRef `json:"consumers"`
+	Month          string        `json:"month"`
+	OpeningKWh     *float64      `json:"openingKWh"`
+	ClosingKWh     *float64      `json:"closingKWh"`
+	ConsumptionKWh float64       `json:"consumptionKWh"`
+	Readings       int           `json:"readings"`
+	MeterReplaced  bool          `json:"meterReplaced"`
+	Complete       bool          `json:"complete"`
+}
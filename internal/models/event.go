@@ -0,0 +1,267 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ DOMAIN EVENT MODELS ================
+
+type DomainEventType string
+
+const (
+	EventCellStatusChanged DomainEventType = "cell.status_changed"
+	EventAlarmRaised       DomainEventType = "alarm.raised"
+	EventPermitIssued      DomainEventType = "permit.issued"
+	EventRuStatusChanged   DomainEventType = "ru.status_changed"
+	EventStockLow          DomainEventType = "inventory.stock_low"
+	EventFaultRecorded     DomainEventType = "fault.recorded"
+	EventDeviceOffline     DomainEventType = "device.offline"
+	EventControlSelect     DomainEventType = "control.select"
+	EventControlOperate    DomainEventType = "control.operate"
+	EventControlCancel     DomainEventType = "control.cancel"
+	EventApprovalRequested DomainEventType = "approval.requested"
+	EventUserMentioned     DomainEventType = "user.mentioned"
+	EventTelemetryAnomaly  DomainEventType = "telemetry.anomaly"
+	EventCapacityOverload  DomainEventType = "capacity.overload"
+	EventOutageApproved    DomainEventType = "outage.approved"
+	EventOutageCancelled   DomainEventType = "outage.cancelled"
+	EventVisionDiscrepancy DomainEventType = "vision.discrepancy"
+	EventThermalHotspot    DomainEventType = "thermal.hotspot"
+	EventTapExcessive      DomainEventType = "transformer.tap_excessive"
+	EventVoltageDeviation  DomainEventType = "voltage.deviation"
+	// EventAlarmCreated - зарегистрирована авария; данные события - сама авария (Alarm)
+	EventAlarmCreated DomainEventType = "alarm.created"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusProcessed OutboxStatus = "processed"
+	OutboxStatusFailed    OutboxStatus = "failed"
+)
+
+const IDPrefixEvent = "evt"
+
+// OutboxEvent - доменное событие в транзакционном outbox.
+// Записывается в одной транзакции с изменением данных и доставляется подписчикам диспетчером.
+type OutboxEvent struct {
+	ID          string          `json:"id" gorm:"primaryKey"`
+	Type        DomainEventType `json:"type" gorm:"index"`
+	RuID        string          `json:"ruId" gorm:"index"`
+	Payload     string          `json:"payload" gorm:"type:jsonb"`
+	Status      OutboxStatus    `json:"status" gorm:"index"`
+	Attempts    int             `json:"attempts"`
+	LastError   *string         `json:"lastError,omitempty"`
+	AvailableAt time.Time       `json:"availableAt" gorm:"index"`
+	ProcessedAt *time.Time      `json:"processedAt,omitempty"`
+	// BrokerPublishedAt - когда событие опубликовано во внешний брокер; брокер ведет
+	// доставку отдельно от подписчиков, nil - еще не опубликовано
+	BrokerPublishedAt *time.Time `json:"brokerPublishedAt,omitempty" gorm:"index"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// BrokerDeliveryStats - статистика публикации событий одного типа во внешний брокер.
+// Накапливается всеми экземплярами и переживает рестарт.
+type BrokerDeliveryStats struct {
+	Type            DomainEventType `json:"type" gorm:"primaryKey"`
+	Published       int64           `json:"published"`
+	Failed          int64           `json:"failed"`
+	LastPublishedAt *time.Time      `json:"lastPublishedAt,omitempty"`
+	LastError       *string         `json:"lastError,omitempty"`
+	LastErrorAt     *time.Time      `json:"lastErrorAt,omitempty"`
+}
+
+func (BrokerDeliveryStats) TableName() string {
+	return "broker_delivery_stats"
+}
+
+// CellStatusChangedPayload - данные события смены статуса ячейки
+type CellStatusChangedPayload struct {
+	CellID         int        `json:"cellId"`
+	CellNumber     string     `json:"cellNumber"`
+	CellName       string     `json:"cellName"`
+	Status         CellStatus `json:"status"`
+	PreviousStatus CellStatus `json:"previousStatus"`
+	IsGrounded     bool       `json:"isGrounded"`
+}
+
+// AlarmRaisedPayload - данные события аварии на ячейке
+type AlarmRaisedPayload struct {
+	CellID     int        `json:"cellId"`
+	CellNumber string     `json:"cellNumber"`
+	CellName   string     `json:"cellName"`
+	Status     CellStatus `json:"status"`
+}
+
+// PermitIssuedPayload - данные события операции по наряду-допуску
+type PermitIssuedPayload struct {
+	RecordID          string  `json:"recordId"`
+	WorkOrderNumber   string  `json:"workOrderNumber"`
+	Action            string  `json:"action"`
+	CellNumber        string  `json:"cellNumber"`
+	Operator          string  `json:"operator"`
+	ResponsiblePerson *string `json:"responsiblePerson,omitempty"`
+}
+
+// RuStatusChangedPayload - данные события смены статуса РУ
+type RuStatusChangedPayload struct {
+	Name           string   `json:"name"`
+	Status         RuStatus `json:"status"`
+	PreviousStatus RuStatus `json:"previousStatus"`
+	// Reason - причина ручной установки; пусто, если статус выведен автоматически
+	Reason string `json:"reason,omitempty"`
+}
+
+// VisionDiscrepancyPayload - данные события расхождения журнала с положением ячейки
+// на панели: Observed - по индикаторам на снимке, Recorded - статус в журнале
+type VisionDiscrepancyPayload struct {
+	IndicationID string     `json:"indicationId"`
+	CellID       int        `json:"cellId"`
+	CellNumber   string     `json:"cellNumber"`
+	CellName     string     `json:"cellName"`
+	Observed     CellStatus `json:"observed"`
+	Recorded     CellStatus `json:"recorded"`
+	ImageRef     string     `json:"imageRef"`
+	CapturedAt   time.Time  `json:"capturedAt"`
+}
+
+// ThermalHotspotPayload - данные события превышения нагрева на термограмме ячейки;
+// передается самая тяжелая точка снимка
+type ThermalHotspotPayload struct {
+	SnapshotID     string      `json:"snapshotId"`
+	CellID         int         `json:"cellId"`
+	CellNumber     string      `json:"cellNumber"`
+	Point          string      `json:"point"`
+	Temperature    float64     `json:"temperature"`
+	DeltaAmbient   float64     `json:"deltaAmbient"`
+	DeltaReference *float64    `json:"deltaReference,omitempty"`
+	Band           ThermalBand `json:"band"`
+	TakenAt        time.Time   `json:"takenAt"`
+}
+
+// TapExcessivePayload - данные события превышения суточного числа переключений
+// ответвлений трансформатора; Day - сутки в формате 2006-01-02
+type TapExcessivePayload struct {
+	TransformerID   string `json:"transformerId"`
+	TransformerName string `json:"transformerName"`
+	CellID          *int   `json:"cellId,omitempty"`
+	Day             string `json:"day"`
+	Operations      int    `json:"operations"`
+	Limit           int    `json:"limit"`
+	Position        int    `json:"position"`
+}
+
+// VoltageDeviationPayload - данные события выхода напряжения ячейки стороны НН за
+// допустимое отклонение; передается фаза с наибольшим отклонением
+type VoltageDeviationPayload struct {
+	CellID           int       `json:"cellId"`
+	CellNumber       string    `json:"cellNumber"`
+	Phase            string    `json:"phase"`
+	Voltage          float64   `json:"voltage"`
+	DeviationPercent float64   `json:"deviationPercent"`
+	MeasuredAt       time.Time `json:"measuredAt"`
+}
+
+// StockLowPayload - данные события падения остатка запчастей ниже минимума
+// TelemetryAnomalyPayload - данные события необычных показаний ячейки
+type TelemetryAnomalyPayload struct {
+	CellID     int               `json:"cellId"`
+	CellNumber string            `json:"cellNumber"`
+	Metric     MeasurementMetric `json:"metric"`
+	Value      float64           `json:"value"`
+	Mean       float64           `json:"mean"`
+	StdDev     float64           `json:"stdDev"`
+	Score      float64           `json:"score"`
+	At         time.Time         `json:"at"`
+}
+
+// CapacityOverloadPayload - данные события перехода секции шин в более высокую полосу загрузки
+type CapacityOverloadPayload struct {
+	RuName       string          `json:"ruName"`
+	VoltageLevel VoltageLevel    `json:"voltageLevel"`
+	Section      int             `json:"section"`
+	CurrentA     float64         `json:"currentA"`
+	CapacityA    float64         `json:"capacityA"`
+	Percent      float64         `json:"percent"`
+	Band         UtilizationBand `json:"band"`
+}
+
+type StockLowPayload struct {
+	WarehouseID   string `json:"warehouseId"`
+	WarehouseName string `json:"warehouseName"`
+	ItemID        string `json:"itemId"`
+	ItemName      string `json:"itemName"`
+	Available     int    `json:"available"`
+	MinQuantity   int    `json:"minQuantity"`
+}
+
+// FaultRecordedPayload - данные события срабатывания релейной защиты
+type FaultRecordedPayload struct {
+	FaultID      string            `json:"faultId"`
+	CellID       int               `json:"cellId"`
+	CellNumber   string            `json:"cellNumber"`
+	TripType     TripType          `json:"tripType"`
+	FaultCurrent *float64          `json:"faultCurrent,omitempty"`
+	AutoReclose  AutoRecloseResult `json:"autoReclose"`
+}
+
+// DeviceOfflinePayload - данные события потери связи с устройством
+type DeviceOfflinePayload struct {
+	DeviceID      string     `json:"deviceId"`
+	Name          string     `json:"name"`
+	Type          DeviceType `json:"type"`
+	Host          string     `json:"host"`
+	LastContactAt *time.Time `json:"lastContactAt,omitempty"`
+}
+
+// ControlCommandPayload - команда телеуправления для шлюза телеметрии
+type ControlCommandPayload struct {
+	CommandID  string        `json:"commandId"`
+	DeviceID   string        `json:"deviceId"`
+	CellID     int           `json:"cellId"`
+	CellNumber string        `json:"cellNumber"`
+	Action     CommandAction `json:"action"`
+}
+
+// Виды запросов на одобрение
+const (
+	ApprovalCellChange         = "cell_change"
+	ApprovalStatusConfirmation = "status_confirmation"
+	ApprovalPlannedOutage      = "planned_outage"
+)
+
+// ApprovalRequestedPayload - данные события запроса одобрения: изменение паспорта
+// ячейки, подтверждение переключения критичной ячейки вторым сотрудником или
+// согласование планового отключения (CellNumber - номера всех ячеек заявки)
+type ApprovalRequestedPayload struct {
+	Kind        string `json:"kind"`
+	RefID       string `json:"refId"`
+	CellID      int    `json:"cellId"`
+	CellNumber  string `json:"cellNumber"`
+	RequestedBy string `json:"requestedBy"`
+}
+
+// UserMentionedPayload - данные события упоминания пользователей (@email) в комментарии
+// к записи журнала операций
+type UserMentionedPayload struct {
+	RecordID   string   `json:"recordId"`
+	CellNumber string   `json:"cellNumber"`
+	Author     string   `json:"author"`
+	Comment    string   `json:"comment"`
+	Emails     []string `json:"emails"`
+}
+
+// OutageChangedPayload - данные событий согласования и отмены планового отключения
+type OutageChangedPayload struct {
+	OutageID  string       `json:"outageId"`
+	Status    OutageStatus `json:"status"`
+	StartsAt  time.Time    `json:"startsAt"`
+	EndsAt    time.Time    `json:"endsAt"`
+	CellIDs   []int        `json:"cellIds"`
+	Consumers int          `json:"consumers"`
+}
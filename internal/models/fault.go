@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ RELAY TRIP / FAULT MODELS ================
+
+type TripType string
+
+const (
+	TripOvercurrent  TripType = "overcurrent"
+	TripEarthFault   TripType = "earth_fault"
+	TripDifferential TripType = "differential"
+	TripDistance     TripType = "distance"
+	TripArcFlash     TripType = "arc_flash"
+	TripOther        TripType = "other"
+)
+
+// AutoRecloseResult - результат АПВ после отключения
+type AutoRecloseResult string
+
+const (
+	AutoRecloseNone       AutoRecloseResult = "none"
+	AutoRecloseSuccessful AutoRecloseResult = "successful"
+	AutoRecloseFailed     AutoRecloseResult = "failed"
+)
+
+type FaultSource string
+
+const (
+	FaultSourceTelemetry FaultSource = "telemetry"
+	FaultSourceManual    FaultSource = "manual"
+)
+
+const IDPrefixFault = "fault"
+
+// FaultEvent - срабатывание релейной защиты на ячейке.
+// EventID связывает запись с аварией, зарегистрированной по событию fault.recorded.
+type FaultEvent struct {
+	ID           string            `json:"id" gorm:"primaryKey"`
+	RuID         string            `json:"ruId" gorm:"index:idx_fault_events_ru_time,priority:1"`
+	CellID       int               `json:"cellId" gorm:"index"`
+	CellNumber   string            `json:"cellNumber"`
+	TripType     TripType          `json:"tripType" gorm:"index"`
+	FaultCurrent *float64          `json:"faultCurrent,omitempty"`
+	AutoReclose  AutoRecloseResult `json:"autoReclose"`
+	OccurredAt   time.Time         `json:"occurredAt" gorm:"index:idx_fault_events_ru_time,priority:2"`
+	Source       FaultSource       `json:"source"`
+	ExternalID   *string           `json:"externalId,omitempty" gorm:"uniqueIndex"`
+	Comment      *string           `json:"comment,omitempty"`
+	RecordedBy   string            `json:"recordedBy"`
+	EventID      string            `json:"-" gorm:"index"`
+	CreatedAt    time.Time         `json:"created_at"`
+
+	Alarm *Alarm `json:"alarm,omitempty" gorm:"-"`
+}
+
+func (FaultEvent) TableName() string {
+	return "fault_events"
+}
+
+// FaultInput - отключение, переданное шлюзом телеметрии или введенное вручную.
+// ExternalID - идентификатор записи в регистраторе; повторная передача не создает дубликат.
+type FaultInput struct {
+	CellID       int               `json:"cellId" binding:"required"`
+	TripType     TripType          `json:"tripType" binding:"required,oneof=overcurrent earth_fault differential distance arc_flash other"`
+	FaultCurrent *float64          `json:"faultCurrent,omitempty" binding:"omitempty,min=0"`
+	AutoReclose  AutoRecloseResult `json:"autoReclose" binding:"required,oneof=none successful failed"`
+	OccurredAt   time.Time         `json:"occurredAt" binding:"required"`
+	ExternalID   *string           `json:"externalId,omitempty" binding:"omitempty,max=100"`
+	Comment      *string           `json:"comment,omitempty" binding:"omitempty,max=1000"`
+}
+
+// TelemetryFaultRequest - отключение от шлюза телеметрии
+type TelemetryFaultRequest struct {
+	RuID string `json:"ruId" binding:"required"`
+	FaultInput
+}
+
+// FaultFilter - отбор журнала отключений РУ
+type FaultFilter struct {
+	CellID   *int       `form:"cellId"`
+	TripType TripType   `form:"tripType" binding:"omitempty,oneof=overcurrent earth_fault differential distance arc_flash other"`
+	From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// ================ LOAD FORECAST MODELS ================
+
+const IDPrefixForecast = "fcst"
+
+// Модели прогноза
+const (
+	// ForecastModelSeasonalWeekly - среднее за тот же час той же недели по последним неделям
+	ForecastModelSeasonalWeekly = "seasonal-weekly"
+	// ForecastModelSeasonalDaily - среднее за тот же час по последним суткам (мало истории)
+	ForecastModelSeasonalDaily = "seasonal-daily"
+)
+
+// Горизонты прогноза
+const (
+	ForecastHorizonDay  = "24h"
+	ForecastHorizonWeek = "7d"
+)
+
+// ForecastHorizons - горизонт прогноза в часах
+var ForecastHorizons = map[string]int{
+	ForecastHorizonDay:  24,
+	ForecastHorizonWeek: 7 * 24,
+}
+
+// ForecastRun - один прогноз нагрузки ячейки. Точность (MAE, MAPE) считается
+// задачей forecast-accuracy, когда горизонт прогноза прошел.
+type ForecastRun struct {
+	ID           string            `json:"id" gorm:"primaryKey"`
+	RuID         string            `json:"ruId" gorm:"index"`
+	CellID       int               `json:"cellId" gorm:"index"`
+	Metric       MeasurementMetric `json:"metric"`
+	Model        string            `json:"model"`
+	Horizon      string            `json:"horizon"`
+	StartAt      time.Time         `json:"startAt"`
+	EndAt        time.Time         `json:"endAt" gorm:"index"`
+	HistoryHours int               `json:"historyHours"`
+	CreatedAt    time.Time         `json:"createdAt"`
+
+	EvaluatedAt *time.Time `json:"evaluatedAt,omitempty"`
+	// Compared - сколько часов прогноза удалось сравнить с фактом
+	Compared int      `json:"compared"`
+	MAE      *float64 `json:"mae,omitempty"`
+	MAPE     *float64 `json:"mape,omitempty"`
+
+	Points []ForecastPoint `json:"points,omitempty" gorm:"-"`
+}
+
+func (ForecastRun) TableName() string {
+	return "forecast_runs"
+}
+
+// ForecastPoint - прогноз на час; Actual заполняется при оценке точности
+type ForecastPoint struct {
+	RunID  string    `json:"-" gorm:"primaryKey"`
+	At     time.Time `json:"at" gorm:"primaryKey"`
+	Value  float64   `json:"value"`
+	Actual *float64  `json:"actual,omitempty"`
+}
+
+func (ForecastPoint) TableName() string {
+	return "forecast_points"
+}
+
+// ForecastQuery - параметры запроса прогноза РУ
+type ForecastQuery struct {
+	CellID  *int   `form:"cellId"`
+	Horizon string `form:"horizon" binding:"omitempty,oneof=24h 7d"`
+}
+
+// RuForecast - прогнозы нагрузки по трансформаторам и вводам секций РУ
+type RuForecast struct {
+	RuID      string        `json:"ruId"`
+	Horizon   string        `json:"horizon"`
+	Forecasts []ForecastRun `json:"forecasts"`
+}
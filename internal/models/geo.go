@@ -0,0 +1,90 @@
+package models
+
+// ================ MAP (GEOJSON) MODELS ================
+
+// Объекты на карте
+const (
+	MapKindSubstation = "substation"
+	MapKindRU         = "ru"
+)
+
+// MapStatus - состояние объекта на карте, по которому интерфейс выбирает цвет
+type MapStatus string
+
+const (
+	MapStatusNormal  MapStatus = "normal"  // все в штатном режиме
+	MapStatusWarning MapStatus = "warning" // ограничения, обслуживание, активные аварии
+	MapStatusAlarm   MapStatus = "alarm"   // аварийный режим, критичные аварии, неисправные ячейки
+	MapStatusOff     MapStatus = "off"     // выведено из работы
+)
+
+// MapStatusColors - цвета состояний по умолчанию
+var MapStatusColors = map[MapStatus]string{
+	MapStatusNormal:  "#2e7d32",
+	MapStatusWarning: "#f9a825",
+	MapStatusAlarm:   "#c62828",
+	MapStatusOff:     "#757575",
+}
+
+// mapStatusRank - порядок тяжести: подстанция окрашивается по худшему из своих РУ
+var mapStatusRank = map[MapStatus]int{
+	MapStatusNormal:  0,
+	MapStatusOff:     1,
+	MapStatusWarning: 2,
+	MapStatusAlarm:   3,
+}
+
+// Worse - более тяжелое из двух состояний
+func (s MapStatus) Worse(other MapStatus) MapStatus {
+	if mapStatusRank[other] > mapStatusRank[s] {
+		return other
+	}
+	return s
+}
+
+// GeoFeatureCollection - GeoJSON (RFC 7946) для карты промышленной зоны
+type GeoFeatureCollection struct {
+	Type     string       `json:"type"`
+	Features []GeoFeature `json:"features"`
+	// Unlocated - идентификаторы РУ и подстанций без координат, не попавших на карту
+	Unlocated []string `json:"unlocated"`
+}
+
+// GeoFeature - точечный объект карты
+type GeoFeature struct {
+	Type       string        `json:"type"`
+	ID         string        `json:"id"`
+	Geometry   GeoPoint      `json:"geometry"`
+	Properties MapProperties `json:"properties"`
+}
+
+// GeoPoint - точка; координаты в порядке GeoJSON: [долгота, широта]
+type GeoPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// MapProperties - свойства объекта карты для подписи и окраски
+type MapProperties struct {
+	Kind           string             `json:"kind"`
+	Name           string             `json:"name"`
+	SubstationID   string             `json:"substationId,omitempty"`
+	Status         MapStatus          `json:"status"`
+	Color          string             `json:"color"`
+	RuStatus       RuStatus           `json:"ruStatus,omitempty"`
+	RUsCount       int                `json:"rusCount,omitempty"`
+	CellsByStatus  map[CellStatus]int `json:"cellsByStatus,omitempty"`
+	ActiveAlarms   int                `json:"activeAlarms"`
+	CriticalAlarms int                `json:"criticalAlarms"`
+}
+
+// NewGeoPoint - точка по широте и долготе
+func NewGeoPoint(latitude, longitude float64) GeoPoint {
+	return GeoPoint{Type: "Point", Coordinates: [2]float64{longitude, latitude}}
+}
+
+// SetLocationRequest - координаты РУ или подстанции (WGS 84)
+type SetLocationRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required,gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude" binding:"required,gte=-180,lte=180"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ HEALTH MODELS ================
+
+// HealthStatus - итоговое состояние экземпляра
+type HealthStatus string
+
+const (
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded - недоступна некритичная зависимость (брокер, SMTP): экземпляр
+	// обслуживает запросы, но часть функций (публикация событий, письма) откладывается
+	HealthDegraded HealthStatus = "degraded"
+	// HealthUnavailable - недоступна критичная зависимость (база данных): /readyz отвечает 503
+	HealthUnavailable HealthStatus = "unavailable"
+)
+
+// ComponentStatus - состояние отдельной зависимости
+type ComponentStatus string
+
+const (
+	ComponentUp   ComponentStatus = "up"
+	ComponentDown ComponentStatus = "down"
+	// ComponentDisabled - зависимость не настроена в конфигурации
+	ComponentDisabled ComponentStatus = "disabled"
+)
+
+// ComponentHealth - результат проверки зависимости с ее временем ответа
+type ComponentHealth struct {
+	Name      string                 `json:"name"`
+	Status    ComponentStatus        `json:"status"`
+	Critical  bool                   `json:"critical"`
+	LatencyMs float64                `json:"latencyMs"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport - ответ /readyz
+type HealthReport struct {
+	Status     HealthStatus      `json:"status"`
+	Components []ComponentHealth `json:"components"`
+	CheckedAt  time.Time         `json:"checkedAt"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ INSPECTION MODELS ================
+
+const (
+	IDPrefixChecklist        = "chk"
+	IDPrefixChecklistItem    = "chki"
+	IDPrefixInspection       = "insp"
+	IDPrefixInspectionResult = "inspr"
+)
+
+// ChecklistTemplate - шаблон обхода для типа РУ. Пустой RuType - шаблон для всех РУ.
+type ChecklistTemplate struct {
+	ID        string          `json:"id" gorm:"primaryKey"`
+	Name      string          `json:"name"`
+	RuType    RUType          `json:"ruType,omitempty" gorm:"index"`
+	Active    bool            `json:"active" gorm:"default:true"`
+	Items     []ChecklistItem `json:"items" gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (ChecklistTemplate) TableName() string {
+	return "checklist_templates"
+}
+
+// ChecklistItem - пункт шаблона. DefectSeverity - важность дефекта,
+// который заводится, если пункт не пройден.
+type ChecklistItem struct {
+	ID             string         `json:"id" gorm:"primaryKey"`
+	TemplateID     string         `json:"templateId" gorm:"index"`
+	Position       int            `json:"position"`
+	Text           string         `json:"text"`
+	DefectSeverity DefectSeverity `json:"defectSeverity"`
+}
+
+func (ChecklistItem) TableName() string {
+	return "checklist_items"
+}
+
+type ChecklistItemRequest struct {
+	Text           string         `json:"text" binding:"required,min=3,max=200"`
+	DefectSeverity DefectSeverity `json:"defectSeverity" binding:"omitempty,oneof=critical major minor"`
+}
+
+// SaveChecklistTemplateRequest - создание шаблона или полная замена пунктов
+type SaveChecklistTemplateRequest struct {
+	Name   string                 `json:"name" binding:"required,min=3,max=100"`
+	RuType RUType                 `json:"ruType" binding:"omitempty,oneof=KRU TP"`
+	Active *bool                  `json:"active,omitempty"`
+	Items  []ChecklistItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type InspectionItemResult string
+
+const (
+	InspectionItemOK     InspectionItemResult = "ok"
+	InspectionItemFailed InspectionItemResult = "failed"
+	InspectionItemNA     InspectionItemResult = "na"
+)
+
+// Inspection - проведенный осмотр РУ по шаблону
+type Inspection struct {
+	ID           string             `json:"id" gorm:"primaryKey"`
+	RuID         string             `json:"ruId" gorm:"index"`
+	TemplateID   string             `json:"templateId"`
+	TemplateName string             `json:"templateName"`
+	InspectedAt  time.Time          `json:"inspectedAt" gorm:"index"`
+	InspectedBy  string             `json:"inspectedBy"`
+	Remarks      string             `json:"remarks"`
+	FailedCount  int                `json:"failedCount"`
+	Results      []InspectionResult `json:"results" gorm:"foreignKey:InspectionID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time          `json:"created_at"`
+}
+
+func (Inspection) TableName() string {
+	return "inspections"
+}
+
+// InspectionResult - результат по пункту осмотра; текст пункта копируется,
+// чтобы правка шаблона не меняла прошлые осмотры
+type InspectionResult struct {
+	ID           string               `json:"id" gorm:"primaryKey"`
+	InspectionID string               `json:"inspectionId" gorm:"index"`
+	ItemID       string               `json:"itemId"`
+	Position     int                  `json:"position"`
+	Text         string               `json:"text"`
+	Result       InspectionItemResult `json:"result"`
+	Remark       string               `json:"remark"`
+	CellID       *int                 `json:"cellId,omitempty"`
+	DefectID     *string              `json:"defectId,omitempty"`
+	Photos       []Photo              `json:"photos" gorm:"-"`
+}
+
+func (InspectionResult) TableName() string {
+	return "inspection_results"
+}
+
+type InspectionResultRequest struct {
+	ItemID string               `json:"itemId" binding:"required"`
+	Result InspectionItemResult `json:"result" binding:"required,oneof=ok failed na"`
+	Remark string               `json:"remark" binding:"max=2000"`
+	CellID *int                 `json:"cellId,omitempty"`
+}
+
+// SubmitInspectionRequest - результаты осмотра; должны быть заполнены все пункты шаблона
+type SubmitInspectionRequest struct {
+	TemplateID  string                    `json:"templateId" binding:"required"`
+	InspectedAt *time.Time                `json:"inspectedAt,omitempty"`
+	Remarks     string                    `json:"remarks" binding:"max=4000"`
+	Results     []InspectionResultRequest `json:"results" binding:"required,min=1,dive"`
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ SPARE PARTS INVENTORY MODELS ================
+
+type InventoryCategory string
+
+const (
+	InventoryBreaker   InventoryCategory = "breaker"
+	InventoryFuse      InventoryCategory = "fuse"
+	InventoryInsulator InventoryCategory = "insulator"
+	InventoryOther     InventoryCategory = "other"
+)
+
+const (
+	IDPrefixWarehouse     = "wh"
+	IDPrefixInventoryItem = "item"
+	IDPrefixReservation   = "resv"
+	IDPrefixStockMovement = "mov"
+)
+
+// Warehouse - склад запасных частей
+type Warehouse struct {
+	ID       string `json:"id" gorm:"primaryKey"`
+	Name     string `json:"name" gorm:"uniqueIndex"`
+	Location string `json:"location"`
+	// OrganizationID - организация, РУ которой снабжает склад
+	OrganizationID string    `json:"organizationId" gorm:"index;not null;default:'default'"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// InventoryItem - номенклатура запасных частей
+type InventoryItem struct {
+	ID        string            `json:"id" gorm:"primaryKey"`
+	SKU       string            `json:"sku" gorm:"uniqueIndex"`
+	Name      string            `json:"name"`
+	Category  InventoryCategory `json:"category" gorm:"index"`
+	Unit      string            `json:"unit"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func (InventoryItem) TableName() string {
+	return "inventory_items"
+}
+
+// StockLevel - остаток позиции на складе. Reserved - зарезервировано под наряды и ТО,
+// доступно к резервированию Quantity - Reserved. При падении доступного остатка
+// ниже MinQuantity поднимается авария.
+type StockLevel struct {
+	WarehouseID string    `json:"warehouseId" gorm:"primaryKey"`
+	ItemID      string    `json:"itemId" gorm:"primaryKey"`
+	Quantity    int       `json:"quantity"`
+	Reserved    int       `json:"reserved"`
+	MinQuantity int       `json:"minQuantity"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Available int  `json:"available" gorm:"-"`
+	Low       bool `json:"low" gorm:"-"`
+}
+
+func (StockLevel) TableName() string {
+	return "stock_levels"
+}
+
+// Fill - вычисляемые поля остатка
+func (l *StockLevel) Fill() {
+	l.Available = l.Quantity - l.Reserved
+	l.Low = l.MinQuantity > 0 && l.Available < l.MinQuantity
+}
+
+// ReservationTarget - под что зарезервированы запчасти
+type ReservationTarget string
+
+const (
+	ReserveForWorkPermit  ReservationTarget = "work_permit"
+	ReserveForMaintenance ReservationTarget = "maintenance"
+	ReserveForDefect      ReservationTarget = "defect"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationActive   ReservationStatus = "reserved"
+	ReservationConsumed ReservationStatus = "consumed"
+	ReservationReleased ReservationStatus = "released"
+)
+
+// Reservation - резерв запчастей под наряд-допуск, ТО РУ или устранение дефекта
+type Reservation struct {
+	ID          string            `json:"id" gorm:"primaryKey"`
+	WarehouseID string            `json:"warehouseId" gorm:"index"`
+	ItemID      string            `json:"itemId" gorm:"index"`
+	Quantity    int               `json:"quantity"`
+	TargetType  ReservationTarget `json:"targetType" gorm:"index:idx_reservations_target,priority:1"`
+	TargetID    string            `json:"targetId" gorm:"index:idx_reservations_target,priority:2"`
+	RuID        string            `json:"ruId" gorm:"index"`
+	Status      ReservationStatus `json:"status" gorm:"index"`
+	ReservedBy  string            `json:"reservedBy"`
+	ClosedBy    *string           `json:"closedBy,omitempty"`
+	ClosedAt    *time.Time        `json:"closedAt,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func (Reservation) TableName() string {
+	return "reservations"
+}
+
+type StockMovementReason string
+
+const (
+	MovementReceipt StockMovementReason = "receipt"
+	MovementConsume StockMovementReason = "consume"
+)
+
+// StockMovement - журнал прихода и расхода
+type StockMovement struct {
+	ID          string              `json:"id" gorm:"primaryKey"`
+	WarehouseID string              `json:"warehouseId" gorm:"index"`
+	ItemID      string              `json:"itemId" gorm:"index"`
+	Delta       int                 `json:"delta"`
+	Reason      StockMovementReason `json:"reason"`
+	RefID       *string             `json:"refId,omitempty"`
+	By          string              `json:"by"`
+	At          time.Time           `json:"at" gorm:"index"`
+}
+
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// CreateWarehouseRequest - OrganizationID задает только администратор установки,
+// иначе используется организация пользователя
+type CreateWarehouseRequest struct {
+	Name           string `json:"name" binding:"required,min=2,max=100"`
+	Location       string `json:"location" binding:"max=200"`
+	OrganizationID string `json:"organizationId"`
+}
+
+type CreateInventoryItemRequest struct {
+	SKU      string            `json:"sku" binding:"required,min=1,max=64"`
+	Name     string            `json:"name" binding:"required,min=2,max=200"`
+	Category InventoryCategory `json:"category" binding:"required,oneof=breaker fuse insulator other"`
+	Unit     string            `json:"unit" binding:"max=20"`
+}
+
+// StockFilter - отбор остатков; Low - только позиции ниже минимального остатка
+type StockFilter struct {
+	WarehouseID string `form:"warehouseId"`
+	ItemID      string `form:"itemId"`
+	Low         bool   `form:"low"`
+	// OrganizationID - только склады организации; задается сервисом по пользователю,
+	// пустая - склады всех организаций
+	OrganizationID string `form:"-"`
+}
+
+type ReceiveStockRequest struct {
+	WarehouseID string `json:"warehouseId" binding:"required"`
+	ItemID      string `json:"itemId" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,min=1"`
+}
+
+type SetMinStockRequest struct {
+	WarehouseID string `json:"warehouseId" binding:"required"`
+	ItemID      string `json:"itemId" binding:"required"`
+	MinQuantity int    `json:"minQuantity" binding:"min=0"`
+}
+
+// ReserveStockRequest - TargetID: ID записи наряда, ID РУ для ТО или ID дефекта
+type ReserveStockRequest struct {
+	WarehouseID string            `json:"warehouseId" binding:"required"`
+	ItemID      string            `json:"itemId" binding:"required"`
+	Quantity    int               `json:"quantity" binding:"required,min=1"`
+	TargetType  ReservationTarget `json:"targetType" binding:"required,oneof=work_permit maintenance defect"`
+	TargetID    string            `json:"targetId" binding:"required"`
+	RuID        string            `json:"ruId" binding:"required"`
+}
+
+type ReservationFilter struct {
+	TargetType ReservationTarget `form:"targetType" binding:"omitempty,oneof=work_permit maintenance defect"`
+	TargetID   string            `form:"targetId"`
+	RuID       string            `form:"ruId"`
+	Status     ReservationStatus `form:"status" binding:"omitempty,oneof=reserved consumed released"`
+	// OrganizationID - только резервы под РУ организации; задается сервисом по пользователю,
+	// пустая - резервы всех организаций
+	OrganizationID string `form:"-"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ SCHEDULED JOB MODELS ================
+
+type JobRunStatus string
+
+const (
+	JobRunNever     JobRunStatus = "never"
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// ScheduledJob - фоновая задача планировщика и результат последнего запуска
+type ScheduledJob struct {
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	Schedule       string       `json:"schedule"`
+	Enabled        bool         `json:"enabled"`
+	Status         JobRunStatus `json:"status"`
+	LastStartedAt  *time.Time   `json:"lastStartedAt,omitempty"`
+	LastFinishedAt *time.Time   `json:"lastFinishedAt,omitempty"`
+	LastDurationMs int64        `json:"lastDurationMs"`
+	LastError      *string      `json:"lastError,omitempty"`
+	// LastSkippedAt - запуск пропущен: задача выполняется или уже выполнена другим экземпляром
+	LastSkippedAt *time.Time `json:"lastSkippedAt,omitempty"`
+	NextRunAt     *time.Time `json:"nextRunAt,omitempty"`
+	Runs          int        `json:"runs"`
+	Failures      int        `json:"failures"`
+}
+
+// JobLock - блокировка фоновой задачи или непрерывного цикла между экземплярами API.
+// LastSlot - последний запуск по расписанию, уже взятый одним из экземпляров;
+// LockedUntil продлевается, пока задача выполняется, а после падения экземпляра
+// блокировка истекает сама.
+type JobLock struct {
+	Name        string     `json:"name" gorm:"primaryKey"`
+	LockedBy    string     `json:"lockedBy"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+	LastSlot    *time.Time `json:"lastSlot,omitempty"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (JobLock) TableName() string {
+	return "job_locks"
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ LOTO MODELS ================
+
+const IDPrefixCellLock = "lock"
+
+// CellLock - замок и плакат на ячейке (lock-out/tag-out). Пока замок не снят,
+// статус ячейки изменить нельзя. На ячейке может быть только один активный замок.
+type CellLock struct {
+	ID              string     `json:"id" gorm:"primaryKey"`
+	CellID          int        `json:"cellId" gorm:"uniqueIndex:idx_cell_locks_active,where:removed_at IS NULL"`
+	RuID            string     `json:"ruId" gorm:"index"`
+	Reason          string     `json:"reason"`
+	PermitReference *string    `json:"permitReference,omitempty"`
+	PlacedBy        string     `json:"placedBy"`
+	PlacedAt        time.Time  `json:"placedAt"`
+	RemovedBy       *string    `json:"removedBy,omitempty"`
+	RemovedAt       *time.Time `json:"removedAt,omitempty"`
+	RemovalComment  *string    `json:"removalComment,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (CellLock) TableName() string {
+	return "cell_locks"
+}
+
+// PlaceCellLockRequest - запрос на установку замка
+type PlaceCellLockRequest struct {
+	Reason          string  `json:"reason" binding:"required,min=3,max=500"`
+	PermitReference *string `json:"permitReference,omitempty" binding:"omitempty,max=100"`
+}
+
+// RemoveCellLockRequest - запрос на снятие замка
+type RemoveCellLockRequest struct {
+	Comment *string `json:"comment,omitempty" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ MAINTENANCE MODELS ================
+
+type MaintenanceJobType string
+
+const (
+	MaintenanceReindex       MaintenanceJobType = "reindex"
+	MaintenanceVacuumAnalyze MaintenanceJobType = "vacuum_analyze"
+	MaintenancePruneOutbox   MaintenanceJobType = "prune_outbox"
+)
+
+type MaintenanceJobStatus string
+
+const (
+	MaintenanceJobRunning   MaintenanceJobStatus = "running"
+	MaintenanceJobCompleted MaintenanceJobStatus = "completed"
+	MaintenanceJobFailed    MaintenanceJobStatus = "failed"
+)
+
+const IDPrefixMaintenanceJob = "job"
+
+// MaintenanceStep - один шаг задачи обслуживания (индекс или таблица)
+type MaintenanceStep struct {
+	Target      string     `json:"target"`
+	Status      string     `json:"status"`
+	RowsDeleted int64      `json:"rowsDeleted,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+// MaintenanceProgress - ход текущей операции по данным pg_stat_progress_*
+type MaintenanceProgress struct {
+	Phase       string `json:"phase"`
+	BlocksDone  int64  `json:"blocksDone"`
+	BlocksTotal int64  `json:"blocksTotal"`
+}
+
+// MaintenanceJob - задача обслуживания БД, запущенная администратором
+type MaintenanceJob struct {
+	ID          string               `json:"id"`
+	Type        MaintenanceJobType   `json:"type"`
+	Status      MaintenanceJobStatus `json:"status"`
+	RequestedBy string               `json:"requestedBy"`
+	StepsTotal  int                  `json:"stepsTotal"`
+	StepsDone   int                  `json:"stepsDone"`
+	Steps       []MaintenanceStep    `json:"steps"`
+	Progress    *MaintenanceProgress `json:"progress,omitempty"`
+	Error       *string              `json:"error,omitempty"`
+	StartedAt   time.Time            `json:"startedAt"`
+	FinishedAt  *time.Time           `json:"finishedAt,omitempty"`
+}
+
+// StartMaintenanceJobRequest - запрос на запуск задачи обслуживания.
+// Tables ограничивает набор таблиц (по умолчанию - все нагруженные таблицы).
+type StartMaintenanceJobRequest struct {
+	Type          MaintenanceJobType `json:"type" binding:"required,oneof=reindex vacuum_analyze prune_outbox"`
+	Tables        []string           `json:"tables,omitempty"`
+	RetentionDays int                `json:"retentionDays,omitempty" binding:"omitempty,min=1,max=3650"`
+}
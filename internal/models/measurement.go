@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ MEASUREMENT MODELS ================
+
+type MeasurementMetric string
+
+const (
+	MetricCurrent     MeasurementMetric = "current"
+	MetricTemperature MeasurementMetric = "temperature"
+	MetricLoad        MeasurementMetric = "load"
+)
+
+// Measurement - сырое значение телеметрии ячейки
+type Measurement struct {
+	ID         int64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	RuID       string            `json:"ruId" gorm:"index"`
+	CellID     int               `json:"cellId" gorm:"index:idx_measurements_cell_time,priority:1"`
+	Metric     MeasurementMetric `json:"metric" gorm:"index:idx_measurements_cell_time,priority:2"`
+	Value      float64           `json:"value"`
+	MeasuredAt time.Time         `json:"measuredAt" gorm:"index:idx_measurements_cell_time,priority:3;index"`
+}
+
+func (Measurement) TableName() string {
+	return "measurements"
+}
+
+// RollupResolution - шаг агрегации измерений
+type RollupResolution string
+
+const (
+	Resolution1m  RollupResolution = "1m"
+	Resolution15m RollupResolution = "15m"
+	Resolution1h  RollupResolution = "1h"
+	ResolutionRaw RollupResolution = "raw"
+)
+
+// MeasurementRollup - агрегат измерений за интервал (min/max/sum/count).
+// Среднее вычисляется как Sum/Count, что позволяет агрегировать агрегаты без искажений.
+type MeasurementRollup struct {
+	Resolution  RollupResolution  `json:"resolution" gorm:"primaryKey"`
+	CellID      int               `json:"cellId" gorm:"primaryKey"`
+	Metric      MeasurementMetric `json:"metric" gorm:"primaryKey"`
+	BucketStart time.Time         `json:"bucketStart" gorm:"primaryKey"`
+	RuID        string            `json:"ruId" gorm:"index"`
+	Min         float64           `json:"min"`
+	Max         float64           `json:"max"`
+	Sum         float64           `json:"-"`
+	Count       int64             `json:"count"`
+	Avg         float64           `json:"avg" gorm:"-"`
+	// Score - оценка аномальности (|z|) по скользящей базовой линии ячейки; считается
+	// для минутных агрегатов тока и температуры, в крупных агрегатах - максимум за интервал
+	Score *float64 `json:"score,omitempty"`
+}
+
+func (MeasurementRollup) TableName() string {
+	return "measurement_rollups"
+}
+
+// RecordMeasurementsRequest - пакет измерений от шлюза телеметрии
+type RecordMeasurementsRequest struct {
+	Measurements []MeasurementInput `json:"measurements" binding:"required,min=1,max=5000,dive"`
+}
+
+type MeasurementInput struct {
+	RuID       string            `json:"ruId" binding:"required"`
+	CellID     int               `json:"cellId" binding:"required"`
+	Metric     MeasurementMetric `json:"metric" binding:"required,oneof=current temperature load"`
+	Value      float64           `json:"value"`
+	MeasuredAt time.Time         `json:"measuredAt" binding:"required"`
+}
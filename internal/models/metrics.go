@@ -0,0 +1,27 @@
+package models
+
+// ================ METRICS MODELS ================
+
+// MetricType - тип метрики Prometheus
+type MetricType string
+
+const MetricGauge MetricType = "gauge"
+
+// MetricFamily - метрика с описанием и значениями по наборам меток
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []MetricSample
+}
+
+// MetricSample - значение метрики; метки - пары имя/значение в порядке вывода
+type MetricSample struct {
+	Labels []MetricLabel
+	Value  float64
+}
+
+type MetricLabel struct {
+	Name  string
+	Value string
+}
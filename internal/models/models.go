@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -12,16 +13,38 @@ const (
 	RoleDispatcher UserRole = "dispatcher"
 	RoleEngineer   UserRole = "engineer"
 	RoleAdmin      UserRole = "admin"
+	// RoleOrgAdmin - администратор организации: управляет пользователями своей организации
+	RoleOrgAdmin UserRole = "org_admin"
+)
+
+// UserStatus - состояние учетной записи. Самостоятельно зарегистрированный пользователь
+// подтверждает адрес почты, затем ждет решения администратора; войти можно только
+// в активную учетную запись.
+type UserStatus string
+
+const (
+	UserPendingVerification UserStatus = "pending_verification"
+	UserPendingApproval     UserStatus = "pending_approval"
+	UserActive              UserStatus = "active"
+	UserRejected            UserStatus = "rejected"
 )
 
 type User struct {
-	ID           string    `json:"id" gorm:"primaryKey"`
-	Name         string    `json:"name"`
-	Email        string    `json:"email" gorm:"uniqueIndex"`
-	PasswordHash string    `json:"-" gorm:"column:password_hash"`
-	Role         UserRole  `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             string     `json:"id" gorm:"primaryKey"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email" gorm:"uniqueIndex"`
+	PasswordHash   string     `json:"-" gorm:"column:password_hash"`
+	Role           UserRole   `json:"role"`
+	OrganizationID string     `json:"organizationId" gorm:"index;not null;default:'default'"`
+	Phone          string     `json:"phone,omitempty" mask:"personal_data:view"`
+	Status         UserStatus `json:"status" gorm:"index;not null;default:'active'"`
+	// Регистрация: подтверждение почты и решение администратора
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
+	ReviewedBy      *string    `json:"reviewedBy,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewedAt,omitempty"`
+	RejectionReason string     `json:"rejectionReason,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 func (User) TableName() string {
@@ -38,7 +61,7 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,max=128"`
 }
 
 type AuthResponse struct {
@@ -47,11 +70,14 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      string    `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	OrganizationID string     `json:"organizationId"`
+	Phone          string     `json:"phone,omitempty" mask:"personal_data:view"`
+	Status         UserStatus `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 // ================ ADMIN MODELS ================
@@ -59,14 +85,53 @@ type UserResponse struct {
 type AdminCreateRequest struct {
 	Name     string `json:"name" binding:"required,min=2,max=100"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role" binding:"required,oneof=admin dispatcher engineer"`
+	Password string `json:"password" binding:"required,max=128"`
+	Role     string `json:"role" binding:"required,oneof=admin org_admin dispatcher engineer"`
+	// OrganizationID - организация пользователя; задается только администратором установки,
+	// администратор организации создает пользователей своей организации
+	OrganizationID string `json:"organizationId"`
+	// Phone - мобильный номер для SMS в формате E.164 (+77001234567)
+	Phone string `json:"phone" binding:"omitempty,e164"`
 }
 
 type AdminUpdateRequest struct {
 	Name  string `json:"name" binding:"required,min=2,max=100"`
 	Email string `json:"email" binding:"required,email"`
-	Role  string `json:"role" binding:"required,oneof=admin dispatcher engineer"`
+	Role  string `json:"role" binding:"required,oneof=admin org_admin dispatcher engineer"`
+	Phone string `json:"phone" binding:"omitempty,e164"`
+}
+
+// ================ SUBSTATION MODELS ================
+
+// Substation - подстанция, к которой относятся РУ (ru_infos.substation_id)
+type Substation struct {
+	ID             string    `json:"id" gorm:"primaryKey"`
+	Name           string    `json:"name"`
+	Location       string    `json:"location"`
+	Description    string    `json:"description"`
+	Voltage        string    `json:"voltage"`
+	InstalledPower string    `json:"installedPower"`
+	Latitude       *float64  `json:"latitude,omitempty"`
+	Longitude      *float64  `json:"longitude,omitempty"`
+	OrganizationID string    `json:"organizationId" gorm:"index;not null;default:'default'"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Substation) TableName() string {
+	return "substations"
+}
+
+// UpdateSubstationRUsRequest - полный список РУ подстанции: перечисленные РУ переносятся
+// на подстанцию, остальные ее РУ от нее отвязываются
+type UpdateSubstationRUsRequest struct {
+	RuIDs []string `json:"ruIds" binding:"required,dive,required"`
+}
+
+// SubstationRUsResult - РУ подстанции после изменения и отвязанные от нее РУ
+type SubstationRUsResult struct {
+	RUs        []RUInfo `json:"rus"`
+	Unassigned []string `json:"unassigned"`
 }
 
 // ================ RU MODELS ================
@@ -79,42 +144,97 @@ const (
 )
 
 type RUInfo struct {
-	ID               string    `json:"id" gorm:"primaryKey"`
-	Name             string    `json:"name"`
-	Voltage          string    `json:"voltage"`
-	Sections         int       `json:"sections"`
-	CellsCount       int       `json:"cellsCount"`
-	Transformers     int       `json:"transformers"`
-	TransformerPower string    `json:"transformerPower"`
-	Location         string    `json:"location"`
-	InstallationDate string    `json:"installationDate"`
-	Manufacturer     string    `json:"manufacturer"`
-	LastMaintenance  string    `json:"lastMaintenance"`
-	NextMaintenance  string    `json:"nextMaintenance"`
-	Status           string    `json:"status"`
-	SchemeType       string    `json:"schemeType"`
-	TotalLoadHigh    string    `json:"totalLoadHigh"`
-	TotalLoadLow     string    `json:"totalLoadLow"`
-	TotalPowerHigh   string    `json:"totalPowerHigh"`
-	TotalPowerLow    string    `json:"totalPowerLow"`
-	MaxCapacityHigh  string    `json:"maxCapacityHigh"`
-	MaxCapacityLow   string    `json:"maxCapacityLow"`
-	OperationalHours int       `json:"operationalHours"`
-	LastInspection   string    `json:"lastInspection"`
-	Type             RUType    `json:"type"`
-	HasHighSide      bool      `json:"hasHighSide"`
-	HasLowSide       bool      `json:"hasLowSide"`
-	BusSections      int       `json:"busSections"`
-	CellsPerSection  int       `json:"cellsPerSection"`
-	SubstationID     string    `json:"substationId"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               string   `json:"id" gorm:"primaryKey"`
+	Name             string   `json:"name"`
+	Voltage          string   `json:"voltage"`
+	Sections         int      `json:"sections"`
+	CellsCount       int      `json:"cellsCount"`
+	Transformers     int      `json:"transformers"`
+	TransformerPower string   `json:"transformerPower" mask:"capacity:view"`
+	Location         string   `json:"location"`
+	InstallationDate string   `json:"installationDate"`
+	Manufacturer     string   `json:"manufacturer"`
+	LastMaintenance  string   `json:"lastMaintenance"`
+	NextMaintenance  string   `json:"nextMaintenance"`
+	Status           RuStatus `json:"status" gorm:"default:normal"`
+	SchemeType       string   `json:"schemeType"`
+	TotalLoadHigh    string   `json:"totalLoadHigh"`
+	TotalLoadLow     string   `json:"totalLoadLow"`
+	TotalPowerHigh   string   `json:"totalPowerHigh" mask:"capacity:view"`
+	TotalPowerLow    string   `json:"totalPowerLow" mask:"capacity:view"`
+	MaxCapacityHigh  string   `json:"maxCapacityHigh" mask:"capacity:view"`
+	MaxCapacityLow   string   `json:"maxCapacityLow" mask:"capacity:view"`
+	OperationalHours float64  `json:"operationalHours"`
+	LastInspection   string   `json:"lastInspection"`
+	Type             RUType   `json:"type"`
+	HasHighSide      bool     `json:"hasHighSide"`
+	HasLowSide       bool     `json:"hasLowSide"`
+	BusSections      int      `json:"busSections"`
+	CellsPerSection  int      `json:"cellsPerSection"`
+	// SubstationID - пустое значение (РУ не привязано к подстанции) хранится как NULL
+	SubstationID   string    `json:"substationId" gorm:"serializer:nullstring"`
+	Latitude       *float64  `json:"latitude,omitempty"`
+	Longitude      *float64  `json:"longitude,omitempty"`
+	OrganizationID string    `json:"organizationId" gorm:"index;not null;default:'default'"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Типизированные даты. Строковые поля выше сохраняются на период перехода.
+	InstallationDateAt *time.Time `json:"installationDateAt,omitempty"`
+	LastMaintenanceAt  *time.Time `json:"lastMaintenanceAt,omitempty"`
+	NextMaintenanceAt  *time.Time `json:"nextMaintenanceAt,omitempty"`
+	LastInspectionAt   *time.Time `json:"lastInspectionAt,omitempty"`
+
+	// Допустимый ток сборных шин сторон ВН/НН в амперах. Заполняется из строковых
+	// MaxCapacityHigh/MaxCapacityLow и синхронизируется с ними на период перехода.
+	MaxCapacityHighA *float64 `json:"maxCapacityHighA,omitempty" mask:"capacity:view"`
+	MaxCapacityLowA  *float64 `json:"maxCapacityLowA,omitempty" mask:"capacity:view"`
+
+	// RuntimeAccountedAt - момент, до которого OperationalHours накоплены планировщиком
+	// (задача runtime-hours): часы растут, пока РУ под напряжением
+	RuntimeAccountedAt *time.Time `json:"-"`
+
+	// ArchivedAt - РУ выведено из эксплуатации (или заведено по ошибке): оно скрыто из
+	// списков, но журнал операций и нарядов сохраняется
+	ArchivedAt *time.Time `json:"archivedAt,omitempty" gorm:"index"`
+	ArchivedBy *string    `json:"archivedBy,omitempty"`
+
+	// Статус, установленный вручную (StatusOverride), с причиной. Без ручной установки
+	// Status выводится из OperationalState при чтении.
+	StatusOverride bool       `json:"statusOverride"`
+	StatusReason   *string    `json:"statusReason,omitempty"`
+	StatusSetBy    *string    `json:"statusSetBy,omitempty"`
+	StatusSetAt    *time.Time `json:"statusSetAt,omitempty"`
+
+	// OperationalState - состояние, вычисленное по ячейкам и активным авариям
+	OperationalState OperationalState `json:"operationalState,omitempty" gorm:"-"`
 }
 
 func (RUInfo) TableName() string {
 	return "ru_infos"
 }
 
+// VoltageLevel - сторона РУ, к которой относится ячейка
+type VoltageLevel string
+
+const (
+	VoltageLevelHigh VoltageLevel = "HIGH" // сторона ВН (6-10 кВ)
+	VoltageLevelLow  VoltageLevel = "LOW"  // сторона НН (0,4 кВ)
+)
+
+// Valid - известная ли сторона
+func (l VoltageLevel) Valid() bool {
+	return l == VoltageLevelHigh || l == VoltageLevelLow
+}
+
+// Opposite - противоположная сторона РУ
+func (l VoltageLevel) Opposite() VoltageLevel {
+	if l == VoltageLevelHigh {
+		return VoltageLevelLow
+	}
+	return VoltageLevelHigh
+}
+
 type CellType string
 
 const (
@@ -141,51 +261,131 @@ const (
 )
 
 type Cell struct {
-	ID                    int        `json:"id" gorm:"primaryKey;autoIncrement"`
-	Number                string     `json:"number"`
-	Name                  string     `json:"name"`
-	Type                  CellType   `json:"type"`
-	Status                CellStatus `json:"status"`
-	Voltage               string     `json:"voltage"`
-	VoltageLevel          string     `json:"voltageLevel"`
-	Power                 *string    `json:"power,omitempty"`
-	Description           string     `json:"description"`
-	LastOperation         *string    `json:"lastOperation,omitempty"`
-	IsGrounded            bool       `json:"isGrounded"`
-	LastGroundedOperation *string    `json:"lastGroundedOperation,omitempty"`
-	TransformerNumber     *string    `json:"transformerNumber,omitempty"`
-	BusSection            *int       `json:"busSection,omitempty"`
-	Current               *float64   `json:"current,omitempty"`
-	Temperature           *float64   `json:"temperature,omitempty"`
-	Load                  *float64   `json:"load,omitempty"`
-	RuID                  string     `json:"ruId" gorm:"index"`
-	CreatedAt             time.Time  `json:"created_at"`
-	UpdatedAt             time.Time  `json:"updated_at"`
+	ID                    int          `json:"id" gorm:"primaryKey;autoIncrement"`
+	Number                string       `json:"number"`
+	Name                  string       `json:"name"`
+	Type                  CellType     `json:"type" binding:"required,oneof=INPUT SR SV TRANSFORMER RESERVE BUS LOW_VOLTAGE OUTPUT PROTECTION MEASUREMENT"`
+	Status                CellStatus   `json:"status" binding:"omitempty,oneof=ON OFF RESERVE ERROR MAINTENANCE"`
+	Voltage               string       `json:"voltage"`
+	VoltageLevel          VoltageLevel `json:"voltageLevel"`
+	Power                 *string      `json:"power,omitempty" mask:"capacity:view"`
+	Description           string       `json:"description"`
+	LastOperation         *string      `json:"lastOperation,omitempty"`
+	IsGrounded            bool         `json:"isGrounded"`
+	LastGroundedOperation *string      `json:"lastGroundedOperation,omitempty"`
+	TransformerNumber     *string      `json:"transformerNumber,omitempty"`
+	BusSection            *int         `json:"busSection,omitempty"`
+	Current               *float64     `json:"current,omitempty"`
+	Temperature           *float64     `json:"temperature,omitempty"`
+	Load                  *float64     `json:"load,omitempty"`
+	RuID                  string       `json:"ruId" gorm:"index"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at"`
+
+	// Типизированные даты. Строковые поля выше сохраняются на период перехода.
+	LastOperationAt         *time.Time `json:"lastOperationAt,omitempty"`
+	LastGroundedOperationAt *time.Time `json:"lastGroundedOperationAt,omitempty"`
+
+	// IsCritical - переключение ячейки (вводы, секционные выключатели) требует роли
+	// инженера/администратора либо подтверждения вторым сотрудником
+	IsCritical bool `json:"isCritical" gorm:"default:false"`
+
+	// OperationCount - число коммутационных операций выключателя (включений и отключений)
+	// с последнего сброса счетчика; OperationLimit - механический ресурс по данным изготовителя
+	OperationCount        int        `json:"operationCount" gorm:"default:0"`
+	OperationLimit        *int       `json:"operationLimit,omitempty"`
+	OperationCountResetAt *time.Time `json:"operationCountResetAt,omitempty"`
+
+	// RuntimeHours - наработка ячейки во включенном состоянии; RuntimeAccountedAt - момент,
+	// до которого она учтена (смена статуса или задача планировщика runtime-hours)
+	RuntimeHours       float64    `json:"runtimeHours" gorm:"default:0"`
+	RuntimeAccountedAt *time.Time `json:"-"`
+
+	// PairedCellID - ячейка противоположной стороны ТП того же присоединения (ВН ↔ НН).
+	// Пара симметрична: на двусторонней схеме ячейки стоят друг против друга.
+	PairedCellID *int `json:"pairedCellId,omitempty" gorm:"index"`
+}
+
+// AccrueRuntime - добавляет к наработке время во включенном состоянии с последнего учета
+// до now; вызывается до смены статуса
+func (c *Cell) AccrueRuntime(now time.Time) {
+	if c.Status == CellStatusON && c.RuntimeAccountedAt != nil && now.After(*c.RuntimeAccountedAt) {
+		c.RuntimeHours += now.Sub(*c.RuntimeAccountedAt).Hours()
+	}
+	c.RuntimeAccountedAt = &now
+}
+
+// LiveRuntimeHours - наработка на момент now с учетом еще не накопленного времени
+func (c *Cell) LiveRuntimeHours(now time.Time) float64 {
+	hours := c.RuntimeHours
+	if c.Status == CellStatusON && c.RuntimeAccountedAt != nil && now.After(*c.RuntimeAccountedAt) {
+		hours += now.Sub(*c.RuntimeAccountedAt).Hours()
+	}
+	return hours
+}
+
+// IsSwitchingOperation - переход статуса, при котором выключатель ячейки включается
+// или отключается (в том числе отключение защитой с переходом в ERROR)
+func IsSwitchingOperation(from, to CellStatus) bool {
+	return (from == CellStatusON) != (to == CellStatusON)
 }
 
 func (Cell) TableName() string {
 	return "cells"
 }
 
+// Префиксы внешних идентификаторов вида <prefix>_<ULID>.
+// Записи, созданные до перехода, сохраняют UUID и по-прежнему находятся по нему.
+const (
+	IDPrefixOperation        = "op"
+	IDPrefixNotificationRule = "nrule"
+)
+
 type OperationRecord struct {
-	ID                string    `json:"id" gorm:"primaryKey"`
-	CellNumber        string    `json:"cellNumber"`
-	CellName          string    `json:"cellName"`
-	Action            string    `json:"action"`
-	Operator          string    `json:"operator"`
-	Timestamp         string    `json:"timestamp"`
-	Reason            *string   `json:"reason,omitempty"`
-	DocumentType      *string   `json:"documentType,omitempty"`
-	OrderNumber       *string   `json:"orderNumber,omitempty"`
-	WorkOrderNumber   *string   `json:"workOrderNumber,omitempty"`
-	StartDate         *string   `json:"startDate,omitempty"`
-	EndDate           *string   `json:"endDate,omitempty"`
-	ResponsiblePerson *string   `json:"responsiblePerson,omitempty"`
-	Comment           *string   `json:"comment,omitempty"`
-	Severity          *string   `json:"severity,omitempty"`
-	RuID              string    `json:"ruId" gorm:"index"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                string          `json:"id" gorm:"primaryKey"`
+	CellNumber        string          `json:"cellNumber"`
+	CellName          string          `json:"cellName"`
+	Action            string          `json:"action"`
+	Operator          string          `json:"operator"`
+	Timestamp         string          `json:"timestamp"`
+	Reason            *string         `json:"reason,omitempty"`
+	DocumentType      *string         `json:"documentType,omitempty"`
+	OrderNumber       *string         `json:"orderNumber,omitempty"`
+	WorkOrderNumber   *string         `json:"workOrderNumber,omitempty"`
+	StartDate         *string         `json:"startDate,omitempty"`
+	EndDate           *string         `json:"endDate,omitempty"`
+	ResponsiblePerson *string         `json:"responsiblePerson,omitempty" mask:"personal_data:view"`
+	Comment           *string         `json:"comment,omitempty"`
+	Severity          *RecordSeverity `json:"severity,omitempty" gorm:"index"`
+	RuID              string          `json:"ruId" gorm:"index"`
+	OrganizationID    string          `json:"organizationId" gorm:"index;not null;default:'default'"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+
+	// UserID - пользователь, внесший запись (из токена); Operator - его имя на момент записи.
+	// У записей, внесенных до появления поля и не сопоставленных с пользователем, пусто.
+	UserID *string `json:"userId,omitempty" gorm:"index"`
+	// ResponsibleUserID - пользователь, ответственный по наряду; ResponsiblePerson - его имя
+	// на момент записи. По нему наряд попадает во входящие ответственного.
+	ResponsibleUserID *string `json:"responsibleUserId,omitempty" gorm:"index"`
+
+	// Типизированные даты. Строковые поля выше сохраняются на период перехода.
+	TimestampAt *time.Time `json:"timestampAt,omitempty"`
+	StartDateAt *time.Time `json:"startDateAt,omitempty"`
+	EndDateAt   *time.Time `json:"endDateAt,omitempty"`
+
+	// Время отправки по часам устройства и его расхождение с временем приема (CreatedAt),
+	// мс: положительное - часы устройства спешат
+	ClientTime  *time.Time `json:"clientTime,omitempty"`
+	ClockSkewMs *int64     `json:"clockSkewMs,omitempty"`
+	Device      string     `json:"device,omitempty" gorm:"index"`
+
+	// Записи журнала не изменяются и не удаляются; цепочка хешей делает правку заметной
+	ChainLink
+
+	// Исправления записи и значения полей с их учетом (только в ответах журнала)
+	Corrections []OperationCorrection `json:"corrections,omitempty" gorm:"-"`
+	Corrected   map[string]*string    `json:"corrected,omitempty" gorm:"-"`
 }
 
 // UpdateCellInfoRequest - запрос на обновление информации ячейки
@@ -199,40 +399,165 @@ func (OperationRecord) TableName() string {
 	return "operation_records"
 }
 
+// RecordSeverity - важность записи журнала операций
+type RecordSeverity string
+
+const (
+	RecordSeverityInfo      RecordSeverity = "info"
+	RecordSeverityWarning   RecordSeverity = "warning"
+	RecordSeverityEmergency RecordSeverity = "emergency"
+)
+
+// Valid - значение из перечня важностей
+func (s RecordSeverity) Valid() bool {
+	return s == RecordSeverityInfo || s == RecordSeverityWarning || s == RecordSeverityEmergency
+}
+
+// legacyRecordSeverities - свободный текст, который вводили в поле важности до перечня
+var legacyRecordSeverities = map[string]RecordSeverity{
+	"emergency":      RecordSeverityEmergency,
+	"critical":       RecordSeverityEmergency,
+	"high":           RecordSeverityEmergency,
+	"аварийная":      RecordSeverityEmergency,
+	"авария":         RecordSeverityEmergency,
+	"критическая":    RecordSeverityEmergency,
+	"высокая":        RecordSeverityEmergency,
+	"warning":        RecordSeverityWarning,
+	"medium":         RecordSeverityWarning,
+	"major":          RecordSeverityWarning,
+	"предупреждение": RecordSeverityWarning,
+	"внимание":       RecordSeverityWarning,
+	"средняя":        RecordSeverityWarning,
+}
+
+// ParseLegacyRecordSeverity - важность по свободному тексту старых записей; все,
+// что не удалось распознать, и пустое значение считаются информационными
+func ParseLegacyRecordSeverity(value string) RecordSeverity {
+	if severity, ok := legacyRecordSeverities[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return severity
+	}
+	return RecordSeverityInfo
+}
+
+// HistoryFilter - отбор записей журнала операций
+type HistoryFilter struct {
+	Severity RecordSeverity `form:"severity" binding:"omitempty,oneof=info warning emergency"`
+	UserID   string         `form:"userId"`
+}
+
+// HistoryExportQuery - период выгрузки журнала (даты YYYY-MM-DD включительно, не больше года)
+type HistoryExportQuery struct {
+	From     string         `form:"from" binding:"required"`
+	To       string         `form:"to" binding:"required"`
+	Severity RecordSeverity `form:"severity" binding:"omitempty,oneof=info warning emergency"`
+	Format   string         `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
 // ================ API RESPONSE MODELS ================
 
 // GetRuResponse - ответ с данными РУ для API
 type GetRuResponse struct {
 	RuInfo RUInfo `json:"ruInfo"`
 	Cells  []Cell `json:"cells"`
+
+	// Polling - приостановка опроса телеметрии РУ: измерения ячеек не обновляются,
+	// и клиенту не следует считать их устаревшими из-за сбоя связи
+	Polling *PollingPause `json:"polling,omitempty"`
+}
+
+// LastModified - время последнего изменения РУ или любой из его ячеек
+func (r *GetRuResponse) LastModified() time.Time {
+	latest := r.RuInfo.UpdatedAt
+	for _, cell := range r.Cells {
+		if cell.UpdatedAt.After(latest) {
+			latest = cell.UpdatedAt
+		}
+	}
+	return latest
 }
 
 // UpdateCellStatusRequest - запрос на обновление статуса ячейки
 type UpdateCellStatusRequest struct {
-	Status     CellStatus `json:"status"`
+	Status     CellStatus `json:"status" binding:"required,oneof=ON OFF RESERVE ERROR MAINTENANCE"`
 	IsGrounded *bool      `json:"isGrounded,omitempty"`
 }
 
-// AddHistoryRecordRequest - запрос на добавление записи в историю
+// RuntimeQuery - отбор оборудования с наработкой не меньше MinHours
+type RuntimeQuery struct {
+	MinHours *float64 `form:"minHours" binding:"omitempty,min=0"`
+}
+
+// RuntimeReport - наработка РУ, трансформаторов и ячеек на момент запроса, часы
+type RuntimeReport struct {
+	RuID             string               `json:"ruId"`
+	OperationalHours float64              `json:"operationalHours"`
+	Energized        bool                 `json:"energized"`
+	Transformers     []TransformerRuntime `json:"transformers"`
+	Cells            []CellRuntime        `json:"cells"`
+	At               time.Time            `json:"at"`
+}
+
+// TransformerRuntime - наработка трансформатора под напряжением по его ячейке
+type TransformerRuntime struct {
+	TransformerID string  `json:"transformerId"`
+	Name          string  `json:"name"`
+	CellID        *int    `json:"cellId,omitempty"`
+	Hours         float64 `json:"hours"`
+	Running       bool    `json:"running"`
+}
+
+type CellRuntime struct {
+	CellID     int        `json:"cellId"`
+	CellNumber string     `json:"cellNumber"`
+	CellName   string     `json:"cellName"`
+	Type       CellType   `json:"type"`
+	Status     CellStatus `json:"status"`
+	Hours      float64    `json:"hours"`
+	Running    bool       `json:"running"`
+}
+
+// UpdateOperationCounterRequest - ресурс выключателя по данным изготовителя и счетчик
+// операций: Count - показание счетчика самого выключателя при начале учета,
+// Reset - обнуление после замены или капитального ремонта выключателя
+type UpdateOperationCounterRequest struct {
+	OperationLimit *int `json:"operationLimit" binding:"omitempty,min=1,max=1000000"`
+	Count          *int `json:"count" binding:"omitempty,min=0,max=1000000"`
+	Reset          bool `json:"reset"`
+}
+
+// AddHistoryRecordRequest - запрос на добавление записи в историю. Оператор берется из
+// токена автора запроса; поле operator, которое присылают старые клиенты, не учитывается.
 type AddHistoryRecordRequest struct {
-	CellNumber        string  `json:"cellNumber"`
-	CellName          string  `json:"cellName"`
-	Action            string  `json:"action"`
-	Operator          string  `json:"operator"`
-	Timestamp         string  `json:"timestamp"`
-	Reason            *string `json:"reason,omitempty"`
-	DocumentType      *string `json:"documentType,omitempty"`
-	OrderNumber       *string `json:"orderNumber,omitempty"`
-	WorkOrderNumber   *string `json:"workOrderNumber,omitempty"`
-	StartDate         *string `json:"startDate,omitempty"`
-	EndDate           *string `json:"endDate,omitempty"`
-	ResponsiblePerson *string `json:"responsiblePerson,omitempty"`
-	Comment           *string `json:"comment,omitempty"`
-	Severity          *string `json:"severity,omitempty"`
+	CellNumber        string          `json:"cellNumber"`
+	CellName          string          `json:"cellName"`
+	Action            string          `json:"action"`
+	Timestamp         string          `json:"timestamp"`
+	Reason            *string         `json:"reason,omitempty"`
+	DocumentType      *string         `json:"documentType,omitempty"`
+	OrderNumber       *string         `json:"orderNumber,omitempty"`
+	WorkOrderNumber   *string         `json:"workOrderNumber,omitempty"`
+	StartDate         *string         `json:"startDate,omitempty"`
+	EndDate           *string         `json:"endDate,omitempty"`
+	ResponsiblePerson *string         `json:"responsiblePerson,omitempty"`
+	Comment           *string         `json:"comment,omitempty"`
+	Severity          *RecordSeverity `json:"severity,omitempty" binding:"omitempty,oneof=info warning emergency"`
+	// ResponsibleUserID - ответственный пользователь организации РУ; если ResponsiblePerson
+	// не задан, подставляется имя пользователя
+	ResponsibleUserID *string `json:"responsibleUserId,omitempty"`
+
+	// Новый формат дат (RFC 3339). На период перехода принимаются и строковые поля выше.
+	TimestampAt *time.Time `json:"timestampAt,omitempty"`
+	StartDateAt *time.Time `json:"startDateAt,omitempty"`
+	EndDateAt   *time.Time `json:"endDateAt,omitempty"`
+
+	// ClientTime - время отправки по часам устройства, для выявления ушедших часов
+	ClientTime *time.Time `json:"clientTime,omitempty"`
+	// Device - клиент или планшет, заполняется сервером
+	Device string `json:"-"`
 }
 
 // ================ PASSWORD CHANGE MODELS ================
 
 type AdminChangePasswordRequest struct {
-	NewPassword string `json:"newPassword" binding:"required,min=6"`
+	NewPassword string `json:"newPassword" binding:"required,max=128"`
 }
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ NOTIFICATION MODELS ================
+
+type EventCategory string
+
+const (
+	EventCategoryAlarm        EventCategory = "alarm"
+	EventCategoryStatusChange EventCategory = "status_change"
+	EventCategoryWorkPermit   EventCategory = "work_permit"
+	EventCategoryMaintenance  EventCategory = "maintenance"
+	EventCategoryApproval     EventCategory = "approval"
+	EventCategoryMention      EventCategory = "mention"
+)
+
+// NotificationRule - правило маршрутизации уведомлений для РУ.
+// Получатель задается либо ролью (группой), либо конкретным пользователем.
+type NotificationRule struct {
+	ID        string        `json:"id" gorm:"primaryKey"`
+	RuID      string        `json:"ruId" gorm:"index"`
+	Category  EventCategory `json:"category" gorm:"index"`
+	Role      *string       `json:"role,omitempty"`
+	UserID    *string       `json:"userId,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (NotificationRule) TableName() string {
+	return "notification_rules"
+}
+
+// CreateNotificationRuleRequest - запрос на создание правила маршрутизации
+type CreateNotificationRuleRequest struct {
+	Category EventCategory `json:"category" binding:"required,oneof=alarm status_change work_permit maintenance approval"`
+	Role     *string       `json:"role,omitempty" binding:"omitempty,oneof=admin org_admin dispatcher engineer"`
+	UserID   *string       `json:"userId,omitempty"`
+}
+
+// NotificationEvent - событие, передаваемое диспетчеру уведомлений
+type NotificationEvent struct {
+	RuID     string        `json:"ruId"`
+	CellID   int           `json:"cellId,omitempty"`
+	Category EventCategory `json:"category"`
+	Title    string        `json:"title"`
+	Message  string        `json:"message"`
+	// SourceID - ключ источника (например, ID события outbox): повторная доставка
+	// того же события не создает пользователю второе уведомление
+	SourceID string `json:"sourceId"`
+	// UserIDs - адресные получатели (упоминания); правила РУ в этом случае не применяются
+	UserIDs []string `json:"userIds,omitempty"`
+}
+
+const IDPrefixNotification = "ntf"
+
+// Notification - уведомление в центре уведомлений пользователя
+type Notification struct {
+	ID        string        `json:"id" gorm:"primaryKey"`
+	UserID    string        `json:"userId" gorm:"uniqueIndex:idx_notifications_user_source"`
+	SourceID  string        `json:"sourceId" gorm:"uniqueIndex:idx_notifications_user_source"`
+	Category  EventCategory `json:"category" gorm:"index"`
+	RuID      string        `json:"ruId,omitempty"`
+	Title     string        `json:"title"`
+	Message   string        `json:"message"`
+	ReadAt    *time.Time    `json:"readAt,omitempty"`
+	CreatedAt time.Time     `json:"created_at" gorm:"index"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// NotificationFilter - параметры списка уведомлений пользователя
+type NotificationFilter struct {
+	UnreadOnly bool          `form:"unread"`
+	Category   EventCategory `form:"category"`
+	Before     *time.Time    `form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit      int           `form:"limit"`
+}
+
+// NotificationList - страница уведомлений и общее число непрочитанных для значка колокольчика
+type NotificationList struct {
+	Items       []Notification `json:"items"`
+	UnreadCount int64          `json:"unreadCount"`
+}
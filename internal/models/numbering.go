@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ================ DOCUMENT NUMBERING MODELS ================
+
+// Серии номеров, которые ведет сервер. Номер наряда-допуска (WorkOrderNumber) всегда из
+// серии permit; номер распоряжения (OrderNumber) - из серии вида документа записи, а для
+// записей без вида или с нарядом - из серии order.
+const (
+	DocumentTypePermit = "permit"
+	DocumentTypeOrder  = "order"
+)
+
+// DocumentNumberSequence - счетчик серии номеров вида документа организации за год.
+// Строка блокируется на время выдачи номера, поэтому номера идут подряд без повторов.
+type DocumentNumberSequence struct {
+	OrganizationID string    `json:"organizationId" gorm:"primaryKey;default:'default'"`
+	DocumentType   string    `json:"documentType" gorm:"primaryKey"`
+	Year           int       `json:"year" gorm:"primaryKey;autoIncrement:false"`
+	Last           int       `json:"last"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (DocumentNumberSequence) TableName() string {
+	return "document_number_sequences"
+}
+
+// IssuedDocumentNumber - выданный номер документа. Номер выдается до того, как документ
+// попадет в журнал, поэтому выданные, но так и не использованные номера видны как пропуски.
+// Нумерация у каждой организации своя: одинаковые номера разных организаций допустимы.
+type IssuedDocumentNumber struct {
+	ID             int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	OrganizationID string    `json:"organizationId" gorm:"uniqueIndex:idx_issued_document_numbers_org,priority:1;not null;default:'default'"`
+	DocumentType   string    `json:"documentType" gorm:"uniqueIndex:idx_issued_document_numbers_org,priority:2"`
+	Number         string    `json:"number" gorm:"uniqueIndex:idx_issued_document_numbers_org,priority:3"`
+	Year           int       `json:"year"`
+	Seq            int       `json:"seq"`
+	RuID           string    `json:"ruId,omitempty" gorm:"index"`
+	IssuedBy       string    `json:"issuedBy"`
+	IssuedAt       time.Time `json:"issuedAt"`
+}
+
+func (IssuedDocumentNumber) TableName() string {
+	return "issued_document_numbers"
+}
+
+// FormatDocumentNumber - номер документа вида 2025-0147
+func FormatDocumentNumber(year, seq int) string {
+	return fmt.Sprintf("%d-%04d", year, seq)
+}
+
+// IssueDocumentNumberRequest - выдача следующего номера документа, например наряда
+// до начала работ, чтобы все записи по наряду ссылались на один номер. Номер выдается
+// в серии организации РУ, а без РУ - организации пользователя.
+type IssueDocumentNumberRequest struct {
+	DocumentType string `json:"documentType" binding:"required,max=100"`
+	RuID         string `json:"ruId" binding:"max=100"`
+	// OrganizationID задает только администратор установки для номера без РУ, иначе
+	// используется организация пользователя
+	OrganizationID string `json:"organizationId" binding:"max=100"`
+}
+
+// DocumentNumberGapsRequest - параметры проверки пропусков в нумерации
+type DocumentNumberGapsRequest struct {
+	DocumentType string `form:"documentType" binding:"required,max=100"`
+	Year         int    `form:"year" binding:"omitempty,min=2000,max=2100"`
+	// OrganizationID задает только администратор установки, иначе используется
+	// организация пользователя
+	OrganizationID string `form:"organizationId" binding:"max=100"`
+}
+
+// DocumentNumberGaps - пропуски в нумерации вида документа за год. Unused - выданные
+// номера, которых нет ни в одной записи журнала; Missing - номера до последнего
+// выданного, которых нет среди выданных (счетчик или таблица номеров изменены вручную).
+type DocumentNumberGaps struct {
+	OrganizationID string   `json:"organizationId"`
+	DocumentType   string   `json:"documentType"`
+	Year           int      `json:"year"`
+	Last           int      `json:"last"`
+	Issued         int      `json:"issued"`
+	Used           int      `json:"used"`
+	Unused         []string `json:"unused"`
+	Missing        []string `json:"missing"`
+}
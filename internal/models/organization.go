@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DefaultOrganizationID - организация, к которой относятся данные, созданные до
+// разделения на арендаторов, и пользователи, зарегистрировавшиеся самостоятельно
+const DefaultOrganizationID = "default"
+
+const IDPrefixOrganization = "org"
+
+// Organization - арендатор (индустриальная зона). Пользователи, подстанции, РУ и журнал
+// операций принадлежат одной организации и не видны пользователям других организаций.
+type Organization struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Organization) TableName() string {
+	return "organizations"
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,min=2,max=200"`
+}
+
+// AssignSubstationRequest - перенос подстанции вместе с ее РУ и журналом в организацию
+type AssignSubstationRequest struct {
+	SubstationID string `json:"substationId" binding:"required"`
+}
@@ -0,0 +1,130 @@
+package models
+
+import "time"
+
+// ================ PLANNED OUTAGE MODELS ================
+
+const (
+	IDPrefixPlannedOutage        = "outage"
+	IDPrefixConsumerNotification = "cntf"
+)
+
+// OutageStatus - состояние согласования планового отключения
+type OutageStatus string
+
+const (
+	OutageRequested OutageStatus = "requested"
+	OutageApproved  OutageStatus = "approved"
+	OutageRejected  OutageStatus = "rejected"
+	OutageCancelled OutageStatus = "cancelled"
+)
+
+// PlannedOutage - плановое отключение ячеек РУ на время работ. Заявку согласует инженер
+// или администратор (не автор); при согласовании потребителям отключаемых линий
+// ставятся в очередь уведомления, а отключение появляется в календаре.
+type PlannedOutage struct {
+	ID             string       `json:"id" gorm:"primaryKey"`
+	RuID           string       `json:"ruId" gorm:"index"`
+	OrganizationID string       `json:"organizationId" gorm:"index;not null;default:'default'"`
+	StartsAt       time.Time    `json:"startsAt" gorm:"index"`
+	EndsAt         time.Time    `json:"endsAt"`
+	Reason         string       `json:"reason"`
+	Status         OutageStatus `json:"status" gorm:"index"`
+	RequestedBy    string       `json:"requestedBy"`
+	ReviewedBy     *string      `json:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time   `json:"reviewedAt,omitempty"`
+	ReviewComment  string       `json:"reviewComment"`
+	Cells          []OutageCell `json:"cells" gorm:"foreignKey:OutageID"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+func (PlannedOutage) TableName() string {
+	return "planned_outages"
+}
+
+// OutageCell - ячейка, отключаемая по заявке
+type OutageCell struct {
+	OutageID   string `json:"-" gorm:"primaryKey"`
+	CellID     int    `json:"cellId" gorm:"primaryKey"`
+	CellNumber string `json:"cellNumber"`
+	CellName   string `json:"cellName"`
+}
+
+func (OutageCell) TableName() string {
+	return "planned_outage_cells"
+}
+
+// ConsumerNotificationStatus - состояние уведомления потребителя
+type ConsumerNotificationStatus string
+
+const (
+	ConsumerNotificationPending ConsumerNotificationStatus = "pending"
+	ConsumerNotificationSent    ConsumerNotificationStatus = "sent"
+	ConsumerNotificationFailed  ConsumerNotificationStatus = "failed"
+	// ConsumerNotificationNoContact - у потребителя нет email, предупредить нужно по телефону
+	ConsumerNotificationNoContact ConsumerNotificationStatus = "no_contact"
+)
+
+// ConsumerNotification - письмо потребителю о плановом отключении или его отмене;
+// доставляется задачей consumer-notifications
+type ConsumerNotification struct {
+	ID         string                     `json:"id" gorm:"primaryKey"`
+	OutageID   string                     `json:"outageId" gorm:"index"`
+	ConsumerID string                     `json:"consumerId" gorm:"index"`
+	Company    string                     `json:"company"`
+	Recipient  string                     `json:"recipient" mask:"personal_data:view"`
+	Subject    string                     `json:"subject"`
+	Body       string                     `json:"body"`
+	Status     ConsumerNotificationStatus `json:"status" gorm:"index"`
+	Attempts   int                        `json:"attempts"`
+	LastError  string                     `json:"lastError,omitempty"`
+	SentAt     *time.Time                 `json:"sentAt,omitempty"`
+	CreatedAt  time.Time                  `json:"createdAt"`
+	UpdatedAt  time.Time                  `json:"updatedAt"`
+}
+
+func (ConsumerNotification) TableName() string {
+	return "consumer_notifications"
+}
+
+// CreateOutageRequest - заявка на плановое отключение ячеек РУ
+type CreateOutageRequest struct {
+	RuID     string    `json:"ruId" binding:"required"`
+	CellIDs  []int     `json:"cellIds" binding:"required,min=1,max=100"`
+	StartsAt time.Time `json:"startsAt" binding:"required"`
+	EndsAt   time.Time `json:"endsAt" binding:"required"`
+	Reason   string    `json:"reason" binding:"required,min=3,max=1000"`
+}
+
+// ReviewOutageRequest - решение по заявке или причина отмены
+type ReviewOutageRequest struct {
+	Comment string `json:"comment" binding:"max=1000"`
+}
+
+// OutageFilter - критерии отбора плановых отключений
+type OutageFilter struct {
+	RuID   string       `form:"ruId"`
+	Status OutageStatus `form:"status" binding:"omitempty,oneof=requested approved rejected cancelled"`
+	From   *time.Time   `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time   `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// PermitConflict - наряд-допуск на ячейку из заявки, действующий в окне отключения
+type PermitConflict struct {
+	RecordID          string     `json:"recordId"`
+	WorkOrderNumber   string     `json:"workOrderNumber"`
+	CellNumber        string     `json:"cellNumber"`
+	Action            string     `json:"action"`
+	ResponsiblePerson *string    `json:"responsiblePerson,omitempty" mask:"personal_data:view"`
+	StartsAt          *time.Time `json:"startsAt,omitempty"`
+	EndsAt            *time.Time `json:"endsAt,omitempty"`
+}
+
+// OutageDetails - заявка с предупреждениями о нарядах, потребителями и уведомлениями
+type OutageDetails struct {
+	Outage        *PlannedOutage         `json:"outage"`
+	Conflicts     []PermitConflict       `json:"conflicts"`
+	Impact        *OutageImpact          `json:"impact"`
+	Notifications []ConsumerNotification `json:"notifications"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// ================ OVERVIEW READ MODEL ================
+
+// RUOverview - РУ вместе с ячейками и последними операциями
+type RUOverview struct {
+	RUInfo
+	Cells            []Cell            `json:"cells,omitempty"`
+	LatestOperations []OperationRecord `json:"latestOperations,omitempty"`
+}
+
+// OverviewOptions - какие вложенные данные включать в обзор подстанции
+type OverviewOptions struct {
+	IncludeCells      bool
+	IncludeOperations bool
+	OperationsLimit   int
+}
+
+// RUStats - агрегаты РУ для сетки обзора: ячейки по статусам и активные аварии
+type RUStats struct {
+	CellsTotal     int                `json:"cellsTotal"`
+	CellsByStatus  map[CellStatus]int `json:"cellsByStatus"`
+	ActiveAlarms   int                `json:"activeAlarms"`
+	CriticalAlarms int                `json:"criticalAlarms"`
+}
+
+// RUSummary - РУ в списке с необязательными агрегатами (include=stats)
+type RUSummary struct {
+	RUInfo
+	Stats *RUStats `json:"stats,omitempty"`
+}
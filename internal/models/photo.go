@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ PHOTO MODELS ================
+
+const IDPrefixPhoto = "photo"
+
+// PhotoOwnerType - сущность, к которой прикреплена фотография
+type PhotoOwnerType string
+
+const (
+	PhotoOwnerDefect         PhotoOwnerType = "defect"
+	PhotoOwnerInspectionItem PhotoOwnerType = "inspection_item"
+	// PhotoOwnerCell - снимок состояния оборудования ячейки; OwnerID - идентификатор ячейки
+	PhotoOwnerCell PhotoOwnerType = "cell"
+)
+
+// Photo - фотография, прикрепленная к записи. Оригинал хранится в объектном хранилище
+// (StorageKey) или, если хранилище не настроено, в БД; миниатюра - всегда в БД.
+// Содержимое отдается отдельным запросом, в списках возвращаются только метаданные.
+// Время съемки, координаты и камера извлекаются из EXIF на сервере.
+type Photo struct {
+	ID          string         `json:"id" gorm:"primaryKey"`
+	OwnerType   PhotoOwnerType `json:"ownerType" gorm:"index:idx_photos_owner,priority:1"`
+	OwnerID     string         `json:"ownerId" gorm:"index:idx_photos_owner,priority:2"`
+	FileName    string         `json:"fileName"`
+	ContentType string         `json:"contentType"`
+	Size        int64          `json:"size"`
+	Data        []byte         `json:"-"`
+	StorageKey  string         `json:"-"`
+	Thumbnail   []byte         `json:"-"`
+	// HasThumbnail - миниатюра есть (для WebP миниатюра не строится)
+	HasThumbnail bool       `json:"hasThumbnail"`
+	Width        int        `json:"width,omitempty"`
+	Height       int        `json:"height,omitempty"`
+	TakenAt      *time.Time `json:"takenAt,omitempty"`
+	Latitude     *float64   `json:"latitude,omitempty"`
+	Longitude    *float64   `json:"longitude,omitempty"`
+	Camera       string     `json:"camera,omitempty"`
+	UploadedBy   string     `json:"uploadedBy"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (Photo) TableName() string {
+	return "photos"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ POLLING MODELS ================
+
+const IDPrefixPollingPause = "pause"
+
+// PollingPause - приостановка опроса телеметрии: для адаптера протокола, для РУ или для
+// адаптера на конкретном РУ (например, на время обслуживания релейной защиты).
+// Пустое поле означает "любой".
+type PollingPause struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	Adapter   string     `json:"adapter,omitempty" gorm:"uniqueIndex:idx_polling_pause_scope"`
+	RuID      string     `json:"ruId,omitempty" gorm:"uniqueIndex:idx_polling_pause_scope"`
+	Reason    string     `json:"reason"`
+	PausedBy  string     `json:"pausedBy"`
+	PausedAt  time.Time  `json:"pausedAt"`
+	Until     *time.Time `json:"until,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (PollingPause) TableName() string {
+	return "polling_pauses"
+}
+
+// Active - действует ли приостановка в указанный момент
+func (p PollingPause) Active(at time.Time) bool {
+	return p.Until == nil || at.Before(*p.Until)
+}
+
+// PausePollingRequest - запрос на приостановку опроса
+type PausePollingRequest struct {
+	Adapter string     `json:"adapter,omitempty" binding:"omitempty,max=50"`
+	RuID    string     `json:"ruId,omitempty"`
+	Reason  string     `json:"reason" binding:"required,min=3,max=500"`
+	Until   *time.Time `json:"until,omitempty"`
+}
+
+// ResumePollingRequest - запрос на возобновление опроса
+type ResumePollingRequest struct {
+	Adapter string `json:"adapter,omitempty"`
+	RuID    string `json:"ruId,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// ================ WEB PUSH MODELS ================
+
+const IDPrefixPushSubscription = "push"
+
+// PushSubscription - подписка браузера на Web Push: уведомления центра уведомлений
+// приходят и при свернутой вкладке консоли диспетчера. Адрес push-сервиса уникален;
+// повторная подписка того же браузера обновляет ключи и владельца.
+type PushSubscription struct {
+	ID            string     `json:"id" gorm:"primaryKey"`
+	UserID        string     `json:"userId" gorm:"index"`
+	Endpoint      string     `json:"endpoint" gorm:"uniqueIndex"`
+	P256dh        string     `json:"-"`
+	Auth          string     `json:"-"`
+	UserAgent     string     `json:"userAgent"`
+	FailureCount  int        `json:"failureCount"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+func (PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
+
+// CreatePushSubscriptionRequest - PushSubscription.toJSON() из браузера
+type CreatePushSubscriptionRequest struct {
+	Endpoint string               `json:"endpoint" binding:"required,url,max=2000"`
+	Keys     PushSubscriptionKeys `json:"keys" binding:"required"`
+}
+
+// PushSubscriptionKeys - ключи шифрования подписки в base64url
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" binding:"required"`
+	Auth   string `json:"auth" binding:"required"`
+}
+
+// PushPublicKey - открытый ключ VAPID для pushManager.subscribe (applicationServerKey)
+type PushPublicKey struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// PushMessage - содержимое push-уведомления для service worker консоли
+type PushMessage struct {
+	ID        string        `json:"id"`
+	Category  EventCategory `json:"category"`
+	RuID      string        `json:"ruId,omitempty"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ READ-ONLY MODE MODELS ================
+
+// ReadOnlyMode - аварийный режим "только чтение" для всего API (на время аварий в сети
+// или миграции данных). Хранится одной строкой с ID = 1.
+type ReadOnlyMode struct {
+	ID        int        `json:"-" gorm:"primaryKey"`
+	Enabled   bool       `json:"enabled"`
+	Reason    string     `json:"reason,omitempty"`
+	SetBy     string     `json:"setBy,omitempty"`
+	SetAt     *time.Time `json:"setAt,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (ReadOnlyMode) TableName() string {
+	return "read_only_mode"
+}
+
+// SetReadOnlyRequest - включение или выключение режима "только чтение"
+type SetReadOnlyRequest struct {
+	Enabled *bool  `json:"enabled" binding:"required"`
+	Reason  string `json:"reason" binding:"max=500"`
+}
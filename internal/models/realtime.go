@@ -0,0 +1,23 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ================ REALTIME MODELS ================
+
+// RealtimeEvent - доменное событие в потоке /stream. Payload опускается, если событие
+// не помещается в сообщение между экземплярами: клиент перечитывает данные сам.
+type RealtimeEvent struct {
+	ID        string          `json:"id"`
+	Type      DomainEventType `json:"type"`
+	RuID      string          `json:"ruId"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// RealtimeQuery - поток событий одного РУ (по умолчанию - всех доступных РУ)
+type RealtimeQuery struct {
+	RuID string `form:"ruId"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// ================ SELF-REGISTRATION MODELS ================
+
+// EmailVerification - ключ подтверждения адреса из письма; хранится только SHA-256 ключа
+type EmailVerification struct {
+	TokenHash string    `json:"-" gorm:"primaryKey"`
+	UserID    string    `json:"userId" gorm:"index"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (EmailVerification) TableName() string {
+	return "email_verifications"
+}
+
+// RegisterResponse - учетная запись создана и ждет подтверждения почты и одобрения;
+// токен выдается только при входе в активную учетную запись
+type RegisterResponse struct {
+	User    UserResponse `json:"user"`
+	Message string       `json:"message"`
+}
+
+// VerifyEmailRequest - ключ из ссылки в письме
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResendVerificationRequest - повторное письмо с подтверждением адреса
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ApproveUserRequest - одобрение регистрации: роль по умолчанию engineer; организацию
+// задает только администратор установки
+type ApproveUserRequest struct {
+	Role           string `json:"role" binding:"omitempty,oneof=admin org_admin dispatcher engineer"`
+	OrganizationID string `json:"organizationId"`
+}
+
+// RejectUserRequest - отклонение регистрации с причиной
+type RejectUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// PendingUser - регистрация в очереди: ждет подтверждения почты или решения администратора
+type PendingUser struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	OrganizationID  string     `json:"organizationId"`
+	Status          UserStatus `json:"status"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ CELL REVISION MODELS ================
+
+type RevisionReason string
+
+const (
+	RevisionBaseline RevisionReason = "baseline" // состояние до первого изменения
+	RevisionUpdate   RevisionReason = "update"
+	RevisionApproval RevisionReason = "approval"
+	RevisionCritical RevisionReason = "critical"
+	RevisionRestore  RevisionReason = "restore"
+)
+
+const IDPrefixCellRevision = "crev"
+
+// CellRevision - конфигурация ячейки после очередного изменения.
+// Перед первым изменением записывается исходная конфигурация (baseline),
+// поэтому к ней всегда можно вернуться.
+type CellRevision struct {
+	ID       string `json:"id" gorm:"primaryKey"`
+	CellID   int    `json:"cellId" gorm:"uniqueIndex:idx_cell_revisions_cell_revision,priority:1"`
+	RuID     string `json:"ruId" gorm:"index"`
+	Revision int    `json:"revision" gorm:"uniqueIndex:idx_cell_revisions_cell_revision,priority:2"`
+
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Voltage     string `json:"voltage"`
+	IsCritical  bool   `json:"isCritical"`
+
+	Reason       RevisionReason `json:"reason"`
+	ChangedBy    string         `json:"changedBy"`
+	RestoredFrom *int           `json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+
+	// Diff - отличия от предыдущей ревизии
+	Diff []FieldDiff `json:"diff" gorm:"-"`
+}
+
+func (CellRevision) TableName() string {
+	return "cell_revisions"
+}
+
+// NewCellRevision - снимок конфигурационных полей ячейки
+func NewCellRevision(cell *Cell) CellRevision {
+	return CellRevision{
+		CellID:      cell.ID,
+		RuID:        cell.RuID,
+		Name:        cell.Name,
+		Description: cell.Description,
+		Voltage:     cell.Voltage,
+		IsCritical:  cell.IsCritical,
+	}
+}
+
+// SameConfig - совпадают ли конфигурационные поля
+func (r *CellRevision) SameConfig(other *CellRevision) bool {
+	return r.Name == other.Name && r.Description == other.Description &&
+		r.Voltage == other.Voltage && r.IsCritical == other.IsCritical
+}
+
+// BuildDiff - заполняет Diff относительно предыдущей ревизии (nil для первой)
+func (r *CellRevision) BuildDiff(prev *CellRevision) {
+	r.Diff = []FieldDiff{}
+	if prev == nil {
+		return
+	}
+	add := func(field, from, to string) {
+		if from != to {
+			r.Diff = append(r.Diff, FieldDiff{Field: field, From: from, To: to})
+		}
+	}
+	add("name", prev.Name, r.Name)
+	add("description", prev.Description, r.Description)
+	add("voltage", prev.Voltage, r.Voltage)
+	add("isCritical", boolString(prev.IsCritical), boolString(r.IsCritical))
+}
+
+func boolString(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// ================ RU ADMINISTRATION MODELS ================
+
+// UpdateRuRequest - полная замена паспортных данных РУ администратором. Статус, часы
+// работы и организация меняются своими операциями (организация следует за подстанцией).
+// Даты - в формате YYYY-MM-DD, пустая строка очищает дату.
+type UpdateRuRequest struct {
+	Name             string   `json:"name" binding:"required,max=200"`
+	Type             RUType   `json:"type" binding:"required,oneof=KRU TP"`
+	Voltage          string   `json:"voltage" binding:"max=50"`
+	Sections         int      `json:"sections" binding:"min=0"`
+	CellsCount       int      `json:"cellsCount" binding:"min=0"`
+	Transformers     int      `json:"transformers" binding:"min=0"`
+	TransformerPower string   `json:"transformerPower" binding:"max=100"`
+	Location         string   `json:"location" binding:"max=500"`
+	InstallationDate string   `json:"installationDate"`
+	Manufacturer     string   `json:"manufacturer" binding:"max=200"`
+	LastMaintenance  string   `json:"lastMaintenance"`
+	NextMaintenance  string   `json:"nextMaintenance"`
+	LastInspection   string   `json:"lastInspection"`
+	SchemeType       string   `json:"schemeType" binding:"max=200"`
+	TotalLoadHigh    string   `json:"totalLoadHigh" binding:"max=50"`
+	TotalLoadLow     string   `json:"totalLoadLow" binding:"max=50"`
+	TotalPowerHigh   string   `json:"totalPowerHigh" binding:"max=50"`
+	TotalPowerLow    string   `json:"totalPowerLow" binding:"max=50"`
+	MaxCapacityHigh  string   `json:"maxCapacityHigh" binding:"max=50"`
+	MaxCapacityLow   string   `json:"maxCapacityLow" binding:"max=50"`
+	HasHighSide      bool     `json:"hasHighSide"`
+	HasLowSide       bool     `json:"hasLowSide"`
+	BusSections      int      `json:"busSections" binding:"min=0,max=20"`
+	CellsPerSection  int      `json:"cellsPerSection" binding:"min=0"`
+	SubstationID     string   `json:"substationId" binding:"required"`
+	Latitude         *float64 `json:"latitude" binding:"omitempty,gte=-90,lte=90"`
+	Longitude        *float64 `json:"longitude" binding:"omitempty,gte=-180,lte=180"`
+}
+
+// DeleteRuQuery - archive=true архивирует РУ, даже если его можно удалить
+type DeleteRuQuery struct {
+	Archive bool `form:"archive"`
+}
+
+// RuDependencies - то, что ссылается на РУ. Записи журнала (в том числе наряды) и
+// прочие записи (аварийные сигналы, измерения, команды и т.п., по таблицам в Records)
+// не удаляются, поэтому РУ с ними только архивируется; действующие наряды и заземления
+// (LOTO) не дают вывести РУ ни одним из способов.
+type RuDependencies struct {
+	Cells          int            `json:"cells"`
+	HistoryRecords int            `json:"historyRecords"`
+	Permits        int            `json:"permits"`
+	OpenPermits    int            `json:"openPermits"`
+	ActiveLocks    int            `json:"activeLocks"`
+	Records        map[string]int `json:"records,omitempty"`
+}
+
+// DeleteRuOutcome - чем закончилось удаление РУ
+type DeleteRuOutcome string
+
+const (
+	DeleteRuDeleted  DeleteRuOutcome = "deleted"
+	DeleteRuArchived DeleteRuOutcome = "archived"
+)
+
+// DeleteRuResult - итог удаления: РУ удалено вместе с ячейками или архивировано
+type DeleteRuResult struct {
+	RuID         string          `json:"ruId"`
+	Outcome      DeleteRuOutcome `json:"outcome"`
+	Dependencies RuDependencies  `json:"dependencies"`
+}
+
+// MaxCellBatch - наибольшее число ячеек в одном пакете создания
+const MaxCellBatch = 500
+
+// CellNumberPrefix - номера новых ячеек имеют вид «яч.N»
+const CellNumberPrefix = "яч."
+
+// cellNumberInput - номер ячейки, как его вводят: «яч.5», «Яч. 5», «яч 5» или просто «5»
+var cellNumberInput = regexp.MustCompile(`^(?:(?i:яч)\s*\.?\s*)?([1-9][0-9]{0,3})$`)
+
+// NormalizeCellNumber - номер в формате «яч.N»; false, если формат не распознан
+func NormalizeCellNumber(number string) (string, bool) {
+	match := cellNumberInput.FindStringSubmatch(number)
+	if match == nil {
+		return "", false
+	}
+	return CellNumberPrefix + match[1], true
+}
+
+// CellSequence - порядковый номер N ячейки «яч.N»; false для номеров старых форматов
+// («В10-2», «Н04-1», «№3»), которые не участвуют в нумерации
+func CellSequence(number string) (int, bool) {
+	if len(number) <= len(CellNumberPrefix) || number[:len(CellNumberPrefix)] != CellNumberPrefix {
+		return 0, false
+	}
+	n, err := strconv.Atoi(number[len(CellNumberPrefix):])
+	if err != nil || n < 1 {
+		return 0, false
+	}
+	return n, true
+}
+
+// NextCellNumberQuery - сторона напряжения, для которой подбирается номер
+type NextCellNumberQuery struct {
+	VoltageLevel VoltageLevel `form:"voltageLevel" binding:"required,oneof=HIGH LOW"`
+}
+
+// NextCellNumber - следующий свободный номер ячейки на стороне РУ. Номер только
+// предлагается: при создании ячейки без номера он назначается в транзакции.
+type NextCellNumber struct {
+	RuID         string       `json:"ruId"`
+	VoltageLevel VoltageLevel `json:"voltageLevel"`
+	Number       string       `json:"number"`
+}
+
+// CreateCellInput - ячейка пакета создания. РУ берется из пути; без статуса ячейка
+// создается отключенной, без уровня напряжения - на единственной стороне РУ, без
+// номера - со следующим свободным номером «яч.N» этой стороны.
+type CreateCellInput struct {
+	Number            string       `json:"number"`
+	Name              string       `json:"name"`
+	Type              CellType     `json:"type"`
+	Status            CellStatus   `json:"status"`
+	Voltage           string       `json:"voltage"`
+	VoltageLevel      VoltageLevel `json:"voltageLevel"`
+	Power             *string      `json:"power,omitempty"`
+	Description       string       `json:"description"`
+	TransformerNumber *string      `json:"transformerNumber,omitempty"`
+	BusSection        *int         `json:"busSection,omitempty"`
+	IsCritical        bool         `json:"isCritical"`
+	OperationLimit    *int         `json:"operationLimit,omitempty"`
+}
+
+// CellRowError - ошибка строки пакета: номер строки (с нуля), поле и код причины
+type CellRowError struct {
+	Row    int    `json:"row"`
+	Number string `json:"number,omitempty"`
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+}
+
+// Коды ошибок строк пакета ячеек
+const (
+	CellRowRequired          = "required"
+	CellRowInvalid           = "invalid"
+	CellRowFormat            = "format"
+	CellRowSideMissing       = "side_missing"
+	CellRowSectionOutOfRange = "section_out_of_range"
+	CellRowDuplicateInBatch  = "duplicate_in_batch"
+	CellRowDuplicateExisting = "duplicate_existing"
+)
@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestNormalizeCellNumber(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{input: "яч.5", want: "яч.5", ok: true},
+		{input: "Яч. 5", want: "яч.5", ok: true},
+		{input: "ЯЧ 12", want: "яч.12", ok: true},
+		{input: "яч5", want: "яч.5", ok: true},
+		{input: "7", want: "яч.7", ok: true},
+		{input: "9999", want: "яч.9999", ok: true},
+		{input: "10000", ok: false},
+		{input: "0", ok: false},
+		{input: "яч.05", ok: false},
+		{input: "В10-2", ok: false},
+		{input: "№3", ok: false},
+		{input: "яч.", ok: false},
+		{input: "", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := NormalizeCellNumber(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("NormalizeCellNumber(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCellSequence(t *testing.T) {
+	tests := []struct {
+		number string
+		want   int
+		ok     bool
+	}{
+		{number: "яч.1", want: 1, ok: true},
+		{number: "яч.42", want: 42, ok: true},
+		{number: "яч.0", ok: false},
+		{number: "яч.", ok: false},
+		{number: "яч.x", ok: false},
+		{number: "Н04-1", ok: false},
+		{number: "5", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			got, ok := CellSequence(tt.number)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("CellSequence(%q) = %d, %v; want %d, %v", tt.number, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"strings"
+)
+
+// ================ RU STATUS MODELS ================
+
+// RuStatus - статус РУ. Хранится кодом; название для интерфейса - в словаре
+// status.ru.<код>.
+type RuStatus string
+
+const (
+	RuStatusNormal       RuStatus = "normal"
+	RuStatusLimited      RuStatus = "limited"
+	RuStatusMaintenance  RuStatus = "maintenance"
+	RuStatusRepair       RuStatus = "repair"
+	RuStatusEmergency    RuStatus = "emergency"
+	RuStatusOutOfService RuStatus = "out_of_service"
+)
+
+// RuStatuses - словарь статусов РУ для проверки запросов
+var RuStatuses = []RuStatus{
+	RuStatusNormal,
+	RuStatusLimited,
+	RuStatusMaintenance,
+	RuStatusRepair,
+	RuStatusEmergency,
+	RuStatusOutOfService,
+}
+
+func (s RuStatus) Valid() bool {
+	for _, status := range RuStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyRuStatuses - тексты статусов, которые РУ хранили до перехода на коды
+var legacyRuStatuses = map[string]RuStatus{
+	"работает в штатном режиме":   RuStatusNormal,
+	"работает с ограничениями":    RuStatusLimited,
+	"на техническом обслуживании": RuStatusMaintenance,
+	"в ремонте":          RuStatusRepair,
+	"аварийный режим":    RuStatusEmergency,
+	"выведено из работы": RuStatusOutOfService,
+}
+
+// ParseLegacyRuStatus - статус по коду или прежнему тексту; неизвестный текст
+// считается ограниченной работой, чтобы не потерять отметку о проблеме
+func ParseLegacyRuStatus(value string) RuStatus {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if status := RuStatus(value); status.Valid() {
+		return status
+	}
+	if value == "" {
+		return RuStatusNormal
+	}
+	if status, ok := legacyRuStatuses[value]; ok {
+		return status
+	}
+	return RuStatusLimited
+}
+
+// OperationalState - состояние РУ, вычисленное по ячейкам и активным авариям
+type OperationalState string
+
+const (
+	OperationalNormal        OperationalState = "normal"
+	OperationalPartialOutage OperationalState = "partial_outage"
+	OperationalFullOutage    OperationalState = "full_outage"
+	OperationalMaintenance   OperationalState = "maintenance"
+)
+
+// operationalRuStatuses - статус РУ без ручной установки по вычисленному состоянию
+var operationalRuStatuses = map[OperationalState]RuStatus{
+	OperationalNormal:        RuStatusNormal,
+	OperationalPartialOutage: RuStatusLimited,
+	OperationalFullOutage:    RuStatusOutOfService,
+	OperationalMaintenance:   RuStatusMaintenance,
+}
+
+// ComputeOperationalState - состояние РУ по агрегатам. Резервные ячейки не учитываются.
+// Все рабочие ячейки на обслуживании - обслуживание; ни одной включенной - полное
+// отключение; неисправная ячейка или критическая авария - частичное. РУ без рабочих
+// ячеек оценивается только по авариям.
+func ComputeOperationalState(stats *RUStats) OperationalState {
+	if stats == nil {
+		return OperationalNormal
+	}
+	working := stats.CellsTotal - stats.CellsByStatus[CellStatusReserve]
+	maintenance := stats.CellsByStatus[CellStatusMaintenance]
+	switch {
+	case working <= 0:
+		if stats.CriticalAlarms > 0 {
+			return OperationalPartialOutage
+		}
+		return OperationalNormal
+	case maintenance == working:
+		return OperationalMaintenance
+	case stats.CellsByStatus[CellStatusON] == 0:
+		return OperationalFullOutage
+	case stats.CellsByStatus[CellStatusError] > 0, stats.CriticalAlarms > 0:
+		return OperationalPartialOutage
+	case maintenance > 0:
+		return OperationalMaintenance
+	}
+	return OperationalNormal
+}
+
+// ApplyOperationalState - проставляет вычисленное состояние; статус РУ без ручной
+// установки выводится из него
+func (ru *RUInfo) ApplyOperationalState(stats *RUStats) {
+	ru.OperationalState = ComputeOperationalState(stats)
+	if !ru.StatusOverride {
+		ru.Status = operationalRuStatuses[ru.OperationalState]
+	}
+}
+
+// UpdateRuStatusRequest - ручная установка статуса РУ; причина обязательна
+type UpdateRuStatusRequest struct {
+	Status RuStatus `json:"status" binding:"required,rustatus"`
+	Reason string   `json:"reason" binding:"required,min=3,max=500"`
+}
@@ -0,0 +1,21 @@
+package models
+
+// ================ SEARCH MODELS ================
+
+type SearchResultType string
+
+const (
+	SearchResultCell      SearchResultType = "cell"
+	SearchResultOperation SearchResultType = "operation"
+	SearchResultRU        SearchResultType = "ru"
+)
+
+// SearchResult - найденный документ с релевантностью и подсвеченным фрагментом
+type SearchResult struct {
+	Type    SearchResultType `json:"type"`
+	ID      string           `json:"id"`
+	RuID    string           `json:"ruId"`
+	Title   string           `json:"title"`
+	Snippet string           `json:"snippet"`
+	Rank    float64          `json:"rank"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ BUS SECTION MODELS ================
+
+const IDPrefixSwitchingOrder = "swo"
+
+// SectionStatus - состояние секции шин по ее ячейкам (резервные не учитываются)
+type SectionStatus string
+
+const (
+	// SectionEnergized - все рабочие ячейки секции включены
+	SectionEnergized SectionStatus = "energized"
+	// SectionPartial - включена часть ячеек
+	SectionPartial SectionStatus = "partial"
+	// SectionDeEnergized - ни одной включенной ячейки
+	SectionDeEnergized SectionStatus = "de_energized"
+	// SectionMaintenance - все рабочие ячейки на обслуживании
+	SectionMaintenance SectionStatus = "maintenance"
+)
+
+// Section - секция шин одной стороны РУ. Ячейки относятся к секции по номеру
+// (Cell.BusSection) и уровню напряжения; секции создаются по ячейкам автоматически.
+// Состояние и нагрузка вычисляются при чтении.
+type Section struct {
+	ID           int          `json:"id" gorm:"primaryKey;autoIncrement"`
+	RuID         string       `json:"ruId" gorm:"uniqueIndex:idx_sections_ru_level_number,priority:1"`
+	VoltageLevel VoltageLevel `json:"voltageLevel" gorm:"uniqueIndex:idx_sections_ru_level_number,priority:2"`
+	Number       int          `json:"number" gorm:"uniqueIndex:idx_sections_ru_level_number,priority:3"`
+	Name         string       `json:"name"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+
+	Status     SectionStatus `json:"status" gorm:"-"`
+	CellsTotal int           `json:"cellsTotal" gorm:"-"`
+	CellsOn    int           `json:"cellsOn" gorm:"-"`
+	// CurrentA - ток секции по последним значениям ячеек: сумма вводов, а без вводов -
+	// отходящих присоединений
+	CurrentA float64 `json:"currentA" gorm:"-"`
+	// Utilization - загрузка относительно допустимого тока шин, если он задан для РУ
+	Utilization *SectionUtilization `json:"utilization,omitempty" gorm:"-"`
+	Cells       []Cell              `json:"cells,omitempty" gorm:"-"`
+}
+
+func (Section) TableName() string {
+	return "bus_sections"
+}
+
+// SwitchingOperation - операция над секцией целиком
+type SwitchingOperation string
+
+const (
+	SwitchingDeEnergize SwitchingOperation = "de_energize"
+	SwitchingEnergize   SwitchingOperation = "energize"
+)
+
+// SwitchingOrderStatus - состояние бланка переключений
+type SwitchingOrderStatus string
+
+const (
+	SwitchingOrderDraft SwitchingOrderStatus = "draft"
+)
+
+// SwitchingOrder - проект бланка переключений: операции с ячейками, которые нужны, чтобы
+// снять или подать напряжение на секцию, в порядке выполнения. Ячейки не переключаются:
+// бланк выполняет оперативный персонал обычными операциями.
+type SwitchingOrder struct {
+	ID        string               `json:"id" gorm:"primaryKey"`
+	RuID      string               `json:"ruId" gorm:"index"`
+	SectionID int                  `json:"sectionId" gorm:"index"`
+	Operation SwitchingOperation   `json:"operation"`
+	Status    SwitchingOrderStatus `json:"status"`
+	Reason    string               `json:"reason"`
+	CreatedBy string               `json:"createdBy"`
+	CreatedAt time.Time            `json:"created_at"`
+	Steps     []SwitchingStep      `json:"steps" gorm:"foreignKey:OrderID"`
+}
+
+func (SwitchingOrder) TableName() string {
+	return "switching_orders"
+}
+
+// Причины, по которым ячейка секции не вошла в бланк
+const (
+	SwitchingSkipInState     = "already_in_state"
+	SwitchingSkipReserve     = "reserve"
+	SwitchingSkipFault       = "fault"
+	SwitchingSkipMaintenance = "maintenance"
+	SwitchingSkipLocked      = "locked"
+	SwitchingSkipGrounded    = "grounded"
+	SwitchingSkipSectional   = "sectional"
+)
+
+// SwitchingStep - операция бланка. Seq - порядок выполнения; у ячеек, не вошедших в
+// бланк, Seq = 0 и указана причина (SkipReason). Critical - переключение потребует
+// подтверждения, как при обычном переключении критичной ячейки.
+type SwitchingStep struct {
+	ID         int64      `json:"-" gorm:"primaryKey;autoIncrement"`
+	OrderID    string     `json:"-" gorm:"index"`
+	Seq        int        `json:"seq"`
+	CellID     int        `json:"cellId"`
+	CellNumber string     `json:"cellNumber"`
+	CellName   string     `json:"cellName"`
+	CellType   CellType   `json:"cellType"`
+	FromStatus CellStatus `json:"fromStatus"`
+	ToStatus   CellStatus `json:"toStatus,omitempty"`
+	Critical   bool       `json:"critical,omitempty"`
+	SkipReason string     `json:"skipReason,omitempty"`
+}
+
+func (SwitchingStep) TableName() string {
+	return "switching_order_steps"
+}
+
+// SectionSwitchingRequest - снятие или подача напряжения на секцию
+type SectionSwitchingRequest struct {
+	Reason string `json:"reason" binding:"required,min=3,max=1000"`
+}
+
+// UpdateSectionRequest - название секции
+type UpdateSectionRequest struct {
+	Name string `json:"name" binding:"max=100"`
+}
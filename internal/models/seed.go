@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ SEED MODELS ================
+
+// SeedFixture - набор начальных данных, который применяется целиком в одной транзакции:
+// подстанции либо РУ с ячейками
+type SeedFixture struct {
+	Name        string       `json:"-"`
+	Substations []Substation `json:"substations,omitempty"`
+	RU          *RUInfo      `json:"ru,omitempty"`
+	Cells       []Cell       `json:"cells,omitempty"`
+}
+
+// SeedVersion - примененный набор начальных данных: контрольная сумма и содержимое, по
+// которому следующая версия набора применяется как разница
+type SeedVersion struct {
+	Fixture   string    `json:"fixture" gorm:"primaryKey"`
+	Checksum  string    `json:"checksum"`
+	Content   string    `json:"-"`
+	AppliedAt time.Time `json:"appliedAt"`
+}
+
+func (SeedVersion) TableName() string {
+	return "seed_versions"
+}
+
+// SeedOutcome - что сделано с набором при запуске
+type SeedOutcome string
+
+const (
+	SeedApplied   SeedOutcome = "applied"   // набор применен впервые
+	SeedUpdated   SeedOutcome = "updated"   // набор изменился, применена разница
+	SeedUnchanged SeedOutcome = "unchanged" // контрольная сумма совпала
+)
+
+// SeedResult - итог применения набора
+type SeedResult struct {
+	Fixture string
+	Outcome SeedOutcome
+	Created int
+	Updated int
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ================ SETTINGS MODELS ================
+
+// SettingType - тип значения настройки, определяющий разбор и проверку
+type SettingType string
+
+const (
+	SettingString     SettingType = "string"
+	SettingInt        SettingType = "int"
+	SettingBool       SettingType = "bool"
+	SettingStringList SettingType = "string_list"
+	// SettingDuration - длительность в формате Go ("15m", "2h")
+	SettingDuration SettingType = "duration"
+)
+
+// Setting - значение настройки, переопределенное администратором. Value хранится в JSON;
+// настройки без строки в таблице берут значение по умолчанию из кода.
+type Setting struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	Value     string    `json:"value" gorm:"type:text"`
+	UpdatedBy string    `json:"updatedBy"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Setting) TableName() string {
+	return "settings"
+}
+
+// SettingView - настройка для интерфейса администратора
+type SettingView struct {
+	Key         string      `json:"key"`
+	Type        SettingType `json:"type"`
+	Description string      `json:"description"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	Overridden  bool        `json:"overridden"`
+	UpdatedBy   string      `json:"updatedBy,omitempty"`
+	UpdatedAt   *time.Time  `json:"updatedAt,omitempty"`
+}
+
+// UpdateSettingsRequest - новые значения настроек по ключам; null возвращает значение по умолчанию
+type UpdateSettingsRequest struct {
+	Values map[string]json.RawMessage `json:"values" binding:"required,min=1"`
+}
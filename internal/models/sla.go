@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// ================ SLA MODELS ================
+
+// SLAKind - что измеряется: устранение дефекта (open → fixed) или работа по
+// наряду-допуску (выдан → закрыт)
+type SLAKind string
+
+const (
+	SLAKindDefect SLAKind = "defect"
+	SLAKindPermit SLAKind = "permit"
+)
+
+// SLAQuery - период (даты YYYY-MM-DD включительно) по дате заведения дефекта или
+// выдачи наряда, РУ и, для списка нарушений, вид, состояние и число строк
+type SLAQuery struct {
+	From   string  `form:"from" binding:"required"`
+	To     string  `form:"to" binding:"required"`
+	RuID   string  `form:"ruId"`
+	Kind   SLAKind `form:"kind" binding:"omitempty,oneof=defect permit"`
+	Status string  `form:"status" binding:"omitempty,oneof=open closed"`
+	Limit  int     `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+// SLAItem - дефект или наряд-допуск с его сроком. Deadline - срок дефекта (dueDate)
+// или начало плюс норматив важности; nil - норматив для важности не задан.
+// ElapsedSeconds - время в работе до закрытия или до момента отчета.
+type SLAItem struct {
+	Kind           SLAKind    `json:"kind"`
+	ID             string     `json:"id"`
+	RuID           string     `json:"ruId"`
+	CellNumber     string     `json:"cellNumber,omitempty"`
+	Title          string     `json:"title"`
+	Severity       string     `json:"severity"`
+	StartedAt      time.Time  `json:"startedAt"`
+	ClosedAt       *time.Time `json:"closedAt,omitempty"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	ElapsedSeconds int64      `json:"elapsedSeconds"`
+	Breached       bool       `json:"breached"`
+	OverdueSeconds int64      `json:"overdueSeconds,omitempty"`
+}
+
+// SLAStats - показатели SLA. CompliancePercent - доля закрытых в срок среди закрытых
+// с нормативом; MeanTimeToCloseHours - среднее время до закрытия
+type SLAStats struct {
+	TargetHours          *float64 `json:"targetHours,omitempty"`
+	Total                int      `json:"total"`
+	Open                 int      `json:"open"`
+	Closed               int      `json:"closed"`
+	Breached             int      `json:"breached"`
+	OpenBreached         int      `json:"openBreached"`
+	CompliancePercent    *float64 `json:"compliancePercent"`
+	MeanTimeToCloseHours *float64 `json:"meanTimeToCloseHours"`
+}
+
+// SLAKindSummary - показатели вида в целом и по важности
+type SLAKindSummary struct {
+	SLAStats
+	BySeverity map[string]SLAStats `json:"bySeverity"`
+}
+
+// SLASummary - сводка SLA для панели руководителя
+type SLASummary struct {
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Defects     SLAKindSummary `json:"defects"`
+	Permits     SLAKindSummary `json:"permits"`
+}
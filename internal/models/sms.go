@@ -0,0 +1,99 @@
+package models
+
+import "time"
+
+// ================ SMS NOTIFICATION MODELS ================
+
+const IDPrefixSms = "sms"
+
+// SmsKind - повод SMS
+type SmsKind string
+
+const (
+	// SmsKindAlarm - критичная авария
+	SmsKindAlarm SmsKind = "alarm"
+	// SmsKindPermitExpiry - истекает срок наряда-допуска
+	SmsKindPermitExpiry SmsKind = "permit_expiry"
+)
+
+// SmsStatus - состояние SMS: в очереди, принято провайдером, доставлено, не доставлено
+type SmsStatus string
+
+const (
+	SmsQueued    SmsStatus = "queued"
+	SmsSent      SmsStatus = "sent"
+	SmsDelivered SmsStatus = "delivered"
+	SmsFailed    SmsStatus = "failed"
+)
+
+// SmsMessage - SMS сотруднику без смартфона (например, на удаленной подстанции).
+// Сообщение с тем же источником пользователь получает один раз; статус доставки
+// обновляется по данным провайдера задачей sms-notifications.
+type SmsMessage struct {
+	ID                string     `json:"id" gorm:"primaryKey"`
+	UserID            string     `json:"userId" gorm:"uniqueIndex:idx_sms_messages_user_source"`
+	SourceID          string     `json:"sourceId" gorm:"uniqueIndex:idx_sms_messages_user_source"`
+	Kind              SmsKind    `json:"kind" gorm:"index"`
+	RuID              string     `json:"ruId" gorm:"index"`
+	OrganizationID    string     `json:"organizationId" gorm:"index;not null;default:'default'"`
+	Phone             string     `json:"phone" mask:"personal_data:view"`
+	Text              string     `json:"text"`
+	Status            SmsStatus  `json:"status" gorm:"index"`
+	Provider          string     `json:"provider,omitempty"`
+	ProviderMessageID string     `json:"providerMessageId,omitempty"`
+	Segments          int        `json:"segments"`
+	Attempts          int        `json:"attempts"`
+	LastError         string     `json:"lastError,omitempty"`
+	SentAt            *time.Time `json:"sentAt,omitempty"`
+	DeliveredAt       *time.Time `json:"deliveredAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt" gorm:"index"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+func (SmsMessage) TableName() string {
+	return "sms_messages"
+}
+
+// SmsFilter - отбор журнала SMS
+type SmsFilter struct {
+	Status SmsStatus `form:"status" binding:"omitempty,oneof=queued sent delivered failed"`
+	Kind   SmsKind   `form:"kind" binding:"omitempty,oneof=alarm permit_expiry"`
+	Limit  int       `form:"limit"`
+}
+
+// SmsUsageQuery - месяц отчета в формате YYYY-MM; по умолчанию текущий
+type SmsUsageQuery struct {
+	Month string `form:"month"`
+}
+
+// SmsUsageRow - сообщения и части SMS организации по поводу и состоянию
+type SmsUsageRow struct {
+	OrganizationID string    `json:"organizationId"`
+	Kind           SmsKind   `json:"kind"`
+	Status         SmsStatus `json:"status"`
+	Messages       int       `json:"messages"`
+	Segments       int       `json:"segments"`
+}
+
+// SmsUsageReport - расход SMS за месяц: тарифицируются части (segments) сообщений,
+// принятых провайдером
+type SmsUsageReport struct {
+	Month          string                 `json:"month"`
+	Messages       int                    `json:"messages"`
+	Segments       int                    `json:"segments"`
+	ByStatus       map[SmsStatus]int      `json:"byStatus"`
+	ByKind         map[SmsKind]SmsUsage   `json:"byKind"`
+	ByOrganization []SmsOrganizationUsage `json:"byOrganization"`
+}
+
+// SmsUsage - число сообщений и частей
+type SmsUsage struct {
+	Messages int `json:"messages"`
+	Segments int `json:"segments"`
+}
+
+// SmsOrganizationUsage - расход SMS организации
+type SmsOrganizationUsage struct {
+	OrganizationID string `json:"organizationId"`
+	SmsUsage
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ RU SNAPSHOT MODELS ================
+
+// RuSnapshotVersion - версия формата снимка; увеличивается при несовместимых изменениях
+const RuSnapshotVersion = 1
+
+// RuSnapshot - полный снимок конфигурации РУ для переноса между окружениями
+type RuSnapshot struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	RU         RUInfo    `json:"ru"`
+	Cells      []Cell    `json:"cells"`
+}
+
+type ImportStrategy string
+
+const (
+	ImportSkip      ImportStrategy = "skip"      // существующее РУ не трогаем
+	ImportOverwrite ImportStrategy = "overwrite" // обновляем РУ и ячейки (по номеру ячейки)
+	ImportNewID     ImportStrategy = "new-id"    // создаем копию РУ под новым ID
+)
+
+// ImportRuRequest - восстановление РУ из снимка
+type ImportRuRequest struct {
+	Strategy ImportStrategy `json:"strategy" binding:"required,oneof=skip overwrite new-id"`
+	NewID    *string        `json:"newId,omitempty" binding:"omitempty,min=1,max=64"`
+	Snapshot RuSnapshot     `json:"snapshot" binding:"required"`
+}
+
+type ImportOutcome string
+
+const (
+	ImportCreated     ImportOutcome = "created"
+	ImportSkipped     ImportOutcome = "skipped"
+	ImportOverwritten ImportOutcome = "overwritten"
+)
+
+// ImportRuResult - итог импорта
+type ImportRuResult struct {
+	RuID         string         `json:"ruId"`
+	Strategy     ImportStrategy `json:"strategy"`
+	Outcome      ImportOutcome  `json:"outcome"`
+	CellsCreated int            `json:"cellsCreated"`
+	CellsUpdated int            `json:"cellsUpdated"`
+	// CellsUnmatched - номера существующих ячеек, которых нет в снимке (не удаляются)
+	CellsUnmatched []string `json:"cellsUnmatched"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ================ SYSTEM USAGE STATISTICS MODELS ================
+
+// SystemStats - статистика использования системы для планирования мощностей и отчетности
+type SystemStats struct {
+	GeneratedAt      time.Time         `json:"generatedAt"`
+	Users            UserStats         `json:"users"`
+	OperationsPerDay []DailyOperations `json:"operationsPerDay"`
+	MostActiveRUs    []RuActivity      `json:"mostActiveRus"`
+	Storage          AttachmentStorage `json:"storage"`
+}
+
+// UserStats - пользователи всего и по ролям (роли без пользователей - с нулем)
+type UserStats struct {
+	Total  int              `json:"total"`
+	ByRole map[UserRole]int `json:"byRole"`
+}
+
+// DailyOperations - операции журнала за сутки (дата YYYY-MM-DD)
+type DailyOperations struct {
+	Date       string `json:"date"`
+	Operations int    `json:"operations"`
+}
+
+// RuActivity - число операций журнала РУ за период статистики
+type RuActivity struct {
+	RuID       string `json:"ruId"`
+	Name       string `json:"name"`
+	Operations int    `json:"operations"`
+}
+
+// AttachmentStorage - объем вложений по видам, байты
+type AttachmentStorage struct {
+	Photos     AttachmentUsage `json:"photos"`
+	Comtrade   AttachmentUsage `json:"comtrade"`
+	TotalBytes int64           `json:"totalBytes"`
+}
+
+// AttachmentUsage - число вложений и их объем: Bytes - оригиналы, из них DatabaseBytes
+// в БД и ExternalBytes во внешнем хранилище; ThumbnailBytes - миниатюры (в БД)
+type AttachmentUsage struct {
+	Count          int   `json:"count"`
+	Bytes          int64 `json:"bytes"`
+	DatabaseBytes  int64 `json:"databaseBytes"`
+	ExternalBytes  int64 `json:"externalBytes"`
+	ThumbnailBytes int64 `json:"thumbnailBytes"`
+}
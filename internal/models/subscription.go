@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ SUBSCRIPTION MODELS ================
+
+const IDPrefixSubscription = "sub"
+
+type SubscriptionSource string
+
+const (
+	// SubscriptionManual - пользователь подписался сам
+	SubscriptionManual SubscriptionSource = "manual"
+	// SubscriptionSubstation - подписка по умолчанию для роли: диспетчер следит за РУ
+	// закрепленных за ним подстанций
+	SubscriptionSubstation SubscriptionSource = "substation"
+)
+
+// Subscription - подписка пользователя на РУ или отдельную ячейку. Пользователь с
+// подписками получает уведомления по РУ только для отслеживаемых РУ и ячеек.
+type Subscription struct {
+	ID     string `json:"id,omitempty" gorm:"primaryKey"`
+	UserID string `json:"userId" gorm:"uniqueIndex:idx_subscriptions_user_target"`
+	RuID   string `json:"ruId" gorm:"uniqueIndex:idx_subscriptions_user_target"`
+	// CellID - 0, если подписка на все РУ
+	CellID    int                `json:"cellId,omitempty" gorm:"uniqueIndex:idx_subscriptions_user_target"`
+	Source    SubscriptionSource `json:"source" gorm:"-"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// Matches - относится ли событие РУ (и ячейки, если она известна) к подписке.
+// Подписка на ячейку не охватывает события РУ в целом.
+func (s Subscription) Matches(ruID string, cellID int) bool {
+	if s.RuID != ruID {
+		return false
+	}
+	return s.CellID == 0 || s.CellID == cellID
+}
+
+// CreateSubscriptionRequest - подписка на РУ или ячейку
+type CreateSubscriptionRequest struct {
+	RuID   string `json:"ruId" binding:"required"`
+	CellID int    `json:"cellId,omitempty" binding:"omitempty,min=1"`
+}
+
+// UserSubstation - подстанция, закрепленная за пользователем
+type UserSubstation struct {
+	UserID       string    `json:"userId" gorm:"primaryKey"`
+	SubstationID string    `json:"substationId" gorm:"primaryKey"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (UserSubstation) TableName() string {
+	return "user_substations"
+}
+
+// SetUserSubstationsRequest - полный список закрепленных подстанций; пустой список снимает закрепление
+type SetUserSubstationsRequest struct {
+	SubstationIDs []string `json:"substationIds" binding:"required"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ DAILY SUMMARY MODELS ================
+
+// DailySummary - сводка диспетчера по подстанции за сутки для утреннего отчета
+type DailySummary struct {
+	SubstationID   string            `json:"substationId"`
+	SubstationName string            `json:"substationName"`
+	Date           string            `json:"date"`
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	Operations     OperationsSummary `json:"operations"`
+	Switching      SwitchingSummary  `json:"switching"`
+	Alarms         AlarmsSummary     `json:"alarms"`
+	Permits        PermitsSummary    `json:"permits"`
+	PeakLoads      []PeakLoad        `json:"peakLoads"`
+}
+
+// OperationsSummary - записи журнала операций за сутки. Severity - важность, по которой
+// отобраны записи (пусто - все); BySeverity считается по всем записям суток.
+// ByDocumentType - по кодам справочника видов документов, записи без документа не входят.
+type OperationsSummary struct {
+	Total          int                    `json:"total"`
+	Severity       RecordSeverity         `json:"severity,omitempty"`
+	ByAction       map[string]int         `json:"byAction"`
+	BySeverity     map[RecordSeverity]int `json:"bySeverity"`
+	ByDocumentType map[string]int         `json:"byDocumentType"`
+}
+
+// DailySummaryRequest - параметры суточной сводки
+type DailySummaryRequest struct {
+	Date     string         `form:"date"`
+	Severity RecordSeverity `form:"severity" binding:"omitempty,oneof=info warning emergency"`
+}
+
+// SwitchingSummary - переключения ячеек: всего смен статуса, сколько ячеек
+// переключалось и в какие статусы
+type SwitchingSummary struct {
+	Switches int                `json:"switches"`
+	Cells    int                `json:"cells"`
+	ByStatus map[CellStatus]int `json:"byStatus"`
+}
+
+// AlarmsSummary - аварии, возникшие за сутки, и квитированные за сутки
+type AlarmsSummary struct {
+	Raised     int                   `json:"raised"`
+	BySeverity map[AlarmSeverity]int `json:"bySeverity"`
+	Cleared    int                   `json:"cleared"`
+}
+
+// PermitsSummary - наряды-допуски, открытые и закрытые за сутки
+type PermitsSummary struct {
+	Opened int `json:"opened"`
+	Closed int `json:"closed"`
+}
+
+// PeakLoad - максимальная нагрузка РУ за сутки (по часовым агрегатам)
+type PeakLoad struct {
+	RuID   string    `json:"ruId"`
+	RuName string    `json:"ruName"`
+	CellID int       `json:"cellId"`
+	Value  float64   `json:"value"`
+	Hour   time.Time `json:"hour"`
+}
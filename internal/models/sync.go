@@ -0,0 +1,115 @@
+package models
+
+import "time"
+
+// ================ OFFLINE SYNC MODELS ================
+
+// SyncChangesQuery - лента изменений с курсора; пустой курсор - полная выгрузка
+type SyncChangesQuery struct {
+	Since string   `form:"since"`
+	RuIDs []string `form:"ruId"`
+}
+
+// SyncChanges - изменения для планшета. Cursor передается в следующий запрос;
+// при HasMore изменения забираются повторным запросом сразу. Записи могут
+// повторяться между страницами - клиент сохраняет их по ID.
+type SyncChanges struct {
+	Cursor      string            `json:"cursor"`
+	HasMore     bool              `json:"hasMore"`
+	ServerTime  time.Time         `json:"serverTime"`
+	RUs         []RUInfo          `json:"rus"`
+	Cells       []Cell            `json:"cells"`
+	Operations  []OperationRecord `json:"operations"`
+	Inspections []Inspection      `json:"inspections"`
+}
+
+// SyncOperation - операция, выполненная без связи: переключение ячейки и/или запись
+// журнала. BaseUpdatedAt - версия ячейки, которую видел планшет; если ячейку с тех пор
+// меняли на сервере, переключение не применяется и возвращается конфликт.
+type SyncOperation struct {
+	ClientID      string                   `json:"clientId" binding:"required,max=100"`
+	RuID          string                   `json:"ruId" binding:"required"`
+	CellID        *int                     `json:"cellId,omitempty" binding:"required_with=Status"`
+	Status        *CellStatus              `json:"status,omitempty" binding:"omitempty,oneof=ON OFF RESERVE ERROR MAINTENANCE"`
+	IsGrounded    *bool                    `json:"isGrounded,omitempty"`
+	BaseUpdatedAt *time.Time               `json:"baseUpdatedAt,omitempty" binding:"required_with=Status"`
+	Record        *AddHistoryRecordRequest `json:"record,omitempty"`
+}
+
+// SyncInspection - осмотр РУ, проведенный без связи
+type SyncInspection struct {
+	ClientID   string                  `json:"clientId" binding:"required,max=100"`
+	RuID       string                  `json:"ruId" binding:"required"`
+	Inspection SubmitInspectionRequest `json:"inspection" binding:"required"`
+}
+
+// SyncUploadRequest - пакет изменений, накопленных планшетом без связи
+type SyncUploadRequest struct {
+	DeviceID string `json:"deviceId" binding:"required,max=100"`
+	// ClientTime - время отправки пакета по часам планшета; переносится в записи журнала
+	ClientTime  *time.Time       `json:"clientTime,omitempty"`
+	Operations  []SyncOperation  `json:"operations" binding:"max=500,dive"`
+	Inspections []SyncInspection `json:"inspections" binding:"max=100,dive"`
+}
+
+// SyncItemKind - тип элемента пакета синхронизации
+type SyncItemKind string
+
+const (
+	SyncItemOperation  SyncItemKind = "operation"
+	SyncItemInspection SyncItemKind = "inspection"
+)
+
+// SyncOutcome - итог обработки элемента пакета
+type SyncOutcome string
+
+const (
+	SyncApplied SyncOutcome = "applied"
+	// SyncDuplicate - элемент уже принят в предыдущей попытке отправки
+	SyncDuplicate SyncOutcome = "duplicate"
+	// SyncPending - переключение критичной ячейки ждет подтверждения вторым сотрудником
+	SyncPending SyncOutcome = "pending_confirmation"
+	// SyncConflict - ячейку изменили на сервере после BaseUpdatedAt; действует серверное состояние
+	SyncConflict SyncOutcome = "conflict"
+	SyncRejected SyncOutcome = "rejected"
+)
+
+// SyncItem - принятый элемент пакета; повторная отправка того же ClientID не дублирует запись
+type SyncItem struct {
+	ClientID  string       `json:"clientId" gorm:"primaryKey"`
+	Kind      SyncItemKind `json:"kind"`
+	ServerID  string       `json:"serverId"`
+	DeviceID  string       `json:"deviceId" gorm:"index"`
+	UserID    string       `json:"userId" gorm:"index"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+func (SyncItem) TableName() string {
+	return "sync_items"
+}
+
+// SyncItemError - причина конфликта или отказа
+type SyncItemError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SyncItemResult - итог по элементу пакета; при конфликте - текущее состояние ячейки на сервере
+type SyncItemResult struct {
+	ClientID string         `json:"clientId"`
+	Kind     SyncItemKind   `json:"kind"`
+	Outcome  SyncOutcome    `json:"outcome"`
+	ServerID string         `json:"serverId,omitempty"`
+	Error    *SyncItemError `json:"error,omitempty"`
+	Cell     *Cell          `json:"cell,omitempty"`
+}
+
+// SyncUploadReport - отчет о разборе пакета
+type SyncUploadReport struct {
+	Applied    int              `json:"applied"`
+	Duplicates int              `json:"duplicates"`
+	Pending    int              `json:"pending"`
+	Conflicts  int              `json:"conflicts"`
+	Rejected   int              `json:"rejected"`
+	Items      []SyncItemResult `json:"items"`
+}
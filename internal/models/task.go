@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ TASK INBOX MODELS ================
+
+type TaskType string
+
+const (
+	TaskTypeMaintenance    TaskType = "maintenance"
+	TaskTypeWorkPermit     TaskType = "work_permit"
+	TaskTypeDefect         TaskType = "defect"
+	TaskTypeApproval       TaskType = "approval"
+	TaskTypeAcknowledgment TaskType = "acknowledgement"
+	// TaskTypeBreakerWear - выключатель выработал ресурс коммутационных операций
+	TaskTypeBreakerWear TaskType = "breaker_wear"
+)
+
+// Task - элемент личной очереди задач пользователя
+type Task struct {
+	Type     TaskType   `json:"type"`
+	RefID    string     `json:"refId"`
+	Title    string     `json:"title"`
+	RuID     string     `json:"ruId,omitempty"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Overdue  bool       `json:"overdue"`
+}
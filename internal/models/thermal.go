@@ -0,0 +1,128 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ THERMOGRAPHY MODELS ================
+
+const IDPrefixThermalSnapshot = "thermal"
+
+// PhotoOwnerThermalSnapshot - термограмма обхода; OwnerID - идентификатор снимка
+const PhotoOwnerThermalSnapshot PhotoOwnerType = "thermal_snapshot"
+
+// ThermalBand - оценка нагрева точки по превышению температуры
+type ThermalBand string
+
+const (
+	ThermalNormal   ThermalBand = "normal"
+	ThermalWarning  ThermalBand = "warning"
+	ThermalCritical ThermalBand = "critical"
+)
+
+// ThermalBandRank - порядок оценок по тяжести
+var ThermalBandRank = map[ThermalBand]int{
+	ThermalNormal:   0,
+	ThermalWarning:  1,
+	ThermalCritical: 2,
+}
+
+// ThermalSnapshot - снимок тепловизора ячейки при тепловизионном обходе: температура
+// воздуха и нагретые точки (контакты, зажимы), найденные на термограмме. MaxTemperature
+// и Band - по самой нагретой и самой тяжелой точке, для трендов без разбора точек.
+type ThermalSnapshot struct {
+	ID             string      `json:"id" gorm:"primaryKey"`
+	RuID           string      `json:"ruId" gorm:"index"`
+	CellID         int         `json:"cellId" gorm:"index:idx_thermal_snapshots_cell_time,priority:1"`
+	CellNumber     string      `json:"cellNumber"`
+	AmbientTemp    float64     `json:"ambientTemp"`
+	MaxTemperature float64     `json:"maxTemperature"`
+	Band           ThermalBand `json:"band" gorm:"index"`
+	// LoadPercent - нагрузка присоединения во время съемки: нагрев при малой нагрузке опаснее
+	LoadPercent *float64  `json:"loadPercent,omitempty"`
+	Emissivity  *float64  `json:"emissivity,omitempty"`
+	Camera      string    `json:"camera,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	TakenBy     string    `json:"takenBy"`
+	TakenAt     time.Time `json:"takenAt" gorm:"index:idx_thermal_snapshots_cell_time,priority:2"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Hotspots []ThermalHotspot `json:"hotspots" gorm:"foreignKey:SnapshotID"`
+	Photos   []Photo          `json:"photos" gorm:"-"`
+}
+
+func (ThermalSnapshot) TableName() string {
+	return "thermal_snapshots"
+}
+
+// ThermalHotspot - нагретая точка на термограмме. DeltaAmbient - превышение над
+// температурой воздуха; DeltaReference - над такой же точкой соседней фазы, если она
+// измерена (ReferenceTemp). Оценка - худшая из двух по порогам настроек thermal.*.
+type ThermalHotspot struct {
+	ID         int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	SnapshotID string `json:"snapshotId" gorm:"index"`
+	// Point - точка измерения, например "шина ВН, фаза A, верхний контакт"
+	Point          string      `json:"point"`
+	Phase          string      `json:"phase,omitempty"`
+	Temperature    float64     `json:"temperature"`
+	ReferenceTemp  *float64    `json:"referenceTemp,omitempty"`
+	DeltaAmbient   float64     `json:"deltaAmbient"`
+	DeltaReference *float64    `json:"deltaReference,omitempty"`
+	Band           ThermalBand `json:"band"`
+}
+
+func (ThermalHotspot) TableName() string {
+	return "thermal_hotspots"
+}
+
+// ThermalHotspotInput - нагретая точка, найденная на термограмме
+type ThermalHotspotInput struct {
+	Point         string   `json:"point" binding:"required,max=200"`
+	Phase         string   `json:"phase" binding:"omitempty,oneof=A B C N"`
+	Temperature   float64  `json:"temperature" binding:"min=-50,max=1000"`
+	ReferenceTemp *float64 `json:"referenceTemp,omitempty" binding:"omitempty,min=-50,max=1000"`
+}
+
+// CreateThermalSnapshotRequest - результаты съемки ячейки тепловизором; сама термограмма
+// прикрепляется отдельным запросом как фотография
+type CreateThermalSnapshotRequest struct {
+	AmbientTemp float64               `json:"ambientTemp" binding:"min=-60,max=60"`
+	LoadPercent *float64              `json:"loadPercent,omitempty" binding:"omitempty,min=0,max=200"`
+	Emissivity  *float64              `json:"emissivity,omitempty" binding:"omitempty,gt=0,max=1"`
+	Camera      string                `json:"camera" binding:"max=100"`
+	Notes       string                `json:"notes" binding:"max=2000"`
+	TakenAt     time.Time             `json:"takenAt" binding:"required"`
+	Hotspots    []ThermalHotspotInput `json:"hotspots" binding:"required,min=1,max=50,dive"`
+}
+
+// ThermalFilter - период снимков ячейки
+type ThermalFilter struct {
+	From *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ThermalTrendSample - значение точки или ячейки на одном снимке
+type ThermalTrendSample struct {
+	SnapshotID     string      `json:"snapshotId"`
+	TakenAt        time.Time   `json:"takenAt"`
+	Temperature    float64     `json:"temperature"`
+	DeltaAmbient   float64     `json:"deltaAmbient"`
+	DeltaReference *float64    `json:"deltaReference,omitempty"`
+	Band           ThermalBand `json:"band"`
+}
+
+// ThermalPointTrend - ряд значений одной точки по снимкам. RatePerMonth - наклон
+// превышения над воздухом, °C в месяц (МНК), если снимков хотя бы два.
+type ThermalPointTrend struct {
+	Point        string               `json:"point"`
+	Phase        string               `json:"phase,omitempty"`
+	Samples      []ThermalTrendSample `json:"samples"`
+	RatePerMonth *float64             `json:"ratePerMonth,omitempty"`
+}
+
+// ThermalTrend - динамика нагрева ячейки: максимум по каждому снимку и ряды по точкам
+type ThermalTrend struct {
+	CellID int                  `json:"cellId"`
+	Max    []ThermalTrendSample `json:"max"`
+	Points []ThermalPointTrend  `json:"points"`
+}
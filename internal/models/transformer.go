@@ -0,0 +1,163 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ TRANSFORMER MODELS ================
+
+const IDPrefixTransformer = "tr"
+
+// Transformer - силовой трансформатор РУ. Связан с ячейками сторон ВН и НН (по ним
+// берется ток нагрузки) и с ячейкой, метрика temperature которой - температура верхних
+// слоев масла. Трансформаторы создаются по ячейкам типа TRANSFORMER с номером
+// трансформатора; паспортные данные уточняются вручную.
+type Transformer struct {
+	ID            string  `json:"id" gorm:"primaryKey"`
+	RuID          string  `json:"ruId" gorm:"uniqueIndex:idx_transformers_ru_name,priority:1"`
+	Name          string  `json:"name" gorm:"uniqueIndex:idx_transformers_ru_name,priority:2"`
+	RatedPowerKVA float64 `json:"ratedPowerKva" mask:"capacity:view"`
+	HighVoltageKV float64 `json:"highVoltageKv"`
+	LowVoltageKV  float64 `json:"lowVoltageKv"`
+	// TapPosition - положение переключателя ответвлений (ПБВ/РПН); изменения
+	// записываются в журнал переключений (TapChange)
+	TapPosition  *int       `json:"tapPosition,omitempty"`
+	TapChangedAt *time.Time `json:"tapChangedAt,omitempty"`
+	// TapCellID - ячейка, от шлюза которой приходит положение переключателя;
+	// без нее используется ячейка стороны ВН
+	TapCellID     *int      `json:"tapCellId,omitempty"`
+	HighCellID    *int      `json:"highCellId,omitempty"`
+	LowCellID     *int      `json:"lowCellId,omitempty"`
+	OilTempCellID *int      `json:"oilTempCellId,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Loading *TransformerLoading `json:"loading,omitempty" gorm:"-"`
+	Aging   *TransformerAging   `json:"aging,omitempty" gorm:"-"`
+}
+
+func (Transformer) TableName() string {
+	return "transformers"
+}
+
+// TransformerLoading - текущая загрузка и тепловой режим по модели IEC 60076-7.
+// LoadFactor - отношение тока к номинальному току стороны (K). Температура масла берется
+// из телеметрии, а без нее рассчитывается по температуре воздуха (TopOilMeasured=false);
+// без наблюдений погоды принимается 20 °C (AmbientAssumed).
+type TransformerLoading struct {
+	Side           VoltageLevel `json:"side"`
+	CurrentA       float64      `json:"currentA"`
+	RatedCurrentA  float64      `json:"ratedCurrentA"`
+	LoadFactor     float64      `json:"loadFactor"`
+	Percent        float64      `json:"percent"`
+	MeasuredAt     *time.Time   `json:"measuredAt,omitempty"`
+	AmbientTemp    float64      `json:"ambientTemp"`
+	AmbientAssumed bool         `json:"ambientAssumed,omitempty"`
+	TopOilTemp     float64      `json:"topOilTemp"`
+	TopOilMeasured bool         `json:"topOilMeasured"`
+	HotSpotTemp    float64      `json:"hotSpotTemp"`
+	// AgingRate - относительная скорость старения изоляции (1 - при 98 °C в наиболее
+	// нагретой точке)
+	AgingRate float64 `json:"agingRate"`
+}
+
+// TransformerAging - износ изоляции за период по часовым агрегатам тока: эквивалентный
+// коэффициент старения (FEQA) и потеря срока службы в часах и в процентах от нормального
+// срока службы изоляции
+type TransformerAging struct {
+	From              time.Time `json:"from"`
+	To                time.Time `json:"to"`
+	Hours             int       `json:"hours"`
+	EquivalentAging   float64   `json:"equivalentAging"`
+	LossOfLifeHours   float64   `json:"lossOfLifeHours"`
+	LossOfLifePercent float64   `json:"lossOfLifePercent"`
+}
+
+// TransformerQuery - период расчета износа изоляции, сутки
+type TransformerQuery struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=365"`
+}
+
+// UpdateTransformerRequest - паспортные данные и привязка трансформатора к ячейкам
+type UpdateTransformerRequest struct {
+	RatedPowerKVA *float64 `json:"ratedPowerKva" binding:"omitempty,gt=0"`
+	HighVoltageKV *float64 `json:"highVoltageKv" binding:"omitempty,gt=0"`
+	LowVoltageKV  *float64 `json:"lowVoltageKv" binding:"omitempty,gt=0"`
+	TapPosition   *int     `json:"tapPosition" binding:"omitempty,min=-50,max=50"`
+	TapCellID     *int     `json:"tapCellId"`
+	HighCellID    *int     `json:"highCellId"`
+	LowCellID     *int     `json:"lowCellId"`
+	OilTempCellID *int     `json:"oilTempCellId"`
+}
+
+// TapCellOrHigh - ячейка, по которой телеметрия сопоставляется с переключателем
+func (t *Transformer) TapCellOrHigh() *int {
+	if t.TapCellID != nil {
+		return t.TapCellID
+	}
+	return t.HighCellID
+}
+
+// TapChangeSource - откуда известно положение переключателя
+type TapChangeSource string
+
+const (
+	TapChangeManual    TapChangeSource = "manual"
+	TapChangeTelemetry TapChangeSource = "telemetry"
+)
+
+// TapChange - переключение ответвления трансформатора. FromPosition пусто, если
+// положение до переключения не было известно.
+type TapChange struct {
+	ID            int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	TransformerID string          `json:"transformerId" gorm:"index:idx_tap_changes_transformer_time,priority:1"`
+	RuID          string          `json:"ruId" gorm:"index"`
+	FromPosition  *int            `json:"fromPosition,omitempty"`
+	ToPosition    int             `json:"toPosition"`
+	Source        TapChangeSource `json:"source"`
+	Reason        *string         `json:"reason,omitempty"`
+	ChangedBy     string          `json:"changedBy"`
+	ChangedAt     time.Time       `json:"changedAt" gorm:"index:idx_tap_changes_transformer_time,priority:2"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (TapChange) TableName() string {
+	return "transformer_tap_changes"
+}
+
+// SetTapPositionRequest - ручная запись положения переключателя
+type SetTapPositionRequest struct {
+	Position  *int       `json:"position" binding:"required,min=-50,max=50"`
+	Reason    string     `json:"reason" binding:"required,min=3,max=500"`
+	ChangedAt *time.Time `json:"changedAt,omitempty"`
+}
+
+// TapChangeFilter - отбор журнала переключений трансформатора
+type TapChangeFilter struct {
+	Source TapChangeSource `form:"source" binding:"omitempty,oneof=manual telemetry"`
+	From   *time.Time      `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time      `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// RecordTapPositionsRequest - пакет положений переключателей от шлюза телеметрии.
+// Трансформатор определяется по ячейке (TapCellID, а без нее - ячейка стороны ВН).
+type RecordTapPositionsRequest struct {
+	Positions []TapPositionInput `json:"positions" binding:"required,min=1,max=1000,dive"`
+}
+
+type TapPositionInput struct {
+	RuID       string    `json:"ruId" binding:"required"`
+	CellID     int       `json:"cellId" binding:"required"`
+	Position   *int      `json:"position" binding:"required,min=-50,max=50"`
+	MeasuredAt time.Time `json:"measuredAt" binding:"required"`
+}
+
+// TapIngestReport - итог приема пакета: записанные переключения, повторы текущего
+// положения, значения старше последнего известного и ячейки без трансформатора
+type TapIngestReport struct {
+	Recorded  int         `json:"recorded"`
+	Unchanged int         `json:"unchanged"`
+	Stale     int         `json:"stale"`
+	Unmapped  int         `json:"unmapped"`
+	Changes   []TapChange `json:"changes"`
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ COMPUTER VISION READING MODELS ================
+
+const IDPrefixVisionReading = "vision"
+
+// VisionReading - показание стрелочного прибора или индикатора ячейки, распознанное
+// сервисом компьютерного зрения по снимку. Показание с достаточной уверенностью
+// записывается в телеметрию (MeasurementID) и проходит тот же путь, что и данные шлюза:
+// агрегаты, базовые линии и аварии. Остальные хранятся для проверки и дообучения модели.
+type VisionReading struct {
+	ID         string            `json:"id" gorm:"primaryKey"`
+	RuID       string            `json:"ruId" gorm:"index"`
+	CellID     int               `json:"cellId" gorm:"index:idx_vision_readings_cell_time,priority:1"`
+	Metric     MeasurementMetric `json:"metric"`
+	Gauge      string            `json:"gauge,omitempty"`
+	Value      float64           `json:"value"`
+	Confidence float64           `json:"confidence"`
+	// ImageRef - ссылка на исходный снимок (ключ в хранилище сервиса распознавания или URL)
+	ImageRef     string    `json:"imageRef"`
+	ModelVersion string    `json:"modelVersion,omitempty"`
+	CapturedAt   time.Time `json:"capturedAt" gorm:"index:idx_vision_readings_cell_time,priority:2"`
+	ExternalID   *string   `json:"externalId,omitempty" gorm:"uniqueIndex"`
+	// Accepted - уверенность не ниже порога vision.min_confidence_percent на момент приема
+	Accepted      bool      `json:"accepted"`
+	MeasurementID *int64    `json:"measurementId,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (VisionReading) TableName() string {
+	return "vision_readings"
+}
+
+// VisionReadingInput - распознанное показание. ExternalID - идентификатор распознавания
+// в сервисе; повторная передача не создает дубликат.
+type VisionReadingInput struct {
+	RuID         string            `json:"ruId" binding:"required"`
+	CellID       int               `json:"cellId" binding:"required"`
+	Metric       MeasurementMetric `json:"metric" binding:"required,oneof=current temperature load"`
+	Gauge        string            `json:"gauge" binding:"max=100"`
+	Value        float64           `json:"value"`
+	Confidence   float64           `json:"confidence" binding:"min=0,max=1"`
+	ImageRef     string            `json:"imageRef" binding:"required,max=500"`
+	ModelVersion string            `json:"modelVersion" binding:"max=100"`
+	CapturedAt   time.Time         `json:"capturedAt" binding:"required"`
+	ExternalID   *string           `json:"externalId,omitempty" binding:"omitempty,max=100"`
+}
+
+// RecordVisionReadingsRequest - пакет показаний от сервиса распознавания
+type RecordVisionReadingsRequest struct {
+	Readings []VisionReadingInput `json:"readings" binding:"required,min=1,max=1000,dive"`
+}
+
+// VisionIngestReport - итог приема пакета: сколько показаний ушло в телеметрию,
+// сколько отсеяно по уверенности и сколько уже было принято раньше
+type VisionIngestReport struct {
+	Accepted       int             `json:"accepted"`
+	BelowThreshold int             `json:"belowThreshold"`
+	Duplicates     int             `json:"duplicates"`
+	MinConfidence  float64         `json:"minConfidence"`
+	Readings       []VisionReading `json:"readings"`
+}
+
+// VisionReadingFilter - отбор распознанных показаний ячейки
+type VisionReadingFilter struct {
+	Metric   MeasurementMetric `form:"metric" binding:"omitempty,oneof=current temperature load"`
+	Accepted *bool             `form:"accepted"`
+	From     *time.Time        `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time        `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// ================ INDICATOR LAMP RECONCILIATION ================
+
+const IDPrefixVisionIndication = "vind"
+
+// IndicationResult - итог сверки распознанного положения с журналом
+type IndicationResult string
+
+const (
+	// IndicationMatch - положение на панели совпадает со статусом ячейки в журнале
+	IndicationMatch IndicationResult = "match"
+	// IndicationMismatch - расхождение: журнал не соответствует состоянию на панели
+	IndicationMismatch IndicationResult = "mismatch"
+	// IndicationStale - после снимка по ячейке была операция, снимок устарел
+	IndicationStale IndicationResult = "stale"
+	// IndicationSkipped - статус ячейки не сравнивается (ERROR) или ячейка удалена
+	IndicationSkipped IndicationResult = "skipped"
+)
+
+// VisionIndication - положение ячейки (ВКЛ/ОТКЛ) по лампам-индикаторам на панели,
+// распознанное по снимку. Задача vision-reconciliation сверяет его со статусом ячейки
+// в журнале и при расхождении поднимает аварию discrepancy.
+type VisionIndication struct {
+	ID     string `json:"id" gorm:"primaryKey"`
+	RuID   string `json:"ruId" gorm:"index"`
+	CellID int    `json:"cellId" gorm:"index:idx_vision_indications_cell_time,priority:1"`
+	// Indication - ON или OFF по индикаторам
+	Indication   CellStatus `json:"indication"`
+	Confidence   float64    `json:"confidence"`
+	ImageRef     string     `json:"imageRef"`
+	ModelVersion string     `json:"modelVersion,omitempty"`
+	CapturedAt   time.Time  `json:"capturedAt" gorm:"index:idx_vision_indications_cell_time,priority:2"`
+	ExternalID   *string    `json:"externalId,omitempty" gorm:"uniqueIndex"`
+	// Accepted - уверенность не ниже порога vision.min_confidence_percent; сверяются только принятые
+	Accepted bool `json:"accepted"`
+	// Результат сверки; пусто, пока индикация не сверена
+	Result         IndicationResult `json:"result,omitempty"`
+	RecordedStatus CellStatus       `json:"recordedStatus,omitempty"`
+	ReconciledAt   *time.Time       `json:"reconciledAt,omitempty" gorm:"index"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+func (VisionIndication) TableName() string {
+	return "vision_indications"
+}
+
+// VisionIndicationInput - распознанное положение ячейки по индикаторам
+type VisionIndicationInput struct {
+	RuID         string     `json:"ruId" binding:"required"`
+	CellID       int        `json:"cellId" binding:"required"`
+	Indication   CellStatus `json:"indication" binding:"required,oneof=ON OFF"`
+	Confidence   float64    `json:"confidence" binding:"min=0,max=1"`
+	ImageRef     string     `json:"imageRef" binding:"required,max=500"`
+	ModelVersion string     `json:"modelVersion" binding:"max=100"`
+	CapturedAt   time.Time  `json:"capturedAt" binding:"required"`
+	ExternalID   *string    `json:"externalId,omitempty" binding:"omitempty,max=100"`
+}
+
+// RecordVisionIndicationsRequest - пакет индикаций от сервиса распознавания
+type RecordVisionIndicationsRequest struct {
+	Indications []VisionIndicationInput `json:"indications" binding:"required,min=1,max=1000,dive"`
+}
+
+// VisionIndicationReport - итог приема пакета индикаций
+type VisionIndicationReport struct {
+	Accepted       int                `json:"accepted"`
+	BelowThreshold int                `json:"belowThreshold"`
+	Duplicates     int                `json:"duplicates"`
+	MinConfidence  float64            `json:"minConfidence"`
+	Indications    []VisionIndication `json:"indications"`
+}
+
+// VisionIndicationFilter - отбор индикаций ячейки
+type VisionIndicationFilter struct {
+	Result IndicationResult `form:"result" binding:"omitempty,oneof=match mismatch stale skipped"`
+	From   *time.Time       `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To     *time.Time       `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+)
+
+// ================ VOLTAGE QUALITY MODELS ================
+
+const (
+	// NominalPhaseVoltage - номинальное фазное напряжение сети 0,4 кВ, В
+	NominalPhaseVoltage = 230.0
+	// VoltageTolerancePercent - допустимое отклонение напряжения (ГОСТ 32144-2013), %
+	VoltageTolerancePercent = 10.0
+)
+
+// VoltagePhases - фазы в порядке выгрузки
+var VoltagePhases = []string{"A", "B", "C"}
+
+// VoltageMeasurement - фазные напряжения ячейки стороны НН. OutOfRange - хотя бы одна
+// фаза вне допустимого отклонения от номинала.
+type VoltageMeasurement struct {
+	CellID     int       `json:"cellId" gorm:"primaryKey"`
+	MeasuredAt time.Time `json:"measuredAt" gorm:"primaryKey;index:idx_voltage_measurements_ru_time,priority:2"`
+	RuID       string    `json:"ruId" gorm:"index:idx_voltage_measurements_ru_time,priority:1"`
+	PhaseA     float64   `json:"phaseA"`
+	PhaseB     float64   `json:"phaseB"`
+	PhaseC     float64   `json:"phaseC"`
+	OutOfRange bool      `json:"outOfRange"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (VoltageMeasurement) TableName() string {
+	return "voltage_measurements"
+}
+
+// Phase - напряжение фазы по имени (A, B, C)
+func (m *VoltageMeasurement) Phase(phase string) float64 {
+	switch phase {
+	case "A":
+		return m.PhaseA
+	case "B":
+		return m.PhaseB
+	default:
+		return m.PhaseC
+	}
+}
+
+// RecordVoltagesRequest - пакет фазных напряжений от шлюза телеметрии
+type RecordVoltagesRequest struct {
+	Measurements []VoltageInput `json:"measurements" binding:"required,min=1,max=5000,dive"`
+}
+
+// VoltageInput - фазные напряжения, В; 0 - потеря фазы
+type VoltageInput struct {
+	RuID       string    `json:"ruId" binding:"required"`
+	CellID     int       `json:"cellId" binding:"required"`
+	PhaseA     *float64  `json:"phaseA" binding:"required,min=0,max=1000"`
+	PhaseB     *float64  `json:"phaseB" binding:"required,min=0,max=1000"`
+	PhaseC     *float64  `json:"phaseC" binding:"required,min=0,max=1000"`
+	MeasuredAt time.Time `json:"measuredAt" binding:"required"`
+}
+
+// VoltageQualityQuery - период отчета (даты YYYY-MM-DD включительно), ячейка и формат
+type VoltageQualityQuery struct {
+	From   string `form:"from" binding:"required"`
+	To     string `form:"to" binding:"required"`
+	CellID *int   `form:"cellId"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// VoltageDailySummary - суточная сводка напряжения ячейки. Compliant - все измерения
+// всех фаз в пределах допустимого отклонения.
+type VoltageDailySummary struct {
+	Date       string                `json:"date"`
+	RuID       string                `json:"ruId"`
+	CellID     int                   `json:"cellId"`
+	CellNumber string                `json:"cellNumber"`
+	CellName   string                `json:"cellName"`
+	Samples    int                   `json:"samples"`
+	Phases     []VoltagePhaseSummary `json:"phases"`
+	Compliant  bool                  `json:"compliant"`
+}
+
+// VoltagePhaseSummary - минимум, максимум и среднее фазы за сутки; WithinPercent - доля
+// измерений в пределах допустимого отклонения
+type VoltagePhaseSummary struct {
+	Phase         string  `json:"phase"`
+	Min           float64 `json:"min"`
+	Max           float64 `json:"max"`
+	Avg           float64 `json:"avg"`
+	OutOfRange    int     `json:"outOfRange"`
+	WithinPercent float64 `json:"withinPercent"`
+}
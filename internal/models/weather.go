@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// ================ WEATHER MODELS ================
+
+// WeatherSourceManual - наблюдение, внесенное вручную (с метеостанции подстанции или сводки)
+const WeatherSourceManual = "manual"
+
+// WeatherObservation - температура воздуха у подстанции; одно наблюдение на момент времени
+type WeatherObservation struct {
+	SubstationID string    `json:"substationId" gorm:"primaryKey"`
+	ObservedAt   time.Time `json:"observedAt" gorm:"primaryKey"`
+	Temperature  float64   `json:"temperature"`
+	Humidity     *float64  `json:"humidity,omitempty"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+func (WeatherObservation) TableName() string {
+	return "weather_observations"
+}
+
+// RecordWeatherRequest - ручной ввод погодных наблюдений
+type RecordWeatherRequest struct {
+	Observations []WeatherInput `json:"observations" binding:"required,min=1,max=1000,dive"`
+}
+
+type WeatherInput struct {
+	SubstationID string    `json:"substationId" binding:"required"`
+	Temperature  *float64  `json:"temperature" binding:"required,gte=-60,lte=70"`
+	Humidity     *float64  `json:"humidity" binding:"omitempty,gte=0,lte=100"`
+	ObservedAt   time.Time `json:"observedAt" binding:"required"`
+}
+
+// LoadTemperaturePoint - часовая точка нагрузки ячейки и температуры воздуха у подстанции
+type LoadTemperaturePoint struct {
+	Hour        time.Time `json:"hour"`
+	Avg         float64   `json:"avg"`
+	Max         float64   `json:"max"`
+	Temperature *float64  `json:"temperature"`
+}
+
+// LoadTemperatureSeries - ряд нагрузка/температура для исследований загрузки трансформаторов
+type LoadTemperatureSeries struct {
+	RuID         string                 `json:"ruId"`
+	CellID       int                    `json:"cellId"`
+	SubstationID string                 `json:"substationId"`
+	Metric       MeasurementMetric      `json:"metric"`
+	From         time.Time              `json:"from"`
+	To           time.Time              `json:"to"`
+	Points       []LoadTemperaturePoint `json:"points"`
+}
@@ -0,0 +1,88 @@
+package permissions
+
+import (
+	"sync"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+// Permission - право на просмотр или действие, выдаваемое ролям
+type Permission string
+
+const (
+	// ViewPersonalData - ФИО ответственных, телефоны, контакты потребителей
+	ViewPersonalData Permission = "personal_data:view"
+	// ViewCapacity - точные значения мощности и пропускной способности
+	ViewCapacity Permission = "capacity:view"
+
+	// HistoryRead - просмотр журнала операций
+	HistoryRead Permission = "history:read"
+	// HistoryWrite - новые записи и исправления журнала
+	HistoryWrite Permission = "history:write"
+	// HistoryExport - выгрузка журнала за период
+	HistoryExport Permission = "history:export"
+	// HistoryBackdate - записи задним числом (время операции раньше допуска записи)
+	HistoryBackdate Permission = "history:backdate"
+)
+
+// Set - набор прав роли
+type Set map[Permission]bool
+
+// Has - есть ли право в наборе
+func (s Set) Has(p Permission) bool {
+	return s[p]
+}
+
+// defaultRolePermissions - права ролей по умолчанию; переопределяются конфигурацией
+var defaultRolePermissions = map[models.UserRole][]Permission{
+	models.RoleAdmin:      {ViewPersonalData, ViewCapacity, HistoryRead, HistoryWrite, HistoryExport, HistoryBackdate},
+	models.RoleOrgAdmin:   {ViewPersonalData, ViewCapacity, HistoryRead, HistoryWrite, HistoryExport, HistoryBackdate},
+	models.RoleEngineer:   {ViewPersonalData, ViewCapacity, HistoryRead, HistoryWrite, HistoryExport},
+	models.RoleDispatcher: {ViewPersonalData, HistoryRead, HistoryWrite},
+}
+
+var (
+	mu              sync.RWMutex
+	rolePermissions = build(defaultRolePermissions)
+)
+
+func build(source map[models.UserRole][]Permission) map[models.UserRole]Set {
+	result := make(map[models.UserRole]Set, len(source))
+	for role, perms := range source {
+		set := make(Set, len(perms))
+		for _, p := range perms {
+			set[p] = true
+		}
+		result[role] = set
+	}
+	return result
+}
+
+// Configure - переопределяет права указанных ролей, остальные роли сохраняют права по умолчанию
+func Configure(overrides map[string][]string) {
+	merged := make(map[models.UserRole][]Permission, len(defaultRolePermissions))
+	for role, perms := range defaultRolePermissions {
+		merged[role] = perms
+	}
+	for role, perms := range overrides {
+		list := make([]Permission, 0, len(perms))
+		for _, p := range perms {
+			list = append(list, Permission(p))
+		}
+		merged[models.UserRole(role)] = list
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	rolePermissions = build(merged)
+}
+
+// ForRole - права роли. Неизвестная роль или анонимный запрос не имеют прав.
+func ForRole(role string) Set {
+	mu.RLock()
+	defer mu.RUnlock()
+	if set, ok := rolePermissions[models.UserRole(role)]; ok {
+		return set
+	}
+	return Set{}
+}
@@ -0,0 +1,77 @@
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+// PostgresBridge - мост через LISTEN/NOTIFY PostgreSQL. NOTIFY отправляется через общий
+// пул соединений, а LISTEN держит отдельное соединение, которое нельзя отдавать
+// PgBouncer в режиме transaction: подписка живет в сессии сервера.
+type PostgresBridge struct {
+	db      *gorm.DB
+	dsn     string
+	channel string
+}
+
+func NewPostgresBridge(db *gorm.DB, dsn, channel string) *PostgresBridge {
+	return &PostgresBridge{db: db, dsn: dsn, channel: channel}
+}
+
+// Publish - NOTIFY в канал; размер сообщения PostgreSQL ограничивает 8000 байтами
+func (b *PostgresBridge) Publish(ctx context.Context, data []byte) error {
+	if err := b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", b.channel, string(data)).Error; err != nil {
+		return fmt.Errorf("failed to notify %s: %w", b.channel, err)
+	}
+	return nil
+}
+
+// Listen - слушает канал и переподключается с нарастающей паузой при обрыве соединения
+func (b *PostgresBridge) Listen(ctx context.Context, handle func(data []byte)) {
+	backoff := listenMinBackoff
+	for ctx.Err() == nil {
+		err := b.listen(ctx, handle, func() { backoff = listenMinBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("⚠️ Realtime bridge: %v; reconnecting in %s", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+}
+
+func (b *PostgresBridge) listen(ctx context.Context, handle func(data []byte), connected func()) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", b.channel, err)
+	}
+	connected()
+	log.Printf("📡 Realtime bridge listening on PostgreSQL channel %s", b.channel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("connection lost: %w", err)
+		}
+		handle([]byte(notification.Payload))
+	}
+}
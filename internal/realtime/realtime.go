@@ -0,0 +1,39 @@
+// Package realtime - передача событий для потоковых клиентов между экземплярами API.
+// Событие, обработанное одним экземпляром, должно дойти до клиентов, подключенных к
+// любому другому, поэтому каждый экземпляр публикует события в мост и слушает его.
+package realtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TypeNone     = ""
+	TypePostgres = "postgres"
+)
+
+// Bridge - мост событий между экземплярами. Доставка "не более одного раза": сообщения,
+// отправленные, пока экземпляр переподключается, до его клиентов не доходят.
+type Bridge interface {
+	Publish(ctx context.Context, data []byte) error
+	// Listen - вызывает handle для каждого сообщения, в том числе отправленного этим
+	// экземпляром; завершается при отмене контекста
+	Listen(ctx context.Context, handle func(data []byte))
+}
+
+// New - создает мост выбранного типа. Для пустого типа возвращает nil: события
+// доходят только до клиентов этого экземпляра.
+func New(bridgeType string, db *gorm.DB, listenDSN, channel string) (Bridge, error) {
+	switch strings.ToLower(bridgeType) {
+	case TypeNone:
+		return nil, nil
+	case TypePostgres:
+		return NewPostgresBridge(db, listenDSN, channel), nil
+	default:
+		return nil, fmt.Errorf("unknown realtime bridge %q", bridgeType)
+	}
+}
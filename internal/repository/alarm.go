@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlarmRepository struct {
+	db *gorm.DB
+}
+
+func NewAlarmRepository(db *gorm.DB) *AlarmRepository {
+	return &AlarmRepository{db: db}
+}
+
+// applyAlarmFilter - общие условия отбора аварий
+func applyAlarmFilter(query *gorm.DB, filter models.AlarmFilter) *gorm.DB {
+	query = scopeOrganizationRUs(query, "ru_id", filter.OrganizationID)
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.Priority != 0 {
+		query = query.Where("priority BETWEEN 1 AND ?", filter.Priority)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Before != nil {
+		query = query.Where("raised_at < ?", *filter.Before)
+	}
+	if filter.After != nil {
+		query = query.Where("raised_at >= ?", *filter.After)
+	}
+	switch filter.Visibility {
+	case models.AlarmVisibilityAll:
+	case models.AlarmVisibilityHidden:
+		query = query.Where("(shelved_until > ? OR suppressed_by IS NOT NULL)", time.Now())
+	default:
+		query = query.Where("(shelved_until IS NULL OR shelved_until <= ?) AND suppressed_by IS NULL", time.Now())
+	}
+	return query
+}
+
+// CreateAlarm - создает аварию вместе с событиями outbox; повторная доставка того же
+// события игнорируется (false), и события при этом не записываются
+func (r *AlarmRepository) CreateAlarm(alarm *models.Alarm, events ...models.OutboxEvent) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).Create(alarm)
+		if result.Error != nil {
+			return fmt.Errorf("failed to create alarm: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return appendOutbox(tx, events)
+	})
+	return created, err
+}
+
+func (r *AlarmRepository) GetAlarmByID(id string) (*models.Alarm, error) {
+	var alarm models.Alarm
+	if err := r.db.Where("id = ?", id).First(&alarm).Error; err != nil {
+		return nil, err
+	}
+	return &alarm, nil
+}
+
+// GetOrganizationAlarm - авария РУ организации; пустая организация - любая авария
+func (r *AlarmRepository) GetOrganizationAlarm(id, organizationID string) (*models.Alarm, error) {
+	var alarm models.Alarm
+	query := scopeOrganizationRUs(r.db.Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&alarm).Error; err != nil {
+		return nil, err
+	}
+	return &alarm, nil
+}
+
+// ShelveAlarm - откладывает активную аварию до until; false - активной аварии нет
+func (r *AlarmRepository) ShelveAlarm(id string, until time.Time, by, reason string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.Alarm{}).
+		Where("id = ? AND status = ?", id, models.AlarmStatusActive).
+		Updates(map[string]interface{}{
+			"shelved_until": until,
+			"shelved_by":    by,
+			"shelve_reason": reason,
+			"updated_at":    at,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to shelve alarm: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UnshelveAlarm - возвращает отложенную аварию в рабочий список. С expiredAt снимается
+// только истекшее откладывание, чтобы не отменить продление, сделанное диспетчером
+// одновременно с задачей планировщика.
+func (r *AlarmRepository) UnshelveAlarm(id string, expiredAt *time.Time, at time.Time) (bool, error) {
+	query := r.db.Model(&models.Alarm{}).Where("id = ? AND shelved_until IS NOT NULL", id)
+	if expiredAt != nil {
+		query = query.Where("shelved_until <= ?", *expiredAt)
+	}
+	result := query.Updates(map[string]interface{}{
+		"shelved_until": nil,
+		"shelved_by":    nil,
+		"shelve_reason": nil,
+		"updated_at":    at,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to unshelve alarm: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetExpiredShelves - аварии, срок откладывания которых истек к моменту at
+func (r *AlarmRepository) GetExpiredShelves(at time.Time) ([]models.Alarm, error) {
+	var alarms []models.Alarm
+	if err := r.db.Where("shelved_until <= ?", at).Order("shelved_until ASC").Find(&alarms).Error; err != nil {
+		return nil, fmt.Errorf("failed to get expired alarm shelves: %w", err)
+	}
+	return alarms, nil
+}
+
+// GetSuppressedAlarms - аварии, подавленные нарядами-допусками
+func (r *AlarmRepository) GetSuppressedAlarms() ([]models.Alarm, error) {
+	var alarms []models.Alarm
+	if err := r.db.Where("suppressed_by IS NOT NULL").Order("raised_at ASC").Find(&alarms).Error; err != nil {
+		return nil, fmt.Errorf("failed to get suppressed alarms: %w", err)
+	}
+	return alarms, nil
+}
+
+// FindOpenPermit - наряд-допуск, открытый по ячейке РУ в момент at (как в проверке
+// конфликтов отключений); nil - наряда нет
+func (r *AlarmRepository) FindOpenPermit(ruID, cellNumber string, at time.Time) (*models.OperationRecord, error) {
+	var records []models.OperationRecord
+	result := r.db.Where("ru_id = ? AND cell_number = ? AND work_order_number IS NOT NULL", ruID, cellNumber).
+		Where("start_date_at IS NOT NULL OR end_date_at IS NOT NULL").
+		Where("(start_date_at IS NULL OR start_date_at <= ?) AND (end_date_at IS NULL OR end_date_at > ?)", at, at).
+		Order("start_date_at DESC").
+		Limit(1).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find open permit: %w", result.Error)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return &records[0], nil
+}
+
+// Unsuppress - снимает подавление аварии нарядом permitID; false - подавление уже снято
+func (r *AlarmRepository) Unsuppress(id, permitID string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.Alarm{}).
+		Where("id = ? AND suppressed_by = ?", id, permitID).
+		Updates(map[string]interface{}{
+			"suppressed_by":         nil,
+			"suppressed_work_order": nil,
+			"updated_at":            at,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to unsuppress alarm: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// BackfillAlarmAnnunciation - приоритет и звуковой сигнал аварий, зарегистрированных
+// до их появления, по источнику и важности
+func BackfillAlarmAnnunciation(db *gorm.DB) error {
+	type group struct {
+		Kind     models.AlarmKind
+		Severity models.AlarmSeverity
+	}
+	var groups []group
+	err := db.Model(&models.Alarm{}).Where("priority IS NULL OR priority = 0").
+		Distinct("kind", "severity").Find(&groups).Error
+	if err != nil {
+		return fmt.Errorf("failed to load alarms without priority: %w", err)
+	}
+
+	var updated int64
+	for _, g := range groups {
+		priority, audible := models.AlarmAnnunciation(g.Kind, g.Severity)
+		result := db.Model(&models.Alarm{}).
+			Where("(priority IS NULL OR priority = 0) AND kind = ? AND severity = ?", g.Kind, g.Severity).
+			UpdateColumns(map[string]interface{}{"priority": priority, "audible_class": audible})
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill alarm priority: %w", result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	if updated > 0 {
+		log.Printf("✅ Backfilled priority for %d alarms", updated)
+	}
+	return nil
+}
+
+func (r *AlarmRepository) GetAlarms(filter models.AlarmFilter, limit int) ([]models.Alarm, error) {
+	var alarms []models.Alarm
+	query := applyAlarmFilter(r.db.Model(&models.Alarm{}), filter).Order("raised_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&alarms).Error; err != nil {
+		return nil, fmt.Errorf("failed to get alarms: %w", err)
+	}
+	return alarms, nil
+}
+
+// GetAlarmStatsRows - аварии РУ организации, возникшие в интервале [from, to), с названием
+// РУ, по времени возникновения; отложенные и подавленные аварии тоже учитываются
+func (r *AlarmRepository) GetAlarmStatsRows(ruID, organizationID string, from, to time.Time) ([]models.AlarmStatsRow, error) {
+	var rows []models.AlarmStatsRow
+	query := r.db.Model(&models.Alarm{}).
+		Joins("LEFT JOIN ru_infos ON ru_infos.id = alarms.ru_id").
+		Select("alarms.ru_id, COALESCE(ru_infos.name, '') AS ru_name, alarms.cell_number, alarms.kind, alarms.severity, alarms.raised_at, alarms.acknowledged_at").
+		Where("alarms.raised_at >= ? AND alarms.raised_at < ?", from, to)
+	if ruID != "" {
+		query = query.Where("alarms.ru_id = ?", ruID)
+	}
+	query = scopeOrganizationRUs(query, "alarms.ru_id", organizationID)
+	if err := query.Order("alarms.raised_at ASC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get alarms for statistics: %w", err)
+	}
+	return rows, nil
+}
+
+// GetEscalations - история эскалации нескольких аварий одним запросом
+func (r *AlarmRepository) GetEscalations(alarmIDs []string) ([]models.AlarmEscalation, error) {
+	escalations := []models.AlarmEscalation{}
+	if len(alarmIDs) == 0 {
+		return escalations, nil
+	}
+	result := r.db.Where("alarm_id IN ?", alarmIDs).Order("step ASC").Find(&escalations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get alarm escalations: %w", result.Error)
+	}
+	return escalations, nil
+}
+
+// CreateEscalation - записывает шаг эскалации; шаг, уже выполненный другим
+// экземпляром, не записывается повторно
+func (r *AlarmRepository) CreateEscalation(escalation *models.AlarmEscalation) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "alarm_id"}, {Name: "step"}},
+		DoNothing: true,
+	}).Create(escalation)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create alarm escalation: %w", result.Error)
+	}
+	return nil
+}
+
+// AcknowledgeAlarms - квитирует активные аварии по фильтру и/или списку идентификаторов
+func (r *AlarmRepository) AcknowledgeAlarms(filter models.AlarmFilter, ids []string, by, comment string, at time.Time) (int64, error) {
+	filter.Status = models.AlarmStatusActive
+	// Аварии, перечисленные явно, квитируются и тогда, когда они отложены или подавлены
+	if len(ids) > 0 && filter.Visibility == "" {
+		filter.Visibility = models.AlarmVisibilityAll
+	}
+	query := applyAlarmFilter(r.db.Model(&models.Alarm{}), filter)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":          models.AlarmStatusAcknowledged,
+		"acknowledged_at": at,
+		"acknowledged_by": by,
+		"ack_comment":     comment,
+		"updated_at":      at,
+	})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to acknowledge alarms: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *AlarmRepository) GetSavedFilters(userID string) ([]models.SavedAlarmFilter, error) {
+	var filters []models.SavedAlarmFilter
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&filters).Error; err != nil {
+		return nil, fmt.Errorf("failed to get saved alarm filters: %w", err)
+	}
+	return filters, nil
+}
+
+func (r *AlarmRepository) CreateSavedFilter(filter *models.SavedAlarmFilter) error {
+	if err := r.db.Create(filter).Error; err != nil {
+		return fmt.Errorf("failed to create saved alarm filter: %w", err)
+	}
+	return nil
+}
+
+func (r *AlarmRepository) DeleteSavedFilter(userID, filterID string) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", filterID, userID).Delete(&models.SavedAlarmFilter{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete saved alarm filter: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnomalyRepository struct {
+	db *gorm.DB
+}
+
+func NewAnomalyRepository(db *gorm.DB) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+// GetBaselines - базовые линии всех ячеек
+func (r *AnomalyRepository) GetBaselines() ([]models.CellBaseline, error) {
+	var baselines []models.CellBaseline
+	if err := r.db.Find(&baselines).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell baselines: %w", err)
+	}
+	return baselines, nil
+}
+
+// GetBaseline - базовая линия ячейки по метрике; nil, если показаний еще не было
+func (r *AnomalyRepository) GetBaseline(cellID int, metric models.MeasurementMetric) (*models.CellBaseline, error) {
+	var baselines []models.CellBaseline
+	if err := r.db.Where("cell_id = ? AND metric = ?", cellID, metric).Limit(1).Find(&baselines).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell baseline: %w", err)
+	}
+	if len(baselines) == 0 {
+		return nil, nil
+	}
+	return &baselines[0], nil
+}
+
+// SaveDetection - обновленные базовые линии, оценки минутных агрегатов и события
+// об аномалиях в одной транзакции
+func (r *AnomalyRepository) SaveDetection(baselines []models.CellBaseline, scored []models.MeasurementRollup, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(baselines) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&baselines).Error; err != nil {
+				return err
+			}
+		}
+		for _, rollup := range scored {
+			err := tx.Model(&models.MeasurementRollup{}).
+				Where("resolution = ? AND cell_id = ? AND metric = ? AND bucket_start = ?",
+					rollup.Resolution, rollup.CellID, rollup.Metric, rollup.BucketStart).
+				Update("score", rollup.Score).Error
+			if err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save anomaly detection: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AssetRepository struct {
+	db *gorm.DB
+}
+
+func NewAssetRepository(db *gorm.DB) *AssetRepository {
+	return &AssetRepository{db: db}
+}
+
+func (r *AssetRepository) GetAssets(filter models.AssetFilter) ([]models.Asset, error) {
+	var assets []models.Asset
+	query := r.db.Model(&models.Asset{})
+	if filter.OrganizationID != "" {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.State != "" {
+		query = query.Where("state = ?", filter.State)
+	}
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.CellID != nil {
+		query = query.Where("cell_id = ?", *filter.CellID)
+	}
+	if filter.Serial != "" {
+		query = query.Where("LOWER(serial_number) LIKE ?", "%"+strings.ToLower(filter.Serial)+"%")
+	}
+	if err := query.Order("type ASC, serial_number ASC").Find(&assets).Error; err != nil {
+		return nil, fmt.Errorf("failed to get assets: %w", err)
+	}
+	return assets, nil
+}
+
+func (r *AssetRepository) GetByID(id string) (*models.Asset, error) {
+	var asset models.Asset
+	if err := r.db.Where("id = ?", id).First(&asset).Error; err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	return &asset, nil
+}
+
+// GetOrganizationAsset - оборудование организации; пустая организация - любое оборудование
+func (r *AssetRepository) GetOrganizationAsset(id, organizationID string) (*models.Asset, error) {
+	var asset models.Asset
+	query := r.db.Where("id = ?", id)
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if err := query.First(&asset).Error; err != nil {
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	return &asset, nil
+}
+
+// GetInstalled - оборудование заданного типа, установленное в ячейке
+func (r *AssetRepository) GetInstalled(ruID string, cellID int, assetType models.AssetType) ([]models.Asset, error) {
+	var assets []models.Asset
+	err := r.db.Where("ru_id = ? AND cell_id = ? AND type = ? AND state = ?", ruID, cellID, assetType, models.AssetInstalled).
+		Find(&assets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installed assets: %w", err)
+	}
+	return assets, nil
+}
+
+func (r *AssetRepository) ExistsBySerial(serial, exceptID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Asset{}).Where("serial_number = ? AND id <> ?", serial, exceptID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check asset serial: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *AssetRepository) GetHistory(assetID string) ([]models.AssetEvent, error) {
+	var events []models.AssetEvent
+	if err := r.db.Where("asset_id = ?", assetID).Order("at ASC").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get asset history: %w", err)
+	}
+	return events, nil
+}
+
+// Save - сохраняет оборудование и записи истории одной транзакцией
+func (r *AssetRepository) Save(assets []*models.Asset, events []models.AssetEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, asset := range assets {
+			if err := tx.Save(asset).Error; err != nil {
+				return err
+			}
+		}
+		if len(events) > 0 {
+			return tx.Create(&events).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save asset: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create - добавляет запись в конец цепочки хешей аудита
+func (r *AuditRepository) Create(entry *models.AuditEntry) error {
+	entry.CreatedAt = chainTime(entry.CreatedAt)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := appendToChain(tx, models.ChainAuditEntries, entry); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetEntries - записи аудита, новые сверху
+func (r *AuditRepository) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	entries := []models.AuditEntry{}
+	query := r.db.Model(&models.AuditEntry{})
+	if filter.UserID != "" {
+		query = query.Where("user_id = ? OR impersonator_id = ?", filter.UserID, filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+	if err := query.Order("created_at DESC").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *AuditRepository) CreateLogin(event *models.LoginEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+	return nil
+}
+
+// GetLogins - попытки входа пользователя, новые сверху. Попытки с несуществующим
+// email к пользователю не привязаны и сюда не попадают.
+func (r *AuditRepository) GetLogins(userID string, limit int) ([]models.LoginEvent, error) {
+	events := []models.LoginEvent{}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get login events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// GetDaysInRange - дни-исключения в диапазоне дат (включительно), даты в формате YYYY-MM-DD
+func (r *CalendarRepository) GetDaysInRange(from, to string) ([]models.CalendarDay, error) {
+	var days []models.CalendarDay
+	result := r.db.Where("date >= ? AND date <= ?", from, to).Order("date ASC").Find(&days)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get calendar days: %w", result.Error)
+	}
+	return days, nil
+}
+
+func (r *CalendarRepository) UpsertDay(day *models.CalendarDay) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "name", "updated_at"}),
+	}).Create(day)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save calendar day: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *CalendarRepository) DeleteDay(date string) (bool, error) {
+	result := r.db.Delete(&models.CalendarDay{}, "date = ?", date)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete calendar day: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveFeedToken - новый ключ подписки заменяет прежний ключ пользователя
+func (r *CalendarRepository) SaveFeedToken(token *models.CalendarFeedToken) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at", "last_used_at"}),
+	}).Create(token)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save calendar feed token: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *CalendarRepository) GetFeedTokenByHash(hash string) (*models.CalendarFeedToken, error) {
+	var token models.CalendarFeedToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r *CalendarRepository) TouchFeedToken(userID string, at time.Time) error {
+	result := r.db.Model(&models.CalendarFeedToken{}).Where("user_id = ?", userID).Update("last_used_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update calendar feed token: %w", result.Error)
+	}
+	return nil
+}
+
+// DeleteFeedToken - отзыв ключа подписки; false, если ключа не было
+func (r *CalendarRepository) DeleteFeedToken(userID string) (bool, error) {
+	result := r.db.Where("user_id = ?", userID).Delete(&models.CalendarFeedToken{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete calendar feed token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
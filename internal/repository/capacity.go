@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// syncCapacity - заполняет недостающую половину пары «строка / амперы», как syncDate для дат.
+// Строки в других единицах (кВА, кВт) не разбираются.
+func syncCapacity(legacy *string, typed **float64) bool {
+	switch {
+	case *typed == nil && *legacy != "":
+		if amperes, ok := utils.ParseAmperes(*legacy); ok {
+			*typed = &amperes
+			return true
+		}
+	case *typed != nil && *legacy == "":
+		*legacy = utils.FormatAmperes(**typed)
+		return true
+	}
+	return false
+}
+
+func syncRuCapacity(ru *models.RUInfo) bool {
+	changed := syncCapacity(&ru.MaxCapacityHigh, &ru.MaxCapacityHighA)
+	changed = syncCapacity(&ru.MaxCapacityLow, &ru.MaxCapacityLowA) || changed
+	return changed
+}
+
+// BackfillCapacity - заполняет допустимый ток в амперах у РУ, созданных до его появления
+func BackfillCapacity(db *gorm.DB) error {
+	var rus []models.RUInfo
+	if err := db.Find(&rus).Error; err != nil {
+		return fmt.Errorf("failed to load RUs for backfill: %w", err)
+	}
+	updated := 0
+	for i := range rus {
+		if syncRuCapacity(&rus[i]) {
+			if err := db.Save(&rus[i]).Error; err != nil {
+				return fmt.Errorf("failed to backfill RU %s: %w", rus[i].ID, err)
+			}
+			updated++
+		}
+	}
+	if updated > 0 {
+		log.Printf("✅ Backfilled numeric capacity for %d RUs", updated)
+	}
+	return nil
+}
+
+type CapacityRepository struct {
+	db *gorm.DB
+}
+
+func NewCapacityRepository(db *gorm.DB) *CapacityRepository {
+	return &CapacityRepository{db: db}
+}
+
+// GetUtilizations - загрузка секций указанных РУ; nil - всех РУ
+func (r *CapacityRepository) GetUtilizations(ruIDs []string) ([]models.SectionUtilization, error) {
+	var rows []models.SectionUtilization
+	query := r.db.Order("ru_id, voltage_level, section")
+	if ruIDs != nil {
+		if len(ruIDs) == 0 {
+			return rows, nil
+		}
+		query = query.Where("ru_id IN ?", ruIDs)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get section utilization: %w", err)
+	}
+	return rows, nil
+}
+
+// ReplaceUtilizations - заменяет рассчитанную загрузку всех секций и пишет события
+// о перегрузке в той же транзакции; секции, выпавшие из расчета, удаляются
+func (r *CapacityRepository) ReplaceUtilizations(rows []models.SectionUtilization, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.SectionUtilization{}).Error; err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save section utilization: %w", err)
+	}
+	return nil
+}
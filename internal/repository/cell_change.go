@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CellChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewCellChangeRepository(db *gorm.DB) *CellChangeRepository {
+	return &CellChangeRepository{db: db}
+}
+
+// GetChanges - изменения ячеек РУ организации; пустая организация - всех
+func (r *CellChangeRepository) GetChanges(state, ruID, organizationID string) ([]models.CellInfoChange, error) {
+	var changes []models.CellInfoChange
+	query := scopeOrganizationRUs(r.db.Order("requested_at DESC"), "ru_id", organizationID)
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+	if ruID != "" {
+		query = query.Where("ru_id = ?", ruID)
+	}
+	if err := query.Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell changes: %w", err)
+	}
+	return changes, nil
+}
+
+// GetPending - ожидающие решения изменения ячеек РУ организации; пустая организация - всех
+func (r *CellChangeRepository) GetPending(organizationID string) ([]models.CellInfoChange, error) {
+	var changes []models.CellInfoChange
+	query := scopeOrganizationRUs(r.db.Where("state = ?", models.ChangePending), "ru_id", organizationID)
+	if err := query.Order("requested_at ASC").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending cell changes: %w", err)
+	}
+	return changes, nil
+}
+
+// GetByID - изменение ячейки РУ организации; пустая организация - любое изменение
+func (r *CellChangeRepository) GetByID(id, organizationID string) (*models.CellInfoChange, error) {
+	var change models.CellInfoChange
+	query := scopeOrganizationRUs(r.db.Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&change).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell change: %w", err)
+	}
+	return &change, nil
+}
+
+// Create - сохраняет предложенное изменение; доменные события пишутся в outbox в той же транзакции
+func (r *CellChangeRepository) Create(change *models.CellInfoChange, events ...models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cell change: %w", err)
+	}
+	return nil
+}
+
+// Review - сохраняет решение; при одобрении в той же транзакции обновляет ячейку
+// и записывает ревизию. Решение принимается только по изменению в статусе pending.
+func (r *CellChangeRepository) Review(change *models.CellInfoChange, cell *models.Cell, previous models.CellRevision, meta models.CellRevision) (bool, error) {
+	reviewed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.CellInfoChange{}).
+			Where("id = ? AND state = ?", change.ID, models.ChangePending).
+			Updates(map[string]interface{}{
+				"state":          change.State,
+				"reviewed_by":    change.ReviewedBy,
+				"reviewed_at":    change.ReviewedAt,
+				"review_comment": change.ReviewComment,
+				"updated_at":     change.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		reviewed = true
+
+		if cell != nil {
+			syncCellDates(cell)
+			if err := tx.Save(cell).Error; err != nil {
+				return err
+			}
+			return appendCellRevision(tx, cell, previous, meta)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to review cell change: %w", err)
+	}
+	return reviewed, nil
+}
@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// cellReference - столбец таблицы, ссылающийся на ячейку по ID
+type cellReference struct {
+	table  string
+	column string
+	// key - остальные столбцы уникального ключа строки: при слиянии строка дубликата,
+	// совпадающая по ключу со строкой оставляемой ячейки, удаляется
+	key []string
+	// drop - строки принадлежат самой ячейке (ее ревизии, базовая линия телеметрии): они
+	// не считаются ссылками, не переносятся при слиянии и удаляются вместе с ячейкой
+	drop bool
+}
+
+// cellReferences - все ссылки на ячейки по ID. Журнал операций ссылается на ячейку по
+// номеру и при слиянии дубликатов с тем же номером не меняется.
+var cellReferences = []cellReference{
+	{table: "alarms", column: "cell_id"},
+	{table: "assets", column: "cell_id"},
+	{table: "asset_events", column: "cell_id"},
+	{table: "cell_baselines", column: "cell_id", drop: true},
+	{table: "cell_info_changes", column: "cell_id"},
+	{table: "cell_locks", column: "cell_id"},
+	{table: "cell_revisions", column: "cell_id", drop: true},
+	{table: "consumer_feeders", column: "cell_id", key: []string{"consumer_id"}},
+	{table: "control_commands", column: "cell_id"},
+	{table: "defects", column: "cell_id"},
+	{table: "device_cells", column: "cell_id", key: []string{"device_id"}},
+	{table: "fault_events", column: "cell_id"},
+	{table: "forecast_runs", column: "cell_id"},
+	{table: "inspection_results", column: "cell_id"},
+	{table: "measurements", column: "cell_id"},
+	{table: "measurement_rollups", column: "cell_id", key: []string{"resolution", "metric", "bucket_start"}},
+	{table: "meter_readings", column: "cell_id", key: []string{"read_at"}},
+	{table: "planned_outage_cells", column: "cell_id", key: []string{"outage_id"}},
+	{table: "status_confirmations", column: "cell_id"},
+	{table: "subscriptions", column: "cell_id", key: []string{"user_id", "ru_id"}},
+	{table: "switching_order_steps", column: "cell_id"},
+	{table: "thermal_snapshots", column: "cell_id"},
+	{table: "transformers", column: "tap_cell_id"},
+	{table: "transformers", column: "high_cell_id"},
+	{table: "transformers", column: "low_cell_id"},
+	{table: "transformers", column: "oil_temp_cell_id"},
+	{table: "vision_indications", column: "cell_id"},
+	{table: "vision_readings", column: "cell_id"},
+	{table: "voltage_measurements", column: "cell_id", key: []string{"measured_at"}},
+}
+
+// cellPhotosReference - снимки ячейки ссылаются на нее строковым owner_id
+const cellPhotosReference = "photos"
+
+// FindDuplicateCells - ячейки с одинаковыми номером и уровнем напряжения в пределах РУ
+// (все РУ при пустом ruID) в порядке групп и ID
+func (r *MaintenanceRepository) FindDuplicateCells(ruID string) ([]models.Cell, error) {
+	var groups []struct {
+		RuID         string
+		Number       string
+		VoltageLevel models.VoltageLevel
+	}
+	query := r.db.Model(&models.Cell{}).Select("ru_id, number, voltage_level").
+		Group("ru_id, number, voltage_level").Having("count(*) > 1").Order("ru_id, number, voltage_level")
+	if ruID != "" {
+		query = query.Where("ru_id = ?", ruID)
+	}
+	if err := query.Scan(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to find duplicate cells: %w", err)
+	}
+
+	var cells []models.Cell
+	for _, group := range groups {
+		var members []models.Cell
+		err := r.db.Where("ru_id = ? AND number = ? AND voltage_level = ?", group.RuID, group.Number, group.VoltageLevel).
+			Order("id ASC").Find(&members).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get duplicate cells: %w", err)
+		}
+		cells = append(cells, members...)
+	}
+	return cells, nil
+}
+
+// FindOrphanCells - ячейки, РУ которых не существует
+func (r *MaintenanceRepository) FindOrphanCells(ruID string) ([]models.Cell, error) {
+	var cells []models.Cell
+	query := r.db.Where("(ru_id IS NULL OR NOT EXISTS (SELECT 1 FROM ru_infos r WHERE r.id = cells.ru_id))")
+	if ruID != "" {
+		query = query.Where("ru_id = ?", ruID)
+	}
+	if err := query.Order("ru_id ASC, id ASC").Find(&cells).Error; err != nil {
+		return nil, fmt.Errorf("failed to find orphan cells: %w", err)
+	}
+	return cells, nil
+}
+
+// CountCellReferences - число строк, ссылающихся на каждую из ячеек, по таблицам
+func (r *MaintenanceRepository) CountCellReferences(cellIDs []int) (map[int]map[string]int64, error) {
+	return countCellReferences(r.db, cellIDs)
+}
+
+func countCellReferences(db *gorm.DB, cellIDs []int) (map[int]map[string]int64, error) {
+	counts := make(map[int]map[string]int64, len(cellIDs))
+	for _, id := range cellIDs {
+		counts[id] = map[string]int64{}
+	}
+	if len(cellIDs) == 0 {
+		return counts, nil
+	}
+
+	type row struct {
+		CellID int
+		Count  int64
+	}
+	for _, ref := range cellReferences {
+		if ref.drop {
+			continue
+		}
+		var rows []row
+		sql := fmt.Sprintf("SELECT %s AS cell_id, count(*) AS count FROM %s WHERE %s IN ? GROUP BY %s",
+			ref.column, ref.table, ref.column, ref.column)
+		if err := db.Raw(sql, cellIDs).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count references in %s: %w", ref.table, err)
+		}
+		name := ref.table
+		if ref.column != "cell_id" {
+			name += "." + ref.column
+		}
+		for _, row := range rows {
+			counts[row.CellID][name] += row.Count
+		}
+	}
+
+	owners := make([]string, len(cellIDs))
+	for i, id := range cellIDs {
+		owners[i] = strconv.Itoa(id)
+	}
+	var photos []struct {
+		OwnerID string
+		Count   int64
+	}
+	err := db.Raw("SELECT owner_id, count(*) AS count FROM photos WHERE owner_type = ? AND owner_id IN ? GROUP BY owner_id",
+		models.PhotoOwnerCell, owners).Scan(&photos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cell photos: %w", err)
+	}
+	for _, photo := range photos {
+		if id, err := strconv.Atoi(photo.OwnerID); err == nil {
+			counts[id][cellPhotosReference] += photo.Count
+		}
+	}
+	return counts, nil
+}
+
+// GetCellBlockers - ячейки с действующим замком или незавершенной командой телеуправления:
+// такие ячейки не сливаются и не удаляются
+func (r *MaintenanceRepository) GetCellBlockers(cellIDs []int) (map[int]string, error) {
+	blockers := map[int]string{}
+	if len(cellIDs) == 0 {
+		return blockers, nil
+	}
+	var locked, commanded []int
+	if err := r.db.Model(&models.CellLock{}).Where("cell_id IN ? AND removed_at IS NULL", cellIDs).Pluck("cell_id", &locked).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell locks: %w", err)
+	}
+	if err := r.db.Model(&models.ControlCommand{}).Where("cell_id IN ? AND completed_at IS NULL", cellIDs).Pluck("cell_id", &commanded).Error; err != nil {
+		return nil, fmt.Errorf("failed to get control commands: %w", err)
+	}
+	for _, id := range commanded {
+		blockers[id] = models.CellSkipActiveCommand
+	}
+	for _, id := range locked {
+		blockers[id] = models.CellSkipActiveLock
+	}
+	return blockers, nil
+}
+
+// MergeCell - переносит все ссылки дубликата на оставляемую ячейку и удаляет дубликат.
+// Строки дубликата, совпадающие по ключу со строками оставляемой ячейки (показания на тот
+// же момент, та же подписка), удаляются: данные оставляемой ячейки приоритетнее.
+func (r *MaintenanceRepository) MergeCell(duplicateID, keepID int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, ref := range cellReferences {
+			if ref.drop {
+				continue
+			}
+			if len(ref.key) > 0 {
+				match := make([]string, len(ref.key))
+				for i, column := range ref.key {
+					match[i] = fmt.Sprintf("k.%s = %s.%s", column, ref.table, column)
+				}
+				sql := fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND EXISTS (SELECT 1 FROM %s k WHERE k.%s = ? AND %s)",
+					ref.table, ref.column, ref.table, ref.column, strings.Join(match, " AND "))
+				if err := tx.Exec(sql, duplicateID, keepID).Error; err != nil {
+					return fmt.Errorf("failed to drop conflicting %s: %w", ref.table, err)
+				}
+			}
+			sql := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", ref.table, ref.column, ref.column)
+			if err := tx.Exec(sql, keepID, duplicateID).Error; err != nil {
+				return fmt.Errorf("failed to move %s: %w", ref.table, err)
+			}
+		}
+		err := tx.Exec("UPDATE photos SET owner_id = ? WHERE owner_type = ? AND owner_id = ?",
+			strconv.Itoa(keepID), models.PhotoOwnerCell, strconv.Itoa(duplicateID)).Error
+		if err != nil {
+			return fmt.Errorf("failed to move cell photos: %w", err)
+		}
+
+		// Пара ВН/НН дубликата переходит к оставляемой ячейке, если у той пары нет
+		var pair []models.Cell
+		if err := tx.Select("id", "paired_cell_id").Where("id IN ?", []int{duplicateID, keepID}).Find(&pair).Error; err != nil {
+			return fmt.Errorf("failed to get cell pairs: %w", err)
+		}
+		partners := map[int]*int{}
+		for _, cell := range pair {
+			partners[cell.ID] = cell.PairedCellID
+		}
+		if partner := partners[duplicateID]; partner != nil && partners[keepID] == nil && *partner != keepID {
+			if err := tx.Model(&models.Cell{}).Where("id = ?", keepID).Update("paired_cell_id", *partner).Error; err != nil {
+				return fmt.Errorf("failed to move cell pair: %w", err)
+			}
+			if err := tx.Model(&models.Cell{}).Where("id = ?", *partner).Update("paired_cell_id", keepID).Error; err != nil {
+				return fmt.Errorf("failed to move cell pair: %w", err)
+			}
+		}
+		return deleteCell(tx, duplicateID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to merge cell %d into %d: %w", duplicateID, keepID, err)
+	}
+	return nil
+}
+
+// DeleteUnreferencedCell - удаляет ячейку, если на нее ничего не ссылается. Ссылки
+// пересчитываются в транзакции удаления; false - ячейка не удалена.
+func (r *MaintenanceRepository) DeleteUnreferencedCell(cellID int) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		counts, err := countCellReferences(tx, []int{cellID})
+		if err != nil {
+			return err
+		}
+		for _, count := range counts[cellID] {
+			if count > 0 {
+				return nil
+			}
+		}
+		deleted = true
+		return deleteCell(tx, cellID)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete cell %d: %w", cellID, err)
+	}
+	return deleted, nil
+}
+
+// deleteCell - удаляет ячейку с ее собственными строками, предварительно разорвав ее пару ВН/НН
+func deleteCell(tx *gorm.DB, cellID int) error {
+	for _, ref := range cellReferences {
+		if !ref.drop {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", ref.table, ref.column), cellID).Error; err != nil {
+			return fmt.Errorf("failed to delete %s: %w", ref.table, err)
+		}
+	}
+	if err := tx.Model(&models.Cell{}).Where("paired_cell_id = ?", cellID).Update("paired_cell_id", nil).Error; err != nil {
+		return fmt.Errorf("failed to unpair cell: %w", err)
+	}
+	if err := tx.Delete(&models.Cell{}, cellID).Error; err != nil {
+		return fmt.Errorf("failed to delete cell: %w", err)
+	}
+	return nil
+}
+
+// EnsureCellNumberIndex - уникальный индекс номеров ячеек; создается, когда в базе не
+// осталось дубликатов (при запуске он пропускается, пока дубликаты есть)
+func (r *MaintenanceRepository) EnsureCellNumberIndex() error {
+	return ensureCellNumberIndex(r.db)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SetCellPair - связывает две ячейки РУ в пару; прежние пары обеих ячеек разрываются
+func (r *RuRepository) SetCellPair(ruID string, cellID, pairedCellID int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		ids := []int{cellID, pairedCellID}
+		if err := unpairCells(tx, ruID, ids); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Cell{}).Where("id = ?", cellID).Update("paired_cell_id", pairedCellID).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Cell{}).Where("id = ?", pairedCellID).Update("paired_cell_id", cellID).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to pair cells: %w", err)
+	}
+	return nil
+}
+
+// ClearCellPair - разрывает пару ячейки с обеих сторон
+func (r *RuRepository) ClearCellPair(ruID string, cellID int) error {
+	if err := unpairCells(r.db, ruID, []int{cellID}); err != nil {
+		return fmt.Errorf("failed to unpair cell: %w", err)
+	}
+	return nil
+}
+
+// unpairCells - снимает пары ячеек и пары, которые на них ссылаются
+func unpairCells(db *gorm.DB, ruID string, cellIDs []int) error {
+	return db.Model(&models.Cell{}).
+		Where("ru_id = ? AND (id IN ? OR paired_cell_id IN ?)", ruID, cellIDs, cellIDs).
+		Update("paired_cell_id", nil).Error
+}
+
+// clearBrokenCellPairs - снимает ссылки на пару, которая не ссылается обратно
+// (например, после импорта снимка, в котором пары другие)
+func clearBrokenCellPairs(db *gorm.DB, ruID string) error {
+	return db.Exec(`
+		UPDATE cells SET paired_cell_id = NULL
+		WHERE ru_id = ? AND paired_cell_id IS NOT NULL
+		AND NOT EXISTS (SELECT 1 FROM cells p WHERE p.id = cells.paired_cell_id AND p.paired_cell_id = cells.id)`,
+		ruID).Error
+}
+
+// BackfillCellPairs - связывает ячейки ВН и НН с одинаковым номером в пределах РУ
+// (так начальные данные обозначают одно присоединение). Выполняется один раз при
+// появлении колонки paired_cell_id, чтобы не восстанавливать пары, снятые вручную.
+func BackfillCellPairs(db *gorm.DB) error {
+	var cells []models.Cell
+	err := db.Select("id", "ru_id", "number", "voltage_level").
+		Where("paired_cell_id IS NULL").Order("ru_id ASC, id ASC").Find(&cells).Error
+	if err != nil {
+		return fmt.Errorf("failed to get cells: %w", err)
+	}
+
+	type side struct {
+		count int
+		id    int
+	}
+	sides := map[string]map[models.VoltageLevel]*side{}
+	var keys []string
+	for _, cell := range cells {
+		key := cell.RuID + "/" + cell.Number
+		if sides[key] == nil {
+			sides[key] = map[models.VoltageLevel]*side{}
+			keys = append(keys, key)
+		}
+		s := sides[key][cell.VoltageLevel]
+		if s == nil {
+			s = &side{}
+			sides[key][cell.VoltageLevel] = s
+		}
+		s.count++
+		s.id = cell.ID
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			high, low := sides[key][models.VoltageLevelHigh], sides[key][models.VoltageLevelLow]
+			if high == nil || low == nil || high.count != 1 || low.count != 1 {
+				continue
+			}
+			if err := tx.Model(&models.Cell{}).Where("id = ?", high.id).UpdateColumn("paired_cell_id", low.id).Error; err != nil {
+				return fmt.Errorf("failed to pair cells: %w", err)
+			}
+			if err := tx.Model(&models.Cell{}).Where("id = ?", low.id).UpdateColumn("paired_cell_id", high.id).Error; err != nil {
+				return fmt.Errorf("failed to pair cells: %w", err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAppendOnly - попытка изменить или удалить запись журнала, который только дополняется
+var ErrAppendOnly = errors.New("table is append-only")
+
+const appendOnlyAllowKey = "append_only:allow"
+
+// AppendOnly - плагин GORM, запрещающий UPDATE и DELETE в журналах операций и аудита.
+// Исключения - служебные изменения полей, не входящих в хеш записи (организация при
+// переносе подстанции, типизированные даты); они выполняются через allowJournalUpdate.
+// Прямой SQL плагин не видит: от правки в обход приложения защищает цепочка хешей.
+type AppendOnly struct{}
+
+// appendOnlyTables - таблицы журналов, которые только дополняются
+var appendOnlyTables = map[string]bool{
+	"operation_records": true,
+	"audit_entries":     true,
+	// Исправления записей журнала
+	"operation_corrections":       true,
+	"operation_correction_fields": true,
+}
+
+func (AppendOnly) Name() string {
+	return "append_only"
+}
+
+func (p AppendOnly) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Update().Before("gorm:update").Register("append_only:update", p.check); err != nil {
+		return err
+	}
+	return cb.Delete().Before("gorm:delete").Register("append_only:delete", p.check)
+}
+
+func (AppendOnly) check(db *gorm.DB) {
+	if !appendOnlyTables[db.Statement.Table] {
+		return
+	}
+	if allowed, ok := db.Get(appendOnlyAllowKey); ok && allowed == true {
+		return
+	}
+	db.AddError(fmt.Errorf("%s: %w", db.Statement.Table, ErrAppendOnly))
+}
+
+// allowJournalUpdate - сессия, которой разрешено изменить служебные поля журнала
+func allowJournalUpdate(tx *gorm.DB) *gorm.DB {
+	return tx.Set(appendOnlyAllowKey, true)
+}
+
+// appendToChain - включает запись в цепочку: блокирует голову цепочки, проставляет
+// номер, хеш предыдущей записи и хеш самой записи. Вызывается в транзакции вставки
+// записи, после того как заполнено все ее содержимое.
+func appendToChain(tx *gorm.DB, chain string, record models.Chained) error {
+	head := models.HashChain{Name: chain, LastHash: models.ChainGenesis, UpdatedAt: time.Now()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&head).Error; err != nil {
+		return fmt.Errorf("failed to create chain head: %w", err)
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", chain).First(&head).Error; err != nil {
+		return fmt.Errorf("failed to lock chain head: %w", err)
+	}
+
+	seq := head.Seq + 1
+	link := record.Link()
+	link.ChainSeq = &seq
+	link.PrevHash = head.LastHash
+	link.Hash = record.ChainHash()
+
+	err := tx.Model(&models.HashChain{}).Where("name = ?", chain).Updates(map[string]interface{}{
+		"seq":        seq,
+		"last_hash":  link.Hash,
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to advance chain head: %w", err)
+	}
+	return nil
+}
+
+// chainTime - время создания записи журнала с точностью, которую хранит БД
+func chainTime(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.Truncate(time.Microsecond)
+}
+
+type ChainRepository struct {
+	db *gorm.DB
+}
+
+func NewChainRepository(db *gorm.DB) *ChainRepository {
+	return &ChainRepository{db: db}
+}
+
+// GetHead - голова цепочки; цепочка без записей возвращается с нулевым номером
+func (r *ChainRepository) GetHead(chain string) (*models.HashChain, error) {
+	head := models.HashChain{Name: chain, LastHash: models.ChainGenesis}
+	err := r.db.Where("name = ?", chain).First(&head).Error
+	if err != nil && !IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get chain head: %w", err)
+	}
+	return &head, nil
+}
+
+// GetOperationRecords - записи журнала операций цепочки после номера afterSeq
+func (r *ChainRepository) GetOperationRecords(afterSeq int64, limit int) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	err := r.db.Where("chain_seq > ?", afterSeq).Order("chain_seq ASC").Limit(limit).Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chained history: %w", err)
+	}
+	return records, nil
+}
+
+// GetAuditEntries - записи аудита цепочки после номера afterSeq
+func (r *ChainRepository) GetAuditEntries(afterSeq int64, limit int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := r.db.Where("chain_seq > ?", afterSeq).Order("chain_seq ASC").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chained audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// GetCorrections - исправления цепочки после номера afterSeq
+func (r *ChainRepository) GetCorrections(afterSeq int64, limit int) ([]models.OperationCorrection, error) {
+	var corrections []models.OperationCorrection
+	err := r.db.Preload("Fields", orderCorrectedFields).
+		Where("chain_seq > ?", afterSeq).Order("chain_seq ASC").Limit(limit).Find(&corrections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chained corrections: %w", err)
+	}
+	return corrections, nil
+}
+
+// CountUnchained - записи таблицы журнала, созданные до включения цепочки
+func (r *ChainRepository) CountUnchained(table string) (int64, error) {
+	var count int64
+	if err := r.db.Table(table).Where("chain_seq IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unchained records: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommandRepository struct {
+	db *gorm.DB
+}
+
+func NewCommandRepository(db *gorm.DB) *CommandRepository {
+	return &CommandRepository{db: db}
+}
+
+// Create - записывает выбранную команду, первую запись аудита и событие для шлюза.
+// Вторая незавершенная команда на ячейку отклоняется уникальным индексом (см. IsDuplicate).
+func (r *CommandRepository) Create(command *models.ControlCommand, events []models.OutboxEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(command).Error; err != nil {
+			return fmt.Errorf("failed to create control command: %w", err)
+		}
+		return appendOutbox(tx, events)
+	})
+}
+
+// Transition - изменяет команду под блокировкой строки; apply возвращает запись аудита
+// и события, которые сохраняются в той же транзакции
+func (r *CommandRepository) Transition(id string, apply func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error)) (*models.ControlCommand, error) {
+	var command models.ControlCommand
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&command).Error; err != nil {
+			return err
+		}
+
+		step, events, err := apply(&command)
+		if err != nil {
+			return err
+		}
+
+		command.UpdatedAt = time.Now()
+		if err := tx.Omit("Steps").Save(&command).Error; err != nil {
+			return err
+		}
+		if step != nil {
+			step.CommandID = command.ID
+			if err := tx.Create(step).Error; err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update control command: %w", err)
+	}
+	return &command, nil
+}
+
+// GetOrganizationCommand - команда РУ организации без шагов; пустая организация - любая команда
+func (r *CommandRepository) GetOrganizationCommand(id, organizationID string) (*models.ControlCommand, error) {
+	var command models.ControlCommand
+	query := scopeOrganizationRUs(r.db.Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&command).Error; err != nil {
+		return nil, fmt.Errorf("failed to get control command: %w", err)
+	}
+	return &command, nil
+}
+
+func (r *CommandRepository) GetByID(ruID, id string) (*models.ControlCommand, error) {
+	var command models.ControlCommand
+	err := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("at, id")
+	}).Where("ru_id = ? AND id = ?", ruID, id).First(&command).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get control command: %w", err)
+	}
+	return &command, nil
+}
+
+// GetCommands - журнал команд РУ, новые сверху
+func (r *CommandRepository) GetCommands(ruID string, filter models.CommandFilter, limit int) ([]models.ControlCommand, error) {
+	var commands []models.ControlCommand
+	query := r.db.Where("ru_id = ?", ruID)
+	if filter.CellID != nil {
+		query = query.Where("cell_id = ?", *filter.CellID)
+	}
+	if filter.State != "" {
+		query = query.Where("state = ?", filter.State)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Find(&commands).Error; err != nil {
+		return nil, fmt.Errorf("failed to get control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// GetPending - команды РУ организации (пустая - всех), ожидающие действий шлюза, для
+// шлюзов без брокера событий
+func (r *CommandRepository) GetPending(deviceID, organizationID string) ([]models.ControlCommand, error) {
+	var commands []models.ControlCommand
+	query := r.db.Where("state IN ?", []models.CommandState{models.CommandSelecting, models.CommandOperating})
+	query = scopeOrganizationRUs(query, "ru_id", organizationID)
+	if deviceID != "" {
+		query = query.Where("device_id = ?", deviceID)
+	}
+	if err := query.Order("created_at").Find(&commands).Error; err != nil {
+		return nil, fmt.Errorf("failed to get pending control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// GetSelected - команды на ячейку, выбранные, но еще не отправленные на исполнение
+func (r *CommandRepository) GetSelected(cellID int) ([]models.ControlCommand, error) {
+	var commands []models.ControlCommand
+	query := r.db.Where("cell_id = ? AND state IN ?", cellID, []models.CommandState{models.CommandSelecting, models.CommandSelected})
+	if err := query.Find(&commands).Error; err != nil {
+		return nil, fmt.Errorf("failed to get selected control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// GetOverdue - незавершенные команды с истекшим сроком этапа
+func (r *CommandRepository) GetOverdue(now time.Time) ([]models.ControlCommand, error) {
+	var commands []models.ControlCommand
+	if err := r.db.Where("completed_at IS NULL AND deadline < ?", now).Find(&commands).Error; err != nil {
+		return nil, fmt.Errorf("failed to get overdue control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// GetAwaitingFeedback - исполненная команда на ячейку, ожидающая изменения статуса
+func (r *CommandRepository) GetAwaitingFeedback(cellID int) (*models.ControlCommand, error) {
+	var command models.ControlCommand
+	err := r.db.Where("cell_id = ? AND state = ?", cellID, models.CommandExecuted).First(&command).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executed control command: %w", err)
+	}
+	return &command, nil
+}
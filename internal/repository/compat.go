@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CompatRepository - счетчики форматов дат, присланных клиентами
+type CompatRepository struct {
+	db *gorm.DB
+}
+
+func NewCompatRepository(db *gorm.DB) *CompatRepository {
+	return &CompatRepository{db: db}
+}
+
+// AddDateFormatUsage - прибавляет накопленные экземпляром приращения к счетчикам
+func (r *CompatRepository) AddDateFormatUsage(usages []models.DateFormatUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "client"}, {Name: "field"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"legacy":    gorm.Expr("date_format_usages.legacy + excluded.legacy"),
+			"modern":    gorm.Expr("date_format_usages.modern + excluded.modern"),
+			"last_seen": gorm.Expr("CASE WHEN excluded.last_seen > date_format_usages.last_seen THEN excluded.last_seen ELSE date_format_usages.last_seen END"),
+		}),
+	}).Create(&usages)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save date format usage: %w", result.Error)
+	}
+	return nil
+}
+
+// GetDateFormatUsage - счетчики, клиенты с наибольшим числом старых форматов - первыми
+func (r *CompatRepository) GetDateFormatUsage() ([]models.DateFormatUsage, error) {
+	usages := []models.DateFormatUsage{}
+	if err := r.db.Order("legacy DESC, client, field").Find(&usages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get date format usage: %w", err)
+	}
+	return usages, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ComtradeRepository struct {
+	db *gorm.DB
+}
+
+func NewComtradeRepository(db *gorm.DB) *ComtradeRepository {
+	return &ComtradeRepository{db: db}
+}
+
+// withFileInfo - подгружает сведения о файлах без содержимого
+func withFileInfo(db *gorm.DB) *gorm.DB {
+	return db.Select("record_id", "kind", "file_name", "size").Order("kind")
+}
+
+// Create - сохраняет запись вместе с каналами и файлами
+func (r *ComtradeRepository) Create(record *models.ComtradeRecord) error {
+	if err := r.db.Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create COMTRADE record: %w", err)
+	}
+	return nil
+}
+
+// GetRecords - каталог осциллограмм РУ по времени пуска, новые сверху
+func (r *ComtradeRepository) GetRecords(ruID string, filter models.ComtradeFilter) ([]models.ComtradeRecord, error) {
+	var records []models.ComtradeRecord
+	query := r.db.Preload("Files", withFileInfo).Where("ru_id = ?", ruID)
+	if filter.FaultID != "" {
+		query = query.Where("fault_id = ?", filter.FaultID)
+	}
+	if filter.From != nil {
+		query = query.Where("trigger_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("trigger_time < ?", *filter.To)
+	}
+	if err := query.Order("trigger_time DESC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get COMTRADE records: %w", err)
+	}
+	return records, nil
+}
+
+func (r *ComtradeRepository) GetByID(ruID, id string) (*models.ComtradeRecord, error) {
+	var record models.ComtradeRecord
+	err := r.db.Preload("Channels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("type, channel_index")
+	}).Preload("Files", withFileInfo).
+		Where("ru_id = ? AND id = ?", ruID, id).First(&record).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get COMTRADE record: %w", err)
+	}
+	return &record, nil
+}
+
+func (r *ComtradeRepository) GetFile(recordID string, kind models.ComtradeFileKind) (*models.ComtradeFile, error) {
+	var file models.ComtradeFile
+	if err := r.db.Where("record_id = ? AND kind = ?", recordID, kind).First(&file).Error; err != nil {
+		return nil, fmt.Errorf("failed to get COMTRADE file: %w", err)
+	}
+	return &file, nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ConfirmationRepository struct {
+	db *gorm.DB
+}
+
+func NewConfirmationRepository(db *gorm.DB) *ConfirmationRepository {
+	return &ConfirmationRepository{db: db}
+}
+
+func (r *ConfirmationRepository) GetPending(ruID string, cellID int) ([]models.StatusConfirmation, error) {
+	var confirmations []models.StatusConfirmation
+	result := r.db.Where("ru_id = ? AND cell_id = ? AND state = ?", ruID, cellID, models.ConfirmationPending).
+		Order("requested_at DESC").
+		Find(&confirmations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get pending confirmations: %w", result.Error)
+	}
+	return confirmations, nil
+}
+
+// GetOpen - неистекшие запросы подтверждения РУ организации на момент at; пустая
+// организация - всех
+func (r *ConfirmationRepository) GetOpen(organizationID string, at time.Time) ([]models.StatusConfirmation, error) {
+	var confirmations []models.StatusConfirmation
+	query := r.db.Where("state = ? AND expires_at > ?", models.ConfirmationPending, at)
+	query = scopeOrganizationRUs(query, "ru_id", organizationID)
+	if err := query.Order("expires_at ASC").Find(&confirmations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get open confirmations: %w", err)
+	}
+	return confirmations, nil
+}
+
+func (r *ConfirmationRepository) GetByID(id string) (*models.StatusConfirmation, error) {
+	var confirmation models.StatusConfirmation
+	if err := r.db.Where("id = ?", id).First(&confirmation).Error; err != nil {
+		return nil, fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	return &confirmation, nil
+}
+
+// CreateReplacing - создает запрос подтверждения, отменяя прежние ожидающие запросы по ячейке;
+// доменные события пишутся в outbox в той же транзакции
+func (r *ConfirmationRepository) CreateReplacing(confirmation *models.StatusConfirmation, events ...models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.StatusConfirmation{}).
+			Where("cell_id = ? AND state = ?", confirmation.CellID, models.ConfirmationPending).
+			Updates(map[string]interface{}{"state": models.ConfirmationCancelled, "updated_at": confirmation.CreatedAt}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(confirmation).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create confirmation: %w", err)
+	}
+	return nil
+}
+
+// MarkConfirmed - переводит запрос в confirmed, только если он еще ожидает подтверждения
+func (r *ConfirmationRepository) MarkConfirmed(confirmation *models.StatusConfirmation) (bool, error) {
+	result := r.db.Model(&models.StatusConfirmation{}).
+		Where("id = ? AND state = ?", confirmation.ID, models.ConfirmationPending).
+		Updates(map[string]interface{}{
+			"state":        models.ConfirmationConfirmed,
+			"confirmed_by": confirmation.ConfirmedBy,
+			"confirmed_at": confirmation.ConfirmedAt,
+			"updated_at":   confirmation.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to confirm: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkDefaultCriticalCells - помечает вводы и секционные выключатели критичными.
+// Выполняется один раз при появлении колонки is_critical.
+func MarkDefaultCriticalCells(db *gorm.DB) error {
+	result := db.Model(&models.Cell{}).
+		Where("type IN ?", []models.CellType{models.CellTypeInput, models.CellTypeSV}).
+		Update("is_critical", true)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark critical cells: %w", result.Error)
+	}
+	return nil
+}
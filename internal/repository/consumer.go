@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ConsumerRepository struct {
+	db *gorm.DB
+}
+
+func NewConsumerRepository(db *gorm.DB) *ConsumerRepository {
+	return &ConsumerRepository{db: db}
+}
+
+// GetConsumers - потребители организации (пустая строка - всех организаций) с ячейками питания
+func (r *ConsumerRepository) GetConsumers(organizationID string, filter models.ConsumerFilter) ([]models.Consumer, error) {
+	var consumers []models.Consumer
+	query := r.db.Preload("Feeders", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ru_id, cell_number")
+	})
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if filter.RuID != "" || filter.CellID != nil {
+		feeders := r.db.Model(&models.ConsumerFeeder{}).Select("consumer_id")
+		if filter.RuID != "" {
+			feeders = feeders.Where("ru_id = ?", filter.RuID)
+		}
+		if filter.CellID != nil {
+			feeders = feeders.Where("cell_id = ?", *filter.CellID)
+		}
+		query = query.Where("id IN (?)", feeders)
+	}
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(company) LIKE ? OR LOWER(contract_number) LIKE ?", pattern, pattern)
+	}
+	if err := query.Order("company ASC").Find(&consumers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get consumers: %w", err)
+	}
+	return consumers, nil
+}
+
+func (r *ConsumerRepository) GetByID(id string) (*models.Consumer, error) {
+	var consumer models.Consumer
+	err := r.db.Preload("Feeders", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ru_id, cell_number")
+	}).Where("id = ?", id).First(&consumer).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer: %w", err)
+	}
+	return &consumer, nil
+}
+
+func (r *ConsumerRepository) ExistsByContractNumber(contractNumber, exceptID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Consumer{}).Where("contract_number = ? AND id <> ?", contractNumber, exceptID).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check contract number: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Save - создает или изменяет потребителя без изменения его ячеек питания
+func (r *ConsumerRepository) Save(consumer *models.Consumer) error {
+	if err := r.db.Omit("Feeders").Save(consumer).Error; err != nil {
+		return fmt.Errorf("failed to save consumer: %w", err)
+	}
+	return nil
+}
+
+// Delete - удаляет потребителя вместе с привязками к ячейкам
+func (r *ConsumerRepository) Delete(id string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("consumer_id = ?", id).Delete(&models.ConsumerFeeder{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.Consumer{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete consumer: %w", err)
+	}
+	return nil
+}
+
+// AddFeeder - привязывает потребителя к ячейке; повторная привязка не меняет данные
+func (r *ConsumerRepository) AddFeeder(feeder *models.ConsumerFeeder) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(feeder).Error; err != nil {
+		return fmt.Errorf("failed to assign feeder: %w", err)
+	}
+	return nil
+}
+
+// RemoveFeeder - отвязывает потребителя от ячейки; false, если привязки не было
+func (r *ConsumerRepository) RemoveFeeder(consumerID string, cellID int) (bool, error) {
+	result := r.db.Where("consumer_id = ? AND cell_id = ?", consumerID, cellID).Delete(&models.ConsumerFeeder{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove feeder: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetByCells - потребители, питающиеся от указанных ячеек, по ячейкам
+func (r *ConsumerRepository) GetByCells(cellIDs []int) (map[int][]models.Consumer, error) {
+	byCell := map[int][]models.Consumer{}
+	if len(cellIDs) == 0 {
+		return byCell, nil
+	}
+	var feeders []models.ConsumerFeeder
+	if err := r.db.Where("cell_id IN ?", cellIDs).Find(&feeders).Error; err != nil {
+		return nil, fmt.Errorf("failed to get consumer feeders: %w", err)
+	}
+	if len(feeders) == 0 {
+		return byCell, nil
+	}
+
+	ids := make([]string, 0, len(feeders))
+	for _, feeder := range feeders {
+		ids = append(ids, feeder.ConsumerID)
+	}
+	var consumers []models.Consumer
+	if err := r.db.Where("id IN ?", ids).Order("company ASC").Find(&consumers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get consumers: %w", err)
+	}
+	byID := make(map[string]models.Consumer, len(consumers))
+	for _, consumer := range consumers {
+		byID[consumer.ID] = consumer
+	}
+	for _, feeder := range feeders {
+		if consumer, ok := byID[feeder.ConsumerID]; ok {
+			byCell[feeder.CellID] = append(byCell[feeder.CellID], consumer)
+		}
+	}
+	return byCell, nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// Период перехода со строковых дат на timestamp: при записи строковое и типизированное
+// поле синхронизируются, так что старые и новые клиенты видят согласованные значения.
+
+// syncDate - заполняет недостающую половину пары «строка / timestamp».
+// Возвращает true, если что-то изменилось.
+func syncDate(legacy *string, typed **time.Time, layout string) bool {
+	switch {
+	case *typed == nil && *legacy != "":
+		if t, err := utils.ParseDate(*legacy); err == nil {
+			*typed = &t
+			return true
+		}
+	case *typed != nil && *legacy == "":
+		*legacy = (*typed).Format(layout)
+		return true
+	}
+	return false
+}
+
+// syncDatePtr - то же, что syncDate, для необязательных строковых полей
+func syncDatePtr(legacy **string, typed **time.Time, layout string) bool {
+	switch {
+	case *typed == nil && *legacy != nil:
+		if t := utils.ParseDatePtr(*legacy); t != nil {
+			*typed = t
+			return true
+		}
+	case *typed != nil && *legacy == nil:
+		*legacy = utils.FormatPtr(*typed, layout)
+		return true
+	}
+	return false
+}
+
+func syncRuDates(ru *models.RUInfo) bool {
+	changed := syncDate(&ru.InstallationDate, &ru.InstallationDateAt, utils.LegacyDateLayout)
+	changed = syncDate(&ru.LastMaintenance, &ru.LastMaintenanceAt, utils.LegacyDateLayout) || changed
+	changed = syncDate(&ru.NextMaintenance, &ru.NextMaintenanceAt, utils.LegacyDateLayout) || changed
+	changed = syncDate(&ru.LastInspection, &ru.LastInspectionAt, utils.LegacyDateLayout) || changed
+	return changed
+}
+
+func syncCellDates(cell *models.Cell) bool {
+	changed := syncDatePtr(&cell.LastOperation, &cell.LastOperationAt, utils.LegacyDateTimeLayout)
+	changed = syncDatePtr(&cell.LastGroundedOperation, &cell.LastGroundedOperationAt, utils.LegacyDateTimeLayout) || changed
+	return changed
+}
+
+func syncRecordDates(record *models.OperationRecord) bool {
+	changed := syncDate(&record.Timestamp, &record.TimestampAt, utils.LegacyDateTimeLayout)
+	changed = syncDatePtr(&record.StartDate, &record.StartDateAt, utils.LegacyDateTimeLayout) || changed
+	changed = syncDatePtr(&record.EndDate, &record.EndDateAt, utils.LegacyDateTimeLayout) || changed
+	return changed
+}
+
+// unsyncedDates - условие на строки, где из пар «строковая колонка / timestamp» заполнена
+// только одна половина. Остальные строки уже синхронизированы и не читаются, так что
+// повторный запуск при старте не сканирует таблицы целиком.
+func unsyncedDates(pairs ...[2]string) string {
+	conditions := make([]string, len(pairs))
+	for i, pair := range pairs {
+		legacy, typed := pair[0], pair[1]
+		// Имена в кавычках: колонка timestamp совпадает с ключевым словом
+		conditions[i] = fmt.Sprintf(`("%[2]s" IS NULL AND "%[1]s" IS NOT NULL AND "%[1]s" <> '') OR ("%[2]s" IS NOT NULL AND ("%[1]s" IS NULL OR "%[1]s" = ''))`, legacy, typed)
+	}
+	return strings.Join(conditions, " OR ")
+}
+
+// BackfillTimestamps - заполняет типизированные даты у записей, созданных до миграции.
+// Читаются только записи с незаполненной половиной пары дат.
+func BackfillTimestamps(db *gorm.DB) error {
+	var rus []models.RUInfo
+	ruDates := unsyncedDates(
+		[2]string{"installation_date", "installation_date_at"},
+		[2]string{"last_maintenance", "last_maintenance_at"},
+		[2]string{"next_maintenance", "next_maintenance_at"},
+		[2]string{"last_inspection", "last_inspection_at"},
+	)
+	if err := db.Where(ruDates).Find(&rus).Error; err != nil {
+		return fmt.Errorf("failed to load RUs for backfill: %w", err)
+	}
+	updated := 0
+	for i := range rus {
+		if syncRuDates(&rus[i]) {
+			if err := db.Save(&rus[i]).Error; err != nil {
+				return fmt.Errorf("failed to backfill RU %s: %w", rus[i].ID, err)
+			}
+			updated++
+		}
+	}
+
+	var cells []models.Cell
+	cellDates := unsyncedDates(
+		[2]string{"last_operation", "last_operation_at"},
+		[2]string{"last_grounded_operation", "last_grounded_operation_at"},
+	)
+	if err := db.Where(cellDates).Find(&cells).Error; err != nil {
+		return fmt.Errorf("failed to load cells for backfill: %w", err)
+	}
+	for i := range cells {
+		if syncCellDates(&cells[i]) {
+			if err := db.Save(&cells[i]).Error; err != nil {
+				return fmt.Errorf("failed to backfill cell %d: %w", cells[i].ID, err)
+			}
+			updated++
+		}
+	}
+
+	var records []models.OperationRecord
+	recordDates := unsyncedDates(
+		[2]string{"timestamp", "timestamp_at"},
+		[2]string{"start_date", "start_date_at"},
+		[2]string{"end_date", "end_date_at"},
+	)
+	if err := db.Where(recordDates).Find(&records).Error; err != nil {
+		return fmt.Errorf("failed to load history for backfill: %w", err)
+	}
+	for i := range records {
+		if syncRecordDates(&records[i]) {
+			if err := allowJournalUpdate(db).Save(&records[i]).Error; err != nil {
+				return fmt.Errorf("failed to backfill history record %s: %w", records[i].ID, err)
+			}
+			updated++
+		}
+	}
+
+	if updated > 0 {
+		log.Printf("✅ Backfilled typed dates for %d records", updated)
+	}
+	return nil
+}
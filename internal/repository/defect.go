@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type DefectRepository struct {
+	db *gorm.DB
+}
+
+func NewDefectRepository(db *gorm.DB) *DefectRepository {
+	return &DefectRepository{db: db}
+}
+
+func (r *DefectRepository) GetDefects(filter models.DefectFilter) ([]models.Defect, error) {
+	var defects []models.Defect
+	query := scopeOrganizationRUs(r.db.Model(&models.Defect{}), "ru_id", filter.OrganizationID)
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.CellID != nil {
+		query = query.Where("cell_id = ?", *filter.CellID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
+	}
+	if filter.AssigneeID != "" {
+		query = query.Where("assignee_id = ?", filter.AssigneeID)
+	}
+	if err := query.Order("created_at DESC").Find(&defects).Error; err != nil {
+		return nil, fmt.Errorf("failed to get defects: %w", err)
+	}
+	return defects, nil
+}
+
+// GetOpenByAssignee - неустраненные дефекты, назначенные пользователю
+func (r *DefectRepository) GetOpenByAssignee(userID string) ([]models.Defect, error) {
+	var defects []models.Defect
+	err := r.db.Where("assignee_id = ? AND status <> ?", userID, models.DefectStatusFixed).
+		Order("created_at ASC").Find(&defects).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned defects: %w", err)
+	}
+	return defects, nil
+}
+
+func (r *DefectRepository) GetByID(id string) (*models.Defect, error) {
+	var defect models.Defect
+	if err := r.db.Where("id = ?", id).First(&defect).Error; err != nil {
+		return nil, fmt.Errorf("failed to get defect: %w", err)
+	}
+	return &defect, nil
+}
+
+// GetOrganizationDefect - дефект РУ организации; пустая организация - любой дефект
+func (r *DefectRepository) GetOrganizationDefect(id, organizationID string) (*models.Defect, error) {
+	var defect models.Defect
+	query := scopeOrganizationRUs(r.db.Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&defect).Error; err != nil {
+		return nil, fmt.Errorf("failed to get defect: %w", err)
+	}
+	return &defect, nil
+}
+
+func (r *DefectRepository) Create(defect *models.Defect) error {
+	if err := r.db.Create(defect).Error; err != nil {
+		return fmt.Errorf("failed to create defect: %w", err)
+	}
+	return nil
+}
+
+func (r *DefectRepository) Update(defect *models.Defect) error {
+	if err := r.db.Save(defect).Error; err != nil {
+		return fmt.Errorf("failed to update defect: %w", err)
+	}
+	return nil
+}
+
+// Delete - удаляет дефект вместе с фотографиями
+func (r *DefectRepository) Delete(id string) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.Defect{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		return tx.Where("owner_type = ? AND owner_id = ?", models.PhotoOwnerDefect, id).Delete(&models.Photo{}).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete defect: %w", err)
+	}
+	return deleted, nil
+}
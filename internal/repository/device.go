@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type DeviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) *DeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+func (r *DeviceRepository) GetDevices(filter models.DeviceFilter) ([]models.Device, error) {
+	var devices []models.Device
+	query := scopeOrganizationRUs(r.db.Preload("Cells"), "ru_id", filter.OrganizationID)
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Order("ru_id, name").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	return devices, nil
+}
+
+// GetEnabled - устройства, подлежащие контролю связи
+func (r *DeviceRepository) GetEnabled() ([]models.Device, error) {
+	var devices []models.Device
+	if err := r.db.Where("enabled = ?", true).Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("failed to get enabled devices: %w", err)
+	}
+	return devices, nil
+}
+
+// GetByID - устройство РУ организации; пустая организация - любое устройство
+func (r *DeviceRepository) GetByID(id, organizationID string) (*models.Device, error) {
+	var device models.Device
+	query := scopeOrganizationRUs(r.db.Preload("Cells").Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&device).Error; err != nil {
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	return &device, nil
+}
+
+// GetControlDevices - включенные устройства, через которые возможно телеуправление ячейкой
+func (r *DeviceRepository) GetControlDevices(cellID int) ([]models.Device, error) {
+	var devices []models.Device
+	err := r.db.Joins("JOIN device_cells ON device_cells.device_id = devices.id").
+		Where("device_cells.cell_id = ? AND devices.enabled = ? AND devices.type IN ?", cellID, true,
+			[]models.DeviceType{models.DeviceRTU, models.DeviceIED, models.DeviceGateway}).
+		Order("devices.name").
+		Find(&devices).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get control devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (r *DeviceRepository) Create(device *models.Device) error {
+	if err := r.db.Create(device).Error; err != nil {
+		return fmt.Errorf("failed to create device: %w", err)
+	}
+	return nil
+}
+
+// Update - сохраняет устройство и заменяет список обслуживаемых ячеек
+func (r *DeviceRepository) Update(device *models.Device) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", device.ID).Delete(&models.DeviceCell{}).Error; err != nil {
+			return err
+		}
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(device).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update device: %w", err)
+	}
+	return nil
+}
+
+func (r *DeviceRepository) Delete(id string) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", id).Delete(&models.DeviceCell{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.Device{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete device: %w", err)
+	}
+	return deleted, nil
+}
+
+// RecordContact - отметка о связи от самого устройства или шлюза; устройство сразу считается на связи
+func (r *DeviceRepository) RecordContact(id string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.Device{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_contact_at": at,
+		"last_error":      nil,
+		"status_since":    gorm.Expr("CASE WHEN status = ? THEN status_since ELSE ? END", models.DeviceStatusOnline, at),
+		"status":          models.DeviceStatusOnline,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record device contact: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveHealth - сохраняет результат проверки связи вместе с событиями в одной транзакции.
+// Если за время проверки от устройства пришла отметка о связи (last_contact_at отличается
+// от prevContact), результат устарел и не сохраняется.
+func (r *DeviceRepository) SaveHealth(device *models.Device, prevContact *time.Time, events []models.OutboxEvent) error {
+	updates := map[string]interface{}{
+		"status":          device.Status,
+		"status_since":    device.StatusSince,
+		"last_check_at":   device.LastCheckAt,
+		"last_error":      device.LastError,
+		"last_contact_at": device.LastContactAt,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Device{}).
+			Where("id = ? AND last_contact_at IS NOT DISTINCT FROM ?", device.ID, prevContact).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save device health: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// errPostgresOnly - операция опирается на системные представления и команды PostgreSQL
+var errPostgresOnly = errors.New("operation requires PostgreSQL")
+
+// IsPostgres - подключение к PostgreSQL; остальные диалекты (SQLite для локальной
+// разработки и тестов) используют переносимые варианты запросов
+func IsPostgres(db *gorm.DB) bool {
+	return db.Dialector.Name() == "postgres"
+}
+
+// requirePostgres - ошибка для операций, недоступных в других диалектах
+func requirePostgres(db *gorm.DB, operation string) error {
+	if !IsPostgres(db) {
+		return fmt.Errorf("%s: %w", operation, errPostgresOnly)
+	}
+	return nil
+}
+
+// epochBucket - начало интервала длиной step секунд, в который попадает column
+func epochBucket(db *gorm.DB, column string) string {
+	if IsPostgres(db) {
+		return fmt.Sprintf("to_timestamp(floor(extract(epoch FROM %s) / ?) * ?)", column)
+	}
+	return fmt.Sprintf("datetime((CAST(strftime('%%s', %s) AS INTEGER) / ?) * ?, 'unixepoch')", column)
+}
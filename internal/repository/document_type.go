@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type DocumentTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentTypeRepository(db *gorm.DB) *DocumentTypeRepository {
+	return &DocumentTypeRepository{db: db}
+}
+
+// SeedDocumentTypes - заполняет пустой справочник видов документов. Справочник, в котором
+// уже что-то есть, не трогается, чтобы удаленные администратором виды не возвращались.
+func SeedDocumentTypes(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.DocumentType{}).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to count document types: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	now := time.Now()
+	types := make([]models.DocumentType, len(models.DefaultDocumentTypes))
+	for i, documentType := range models.DefaultDocumentTypes {
+		documentType.Active = true
+		documentType.CreatedAt = now
+		documentType.UpdatedAt = now
+		types[i] = documentType
+	}
+	if err := db.Create(&types).Error; err != nil {
+		return fmt.Errorf("failed to seed document types: %w", err)
+	}
+	log.Printf("✅ Seeded %d document types", len(types))
+	return nil
+}
+
+// GetAll - виды документов по названию
+func (r *DocumentTypeRepository) GetAll(activeOnly bool) ([]models.DocumentType, error) {
+	var types []models.DocumentType
+	query := r.db.Order("name ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&types).Error; err != nil {
+		return nil, fmt.Errorf("failed to get document types: %w", err)
+	}
+	return types, nil
+}
+
+func (r *DocumentTypeRepository) GetByCode(code string) (*models.DocumentType, error) {
+	var documentType models.DocumentType
+	if err := r.db.Where("code = ?", code).First(&documentType).Error; err != nil {
+		return nil, err
+	}
+	return &documentType, nil
+}
+
+// Find - вид документа по коду или названию без учета регистра
+func (r *DocumentTypeRepository) Find(value string) (*models.DocumentType, error) {
+	var documentType models.DocumentType
+	err := r.db.Where("lower(code) = lower(?) OR lower(name) = lower(?)", value, value).
+		Order("code ASC").First(&documentType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &documentType, nil
+}
+
+func (r *DocumentTypeRepository) Create(documentType *models.DocumentType) error {
+	if err := r.db.Create(documentType).Error; err != nil {
+		return fmt.Errorf("failed to create document type: %w", err)
+	}
+	return nil
+}
+
+func (r *DocumentTypeRepository) Save(documentType *models.DocumentType) error {
+	if err := r.db.Save(documentType).Error; err != nil {
+		return fmt.Errorf("failed to save document type: %w", err)
+	}
+	return nil
+}
+
+// CountRecords - записи журнала с видом документа (по коду в записи или в исправлении)
+func (r *DocumentTypeRepository) CountRecords(code string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.OperationRecord{}).
+		Where("document_type = ?", code).
+		Or("id IN (?)", r.db.Model(&models.OperationCorrection{}).
+			Select("operation_corrections.record_id").
+			Joins("JOIN operation_correction_fields f ON f.correction_id = operation_corrections.id").
+			Where("f.field = 'documentType' AND f.to_value = ?", code)).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count document type records: %w", err)
+	}
+	return count, nil
+}
+
+func (r *DocumentTypeRepository) Delete(code string) (bool, error) {
+	result := r.db.Where("code = ?", code).Delete(&models.DocumentType{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete document type: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
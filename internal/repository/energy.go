@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EnergyRepository struct {
+	db *gorm.DB
+}
+
+func NewEnergyRepository(db *gorm.DB) *EnergyRepository {
+	return &EnergyRepository{db: db}
+}
+
+// SaveReadings - сохраняет показания; повтор на тот же момент заменяет значение
+func (r *EnergyRepository) SaveReadings(readings []models.MeterReading) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cell_id"}, {Name: "read_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"energy_kwh", "source", "meter_replaced", "recorded_by"}),
+	}).CreateInBatches(readings, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to save meter readings: %w", err)
+	}
+	return nil
+}
+
+// GetReadings - показания ячеек за период [from, to] по возрастанию времени
+func (r *EnergyRepository) GetReadings(cellIDs []int, from, to time.Time) ([]models.MeterReading, error) {
+	var readings []models.MeterReading
+	if len(cellIDs) == 0 {
+		return readings, nil
+	}
+	result := r.db.Where("cell_id IN ? AND read_at >= ? AND read_at <= ?", cellIDs, from, to).
+		Order("cell_id, read_at ASC").
+		Find(&readings)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get meter readings: %w", result.Error)
+	}
+	return readings, nil
+}
+
+// GetLastReadings - последнее показание каждой ячейки на момент at (включительно)
+func (r *EnergyRepository) GetLastReadings(cellIDs []int, at time.Time) (map[int]models.MeterReading, error) {
+	last := make(map[int]models.MeterReading, len(cellIDs))
+	if len(cellIDs) == 0 {
+		return last, nil
+	}
+	var readings []models.MeterReading
+	latest := r.db.Model(&models.MeterReading{}).
+		Select("cell_id, MAX(read_at)").
+		Where("cell_id IN ? AND read_at <= ?", cellIDs, at).
+		Group("cell_id")
+	if err := r.db.Where("(cell_id, read_at) IN (?)", latest).Find(&readings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get last meter readings: %w", err)
+	}
+	for _, reading := range readings {
+		last[reading.CellID] = reading
+	}
+	return last, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FaultRepository struct {
+	db *gorm.DB
+}
+
+func NewFaultRepository(db *gorm.DB) *FaultRepository {
+	return &FaultRepository{db: db}
+}
+
+// Create - записывает отключение вместе с событием fault.recorded в одной транзакции
+func (r *FaultRepository) Create(fault *models.FaultEvent, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(fault).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create fault event: %w", err)
+	}
+	return nil
+}
+
+func (r *FaultRepository) GetByExternalID(externalID string) (*models.FaultEvent, error) {
+	var fault models.FaultEvent
+	if err := r.db.Where("external_id = ?", externalID).First(&fault).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fault event: %w", err)
+	}
+	return &fault, nil
+}
+
+func (r *FaultRepository) GetByID(ruID, id string) (*models.FaultEvent, error) {
+	var fault models.FaultEvent
+	if err := r.db.Where("ru_id = ? AND id = ?", ruID, id).First(&fault).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fault event: %w", err)
+	}
+	faults := []models.FaultEvent{fault}
+	if err := r.attachAlarms(faults); err != nil {
+		return nil, err
+	}
+	return &faults[0], nil
+}
+
+// GetFaults - журнал отключений РУ, новые сверху, со связанными авариями
+func (r *FaultRepository) GetFaults(ruID string, filter models.FaultFilter) ([]models.FaultEvent, error) {
+	var faults []models.FaultEvent
+	query := r.db.Where("ru_id = ?", ruID)
+	if filter.CellID != nil {
+		query = query.Where("cell_id = ?", *filter.CellID)
+	}
+	if filter.TripType != "" {
+		query = query.Where("trip_type = ?", filter.TripType)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+	if err := query.Order("occurred_at DESC").Find(&faults).Error; err != nil {
+		return nil, fmt.Errorf("failed to get fault events: %w", err)
+	}
+	if err := r.attachAlarms(faults); err != nil {
+		return nil, err
+	}
+	return faults, nil
+}
+
+// attachAlarms - подставляет аварии, зарегистрированные по событиям отключений
+func (r *FaultRepository) attachAlarms(faults []models.FaultEvent) error {
+	if len(faults) == 0 {
+		return nil
+	}
+	eventIDs := make([]string, len(faults))
+	for i := range faults {
+		eventIDs[i] = faults[i].EventID
+	}
+
+	var alarms []models.Alarm
+	if err := r.db.Where("event_id IN ?", eventIDs).Find(&alarms).Error; err != nil {
+		return fmt.Errorf("failed to get fault alarms: %w", err)
+	}
+	byEvent := make(map[string]*models.Alarm, len(alarms))
+	for i := range alarms {
+		byEvent[alarms[i].EventID] = &alarms[i]
+	}
+	for i := range faults {
+		faults[i].Alarm = byEvent[faults[i].EventID]
+	}
+	return nil
+}
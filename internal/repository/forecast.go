@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ForecastRepository struct {
+	db *gorm.DB
+}
+
+func NewForecastRepository(db *gorm.DB) *ForecastRepository {
+	return &ForecastRepository{db: db}
+}
+
+// CreateRun - сохраняет прогноз вместе с почасовыми значениями
+func (r *ForecastRepository) CreateRun(run *models.ForecastRun) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		for i := range run.Points {
+			run.Points[i].RunID = run.ID
+		}
+		if len(run.Points) > 0 {
+			return tx.CreateInBatches(run.Points, 500).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create forecast run: %w", err)
+	}
+	return nil
+}
+
+// GetLatestRun - последний прогноз ячейки с тем же горизонтом, построенный не раньше since
+func (r *ForecastRepository) GetLatestRun(cellID int, horizon string, since time.Time) (*models.ForecastRun, error) {
+	var runs []models.ForecastRun
+	result := r.db.Where("cell_id = ? AND horizon = ? AND created_at >= ?", cellID, horizon, since).
+		Order("created_at DESC").Limit(1).Find(&runs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get forecast run: %w", result.Error)
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	return &runs[0], nil
+}
+
+// GetRuns - прогнозы РУ (или ячейки) от новых к старым
+func (r *ForecastRepository) GetRuns(ruID string, cellID *int, limit int) ([]models.ForecastRun, error) {
+	var runs []models.ForecastRun
+	query := r.db.Where("ru_id = ?", ruID)
+	if cellID != nil {
+		query = query.Where("cell_id = ?", *cellID)
+	}
+	if err := query.Order("created_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get forecast runs: %w", err)
+	}
+	return runs, nil
+}
+
+// GetPendingEvaluation - прогнозы, горизонт которых прошел, а точность еще не оценена
+func (r *ForecastRepository) GetPendingEvaluation(before time.Time, limit int) ([]models.ForecastRun, error) {
+	var runs []models.ForecastRun
+	result := r.db.Where("evaluated_at IS NULL AND end_at <= ?", before).
+		Order("end_at ASC").Limit(limit).Find(&runs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get forecast runs: %w", result.Error)
+	}
+	return runs, nil
+}
+
+func (r *ForecastRepository) GetPoints(runID string) ([]models.ForecastPoint, error) {
+	var points []models.ForecastPoint
+	if err := r.db.Where("run_id = ?", runID).Order("at ASC").Find(&points).Error; err != nil {
+		return nil, fmt.Errorf("failed to get forecast points: %w", err)
+	}
+	return points, nil
+}
+
+// SaveEvaluation - фактические значения и метрики точности прогноза
+func (r *ForecastRepository) SaveEvaluation(run *models.ForecastRun, points []models.ForecastPoint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, point := range points {
+			if point.Actual == nil {
+				continue
+			}
+			err := tx.Model(&models.ForecastPoint{}).
+				Where("run_id = ? AND at = ?", run.ID, point.At).
+				Update("actual", *point.Actual).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.ForecastRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+			"evaluated_at": run.EvaluatedAt,
+			"compared":     run.Compared,
+			"mae":          run.MAE,
+			"mape":         run.MAPE,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save forecast evaluation: %w", err)
+	}
+	return nil
+}
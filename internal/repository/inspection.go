@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type InspectionRepository struct {
+	db *gorm.DB
+}
+
+func NewInspectionRepository(db *gorm.DB) *InspectionRepository {
+	return &InspectionRepository{db: db}
+}
+
+func orderItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// GetTemplates - шаблоны для типа РУ (включая общие); пустой ruType - все шаблоны
+func (r *InspectionRepository) GetTemplates(ruType string, activeOnly bool) ([]models.ChecklistTemplate, error) {
+	var templates []models.ChecklistTemplate
+	query := r.db.Preload("Items", orderItems).Order("name ASC")
+	if ruType != "" {
+		query = query.Where("ru_type = ? OR ru_type = ''", ruType)
+	}
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to get checklist templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *InspectionRepository) GetTemplate(id string) (*models.ChecklistTemplate, error) {
+	var template models.ChecklistTemplate
+	if err := r.db.Preload("Items", orderItems).Where("id = ?", id).First(&template).Error; err != nil {
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+	return &template, nil
+}
+
+func (r *InspectionRepository) CreateTemplate(template *models.ChecklistTemplate) error {
+	if err := r.db.Create(template).Error; err != nil {
+		return fmt.Errorf("failed to create checklist template: %w", err)
+	}
+	return nil
+}
+
+// ReplaceTemplate - обновляет шаблон и полностью заменяет его пункты
+func (r *InspectionRepository) ReplaceTemplate(template *models.ChecklistTemplate) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", template.ID).Delete(&models.ChecklistItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(template).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update checklist template: %w", err)
+	}
+	return nil
+}
+
+func (r *InspectionRepository) DeleteTemplate(id string) (bool, error) {
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&models.ChecklistItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&models.ChecklistTemplate{})
+		deleted = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete checklist template: %w", err)
+	}
+	return deleted, nil
+}
+
+// GetInspections - осмотры РУ без результатов по пунктам
+func (r *InspectionRepository) GetInspections(ruID string, limit int) ([]models.Inspection, error) {
+	var inspections []models.Inspection
+	query := r.db.Where("ru_id = ?", ruID).Order("inspected_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&inspections).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inspections: %w", err)
+	}
+	return inspections, nil
+}
+
+// GetInspection - осмотр РУ организации; пустая организация - любой осмотр
+func (r *InspectionRepository) GetInspection(id, organizationID string) (*models.Inspection, error) {
+	var inspection models.Inspection
+	query := scopeOrganizationRUs(r.db.Preload("Results", orderItems).Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&inspection).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inspection: %w", err)
+	}
+	return &inspection, nil
+}
+
+// GetResult - результат пункта осмотра РУ организации; пустая организация - любой осмотр
+func (r *InspectionRepository) GetResult(inspectionID, resultID, organizationID string) (*models.InspectionResult, error) {
+	var result models.InspectionResult
+	inspections := scopeOrganizationRUs(r.db.Model(&models.Inspection{}).Select("id").Where("id = ?", inspectionID), "ru_id", organizationID)
+	if err := r.db.Where("id = ? AND inspection_id IN (?)", resultID, inspections).First(&result).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inspection result: %w", err)
+	}
+	return &result, nil
+}
+
+// SubmitInspection - сохраняет осмотр с результатами, дефекты по непройденным
+// пунктам и (если передано) РУ с новой датой осмотра - одной транзакцией
+func (r *InspectionRepository) SubmitInspection(inspection *models.Inspection, ru *models.RUInfo, defects []models.Defect) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(inspection).Error; err != nil {
+			return err
+		}
+		if len(defects) > 0 {
+			if err := tx.Create(&defects).Error; err != nil {
+				return err
+			}
+		}
+		if ru != nil {
+			syncRuDates(ru)
+			syncRuCapacity(ru)
+			return tx.Save(ru).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit inspection: %w", err)
+	}
+	return nil
+}
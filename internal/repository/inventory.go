@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) *InventoryRepository {
+	return &InventoryRepository{db: db}
+}
+
+// StockChange - записи, сохраняемые вместе с измененным остатком
+type StockChange struct {
+	Reservation *models.Reservation
+	Movement    *models.StockMovement
+	Events      []models.OutboxEvent
+}
+
+// GetWarehouses - склады организации; пустая организация - все склады
+func (r *InventoryRepository) GetWarehouses(organizationID string) ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	query := r.db.Order("name ASC")
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if err := query.Find(&warehouses).Error; err != nil {
+		return nil, fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	return warehouses, nil
+}
+
+// GetWarehouse - склад организации; пустая организация - любой склад
+func (r *InventoryRepository) GetWarehouse(id, organizationID string) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	query := r.db.Where("id = ?", id)
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if err := query.First(&warehouse).Error; err != nil {
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	return &warehouse, nil
+}
+
+func (r *InventoryRepository) CreateWarehouse(warehouse *models.Warehouse) error {
+	if err := r.db.Create(warehouse).Error; err != nil {
+		return fmt.Errorf("failed to create warehouse: %w", err)
+	}
+	return nil
+}
+
+func (r *InventoryRepository) GetItems(category string) ([]models.InventoryItem, error) {
+	var items []models.InventoryItem
+	query := r.db.Order("name ASC")
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	if err := query.Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inventory items: %w", err)
+	}
+	return items, nil
+}
+
+func (r *InventoryRepository) GetItem(id string) (*models.InventoryItem, error) {
+	var item models.InventoryItem
+	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+		return nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	return &item, nil
+}
+
+func (r *InventoryRepository) ExistsBySKU(sku string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.InventoryItem{}).Where("sku = ?", sku).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check SKU: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (r *InventoryRepository) CreateItem(item *models.InventoryItem) error {
+	if err := r.db.Create(item).Error; err != nil {
+		return fmt.Errorf("failed to create inventory item: %w", err)
+	}
+	return nil
+}
+
+func (r *InventoryRepository) GetStock(filter models.StockFilter) ([]models.StockLevel, error) {
+	var levels []models.StockLevel
+	query := r.db.Model(&models.StockLevel{})
+	if filter.OrganizationID != "" {
+		query = query.Where("warehouse_id IN (SELECT id FROM warehouses WHERE organization_id = ?)", filter.OrganizationID)
+	}
+	if filter.WarehouseID != "" {
+		query = query.Where("warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.ItemID != "" {
+		query = query.Where("item_id = ?", filter.ItemID)
+	}
+	if filter.Low {
+		query = query.Where("min_quantity > 0 AND quantity - reserved < min_quantity")
+	}
+	if err := query.Order("warehouse_id ASC, item_id ASC").Find(&levels).Error; err != nil {
+		return nil, fmt.Errorf("failed to get stock levels: %w", err)
+	}
+	for i := range levels {
+		levels[i].Fill()
+	}
+	return levels, nil
+}
+
+func (r *InventoryRepository) GetReservations(filter models.ReservationFilter) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	query := scopeOrganizationRUs(r.db.Model(&models.Reservation{}), "ru_id", filter.OrganizationID)
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Order("created_at DESC").Find(&reservations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// GetReservation - резерв под РУ организации; пустая организация - любой резерв
+func (r *InventoryRepository) GetReservation(id, organizationID string) (*models.Reservation, error) {
+	var reservation models.Reservation
+	query := scopeOrganizationRUs(r.db.Where("id = ?", id), "ru_id", organizationID)
+	if err := query.First(&reservation).Error; err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	return &reservation, nil
+}
+
+// UpdateStock - блокирует строку остатка (и резерв, если передан его ID), применяет
+// изменение и сохраняет остаток вместе с резервом, движением и событиями одной транзакцией.
+// Блокировка сериализует параллельные резервирования одной позиции.
+func (r *InventoryRepository) UpdateStock(warehouseID, itemID, reservationID string, apply func(level *models.StockLevel, reservation *models.Reservation) (*StockChange, error)) (*models.StockLevel, error) {
+	var level models.StockLevel
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		seed := models.StockLevel{WarehouseID: warehouseID, ItemID: itemID, UpdatedAt: time.Now()}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seed).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("warehouse_id = ? AND item_id = ?", warehouseID, itemID).First(&level).Error; err != nil {
+			return err
+		}
+
+		var reservation *models.Reservation
+		if reservationID != "" {
+			reservation = &models.Reservation{}
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", reservationID).First(reservation).Error; err != nil {
+				return err
+			}
+		}
+
+		change, err := apply(&level, reservation)
+		if err != nil {
+			return err
+		}
+
+		level.UpdatedAt = time.Now()
+		if err := tx.Save(&level).Error; err != nil {
+			return err
+		}
+		if change.Reservation != nil {
+			if err := tx.Save(change.Reservation).Error; err != nil {
+				return err
+			}
+		}
+		if change.Movement != nil {
+			if err := tx.Create(change.Movement).Error; err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, change.Events)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update stock: %w", err)
+	}
+	level.Fill()
+	return &level, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobLockRepository struct {
+	db *gorm.DB
+}
+
+func NewJobLockRepository(db *gorm.DB) *JobLockRepository {
+	return &JobLockRepository{db: db}
+}
+
+// TryLock - берет или продлевает блокировку одним UPDATE, поэтому из нескольких
+// экземпляров ее получает только один. Блокировка свободна, если истекла или уже
+// принадлежит holder. С slot блокировка берется только для запуска по расписанию,
+// который еще не взял ни один экземпляр.
+func (r *JobLockRepository) TryLock(name, holder string, slot *time.Time, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.JobLock{Name: name, UpdatedAt: now}).Error; err != nil {
+		return false, fmt.Errorf("failed to create job lock: %w", err)
+	}
+
+	updates := map[string]interface{}{"locked_by": holder, "locked_until": now.Add(ttl), "updated_at": now}
+	query := r.db.Model(&models.JobLock{}).
+		Where("name = ?", name).
+		Where("(locked_until IS NULL OR locked_until < ? OR locked_by = ?)", now, holder)
+	if slot != nil {
+		query = query.Where("(last_slot IS NULL OR last_slot < ?)", *slot)
+		updates["last_slot"] = *slot
+	}
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to lock job: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Unlock - снимает блокировку, если она еще принадлежит holder
+func (r *JobLockRepository) Unlock(name, holder string) error {
+	err := r.db.Model(&models.JobLock{}).
+		Where("name = ? AND locked_by = ?", name, holder).
+		Updates(map[string]interface{}{"locked_until": nil, "updated_at": time.Now()}).Error
+	if err != nil {
+		return fmt.Errorf("failed to unlock job: %w", err)
+	}
+	return nil
+}
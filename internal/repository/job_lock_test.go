@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestJobLock(t *testing.T) {
+	slot := time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC)
+	nextSlot := slot.Add(24 * time.Hour)
+
+	// step - попытка экземпляра взять блокировку (или снять ее при unlock)
+	type step struct {
+		holder string
+		slot   *time.Time
+		ttl    time.Duration
+		unlock bool
+		want   bool
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "only one instance holds the lock",
+			steps: []step{
+				{holder: "a", ttl: time.Minute, want: true},
+				{holder: "b", ttl: time.Minute, want: false},
+				// Владелец продлевает свою блокировку
+				{holder: "a", ttl: time.Minute, want: true},
+			},
+		},
+		{
+			name: "released lock is free",
+			steps: []step{
+				{holder: "a", ttl: time.Minute, want: true},
+				{holder: "a", unlock: true},
+				{holder: "b", ttl: time.Minute, want: true},
+			},
+		},
+		{
+			name: "unlock by another instance does not release",
+			steps: []step{
+				{holder: "a", ttl: time.Minute, want: true},
+				{holder: "b", unlock: true},
+				{holder: "b", ttl: time.Minute, want: false},
+			},
+		},
+		{
+			// Блокировка упавшего экземпляра истекает сама
+			name: "expired lock is taken over",
+			steps: []step{
+				{holder: "a", ttl: -time.Second, want: true},
+				{holder: "b", ttl: time.Minute, want: true},
+				{holder: "a", ttl: time.Minute, want: false},
+			},
+		},
+		{
+			name: "scheduled slot runs once",
+			steps: []step{
+				{holder: "a", slot: &slot, ttl: time.Minute, want: true},
+				{holder: "a", unlock: true},
+				{holder: "b", slot: &slot, ttl: time.Minute, want: false},
+				{holder: "a", slot: &slot, ttl: time.Minute, want: false},
+				{holder: "b", slot: &nextSlot, ttl: time.Minute, want: true},
+			},
+		},
+		{
+			name: "older slot is not run after newer one",
+			steps: []step{
+				{holder: "a", slot: &nextSlot, ttl: time.Minute, want: true},
+				{holder: "a", unlock: true},
+				{holder: "b", slot: &slot, ttl: time.Minute, want: false},
+			},
+		},
+		{
+			name: "manual run does not consume the slot",
+			steps: []step{
+				{holder: "a", ttl: time.Minute, want: true},
+				{holder: "a", unlock: true},
+				{holder: "b", slot: &slot, ttl: time.Minute, want: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewJobLockRepository(newTestDB(t, &models.JobLock{}))
+			for i, s := range tt.steps {
+				if s.unlock {
+					if err := repo.Unlock("job", s.holder); err != nil {
+						t.Fatalf("step %d: Unlock: %v", i, err)
+					}
+					continue
+				}
+				got, err := repo.TryLock("job", s.holder, s.slot, s.ttl)
+				if err != nil {
+					t.Fatalf("step %d: TryLock: %v", i, err)
+				}
+				if got != s.want {
+					t.Fatalf("step %d: TryLock(%s) = %v, want %v", i, s.holder, got, s.want)
+				}
+			}
+		})
+	}
+}
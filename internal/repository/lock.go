@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CellLockRepository struct {
+	db *gorm.DB
+}
+
+func NewCellLockRepository(db *gorm.DB) *CellLockRepository {
+	return &CellLockRepository{db: db}
+}
+
+// GetActiveLock - активный замок ячейки или nil, если ячейка не заблокирована
+func (r *CellLockRepository) GetActiveLock(cellID int) (*models.CellLock, error) {
+	var lock models.CellLock
+	result := r.db.Where("cell_id = ? AND removed_at IS NULL", cellID).First(&lock)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cell lock: %w", result.Error)
+	}
+	return &lock, nil
+}
+
+// GetActiveLocks - активные замки нескольких ячеек по идентификатору ячейки
+func (r *CellLockRepository) GetActiveLocks(cellIDs []int) (map[int]models.CellLock, error) {
+	locks := map[int]models.CellLock{}
+	if len(cellIDs) == 0 {
+		return locks, nil
+	}
+	var rows []models.CellLock
+	if err := r.db.Where("cell_id IN ? AND removed_at IS NULL", cellIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell locks: %w", err)
+	}
+	for _, lock := range rows {
+		locks[lock.CellID] = lock
+	}
+	return locks, nil
+}
+
+// GetLocks - история замков ячейки, последние первыми
+func (r *CellLockRepository) GetLocks(cellID int) ([]models.CellLock, error) {
+	var locks []models.CellLock
+	if err := r.db.Where("cell_id = ?", cellID).Order("placed_at DESC").Find(&locks).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cell locks: %w", err)
+	}
+	return locks, nil
+}
+
+// CreateLock - устанавливает замок вместе с записью в журнале операций
+func (r *CellLockRepository) CreateLock(lock *models.CellLock, record *models.OperationRecord) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(lock).Error; err != nil {
+			return err
+		}
+		return createHistoryRecord(tx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cell lock: %w", err)
+	}
+	return nil
+}
+
+// SaveRemoval - сохраняет снятие замка вместе с записью в журнале операций
+func (r *CellLockRepository) SaveRemoval(lock *models.CellLock, record *models.OperationRecord) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(lock).Error; err != nil {
+			return err
+		}
+		return createHistoryRecord(tx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove cell lock: %w", err)
+	}
+	return nil
+}
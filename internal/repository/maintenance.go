@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MaintenanceTables - нагруженные таблицы, для которых разрешены задачи обслуживания
+var MaintenanceTables = []string{"cells", "operation_records", "ru_infos", "outbox_events"}
+
+type MaintenanceRepository struct {
+	db *gorm.DB
+}
+
+func NewMaintenanceRepository(db *gorm.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+// quoteIdent - экранирует идентификатор PostgreSQL
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// GetIndexes - индексы указанных таблиц в схеме public
+func (r *MaintenanceRepository) GetIndexes(tables []string) ([]string, error) {
+	if err := requirePostgres(r.db, "list indexes"); err != nil {
+		return nil, err
+	}
+	var indexes []string
+	result := r.db.Raw(
+		"SELECT indexname FROM pg_indexes WHERE schemaname = 'public' AND tablename IN ? ORDER BY tablename, indexname",
+		tables,
+	).Scan(&indexes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", result.Error)
+	}
+	return indexes, nil
+}
+
+// ReindexConcurrently - перестраивает индекс без блокировки записи.
+// REINDEX CONCURRENTLY нельзя выполнять внутри транзакции. Выполняется со сроком ctx,
+// а не с общим тайм-аутом запросов: на большой таблице он длится дольше.
+func (r *MaintenanceRepository) ReindexConcurrently(ctx context.Context, index string) error {
+	if err := requirePostgres(r.db, "reindex"); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Exec("REINDEX INDEX CONCURRENTLY " + quoteIdent(index)).Error; err != nil {
+		return fmt.Errorf("failed to reindex %s: %w", index, err)
+	}
+	return nil
+}
+
+// VacuumAnalyze - VACUUM (ANALYZE) таблицы; не блокирует чтение и запись. Срок
+// выполнения задает ctx, как у ReindexConcurrently.
+func (r *MaintenanceRepository) VacuumAnalyze(ctx context.Context, table string) error {
+	if err := requirePostgres(r.db, "vacuum"); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Exec("VACUUM (ANALYZE) " + quoteIdent(table)).Error; err != nil {
+		return fmt.Errorf("failed to vacuum %s: %w", table, err)
+	}
+	return nil
+}
+
+// PruneOutboxBatch - удаляет пачку обработанных событий outbox старше указанного момента;
+// с keepUnpublished события, еще не опубликованные во внешний брокер, остаются
+func (r *MaintenanceRepository) PruneOutboxBatch(before time.Time, batch int, keepUnpublished bool) (int64, error) {
+	condition := "status = ? AND processed_at < ?"
+	if keepUnpublished {
+		condition += " AND broker_published_at IS NOT NULL"
+	}
+	result := r.db.Exec(
+		"DELETE FROM outbox_events WHERE id IN (SELECT id FROM outbox_events WHERE "+condition+" LIMIT ?)",
+		models.OutboxStatusProcessed, before, batch,
+	)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// GetProgress - ход выполняемых REINDEX или VACUUM по системным представлениям pg_stat_progress_*
+func (r *MaintenanceRepository) GetProgress(jobType models.MaintenanceJobType) (*models.MaintenanceProgress, error) {
+	if err := requirePostgres(r.db, "maintenance progress"); err != nil {
+		return nil, err
+	}
+	var query string
+	switch jobType {
+	case models.MaintenanceReindex:
+		query = "SELECT phase, blocks_done, blocks_total FROM pg_stat_progress_create_index WHERE datname = current_database() LIMIT 1"
+	case models.MaintenanceVacuumAnalyze:
+		query = "SELECT phase, heap_blks_scanned AS blocks_done, heap_blks_total AS blocks_total FROM pg_stat_progress_vacuum WHERE datname = current_database() LIMIT 1"
+	default:
+		return nil, nil
+	}
+
+	var rows []struct {
+		Phase       string
+		BlocksDone  int64
+		BlocksTotal int64
+	}
+	if err := r.db.Raw(query).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get maintenance progress: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &models.MaintenanceProgress{
+		Phase:       rows[0].Phase,
+		BlocksDone:  rows[0].BlocksDone,
+		BlocksTotal: rows[0].BlocksTotal,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestPruneOutboxBatch(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -40)
+	recent := now.AddDate(0, 0, -1)
+	seed := []models.OutboxEvent{
+		{ID: "old-published", Status: models.OutboxStatusProcessed, ProcessedAt: &old, BrokerPublishedAt: &old},
+		{ID: "old-unpublished", Status: models.OutboxStatusProcessed, ProcessedAt: &old},
+		{ID: "old-pending", Status: models.OutboxStatusPending},
+		{ID: "recent", Status: models.OutboxStatusProcessed, ProcessedAt: &recent, BrokerPublishedAt: &recent},
+	}
+
+	tests := []struct {
+		name            string
+		keepUnpublished bool
+		wantLeft        []string
+	}{
+		{
+			name:     "without broker all old processed events are pruned",
+			wantLeft: []string{"old-pending", "recent"},
+		},
+		{
+			// Событие, которое брокер еще не опубликовал, нельзя удалять
+			name:            "with broker unpublished events stay",
+			keepUnpublished: true,
+			wantLeft:        []string{"old-pending", "old-unpublished", "recent"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.OutboxEvent{})
+			events := slices.Clone(seed)
+			if err := db.Create(&events).Error; err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+			repo := NewMaintenanceRepository(db)
+			if _, err := repo.PruneOutboxBatch(now.AddDate(0, 0, -30), 100, tt.keepUnpublished); err != nil {
+				t.Fatalf("prune: %v", err)
+			}
+			var left []string
+			if err := db.Model(&models.OutboxEvent{}).Order("id").Pluck("id", &left).Error; err != nil {
+				t.Fatalf("load events: %v", err)
+			}
+			if !slices.Equal(left, tt.wantLeft) {
+				t.Errorf("left = %v, want %v", left, tt.wantLeft)
+			}
+		})
+	}
+}
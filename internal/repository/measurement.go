@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MeasurementRepository struct {
+	db *gorm.DB
+}
+
+func NewMeasurementRepository(db *gorm.DB) *MeasurementRepository {
+	return &MeasurementRepository{db: db}
+}
+
+func (r *MeasurementRepository) CreateMeasurements(measurements []models.Measurement) error {
+	if err := r.db.CreateInBatches(measurements, 500).Error; err != nil {
+		return fmt.Errorf("failed to create measurements: %w", err)
+	}
+	return nil
+}
+
+// RollupRaw - агрегирует сырые измерения интервала [from, to) в бакеты размера step.
+// Повторный запуск пересчитывает бакеты (ON CONFLICT DO UPDATE), поэтому поздние данные учитываются.
+func (r *MeasurementRepository) RollupRaw(resolution models.RollupResolution, step time.Duration, from, to time.Time) (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO measurement_rollups (resolution, cell_id, metric, bucket_start, ru_id, min, max, sum, count)
+		SELECT ?, cell_id, metric, `+epochBucket(r.db, "measured_at")+`, MAX(ru_id),
+		       MIN(value), MAX(value), SUM(value), COUNT(*)
+		FROM measurements
+		WHERE measured_at >= ? AND measured_at < ?
+		GROUP BY cell_id, metric, 4
+		ON CONFLICT (resolution, cell_id, metric, bucket_start) DO UPDATE
+		SET min = EXCLUDED.min, max = EXCLUDED.max, sum = EXCLUDED.sum, count = EXCLUDED.count`,
+		resolution, stepSeconds(step), stepSeconds(step), from, to,
+	)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to roll up raw measurements: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// RollupFrom - агрегирует бакеты меньшего разрешения в бакеты большего
+func (r *MeasurementRepository) RollupFrom(source, target models.RollupResolution, step time.Duration, from, to time.Time) (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO measurement_rollups (resolution, cell_id, metric, bucket_start, ru_id, min, max, sum, count, score)
+		SELECT ?, cell_id, metric, `+epochBucket(r.db, "bucket_start")+`, MAX(ru_id),
+		       MIN(min), MAX(max), SUM(sum), SUM(count), MAX(score)
+		FROM measurement_rollups
+		WHERE resolution = ? AND bucket_start >= ? AND bucket_start < ?
+		GROUP BY cell_id, metric, 4
+		ON CONFLICT (resolution, cell_id, metric, bucket_start) DO UPDATE
+		SET min = EXCLUDED.min, max = EXCLUDED.max, sum = EXCLUDED.sum, count = EXCLUDED.count, score = EXCLUDED.score`,
+		target, stepSeconds(step), stepSeconds(step), source, from, to,
+	)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to roll up %s measurements: %w", source, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteRawBefore - удаляет сырые измерения старше указанного момента
+func (r *MeasurementRepository) DeleteRawBefore(before time.Time) (int64, error) {
+	result := r.db.Where("measured_at < ?", before).Delete(&models.Measurement{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire raw measurements: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteRollupsBefore - удаляет агрегаты разрешения старше указанного момента
+func (r *MeasurementRepository) DeleteRollupsBefore(resolution models.RollupResolution, before time.Time) (int64, error) {
+	result := r.db.Where("resolution = ? AND bucket_start < ?", resolution, before).Delete(&models.MeasurementRollup{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to expire %s rollups: %w", resolution, result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *MeasurementRepository) GetRaw(cellID int, metric models.MeasurementMetric, from, to time.Time) ([]models.Measurement, error) {
+	var measurements []models.Measurement
+	result := r.db.Where("cell_id = ? AND metric = ? AND measured_at >= ? AND measured_at < ?", cellID, metric, from, to).
+		Order("measured_at ASC").
+		Find(&measurements)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get measurements: %w", result.Error)
+	}
+	return measurements, nil
+}
+
+func (r *MeasurementRepository) GetRollups(resolution models.RollupResolution, cellID int, metric models.MeasurementMetric, from, to time.Time) ([]models.MeasurementRollup, error) {
+	var rollups []models.MeasurementRollup
+	result := r.db.Where("resolution = ? AND cell_id = ? AND metric = ? AND bucket_start >= ? AND bucket_start < ?", resolution, cellID, metric, from, to).
+		Order("bucket_start ASC").
+		Find(&rollups)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get measurement rollups: %w", result.Error)
+	}
+	for i := range rollups {
+		if rollups[i].Count > 0 {
+			rollups[i].Avg = rollups[i].Sum / float64(rollups[i].Count)
+		}
+	}
+	return rollups, nil
+}
+
+// GetRecentRollups - агрегаты всех ячеек по метрикам за [from, to) в порядке времени
+func (r *MeasurementRepository) GetRecentRollups(resolution models.RollupResolution, metrics []models.MeasurementMetric, from, to time.Time) ([]models.MeasurementRollup, error) {
+	var rollups []models.MeasurementRollup
+	result := r.db.Where("resolution = ? AND metric IN ? AND bucket_start >= ? AND bucket_start < ?", resolution, metrics, from, to).
+		Order("bucket_start ASC").
+		Find(&rollups)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get measurement rollups: %w", result.Error)
+	}
+	for i := range rollups {
+		if rollups[i].Count > 0 {
+			rollups[i].Avg = rollups[i].Sum / float64(rollups[i].Count)
+		}
+	}
+	return rollups, nil
+}
+
+func stepSeconds(d time.Duration) int64 {
+	return int64(d.Seconds())
+}
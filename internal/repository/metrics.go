@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MetricsRepository - агрегаты для метрик Prometheus
+type MetricsRepository struct {
+	db *gorm.DB
+}
+
+func NewMetricsRepository(db *gorm.DB) *MetricsRepository {
+	return &MetricsRepository{db: db}
+}
+
+// RuCount - число объектов РУ с необязательной меткой (важность аварии)
+type RuCount struct {
+	RuID  string
+	Label string
+	Count int
+}
+
+// CountErrorCells - ячейки в состоянии ERROR по действующим РУ
+func (r *MetricsRepository) CountErrorCells() ([]RuCount, error) {
+	var rows []RuCount
+	err := r.db.Model(&models.Cell{}).
+		Joins("JOIN ru_infos ON ru_infos.id = cells.ru_id AND ru_infos.archived_at IS NULL").
+		Where("cells.status = ?", models.CellStatusError).
+		Select("cells.ru_id AS ru_id, count(*) AS count").
+		Group("cells.ru_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count error cells: %w", err)
+	}
+	return rows, nil
+}
+
+// CountUnacknowledgedAlarms - неквитированные аварии по РУ и важности
+func (r *MetricsRepository) CountUnacknowledgedAlarms() ([]RuCount, error) {
+	var rows []RuCount
+	err := r.db.Model(&models.Alarm{}).
+		Where("status = ?", models.AlarmStatusActive).
+		Select("ru_id, severity AS label, count(*) AS count").
+		Group("ru_id, severity").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unacknowledged alarms: %w", err)
+	}
+	return rows, nil
+}
+
+// CountOfflineDevices - включенные в опрос устройства без связи по РУ
+func (r *MetricsRepository) CountOfflineDevices() ([]RuCount, error) {
+	var rows []RuCount
+	err := r.db.Model(&models.Device{}).
+		Where("enabled = ? AND status = ?", true, models.DeviceStatusOffline).
+		Select("ru_id, count(*) AS count").
+		Group("ru_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count offline devices: %w", err)
+	}
+	return rows, nil
+}
+
+// GetOldestPendingApprovals - время запроса самого старого ожидающего одобрения каждого
+// вида; вид без ожидающих запросов в результат не попадает
+func (r *MetricsRepository) GetOldestPendingApprovals(now time.Time) (map[string]time.Time, error) {
+	oldest := map[string]time.Time{}
+
+	var confirmation models.StatusConfirmation
+	err := r.db.Select("requested_at").
+		Where("state = ? AND expires_at > ?", models.ConfirmationPending, now).
+		Order("requested_at ASC").First(&confirmation).Error
+	if err := collectOldest(oldest, models.ApprovalStatusConfirmation, confirmation.RequestedAt, err); err != nil {
+		return nil, err
+	}
+
+	var change models.CellInfoChange
+	err = r.db.Select("requested_at").
+		Where("state = ?", models.ChangePending).
+		Order("requested_at ASC").First(&change).Error
+	if err := collectOldest(oldest, models.ApprovalCellChange, change.RequestedAt, err); err != nil {
+		return nil, err
+	}
+
+	var outage models.PlannedOutage
+	err = r.db.Select("created_at").
+		Where("status = ?", models.OutageRequested).
+		Order("created_at ASC").First(&outage).Error
+	if err := collectOldest(oldest, models.ApprovalPlannedOutage, outage.CreatedAt, err); err != nil {
+		return nil, err
+	}
+	return oldest, nil
+}
+
+func collectOldest(oldest map[string]time.Time, kind string, requestedAt time.Time, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get oldest pending %s: %w", kind, err)
+	}
+	oldest[kind] = requestedAt
+	return nil
+}
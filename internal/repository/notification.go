@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) GetRulesByRuID(ruID string) ([]models.NotificationRule, error) {
+	var rules []models.NotificationRule
+	result := r.db.Where("ru_id = ?", ruID).Order("created_at ASC").Find(&rules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notification rules: %w", result.Error)
+	}
+	return rules, nil
+}
+
+func (r *NotificationRepository) GetRulesForEvent(ruID string, category models.EventCategory) ([]models.NotificationRule, error) {
+	var rules []models.NotificationRule
+	result := r.db.Where("ru_id = ? AND category = ?", ruID, category).Find(&rules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notification rules for event: %w", result.Error)
+	}
+	return rules, nil
+}
+
+func (r *NotificationRepository) CreateRule(rule *models.NotificationRule) error {
+	result := r.db.Create(rule)
+	if result.Error != nil {
+		return fmt.Errorf("failed to create notification rule: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) DeleteRule(ruID, ruleID string) (bool, error) {
+	result := r.db.Delete(&models.NotificationRule{}, "id = ? AND ru_id = ?", ruleID, ruID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete notification rule: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// CreateNotifications - сохраняет уведомления получателей и возвращает новые. Уведомление
+// с тем же источником у пользователя уже есть - повторная доставка события пропускается.
+func (r *NotificationRepository) CreateNotifications(notifications []models.Notification) ([]models.Notification, error) {
+	var created []models.Notification
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, notification := range notifications {
+			result := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "source_id"}},
+				DoNothing: true,
+			}).Create(&notification)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				created = append(created, notification)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifications: %w", err)
+	}
+	return created, nil
+}
+
+// GetNotifications - уведомления пользователя, новые сверху
+func (r *NotificationRepository) GetNotifications(userID string, filter models.NotificationFilter) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	query := r.db.Where("user_id = ?", userID)
+	if filter.UnreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Before != nil {
+		query = query.Where("created_at < ?", *filter.Before)
+	}
+	result := query.Order("created_at DESC").Limit(filter.Limit).Find(&notifications)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", result.Error)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID string) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", result.Error)
+	}
+	return count, nil
+}
+
+// MarkRead - отмечает уведомление прочитанным; время первого прочтения сохраняется.
+// false - у пользователя нет такого уведомления.
+func (r *NotificationRepository) MarkRead(userID, id string, at time.Time) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark notification read: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkAllRead - отмечает прочитанными все непрочитанные уведомления пользователя
+func (r *NotificationRepository) MarkAllRead(userID string, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NumberingRepository struct {
+	db *gorm.DB
+}
+
+func NewNumberingRepository(db *gorm.DB) *NumberingRepository {
+	return &NumberingRepository{db: db}
+}
+
+// Issue - выдает следующий номер серии организации за год: блокирует счетчик серии,
+// увеличивает его и записывает выданный номер в той же транзакции
+func (r *NumberingRepository) Issue(issued *models.IssuedDocumentNumber) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		sequence := models.DocumentNumberSequence{
+			OrganizationID: issued.OrganizationID,
+			DocumentType:   issued.DocumentType,
+			Year:           issued.Year,
+			UpdatedAt:      time.Now(),
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("organization_id = ? AND document_type = ? AND year = ?", issued.OrganizationID, issued.DocumentType, issued.Year).
+			First(&sequence).Error
+		if err != nil {
+			return err
+		}
+
+		issued.Seq = sequence.Last + 1
+		issued.Number = models.FormatDocumentNumber(issued.Year, issued.Seq)
+		err = tx.Model(&models.DocumentNumberSequence{}).
+			Where("organization_id = ? AND document_type = ? AND year = ?", issued.OrganizationID, issued.DocumentType, issued.Year).
+			Updates(map[string]interface{}{"last": issued.Seq, "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(issued).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to issue document number: %w", err)
+	}
+	return nil
+}
+
+// IsIssued - выдавался ли номер в серии организации
+func (r *NumberingRepository) IsIssued(organizationID, documentType, number string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.IssuedDocumentNumber{}).
+		Where("organization_id = ? AND document_type = ? AND number = ?", organizationID, documentType, number).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check document number: %w", err)
+	}
+	return count > 0, nil
+}
+
+// IsRecorded - встречается ли номер в поле записи журнала организации (с учетом исправлений)
+func (r *NumberingRepository) IsRecorded(organizationID, field, column, number string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.OperationRecord{}).
+		Where("organization_id = ?", organizationID).
+		Where(correctedColumnExpr(field, column)+" = ?", number).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check recorded document number: %w", err)
+	}
+	return count > 0, nil
+}
+
+// GetSequence - счетчик серии организации за год; серия без выданных номеров
+// возвращается с нулем
+func (r *NumberingRepository) GetSequence(organizationID, documentType string, year int) (*models.DocumentNumberSequence, error) {
+	sequence := models.DocumentNumberSequence{OrganizationID: organizationID, DocumentType: documentType, Year: year}
+	err := r.db.Where("organization_id = ? AND document_type = ? AND year = ?", organizationID, documentType, year).First(&sequence).Error
+	if err != nil && !IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get number sequence: %w", err)
+	}
+	return &sequence, nil
+}
+
+// GetIssued - номера серии организации, выданные за год, по порядку
+func (r *NumberingRepository) GetIssued(organizationID, documentType string, year int) ([]models.IssuedDocumentNumber, error) {
+	var issued []models.IssuedDocumentNumber
+	err := r.db.Where("organization_id = ? AND document_type = ? AND year = ?", organizationID, documentType, year).
+		Order("seq ASC").Find(&issued).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issued document numbers: %w", err)
+	}
+	return issued, nil
+}
+
+// GetRecordedNumbers - номера из списка, которые встречаются в поле записей журнала
+// организации
+func (r *NumberingRepository) GetRecordedNumbers(organizationID, field, column string, numbers []string) (map[string]bool, error) {
+	recorded := map[string]bool{}
+	if len(numbers) == 0 {
+		return recorded, nil
+	}
+	expr := correctedColumnExpr(field, column)
+	var values []string
+	err := r.db.Model(&models.OperationRecord{}).
+		Distinct().
+		Where("organization_id = ?", organizationID).
+		Where(expr+" IN ?", numbers).
+		Pluck(expr, &values).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recorded document numbers: %w", err)
+	}
+	for _, value := range values {
+		recorded[value] = true
+	}
+	return recorded, nil
+}
+
+// MigrateDocumentNumbering - переводит нумерацию, которая велась одной серией на всю
+// установку, на серии организаций. Выданные номера относятся к организации своего РУ,
+// счетчики пересчитываются по выданным номерам каждой организации, а прежний
+// уникальный индекс (вид документа, номер) заменяется индексом с организацией.
+// Выполняется один раз, при появлении колонки организации.
+func MigrateDocumentNumbering(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&models.IssuedDocumentNumber{}, "idx_issued_document_numbers") {
+			if err := tx.Migrator().DropIndex(&models.IssuedDocumentNumber{}, "idx_issued_document_numbers"); err != nil {
+				return fmt.Errorf("failed to drop document number index: %w", err)
+			}
+		}
+		err := tx.Model(&models.IssuedDocumentNumber{}).
+			Where("ru_id <> '' AND organization_id = ?", models.DefaultOrganizationID).
+			Update("organization_id", gorm.Expr("COALESCE((SELECT organization_id FROM ru_infos WHERE ru_infos.id = issued_document_numbers.ru_id), ?)", models.DefaultOrganizationID)).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill document number organizations: %w", err)
+		}
+
+		if err := rekeyNumberSequences(tx); err != nil {
+			return err
+		}
+		err = tx.Exec(`
+			INSERT INTO document_number_sequences (organization_id, document_type, year, last, updated_at)
+			SELECT organization_id, document_type, year, MAX(seq), CURRENT_TIMESTAMP
+			FROM issued_document_numbers
+			GROUP BY organization_id, document_type, year`).Error
+		if err != nil {
+			return fmt.Errorf("failed to rebuild number sequences: %w", err)
+		}
+		return nil
+	})
+}
+
+// rekeyNumberSequences - счетчики с первичным ключом, включающим организацию. Прежние
+// счетчики общие для всех организаций, поэтому они удаляются и строятся заново.
+// SQLite не меняет первичный ключ существующей таблицы, поэтому таблица пересоздается.
+func rekeyNumberSequences(tx *gorm.DB) error {
+	if IsPostgres(tx) {
+		err := tx.Exec(`
+			ALTER TABLE document_number_sequences
+				DROP CONSTRAINT IF EXISTS document_number_sequences_pkey,
+				ADD PRIMARY KEY (organization_id, document_type, year)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to rekey number sequences: %w", err)
+		}
+		if err := tx.Exec("DELETE FROM document_number_sequences").Error; err != nil {
+			return fmt.Errorf("failed to reset number sequences: %w", err)
+		}
+		return nil
+	}
+	if err := tx.Migrator().DropTable(&models.DocumentNumberSequence{}); err != nil {
+		return fmt.Errorf("failed to drop number sequences: %w", err)
+	}
+	if err := tx.Migrator().CreateTable(&models.DocumentNumberSequence{}); err != nil {
+		return fmt.Errorf("failed to create number sequences: %w", err)
+	}
+	return nil
+}
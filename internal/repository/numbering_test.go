@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestMigrateDocumentNumbering(t *testing.T) {
+	db := newTestDB(t, &models.RUInfo{})
+	// Схема до разделения нумерации по организациям: одна серия на установку
+	for _, sql := range []string{
+		`CREATE TABLE document_number_sequences (document_type text, year integer, last integer, updated_at datetime, PRIMARY KEY (document_type, year))`,
+		`CREATE TABLE issued_document_numbers (id integer PRIMARY KEY AUTOINCREMENT, document_type text, number text, year integer, seq integer, ru_id text, issued_by text, issued_at datetime)`,
+		`CREATE UNIQUE INDEX idx_issued_document_numbers ON issued_document_numbers (document_type, number)`,
+		`INSERT INTO document_number_sequences VALUES ('permit', 2025, 3, CURRENT_TIMESTAMP)`,
+		`INSERT INTO issued_document_numbers (document_type, number, year, seq, ru_id) VALUES
+			('permit', '2025-0001', 2025, 1, 'ru-a'), ('permit', '2025-0002', 2025, 2, 'ru-b'), ('permit', '2025-0003', 2025, 3, 'ru-a')`,
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatalf("old schema: %v", err)
+		}
+	}
+	for _, ru := range []models.RUInfo{{ID: "ru-a", OrganizationID: "org-a"}, {ID: "ru-b", OrganizationID: "org-b"}} {
+		if err := db.Create(&ru).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	if err := db.AutoMigrate(&models.DocumentNumberSequence{}, &models.IssuedDocumentNumber{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := MigrateDocumentNumbering(db); err != nil {
+		t.Fatalf("MigrateDocumentNumbering: %v", err)
+	}
+
+	repo := NewNumberingRepository(db)
+	// Счетчики пересчитаны по номерам каждой организации
+	for organizationID, want := range map[string]int{"org-a": 3, "org-b": 2} {
+		sequence, err := repo.GetSequence(organizationID, "permit", 2025)
+		if err != nil {
+			t.Fatalf("sequence: %v", err)
+		}
+		if sequence.Last != want {
+			t.Errorf("%s last = %d, want %d", organizationID, sequence.Last, want)
+		}
+	}
+
+	// Одинаковый номер у другой организации допустим, в своей серии номера не повторяются
+	issued := &models.IssuedDocumentNumber{OrganizationID: "org-b", DocumentType: "permit", Year: 2025, IssuedAt: time.Now()}
+	if err := repo.Issue(issued); err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if issued.Number != "2025-0003" {
+		t.Errorf("org-b number = %s, want 2025-0003", issued.Number)
+	}
+	if ok, _ := repo.IsIssued("org-b", "permit", "2025-0001"); ok {
+		t.Error("org-a number is reported as issued in org-b")
+	}
+}
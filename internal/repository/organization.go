@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{db: db}
+}
+
+// EnsureDefaultOrganization - организация по умолчанию, к которой относятся существующие данные
+func EnsureDefaultOrganization(db *gorm.DB) error {
+	now := time.Now()
+	org := models.Organization{ID: models.DefaultOrganizationID, Name: "Default", CreatedAt: now, UpdatedAt: now}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&org).Error; err != nil {
+		return fmt.Errorf("failed to create default organization: %w", err)
+	}
+	return nil
+}
+
+// BackfillAssetOrganizations - установленное оборудование, зарегистрированное до учета
+// владельца, относится к организации своего РУ
+func BackfillAssetOrganizations(db *gorm.DB) error {
+	err := db.Model(&models.Asset{}).
+		Where("ru_id IS NOT NULL AND organization_id = ?", models.DefaultOrganizationID).
+		Update("organization_id", gorm.Expr("COALESCE((SELECT organization_id FROM ru_infos WHERE ru_infos.id = assets.ru_id), ?)", models.DefaultOrganizationID)).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill asset organizations: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) Create(org *models.Organization) error {
+	if err := r.db.Create(org).Error; err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) GetAll() ([]models.Organization, error) {
+	var orgs []models.Organization
+	if err := r.db.Order("name ASC").Find(&orgs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get organizations: %w", err)
+	}
+	return orgs, nil
+}
+
+func (r *OrganizationRepository) GetByID(id string) (*models.Organization, error) {
+	var org models.Organization
+	if err := r.db.Where("id = ?", id).First(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// AssignSubstation - переносит подстанцию, ее РУ, их журнал операций и установленное
+// оборудование в организацию одной транзакцией, чтобы данные подстанции не оказались
+// в двух организациях
+func (r *OrganizationRepository) AssignSubstation(organizationID, substationID string) (int64, error) {
+	var moved int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Substation{}).Where("id = ?", substationID).
+			Update("organization_id", organizationID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.RUInfo{}).Where("substation_id = ?", substationID).
+			Update("organization_id", organizationID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		if err := tx.Model(&models.Asset{}).
+			Where("ru_id IN (?)", tx.Model(&models.RUInfo{}).Select("id").Where("substation_id = ?", substationID)).
+			Update("organization_id", organizationID).Error; err != nil {
+			return err
+		}
+
+		// Организация не входит в хеш записи журнала, перенос цепочку не нарушает
+		return allowJournalUpdate(tx).Model(&models.OperationRecord{}).
+			Where("ru_id IN (?)", tx.Model(&models.RUInfo{}).Select("id").Where("substation_id = ?", substationID)).
+			Update("organization_id", organizationID).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign substation: %w", err)
+	}
+	return moved, nil
+}
+
+// stampRecordOrganization - запись журнала наследует организацию своего РУ
+func stampRecordOrganization(tx *gorm.DB, record *models.OperationRecord) error {
+	if record.OrganizationID != "" {
+		return nil
+	}
+	var orgs []string
+	if err := tx.Model(&models.RUInfo{}).Where("id = ?", record.RuID).Pluck("organization_id", &orgs).Error; err != nil {
+		return err
+	}
+	record.OrganizationID = models.DefaultOrganizationID
+	if len(orgs) > 0 && orgs[0] != "" {
+		record.OrganizationID = orgs[0]
+	}
+	return nil
+}
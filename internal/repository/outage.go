@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutageRepository struct {
+	db *gorm.DB
+}
+
+func NewOutageRepository(db *gorm.DB) *OutageRepository {
+	return &OutageRepository{db: db}
+}
+
+// GetOutages - плановые отключения организации (пустая строка - всех организаций),
+// пересекающиеся с периодом фильтра
+func (r *OutageRepository) GetOutages(organizationID string, filter models.OutageFilter) ([]models.PlannedOutage, error) {
+	var outages []models.PlannedOutage
+	query := r.db.Preload("Cells")
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if filter.RuID != "" {
+		query = query.Where("ru_id = ?", filter.RuID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("ends_at > ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("starts_at < ?", *filter.To)
+	}
+	if err := query.Order("starts_at ASC").Find(&outages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get planned outages: %w", err)
+	}
+	return outages, nil
+}
+
+func (r *OutageRepository) GetByID(id string) (*models.PlannedOutage, error) {
+	var outage models.PlannedOutage
+	if err := r.db.Preload("Cells").Where("id = ?", id).First(&outage).Error; err != nil {
+		return nil, fmt.Errorf("failed to get planned outage: %w", err)
+	}
+	return &outage, nil
+}
+
+// Create - сохраняет заявку с ячейками и событием запроса согласования
+func (r *OutageRepository) Create(outage *models.PlannedOutage, events ...models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(outage).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create planned outage: %w", err)
+	}
+	return nil
+}
+
+// Review - сохраняет решение по заявке вместе с уведомлениями потребителей, записью
+// календаря и событиями в одной транзакции
+func (r *OutageRepository) Review(outage *models.PlannedOutage, notifications []models.ConsumerNotification, entry *models.CalendarEntry, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Cells").Save(outage).Error; err != nil {
+			return err
+		}
+		if len(notifications) > 0 {
+			if err := tx.Create(&notifications).Error; err != nil {
+				return err
+			}
+		}
+		if entry != nil {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "kind"}, {Name: "ref_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"title":       entry.Title,
+					"description": entry.Description,
+					"location":    entry.Location,
+					"starts_at":   entry.StartsAt,
+					"ends_at":     entry.EndsAt,
+					"status":      entry.Status,
+					"sequence":    gorm.Expr("calendar_entries.sequence + 1"),
+					"updated_at":  entry.UpdatedAt,
+				}),
+			}).Create(entry).Error
+			if err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save planned outage: %w", err)
+	}
+	return nil
+}
+
+// GetPermitConflicts - наряды-допуски на ячейки РУ, действующие в интервале [from, to).
+// Наряд без даты окончания считается действующим до закрытия.
+func (r *OutageRepository) GetPermitConflicts(ruID string, cellNumbers []string, from, to time.Time) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	if len(cellNumbers) == 0 {
+		return records, nil
+	}
+	result := r.db.Where("ru_id = ? AND cell_number IN ? AND work_order_number IS NOT NULL", ruID, cellNumbers).
+		Where("start_date_at IS NOT NULL OR end_date_at IS NOT NULL").
+		Where("(start_date_at IS NULL OR start_date_at < ?) AND (end_date_at IS NULL OR end_date_at > ?)", to, from).
+		Order("start_date_at ASC").
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get conflicting permits: %w", result.Error)
+	}
+	return records, nil
+}
+
+func (r *OutageRepository) GetNotifications(outageID string) ([]models.ConsumerNotification, error) {
+	var notifications []models.ConsumerNotification
+	if err := r.db.Where("outage_id = ?", outageID).Order("created_at ASC, company ASC").Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get consumer notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// GetPendingNotifications - уведомления потребителей, ожидающие отправки
+func (r *OutageRepository) GetPendingNotifications(limit int) ([]models.ConsumerNotification, error) {
+	var notifications []models.ConsumerNotification
+	result := r.db.Where("status = ?", models.ConsumerNotificationPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&notifications)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get pending consumer notifications: %w", result.Error)
+	}
+	return notifications, nil
+}
+
+func (r *OutageRepository) UpdateNotification(notification *models.ConsumerNotification) error {
+	if err := r.db.Save(notification).Error; err != nil {
+		return fmt.Errorf("failed to update consumer notification: %w", err)
+	}
+	return nil
+}
+
+// GetCalendarEntries - записи календаря организации (пустая строка - всех), пересекающиеся с периодом
+func (r *OutageRepository) GetCalendarEntries(organizationID string, from, to time.Time) ([]models.CalendarEntry, error) {
+	var entries []models.CalendarEntry
+	query := r.db.Where("ends_at > ? AND starts_at < ?", from, to)
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if err := query.Order("starts_at ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to get calendar entries: %w", err)
+	}
+	return entries, nil
+}
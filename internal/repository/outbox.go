@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// appendOutbox - записывает события в outbox в рамках переданной транзакции
+func appendOutbox(tx *gorm.DB, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := tx.Create(&events).Error; err != nil {
+		return fmt.Errorf("failed to append outbox events: %w", err)
+	}
+	return nil
+}
+
+// ProcessPending - выбирает готовые к доставке события и обрабатывает их в одной транзакции.
+// Строки блокируются с SKIP LOCKED, поэтому несколько экземпляров не доставят событие дважды.
+// Неудачная попытка откладывается на backoff*attempts, после maxAttempts событие помечается failed.
+func (r *OutboxRepository) ProcessPending(limit, maxAttempts int, backoff time.Duration, handle func(event *models.OutboxEvent) error) (int, error) {
+	processed := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var events []models.OutboxEvent
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", models.OutboxStatusPending, time.Now()).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&events)
+		if result.Error != nil {
+			return fmt.Errorf("failed to fetch outbox events: %w", result.Error)
+		}
+
+		for i := range events {
+			event := &events[i]
+			event.Attempts++
+
+			if err := handle(event); err != nil {
+				msg := err.Error()
+				event.LastError = &msg
+				if event.Attempts >= maxAttempts {
+					event.Status = models.OutboxStatusFailed
+				} else {
+					event.AvailableAt = time.Now().Add(backoff * time.Duration(event.Attempts))
+				}
+			} else {
+				now := time.Now()
+				event.Status = models.OutboxStatusProcessed
+				event.ProcessedAt = &now
+				event.LastError = nil
+			}
+
+			if err := tx.Save(event).Error; err != nil {
+				return fmt.Errorf("failed to update outbox event: %w", err)
+			}
+			processed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return processed, nil
+}
+
+// GetEvents - события outbox в обратном хронологическом порядке, с фильтром по статусу
+func (r *OutboxRepository) GetEvents(status string, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	query := r.db.Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// RequeueEvent - возвращает событие в очередь доставки
+func (r *OutboxRepository) RequeueEvent(id string) (bool, error) {
+	result := r.db.Model(&models.OutboxEvent{}).
+		Where("id = ? AND status = ?", id, models.OutboxStatusFailed).
+		Updates(map[string]interface{}{
+			"status":       models.OutboxStatusPending,
+			"attempts":     0,
+			"available_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to requeue outbox event: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetUnpublished - события, еще не опубликованные во внешний брокер, в порядке создания
+func (r *OutboxRepository) GetUnpublished(limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Where("broker_published_at IS NULL").
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unpublished outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// CountUnpublished - сколько событий ждет публикации во внешний брокер
+func (r *OutboxRepository) CountUnpublished() (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.OutboxEvent{}).Where("broker_published_at IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unpublished outbox events: %w", err)
+	}
+	return count, nil
+}
+
+// MarkPublished - отмечает событие опубликованным и учитывает публикацию в статистике
+func (r *OutboxRepository) MarkPublished(event *models.OutboxEvent, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.OutboxEvent{}).
+			Where("id = ?", event.ID).
+			Update("broker_published_at", at).Error
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox event published: %w", err)
+		}
+		return addBrokerStats(tx, models.BrokerDeliveryStats{Type: event.Type, Published: 1, LastPublishedAt: &at})
+	})
+}
+
+// RecordPublishFailure - учитывает неудачную публикацию события в статистике
+func (r *OutboxRepository) RecordPublishFailure(eventType models.DomainEventType, message string, at time.Time) error {
+	return addBrokerStats(r.db, models.BrokerDeliveryStats{Type: eventType, Failed: 1, LastError: &message, LastErrorAt: &at})
+}
+
+// addBrokerStats - прибавляет приращения к статистике типа события; время и ошибка
+// заменяются, только если заданы
+func addBrokerStats(db *gorm.DB, delta models.BrokerDeliveryStats) error {
+	result := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"published":         gorm.Expr("broker_delivery_stats.published + excluded.published"),
+			"failed":            gorm.Expr("broker_delivery_stats.failed + excluded.failed"),
+			"last_published_at": gorm.Expr("COALESCE(excluded.last_published_at, broker_delivery_stats.last_published_at)"),
+			"last_error":        gorm.Expr("COALESCE(excluded.last_error, broker_delivery_stats.last_error)"),
+			"last_error_at":     gorm.Expr("COALESCE(excluded.last_error_at, broker_delivery_stats.last_error_at)"),
+		}),
+	}).Create(&delta)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save broker delivery stats: %w", result.Error)
+	}
+	return nil
+}
+
+// GetBrokerStats - статистика публикации во внешний брокер по типам событий
+func (r *OutboxRepository) GetBrokerStats() ([]models.BrokerDeliveryStats, error) {
+	stats := []models.BrokerDeliveryStats{}
+	if err := r.db.Order("type").Find(&stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get broker delivery stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type PhotoRepository struct {
+	db *gorm.DB
+}
+
+func NewPhotoRepository(db *gorm.DB) *PhotoRepository {
+	return &PhotoRepository{db: db}
+}
+
+// GetByOwners - метаданные фотографий (без содержимого и миниатюр) для набора записей
+func (r *PhotoRepository) GetByOwners(ownerType models.PhotoOwnerType, ownerIDs []string) ([]models.Photo, error) {
+	var photos []models.Photo
+	if len(ownerIDs) == 0 {
+		return photos, nil
+	}
+	err := r.db.Omit("data", "thumbnail").
+		Where("owner_type = ? AND owner_id IN ?", ownerType, ownerIDs).
+		Order("created_at ASC").Find(&photos).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
+	}
+	return photos, nil
+}
+
+func (r *PhotoRepository) GetByID(ownerType models.PhotoOwnerType, ownerID, photoID string) (*models.Photo, error) {
+	var photo models.Photo
+	err := r.db.Where("id = ? AND owner_type = ? AND owner_id = ?", photoID, ownerType, ownerID).First(&photo).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photo: %w", err)
+	}
+	return &photo, nil
+}
+
+func (r *PhotoRepository) Create(photo *models.Photo) error {
+	if err := r.db.Create(photo).Error; err != nil {
+		return fmt.Errorf("failed to create photo: %w", err)
+	}
+	return nil
+}
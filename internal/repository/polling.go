@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PollingRepository struct {
+	db *gorm.DB
+}
+
+func NewPollingRepository(db *gorm.DB) *PollingRepository {
+	return &PollingRepository{db: db}
+}
+
+func (r *PollingRepository) GetPauses() ([]models.PollingPause, error) {
+	var pauses []models.PollingPause
+	if err := r.db.Order("paused_at DESC").Find(&pauses).Error; err != nil {
+		return nil, fmt.Errorf("failed to get polling pauses: %w", err)
+	}
+	return pauses, nil
+}
+
+// UpsertPause - создает или обновляет приостановку для области (адаптер, РУ)
+func (r *PollingRepository) UpsertPause(pause *models.PollingPause) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "adapter"}, {Name: "ru_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "paused_by", "paused_at", "until", "updated_at"}),
+	}).Create(pause)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save polling pause: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *PollingRepository) DeletePause(adapter, ruID string) (bool, error) {
+	result := r.db.Where("adapter = ? AND ru_id = ?", adapter, ruID).Delete(&models.PollingPause{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete polling pause: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
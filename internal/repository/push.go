@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PushRepository struct {
+	db *gorm.DB
+}
+
+func NewPushRepository(db *gorm.DB) *PushRepository {
+	return &PushRepository{db: db}
+}
+
+// SaveSubscription - сохраняет подписку; для известного адреса push-сервиса
+// обновляет ключи, владельца и сбрасывает счетчик ошибок
+func (r *PushRepository) SaveSubscription(subscription *models.PushSubscription) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"user_id":       subscription.UserID,
+			"p256dh":        subscription.P256dh,
+			"auth":          subscription.Auth,
+			"user_agent":    subscription.UserAgent,
+			"failure_count": 0,
+			"updated_at":    subscription.UpdatedAt,
+		}),
+	}).Create(subscription)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save push subscription: %w", result.Error)
+	}
+	// При обновлении существующей подписки в базе остается ее прежний ID
+	if err := r.db.Where("endpoint = ?", subscription.Endpoint).First(subscription).Error; err != nil {
+		return fmt.Errorf("failed to get push subscription: %w", err)
+	}
+	return nil
+}
+
+// GetSubscriptions - подписки пользователей
+func (r *PushRepository) GetSubscriptions(userIDs []string) ([]models.PushSubscription, error) {
+	subscriptions := []models.PushSubscription{}
+	result := r.db.Where("user_id IN ?", userIDs).Order("created_at ASC").Find(&subscriptions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get push subscriptions: %w", result.Error)
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription - false, если у пользователя нет такой подписки
+func (r *PushRepository) DeleteSubscription(userID, id string) (bool, error) {
+	result := r.db.Delete(&models.PushSubscription{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete push subscription: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired - удаляет подписку, отозванную браузером
+func (r *PushRepository) DeleteExpired(id string) error {
+	if err := r.db.Delete(&models.PushSubscription{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	return nil
+}
+
+// MarkDelivered - успешная отправка сбрасывает счетчик ошибок
+func (r *PushRepository) MarkDelivered(id string, at time.Time) error {
+	err := r.db.Model(&models.PushSubscription{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"failure_count": 0, "last_success_at": at}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update push subscription: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed - увеличивает счетчик ошибок подряд и возвращает его значение
+func (r *PushRepository) MarkFailed(id string) (int, error) {
+	err := r.db.Model(&models.PushSubscription{}).Where("id = ?", id).
+		Update("failure_count", gorm.Expr("failure_count + 1")).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to update push subscription: %w", err)
+	}
+	var subscription models.PushSubscription
+	if err := r.db.Select("failure_count").Where("id = ?", id).First(&subscription).Error; err != nil {
+		return 0, fmt.Errorf("failed to get push subscription: %w", err)
+	}
+	return subscription.FailureCount, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// readOnlyModeID - единственная строка таблицы read_only_mode
+const readOnlyModeID = 1
+
+type ReadOnlyRepository struct {
+	db *gorm.DB
+}
+
+func NewReadOnlyRepository(db *gorm.DB) *ReadOnlyRepository {
+	return &ReadOnlyRepository{db: db}
+}
+
+// Get - текущее состояние режима; если строки еще нет, режим выключен
+func (r *ReadOnlyRepository) Get() (*models.ReadOnlyMode, error) {
+	var mode models.ReadOnlyMode
+	err := r.db.Where("id = ?", readOnlyModeID).First(&mode).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.ReadOnlyMode{ID: readOnlyModeID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read-only mode: %w", err)
+	}
+	return &mode, nil
+}
+
+func (r *ReadOnlyRepository) Save(mode *models.ReadOnlyMode) error {
+	mode.ID = readOnlyModeID
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "reason", "set_by", "set_at", "updated_at"}),
+	}).Create(mode)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save read-only mode: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+type CellRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewCellRevisionRepository(db *gorm.DB) *CellRevisionRepository {
+	return &CellRevisionRepository{db: db}
+}
+
+func (r *CellRevisionRepository) GetByCell(cellID int, ruID string) ([]models.CellRevision, error) {
+	var revisions []models.CellRevision
+	err := r.db.Where("cell_id = ? AND ru_id = ?", cellID, ruID).
+		Order("revision ASC").Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *CellRevisionRepository) GetByRevision(cellID int, ruID string, revision int) (*models.CellRevision, error) {
+	var rev models.CellRevision
+	err := r.db.Where("cell_id = ? AND ru_id = ? AND revision = ?", cellID, ruID, revision).First(&rev).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// SaveCell - сохраняет ячейку и в той же транзакции записывает ревизию
+// конфигурации. previous - состояние ячейки до изменения.
+func (r *CellRevisionRepository) SaveCell(cell *models.Cell, previous models.CellRevision, meta models.CellRevision) error {
+	syncCellDates(cell)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(cell).Error; err != nil {
+			return err
+		}
+		return appendCellRevision(tx, cell, previous, meta)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save cell revision: %w", err)
+	}
+	return nil
+}
+
+// appendCellRevision - пишет ревизию, если конфигурация изменилась. Для ячейки
+// без истории сначала записывается исходное состояние (baseline).
+func appendCellRevision(tx *gorm.DB, cell *models.Cell, previous models.CellRevision, meta models.CellRevision) error {
+	current := models.NewCellRevision(cell)
+	if current.SameConfig(&previous) {
+		return nil
+	}
+
+	var last models.CellRevision
+	err := tx.Where("cell_id = ?", cell.ID).Order("revision DESC").Limit(1).Find(&last).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	next := last.Revision + 1
+	if last.ID == "" {
+		previous.ID = utils.NewID(models.IDPrefixCellRevision)
+		previous.Revision = 1
+		previous.Reason = models.RevisionBaseline
+		previous.CreatedAt = now
+		if err := tx.Create(&previous).Error; err != nil {
+			return err
+		}
+		next = 2
+	}
+
+	current.ID = utils.NewID(models.IDPrefixCellRevision)
+	current.Revision = next
+	current.Reason = meta.Reason
+	current.ChangedBy = meta.ChangedBy
+	current.RestoredFrom = meta.RestoredFrom
+	current.CreatedAt = now
+	return tx.Create(&current).Error
+}
@@ -1,21 +1,41 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
 type RuRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	replica *gorm.DB
 }
 
 func NewRuRepository(db *gorm.DB) *RuRepository {
 	return &RuRepository{db: db}
 }
 
+// UseReplica - реплика только для чтения для запросов через ReadOnly; nil - без реплики
+func (r *RuRepository) UseReplica(replica *gorm.DB) {
+	r.replica = replica
+}
+
+// ReadOnly - репозиторий, читающий из реплики, если она подключена. Данные реплики
+// могут отставать, поэтому он годится только для чтения без последующей записи.
+func (r *RuRepository) ReadOnly() *RuRepository {
+	if r.replica == nil {
+		return r
+	}
+	return &RuRepository{db: r.replica}
+}
+
 func (r *RuRepository) GetRuByID(ruID string) (*models.RUInfo, error) {
 	var ruInfo models.RUInfo
 	result := r.db.Where("id = ?", ruID).First(&ruInfo)
@@ -24,10 +44,19 @@ func (r *RuRepository) GetRuByID(ruID string) (*models.RUInfo, error) {
 	}
 	return &ruInfo, nil
 }
-func (r *RuRepository) UpdateRu(ruInfo *models.RUInfo) error {
-	result := r.db.Save(ruInfo)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update RU: %w", result.Error)
+
+// UpdateRu - сохраняет РУ; доменные события пишутся в outbox в той же транзакции
+func (r *RuRepository) UpdateRu(ruInfo *models.RUInfo, events ...models.OutboxEvent) error {
+	syncRuDates(ruInfo)
+	syncRuCapacity(ruInfo)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(ruInfo).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update RU: %w", err)
 	}
 	return nil
 }
@@ -49,17 +78,54 @@ func (r *RuRepository) GetCellByID(cellID int, ruID string) (*models.Cell, error
 	return &cell, nil
 }
 
-func (r *RuRepository) UpdateCell(cell *models.Cell) error {
-	result := r.db.Save(cell)
-	if result.Error != nil {
-		return fmt.Errorf("failed to update cell: %w", result.Error)
+// UpdateCell - сохраняет ячейку; доменные события пишутся в outbox в той же транзакции
+func (r *RuRepository) UpdateCell(cell *models.Cell, events ...models.OutboxEvent) error {
+	syncCellDates(cell)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(cell).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update cell: %w", err)
+	}
+	return nil
+}
+
+// IncrementOperationCount - учитывает коммутационную операцию выключателя ячейки
+func (r *RuRepository) IncrementOperationCount(cellID int) error {
+	err := r.db.Model(&models.Cell{}).Where("id = ?", cellID).
+		UpdateColumn("operation_count", gorm.Expr("operation_count + 1")).Error
+	if err != nil {
+		return fmt.Errorf("failed to increment operation count: %w", err)
 	}
 	return nil
 }
 
-func (r *RuRepository) GetHistoryByRuID(ruID string, limit int) ([]models.OperationRecord, error) {
+// GetCellsByOperationWear - ячейки с заданным ресурсом выключателя, выработавшие не
+// меньше percent процентов ресурса, по убыванию выработки; только РУ организации
+// (пустая - всех организаций)
+func (r *RuRepository) GetCellsByOperationWear(percent int, organizationID string) ([]models.Cell, error) {
+	var cells []models.Cell
+	query := r.db.Where("operation_limit > 0 AND operation_count * 100 >= operation_limit * ?", percent)
+	err := scopeOrganizationRUs(query, "ru_id", organizationID).
+		Order("operation_count * 1.0 / operation_limit DESC, id ASC").Find(&cells).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worn cells: %w", err)
+	}
+	return cells, nil
+}
+
+func (r *RuRepository) GetHistoryByRuID(ruID string, filter models.HistoryFilter, limit int) ([]models.OperationRecord, error) {
 	var records []models.OperationRecord
 	query := r.db.Where("ru_id = ?", ruID).Order("created_at DESC")
+	if filter.Severity != "" {
+		query = query.Where(recordSeverityExpr+" = ?", filter.Severity)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -72,19 +138,577 @@ func (r *RuRepository) GetHistoryByRuID(ruID string, limit int) ([]models.Operat
 	return records, nil
 }
 
-func (r *RuRepository) AddHistoryRecord(record *models.OperationRecord) error {
-	result := r.db.Create(record)
-	if result.Error != nil {
-		return fmt.Errorf("failed to add history record: %w", result.Error)
+// GetHistoryForPeriod - записи журнала РУ со временем операции в [from, to), от старых к новым
+func (r *RuRepository) GetHistoryForPeriod(ruID string, severity models.RecordSeverity, from, to time.Time) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	query := r.db.Where("ru_id = ?", ruID).
+		Where(operationTimeExpr+" >= ? AND "+operationTimeExpr+" < ?", from, to).
+		Order(operationTimeExpr + ", created_at")
+	if severity != "" {
+		query = query.Where(recordSeverityExpr+" = ?", severity)
+	}
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get history for period: %w", err)
+	}
+	return records, nil
+}
+
+// GetClockSkew - расхождение часов по устройствам для записей, созданных начиная с since
+func (r *RuRepository) GetClockSkew(since time.Time) ([]models.DeviceClockSkew, error) {
+	var rows []struct {
+		Device    string
+		Records   int
+		AvgSkewMs float64
+		MinSkewMs int64
+		MaxSkewMs int64
+	}
+	err := r.db.Model(&models.OperationRecord{}).
+		Select("device, COUNT(*) AS records, AVG(clock_skew_ms) AS avg_skew_ms, MIN(clock_skew_ms) AS min_skew_ms, MAX(clock_skew_ms) AS max_skew_ms").
+		Where("clock_skew_ms IS NOT NULL AND created_at >= ?", since).
+		Group("device").
+		Order("device").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clock skew: %w", err)
+	}
+	devices := make([]models.DeviceClockSkew, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, models.DeviceClockSkew{
+			Device:    row.Device,
+			Records:   row.Records,
+			AvgSkewMs: int64(row.AvgSkewMs),
+			MinSkewMs: row.MinSkewMs,
+			MaxSkewMs: row.MaxSkewMs,
+		})
+	}
+	return devices, nil
+}
+
+// AddHistoryRecord - добавляет запись журнала; доменные события пишутся в outbox в той же транзакции
+// GetHistoryByCell - последние операции по ячейке РУ
+func (r *RuRepository) GetHistoryByCell(ruID, cellNumber string, limit int) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	query := r.db.Where("ru_id = ? AND cell_number = ?", ruID, cellNumber).Order("created_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get history by cell: %w", err)
+	}
+	return records, nil
+}
+
+func (r *RuRepository) AddHistoryRecord(record *models.OperationRecord, events ...models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := createHistoryRecord(tx, record); err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add history record: %w", err)
 	}
 	return nil
 }
 
+// createHistoryRecord - вставляет запись журнала операций в конец цепочки хешей.
+// Все записи журнала создаются только через эту функцию.
+func createHistoryRecord(tx *gorm.DB, record *models.OperationRecord) error {
+	syncRecordDates(record)
+	if record.Severity == nil {
+		severity := models.RecordSeverityInfo
+		record.Severity = &severity
+	}
+	if err := stampRecordOrganization(tx, record); err != nil {
+		return err
+	}
+	record.CreatedAt = chainTime(record.CreatedAt)
+	record.UpdatedAt = record.CreatedAt
+	if err := appendToChain(tx, models.ChainOperationRecords, record); err != nil {
+		return err
+	}
+	return tx.Create(record).Error
+}
+
+// AddCorrection - добавляет исправление записи журнала в конец цепочки исправлений
+func (r *RuRepository) AddCorrection(correction *models.OperationCorrection) error {
+	correction.CreatedAt = chainTime(correction.CreatedAt)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := appendToChain(tx, models.ChainCorrections, correction); err != nil {
+			return err
+		}
+		return tx.Create(correction).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add correction: %w", err)
+	}
+	return nil
+}
+
+// GetCorrectionsByRecords - исправления записей журнала в порядке создания
+func (r *RuRepository) GetCorrectionsByRecords(recordIDs []string) ([]models.OperationCorrection, error) {
+	var corrections []models.OperationCorrection
+	if len(recordIDs) == 0 {
+		return corrections, nil
+	}
+	err := r.db.Preload("Fields", orderCorrectedFields).
+		Where("record_id IN ?", recordIDs).Order("created_at ASC, id ASC").Find(&corrections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corrections: %w", err)
+	}
+	return corrections, nil
+}
+
+// orderCorrectedFields - поля исправления в порядке сохранения, в котором они хешируются
+func orderCorrectedFields(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
+// correctedColumnExpr - SQL-значение поля записи журнала с учетом последнего исправления
+// (в том числе исправления, очистившего поле)
+func correctedColumnExpr(field, column string) string {
+	latest := fmt.Sprintf(`(SELECT f.to_value FROM operation_correction_fields f
+	JOIN operation_corrections c ON c.id = f.correction_id
+	WHERE c.record_id = operation_records.id AND f.field = '%s'
+	ORDER BY c.created_at DESC, f.id DESC LIMIT 1)`, field)
+	return fmt.Sprintf("CASE WHEN EXISTS %s THEN %s ELSE operation_records.%s END", latest, latest, column)
+}
+
+// recordSeverityExpr - важность записи журнала с учетом исправлений. Записи без
+// важности (созданные до перечня и не попавшие под миграцию) - информационные.
+var recordSeverityExpr = "COALESCE(" + correctedColumnExpr("severity", "severity") + ", 'info')"
+
+// BackfillRecordUsers - сопоставляет записи журнала, внесенные до появления user_id,
+// с пользователями по тексту оператора: адресу почты или имени, если имя однозначно.
+// Пользователь не входит в хеш записи, поэтому цепочка не нарушается.
+func BackfillRecordUsers(db *gorm.DB) error {
+	var users []models.User
+	if err := db.Select("id", "name", "email").Find(&users).Error; err != nil {
+		return fmt.Errorf("failed to load users for history backfill: %w", err)
+	}
+	byOperator := make(map[string]string, len(users)*2)
+	ambiguous := map[string]bool{}
+	for _, user := range users {
+		byOperator[user.Email] = user.ID
+		if name := strings.TrimSpace(user.Name); name != "" {
+			if _, ok := byOperator[name]; ok {
+				ambiguous[name] = true
+			}
+			byOperator[name] = user.ID
+		}
+	}
+
+	var updated int64
+	for operator, userID := range byOperator {
+		if ambiguous[operator] {
+			continue
+		}
+		result := allowJournalUpdate(db).Model(&models.OperationRecord{}).
+			Where("user_id IS NULL AND operator = ?", operator).
+			UpdateColumn("user_id", userID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill history users: %w", result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	if updated > 0 {
+		log.Printf("✅ Linked %d history records to users", updated)
+	}
+	return nil
+}
+
+// BackfillRecordResponsibleUsers - ответственный пользователь нарядов, внесенных до
+// появления responsible_user_id, по имени ответственного. Имя сопоставляется только с
+// единственным пользователем организации записи; однофамильцы не сопоставляются.
+func BackfillRecordResponsibleUsers(db *gorm.DB) error {
+	var users []models.User
+	if err := db.Select("id", "name", "organization_id").Find(&users).Error; err != nil {
+		return fmt.Errorf("failed to load users for responsible backfill: %w", err)
+	}
+	type key struct{ organizationID, name string }
+	byName := make(map[key]string, len(users))
+	ambiguous := map[key]bool{}
+	for _, user := range users {
+		name := strings.TrimSpace(user.Name)
+		if name == "" {
+			continue
+		}
+		k := key{organizationID: user.OrganizationID, name: name}
+		if _, ok := byName[k]; ok {
+			ambiguous[k] = true
+		}
+		byName[k] = user.ID
+	}
+
+	var updated int64
+	for k, userID := range byName {
+		if ambiguous[k] {
+			continue
+		}
+		result := allowJournalUpdate(db).Model(&models.OperationRecord{}).
+			Where("responsible_user_id IS NULL AND work_order_number IS NOT NULL AND organization_id = ? AND responsible_person = ?", k.organizationID, k.name).
+			UpdateColumn("responsible_user_id", userID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill responsible users: %w", result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	if updated > 0 {
+		log.Printf("✅ Linked %d work permits to responsible users", updated)
+	}
+	return nil
+}
+
+// BackfillRecordSeverity - переводит свободный текст важности старых записей журнала в
+// перечень. Записи, уже включенные в цепочку хешей, не трогаются: правка содержимого
+// нарушила бы цепочку, а при отборе пустая важность и так считается информационной.
+func BackfillRecordSeverity(db *gorm.DB) error {
+	valid := []models.RecordSeverity{models.RecordSeverityInfo, models.RecordSeverityWarning, models.RecordSeverityEmergency}
+	var legacy []*string
+	err := db.Model(&models.OperationRecord{}).
+		Where("chain_seq IS NULL AND (severity IS NULL OR severity NOT IN ?)", valid).
+		Distinct().Pluck("severity", &legacy).Error
+	if err != nil {
+		return fmt.Errorf("failed to load legacy severities: %w", err)
+	}
+
+	var updated int64
+	for _, value := range legacy {
+		query := allowJournalUpdate(db).Model(&models.OperationRecord{}).Where("chain_seq IS NULL")
+		severity := models.RecordSeverityInfo
+		if value == nil {
+			query = query.Where("severity IS NULL")
+		} else {
+			query = query.Where("severity = ?", *value)
+			severity = models.ParseLegacyRecordSeverity(*value)
+		}
+		result := query.UpdateColumn("severity", severity)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill severity: %w", result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	if updated > 0 {
+		log.Printf("✅ Backfilled severity for %d history records", updated)
+	}
+	return nil
+}
+
+// BackfillRuStatus - переводит текстовые статусы РУ, сохраненные до перехода на коды.
+// Статус, отличный от штатного, был установлен вручную - он сохраняется как ручная
+// установка, причиной становится прежний текст.
+func BackfillRuStatus(db *gorm.DB) error {
+	var legacy []string
+	err := db.Model(&models.RUInfo{}).
+		Where("status IS NULL OR status NOT IN ?", models.RuStatuses).
+		Distinct().Pluck("COALESCE(status, '')", &legacy).Error
+	if err != nil {
+		return fmt.Errorf("failed to load legacy RU statuses: %w", err)
+	}
+
+	var updated int64
+	for _, value := range legacy {
+		status := models.ParseLegacyRuStatus(value)
+		columns := map[string]interface{}{"status": status}
+		if status != models.RuStatusNormal {
+			reason := strings.TrimSpace(value)
+			columns["status_override"] = true
+			columns["status_reason"] = reason
+		}
+		result := db.Model(&models.RUInfo{}).
+			Where("COALESCE(status, '') = ?", value).
+			UpdateColumns(columns)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill RU status: %w", result.Error)
+		}
+		updated += result.RowsAffected
+	}
+	if updated > 0 {
+		log.Printf("✅ Backfilled status for %d RUs", updated)
+	}
+	return nil
+}
+
+// GetRUsByOrganization - действующие (не архивированные) РУ организации
+func (r *RuRepository) GetRUsByOrganization(organizationID string) ([]models.RUInfo, error) {
+	var rus []models.RUInfo
+	result := r.db.Where("organization_id = ? AND archived_at IS NULL", organizationID).Order("created_at DESC").Find(&rus)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get organization RUs: %w", result.Error)
+	}
+	return rus, nil
+}
+
+// GetRuOrganization - организация РУ без загрузки всей записи
+func (r *RuRepository) GetRuOrganization(ruID string) (string, error) {
+	var ru models.RUInfo
+	if err := r.db.Select("id", "organization_id").Where("id = ?", ruID).First(&ru).Error; err != nil {
+		return "", err
+	}
+	return ru.OrganizationID, nil
+}
+
+// GetRuOrganizations - организации РУ по их идентификаторам; несуществующих РУ в ответе нет
+func (r *RuRepository) GetRuOrganizations(ruIDs []string) (map[string]string, error) {
+	organizations := make(map[string]string, len(ruIDs))
+	if len(ruIDs) == 0 {
+		return organizations, nil
+	}
+	var rus []models.RUInfo
+	if err := r.db.Select("id", "organization_id").Where("id IN ?", ruIDs).Find(&rus).Error; err != nil {
+		return nil, fmt.Errorf("failed to get RU organizations: %w", err)
+	}
+	for _, ru := range rus {
+		organizations[ru.ID] = ru.OrganizationID
+	}
+	return organizations, nil
+}
+
+// GetAllRUs - действующие РУ, новые первыми
 func (r *RuRepository) GetAllRUs() ([]models.RUInfo, error) {
 	var rus []models.RUInfo
-	result := r.db.Order("created_at DESC").Find(&rus)
+	result := r.db.Where("archived_at IS NULL").Order("created_at DESC").Find(&rus)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get all RUs: %w", result.Error)
 	}
 	return rus, nil
 }
+
+// GetRuStats - агрегаты по всем РУ двумя сгруппированными запросами (ячейки и активные аварии)
+func (r *RuRepository) GetRuStats() (map[string]*models.RUStats, error) {
+	return r.ruStats(nil)
+}
+
+// GetRuStatsByIDs - агрегаты по перечисленным РУ
+func (r *RuRepository) GetRuStatsByIDs(ruIDs []string) (map[string]*models.RUStats, error) {
+	if len(ruIDs) == 0 {
+		return map[string]*models.RUStats{}, nil
+	}
+	return r.ruStats(ruIDs)
+}
+
+// ruStats - агрегаты по РУ; ruIDs == nil - по всем
+func (r *RuRepository) ruStats(ruIDs []string) (map[string]*models.RUStats, error) {
+	stats := map[string]*models.RUStats{}
+	statsFor := func(ruID string) *models.RUStats {
+		if stats[ruID] == nil {
+			stats[ruID] = &models.RUStats{CellsByStatus: map[models.CellStatus]int{}}
+		}
+		return stats[ruID]
+	}
+
+	var cellCounts []struct {
+		RuID   string
+		Status models.CellStatus
+		Count  int
+	}
+	cells := r.db.Model(&models.Cell{})
+	if ruIDs != nil {
+		cells = cells.Where("ru_id IN ?", ruIDs)
+	}
+	err := cells.
+		Select("ru_id, status, count(*) AS count").
+		Group("ru_id, status").
+		Scan(&cellCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cells: %w", err)
+	}
+	for _, row := range cellCounts {
+		st := statsFor(row.RuID)
+		st.CellsByStatus[row.Status] += row.Count
+		st.CellsTotal += row.Count
+	}
+
+	var alarmCounts []struct {
+		RuID     string
+		Severity models.AlarmSeverity
+		Count    int
+	}
+	alarms := r.db.Model(&models.Alarm{})
+	if ruIDs != nil {
+		alarms = alarms.Where("ru_id IN ?", ruIDs)
+	}
+	err = alarms.
+		Select("ru_id, severity, count(*) AS count").
+		Where("status = ?", models.AlarmStatusActive).
+		Group("ru_id, severity").
+		Scan(&alarmCounts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count alarms: %w", err)
+	}
+	for _, row := range alarmCounts {
+		st := statsFor(row.RuID)
+		st.ActiveAlarms += row.Count
+		if row.Severity == models.AlarmSeverityCritical {
+			st.CriticalAlarms += row.Count
+		}
+	}
+
+	return stats, nil
+}
+
+// GetWorkPermitsByResponsible - наряды, где пользователь указан ответственным
+func (r *RuRepository) GetWorkPermitsByResponsible(userID string) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	result := r.db.Where("responsible_user_id = ? AND work_order_number IS NOT NULL", userID).
+		Order("created_at DESC").
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get work permits by responsible person: %w", result.Error)
+	}
+	return records, nil
+}
+
+// IsNotFound - проверяет, что ошибка репозитория означает отсутствие записи
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// IsDuplicate - проверяет, что запись нарушила уникальный индекс
+func IsDuplicate(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// IsForeignKeyViolation - проверяет, что запись ссылается на несуществующую строку
+// или удаляемая строка еще используется
+func IsForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23503"
+	}
+	return errors.Is(err, gorm.ErrForeignKeyViolated)
+}
+
+// GetSubstationByID - подстанция по идентификатору
+func (r *RuRepository) GetSubstationByID(id string) (*models.Substation, error) {
+	var substation models.Substation
+	if err := r.db.Where("id = ?", id).First(&substation).Error; err != nil {
+		return nil, fmt.Errorf("failed to get substation: %w", err)
+	}
+	return &substation, nil
+}
+
+// GetSubstations - подстанции организации; пустая организация - все подстанции
+func (r *RuRepository) GetSubstations(organizationID string) ([]models.Substation, error) {
+	var substations []models.Substation
+	query := r.db.Order("name")
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if err := query.Find(&substations).Error; err != nil {
+		return nil, fmt.Errorf("failed to get substations: %w", err)
+	}
+	return substations, nil
+}
+
+// SetRuLocation - координаты РУ; false, если РУ не найдено
+func (r *RuRepository) SetRuLocation(ruID string, latitude, longitude float64) (bool, error) {
+	result := r.db.Model(&models.RUInfo{}).Where("id = ?", ruID).
+		Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set RU location: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SetSubstationLocation - координаты подстанции; false, если подстанция не найдена
+func (r *RuRepository) SetSubstationLocation(substationID string, latitude, longitude float64) (bool, error) {
+	result := r.db.Model(&models.Substation{}).Where("id = ?", substationID).
+		Updates(map[string]interface{}{"latitude": latitude, "longitude": longitude, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set substation location: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetRUsBySubstationID - действующие РУ подстанции по названию
+func (r *RuRepository) GetRUsBySubstationID(substationID string) ([]models.RUInfo, error) {
+	var rus []models.RUInfo
+	result := r.db.Where("substation_id = ? AND archived_at IS NULL", substationID).Order("name ASC").Find(&rus)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get RUs by substation ID: %w", result.Error)
+	}
+	return rus, nil
+}
+
+// SetSubstationRUs - переносит РУ assign на подстанцию (вместе с организацией подстанции
+// у РУ и их записей журнала) и отвязывает РУ unassign одной транзакцией
+func (r *RuRepository) SetSubstationRUs(substation *models.Substation, assign, unassign []string) error {
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(assign) > 0 {
+			err := tx.Model(&models.RUInfo{}).Where("id IN ?", assign).Updates(map[string]interface{}{
+				"substation_id":   substation.ID,
+				"organization_id": substation.OrganizationID,
+				"updated_at":      now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			// Организация не входит в хеш записи журнала, перенос цепочку не нарушает
+			err = allowJournalUpdate(tx).Model(&models.OperationRecord{}).
+				Where("ru_id IN ? AND organization_id <> ?", assign, substation.OrganizationID).
+				Update("organization_id", substation.OrganizationID).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(unassign) > 0 {
+			err := tx.Model(&models.RUInfo{}).Where("id IN ? AND substation_id = ?", unassign, substation.ID).
+				Updates(map[string]interface{}{"substation_id": nil, "updated_at": now}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update substation RUs: %w", err)
+	}
+	return nil
+}
+
+// GetCellsByRuIDs - ячейки нескольких РУ одним запросом
+func (r *RuRepository) GetCellsByRuIDs(ruIDs []string) ([]models.Cell, error) {
+	var cells []models.Cell
+	if len(ruIDs) == 0 {
+		return cells, nil
+	}
+	result := r.db.Where("ru_id IN ?", ruIDs).Order("ru_id ASC, id ASC").Find(&cells)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get cells by RU IDs: %w", result.Error)
+	}
+	return cells, nil
+}
+
+// GetLatestHistoryByRuIDs - последние perRu операций по каждому РУ одним запросом
+func (r *RuRepository) GetLatestHistoryByRuIDs(ruIDs []string, perRu int) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	if len(ruIDs) == 0 || perRu <= 0 {
+		return records, nil
+	}
+	ranked := r.db.Model(&models.OperationRecord{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY ru_id ORDER BY created_at DESC) AS rn").
+		Where("ru_id IN ?", ruIDs)
+	result := r.db.Table("(?) AS ranked", ranked).
+		Where("rn <= ?", perRu).
+		Order("ru_id ASC, created_at DESC").
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get latest history by RU IDs: %w", result.Error)
+	}
+	return records, nil
+}
+
+func (r *RuRepository) GetHistoryRecordByID(ruID, recordID string) (*models.OperationRecord, error) {
+	var record models.OperationRecord
+	result := r.db.Where("id = ? AND ru_id = ?", recordID, ruID).First(&record)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get history record by ID: %w", result.Error)
+	}
+	return &record, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRuHasRecords - у РУ есть записи, и его можно только архивировать
+var ErrRuHasRecords = errors.New("RU has records")
+
+// GetRuDependencies - ячейки, записи журнала, наряды (всего и действующие на момент now),
+// активные замки и прочие записи РУ. Наряд без дат окончания и начала действующим не
+// считается, как при проверке плановых отключений.
+func (r *RuRepository) GetRuDependencies(ruID string, now time.Time) (*models.RuDependencies, error) {
+	var cells, records, permits, openPermits, locks int64
+	if err := r.db.Model(&models.Cell{}).Where("ru_id = ?", ruID).Count(&cells).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cells: %w", err)
+	}
+	if err := r.db.Model(&models.OperationRecord{}).Where("ru_id = ?", ruID).Count(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to count history records: %w", err)
+	}
+	if err := r.db.Model(&models.OperationRecord{}).Where("ru_id = ? AND work_order_number IS NOT NULL", ruID).Count(&permits).Error; err != nil {
+		return nil, fmt.Errorf("failed to count permits: %w", err)
+	}
+	err := r.db.Model(&models.OperationRecord{}).Where("ru_id = ? AND work_order_number IS NOT NULL", ruID).
+		Where("start_date_at IS NOT NULL OR end_date_at IS NOT NULL").
+		Where("end_date_at IS NULL OR end_date_at > ?", now).
+		Count(&openPermits).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open permits: %w", err)
+	}
+	if err := r.db.Model(&models.CellLock{}).Where("ru_id = ? AND removed_at IS NULL", ruID).Count(&locks).Error; err != nil {
+		return nil, fmt.Errorf("failed to count cell locks: %w", err)
+	}
+	other, err := countRuRecords(r.db, ruID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.RuDependencies{
+		Cells:          int(cells),
+		HistoryRecords: int(records),
+		Permits:        int(permits),
+		OpenPermits:    int(openPermits),
+		ActiveLocks:    int(locks),
+		Records:        other,
+	}, nil
+}
+
+// DeleteRu - удаляет РУ без журнала и прочих записей одной транзакцией вместе с
+// ячейками и настройками (ruOwnedTables, привязки устройств к ячейкам). Записи
+// пересчитываются в транзакции удаления: если они появились, возвращается
+// ErrRuHasRecords. Журнал операций, кроме того, защищен внешним ключом
+// fk_operation_records_ru.
+func (r *RuRepository) DeleteRu(ruID string) (bool, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var history int64
+		if err := tx.Model(&models.OperationRecord{}).Where("ru_id = ?", ruID).Count(&history).Error; err != nil {
+			return err
+		}
+		records, err := countRuRecords(tx, ruID)
+		if err != nil {
+			return err
+		}
+		if history > 0 || len(records) > 0 {
+			return ErrRuHasRecords
+		}
+
+		var cellIDs []int
+		if err := tx.Model(&models.Cell{}).Where("ru_id = ?", ruID).Pluck("id", &cellIDs).Error; err != nil {
+			return err
+		}
+		err = tx.Exec("DELETE FROM device_cells WHERE cell_id IN (SELECT id FROM cells WHERE ru_id = ?) OR device_id IN (SELECT id FROM devices WHERE ru_id = ?)",
+			ruID, ruID).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete device_cells: %w", err)
+		}
+		for _, table := range ruOwnedTables {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE ru_id = ?", table), ruID).Error; err != nil {
+				return fmt.Errorf("failed to delete %s: %w", table, err)
+			}
+		}
+		for _, cellID := range cellIDs {
+			if err := deleteCell(tx, cellID); err != nil {
+				return err
+			}
+		}
+		result := tx.Where("id = ?", ruID).Delete(&models.RUInfo{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRuHasRecords) {
+			return false, err
+		}
+		return false, fmt.Errorf("failed to delete RU: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+// ruRecordTables - таблицы записей, которые ссылаются на РУ по ru_id: события,
+// измерения, команды, заявки и т.п. Как и журнал, они не удаляются, поэтому РУ с любой
+// из них только архивируется.
+var ruRecordTables = []string{
+	"alarms",
+	"asset_events",
+	"assets",
+	"calendar_entries",
+	"cell_info_changes",
+	"cell_locks",
+	"comtrade_records",
+	"control_commands",
+	"defects",
+	"fault_events",
+	"forecast_runs",
+	"inspections",
+	"issued_document_numbers",
+	"measurement_rollups",
+	"measurements",
+	"meter_readings",
+	"notifications",
+	"planned_outages",
+	"reservations",
+	"sms_messages",
+	"status_confirmations",
+	"switching_orders",
+	"thermal_snapshots",
+	"transformer_tap_changes",
+	"vision_indications",
+	"vision_readings",
+	"voltage_measurements",
+}
+
+// ruOwnedTables - настройки РУ, которые удаляются вместе с ним: секции шин,
+// трансформаторы, устройства, правила уведомлений, подписки и т.п.
+var ruOwnedTables = []string{
+	"bus_sections",
+	"consumer_feeders",
+	"devices",
+	"notification_rules",
+	"polling_pauses",
+	"section_utilizations",
+	"subscriptions",
+	"transformers",
+}
+
+// ruOwnedCellTables - ссылки на ячейки из настроек РУ (и привязки устройств к ячейкам):
+// они не считаются записями и удаляются вместе с РУ
+var ruOwnedCellTables = map[string]bool{
+	"consumer_feeders": true,
+	"device_cells":     true,
+	"subscriptions":    true,
+	"transformers":     true,
+}
+
+// countRuRecords - число записей РУ по таблицам (только ненулевые): строки таблиц
+// ruRecordTables и записи, ссылающиеся на ячейки РУ, в том числе снимки ячеек.
+// Журнал операций считается отдельно.
+func countRuRecords(db *gorm.DB, ruID string) (map[string]int, error) {
+	records := map[string]int{}
+	recordTables := map[string]bool{}
+	for _, table := range ruRecordTables {
+		recordTables[table] = true
+		var count int64
+		if err := db.Table(table).Where("ru_id = ?", ruID).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		if count > 0 {
+			records[table] = int(count)
+		}
+	}
+
+	var cellIDs []int
+	if err := db.Model(&models.Cell{}).Where("ru_id = ?", ruID).Pluck("id", &cellIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	counts, err := countCellReferences(db, cellIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, refs := range counts {
+		for name, count := range refs {
+			table, _, _ := strings.Cut(name, ".")
+			if count == 0 || recordTables[table] || ruOwnedCellTables[table] {
+				continue
+			}
+			records[table] += int(count)
+		}
+	}
+	return records, nil
+}
+
+// GetArchivedRUs - архивированные РУ, последние первыми
+func (r *RuRepository) GetArchivedRUs() ([]models.RUInfo, error) {
+	var rus []models.RUInfo
+	if err := r.db.Where("archived_at IS NOT NULL").Order("archived_at DESC").Find(&rus).Error; err != nil {
+		return nil, fmt.Errorf("failed to get archived RUs: %w", err)
+	}
+	return rus, nil
+}
+
+// NextCellSequence - следующий порядковый номер «яч.N» на стороне РУ: на единицу больше
+// наибольшего
+func (r *RuRepository) NextCellSequence(ruID string, voltageLevel models.VoltageLevel) (int, error) {
+	return nextCellSequence(r.db, ruID, voltageLevel)
+}
+
+func nextCellSequence(db *gorm.DB, ruID string, voltageLevel models.VoltageLevel) (int, error) {
+	var numbers []string
+	err := db.Model(&models.Cell{}).
+		Where("ru_id = ? AND voltage_level = ? AND number LIKE ?", ruID, voltageLevel, models.CellNumberPrefix+"%").
+		Pluck("number", &numbers).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get cell numbers: %w", err)
+	}
+	next := 1
+	for _, number := range numbers {
+		if n, ok := models.CellSequence(number); ok && n >= next {
+			next = n + 1
+		}
+	}
+	return next, nil
+}
+
+// CreateCells - создает ячейки одной транзакцией: при ошибке любой из них не создается ни одна.
+// Ячейкам без номера номера назначаются под блокировкой РУ после наибольшего номера РУ и
+// пакета, так что одновременные пакеты не получают одинаковых номеров.
+func (r *RuRepository) CreateCells(cells []models.Cell) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		next := map[models.VoltageLevel]int{}
+		for i := range cells {
+			if cells[i].Number != "" {
+				continue
+			}
+			level := cells[i].VoltageLevel
+			if _, ok := next[level]; !ok {
+				if len(next) == 0 {
+					err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+						Where("id = ?", cells[i].RuID).First(&models.RUInfo{}).Error
+					if err != nil {
+						return err
+					}
+				}
+				seq, err := nextCellSequence(tx, cells[i].RuID, level)
+				if err != nil {
+					return err
+				}
+				// Номера, заданные в самом пакете, тоже заняты
+				for _, cell := range cells {
+					if n, ok := models.CellSequence(cell.Number); ok && cell.VoltageLevel == level && n >= seq {
+						seq = n + 1
+					}
+				}
+				next[level] = seq
+			}
+			cells[i].Number = models.CellNumberPrefix + strconv.Itoa(next[level])
+			next[level]++
+		}
+		for i := range cells {
+			syncCellDates(&cells[i])
+			if err := tx.Create(&cells[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create cells: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RuntimeRepository struct {
+	db *gorm.DB
+}
+
+func NewRuntimeRepository(db *gorm.DB) *RuntimeRepository {
+	return &RuntimeRepository{db: db}
+}
+
+// GetRunningCells - включенные ячейки всех РУ
+func (r *RuntimeRepository) GetRunningCells() ([]models.Cell, error) {
+	var cells []models.Cell
+	if err := r.db.Where("status = ?", models.CellStatusON).Order("id ASC").Find(&cells).Error; err != nil {
+		return nil, fmt.Errorf("failed to get running cells: %w", err)
+	}
+	return cells, nil
+}
+
+// SaveCellRuntime - сохраняет накопленную наработку ячейки, если с момента чтения ее
+// статус и момент учета не изменились (иначе наработку уже учла смена статуса)
+func (r *RuntimeRepository) SaveCellRuntime(cell *models.Cell, accountedAt *time.Time) (bool, error) {
+	query := r.db.Model(&models.Cell{}).Where("id = ? AND status = ?", cell.ID, models.CellStatusON)
+	if accountedAt == nil {
+		query = query.Where("runtime_accounted_at IS NULL")
+	} else {
+		query = query.Where("runtime_accounted_at = ?", *accountedAt)
+	}
+	result := query.UpdateColumns(map[string]interface{}{
+		"runtime_hours":        cell.RuntimeHours,
+		"runtime_accounted_at": cell.RuntimeAccountedAt,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to save cell runtime: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveRuRuntime - сохраняет наработку РУ
+func (r *RuntimeRepository) SaveRuRuntime(ru *models.RUInfo) error {
+	err := r.db.Model(&models.RUInfo{}).Where("id = ?", ru.ID).UpdateColumns(map[string]interface{}{
+		"operational_hours":    ru.OperationalHours,
+		"runtime_accounted_at": ru.RuntimeAccountedAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to save RU runtime: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// cellNumberIndex - номер ячейки уникален в пределах РУ и уровня напряжения
+// (одинаковые номера на стороне 10 кВ и 0,4 кВ допустимы)
+const cellNumberIndex = "idx_cells_ru_number_level"
+
+// cellVoltageLevelCheck - уровень напряжения ячейки только из перечня models.VoltageLevel
+const cellVoltageLevelCheck = "chk_cells_voltage_level"
+
+// foreignKey - внешний ключ, который добавляется к уже существующим таблицам
+type foreignKey struct {
+	name     string
+	table    string
+	column   string
+	refTable string
+	onDelete string
+}
+
+// integrityForeignKeys - ссылочная целостность РУ: ячейки удаляются вместе с РУ,
+// пара ячейки снимается при удалении ее ячейки противоположной стороны,
+// а РУ с журналом операций и подстанция с РУ удалены быть не могут
+var integrityForeignKeys = []foreignKey{
+	{name: "fk_cells_ru", table: "cells", column: "ru_id", refTable: "ru_infos", onDelete: "CASCADE"},
+	{name: "fk_cells_paired_cell", table: "cells", column: "paired_cell_id", refTable: "cells", onDelete: "SET NULL"},
+	{name: "fk_operation_records_ru", table: "operation_records", column: "ru_id", refTable: "ru_infos", onDelete: "RESTRICT"},
+	{name: "fk_ru_infos_substation", table: "ru_infos", column: "substation_id", refTable: "substations", onDelete: "RESTRICT"},
+}
+
+func init() {
+	schema.RegisterSerializer("nullstring", nullStringSerializer{})
+}
+
+// nullStringSerializer - хранит пустую строку как NULL. Внешний ключ не проверяет NULL,
+// поэтому так можно хранить необязательную ссылку в строковом поле (РУ без подстанции).
+type nullStringSerializer struct{}
+
+func (nullStringSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	value := ""
+	switch v := dbValue.(type) {
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(value)
+	return nil
+}
+
+func (nullStringSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if value, ok := fieldValue.(string); ok && value != "" {
+		return value, nil
+	}
+	return nil, nil
+}
+
+// BackfillSubstations - создает записи подстанций, на которые уже ссылаются РУ, а пустые
+// ссылки заменяет на NULL, чтобы внешний ключ ru_infos -> substations проходил проверку
+// на существующих данных
+func BackfillSubstations(db *gorm.DB) error {
+	if err := db.Exec(`UPDATE ru_infos SET substation_id = NULL WHERE substation_id = ''`).Error; err != nil {
+		return fmt.Errorf("failed to clear empty substation references: %w", err)
+	}
+	err := db.Exec(`
+		INSERT INTO substations (id, name, created_at, updated_at)
+		SELECT DISTINCT substation_id, substation_id, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+		FROM ru_infos
+		WHERE substation_id IS NOT NULL
+		ON CONFLICT (id) DO NOTHING`).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill substations: %w", err)
+	}
+	return nil
+}
+
+// EnsureIntegrityConstraints - уникальность номеров ячеек и внешние ключи РУ.
+// Ограничения добавляются к существующей схеме без остановки запуска: внешние ключи
+// создаются как NOT VALID (новые строки проверяются сразу) и затем валидируются;
+// если старые данные нарушают ограничение, это логируется, а ограничение остается
+// непроверенным для старых строк до очистки данных.
+func EnsureIntegrityConstraints(db *gorm.DB) error {
+	if err := ensureCellNumberIndex(db); err != nil {
+		return err
+	}
+	// SQLite не добавляет внешние ключи к существующим таблицам (ALTER TABLE ADD CONSTRAINT);
+	// для локальной разработки достаточно уникального индекса
+	if !IsPostgres(db) {
+		return nil
+	}
+	for _, fk := range integrityForeignKeys {
+		if err := ensureForeignKey(db, fk); err != nil {
+			return err
+		}
+	}
+	return ensureVoltageLevelCheck(db)
+}
+
+// ensureVoltageLevelCheck - ограничение CHECK на уровень напряжения ячеек; добавляется
+// как NOT VALID и валидируется так же, как внешние ключи
+func ensureVoltageLevelCheck(db *gorm.DB) error {
+	var state []struct{ Convalidated bool }
+	if err := db.Raw("SELECT convalidated FROM pg_constraint WHERE conname = ?", cellVoltageLevelCheck).Scan(&state).Error; err != nil {
+		return fmt.Errorf("failed to check constraint %s: %w", cellVoltageLevelCheck, err)
+	}
+	if len(state) > 0 && state[0].Convalidated {
+		return nil
+	}
+
+	if len(state) == 0 {
+		sql := fmt.Sprintf("ALTER TABLE cells ADD CONSTRAINT %s CHECK (voltage_level IN ('HIGH', 'LOW')) NOT VALID", cellVoltageLevelCheck)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to add constraint %s: %w", cellVoltageLevelCheck, err)
+		}
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE cells VALIDATE CONSTRAINT %s", cellVoltageLevelCheck)).Error; err != nil {
+		var invalid int64
+		db.Raw("SELECT count(*) FROM cells WHERE voltage_level NOT IN ('HIGH', 'LOW')").Scan(&invalid)
+		log.Printf("⚠️ Constraint %s is enforced for new rows only: %d existing cells have an unknown voltage level",
+			cellVoltageLevelCheck, invalid)
+	}
+	return nil
+}
+
+func ensureCellNumberIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex("cells", cellNumberIndex) {
+		return nil
+	}
+
+	var duplicates []struct {
+		RuID         string
+		Number       string
+		VoltageLevel string
+		Count        int
+	}
+	err := db.Raw(`
+		SELECT ru_id, number, voltage_level, count(*) AS count
+		FROM cells
+		GROUP BY ru_id, number, voltage_level
+		HAVING count(*) > 1
+		ORDER BY ru_id, number`).Scan(&duplicates).Error
+	if err != nil {
+		return fmt.Errorf("failed to find duplicate cells: %w", err)
+	}
+	if len(duplicates) > 0 {
+		for _, d := range duplicates {
+			log.Printf("⚠️ Duplicate cell %s (%s) in RU %s: %d rows", d.Number, d.VoltageLevel, d.RuID, d.Count)
+		}
+		log.Printf("⚠️ Unique index %s is not created until duplicate cells are resolved", cellNumberIndex)
+		return nil
+	}
+
+	sql := fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS %s ON cells (ru_id, number, voltage_level)", cellNumberIndex)
+	if err := db.Exec(sql).Error; err != nil {
+		return fmt.Errorf("failed to create index %s: %w", cellNumberIndex, err)
+	}
+	return nil
+}
+
+func ensureForeignKey(db *gorm.DB, fk foreignKey) error {
+	var state []struct{ Convalidated bool }
+	if err := db.Raw("SELECT convalidated FROM pg_constraint WHERE conname = ?", fk.name).Scan(&state).Error; err != nil {
+		return fmt.Errorf("failed to check constraint %s: %w", fk.name, err)
+	}
+	if len(state) > 0 && state[0].Convalidated {
+		return nil
+	}
+
+	if len(state) == 0 {
+		sql := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (id) ON UPDATE CASCADE ON DELETE %s NOT VALID",
+			fk.table, fk.name, fk.column, fk.refTable, fk.onDelete)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to add constraint %s: %w", fk.name, err)
+		}
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE %s VALIDATE CONSTRAINT %s", fk.table, fk.name)).Error; err != nil {
+		var orphans int64
+		db.Raw(fmt.Sprintf("SELECT count(*) FROM %s t WHERE NOT EXISTS (SELECT 1 FROM %s r WHERE r.id = t.%s)",
+			fk.table, fk.refTable, fk.column)).Scan(&orphans)
+		log.Printf("⚠️ Constraint %s is enforced for new rows only: %d existing %s rows reference missing %s",
+			fk.name, orphans, fk.table, fk.refTable)
+	}
+	return nil
+}
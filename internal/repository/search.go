@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SearchConfig - конфигурация полнотекстового поиска PostgreSQL (копия russian:
+// русский стемминг, латиница - через english_stem)
+const SearchConfig = "sez_search"
+
+// Казахские буквы приводятся к близким русским до стемминга, чтобы русский
+// стеммер обрабатывал смешанные тексты. Та же замена применяется к запросу.
+const (
+	kazakhLetters  = "әғқңөұүһі"
+	kazakhReplaced = "агкноуухи"
+)
+
+type searchSource struct {
+	resultType models.SearchResultType
+	table      string
+	id         string
+	title      string
+	document   string
+}
+
+var searchSources = []searchSource{
+	{
+		resultType: models.SearchResultCell,
+		table:      "cells",
+		id:         "CAST(id AS TEXT)",
+		title:      "number || ' ' || name",
+		document:   "coalesce(number, '') || ' ' || coalesce(name, '') || ' ' || coalesce(description, '')",
+	},
+	{
+		resultType: models.SearchResultOperation,
+		table:      "operation_records",
+		id:         "id",
+		title:      "cell_number || ' ' || action",
+		document:   "coalesce(cell_name, '') || ' ' || coalesce(action, '') || ' ' || coalesce(reason, '') || ' ' || coalesce(comment, '')",
+	},
+	{
+		resultType: models.SearchResultRU,
+		table:      "ru_infos",
+		id:         "id",
+		title:      "name",
+		document:   "coalesce(name, '') || ' ' || coalesce(location, '') || ' ' || coalesce(manufacturer, '')",
+	},
+}
+
+// normalizedDocument - текст документа в том виде, в котором он индексируется
+func (s searchSource) normalizedDocument() string {
+	return fmt.Sprintf("translate(lower(%s), '%s', '%s')", s.document, kazakhLetters, kazakhReplaced)
+}
+
+// vector - выражение tsvector; должно совпадать с выражением индекса, иначе индекс не используется
+func (s searchSource) vector() string {
+	return fmt.Sprintf("to_tsvector('%s', %s)", SearchConfig, s.normalizedDocument())
+}
+
+// EnsureSearchIndexes - создает конфигурацию поиска и GIN-индексы по выражениям
+func EnsureSearchIndexes(db *gorm.DB) error {
+	if !IsPostgres(db) {
+		return nil
+	}
+
+	var exists bool
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", SearchConfig).Scan(&exists).Error; err != nil {
+		return fmt.Errorf("failed to check search configuration: %w", err)
+	}
+	if !exists {
+		if err := db.Exec(fmt.Sprintf("CREATE TEXT SEARCH CONFIGURATION %s (COPY = russian)", SearchConfig)).Error; err != nil {
+			return fmt.Errorf("failed to create search configuration: %w", err)
+		}
+		log.Printf("✅ Created text search configuration %s", SearchConfig)
+	}
+
+	for _, source := range searchSources {
+		stmt := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_search ON %s USING GIN (%s)", source.table, source.table, source.vector())
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create search index on %s: %w", source.table, err)
+		}
+	}
+	return nil
+}
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search - ищет по tsquery в выбранных типах документов РУ организации (пустая - всех
+// организаций), результаты отсортированы по релевантности
+func (r *SearchRepository) Search(tsquery string, types map[models.SearchResultType]bool, ruID, organizationID string, limit int) ([]models.SearchResult, error) {
+	if !IsPostgres(r.db) {
+		return r.searchLike(tsquery, types, ruID, organizationID, limit)
+	}
+
+	results := []models.SearchResult{}
+
+	for _, source := range searchSources {
+		if len(types) > 0 && !types[source.resultType] {
+			continue
+		}
+
+		query := fmt.Sprintf("to_tsquery('%s', ?)", SearchConfig)
+		var rows []models.SearchResult
+		stmt := r.db.Table(source.table).
+			Select(fmt.Sprintf(
+				"? AS type, %s AS id, %s AS ru_id, %s AS title, ts_headline('%s', %s, %s, 'MaxWords=20, MinWords=5') AS snippet, ts_rank(%s, %s) AS rank",
+				source.id, ruIDColumn(source), source.title, SearchConfig, source.document, query, source.vector(), query,
+			), source.resultType, tsquery, tsquery).
+			Where(fmt.Sprintf("%s @@ %s", source.vector(), query), tsquery)
+		if ruID != "" {
+			stmt = stmt.Where(ruIDColumn(source)+" = ?", ruID)
+		}
+		stmt = scopeOrganizationRUs(stmt, ruIDColumn(source), organizationID)
+		if err := stmt.Order("rank DESC").Limit(limit).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", source.table, err)
+		}
+		results = append(results, rows...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchLike - поиск подстрокой для диалектов без полнотекстового поиска (SQLite при
+// локальной разработке): без стемминга и ранжирования, каждая группа слов запроса
+// to_tsquery должна встретиться в документе
+func (r *SearchRepository) searchLike(tsquery string, types map[models.SearchResultType]bool, ruID, organizationID string, limit int) ([]models.SearchResult, error) {
+	results := []models.SearchResult{}
+
+	for _, source := range searchSources {
+		if len(types) > 0 && !types[source.resultType] {
+			continue
+		}
+
+		document := fmt.Sprintf("lower(%s)", source.document)
+		var rows []models.SearchResult
+		stmt := r.db.Table(source.table).
+			Select(fmt.Sprintf("? AS type, %s AS id, %s AS ru_id, %s AS title, %s AS snippet, 0 AS rank",
+				source.id, ruIDColumn(source), source.title, source.document), source.resultType)
+		for _, group := range strings.Split(tsquery, " & ") {
+			var conditions []string
+			var args []interface{}
+			for _, word := range strings.Split(strings.Trim(group, "()"), " | ") {
+				conditions = append(conditions, document+" LIKE ?")
+				args = append(args, "%"+strings.TrimSuffix(strings.TrimSpace(word), ":*")+"%")
+			}
+			stmt = stmt.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		}
+		if ruID != "" {
+			stmt = stmt.Where(ruIDColumn(source)+" = ?", ruID)
+		}
+		stmt = scopeOrganizationRUs(stmt, ruIDColumn(source), organizationID)
+		if err := stmt.Limit(limit).Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", source.table, err)
+		}
+		results = append(results, rows...)
+	}
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func ruIDColumn(source searchSource) string {
+	if source.resultType == models.SearchResultRU {
+		return "id"
+	}
+	return "ru_id"
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SectionRepository struct {
+	db *gorm.DB
+}
+
+func NewSectionRepository(db *gorm.DB) *SectionRepository {
+	return &SectionRepository{db: db}
+}
+
+// SyncSections - создает секции, на которые ссылаются ячейки, но которых еще нет.
+// ruID ограничивает синхронизацию одним РУ; пустой - все РУ.
+func SyncSections(db *gorm.DB, ruID string) error {
+	filter := ""
+	args := []interface{}{}
+	if ruID != "" {
+		filter = "AND ru_id = ?"
+		args = append(args, ruID)
+	}
+	err := db.Exec(`
+		INSERT INTO bus_sections (ru_id, voltage_level, number, name, created_at, updated_at)
+		SELECT DISTINCT ru_id, voltage_level, bus_section, '', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+		FROM cells
+		WHERE bus_section IS NOT NULL `+filter+`
+		ON CONFLICT (ru_id, voltage_level, number) DO NOTHING`, args...).Error
+	if err != nil {
+		return fmt.Errorf("failed to sync sections: %w", err)
+	}
+	return nil
+}
+
+// GetByRuID - секции РУ по уровню напряжения и номеру
+func (r *SectionRepository) GetByRuID(ruID string) ([]models.Section, error) {
+	if err := SyncSections(r.db, ruID); err != nil {
+		return nil, err
+	}
+	var sections []models.Section
+	err := r.db.Where("ru_id = ?", ruID).Order("voltage_level ASC, number ASC").Find(&sections).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	return sections, nil
+}
+
+func (r *SectionRepository) GetByID(ruID string, sectionID int) (*models.Section, error) {
+	var section models.Section
+	if err := r.db.Where("id = ? AND ru_id = ?", sectionID, ruID).First(&section).Error; err != nil {
+		return nil, fmt.Errorf("failed to get section: %w", err)
+	}
+	return &section, nil
+}
+
+// GetCells - ячейки секции
+func (r *SectionRepository) GetCells(section *models.Section) ([]models.Cell, error) {
+	var cells []models.Cell
+	err := r.db.Where("ru_id = ? AND voltage_level = ? AND bus_section = ?", section.RuID, section.VoltageLevel, section.Number).
+		Order("id ASC").Find(&cells).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get section cells: %w", err)
+	}
+	return cells, nil
+}
+
+func (r *SectionRepository) Save(section *models.Section) error {
+	if err := r.db.Save(section).Error; err != nil {
+		return fmt.Errorf("failed to save section: %w", err)
+	}
+	return nil
+}
+
+// CreateOrder - сохраняет бланк переключений вместе с операциями
+func (r *SectionRepository) CreateOrder(order *models.SwitchingOrder) error {
+	if err := r.db.Create(order).Error; err != nil {
+		return fmt.Errorf("failed to create switching order: %w", err)
+	}
+	return nil
+}
+
+// GetOrders - бланки переключений РУ, последние первыми
+func (r *SectionRepository) GetOrders(ruID string, limit int) ([]models.SwitchingOrder, error) {
+	var orders []models.SwitchingOrder
+	err := r.db.Preload("Steps", orderSwitchingSteps).
+		Where("ru_id = ?", ruID).Order("created_at DESC").Limit(limit).Find(&orders).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get switching orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (r *SectionRepository) GetOrder(ruID, orderID string) (*models.SwitchingOrder, error) {
+	var order models.SwitchingOrder
+	err := r.db.Preload("Steps", orderSwitchingSteps).
+		Where("id = ? AND ru_id = ?", orderID, ruID).First(&order).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get switching order: %w", err)
+	}
+	return &order, nil
+}
+
+// orderSwitchingSteps - операции бланка в порядке выполнения, пропущенные ячейки в конце
+func orderSwitchingSteps(db *gorm.DB) *gorm.DB {
+	return db.Order("seq = 0 ASC, seq ASC, id ASC")
+}
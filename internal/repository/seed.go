@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedIgnoredFields - поля, которые задает база, а не набор начальных данных
+var seedIgnoredFields = map[string]bool{"ID": true, "CreatedAt": true, "UpdatedAt": true}
+
+// ApplySeedFixture - применяет набор начальных данных одной транзакцией: при ошибке не
+// создается ничего, в том числе половина ячеек РУ. Набор с той же контрольной суммой
+// пропускается. Изменившийся набор применяется как разница с прошлой версией: создаются
+// новые записи, а у существующих меняются только поля, измененные в наборе, так что
+// правки, сделанные в работе, сохраняются. Записи, которые уже были в базе до учета
+// версий, не меняются - недостающие создаются.
+func ApplySeedFixture(db *gorm.DB, fixture models.SeedFixture) (*models.SeedResult, error) {
+	content, err := json.Marshal(fixture)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode seed fixture %s: %w", fixture.Name, err)
+	}
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+	result := &models.SeedResult{Fixture: fixture.Name, Outcome: models.SeedUnchanged}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var versions []models.SeedVersion
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("fixture = ?", fixture.Name).Limit(1).Find(&versions).Error
+		if err != nil {
+			return fmt.Errorf("failed to get seed version: %w", err)
+		}
+		var previous *models.SeedFixture
+		if len(versions) > 0 {
+			if versions[0].Checksum == checksum {
+				return nil
+			}
+			previous = &models.SeedFixture{}
+			if err := json.Unmarshal([]byte(versions[0].Content), previous); err != nil {
+				return fmt.Errorf("failed to decode previous seed fixture: %w", err)
+			}
+		}
+
+		result.Outcome = models.SeedApplied
+		if previous != nil {
+			result.Outcome = models.SeedUpdated
+		}
+		if err := seedSubstations(tx, fixture, previous, result); err != nil {
+			return err
+		}
+		if err := seedRu(tx, fixture, previous, result); err != nil {
+			return err
+		}
+		if err := seedCells(tx, fixture, previous, result); err != nil {
+			return err
+		}
+
+		version := models.SeedVersion{Fixture: fixture.Name, Checksum: checksum, Content: string(content), AppliedAt: time.Now()}
+		return tx.Save(&version).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply seed fixture %s: %w", fixture.Name, err)
+	}
+	return result, nil
+}
+
+func seedSubstations(tx *gorm.DB, fixture models.SeedFixture, previous *models.SeedFixture, result *models.SeedResult) error {
+	before := map[string]models.Substation{}
+	if previous != nil {
+		for _, substation := range previous.Substations {
+			before[substation.ID] = substation
+		}
+	}
+	for _, substation := range fixture.Substations {
+		var existing []models.Substation
+		if err := tx.Where("id = ?", substation.ID).Limit(1).Find(&existing).Error; err != nil {
+			return fmt.Errorf("failed to get substation %s: %w", substation.ID, err)
+		}
+		if len(existing) == 0 {
+			if err := tx.Create(&substation).Error; err != nil {
+				return fmt.Errorf("failed to create substation %s: %w", substation.ID, err)
+			}
+			result.Created++
+			continue
+		}
+		old, ok := before[substation.ID]
+		if !ok || !applySeedDiff(&existing[0], &old, &substation) {
+			continue
+		}
+		if err := tx.Save(&existing[0]).Error; err != nil {
+			return fmt.Errorf("failed to update substation %s: %w", substation.ID, err)
+		}
+		result.Updated++
+	}
+	return nil
+}
+
+func seedRu(tx *gorm.DB, fixture models.SeedFixture, previous *models.SeedFixture, result *models.SeedResult) error {
+	if fixture.RU == nil {
+		return nil
+	}
+	ru := *fixture.RU
+	var existing []models.RUInfo
+	if err := tx.Where("id = ?", ru.ID).Limit(1).Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get RU %s: %w", ru.ID, err)
+	}
+	if len(existing) == 0 {
+		syncRuDates(&ru)
+		syncRuCapacity(&ru)
+		if err := tx.Create(&ru).Error; err != nil {
+			return fmt.Errorf("failed to create RU %s: %w", ru.ID, err)
+		}
+		result.Created++
+		return nil
+	}
+	if previous == nil || previous.RU == nil || !applySeedDiff(&existing[0], previous.RU, &ru) {
+		return nil
+	}
+	syncRuDates(&existing[0])
+	syncRuCapacity(&existing[0])
+	if err := tx.Save(&existing[0]).Error; err != nil {
+		return fmt.Errorf("failed to update RU %s: %w", ru.ID, err)
+	}
+	result.Updated++
+	return nil
+}
+
+// seedCells - ячейки набора сопоставляются с базой по РУ, номеру и уровню напряжения.
+// Ячейки, исчезнувшие из набора, не удаляются: на них могут ссылаться журнал и телеметрия.
+func seedCells(tx *gorm.DB, fixture models.SeedFixture, previous *models.SeedFixture, result *models.SeedResult) error {
+	if len(fixture.Cells) == 0 {
+		return nil
+	}
+	key := func(cell models.Cell) string {
+		return cell.RuID + "/" + string(cell.VoltageLevel) + "/" + cell.Number
+	}
+	before := map[string]models.Cell{}
+	if previous != nil {
+		for _, cell := range previous.Cells {
+			before[key(cell)] = cell
+		}
+	}
+	ruIDs := map[string]bool{}
+	for _, cell := range fixture.Cells {
+		ruIDs[cell.RuID] = true
+	}
+	ids := make([]string, 0, len(ruIDs))
+	for id := range ruIDs {
+		ids = append(ids, id)
+	}
+	var current []models.Cell
+	if err := tx.Where("ru_id IN ?", ids).Order("id ASC").Find(&current).Error; err != nil {
+		return fmt.Errorf("failed to get cells: %w", err)
+	}
+	existing := make(map[string]*models.Cell, len(current))
+	for i := range current {
+		if _, ok := existing[key(current[i])]; !ok {
+			existing[key(current[i])] = &current[i]
+		}
+	}
+
+	for _, cell := range fixture.Cells {
+		k := key(cell)
+		old, seeded := before[k]
+		delete(before, k)
+		target, ok := existing[k]
+		if !ok {
+			syncCellDates(&cell)
+			if err := tx.Create(&cell).Error; err != nil {
+				return fmt.Errorf("failed to create cell %s: %w", cell.Number, err)
+			}
+			result.Created++
+			continue
+		}
+		if !seeded || !applySeedDiff(target, &old, &cell) {
+			continue
+		}
+		syncCellDates(target)
+		if err := tx.Save(target).Error; err != nil {
+			return fmt.Errorf("failed to update cell %s: %w", cell.Number, err)
+		}
+		result.Updated++
+	}
+	for _, cell := range before {
+		log.Printf("⚠️ Seed fixture %s no longer contains cell %s (%s) of %s; the cell is left in place",
+			fixture.Name, cell.Number, cell.VoltageLevel, cell.RuID)
+	}
+	return nil
+}
+
+// applySeedDiff - переносит в target поля, которые отличаются в старой и новой версии
+// набора; false - таких полей нет
+func applySeedDiff(target, old, next interface{}) bool {
+	t := reflect.ValueOf(target).Elem()
+	o := reflect.ValueOf(old).Elem()
+	n := reflect.ValueOf(next).Elem()
+	changed := false
+	for i := 0; i < n.NumField(); i++ {
+		field := n.Type().Field(i)
+		if !field.IsExported() || seedIgnoredFields[field.Name] {
+			continue
+		}
+		if reflect.DeepEqual(o.Field(i).Interface(), n.Field(i).Interface()) {
+			continue
+		}
+		t.Field(i).Set(n.Field(i))
+		changed = true
+	}
+	return changed
+}
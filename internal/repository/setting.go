@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SettingRepository struct {
+	db *gorm.DB
+}
+
+func NewSettingRepository(db *gorm.DB) *SettingRepository {
+	return &SettingRepository{db: db}
+}
+
+func (r *SettingRepository) GetAll() ([]models.Setting, error) {
+	var settings []models.Setting
+	if err := r.db.Order("key").Find(&settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Save - сохраняет измененные и удаляет сброшенные к умолчанию настройки одной транзакцией
+func (r *SettingRepository) Save(upserts []models.Setting, resets []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(upserts) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "key"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+			}).Create(&upserts).Error
+			if err != nil {
+				return fmt.Errorf("failed to save settings: %w", err)
+			}
+		}
+		if len(resets) > 0 {
+			if err := tx.Where("key IN ?", resets).Delete(&models.Setting{}).Error; err != nil {
+				return fmt.Errorf("failed to reset settings: %w", err)
+			}
+		}
+		return nil
+	})
+}
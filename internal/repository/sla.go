@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SLARepository - дефекты и наряды-допуски для расчета сроков устранения и работ
+type SLARepository struct {
+	db *gorm.DB
+}
+
+func NewSLARepository(db *gorm.DB) *SLARepository {
+	return &SLARepository{db: db}
+}
+
+// GetDefects - дефекты РУ организации, заведенные в интервале [from, to); пустая
+// организация - дефекты всех РУ
+func (r *SLARepository) GetDefects(ruID, organizationID string, from, to time.Time) ([]models.Defect, error) {
+	var defects []models.Defect
+	query := r.db.Where("created_at >= ? AND created_at < ?", from, to)
+	if ruID != "" {
+		query = query.Where("ru_id = ?", ruID)
+	}
+	query = scopeOrganizationRUs(query, "ru_id", organizationID)
+	if err := query.Order("created_at ASC").Find(&defects).Error; err != nil {
+		return nil, fmt.Errorf("failed to get defects for SLA: %w", err)
+	}
+	return defects, nil
+}
+
+// GetPermits - наряды-допуски РУ организации с датой выдачи (начала) в интервале [from, to)
+func (r *SLARepository) GetPermits(ruID, organizationID string, from, to time.Time) ([]models.OperationRecord, error) {
+	var permits []models.OperationRecord
+	query := r.db.Where("work_order_number IS NOT NULL AND start_date_at >= ? AND start_date_at < ?", from, to)
+	if ruID != "" {
+		query = query.Where("ru_id = ?", ruID)
+	}
+	query = scopeOrganizationRUs(query, "ru_id", organizationID)
+	if err := query.Order("start_date_at ASC").Find(&permits).Error; err != nil {
+		return nil, fmt.Errorf("failed to get permits for SLA: %w", err)
+	}
+	return permits, nil
+}
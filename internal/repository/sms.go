@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SmsRepository - очередь и журнал SMS, а также поводы для них: новые критичные
+// аварии и истекающие наряды-допуски
+type SmsRepository struct {
+	db *gorm.DB
+}
+
+func NewSmsRepository(db *gorm.DB) *SmsRepository {
+	return &SmsRepository{db: db}
+}
+
+// CreateMessages - ставит SMS в очередь. Сообщение с тем же источником у пользователя
+// уже есть - повторный запуск задачи его пропускает.
+func (r *SmsRepository) CreateMessages(messages []models.SmsMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "source_id"}},
+		DoNothing: true,
+	}).Create(&messages)
+	if result.Error != nil {
+		return fmt.Errorf("failed to queue SMS: %w", result.Error)
+	}
+	return nil
+}
+
+// GetQueued - сообщения, ожидающие отправки, в порядке постановки в очередь
+func (r *SmsRepository) GetQueued(limit int) ([]models.SmsMessage, error) {
+	var messages []models.SmsMessage
+	result := r.db.Where("status = ?", models.SmsQueued).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&messages)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get queued SMS: %w", result.Error)
+	}
+	return messages, nil
+}
+
+// GetAwaitingDelivery - принятые провайдером сообщения без итогового статуса,
+// отправленные не раньше since
+func (r *SmsRepository) GetAwaitingDelivery(since time.Time, limit int) ([]models.SmsMessage, error) {
+	var messages []models.SmsMessage
+	result := r.db.Where("status = ? AND provider_message_id <> '' AND sent_at >= ?", models.SmsSent, since).
+		Order("sent_at ASC").
+		Limit(limit).
+		Find(&messages)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get SMS awaiting delivery: %w", result.Error)
+	}
+	return messages, nil
+}
+
+func (r *SmsRepository) UpdateMessage(message *models.SmsMessage) error {
+	if err := r.db.Save(message).Error; err != nil {
+		return fmt.Errorf("failed to update SMS: %w", err)
+	}
+	return nil
+}
+
+// GetMessages - журнал SMS, новые сверху
+func (r *SmsRepository) GetMessages(filter models.SmsFilter) ([]models.SmsMessage, error) {
+	messages := []models.SmsMessage{}
+	query := r.db.Model(&models.SmsMessage{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if err := query.Order("created_at DESC").Limit(filter.Limit).Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to get SMS: %w", err)
+	}
+	return messages, nil
+}
+
+// GetUsage - сообщения и части, поставленные в очередь за интервал [from, to),
+// по организации, поводу и состоянию
+func (r *SmsRepository) GetUsage(from, to time.Time) ([]models.SmsUsageRow, error) {
+	var rows []models.SmsUsageRow
+	err := r.db.Model(&models.SmsMessage{}).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Select("organization_id, kind, status, count(*) AS messages, COALESCE(SUM(segments), 0) AS segments").
+		Group("organization_id, kind, status").
+		Order("organization_id, kind, status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get SMS usage: %w", err)
+	}
+	return rows, nil
+}
+
+// GetNewCriticalAlarms - активные критичные аварии, возникшие не раньше since;
+// отложенные и подавленные нарядом-допуском аварии не рассылаются
+func (r *SmsRepository) GetNewCriticalAlarms(since time.Time) ([]models.Alarm, error) {
+	var alarms []models.Alarm
+	result := r.db.Where("severity = ? AND status = ? AND raised_at >= ?", models.AlarmSeverityCritical, models.AlarmStatusActive, since).
+		Where("(shelved_until IS NULL OR shelved_until <= ?) AND suppressed_by IS NULL", time.Now()).
+		Order("raised_at ASC").
+		Find(&alarms)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get critical alarms: %w", result.Error)
+	}
+	return alarms, nil
+}
+
+// GetExpiringPermits - наряды-допуски, срок которых истекает в интервале (from, to]
+func (r *SmsRepository) GetExpiringPermits(from, to time.Time) ([]models.OperationRecord, error) {
+	var permits []models.OperationRecord
+	result := r.db.Where("work_order_number IS NOT NULL AND end_date_at > ? AND end_date_at <= ?", from, to).
+		Order("end_date_at ASC").
+		Find(&permits)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get expiring permits: %w", result.Error)
+	}
+	return permits, nil
+}
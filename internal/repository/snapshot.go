@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImportRu - сохраняет РУ и ячейки снимка одной транзакцией.
+// Ячейки с нулевым ID создаются, остальные обновляются. pairs - пары ВН/НН снимка
+// по индексам в cells: ID созданных ячеек известны только после сохранения.
+func (r *RuRepository) ImportRu(ru *models.RUInfo, create bool, cells []models.Cell, pairs map[int]int) error {
+	syncRuDates(ru)
+	syncRuCapacity(ru)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if create {
+			if err := tx.Create(ru).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Save(ru).Error; err != nil {
+			return err
+		}
+
+		for i := range cells {
+			syncCellDates(&cells[i])
+			cells[i].PairedCellID = nil
+			if err := tx.Save(&cells[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i, j := range pairs {
+			if err := tx.Model(&models.Cell{}).Where("id = ?", cells[i].ID).Update("paired_cell_id", cells[j].ID).Error; err != nil {
+				return err
+			}
+			cells[i].PairedCellID = &cells[j].ID
+		}
+		return clearBrokenCellPairs(tx, ru.ID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import RU: %w", err)
+	}
+	return nil
+}
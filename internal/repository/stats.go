@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// operationTimeExpr - время операции журнала: время выполнения или, для старых записей,
+// время создания записи
+const operationTimeExpr = "COALESCE(timestamp_at, created_at)"
+
+// StatsRepository - агрегаты статистики использования системы для администратора
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// CountUsersByRole - число пользователей каждой роли
+func (r *StatsRepository) CountUsersByRole() (map[models.UserRole]int, error) {
+	var rows []struct {
+		Role  models.UserRole
+		Count int
+	}
+	err := r.db.Model(&models.User{}).
+		Select("role, count(*) AS count").
+		Group("role").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+
+	counts := make(map[models.UserRole]int, len(rows))
+	for _, row := range rows {
+		counts[row.Role] = row.Count
+	}
+	return counts, nil
+}
+
+// CountOperations - операции журнала за интервал [from, to) по всем РУ
+func (r *StatsRepository) CountOperations(from, to time.Time) (int, error) {
+	var count int64
+	err := r.db.Model(&models.OperationRecord{}).
+		Where(operationTimeExpr+" >= ? AND "+operationTimeExpr+" < ?", from, to).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count operations: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetMostActiveRUs - РУ с наибольшим числом операций за интервал [from, to), не более limit
+func (r *StatsRepository) GetMostActiveRUs(from, to time.Time, limit int) ([]models.RuActivity, error) {
+	var rows []models.RuActivity
+	err := r.db.Model(&models.OperationRecord{}).
+		Joins("JOIN ru_infos ON ru_infos.id = operation_records.ru_id").
+		Where(operationTimeExpr+" >= ? AND "+operationTimeExpr+" < ?", from, to).
+		Select("operation_records.ru_id AS ru_id, MAX(ru_infos.name) AS name, count(*) AS operations").
+		Group("operation_records.ru_id").
+		Order("operations DESC, ru_id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get most active RUs: %w", err)
+	}
+	return rows, nil
+}
+
+// GetPhotoUsage - фотографии: число, объем оригиналов (в БД и во внешнем хранилище)
+// и объем миниатюр, которые всегда хранятся в БД
+func (r *StatsRepository) GetPhotoUsage() (models.AttachmentUsage, error) {
+	var usage models.AttachmentUsage
+	err := r.db.Model(&models.Photo{}).
+		Select(`count(*) AS count,
+			COALESCE(SUM(size), 0) AS bytes,
+			COALESCE(SUM(CASE WHEN storage_key = '' OR storage_key IS NULL THEN size ELSE 0 END), 0) AS database_bytes,
+			COALESCE(SUM(CASE WHEN storage_key = '' OR storage_key IS NULL THEN 0 ELSE size END), 0) AS external_bytes,
+			COALESCE(SUM(length(thumbnail)), 0) AS thumbnail_bytes`).
+		Scan(&usage).Error
+	if err != nil {
+		return usage, fmt.Errorf("failed to get photo usage: %w", err)
+	}
+	return usage, nil
+}
+
+// GetComtradeUsage - файлы осциллограмм COMTRADE: число и объем (хранятся в БД)
+func (r *StatsRepository) GetComtradeUsage() (models.AttachmentUsage, error) {
+	var usage models.AttachmentUsage
+	err := r.db.Model(&models.ComtradeFile{}).
+		Select("count(*) AS count, COALESCE(SUM(size), 0) AS bytes, COALESCE(SUM(size), 0) AS database_bytes").
+		Scan(&usage).Error
+	if err != nil {
+		return usage, fmt.Errorf("failed to get COMTRADE usage: %w", err)
+	}
+	return usage, nil
+}
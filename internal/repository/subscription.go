@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// GetByUsers - подписки нескольких пользователей одним запросом
+func (r *SubscriptionRepository) GetByUsers(userIDs []string) ([]models.Subscription, error) {
+	subscriptions := []models.Subscription{}
+	if len(userIDs) == 0 {
+		return subscriptions, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).Order("created_at ASC").Find(&subscriptions)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get subscriptions: %w", result.Error)
+	}
+	return subscriptions, nil
+}
+
+func (r *SubscriptionRepository) Create(subscription *models.Subscription) error {
+	if err := r.db.Create(subscription).Error; err != nil {
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) Delete(userID, id string) (bool, error) {
+	result := r.db.Delete(&models.Subscription{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete subscription: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetAssignments - закрепленные подстанции нескольких пользователей
+func (r *SubscriptionRepository) GetAssignments(userIDs []string) ([]models.UserSubstation, error) {
+	assignments := []models.UserSubstation{}
+	if len(userIDs) == 0 {
+		return assignments, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).Order("substation_id ASC").Find(&assignments)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get substation assignments: %w", result.Error)
+	}
+	return assignments, nil
+}
+
+// ReplaceAssignments - заменяет список закрепленных подстанций пользователя
+func (r *SubscriptionRepository) ReplaceAssignments(userID string, substationIDs []string, at time.Time) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.UserSubstation{}, "user_id = ?", userID).Error; err != nil {
+			return err
+		}
+		if len(substationIDs) == 0 {
+			return nil
+		}
+		assignments := make([]models.UserSubstation, 0, len(substationIDs))
+		for _, id := range substationIDs {
+			assignments = append(assignments, models.UserSubstation{UserID: userID, SubstationID: id, CreatedAt: at})
+		}
+		return tx.Create(&assignments).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace substation assignments: %w", err)
+	}
+	return nil
+}
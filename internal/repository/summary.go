@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SummaryRepository - агрегаты по группе РУ за интервал для суточной сводки
+type SummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewSummaryRepository(db *gorm.DB) *SummaryRepository {
+	return &SummaryRepository{db: db}
+}
+
+// CountOperationsByAction - записи журнала по действиям; время записи - время операции,
+// для записей без типизированной даты - время создания. Непустая severity отбирает записи
+// этой важности с учетом исправлений.
+func (r *SummaryRepository) CountOperationsByAction(ruIDs []string, from, to time.Time, severity models.RecordSeverity) (map[string]int, error) {
+	var rows []struct {
+		Action string
+		Count  int
+	}
+	query := r.operationsQuery(ruIDs, from, to)
+	if severity != "" {
+		query = query.Where(recordSeverityExpr+" = ?", severity)
+	}
+	err := query.Select("action, count(*) AS count").
+		Group("action").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count operations: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Action] = row.Count
+	}
+	return counts, nil
+}
+
+// CountOperationsBySeverity - записи журнала по важности с учетом исправлений
+func (r *SummaryRepository) CountOperationsBySeverity(ruIDs []string, from, to time.Time) (map[models.RecordSeverity]int, error) {
+	var rows []struct {
+		Severity models.RecordSeverity
+		Count    int
+	}
+	err := r.operationsQuery(ruIDs, from, to).
+		Select(recordSeverityExpr + " AS severity, count(*) AS count").
+		Group(recordSeverityExpr).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count operations by severity: %w", err)
+	}
+
+	counts := make(map[models.RecordSeverity]int, len(rows))
+	for _, row := range rows {
+		counts[row.Severity] = row.Count
+	}
+	return counts, nil
+}
+
+// CountOperationsByDocumentType - записи журнала с документом по видам справочника.
+// Документ записи (с учетом исправлений) сопоставляется с кодом или названием вида,
+// так что старые записи со свободным текстом тоже попадают в свой вид; не найденные
+// в справочнике считаются unclassified.
+func (r *SummaryRepository) CountOperationsByDocumentType(ruIDs []string, from, to time.Time, severity models.RecordSeverity) (map[string]int, error) {
+	records := r.operationsQuery(ruIDs, from, to).
+		Select(correctedColumnExpr("documentType", "document_type") + " AS document_type")
+	if severity != "" {
+		records = records.Where(recordSeverityExpr+" = ?", severity)
+	}
+
+	var rows []struct {
+		Code  string
+		Count int
+	}
+	codes := r.db.Table("(?) AS r", records).
+		Select(`COALESCE((SELECT d.code FROM document_types d
+			WHERE lower(d.code) = lower(trim(r.document_type)) OR lower(d.name) = lower(trim(r.document_type))
+			ORDER BY d.code LIMIT 1), ?) AS code`, models.DocumentTypeUnclassified).
+		Where("r.document_type IS NOT NULL AND trim(r.document_type) <> ''")
+	err := r.db.Table("(?) AS c", codes).
+		Select("c.code, count(*) AS count").
+		Group("c.code").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count operations by document type: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Code] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *SummaryRepository) operationsQuery(ruIDs []string, from, to time.Time) *gorm.DB {
+	return r.db.Model(&models.OperationRecord{}).
+		Where("ru_id IN ?", ruIDs).
+		Where("COALESCE(timestamp_at, created_at) >= ? AND COALESCE(timestamp_at, created_at) < ?", from, to)
+}
+
+// GetEvents - доменные события РУ заданного типа за интервал. Доставленные события
+// хранятся в outbox до очистки (outbox-retention), поэтому за старые даты список пуст.
+func (r *SummaryRepository) GetEvents(eventType models.DomainEventType, ruIDs []string, from, to time.Time) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.Where("type = ? AND ru_id IN ? AND created_at >= ? AND created_at < ?", eventType, ruIDs, from, to).
+		Order("created_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
+}
+
+// CountAlarmsRaised - аварии по важности, возникшие за интервал
+func (r *SummaryRepository) CountAlarmsRaised(ruIDs []string, from, to time.Time) (map[models.AlarmSeverity]int, error) {
+	var rows []struct {
+		Severity models.AlarmSeverity
+		Count    int
+	}
+	err := r.db.Model(&models.Alarm{}).
+		Select("severity, count(*) AS count").
+		Where("ru_id IN ? AND raised_at >= ? AND raised_at < ?", ruIDs, from, to).
+		Group("severity").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count alarms: %w", err)
+	}
+
+	counts := make(map[models.AlarmSeverity]int, len(rows))
+	for _, row := range rows {
+		counts[row.Severity] = row.Count
+	}
+	return counts, nil
+}
+
+// CountAlarmsCleared - аварии, квитированные за интервал
+func (r *SummaryRepository) CountAlarmsCleared(ruIDs []string, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Alarm{}).
+		Where("ru_id IN ? AND acknowledged_at >= ? AND acknowledged_at < ?", ruIDs, from, to).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count cleared alarms: %w", err)
+	}
+	return count, nil
+}
+
+// CountPermits - наряды-допуски с датой начала (opened) и окончания (closed) в интервале
+func (r *SummaryRepository) CountPermits(ruIDs []string, from, to time.Time) (opened, closed int64, err error) {
+	permits := r.db.Model(&models.OperationRecord{}).Where("ru_id IN ? AND work_order_number IS NOT NULL", ruIDs)
+	if err := permits.Session(&gorm.Session{}).Where("start_date_at >= ? AND start_date_at < ?", from, to).Count(&opened).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count opened permits: %w", err)
+	}
+	if err := permits.Session(&gorm.Session{}).Where("end_date_at >= ? AND end_date_at < ?", from, to).Count(&closed).Error; err != nil {
+		return 0, 0, fmt.Errorf("failed to count closed permits: %w", err)
+	}
+	return opened, closed, nil
+}
+
+// GetPeakLoads - часовой агрегат с максимальной нагрузкой для каждого РУ за интервал
+func (r *SummaryRepository) GetPeakLoads(ruIDs []string, from, to time.Time) (map[string]models.MeasurementRollup, error) {
+	var rollups []models.MeasurementRollup
+	err := r.db.Where("resolution = ? AND metric = ? AND ru_id IN ? AND bucket_start >= ? AND bucket_start < ?",
+		models.Resolution1h, models.MetricLoad, ruIDs, from, to).
+		Order("max DESC").
+		Find(&rollups).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get peak loads: %w", err)
+	}
+
+	peaks := make(map[string]models.MeasurementRollup)
+	for _, rollup := range rollups {
+		if _, ok := peaks[rollup.RuID]; !ok {
+			peaks[rollup.RuID] = rollup
+		}
+	}
+	return peaks, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestCountOperationsByDocumentType(t *testing.T) {
+	db := newTestDB(t, &models.OperationRecord{}, &models.OperationCorrection{}, &models.CorrectedField{}, &models.DocumentType{})
+	repo := NewSummaryRepository(db)
+	at := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	types := []models.DocumentType{
+		{Code: "order", Name: "Order", Active: true},
+		{Code: "permit", Name: "Work permit", Active: true},
+	}
+	if err := db.Create(&types).Error; err != nil {
+		t.Fatalf("seed document types: %v", err)
+	}
+
+	doc := func(s string) *string { return &s }
+	records := []models.OperationRecord{
+		{ID: "r1", RuID: "ru-1", DocumentType: doc("order"), TimestampAt: &at},
+		{ID: "r2", RuID: "ru-1", DocumentType: doc(" ORDER "), TimestampAt: &at},
+		// Старая запись со свободным текстом - по названию вида
+		{ID: "r3", RuID: "ru-1", DocumentType: doc("work PERMIT"), TimestampAt: &at},
+		{ID: "r4", RuID: "ru-1", DocumentType: doc("служебная записка"), TimestampAt: &at},
+		{ID: "r5", RuID: "ru-1", DocumentType: doc(""), TimestampAt: &at},
+		{ID: "r6", RuID: "ru-1", TimestampAt: &at},
+		{ID: "r7", RuID: "ru-2", DocumentType: doc("order"), TimestampAt: &at},
+	}
+	if err := db.Create(&records).Error; err != nil {
+		t.Fatalf("seed records: %v", err)
+	}
+
+	got, err := repo.CountOperationsByDocumentType([]string{"ru-1"}, at.Add(-time.Hour), at.Add(time.Hour), "")
+	if err != nil {
+		t.Fatalf("CountOperationsByDocumentType: %v", err)
+	}
+	want := map[string]int{"order": 2, "permit": 1, models.DocumentTypeUnclassified: 1}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for code, count := range want {
+		if got[code] != count {
+			t.Errorf("%s: got %d, want %d", code, got[code], count)
+		}
+	}
+}
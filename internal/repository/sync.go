@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SyncRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncRepository(db *gorm.DB) *SyncRepository {
+	return &SyncRepository{db: db}
+}
+
+// GetChangedCells - ячейки РУ, измененные начиная с since, в порядке изменения
+func (r *SyncRepository) GetChangedCells(ruIDs []string, since time.Time, limit int) ([]models.Cell, error) {
+	var cells []models.Cell
+	result := r.db.Where("ru_id IN ? AND updated_at >= ?", ruIDs, since).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&cells)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get changed cells: %w", result.Error)
+	}
+	return cells, nil
+}
+
+// GetChangedOperations - записи журнала РУ, добавленные или измененные начиная с since
+func (r *SyncRepository) GetChangedOperations(ruIDs []string, since time.Time, limit int) ([]models.OperationRecord, error) {
+	var records []models.OperationRecord
+	result := r.db.Where("ru_id IN ? AND updated_at >= ?", ruIDs, since).
+		Order("updated_at ASC, id ASC").
+		Limit(limit).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get changed operations: %w", result.Error)
+	}
+	return records, nil
+}
+
+// GetNewInspections - осмотры РУ, зарегистрированные начиная с since (осмотры не изменяются)
+func (r *SyncRepository) GetNewInspections(ruIDs []string, since time.Time, limit int) ([]models.Inspection, error) {
+	var inspections []models.Inspection
+	result := r.db.Preload("Results", orderItems).
+		Where("ru_id IN ? AND created_at >= ?", ruIDs, since).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&inspections)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get new inspections: %w", result.Error)
+	}
+	return inspections, nil
+}
+
+// GetItems - уже принятые элементы пакета по идентификаторам клиента
+func (r *SyncRepository) GetItems(clientIDs []string) (map[string]models.SyncItem, error) {
+	items := make(map[string]models.SyncItem, len(clientIDs))
+	if len(clientIDs) == 0 {
+		return items, nil
+	}
+	var rows []models.SyncItem
+	if err := r.db.Where("client_id IN ?", clientIDs).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get sync items: %w", err)
+	}
+	for _, row := range rows {
+		items[row.ClientID] = row
+	}
+	return items, nil
+}
+
+func (r *SyncRepository) SaveItem(item *models.SyncItem) error {
+	if err := r.db.Create(item).Error; err != nil {
+		return fmt.Errorf("failed to save sync item: %w", err)
+	}
+	return nil
+}
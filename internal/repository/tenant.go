@@ -0,0 +1,12 @@
+package repository
+
+import "gorm.io/gorm"
+
+// scopeOrganizationRUs - только строки РУ организации: column - колонка с идентификатором
+// РУ. Пустая организация - без ограничения (администратор установки).
+func scopeOrganizationRUs(query *gorm.DB, column, organizationID string) *gorm.DB {
+	if organizationID == "" {
+		return query
+	}
+	return query.Where(column+" IN (SELECT id FROM ru_infos WHERE organization_id = ?)", organizationID)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ThermalRepository struct {
+	db *gorm.DB
+}
+
+func NewThermalRepository(db *gorm.DB) *ThermalRepository {
+	return &ThermalRepository{db: db}
+}
+
+func orderHotspots(db *gorm.DB) *gorm.DB {
+	return db.Order("id ASC")
+}
+
+// Create - снимок с нагретыми точками и событие о превышении в одной транзакции
+func (r *ThermalRepository) Create(snapshot *models.ThermalSnapshot, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create thermal snapshot: %w", err)
+	}
+	return nil
+}
+
+func (r *ThermalRepository) GetByID(cellID int, id string) (*models.ThermalSnapshot, error) {
+	var snapshot models.ThermalSnapshot
+	err := r.db.Preload("Hotspots", orderHotspots).Where("id = ? AND cell_id = ?", id, cellID).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// GetByCell - снимки ячейки за период в порядке съемки
+func (r *ThermalRepository) GetByCell(cellID int, filter models.ThermalFilter, limit int) ([]models.ThermalSnapshot, error) {
+	var snapshots []models.ThermalSnapshot
+	query := r.db.Preload("Hotspots", orderHotspots).Where("cell_id = ?", cellID)
+	if filter.From != nil {
+		query = query.Where("taken_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("taken_at < ?", *filter.To)
+	}
+	if err := query.Order("taken_at ASC").Limit(limit).Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("failed to get thermal snapshots: %w", err)
+	}
+	return snapshots, nil
+}
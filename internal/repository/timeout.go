@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryCancelKey = "query_timeout:cancel"
+
+// QueryTimeout - плагин GORM, ограничивающий время каждого запроса. Контекст с
+// тайм-аутом назначается перед выполнением и освобождается после, поэтому медленный
+// запрос отменяется на стороне PostgreSQL, а не копит горутины в ожидании ответа.
+// Запросы, уже выполняемые с дедлайном (db.WithContext), не переопределяются.
+type QueryTimeout struct {
+	Timeout time.Duration
+}
+
+func (QueryTimeout) Name() string {
+	return "query_timeout"
+}
+
+func (p QueryTimeout) Initialize(db *gorm.DB) error {
+	if p.Timeout <= 0 {
+		return nil
+	}
+
+	cb := db.Callback()
+	steps := []error{
+		cb.Create().Before("gorm:create").Register("query_timeout:before_create", p.begin),
+		cb.Create().After("gorm:create").Register("query_timeout:after_create", p.end),
+		cb.Query().Before("gorm:query").Register("query_timeout:before_query", p.begin),
+		cb.Query().After("gorm:query").Register("query_timeout:after_query", p.end),
+		cb.Update().Before("gorm:update").Register("query_timeout:before_update", p.begin),
+		cb.Update().After("gorm:update").Register("query_timeout:after_update", p.end),
+		cb.Delete().Before("gorm:delete").Register("query_timeout:before_delete", p.begin),
+		cb.Delete().After("gorm:delete").Register("query_timeout:after_delete", p.end),
+		cb.Raw().Before("gorm:raw").Register("query_timeout:before_raw", p.begin),
+		cb.Raw().After("gorm:raw").Register("query_timeout:after_raw", p.end),
+		// Row/Rows и Scan читают строки уже после колбэков, поэтому контекст здесь
+		// не отменяется досрочно и освобождается по истечении тайм-аута
+		cb.Row().Before("gorm:row").Register("query_timeout:before_row", p.begin),
+	}
+	for _, err := range steps {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timeoutParentKey - исходный контекст запроса под контекстом плагина. Цепочка GORM
+// может выполнить несколько запросов на одном Statement (Count, затем Find), и каждый
+// должен получить свой тайм-аут, а не уже истекший контекст предыдущего.
+type timeoutParentKey struct{}
+
+func (p QueryTimeout) begin(db *gorm.DB) {
+	parent := db.Statement.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	if original, ok := parent.Value(timeoutParentKey{}).(context.Context); ok {
+		parent = original
+	}
+	if _, ok := parent.Deadline(); ok {
+		db.Statement.Context = parent
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(parent, p.Timeout)
+	db.Statement.Context = context.WithValue(ctx, timeoutParentKey{}, parent)
+	db.InstanceSet(queryCancelKey, cancel)
+}
+
+func (p QueryTimeout) end(db *gorm.DB) {
+	value, ok := db.InstanceGet(queryCancelKey)
+	if !ok {
+		return
+	}
+	if cancel, ok := value.(context.CancelFunc); ok {
+		cancel()
+	}
+	if original, ok := db.Statement.Context.Value(timeoutParentKey{}).(context.Context); ok {
+		db.Statement.Context = original
+	}
+}
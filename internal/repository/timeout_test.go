@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQueryTimeoutKeepsCallerDeadline(t *testing.T) {
+	db := newTestDB(t)
+	// Общий тайм-аут истекает раньше, чем запрос успевает начаться
+	if err := db.Use(QueryTimeout{Timeout: time.Nanosecond}); err != nil {
+		t.Fatalf("register plugin: %v", err)
+	}
+
+	if err := db.Exec("SELECT 1").Error; err == nil {
+		t.Fatal("statement without deadline ignored the query timeout")
+	}
+
+	// Задачи обслуживания задают свой срок, и плагин его не сокращает
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if err := db.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+		t.Fatalf("statement with own deadline: %v", err)
+	}
+}
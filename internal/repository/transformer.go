@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncTransformers - создает трансформаторы по ячейкам типа TRANSFORMER с номером
+// трансформатора, которых еще нет: ячейка стороны НН - сторона нагрузки, ячейка стороны
+// ВН - источник температуры масла. Мощность и напряжения разбираются из строковых полей
+// ячеек ("100 кВА", "0,4 кВ"). ruID ограничивает синхронизацию одним РУ; пустой - все РУ.
+func SyncTransformers(db *gorm.DB, ruID string) error {
+	cellsQuery := db.Where("type = ? AND transformer_number IS NOT NULL AND transformer_number <> ''", models.CellTypeTransformer)
+	existingQuery := db.Model(&models.Transformer{})
+	if ruID != "" {
+		cellsQuery = cellsQuery.Where("ru_id = ?", ruID)
+		existingQuery = existingQuery.Where("ru_id = ?", ruID)
+	}
+	var cells []models.Cell
+	if err := cellsQuery.Order("id ASC").Find(&cells).Error; err != nil {
+		return fmt.Errorf("failed to get transformer cells: %w", err)
+	}
+	if len(cells) == 0 {
+		return nil
+	}
+	var existing []models.Transformer
+	if err := existingQuery.Select("ru_id, name").Find(&existing).Error; err != nil {
+		return fmt.Errorf("failed to get transformers: %w", err)
+	}
+
+	type transformerKey struct{ ruID, name string }
+	known := make(map[transformerKey]bool, len(existing))
+	for _, transformer := range existing {
+		known[transformerKey{transformer.RuID, transformer.Name}] = true
+	}
+
+	now := time.Now()
+	index := map[transformerKey]int{}
+	var created []models.Transformer
+	for _, cell := range cells {
+		key := transformerKey{cell.RuID, strings.TrimSpace(*cell.TransformerNumber)}
+		if known[key] {
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(created)
+			index[key] = i
+			created = append(created, models.Transformer{
+				ID:        utils.NewID(models.IDPrefixTransformer),
+				RuID:      key.ruID,
+				Name:      key.name,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+		transformer := &created[i]
+
+		cellID := cell.ID
+		kv, _ := utils.ParseKilovolts(cell.Voltage)
+		if cell.VoltageLevel == "LOW" {
+			if transformer.LowCellID == nil {
+				transformer.LowCellID, transformer.LowVoltageKV = &cellID, kv
+			}
+		} else if transformer.HighCellID == nil {
+			transformer.HighCellID, transformer.HighVoltageKV = &cellID, kv
+			transformer.OilTempCellID = &cellID
+		}
+		if transformer.RatedPowerKVA == 0 && cell.Power != nil {
+			if kva, ok := utils.ParseKVA(*cell.Power); ok {
+				transformer.RatedPowerKVA = kva
+			}
+		}
+	}
+	if len(created) == 0 {
+		return nil
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+		return fmt.Errorf("failed to sync transformers: %w", err)
+	}
+	return nil
+}
+
+type TransformerRepository struct {
+	db *gorm.DB
+}
+
+func NewTransformerRepository(db *gorm.DB) *TransformerRepository {
+	return &TransformerRepository{db: db}
+}
+
+// GetByRuID - трансформаторы РУ по названию
+func (r *TransformerRepository) GetByRuID(ruID string) ([]models.Transformer, error) {
+	if err := SyncTransformers(r.db, ruID); err != nil {
+		return nil, err
+	}
+	var transformers []models.Transformer
+	if err := r.db.Where("ru_id = ?", ruID).Order("name ASC").Find(&transformers).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transformers: %w", err)
+	}
+	return transformers, nil
+}
+
+func (r *TransformerRepository) GetByID(ruID, transformerID string) (*models.Transformer, error) {
+	var transformer models.Transformer
+	if err := r.db.Where("id = ? AND ru_id = ?", transformerID, ruID).First(&transformer).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transformer: %w", err)
+	}
+	return &transformer, nil
+}
+
+func (r *TransformerRepository) Save(transformer *models.Transformer) error {
+	if err := r.db.Save(transformer).Error; err != nil {
+		return fmt.Errorf("failed to save transformer: %w", err)
+	}
+	return nil
+}
+
+// GetByTapCell - трансформаторы РУ, положение переключателя которых передается по ячейке
+func (r *TransformerRepository) GetByTapCell(ruID string, cellID int) ([]models.Transformer, error) {
+	var transformers []models.Transformer
+	err := r.db.Where("ru_id = ? AND (tap_cell_id = ? OR (tap_cell_id IS NULL AND high_cell_id = ?))", ruID, cellID, cellID).
+		Order("name ASC").Find(&transformers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transformers: %w", err)
+	}
+	return transformers, nil
+}
+
+// SaveTapChange - сохраняет трансформатор с новым положением, запись журнала
+// переключений и события в одной транзакции
+func (r *TransformerRepository) SaveTapChange(transformer *models.Transformer, change *models.TapChange, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(transformer).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save tap change: %w", err)
+	}
+	return nil
+}
+
+// CountTapChanges - число переключений трансформатора за [from, to)
+func (r *TransformerRepository) CountTapChanges(transformerID string, from, to time.Time) (int, error) {
+	var count int64
+	err := r.db.Model(&models.TapChange{}).
+		Where("transformer_id = ? AND changed_at >= ? AND changed_at < ?", transformerID, from, to).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tap changes: %w", err)
+	}
+	return int(count), nil
+}
+
+// GetTapChanges - журнал переключений трансформатора, новые сверху
+func (r *TransformerRepository) GetTapChanges(transformerID string, filter models.TapChangeFilter) ([]models.TapChange, error) {
+	var changes []models.TapChange
+	query := r.db.Where("transformer_id = ?", transformerID)
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.From != nil {
+		query = query.Where("changed_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("changed_at < ?", *filter.To)
+	}
+	if err := query.Order("changed_at DESC, id DESC").Find(&changes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tap changes: %w", err)
+	}
+	return changes, nil
+}
@@ -25,6 +25,13 @@ func (r *UserRepository) Create(user *models.User) error {
 		user.ID = uuid.New().String()
 	}
 
+	if user.OrganizationID == "" {
+		user.OrganizationID = models.DefaultOrganizationID
+	}
+	if user.Status == "" {
+		user.Status = models.UserActive
+	}
+
 	// Устанавливаем временные метки
 	now := time.Now()
 	if user.CreatedAt.IsZero() {
@@ -102,15 +109,98 @@ func (r *UserRepository) GetAll() ([]*models.User, error) {
 	return users, nil
 }
 
+// GetByOrganization - пользователи организации
+func (r *UserRepository) GetByOrganization(organizationID string) ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("organization_id = ?", organizationID).Order("created_at DESC").Find(&users)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get organization users: %w", result.Error)
+	}
+	return users, nil
+}
+
+// GetActive - активные пользователи (без ожидающих одобрения и отклоненных регистраций)
+func (r *UserRepository) GetActive() ([]*models.User, error) {
+	var users []*models.User
+	result := r.db.Where("status = ?", models.UserActive).Order("created_at DESC").Find(&users)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get active users: %w", result.Error)
+	}
+	return users, nil
+}
+
+// GetUsersByRole - активные пользователи роли
 func (r *UserRepository) GetUsersByRole(role string) ([]*models.User, error) {
 	var users []*models.User
-	result := r.db.Where("role = ?", role).Order("created_at DESC").Find(&users)
+	result := r.db.Where("role = ? AND status = ?", role, models.UserActive).Order("created_at DESC").Find(&users)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get users by role: %w", result.Error)
 	}
 	return users, nil
 }
 
+// GetPending - регистрации, ожидающие подтверждения почты или одобрения; пустая
+// организация - все организации
+func (r *UserRepository) GetPending(organizationID string) ([]*models.User, error) {
+	var users []*models.User
+	query := r.db.Where("status IN ?", []models.UserStatus{models.UserPendingVerification, models.UserPendingApproval})
+	if organizationID != "" {
+		query = query.Where("organization_id = ?", organizationID)
+	}
+	if result := query.Order("created_at ASC").Find(&users); result.Error != nil {
+		return nil, fmt.Errorf("failed to get pending users: %w", result.Error)
+	}
+	return users, nil
+}
+
+// CreateVerification - сохраняет ключ подтверждения адреса
+func (r *UserRepository) CreateVerification(verification *models.EmailVerification) error {
+	if err := r.db.Create(verification).Error; err != nil {
+		return fmt.Errorf("failed to create email verification: %w", err)
+	}
+	return nil
+}
+
+// FindVerification - ключ подтверждения по хешу; nil, если ключа нет
+func (r *UserRepository) FindVerification(tokenHash string) (*models.EmailVerification, error) {
+	var verification models.EmailVerification
+	result := r.db.Where("token_hash = ?", tokenHash).First(&verification)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find email verification: %w", result.Error)
+	}
+	return &verification, nil
+}
+
+// LastVerificationAt - время последнего письма с подтверждением пользователю
+func (r *UserRepository) LastVerificationAt(userID string) (*time.Time, error) {
+	var verification models.EmailVerification
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").First(&verification)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find email verification: %w", result.Error)
+	}
+	return &verification.CreatedAt, nil
+}
+
+// VerifyEmail - адрес подтвержден: пользователь переходит в очередь одобрения,
+// все его ключи подтверждения удаляются
+func (r *UserRepository) VerifyEmail(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.EmailVerification{}).Error; err != nil {
+			return fmt.Errorf("failed to delete email verifications: %w", err)
+		}
+		return nil
+	})
+}
+
 func (r *UserRepository) Count() (int64, error) {
 	var count int64
 	result := r.db.Model(&models.User{}).Count(&count)
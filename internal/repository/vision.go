@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type VisionRepository struct {
+	db *gorm.DB
+}
+
+func NewVisionRepository(db *gorm.DB) *VisionRepository {
+	return &VisionRepository{db: db}
+}
+
+// Create - показания и измерения телеметрии из принятых показаний в одной транзакции;
+// measurements[i] соответствует readings[accepted[i]], ссылка на измерение проставляется в показание
+func (r *VisionRepository) Create(readings []models.VisionReading, measurements []models.Measurement, accepted []int) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if len(measurements) > 0 {
+			if err := tx.CreateInBatches(measurements, 500).Error; err != nil {
+				return err
+			}
+			for i, index := range accepted {
+				readings[index].MeasurementID = &measurements[i].ID
+			}
+		}
+		return tx.CreateInBatches(readings, 500).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create vision readings: %w", err)
+	}
+	return nil
+}
+
+// GetByExternalIDs - ранее принятые показания по идентификаторам распознавания
+func (r *VisionRepository) GetByExternalIDs(externalIDs []string) ([]models.VisionReading, error) {
+	var readings []models.VisionReading
+	if len(externalIDs) == 0 {
+		return readings, nil
+	}
+	if err := r.db.Where("external_id IN ?", externalIDs).Find(&readings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get vision readings: %w", err)
+	}
+	return readings, nil
+}
+
+// GetByCell - распознанные показания ячейки, новые сверху
+func (r *VisionRepository) GetByCell(cellID int, filter models.VisionReadingFilter, limit int) ([]models.VisionReading, error) {
+	var readings []models.VisionReading
+	query := r.db.Where("cell_id = ?", cellID)
+	if filter.Metric != "" {
+		query = query.Where("metric = ?", filter.Metric)
+	}
+	if filter.Accepted != nil {
+		query = query.Where("accepted = ?", *filter.Accepted)
+	}
+	if filter.From != nil {
+		query = query.Where("captured_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("captured_at < ?", *filter.To)
+	}
+	if err := query.Order("captured_at DESC").Limit(limit).Find(&readings).Error; err != nil {
+		return nil, fmt.Errorf("failed to get vision readings: %w", err)
+	}
+	return readings, nil
+}
+
+// CreateIndications - пакет распознанных индикаций
+func (r *VisionRepository) CreateIndications(indications []models.VisionIndication) error {
+	if err := r.db.CreateInBatches(indications, 500).Error; err != nil {
+		return fmt.Errorf("failed to create vision indications: %w", err)
+	}
+	return nil
+}
+
+// GetIndicationsByExternalIDs - ранее принятые индикации по идентификаторам распознавания
+func (r *VisionRepository) GetIndicationsByExternalIDs(externalIDs []string) ([]models.VisionIndication, error) {
+	var indications []models.VisionIndication
+	if len(externalIDs) == 0 {
+		return indications, nil
+	}
+	if err := r.db.Where("external_id IN ?", externalIDs).Find(&indications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get vision indications: %w", err)
+	}
+	return indications, nil
+}
+
+// GetUnreconciledIndications - принятые индикации, снятые до before и еще не сверенные,
+// в порядке съемки
+func (r *VisionRepository) GetUnreconciledIndications(before time.Time, limit int) ([]models.VisionIndication, error) {
+	var indications []models.VisionIndication
+	err := r.db.Where("accepted = ? AND reconciled_at IS NULL AND captured_at < ?", true, before).
+		Order("captured_at, id").Limit(limit).Find(&indications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unreconciled indications: %w", err)
+	}
+	return indications, nil
+}
+
+// GetLastCompared - последняя сверенная индикация с результатом match или mismatch
+// по каждой ячейке: от нее зависит, новое ли это расхождение
+func (r *VisionRepository) GetLastCompared(cellIDs []int) ([]models.VisionIndication, error) {
+	var indications []models.VisionIndication
+	if len(cellIDs) == 0 {
+		return indications, nil
+	}
+	compared := []models.IndicationResult{models.IndicationMatch, models.IndicationMismatch}
+	latest := r.db.Model(&models.VisionIndication{}).
+		Select("cell_id, MAX(captured_at) AS captured_at").
+		Where("cell_id IN ? AND result IN ?", cellIDs, compared).
+		Group("cell_id")
+	err := r.db.Joins("JOIN (?) AS latest ON latest.cell_id = vision_indications.cell_id AND latest.captured_at = vision_indications.captured_at", latest).
+		Where("vision_indications.result IN ?", compared).
+		Order("vision_indications.cell_id, vision_indications.id").Find(&indications).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compared indications: %w", err)
+	}
+
+	// Снимки одной ячейки с одинаковым временем съемки: берется один
+	unique := indications[:0]
+	for i, indication := range indications {
+		if i > 0 && indication.CellID == indications[i-1].CellID {
+			continue
+		}
+		unique = append(unique, indication)
+	}
+	return unique, nil
+}
+
+// SaveReconciliation - результаты сверки и события о расхождениях в одной транзакции
+func (r *VisionRepository) SaveReconciliation(indications []models.VisionIndication, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, indication := range indications {
+			err := tx.Model(&models.VisionIndication{}).Where("id = ?", indication.ID).Updates(map[string]interface{}{
+				"result":          indication.Result,
+				"recorded_status": indication.RecordedStatus,
+				"reconciled_at":   indication.ReconciledAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save reconciliation: %w", err)
+	}
+	return nil
+}
+
+// GetIndicationsByCell - индикации ячейки, новые сверху
+func (r *VisionRepository) GetIndicationsByCell(cellID int, filter models.VisionIndicationFilter, limit int) ([]models.VisionIndication, error) {
+	var indications []models.VisionIndication
+	query := r.db.Where("cell_id = ?", cellID)
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if filter.From != nil {
+		query = query.Where("captured_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("captured_at < ?", *filter.To)
+	}
+	if err := query.Order("captured_at DESC").Limit(limit).Find(&indications).Error; err != nil {
+		return nil, fmt.Errorf("failed to get vision indications: %w", err)
+	}
+	return indications, nil
+}
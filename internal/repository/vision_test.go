@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestGetLastCompared(t *testing.T) {
+	db := newTestDB(t, &models.VisionIndication{})
+	repo := NewVisionRepository(db)
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	indications := []models.VisionIndication{
+		{ID: "a1", CellID: 1, CapturedAt: base, Result: models.IndicationMismatch},
+		{ID: "a2", CellID: 1, CapturedAt: base.Add(time.Hour), Result: models.IndicationMatch},
+		// Несверенная и устаревшая индикации новее, но не учитываются
+		{ID: "a3", CellID: 1, CapturedAt: base.Add(2 * time.Hour)},
+		{ID: "a4", CellID: 1, CapturedAt: base.Add(3 * time.Hour), Result: models.IndicationStale},
+		{ID: "b1", CellID: 2, CapturedAt: base, Result: models.IndicationMismatch},
+		// Два снимка с одинаковым временем - в ответе один
+		{ID: "c1", CellID: 3, CapturedAt: base, Result: models.IndicationMatch},
+		{ID: "c2", CellID: 3, CapturedAt: base, Result: models.IndicationMismatch},
+		{ID: "d1", CellID: 4, CapturedAt: base.Add(time.Hour), Result: models.IndicationMatch},
+	}
+	if err := db.Create(&indications).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	got, err := repo.GetLastCompared([]int{1, 2, 3, 5})
+	if err != nil {
+		t.Fatalf("GetLastCompared: %v", err)
+	}
+	want := map[int]string{1: "a2", 2: "b1", 3: "c1"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d indications, want %d: %+v", len(got), len(want), got)
+	}
+	for _, indication := range got {
+		if want[indication.CellID] != indication.ID {
+			t.Errorf("cell %d: got %s, want %s", indication.CellID, indication.ID, want[indication.CellID])
+		}
+	}
+
+	empty, err := repo.GetLastCompared(nil)
+	if err != nil || len(empty) != 0 {
+		t.Errorf("GetLastCompared(nil) = %v, %v; want empty", empty, err)
+	}
+}
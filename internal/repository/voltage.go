@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoltageRepository struct {
+	db *gorm.DB
+}
+
+func NewVoltageRepository(db *gorm.DB) *VoltageRepository {
+	return &VoltageRepository{db: db}
+}
+
+// Save - сохраняет измерения и события об отклонении напряжения в одной транзакции;
+// повтор на тот же момент заменяет значение
+func (r *VoltageRepository) Save(measurements []models.VoltageMeasurement, events []models.OutboxEvent) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cell_id"}, {Name: "measured_at"}},
+			DoUpdates: clause.AssignmentColumns([]string{"phase_a", "phase_b", "phase_c", "out_of_range"}),
+		}).CreateInBatches(measurements, 500).Error
+		if err != nil {
+			return err
+		}
+		return appendOutbox(tx, events)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save voltage measurements: %w", err)
+	}
+	return nil
+}
+
+// GetLast - последнее измерение каждой из ячеек
+func (r *VoltageRepository) GetLast(cellIDs []int) (map[int]models.VoltageMeasurement, error) {
+	last := make(map[int]models.VoltageMeasurement, len(cellIDs))
+	for _, cellID := range cellIDs {
+		var measurement models.VoltageMeasurement
+		err := r.db.Where("cell_id = ?", cellID).Order("measured_at DESC").Limit(1).Find(&measurement).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last voltage measurement: %w", err)
+		}
+		if measurement.CellID != 0 {
+			last[cellID] = measurement
+		}
+	}
+	return last, nil
+}
+
+// voltageStatsRow - агрегаты фаз ячейки за интервал
+type voltageStatsRow struct {
+	CellID  int
+	Samples int
+	MinA    float64
+	MaxA    float64
+	AvgA    float64
+	OutA    int
+	MinB    float64
+	MaxB    float64
+	AvgB    float64
+	OutB    int
+	MinC    float64
+	MaxC    float64
+	AvgC    float64
+	OutC    int
+}
+
+// GetStats - минимум, максимум, среднее и число значений вне [low, high] по фазам ячеек РУ
+// за [from, to); cellID ограничивает выборку одной ячейкой. Фазы в сводке - в порядке A, B, C.
+func (r *VoltageRepository) GetStats(ruID string, cellID *int, from, to time.Time, low, high float64) ([]models.VoltageDailySummary, error) {
+	columns := "cell_id, COUNT(*) AS samples"
+	var args []interface{}
+	for _, phase := range []string{"a", "b", "c"} {
+		column := "phase_" + phase
+		columns += fmt.Sprintf(", MIN(%[1]s) AS min_%[2]s, MAX(%[1]s) AS max_%[2]s, AVG(%[1]s) AS avg_%[2]s, "+
+			"SUM(CASE WHEN %[1]s < ? OR %[1]s > ? THEN 1 ELSE 0 END) AS out_%[2]s", column, phase)
+		args = append(args, low, high)
+	}
+
+	query := r.db.Model(&models.VoltageMeasurement{}).
+		Select(columns, args...).
+		Where("ru_id = ? AND measured_at >= ? AND measured_at < ?", ruID, from, to)
+	if cellID != nil {
+		query = query.Where("cell_id = ?", *cellID)
+	}
+	var rows []voltageStatsRow
+	if err := query.Group("cell_id").Order("cell_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get voltage statistics: %w", err)
+	}
+
+	summaries := make([]models.VoltageDailySummary, len(rows))
+	for i, row := range rows {
+		summaries[i] = models.VoltageDailySummary{
+			RuID:    ruID,
+			CellID:  row.CellID,
+			Samples: row.Samples,
+			Phases: []models.VoltagePhaseSummary{
+				{Phase: "A", Min: row.MinA, Max: row.MaxA, Avg: row.AvgA, OutOfRange: row.OutA},
+				{Phase: "B", Min: row.MinB, Max: row.MaxB, Avg: row.AvgB, OutOfRange: row.OutB},
+				{Phase: "C", Min: row.MinC, Max: row.MaxC, Avg: row.AvgC, OutOfRange: row.OutC},
+			},
+		}
+	}
+	return summaries, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WeatherRepository struct {
+	db *gorm.DB
+}
+
+func NewWeatherRepository(db *gorm.DB) *WeatherRepository {
+	return &WeatherRepository{db: db}
+}
+
+// SaveObservations - сохраняет наблюдения; повтор на тот же момент заменяет значение
+func (r *WeatherRepository) SaveObservations(observations []models.WeatherObservation) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "substation_id"}, {Name: "observed_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"temperature", "humidity", "source"}),
+	}).CreateInBatches(observations, 500).Error
+	if err != nil {
+		return fmt.Errorf("failed to save weather observations: %w", err)
+	}
+	return nil
+}
+
+// GetObservations - наблюдения подстанции за период [from, to)
+func (r *WeatherRepository) GetObservations(substationID string, from, to time.Time) ([]models.WeatherObservation, error) {
+	var observations []models.WeatherObservation
+	result := r.db.Where("substation_id = ? AND observed_at >= ? AND observed_at < ?", substationID, from, to).
+		Order("observed_at ASC").
+		Find(&observations)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get weather observations: %w", result.Error)
+	}
+	return observations, nil
+}
@@ -1,44 +1,97 @@
 package service
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/repository"
 	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
 )
 
+// AdminService - управление пользователями. Администратор установки управляет всеми
+// пользователями, администратор организации - только пользователями своей организации.
 type AdminService struct {
 	userRepo  *repository.UserRepository
+	orgRepo   *repository.OrganizationRepository
+	settings  *SettingsService
+	audit     *AuditService
 	jwtSecret string
 }
 
-func NewAdminService(userRepo *repository.UserRepository, jwtSecret string) *AdminService {
+func NewAdminService(userRepo *repository.UserRepository, orgRepo *repository.OrganizationRepository, settings *SettingsService, audit *AuditService, jwtSecret string) *AdminService {
 	return &AdminService{
 		userRepo:  userRepo,
+		orgRepo:   orgRepo,
+		settings:  settings,
+		audit:     audit,
 		jwtSecret: jwtSecret,
 	}
 }
 
-// Валидация пароля
-func validatePassword(password string) (bool, string) {
-	if len(password) < 6 {
-		return false, "Пароль должен содержать минимум 6 символов"
+var specialCharRegex = regexp.MustCompile(`[!@#$%^&*()_+\-=\[\]{};':"\\|,.<>\/?]`)
+
+// validatePassword - проверка пароля по политике из системных настроек
+func validatePassword(settings *SettingsService, password string) error {
+	if minLength := settings.Int(SettingPasswordMinLength); len(password) < minLength {
+		return ErrPasswordTooShort.WithDetails(map[string]interface{}{"minLength": minLength})
 	}
 
 	// Проверка на наличие специального символа
-	specialCharRegex := regexp.MustCompile(`[!@#$%^&*()_+\-=\[\]{};':"\\|,.<>\/?]`)
-	if !specialCharRegex.MatchString(password) {
-		return false, "Пароль должен содержать хотя бы один специальный символ (!@#$%^&* и т.д.)"
+	if settings.Bool(SettingPasswordRequireSpecial) && !specialCharRegex.MatchString(password) {
+		return ErrPasswordNoSpecial
+	}
+
+	return nil
+}
+
+// scopedUser - пользователь, которым может управлять actor. Пользователи чужой
+// организации не видны, администратора установки изменяет только администратор установки.
+func (s *AdminService) scopedUser(actor models.Actor, userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || !actor.CanAccessOrganization(user.OrganizationID) {
+		return nil, ErrUserNotFound
 	}
+	if user.Role == models.RoleAdmin && !actor.IsPlatformAdmin() {
+		return nil, ErrRoleNotAllowed
+	}
+	return user, nil
+}
 
-	return true, ""
+// assignableRole - роль из запроса; администратор организации не назначает роль
+// администратора установки
+func assignableRole(actor models.Actor, role string) (models.UserRole, error) {
+	var userRole models.UserRole
+	switch role {
+	case "admin":
+		userRole = models.RoleAdmin
+	case "org_admin":
+		userRole = models.RoleOrgAdmin
+	case "dispatcher":
+		userRole = models.RoleDispatcher
+	case "engineer":
+		userRole = models.RoleEngineer
+	default:
+		return "", ErrInvalidRole
+	}
+	if userRole == models.RoleAdmin && !actor.IsPlatformAdmin() {
+		return "", ErrRoleNotAllowed
+	}
+	return userRole, nil
 }
 
-func (s *AdminService) GetAllUsers() ([]models.UserResponse, error) {
-	users, err := s.userRepo.GetAll()
+func (s *AdminService) GetAllUsers(actor models.Actor) ([]models.UserResponse, error) {
+	var users []*models.User
+	var err error
+	if actor.IsPlatformAdmin() {
+		users, err = s.userRepo.GetAll()
+	} else {
+		users, err = s.userRepo.GetByOrganization(actor.OrganizationID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
@@ -46,30 +99,33 @@ func (s *AdminService) GetAllUsers() ([]models.UserResponse, error) {
 	var response []models.UserResponse
 	for _, user := range users {
 		response = append(response, models.UserResponse{
-			ID:        user.ID,
-			Name:      user.Name,
-			Email:     user.Email,
-			Role:      string(user.Role),
-			CreatedAt: user.CreatedAt,
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			Role:           string(user.Role),
+			OrganizationID: user.OrganizationID,
+			Phone:          user.Phone,
+			Status:         user.Status,
+			CreatedAt:      user.CreatedAt,
 		})
 	}
 
 	return response, nil
 }
 
-func (s *AdminService) CreateUser(req *models.AdminCreateRequest) (*models.UserResponse, error) {
+func (s *AdminService) CreateUser(actor models.Actor, req *models.AdminCreateRequest) (*models.UserResponse, error) {
 	// Проверяем, существует ли пользователь с таким email
 	exists, err := s.userRepo.ExistsByEmail(req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check email: %w", err)
 	}
 	if exists {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrUserExists
 	}
 
 	// Валидация пароля
-	if valid, message := validatePassword(req.Password); !valid {
-		return nil, errors.New(message)
+	if err := validatePassword(s.settings, req.Password); err != nil {
+		return nil, err
 	}
 
 	// Хешируем пароль
@@ -79,24 +135,31 @@ func (s *AdminService) CreateUser(req *models.AdminCreateRequest) (*models.UserR
 	}
 
 	// Преобразуем строку роли в UserRole
-	var userRole models.UserRole
-	switch req.Role {
-	case "admin":
-		userRole = models.RoleAdmin
-	case "dispatcher":
-		userRole = models.RoleDispatcher
-	case "engineer":
-		userRole = models.RoleEngineer
-	default:
-		return nil, errors.New("invalid role")
+	userRole, err := assignableRole(actor, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Администратор организации создает пользователей только своей организации
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() && req.OrganizationID != "" {
+		if _, err := s.orgRepo.GetByID(req.OrganizationID); err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrOrganizationNotFound
+			}
+			return nil, fmt.Errorf("failed to get organization: %w", err)
+		}
+		organizationID = req.OrganizationID
 	}
 
 	// Создаем пользователя
 	user := &models.User{
-		Name:         req.Name,
-		Email:        req.Email,
-		PasswordHash: passwordHash,
-		Role:         userRole,
+		Name:           req.Name,
+		Email:          req.Email,
+		PasswordHash:   passwordHash,
+		Role:           userRole,
+		OrganizationID: organizationID,
+		Phone:          req.Phone,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
@@ -104,22 +167,22 @@ func (s *AdminService) CreateUser(req *models.AdminCreateRequest) (*models.UserR
 	}
 
 	return &models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		OrganizationID: user.OrganizationID,
+		Phone:          user.Phone,
+		Status:         user.Status,
+		CreatedAt:      user.CreatedAt,
 	}, nil
 }
 
-func (s *AdminService) UpdateUser(userID string, req *models.AdminUpdateRequest) (*models.UserResponse, error) {
+func (s *AdminService) UpdateUser(actor models.Actor, userID string, req *models.AdminUpdateRequest) (*models.UserResponse, error) {
 	// Находим пользователя
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.scopedUser(actor, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find user: %w", err)
-	}
-	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, err
 	}
 
 	// Проверяем email на уникальность (если email изменился)
@@ -129,27 +192,21 @@ func (s *AdminService) UpdateUser(userID string, req *models.AdminUpdateRequest)
 			return nil, fmt.Errorf("failed to check email: %w", err)
 		}
 		if exists {
-			return nil, errors.New("email already taken by another user")
+			return nil, ErrEmailTaken
 		}
 	}
 
 	// Преобразуем строку роли в UserRole
-	var userRole models.UserRole
-	switch req.Role {
-	case "admin":
-		userRole = models.RoleAdmin
-	case "dispatcher":
-		userRole = models.RoleDispatcher
-	case "engineer":
-		userRole = models.RoleEngineer
-	default:
-		return nil, errors.New("invalid role")
+	userRole, err := assignableRole(actor, req.Role)
+	if err != nil {
+		return nil, err
 	}
 
 	// Обновляем данные
 	user.Name = req.Name
 	user.Email = req.Email
 	user.Role = userRole
+	user.Phone = req.Phone
 
 	// Сохраняем изменения
 	if err := s.userRepo.Update(user); err != nil {
@@ -157,22 +214,21 @@ func (s *AdminService) UpdateUser(userID string, req *models.AdminUpdateRequest)
 	}
 
 	return &models.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		OrganizationID: user.OrganizationID,
+		Phone:          user.Phone,
+		Status:         user.Status,
+		CreatedAt:      user.CreatedAt,
 	}, nil
 }
 
-func (s *AdminService) DeleteUser(userID string) error {
+func (s *AdminService) DeleteUser(actor models.Actor, userID string) error {
 	// Находим пользователя
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
-	}
-	if user == nil {
-		return errors.New("user not found")
+	if _, err := s.scopedUser(actor, userID); err != nil {
+		return err
 	}
 
 	// Удаляем пользователя
@@ -183,19 +239,16 @@ func (s *AdminService) DeleteUser(userID string) error {
 	return nil
 }
 
-func (s *AdminService) ChangeUserPassword(userID string, req *models.AdminChangePasswordRequest) error {
+func (s *AdminService) ChangeUserPassword(actor models.Actor, userID string, req *models.AdminChangePasswordRequest) error {
 	// Находим пользователя
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.scopedUser(actor, userID)
 	if err != nil {
-		return fmt.Errorf("failed to find user: %w", err)
-	}
-	if user == nil {
-		return errors.New("user not found")
+		return err
 	}
 
 	// Валидация пароля
-	if valid, message := validatePassword(req.NewPassword); !valid {
-		return errors.New(message)
+	if err := validatePassword(s.settings, req.NewPassword); err != nil {
+		return err
 	}
 
 	// Хешируем новый пароль
@@ -214,3 +267,94 @@ func (s *AdminService) ChangeUserPassword(userID string, req *models.AdminChange
 
 	return nil
 }
+
+// Impersonate - короткоживущий токен администратора от имени пользователя для
+// воспроизведения проблем с правами. Работать можно только от имени активной учетной
+// записи. Токен помечен администратором и текстом баннера на языке запроса, выдача
+// фиксируется в журнале аудита вместе с причиной.
+func (s *AdminService) Impersonate(actor models.Actor, userID string, req *models.ImpersonateRequest, audit models.AuditEntry, loc i18n.Lang) (*models.ImpersonationResponse, error) {
+	if actor.ImpersonatorID != "" {
+		return nil, ErrImpersonationNested
+	}
+	if userID == actor.UserID {
+		return nil, ErrImpersonationNotAllowed
+	}
+
+	user, err := s.scopedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == models.RoleAdmin {
+		return nil, ErrImpersonationNotAllowed
+	}
+	// Учетная запись, которая не может войти сама, недоступна и от ее имени
+	if inactiveAccountError(user.Status) != nil {
+		return nil, ErrImpersonationNotAllowed.WithDetails(map[string]interface{}{"status": user.Status})
+	}
+
+	admin, err := s.userRepo.FindByID(actor.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if admin == nil {
+		return nil, ErrUserNotFound
+	}
+
+	banner := i18n.T(loc, "impersonation.banner", admin.Email, user.Name, user.Email)
+	token, expiresAt, err := utils.GenerateImpersonationToken(user, admin, banner, s.jwtSecret, s.settings.Duration(SettingImpersonationTTL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	audit.Action = models.AuditActionImpersonationStart
+	audit.UserID = user.ID
+	audit.UserEmail = user.Email
+	audit.ImpersonatorID = &admin.ID
+	audit.ImpersonatorEmail = &admin.Email
+	audit.Details = req.Reason
+	s.audit.Record(audit)
+
+	return &models.ImpersonationResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		Banner:    banner,
+		User: models.UserResponse{
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			Role:           string(user.Role),
+			OrganizationID: user.OrganizationID,
+			Phone:          user.Phone,
+			Status:         user.Status,
+			CreatedAt:      user.CreatedAt,
+		},
+	}, nil
+}
+
+// GetUserActivity - входы пользователя и его последние изменения для разбора инцидентов
+func (s *AdminService) GetUserActivity(actor models.Actor, userID string, limit int) (*models.UserActivity, error) {
+	user, err := s.scopedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	logins, actions, err := s.audit.GetUserActivity(userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user activity: %w", err)
+	}
+
+	return &models.UserActivity{
+		User: models.UserResponse{
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			Role:           string(user.Role),
+			OrganizationID: user.OrganizationID,
+			Phone:          user.Phone,
+			Status:         user.Status,
+			CreatedAt:      user.CreatedAt,
+		},
+		Logins:  logins,
+		Actions: actions,
+	}, nil
+}
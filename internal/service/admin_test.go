@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+func TestImpersonateInactiveAccount(t *testing.T) {
+	// От имени учетной записи, которая не может войти сама, работать нельзя
+	statuses := []models.UserStatus{models.UserPendingVerification, models.UserPendingApproval, models.UserRejected}
+	for _, status := range statuses {
+		t.Run(string(status), func(t *testing.T) {
+			db := newTestDB(t, &models.User{})
+			user := models.User{ID: "usr-1", Name: "User", Email: "user@example.com", Role: models.RoleDispatcher,
+				OrganizationID: models.DefaultOrganizationID, Status: status}
+			if err := db.Create(&user).Error; err != nil {
+				t.Fatalf("seed user: %v", err)
+			}
+			service := &AdminService{userRepo: repository.NewUserRepository(db)}
+
+			actor := models.Actor{UserID: "usr-admin", Email: "admin@example.com", Role: models.RoleAdmin}
+			_, err := service.Impersonate(actor, "usr-1", &models.ImpersonateRequest{Reason: "check"}, models.AuditEntry{}, i18n.Default)
+			if !errors.Is(err, ErrImpersonationNotAllowed) {
+				t.Errorf("Impersonate error = %v, want %v", err, ErrImpersonationNotAllowed)
+			}
+		})
+	}
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	alarmsDefaultLimit = 200
+	alarmsMaxLimit     = 1000
+)
+
+type AlarmService struct {
+	alarmRepo     *repository.AlarmRepository
+	userRepo      *repository.UserRepository
+	ruRepo        *repository.RuRepository
+	settings      *SettingsService
+	notifications *NotificationService
+	audit         *AuditService
+}
+
+func NewAlarmService(alarmRepo *repository.AlarmRepository, userRepo *repository.UserRepository, settings *SettingsService, notifications *NotificationService, audit *AuditService, ruRepo *repository.RuRepository) *AlarmService {
+	return &AlarmService{
+		alarmRepo:     alarmRepo,
+		userRepo:      userRepo,
+		ruRepo:        ruRepo,
+		settings:      settings,
+		notifications: notifications,
+		audit:         audit,
+	}
+}
+
+// HandleEvent - подписчик шины событий: регистрирует аварию по событиям alarm.raised,
+// fault.recorded, device.offline, inventory.stock_low, telemetry.anomaly, capacity.overload
+// vision.discrepancy, thermal.hotspot, transformer.tap_excessive и voltage.deviation
+func (s *AlarmService) HandleEvent(event *models.OutboxEvent) error {
+	switch event.Type {
+	case models.EventStockLow:
+		return s.raiseStockLow(event)
+	case models.EventFaultRecorded:
+		return s.raiseFault(event)
+	case models.EventDeviceOffline:
+		return s.raiseDeviceOffline(event)
+	case models.EventTelemetryAnomaly:
+		return s.raiseAnomaly(event)
+	case models.EventCapacityOverload:
+		return s.raiseCapacityOverload(event)
+	case models.EventVisionDiscrepancy:
+		return s.raiseDiscrepancy(event)
+	case models.EventThermalHotspot:
+		return s.raiseThermalHotspot(event)
+	case models.EventTapExcessive:
+		return s.raiseTapExcessive(event)
+	case models.EventVoltageDeviation:
+		return s.raiseVoltageDeviation(event)
+	case models.EventAlarmRaised:
+	default:
+		return nil
+	}
+
+	var payload models.AlarmRaisedPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	statusName := i18n.T(i18n.Default, "status.cell."+string(payload.Status))
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindCellStatus,
+		Severity:   models.AlarmSeverityCritical,
+		Status:     models.AlarmStatusActive,
+		Message:    i18n.T(i18n.Default, "alarm.cell_status.message", payload.CellNumber, payload.CellName, statusName),
+		EventID:    event.ID,
+		RaisedAt:   event.CreatedAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseFault - авария по срабатыванию защиты; успешное АПВ понижает ее до предупреждения
+func (s *AlarmService) raiseFault(event *models.OutboxEvent) error {
+	var payload models.FaultRecordedPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	severity := models.AlarmSeverityCritical
+	if payload.AutoReclose == models.AutoRecloseSuccessful {
+		severity = models.AlarmSeverityWarning
+	}
+	current := "-"
+	if payload.FaultCurrent != nil {
+		current = fmt.Sprintf("%.0f", *payload.FaultCurrent)
+	}
+
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindFault,
+		Severity:   severity,
+		Status:     models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.fault.message", payload.CellNumber,
+			i18n.T(i18n.Default, "fault.trip."+string(payload.TripType)), current,
+			i18n.T(i18n.Default, "fault.reclose."+string(payload.AutoReclose))),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseDeviceOffline - потеря связи с устройством; без RTU или шлюза РУ остается без телеметрии
+func (s *AlarmService) raiseDeviceOffline(event *models.OutboxEvent) error {
+	var payload models.DeviceOfflinePayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	severity := models.AlarmSeverityWarning
+	if payload.Type == models.DeviceRTU || payload.Type == models.DeviceGateway {
+		severity = models.AlarmSeverityCritical
+	}
+	lastContact := "-"
+	if payload.LastContactAt != nil {
+		lastContact = payload.LastContactAt.Format(utils.LegacyDateTimeLayout)
+	}
+
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:        utils.NewID(models.IDPrefixAlarm),
+		RuID:      event.RuID,
+		Kind:      models.AlarmKindDeviceOffline,
+		Severity:  severity,
+		Status:    models.AlarmStatusActive,
+		Message:   i18n.T(i18n.Default, "alarm.device_offline.message", payload.Name, payload.Host, lastContact),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseAnomaly - предупреждение о необычных показаниях ячейки, еще не достигших уставок
+func (s *AlarmService) raiseAnomaly(event *models.OutboxEvent) error {
+	var payload models.TelemetryAnomalyPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindAnomaly,
+		Severity:   models.AlarmSeverityWarning,
+		Status:     models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.anomaly.message", payload.CellNumber,
+			i18n.T(i18n.Default, "metric."+string(payload.Metric)), payload.Value, payload.Mean, payload.StdDev, payload.Score),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseCapacityOverload - загрузка секции шин выше порога warning/critical из настроек
+func (s *AlarmService) raiseCapacityOverload(event *models.OutboxEvent) error {
+	var payload models.CapacityOverloadPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	severity := models.AlarmSeverityWarning
+	if payload.Band == models.UtilizationCritical {
+		severity = models.AlarmSeverityCritical
+	}
+
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:       utils.NewID(models.IDPrefixAlarm),
+		RuID:     event.RuID,
+		Kind:     models.AlarmKindCapacity,
+		Severity: severity,
+		Status:   models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.capacity.message", payload.RuName,
+			i18n.T(i18n.Default, "capacity.side."+string(payload.VoltageLevel)), payload.Section, payload.Percent, payload.CurrentA, payload.CapacityA),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseDiscrepancy - журнал расходится с положением ячейки на панели по снимку;
+// в сообщении оба значения, чтобы диспетчер знал, что проверить на месте
+func (s *AlarmService) raiseDiscrepancy(event *models.OutboxEvent) error {
+	var payload models.VisionDiscrepancyPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindDiscrepancy,
+		Severity:   models.AlarmSeverityWarning,
+		Status:     models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.discrepancy.message", payload.CellNumber, payload.CellName,
+			i18n.T(i18n.Default, "status.cell."+string(payload.Observed)),
+			i18n.T(i18n.Default, "status.cell."+string(payload.Recorded)),
+			payload.CapturedAt.Format(utils.LegacyDateTimeLayout)),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseThermalHotspot - перегрев контакта по термограмме; превышение над соседней фазой
+// указывается, если оно измерено
+func (s *AlarmService) raiseThermalHotspot(event *models.OutboxEvent) error {
+	var payload models.ThermalHotspotPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	severity := models.AlarmSeverityWarning
+	if payload.Band == models.ThermalCritical {
+		severity = models.AlarmSeverityCritical
+	}
+	phaseDelta := "-"
+	if payload.DeltaReference != nil {
+		phaseDelta = fmt.Sprintf("%.1f", *payload.DeltaReference)
+	}
+
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindThermal,
+		Severity:   severity,
+		Status:     models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.thermal.message", payload.CellNumber, payload.Point,
+			payload.Temperature, payload.DeltaAmbient, phaseDelta),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseTapExcessive - предупреждение о частых переключениях ответвлений: износ контактов
+// РПН и признак нестабильного напряжения на стороне НН
+func (s *AlarmService) raiseTapExcessive(event *models.OutboxEvent) error {
+	var payload models.TapExcessivePayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:        utils.NewID(models.IDPrefixAlarm),
+		RuID:      event.RuID,
+		CellID:    payload.CellID,
+		Kind:      models.AlarmKindTapChanger,
+		Severity:  models.AlarmSeverityWarning,
+		Status:    models.AlarmStatusActive,
+		Message:   i18n.T(i18n.Default, "alarm.tap_excessive.message", payload.TransformerName, payload.Operations, payload.Day, payload.Limit),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseVoltageDeviation - напряжение стороны НН вне ±10 % номинала; отклонение от 20 %
+// (в том числе потеря фазы) - критическая авария
+func (s *AlarmService) raiseVoltageDeviation(event *models.OutboxEvent) error {
+	var payload models.VoltageDeviationPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	severity := models.AlarmSeverityWarning
+	if math.Abs(payload.DeviationPercent) >= voltageCriticalPercent {
+		severity = models.AlarmSeverityCritical
+	}
+
+	cellID := payload.CellID
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:         utils.NewID(models.IDPrefixAlarm),
+		RuID:       event.RuID,
+		CellID:     &cellID,
+		CellNumber: payload.CellNumber,
+		Kind:       models.AlarmKindVoltage,
+		Severity:   severity,
+		Status:     models.AlarmStatusActive,
+		Message: i18n.T(i18n.Default, "alarm.voltage.message", payload.CellNumber, payload.Phase,
+			payload.Voltage, payload.DeviationPercent),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// raiseStockLow - предупреждение о падении остатка запчастей ниже минимума
+func (s *AlarmService) raiseStockLow(event *models.OutboxEvent) error {
+	var payload models.StockLowPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	alarm := &models.Alarm{
+		ID:        utils.NewID(models.IDPrefixAlarm),
+		Kind:      models.AlarmKindStockLow,
+		Severity:  models.AlarmSeverityWarning,
+		Status:    models.AlarmStatusActive,
+		Message:   i18n.T(i18n.Default, "alarm.stock_low.message", payload.ItemName, payload.WarehouseName, payload.Available, payload.MinQuantity),
+		EventID:   event.ID,
+		RaisedAt:  event.CreatedAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.createAlarm(alarm)
+}
+
+// createAlarm - дополняет аварию приоритетом, звуковым сигналом и рекомендуемым
+// действием для пульта диспетчера и сохраняет ее вместе с событием alarm.created.
+// Авария по ячейке под открытым нарядом-допуском сразу создается подавленной.
+func (s *AlarmService) createAlarm(alarm *models.Alarm) error {
+	alarm.Priority, alarm.AudibleClass = models.AlarmAnnunciation(alarm.Kind, alarm.Severity)
+	alarm.RecommendedAction = recommendedAction(alarm.Kind, alarm.Severity)
+	if err := s.suppressUnderPermit(alarm); err != nil {
+		return err
+	}
+	event, err := newEvent(models.EventAlarmCreated, alarm.RuID, alarm)
+	if err != nil {
+		return err
+	}
+	created, err := s.alarmRepo.CreateAlarm(alarm, event)
+	if err != nil {
+		return err
+	}
+	if created && alarm.SuppressedBy != nil {
+		s.recordSystemAudit(models.AuditActionAlarmSuppress,
+			fmt.Sprintf("alarm %s suppressed: cell %s is under work permit %s", alarm.ID, alarm.CellNumber, *alarm.SuppressedWorkOrder))
+	}
+	return nil
+}
+
+// recommendedAction - действие диспетчера по аварии; текст для важности
+// (alarm.action.<kind>.<severity>) уточняет общий текст источника
+func recommendedAction(kind models.AlarmKind, severity models.AlarmSeverity) string {
+	if action, ok := i18n.Lookup(i18n.Default, "alarm.action."+string(kind)+"."+string(severity)); ok {
+		return action
+	}
+	return i18n.T(i18n.Default, "alarm.action."+string(kind))
+}
+
+// GetAlarms - аварии РУ организации пользователя; администратор установки видит все
+func (s *AlarmService) GetAlarms(actor models.Actor, filter models.AlarmFilter, limit int) ([]models.Alarm, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	if limit <= 0 {
+		limit = alarmsDefaultLimit
+	}
+	if limit > alarmsMaxLimit {
+		limit = alarmsMaxLimit
+	}
+
+	alarms, err := s.alarmRepo.GetAlarms(filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alarms: %w", err)
+	}
+	if err := s.attachEscalations(alarms); err != nil {
+		return nil, fmt.Errorf("failed to get alarm escalations: %w", err)
+	}
+	// Аварии, зарегистрированные до появления рекомендаций
+	for i := range alarms {
+		if alarms[i].RecommendedAction == "" {
+			alarms[i].RecommendedAction = recommendedAction(alarms[i].Kind, alarms[i].Severity)
+		}
+	}
+	return alarms, nil
+}
+
+// Acknowledge - массовое квитирование активных аварий. Пустой фильтр без списка
+// идентификаторов не допускается, чтобы случайно не квитировать все аварии. Квитируются
+// только аварии РУ организации пользователя, в том числе перечисленные явно.
+func (s *AlarmService) Acknowledge(actor models.Actor, req *models.AckAlarmsRequest) (int64, error) {
+	if req.Filter.IsEmpty() && len(req.IDs) == 0 {
+		return 0, ErrAlarmFilterEmpty
+	}
+	filter := req.Filter
+	filter.OrganizationID = actor.OrganizationScope()
+
+	ids := make([]string, len(req.IDs))
+	for i, id := range req.IDs {
+		ids[i] = utils.NormalizeID(models.IDPrefixAlarm, id)
+	}
+
+	count, err := s.alarmRepo.AcknowledgeAlarms(filter, ids, actor.Email, req.Comment, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to acknowledge alarms: %w", err)
+	}
+	return count, nil
+}
+
+func (s *AlarmService) GetSavedFilters(userID string) ([]models.SavedAlarmFilter, error) {
+	filters, err := s.alarmRepo.GetSavedFilters(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved filters: %w", err)
+	}
+	return filters, nil
+}
+
+func (s *AlarmService) CreateSavedFilter(userID string, req *models.CreateSavedAlarmFilterRequest) (*models.SavedAlarmFilter, error) {
+	now := time.Now()
+	filter := &models.SavedAlarmFilter{
+		ID:        utils.NewID(models.IDPrefixAlarmFilter),
+		UserID:    userID,
+		Name:      req.Name,
+		RuID:      req.RuID,
+		Severity:  req.Severity,
+		Status:    req.Status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.alarmRepo.CreateSavedFilter(filter); err != nil {
+		return nil, fmt.Errorf("failed to create saved filter: %w", err)
+	}
+	return filter, nil
+}
+
+func (s *AlarmService) DeleteSavedFilter(userID, filterID string) error {
+	deleted, err := s.alarmRepo.DeleteSavedFilter(userID, utils.NormalizeID(models.IDPrefixAlarmFilter, filterID))
+	if err != nil {
+		return fmt.Errorf("failed to delete saved filter: %w", err)
+	}
+	if !deleted {
+		return ErrAlarmFilterNotFound
+	}
+	return nil
+}
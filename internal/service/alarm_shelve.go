@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// alarmSystemActor - автор записей аудита, сделанных планировщиком
+const alarmSystemActor = "system"
+
+// Shelve - диспетчер откладывает мешающую активную аварию не дольше alarms.shelve_max.
+// Отложенная авария не показывается в рабочем списке, не эскалируется и не рассылается
+// по SMS, а по истечении срока возвращается автоматически.
+func (s *AlarmService) Shelve(actor models.Actor, alarmID string, req *models.ShelveAlarmRequest, audit models.AuditEntry) (*models.Alarm, error) {
+	duration := time.Duration(req.Minutes) * time.Minute
+	maxDuration := s.settings.Duration(SettingAlarmShelveMax)
+	if duration > maxDuration {
+		return nil, ErrAlarmShelveTooLong.WithDetails(map[string]interface{}{"maxMinutes": int(maxDuration / time.Minute)})
+	}
+
+	id := utils.NormalizeID(models.IDPrefixAlarm, alarmID)
+	if err := s.checkAlarmAccess(actor, id); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	until := now.Add(duration)
+	shelved, err := s.alarmRepo.ShelveAlarm(id, until, actor.Email, req.Reason, now)
+	if err != nil {
+		return nil, err
+	}
+	if !shelved {
+		return nil, s.alarmStateError(id, ErrAlarmNotActive)
+	}
+
+	s.recordAlarmAudit(actor, audit, models.AuditActionAlarmShelve,
+		fmt.Sprintf("alarm %s shelved until %s: %s", id, until.Format(time.RFC3339), req.Reason))
+	return s.alarm(id)
+}
+
+// Unshelve - досрочно возвращает отложенную аварию в рабочий список
+func (s *AlarmService) Unshelve(actor models.Actor, alarmID string, audit models.AuditEntry) (*models.Alarm, error) {
+	id := utils.NormalizeID(models.IDPrefixAlarm, alarmID)
+	if err := s.checkAlarmAccess(actor, id); err != nil {
+		return nil, err
+	}
+	unshelved, err := s.alarmRepo.UnshelveAlarm(id, nil, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !unshelved {
+		return nil, s.alarmStateError(id, ErrAlarmNotShelved)
+	}
+
+	s.recordAlarmAudit(actor, audit, models.AuditActionAlarmUnshelve, fmt.Sprintf("alarm %s unshelved", id))
+	return s.alarm(id)
+}
+
+// ReleaseAlarms - возвращает в рабочий список аварии с истекшим сроком откладывания
+// и аварии, наряд-допуск по ячейке которых закрыт. Снятие условное, поэтому повторный
+// запуск или второй экземпляр не пишут аудит дважды.
+func (s *AlarmService) ReleaseAlarms(ctx context.Context, now time.Time) error {
+	expired, err := s.alarmRepo.GetExpiredShelves(now)
+	if err != nil {
+		return err
+	}
+	for _, alarm := range expired {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		unshelved, err := s.alarmRepo.UnshelveAlarm(alarm.ID, &now, now)
+		if err != nil {
+			return err
+		}
+		if unshelved {
+			s.recordSystemAudit(models.AuditActionAlarmUnshelve,
+				fmt.Sprintf("alarm %s shelve expired at %s", alarm.ID, alarm.ShelvedUntil.Format(time.RFC3339)))
+		}
+	}
+
+	suppressed, err := s.alarmRepo.GetSuppressedAlarms()
+	if err != nil {
+		return err
+	}
+	for _, alarm := range suppressed {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		permit, err := s.alarmRepo.FindOpenPermit(alarm.RuID, alarm.CellNumber, now)
+		if err != nil {
+			return err
+		}
+		// Пока по ячейке открыт наряд (тот же или продленный новым), авария остается подавленной
+		if permit != nil {
+			continue
+		}
+		released, err := s.alarmRepo.Unsuppress(alarm.ID, *alarm.SuppressedBy, now)
+		if err != nil {
+			return err
+		}
+		if released {
+			workOrder := *alarm.SuppressedBy
+			if alarm.SuppressedWorkOrder != nil {
+				workOrder = *alarm.SuppressedWorkOrder
+			}
+			s.recordSystemAudit(models.AuditActionAlarmUnsuppress,
+				fmt.Sprintf("alarm %s released: work permit %s closed", alarm.ID, workOrder))
+		}
+	}
+	return nil
+}
+
+// suppressUnderPermit - подавляет новую аварию по ячейке, на которой открыт наряд-допуск
+func (s *AlarmService) suppressUnderPermit(alarm *models.Alarm) error {
+	if !s.settings.Bool(SettingAlarmSuppressUnderPermit) || alarm.CellNumber == "" || alarm.RuID == "" {
+		return nil
+	}
+	permit, err := s.alarmRepo.FindOpenPermit(alarm.RuID, alarm.CellNumber, alarm.RaisedAt)
+	if err != nil {
+		return err
+	}
+	if permit == nil {
+		return nil
+	}
+	alarm.SuppressedBy = &permit.ID
+	alarm.SuppressedWorkOrder = permit.WorkOrderNumber
+	return nil
+}
+
+// checkAlarmAccess - авария относится к РУ организации пользователя; чужая авария
+// не отличается от несуществующей
+func (s *AlarmService) checkAlarmAccess(actor models.Actor, id string) error {
+	if _, err := s.alarmRepo.GetOrganizationAlarm(id, actor.OrganizationScope()); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrAlarmNotFound
+		}
+		return fmt.Errorf("failed to get alarm: %w", err)
+	}
+	return nil
+}
+
+// alarmStateError - авария не найдена или находится не в том состоянии
+func (s *AlarmService) alarmStateError(id string, stateErr error) error {
+	if _, err := s.alarmRepo.GetAlarmByID(id); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrAlarmNotFound
+		}
+		return fmt.Errorf("failed to get alarm: %w", err)
+	}
+	return stateErr
+}
+
+func (s *AlarmService) alarm(id string) (*models.Alarm, error) {
+	alarm, err := s.alarmRepo.GetAlarmByID(id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrAlarmNotFound
+		}
+		return nil, fmt.Errorf("failed to get alarm: %w", err)
+	}
+	if alarm.RecommendedAction == "" {
+		alarm.RecommendedAction = recommendedAction(alarm.Kind, alarm.Severity)
+	}
+	return alarm, nil
+}
+
+// recordAlarmAudit - запись аудита действия диспетчера над аварией
+func (s *AlarmService) recordAlarmAudit(actor models.Actor, audit models.AuditEntry, action, details string) {
+	audit.Action = action
+	audit.UserID = actor.UserID
+	audit.UserEmail = actor.Email
+	if actor.ImpersonatorID != "" {
+		audit.ImpersonatorID = &actor.ImpersonatorID
+	}
+	audit.Details = details
+	s.audit.Record(audit)
+}
+
+// recordSystemAudit - запись аудита действия планировщика над аварией
+func (s *AlarmService) recordSystemAudit(action, details string) {
+	s.audit.Record(models.AuditEntry{
+		Action:    action,
+		UserEmail: alarmSystemActor,
+		Details:   details,
+	})
+}
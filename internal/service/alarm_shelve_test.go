@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+func newTestAlarmService(t *testing.T) (*AlarmService, *repository.AlarmRepository, func(...interface{})) {
+	t.Helper()
+	db := newTestDB(t, &models.Alarm{}, &models.RUInfo{}, &models.OperationRecord{}, &models.Setting{}, &models.AuditEntry{})
+	rus := []models.RUInfo{
+		{ID: "ru-a", Name: "RU A", OrganizationID: "org-a"},
+		{ID: "ru-b", Name: "RU B", OrganizationID: "org-b"},
+	}
+	if err := db.Create(&rus).Error; err != nil {
+		t.Fatalf("seed RUs: %v", err)
+	}
+	alarmRepo := repository.NewAlarmRepository(db)
+	settings := NewSettingsService(repository.NewSettingRepository(db))
+	audit := NewAuditService(repository.NewAuditRepository(db))
+	seed := func(rows ...interface{}) {
+		t.Helper()
+		for _, row := range rows {
+			if err := db.Create(row).Error; err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+		}
+	}
+	return NewAlarmService(alarmRepo, nil, settings, nil, audit, repository.NewRuRepository(db)), alarmRepo, seed
+}
+
+func TestReleaseAlarms(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name string
+		// permit - запись журнала с нарядом по ячейке аварии, nil - наряда нет
+		permit         *models.OperationRecord
+		alarm          models.Alarm
+		wantShelved    bool
+		wantSuppressed bool
+	}{
+		{
+			name:  "expired shelve is released",
+			alarm: models.Alarm{ShelvedUntil: at(-time.Minute)},
+		},
+		{
+			name:  "shelve expiring right now is released",
+			alarm: models.Alarm{ShelvedUntil: at(0)},
+		},
+		{
+			name:        "active shelve stays",
+			alarm:       models.Alarm{ShelvedUntil: at(time.Minute)},
+			wantShelved: true,
+		},
+		{
+			name:   "closed permit releases suppression",
+			permit: &models.OperationRecord{StartDateAt: at(-2 * time.Hour), EndDateAt: at(-time.Minute)},
+			alarm:  models.Alarm{SuppressedBy: str("rec-1"), SuppressedWorkOrder: str("WO-1")},
+		},
+		{
+			name:           "open permit keeps suppression",
+			permit:         &models.OperationRecord{StartDateAt: at(-2 * time.Hour), EndDateAt: at(time.Hour)},
+			alarm:          models.Alarm{SuppressedBy: str("rec-1"), SuppressedWorkOrder: str("WO-1")},
+			wantSuppressed: true,
+		},
+		{
+			name:           "permit without end date keeps suppression",
+			permit:         &models.OperationRecord{StartDateAt: at(-2 * time.Hour)},
+			alarm:          models.Alarm{SuppressedBy: str("rec-1")},
+			wantSuppressed: true,
+		},
+		{
+			name:  "suppression without any permit is released",
+			alarm: models.Alarm{SuppressedBy: str("rec-1")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, alarmRepo, seed := newTestAlarmService(t)
+			if tt.permit != nil {
+				permit := *tt.permit
+				permit.ID, permit.RuID, permit.CellNumber, permit.WorkOrderNumber = "rec-1", "ru-a", "5", str("WO-1")
+				seed(&permit)
+			}
+			alarm := tt.alarm
+			alarm.ID, alarm.RuID, alarm.CellNumber, alarm.EventID = "alarm-1", "ru-a", "5", "event-1"
+			alarm.Status, alarm.RaisedAt = models.AlarmStatusActive, now.Add(-3*time.Hour)
+			seed(&alarm)
+
+			if err := service.ReleaseAlarms(context.Background(), now); err != nil {
+				t.Fatalf("ReleaseAlarms: %v", err)
+			}
+			got, err := alarmRepo.GetAlarmByID("alarm-1")
+			if err != nil {
+				t.Fatalf("GetAlarmByID: %v", err)
+			}
+			if shelved := got.ShelvedUntil != nil; shelved != tt.wantShelved {
+				t.Errorf("shelved = %v, want %v", shelved, tt.wantShelved)
+			}
+			if suppressed := got.SuppressedBy != nil; suppressed != tt.wantSuppressed {
+				t.Errorf("suppressed = %v, want %v", suppressed, tt.wantSuppressed)
+			}
+		})
+	}
+}
+
+func TestShelveChecksOrganization(t *testing.T) {
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		wantErr error
+	}{
+		{name: "same organization", actor: models.Actor{UserID: "u1", Role: models.RoleDispatcher, OrganizationID: "org-a"}},
+		{name: "platform admin", actor: models.Actor{UserID: "u2", Role: models.RoleAdmin, OrganizationID: "org-b"}},
+		{name: "other organization", actor: models.Actor{UserID: "u3", Role: models.RoleDispatcher, OrganizationID: "org-b"}, wantErr: ErrAlarmNotFound},
+		{name: "org admin of other organization", actor: models.Actor{UserID: "u4", Role: models.RoleOrgAdmin, OrganizationID: "org-b"}, wantErr: ErrAlarmNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, alarmRepo, seed := newTestAlarmService(t)
+			seed(&models.Alarm{ID: "alarm-1", RuID: "ru-a", EventID: "event-1", Status: models.AlarmStatusActive, RaisedAt: time.Now()})
+
+			_, err := service.Shelve(tt.actor, "alarm-1", &models.ShelveAlarmRequest{Minutes: 10, Reason: "maintenance"}, models.AuditEntry{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Shelve error = %v, want %v", err, tt.wantErr)
+			}
+			got, _ := alarmRepo.GetAlarmByID("alarm-1")
+			if shelved := got.ShelvedUntil != nil; shelved != (tt.wantErr == nil) {
+				t.Fatalf("shelved = %v after Shelve", shelved)
+			}
+
+			if tt.wantErr == nil {
+				if _, err := service.Unshelve(tt.actor, "alarm-1", models.AuditEntry{}); err != nil {
+					t.Fatalf("Unshelve: %v", err)
+				}
+				return
+			}
+			// Чужую отложенную аварию нельзя и вернуть
+			until := time.Now().Add(time.Hour)
+			seed(&models.Alarm{ID: "alarm-2", RuID: "ru-a", EventID: "event-2", Status: models.AlarmStatusActive, RaisedAt: time.Now(), ShelvedUntil: &until})
+			if _, err := service.Unshelve(tt.actor, "alarm-2", models.AuditEntry{}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Unshelve error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+const (
+	// maxAlarmStatsDays - самый длинный период статистики аварий
+	maxAlarmStatsDays = 366
+	// alarmStatsDefaultLimit - источников в отчете, если limit не задан
+	alarmStatsDefaultLimit = 50
+	// alarmStatsByCell - группировка по ячейкам (по умолчанию); иначе по РУ
+	alarmStatsByCell = "cell"
+)
+
+// GetAlarmStats - статистика аварий по ячейкам или РУ за период [from, to] (даты
+// включительно) для ежемесячного разбора надежности: число аварий по важности и видам,
+// среднее время квитирования и дребезг. Источники упорядочены от самых шумных, чтобы
+// сразу были видны датчики и уставки, требующие настройки. Учитываются только аварии РУ
+// организации пользователя.
+func (s *AlarmService) GetAlarmStats(actor models.Actor, query models.AlarmStatsQuery) (*models.AlarmStatsReport, error) {
+	from, err := time.ParseInLocation(summaryDateLayout, query.From, time.Local)
+	if err != nil {
+		return nil, ErrAlarmStatsRangeInvalid
+	}
+	to, err := time.ParseInLocation(summaryDateLayout, query.To, time.Local)
+	if err != nil || to.Before(from) || to.After(from.AddDate(0, 0, maxAlarmStatsDays-1)) {
+		return nil, ErrAlarmStatsRangeInvalid
+	}
+	groupBy := query.GroupBy
+	if groupBy == "" {
+		groupBy = alarmStatsByCell
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = alarmStatsDefaultLimit
+	}
+
+	if query.RuID != "" {
+		if err := checkRuAccess(s.ruRepo, actor, query.RuID); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := s.alarmRepo.GetAlarmStatsRows(query.RuID, actor.OrganizationScope(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	chatterCount := s.settings.Int(SettingAlarmChatterCount)
+	chatterWindow := s.settings.Duration(SettingAlarmChatterWindow)
+	report := &models.AlarmStatsReport{
+		From:                 query.From,
+		To:                   query.To,
+		GroupBy:              groupBy,
+		ChatterCount:         chatterCount,
+		ChatterWindowSeconds: int(chatterWindow / time.Second),
+		Total:                newAlarmStats("", "", ""),
+		Items:                []models.AlarmStats{},
+	}
+
+	type sourceKey struct {
+		ruID, cellNumber string
+		kind             models.AlarmKind
+	}
+	totalAck := time.Duration(0)
+	ackByGroup := map[string]time.Duration{}
+	groups := map[string]*models.AlarmStats{}
+	order := []string{}
+	raisedBySource := map[sourceKey][]time.Time{}
+	for _, row := range rows {
+		key := row.RuID
+		cellNumber := ""
+		if groupBy == alarmStatsByCell {
+			cellNumber = row.CellNumber
+			key += "\x00" + cellNumber
+		}
+		group, ok := groups[key]
+		if !ok {
+			stats := newAlarmStats(row.RuID, row.RuName, cellNumber)
+			group = &stats
+			groups[key] = group
+			order = append(order, key)
+		}
+
+		for _, stats := range []*models.AlarmStats{group, &report.Total} {
+			stats.Count++
+			stats.ByKind[row.Kind]++
+			switch row.Severity {
+			case models.AlarmSeverityCritical:
+				stats.Critical++
+			case models.AlarmSeverityWarning:
+				stats.Warning++
+			default:
+				stats.Info++
+			}
+		}
+		if row.AcknowledgedAt != nil && !row.AcknowledgedAt.Before(row.RaisedAt) {
+			elapsed := row.AcknowledgedAt.Sub(row.RaisedAt)
+			group.Acknowledged++
+			report.Total.Acknowledged++
+			ackByGroup[key] += elapsed
+			totalAck += elapsed
+		}
+		// Дребезг определяется по источнику аварии (ячейка и вид) и при группировке по РУ
+		source := sourceKey{ruID: row.RuID, cellNumber: row.CellNumber, kind: row.Kind}
+		raisedBySource[source] = append(raisedBySource[source], row.RaisedAt)
+	}
+
+	for source, raised := range raisedBySource {
+		episodes := chatterEpisodes(raised, chatterCount, chatterWindow)
+		if episodes == 0 {
+			continue
+		}
+		key := source.ruID
+		if groupBy == alarmStatsByCell {
+			key += "\x00" + source.cellNumber
+		}
+		for _, stats := range []*models.AlarmStats{groups[key], &report.Total} {
+			stats.ChatteringEpisodes += episodes
+			stats.ChatteringKinds = appendAlarmKind(stats.ChatteringKinds, source.kind)
+		}
+	}
+
+	report.Total.MeanTimeToAckSeconds = meanSeconds(totalAck, report.Total.Acknowledged)
+	sort.Slice(report.Total.ChatteringKinds, func(i, j int) bool {
+		return report.Total.ChatteringKinds[i] < report.Total.ChatteringKinds[j]
+	})
+	for _, key := range order {
+		group := groups[key]
+		group.MeanTimeToAckSeconds = meanSeconds(ackByGroup[key], group.Acknowledged)
+		sort.Slice(group.ChatteringKinds, func(i, j int) bool {
+			return group.ChatteringKinds[i] < group.ChatteringKinds[j]
+		})
+		report.Items = append(report.Items, *group)
+	}
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		a, b := report.Items[i], report.Items[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.ChatteringEpisodes != b.ChatteringEpisodes {
+			return a.ChatteringEpisodes > b.ChatteringEpisodes
+		}
+		if a.RuID != b.RuID {
+			return a.RuID < b.RuID
+		}
+		return a.CellNumber < b.CellNumber
+	})
+	if len(report.Items) > limit {
+		report.Items = report.Items[:limit]
+	}
+	return report, nil
+}
+
+func newAlarmStats(ruID, ruName, cellNumber string) models.AlarmStats {
+	return models.AlarmStats{
+		RuID:       ruID,
+		RuName:     ruName,
+		CellNumber: cellNumber,
+		ByKind:     map[models.AlarmKind]int{},
+	}
+}
+
+// chatterEpisodes - число эпизодов дребезга: серий, в которых не меньше count аварий
+// укладываются в window. Серия считается один раз, пока окно не опустеет ниже count.
+// raised - времена аварий одного источника по возрастанию.
+func chatterEpisodes(raised []time.Time, count int, window time.Duration) int {
+	if count < 2 {
+		return 0
+	}
+	episodes, left, inEpisode := 0, 0, false
+	for right := range raised {
+		for raised[right].Sub(raised[left]) > window {
+			left++
+		}
+		if right-left+1 < count {
+			inEpisode = false
+			continue
+		}
+		if !inEpisode {
+			episodes++
+			inEpisode = true
+		}
+	}
+	return episodes
+}
+
+func appendAlarmKind(kinds []models.AlarmKind, kind models.AlarmKind) []models.AlarmKind {
+	for _, k := range kinds {
+		if k == kind {
+			return kinds
+		}
+	}
+	return append(kinds, kind)
+}
+
+// meanSeconds - среднее время в секундах с одним знаком; nil, если усреднять нечего
+func meanSeconds(total time.Duration, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	mean := round1(total.Seconds() / float64(count))
+	return &mean
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+func TestChatterEpisodes(t *testing.T) {
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	// times - смещения аварий от base в минутах
+	times := func(minutes ...int) []time.Time {
+		raised := make([]time.Time, len(minutes))
+		for i, m := range minutes {
+			raised[i] = base.Add(time.Duration(m) * time.Minute)
+		}
+		return raised
+	}
+
+	tests := []struct {
+		name   string
+		raised []time.Time
+		count  int
+		window time.Duration
+		want   int
+	}{
+		{name: "no alarms", raised: nil, count: 3, window: 10 * time.Minute, want: 0},
+		{name: "count below two disables chatter", raised: times(0, 1, 2), count: 1, window: 10 * time.Minute, want: 0},
+		{name: "too few alarms", raised: times(0, 1), count: 3, window: 10 * time.Minute, want: 0},
+		{name: "one burst", raised: times(0, 1, 2), count: 3, window: 10 * time.Minute, want: 1},
+		{name: "window edge is inclusive", raised: times(0, 5, 10), count: 3, window: 10 * time.Minute, want: 1},
+		{name: "spread wider than window", raised: times(0, 6, 12), count: 3, window: 10 * time.Minute, want: 0},
+		{name: "long burst counts once", raised: times(0, 1, 2, 3, 4, 5, 6), count: 3, window: 10 * time.Minute, want: 1},
+		{name: "two bursts separated by quiet", raised: times(0, 1, 2, 60, 61, 62), count: 3, window: 10 * time.Minute, want: 2},
+		{name: "burst resumes after window thins out", raised: times(0, 1, 2, 20, 21, 22, 23), count: 3, window: 10 * time.Minute, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chatterEpisodes(tt.raised, tt.count, tt.window); got != tt.want {
+				t.Errorf("chatterEpisodes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeanSeconds(t *testing.T) {
+	tests := []struct {
+		name  string
+		total time.Duration
+		count int
+		want  *float64
+	}{
+		{name: "nothing to average", total: 0, count: 0, want: nil},
+		{name: "whole seconds", total: 90 * time.Second, count: 3, want: ptr(30.0)},
+		{name: "rounded to one decimal", total: 10 * time.Second, count: 3, want: ptr(3.3)},
+		{name: "sub-second", total: 250 * time.Millisecond, count: 1, want: ptr(0.3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := meanSeconds(tt.total, tt.count)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("meanSeconds() = %v, want %v", deref(got), deref(tt.want))
+			}
+		})
+	}
+}
+
+func TestGetAlarmStatsScopedByOrganization(t *testing.T) {
+	service, _, seed := newTestAlarmService(t)
+	raised := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	seed(
+		&models.Alarm{ID: "alarm-a", RuID: "ru-a", EventID: "event-a", Severity: models.AlarmSeverityCritical, Status: models.AlarmStatusActive, RaisedAt: raised},
+		&models.Alarm{ID: "alarm-b", RuID: "ru-b", EventID: "event-b", Severity: models.AlarmSeverityCritical, Status: models.AlarmStatusActive, RaisedAt: raised},
+	)
+	query := models.AlarmStatsQuery{From: "2025-03-01", To: "2025-03-31", GroupBy: "ru"}
+
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		ruID    string
+		want    int
+		wantErr error
+	}{
+		{name: "own organization", actor: models.Actor{Role: models.RoleDispatcher, OrganizationID: "org-a"}, want: 1},
+		{name: "platform admin sees all", actor: models.Actor{Role: models.RoleAdmin, OrganizationID: "org-a"}, want: 2},
+		{name: "own RU", actor: models.Actor{Role: models.RoleDispatcher, OrganizationID: "org-a"}, ruID: "ru-a", want: 1},
+		{name: "RU of other organization", actor: models.Actor{Role: models.RoleDispatcher, OrganizationID: "org-a"}, ruID: "ru-b", wantErr: ErrRuNotFound},
+		{name: "unknown RU", actor: models.Actor{Role: models.RoleDispatcher, OrganizationID: "org-a"}, ruID: "ru-x", wantErr: ErrRuNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := query
+			q.RuID = tt.ruID
+			report, err := service.GetAlarmStats(tt.actor, q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAlarmStats error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && report.Total.Count != tt.want {
+				t.Errorf("total = %d, want %d", report.Total.Count, tt.want)
+			}
+		})
+	}
+}
+
+func ptr(v float64) *float64 { return &v }
+
+func deref(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
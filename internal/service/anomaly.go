@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// anomalyLookback - окно минутных агрегатов, просматриваемое за один запуск;
+	// уже учтенные бакеты пропускаются по LastAt базовой линии
+	anomalyLookback = 15 * time.Minute
+	// anomalyWarmup - сколько минут показаний нужно, прежде чем базовой линии можно доверять
+	anomalyWarmup = 60
+	// anomalyDampening - во время аномалии базовая линия подстраивается медленнее,
+	// чтобы необычные показания не стали нормой раньше, чем их заметят
+	anomalyDampening = 10
+)
+
+// anomalyMetrics - метрики, для которых строится базовая линия
+var anomalyMetrics = []models.MeasurementMetric{models.MetricCurrent, models.MetricTemperature}
+
+// anomalyMinStdDev - нижняя граница стандартного отклонения: на почти постоянном
+// сигнале шум в доли ампера или градуса не должен давать огромный z-score
+var anomalyMinStdDev = map[models.MeasurementMetric]float64{
+	models.MetricCurrent:     1,
+	models.MetricTemperature: 0.5,
+}
+
+// AnomalyService - обнаружение необычных показаний телеметрии ниже абсолютных уставок.
+// Для каждой ячейки и метрики ведется EWMA среднего и дисперсии минутных агрегатов;
+// значение, отклонившееся больше чем на порог z-score, поднимает предупреждение
+// (событие telemetry.anomaly). Для температуры учитывается только рост - опасен нагрев.
+type AnomalyService struct {
+	anomalyRepo     *repository.AnomalyRepository
+	measurementRepo *repository.MeasurementRepository
+	ruRepo          *repository.RuRepository
+	settings        *SettingsService
+}
+
+func NewAnomalyService(anomalyRepo *repository.AnomalyRepository, measurementRepo *repository.MeasurementRepository, ruRepo *repository.RuRepository, settings *SettingsService) *AnomalyService {
+	return &AnomalyService{
+		anomalyRepo:     anomalyRepo,
+		measurementRepo: measurementRepo,
+		ruRepo:          ruRepo,
+		settings:        settings,
+	}
+}
+
+type baselineKey struct {
+	cellID int
+	metric models.MeasurementMetric
+}
+
+// Detect - обрабатывает закрытые минутные бакеты: считает оценку каждого, обновляет
+// базовые линии и поднимает событие при входе ячейки в аномалию. Выход из аномалии -
+// когда оценка опускается ниже половины порога.
+func (s *AnomalyService) Detect(ctx context.Context, now time.Time) error {
+	// Бакет считается закрытым через минуту после окончания, когда его уже посчитало прореживание
+	to := now.Truncate(time.Minute).Add(-time.Minute)
+	rollups, err := s.measurementRepo.GetRecentRollups(models.Resolution1m, anomalyMetrics, to.Add(-anomalyLookback), to)
+	if err != nil {
+		return err
+	}
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	existing, err := s.anomalyRepo.GetBaselines()
+	if err != nil {
+		return err
+	}
+	baselines := make(map[baselineKey]*models.CellBaseline, len(existing))
+	for i := range existing {
+		baselines[baselineKey{existing[i].CellID, existing[i].Metric}] = &existing[i]
+	}
+
+	threshold := float64(s.settings.Int(SettingAnomalyZThreshold))
+	alpha := 1 - math.Pow(0.5, float64(time.Minute)/float64(s.settings.Duration(SettingAnomalyHalfLife)))
+
+	changed := map[baselineKey]bool{}
+	var scored []models.MeasurementRollup
+	var events []models.OutboxEvent
+	for _, rollup := range rollups {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		key := baselineKey{rollup.CellID, rollup.Metric}
+		baseline := baselines[key]
+		if baseline == nil {
+			baseline = &models.CellBaseline{CellID: rollup.CellID, Metric: rollup.Metric, RuID: rollup.RuID, Mean: rollup.Avg}
+			baselines[key] = baseline
+		} else if !rollup.BucketStart.After(baseline.LastAt) {
+			continue
+		}
+
+		score := 0.0
+		if baseline.Samples >= anomalyWarmup {
+			score = anomalyScore(rollup.Metric, rollup.Avg, baseline.Mean, baseline.Variance)
+			rollup.Score = &score
+			scored = append(scored, rollup)
+		}
+
+		if score >= threshold && baseline.AnomalySince == nil {
+			event, err := s.anomalyEvent(baseline, rollup, score)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+			since := rollup.BucketStart
+			baseline.AnomalySince = &since
+		} else if score < threshold/2 {
+			baseline.AnomalySince = nil
+		}
+
+		weight := alpha
+		if baseline.AnomalySince != nil {
+			weight /= anomalyDampening
+		}
+		diff := rollup.Avg - baseline.Mean
+		baseline.Mean += weight * diff
+		baseline.Variance = (1 - weight) * (baseline.Variance + weight*diff*diff)
+		baseline.Samples++
+		baseline.LastValue = rollup.Avg
+		baseline.LastScore = score
+		baseline.LastAt = rollup.BucketStart
+		baseline.UpdatedAt = now
+		changed[key] = true
+	}
+
+	updated := make([]models.CellBaseline, 0, len(changed))
+	for key := range changed {
+		updated = append(updated, *baselines[key])
+	}
+	return s.anomalyRepo.SaveDetection(updated, scored, events)
+}
+
+// anomalyScore - отклонение от базовой линии в стандартных отклонениях
+func anomalyScore(metric models.MeasurementMetric, value, mean, variance float64) float64 {
+	deviation := value - mean
+	if metric == models.MetricTemperature && deviation < 0 {
+		return 0
+	}
+	stdDev := math.Max(math.Sqrt(variance), anomalyMinStdDev[metric])
+	return math.Round(math.Abs(deviation)/stdDev*100) / 100
+}
+
+func (s *AnomalyService) anomalyEvent(baseline *models.CellBaseline, rollup models.MeasurementRollup, score float64) (models.OutboxEvent, error) {
+	cellNumber := fmt.Sprintf("%d", rollup.CellID)
+	if cell, err := s.ruRepo.GetCellByID(rollup.CellID, rollup.RuID); err == nil {
+		cellNumber = cell.Number
+	}
+	return newEvent(models.EventTelemetryAnomaly, rollup.RuID, models.TelemetryAnomalyPayload{
+		CellID:     rollup.CellID,
+		CellNumber: cellNumber,
+		Metric:     rollup.Metric,
+		Value:      rollup.Avg,
+		Mean:       baseline.Mean,
+		StdDev:     math.Max(math.Sqrt(baseline.Variance), anomalyMinStdDev[rollup.Metric]),
+		Score:      score,
+		At:         rollup.BucketStart,
+	})
+}
+
+// Baseline - текущая базовая линия ячейки для графиков трендов; nil, если ее еще нет
+func (s *AnomalyService) Baseline(cellID int, metric models.MeasurementMetric) (*models.CellBaseline, error) {
+	baseline, err := s.anomalyRepo.GetBaseline(cellID, metric)
+	if err != nil || baseline == nil {
+		return nil, err
+	}
+	baseline.StdDev = math.Max(math.Sqrt(baseline.Variance), anomalyMinStdDev[metric])
+	return baseline, nil
+}
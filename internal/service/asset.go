@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+type AssetService struct {
+	assetRepo *repository.AssetRepository
+	ruRepo    *repository.RuRepository
+}
+
+func NewAssetService(assetRepo *repository.AssetRepository, ruRepo *repository.RuRepository) *AssetService {
+	return &AssetService{assetRepo: assetRepo, ruRepo: ruRepo}
+}
+
+// GetAssets - оборудование организации пользователя
+func (s *AssetService) GetAssets(actor models.Actor, filter models.AssetFilter) ([]models.Asset, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	assets, err := s.assetRepo.GetAssets(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assets: %w", err)
+	}
+	return assets, nil
+}
+
+// GetAsset - оборудование организации пользователя; чужое не отличается от несуществующего
+func (s *AssetService) GetAsset(actor models.Actor, id string) (*models.Asset, error) {
+	asset, err := s.assetRepo.GetOrganizationAsset(utils.NormalizeID(models.IDPrefixAsset, id), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	return asset, nil
+}
+
+func (s *AssetService) GetHistory(actor models.Actor, id string) ([]models.AssetEvent, error) {
+	asset, err := s.GetAsset(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.assetRepo.GetHistory(asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset history: %w", err)
+	}
+	return events, nil
+}
+
+// CreateAsset - регистрирует оборудование на складе организации пользователя
+func (s *AssetService) CreateAsset(req *models.CreateAssetRequest, actor models.Actor) (*models.Asset, error) {
+	if err := s.checkSerial(req.SerialNumber, ""); err != nil {
+		return nil, err
+	}
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() && req.OrganizationID != "" {
+		organizationID = req.OrganizationID
+	}
+
+	now := time.Now()
+	asset := &models.Asset{
+		ID:             utils.NewID(models.IDPrefixAsset),
+		SerialNumber:   req.SerialNumber,
+		Type:           req.Type,
+		Manufacturer:   req.Manufacturer,
+		Model:          req.Model,
+		Year:           req.Year,
+		State:          models.AssetInStock,
+		Notes:          req.Notes,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		OrganizationID: organizationID,
+	}
+	event := newAssetEvent(asset, models.AssetEventRegistered, "", actor, now)
+	event.ToState = asset.State
+
+	if err := s.assetRepo.Save([]*models.Asset{asset}, []models.AssetEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+	return asset, nil
+}
+
+func (s *AssetService) UpdateAsset(id string, req *models.UpdateAssetRequest, actor models.Actor) (*models.Asset, error) {
+	asset, err := s.GetAsset(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.SerialNumber != nil && *req.SerialNumber != asset.SerialNumber {
+		if err := s.checkSerial(*req.SerialNumber, asset.ID); err != nil {
+			return nil, err
+		}
+		asset.SerialNumber = *req.SerialNumber
+	}
+	if req.Manufacturer != nil {
+		asset.Manufacturer = *req.Manufacturer
+	}
+	if req.Model != nil {
+		asset.Model = *req.Model
+	}
+	if req.Year != nil {
+		asset.Year = req.Year
+	}
+	if req.Notes != nil {
+		asset.Notes = *req.Notes
+	}
+
+	now := time.Now()
+	asset.UpdatedAt = now
+	event := newAssetEvent(asset, models.AssetEventUpdated, "", actor, now)
+	if err := s.assetRepo.Save([]*models.Asset{asset}, []models.AssetEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to update asset: %w", err)
+	}
+	return asset, nil
+}
+
+// MoveAsset - устанавливает оборудование в ячейку (со склада, из ремонта или из другой ячейки)
+// РУ той же организации, что и оборудование
+func (s *AssetService) MoveAsset(id string, req *models.MoveAssetRequest, actor models.Actor) (*models.Asset, error) {
+	asset, err := s.GetAsset(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if asset.State == models.AssetDecommissioned {
+		return nil, ErrAssetDecommissioned
+	}
+	organizationID, err := ruOrganization(s.ruRepo, actor, req.RuID)
+	if err != nil {
+		return nil, err
+	}
+	if organizationID != asset.OrganizationID {
+		return nil, ErrAssetOrganizationMismatch
+	}
+
+	cell, err := s.ruRepo.GetCellByID(req.CellID, req.RuID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	if asset.State == models.AssetInstalled && asset.CellID != nil && *asset.CellID == cell.ID {
+		return asset, nil
+	}
+
+	occupants, err := s.assetRepo.GetInstalled(cell.RuID, cell.ID, asset.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get installed assets: %w", err)
+	}
+	if len(occupants) > 0 && !req.Replace {
+		return nil, ErrAssetSlotOccupied.WithDetails(occupants)
+	}
+
+	now := time.Now()
+	changed := []*models.Asset{}
+	var events []models.AssetEvent
+
+	// Замена: установленное оборудование снимается на склад
+	for i := range occupants {
+		occupant := &occupants[i]
+		events = append(events, removeAsset(occupant, models.AssetInStock, req.Comment, actor, now))
+		changed = append(changed, occupant)
+	}
+	if asset.State == models.AssetInstalled {
+		events = append(events, removeAsset(asset, models.AssetInStock, req.Comment, actor, now))
+	}
+
+	fromState := asset.State
+	asset.State = models.AssetInstalled
+	asset.RuID = &cell.RuID
+	asset.CellID = &cell.ID
+	asset.CellNumber = &cell.Number
+	asset.InstalledAt = &now
+	asset.UpdatedAt = now
+
+	event := newAssetEvent(asset, models.AssetEventInstalled, req.Comment, actor, now)
+	event.FromState = fromState
+	event.ToState = asset.State
+	events = append(events, event)
+	changed = append(changed, asset)
+
+	if err := s.assetRepo.Save(changed, events); err != nil {
+		return nil, fmt.Errorf("failed to move asset: %w", err)
+	}
+	return asset, nil
+}
+
+// ChangeState - снятие в ремонт, на склад или списание. Установленное
+// оборудование при этом снимается с ячейки.
+func (s *AssetService) ChangeState(id string, req *models.ChangeAssetStateRequest, actor models.Actor) (*models.Asset, error) {
+	asset, err := s.GetAsset(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if asset.State == models.AssetDecommissioned {
+		return nil, ErrAssetDecommissioned
+	}
+	if asset.State == req.State {
+		return asset, nil
+	}
+
+	now := time.Now()
+	var event models.AssetEvent
+	if asset.State == models.AssetInstalled {
+		event = removeAsset(asset, req.State, req.Comment, actor, now)
+	} else {
+		event = newAssetEvent(asset, models.AssetEventStateChanged, req.Comment, actor, now)
+		event.FromState = asset.State
+		event.ToState = req.State
+		asset.State = req.State
+		asset.UpdatedAt = now
+	}
+
+	if err := s.assetRepo.Save([]*models.Asset{asset}, []models.AssetEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to change asset state: %w", err)
+	}
+	return asset, nil
+}
+
+func (s *AssetService) checkSerial(serial, exceptID string) error {
+	exists, err := s.assetRepo.ExistsBySerial(serial, exceptID)
+	if err != nil {
+		return fmt.Errorf("failed to check asset serial: %w", err)
+	}
+	if exists {
+		return ErrAssetSerialExists
+	}
+	return nil
+}
+
+// removeAsset - снимает оборудование с ячейки; событие хранит ячейку, с которой сняли
+func removeAsset(asset *models.Asset, state models.AssetState, comment string, actor models.Actor, now time.Time) models.AssetEvent {
+	event := newAssetEvent(asset, models.AssetEventRemoved, comment, actor, now)
+	event.FromState = asset.State
+	event.ToState = state
+
+	asset.State = state
+	asset.RuID = nil
+	asset.CellID = nil
+	asset.CellNumber = nil
+	asset.InstalledAt = nil
+	asset.UpdatedAt = now
+	return event
+}
+
+func newAssetEvent(asset *models.Asset, eventType models.AssetEventType, comment string, actor models.Actor, now time.Time) models.AssetEvent {
+	return models.AssetEvent{
+		ID:         utils.NewID(models.IDPrefixAssetEvent),
+		AssetID:    asset.ID,
+		Type:       eventType,
+		RuID:       asset.RuID,
+		CellID:     asset.CellID,
+		CellNumber: asset.CellNumber,
+		Comment:    comment,
+		By:         actor.Email,
+		At:         now,
+	}
+}
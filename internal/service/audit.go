@@ -0,0 +1,71 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	auditDefaultLimit = 100
+	auditMaxLimit     = 1000
+)
+
+// AuditService - журнал аудита изменяющих запросов и действий администраторов
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+func NewAuditService(auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record - сохраняет запись аудита. Сбой записи не отменяет уже выполненное действие
+// и только логируется.
+func (s *AuditService) Record(entry models.AuditEntry) {
+	entry.ID = utils.NewID(models.IDPrefixAudit)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := s.auditRepo.Create(&entry); err != nil {
+		log.Printf("⚠️ Failed to record audit entry %s %s: %v", entry.Action, entry.Path, err)
+	}
+}
+
+func (s *AuditService) GetEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = auditDefaultLimit
+	}
+	filter.Limit = min(filter.Limit, auditMaxLimit)
+	return s.auditRepo.GetEntries(filter)
+}
+
+// RecordLogin - сохраняет попытку входа; сбой записи не мешает входу
+func (s *AuditService) RecordLogin(event models.LoginEvent) {
+	event.ID = utils.NewID(models.IDPrefixLogin)
+	event.CreatedAt = time.Now()
+	if err := s.auditRepo.CreateLogin(&event); err != nil {
+		log.Printf("⚠️ Failed to record login of %s: %v", event.Email, err)
+	}
+}
+
+// GetUserActivity - последние входы и изменения пользователя
+func (s *AuditService) GetUserActivity(userID string, limit int) ([]models.LoginEvent, []models.AuditEntry, error) {
+	if limit <= 0 {
+		limit = auditDefaultLimit
+	}
+	limit = min(limit, auditMaxLimit)
+
+	logins, err := s.auditRepo.GetLogins(userID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	actions, err := s.auditRepo.GetEntries(models.AuditFilter{UserID: userID, Limit: limit})
+	if err != nil {
+		return nil, nil, err
+	}
+	return logins, actions, nil
+}
@@ -1,36 +1,54 @@
 package service
 
 import (
-	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/mailer"
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
 	"github.com/Temoojeen/sez-vision-backend/internal/repository"
 	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
 )
 
 type AuthService struct {
-	userRepo  *repository.UserRepository
-	jwtSecret string
-	jwtTTL    time.Duration
+	userRepo      *repository.UserRepository
+	settings      *SettingsService
+	audit         *AuditService
+	notifications *NotificationService
+	mailer        mailer.Sender
+	publicURL     string
+	jwtSecret     string
+	jwtTTL        time.Duration
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtSecret string, jwtTTL time.Duration) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, settings *SettingsService, audit *AuditService, notifications *NotificationService, sender mailer.Sender, publicURL, jwtSecret string, jwtTTL time.Duration) *AuthService {
 	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-		jwtTTL:    jwtTTL,
+		userRepo:      userRepo,
+		settings:      settings,
+		audit:         audit,
+		notifications: notifications,
+		mailer:        sender,
+		publicURL:     publicURL,
+		jwtSecret:     jwtSecret,
+		jwtTTL:        jwtTTL,
 	}
 }
 
-func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthResponse, error) {
+// Register - самостоятельная регистрация. Учетная запись создается неактивной: пользователь
+// подтверждает адрес по ссылке из письма, затем администратор одобряет регистрацию и
+// назначает роль. Без SMTP письмо не отправить, и регистрация сразу ждет одобрения.
+func (s *AuthService) Register(req *models.RegisterRequest, loc i18n.Lang) (*models.RegisterResponse, error) {
 	exists, err := s.userRepo.ExistsByEmail(req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check email: %w", err)
 	}
 	if exists {
-		return nil, errors.New("user with this email already exists")
+		return nil, ErrUserExists
+	}
+
+	if err := validatePassword(s.settings, req.Password); err != nil {
+		return nil, err
 	}
 
 	passwordHash, err := utils.HashPassword(req.Password)
@@ -43,42 +61,69 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.AuthRespons
 		Email:        req.Email,
 		PasswordHash: passwordHash,
 		Role:         models.RoleEngineer,
+		Status:       models.UserPendingVerification,
+	}
+	if s.mailer == nil {
+		user.Status = models.UserPendingApproval
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	token, err := utils.GenerateToken(user, s.jwtSecret, s.jwtTTL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+	message := "auth.registered_pending_approval"
+	if user.Status == models.UserPendingVerification {
+		message = "auth.registered_verify_email"
+		s.sendVerification(user, loc)
+	} else {
+		s.notifyRegistration(user)
 	}
 
-	return &models.AuthResponse{
-		User: models.UserResponse{ // Изменено: передаем значение, а не указатель
-			ID:        user.ID,
-			Name:      user.Name,
-			Email:     user.Email,
-			Role:      string(user.Role),
-			CreatedAt: user.CreatedAt,
+	return &models.RegisterResponse{
+		User: models.UserResponse{
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			Role:           string(user.Role),
+			OrganizationID: user.OrganizationID,
+			Phone:          user.Phone,
+			Status:         user.Status,
+			CreatedAt:      user.CreatedAt,
 		},
-		Token: token,
+		Message: message,
 	}, nil
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, error) {
+// Login - вход по email и паролю; каждая попытка сохраняется в истории входов
+func (s *AuthService) Login(req *models.LoginRequest, client models.ClientInfo) (*models.AuthResponse, error) {
+	event := models.LoginEvent{Email: req.Email, IP: client.IP, UserAgent: client.UserAgent}
+
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("invalid email or password")
+		event.FailureReason = models.LoginFailureUnknownEmail
+		s.audit.RecordLogin(event)
+		return nil, ErrInvalidCredentials
 	}
 
+	event.UserID = &user.ID
 	if !utils.CheckPassword(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid email or password")
+		event.FailureReason = models.LoginFailureInvalidPassword
+		s.audit.RecordLogin(event)
+		return nil, ErrInvalidCredentials
+	}
+	// Состояние регистрации сообщается только знающему пароль
+	if err := inactiveAccountError(user.Status); err != nil {
+		event.FailureReason = models.LoginFailureInactive
+		s.audit.RecordLogin(event)
+		return nil, err
 	}
 
+	event.Success = true
+	s.audit.RecordLogin(event)
+
 	token, err := utils.GenerateToken(user, s.jwtSecret, s.jwtTTL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
@@ -86,11 +131,14 @@ func (s *AuthService) Login(req *models.LoginRequest) (*models.AuthResponse, err
 
 	return &models.AuthResponse{
 		User: models.UserResponse{ // Изменено: передаем значение, а не указатель
-			ID:        user.ID,
-			Name:      user.Name,
-			Email:     user.Email,
-			Role:      string(user.Role),
-			CreatedAt: user.CreatedAt,
+			ID:             user.ID,
+			Name:           user.Name,
+			Email:          user.Email,
+			Role:           string(user.Role),
+			OrganizationID: user.OrganizationID,
+			Phone:          user.Phone,
+			Status:         user.Status,
+			CreatedAt:      user.CreatedAt,
 		},
 		Token: token,
 	}, nil
@@ -102,14 +150,17 @@ func (s *AuthService) GetCurrentUser(userID string) (*models.UserResponse, error
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	return &models.UserResponse{ // Здесь возвращаем указатель
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		OrganizationID: user.OrganizationID,
+		Phone:          user.Phone,
+		Status:         user.Status,
+		CreatedAt:      user.CreatedAt,
 	}, nil
 }
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const calendarDateLayout = "2006-01-02"
+
+// defaultHolidays - государственные праздники РК с фиксированной датой (месяц-день).
+// Праздники с плавающей датой (Курбан айт) и переносы задаются через календарь в БД.
+var defaultHolidays = map[string]string{
+	"01-01": "Новый год",
+	"01-02": "Новый год",
+	"01-07": "Православное Рождество",
+	"03-08": "Международный женский день",
+	"03-21": "Наурыз мейрамы",
+	"03-22": "Наурыз мейрамы",
+	"03-23": "Наурыз мейрамы",
+	"05-01": "Праздник единства народа Казахстана",
+	"05-07": "День защитника Отечества",
+	"05-09": "День Победы",
+	"07-06": "День Столицы",
+	"08-30": "День Конституции",
+	"10-25": "День Республики",
+	"12-16": "День Независимости",
+}
+
+// WorkCalendar - производственный календарь на загруженный диапазон дат
+type WorkCalendar struct {
+	days map[string]models.CalendarDay
+}
+
+func dateKey(t time.Time) string {
+	return t.Format(calendarDateLayout)
+}
+
+func isWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// Day - возвращает день-исключение, если дата отличается от обычного графика
+func (w *WorkCalendar) Day(t time.Time) (models.CalendarDay, bool) {
+	day, ok := w.days[dateKey(t)]
+	return day, ok
+}
+
+// IsWorkingDay - рабочий ли день с учетом праздников и переносов
+func (w *WorkCalendar) IsWorkingDay(t time.Time) bool {
+	if day, ok := w.Day(t); ok {
+		return day.Type != models.CalendarDayHoliday
+	}
+	return !isWeekend(t)
+}
+
+// IsShortened - сокращенный ли рабочий день
+func (w *WorkCalendar) IsShortened(t time.Time) bool {
+	day, ok := w.Day(t)
+	return ok && day.Type == models.CalendarDayShortened
+}
+
+// AddWorkingDays - сдвигает дату на n рабочих дней (n может быть отрицательным)
+func (w *WorkCalendar) AddWorkingDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if w.IsWorkingDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// PreviousWorkingDay - ближайший рабочий день, не позже указанной даты
+func (w *WorkCalendar) PreviousWorkingDay(t time.Time) time.Time {
+	for !w.IsWorkingDay(t) {
+		t = t.AddDate(0, 0, -1)
+	}
+	return t
+}
+
+// NextWorkingDay - ближайший рабочий день, не раньше указанной даты
+func (w *WorkCalendar) NextWorkingDay(t time.Time) time.Time {
+	for !w.IsWorkingDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// WorkingDaysBetween - число рабочих дней в интервале (from, to]
+func (w *WorkCalendar) WorkingDaysBetween(from, to time.Time) int {
+	count := 0
+	for d := from.AddDate(0, 0, 1); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if w.IsWorkingDay(d) {
+			count++
+		}
+	}
+	return count
+}
+
+type CalendarService struct {
+	calendarRepo *repository.CalendarRepository
+}
+
+func NewCalendarService(calendarRepo *repository.CalendarRepository) *CalendarService {
+	return &CalendarService{calendarRepo: calendarRepo}
+}
+
+// Load - загружает календарь на диапазон дат: праздники по умолчанию
+// (с переносом выпавших на выходные) дополняются и переопределяются записями из БД
+func (s *CalendarService) Load(from, to time.Time) (*WorkCalendar, error) {
+	if to.Before(from) {
+		from, to = to, from
+	}
+
+	calendar := &WorkCalendar{days: make(map[string]models.CalendarDay)}
+
+	for year := from.Year(); year <= to.Year(); year++ {
+		for key, day := range defaultDaysForYear(year) {
+			calendar.days[key] = day
+		}
+	}
+
+	overrides, err := s.calendarRepo.GetDaysInRange(dateKey(from), dateKey(to))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load calendar: %w", err)
+	}
+	for _, day := range overrides {
+		calendar.days[day.Date] = day
+	}
+
+	return calendar, nil
+}
+
+// LoadAround - загружает календарь с запасом вокруг даты для расчетов на days рабочих дней
+func (s *CalendarService) LoadAround(t time.Time, days int) (*WorkCalendar, error) {
+	if days < 0 {
+		days = -days
+	}
+	// Запас покрывает выходные и длинные праздничные периоды
+	margin := days*2 + 31
+	return s.Load(t.AddDate(0, 0, -margin), t.AddDate(0, 0, margin))
+}
+
+// GetYear - список дней-исключений за год
+func (s *CalendarService) GetYear(year int) ([]models.CalendarDay, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.Local)
+
+	calendar, err := s.Load(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	days := []models.CalendarDay{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if day, ok := calendar.Day(d); ok {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func (s *CalendarService) UpsertDay(date string, req *models.UpsertCalendarDayRequest) (*models.CalendarDay, error) {
+	if _, err := time.Parse(calendarDateLayout, date); err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	now := time.Now()
+	day := &models.CalendarDay{
+		Date:      date,
+		Type:      req.Type,
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.calendarRepo.UpsertDay(day); err != nil {
+		return nil, fmt.Errorf("failed to save calendar day: %w", err)
+	}
+	return day, nil
+}
+
+func (s *CalendarService) DeleteDay(date string) error {
+	deleted, err := s.calendarRepo.DeleteDay(date)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar day: %w", err)
+	}
+	if !deleted {
+		return ErrCalendarDayNotFound
+	}
+	return nil
+}
+
+// defaultDaysForYear - праздники по умолчанию за год. Праздник, выпавший на выходной,
+// переносится на следующий рабочий день.
+func defaultDaysForYear(year int) map[string]models.CalendarDay {
+	days := make(map[string]models.CalendarDay)
+	for md, name := range defaultHolidays {
+		t, err := time.ParseInLocation(calendarDateLayout, fmt.Sprintf("%d-%s", year, md), time.Local)
+		if err != nil {
+			continue
+		}
+		days[dateKey(t)] = models.CalendarDay{Date: dateKey(t), Type: models.CalendarDayHoliday, Name: name}
+	}
+
+	for md, name := range defaultHolidays {
+		t, err := time.ParseInLocation(calendarDateLayout, fmt.Sprintf("%d-%s", year, md), time.Local)
+		if err != nil || !isWeekend(t) {
+			continue
+		}
+		// Православное Рождество на выходной не переносится
+		if md == "01-07" {
+			continue
+		}
+		next := t.AddDate(0, 0, 1)
+		for {
+			_, taken := days[dateKey(next)]
+			if !isWeekend(next) && !taken {
+				break
+			}
+			next = next.AddDate(0, 0, 1)
+		}
+		days[dateKey(next)] = models.CalendarDay{Date: dateKey(next), Type: models.CalendarDayHoliday, Name: name + " (перенос)"}
+	}
+
+	return days
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/ical"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// Период ленты iCal: прошедшие события за месяц и будущие на год вперед
+	calendarFeedPast  = 30 * 24 * time.Hour
+	calendarFeedAhead = 365 * 24 * time.Hour
+	// calendarFeedPath - адрес ленты; ключ подписки передается параметром token
+	calendarFeedPath = "/api/calendar.ics"
+)
+
+func calendarFeedWindow(now time.Time) (time.Time, time.Time) {
+	return now.Add(-calendarFeedPast), now.Add(calendarFeedAhead)
+}
+
+// CalendarFeedService - лента iCal пользователя: плановые отключения, сроки ТО и
+// осмотров РУ. Лента открывается по ключу подписки, а не по JWT.
+type CalendarFeedService struct {
+	calendarRepo *repository.CalendarRepository
+	userRepo     *repository.UserRepository
+	ruService    *RuService
+	calendar     *CalendarService
+	outages      *OutageService
+	settings     *SettingsService
+}
+
+func NewCalendarFeedService(calendarRepo *repository.CalendarRepository, userRepo *repository.UserRepository, ruService *RuService, calendar *CalendarService, outages *OutageService, settings *SettingsService) *CalendarFeedService {
+	return &CalendarFeedService{
+		calendarRepo: calendarRepo,
+		userRepo:     userRepo,
+		ruService:    ruService,
+		calendar:     calendar,
+		outages:      outages,
+		settings:     settings,
+	}
+}
+
+// IssueToken - новый ключ подписки; прежний ключ пользователя перестает действовать
+func (s *CalendarFeedService) IssueToken(actor models.Actor) (*models.CalendarFeedTokenResponse, error) {
+	if actor.ImpersonatorID != "" {
+		return nil, ErrImpersonationNotAllowed
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	record := &models.CalendarFeedToken{
+		UserID:    actor.UserID,
+		TokenHash: feedTokenHash(token),
+		CreatedAt: time.Now(),
+	}
+	if err := s.calendarRepo.SaveFeedToken(record); err != nil {
+		return nil, err
+	}
+	return &models.CalendarFeedTokenResponse{
+		Token:     token,
+		Path:      calendarFeedPath + "?token=" + token,
+		CreatedAt: record.CreatedAt,
+	}, nil
+}
+
+func (s *CalendarFeedService) RevokeToken(actor models.Actor) error {
+	deleted, err := s.calendarRepo.DeleteFeedToken(actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedTokenNotFound
+	}
+	return nil
+}
+
+// Feed - лента по ключу подписки с правами владельца ключа на момент запроса:
+// отключения его организации, а инженерам и администраторам - еще сроки ТО и осмотров
+func (s *CalendarFeedService) Feed(token string) (ical.Calendar, error) {
+	if token == "" {
+		return ical.Calendar{}, ErrCalendarFeedTokenInvalid
+	}
+	record, err := s.calendarRepo.GetFeedTokenByHash(feedTokenHash(token))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return ical.Calendar{}, ErrCalendarFeedTokenInvalid
+		}
+		return ical.Calendar{}, err
+	}
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil {
+		return ical.Calendar{}, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return ical.Calendar{}, ErrCalendarFeedTokenInvalid
+	}
+	actor := models.UserActor(user)
+
+	now := time.Now()
+	from, to := calendarFeedWindow(now)
+	events, err := s.outages.CalendarEvents(actor, from, to)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	if actor.IsElevated() {
+		deadlines, err := s.deadlineEvents(actor, from, to, now)
+		if err != nil {
+			return ical.Calendar{}, err
+		}
+		events = append(events, deadlines...)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	if err := s.calendarRepo.TouchFeedToken(record.UserID, now); err != nil {
+		log.Printf("⚠️ Failed to record calendar feed access: %v", err)
+	}
+	return ical.Calendar{Name: i18n.T(i18n.Default, "calendar.feed.name"), Events: events}, nil
+}
+
+// deadlineEvents - сроки ТО и очередных осмотров видимых РУ событиями на весь день.
+// UID включает дату срока: после переноса срока старое событие исчезает из ленты.
+func (s *CalendarFeedService) deadlineEvents(actor models.Actor, from, to, now time.Time) ([]ical.Event, error) {
+	rus, err := s.ruService.GetVisibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.calendar.Load(from, to)
+	if err != nil {
+		return nil, err
+	}
+	interval := s.settings.Int(SettingInspectionIntervalDays)
+
+	var events []ical.Event
+	for _, ru := range rus {
+		if deadline, ok := maintenanceDeadline(ru, calendar); ok && inFeedWindow(deadline, from, to) {
+			events = append(events, deadlineEvent("maintenance", ru, deadline,
+				i18n.T(i18n.Default, "calendar.feed.maintenance", ru.Name), now))
+		}
+
+		lastInspection := ru.LastInspectionAt
+		if lastInspection == nil {
+			lastInspection = utils.ParseDatePtr(&ru.LastInspection)
+		}
+		if lastInspection == nil {
+			continue
+		}
+		deadline := calendar.PreviousWorkingDay(lastInspection.AddDate(0, 0, interval))
+		if inFeedWindow(deadline, from, to) {
+			events = append(events, deadlineEvent("inspection", ru, deadline,
+				i18n.T(i18n.Default, "calendar.feed.inspection", ru.Name), now))
+		}
+	}
+	return events, nil
+}
+
+func deadlineEvent(kind string, ru models.RUInfo, deadline time.Time, summary string, now time.Time) ical.Event {
+	day := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.UTC)
+	return ical.Event{
+		UID:      fmt.Sprintf("%s-%s-%s@sez-vision", kind, ru.ID, day.Format("20060102")),
+		Summary:  summary,
+		Location: ru.Location,
+		Start:    day,
+		End:      day.AddDate(0, 0, 1),
+		AllDay:   true,
+		Status:   ical.StatusConfirmed,
+		Updated:  now,
+	}
+}
+
+func inFeedWindow(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+// feedTokenHash - в базе хранится только хеш ключа подписки
+func feedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// utilizationHysteresis - на сколько процентных пунктов загрузка должна опуститься
+	// ниже порога, чтобы секция вернулась в более низкую полосу (без дребезга аварий)
+	utilizationHysteresis = 5.0
+	// utilizationFreshness - телеметрия тока старше этого срока не учитывается,
+	// вместо нее берется последнее значение из карточки ячейки
+	utilizationFreshness = 5 * time.Minute
+)
+
+// sectionFeederTypes - отходящие присоединения, по которым считается ток секции без вводов
+var sectionFeederTypes = map[models.CellType]bool{
+	models.CellTypeOutput:      true,
+	models.CellTypeTransformer: true,
+	models.CellTypeLowVoltage:  true,
+}
+
+// CapacityService - загрузка секций шин относительно допустимого тока стороны РУ.
+// Ток секции - сумма токов вводов секции, а без вводов - сумма отходящих присоединений.
+// При переходе секции в полосу warning/critical поднимается событие capacity.overload.
+type CapacityService struct {
+	capacityRepo    *repository.CapacityRepository
+	ruRepo          *repository.RuRepository
+	measurementRepo *repository.MeasurementRepository
+	ruService       *RuService
+	settings        *SettingsService
+}
+
+func NewCapacityService(capacityRepo *repository.CapacityRepository, ruRepo *repository.RuRepository, measurementRepo *repository.MeasurementRepository, ruService *RuService, settings *SettingsService) *CapacityService {
+	return &CapacityService{
+		capacityRepo:    capacityRepo,
+		ruRepo:          ruRepo,
+		measurementRepo: measurementRepo,
+		ruService:       ruService,
+		settings:        settings,
+	}
+}
+
+type sectionKey struct {
+	ruID         string
+	voltageLevel models.VoltageLevel
+	section      int
+}
+
+// Compute - пересчитывает загрузку всех секций РУ с заданным допустимым током
+func (s *CapacityService) Compute(ctx context.Context, now time.Time) error {
+	rus, err := s.ruRepo.GetAllRUs()
+	if err != nil {
+		return err
+	}
+	ruIDs := make([]string, 0, len(rus))
+	for _, ru := range rus {
+		if ru.MaxCapacityHighA != nil || ru.MaxCapacityLowA != nil {
+			ruIDs = append(ruIDs, ru.ID)
+		}
+	}
+	cells, err := s.ruRepo.GetCellsByRuIDs(ruIDs)
+	if err != nil {
+		return err
+	}
+	currents, err := s.latestCurrents(now)
+	if err != nil {
+		return err
+	}
+	previous, err := s.capacityRepo.GetUtilizations(nil)
+	if err != nil {
+		return err
+	}
+	prevByKey := make(map[sectionKey]models.SectionUtilization, len(previous))
+	for _, row := range previous {
+		prevByKey[sectionKey{row.RuID, row.VoltageLevel, row.Section}] = row
+	}
+
+	// Токи вводов и отходящих присоединений по секциям
+	type sectionLoad struct {
+		inputs, feeders       float64
+		hasInputs, hasFeeders bool
+	}
+	loads := map[sectionKey]*sectionLoad{}
+	for _, cell := range cells {
+		current, ok := currents[cell.ID]
+		if !ok && cell.Current != nil {
+			current, ok = *cell.Current, true
+		}
+		if !ok {
+			continue
+		}
+		section := 0
+		if cell.BusSection != nil {
+			section = *cell.BusSection
+		}
+		key := sectionKey{cell.RuID, cell.VoltageLevel, section}
+		load := loads[key]
+		if load == nil {
+			load = &sectionLoad{}
+			loads[key] = load
+		}
+		switch {
+		case cell.Type == models.CellTypeInput:
+			load.inputs += current
+			load.hasInputs = true
+		case sectionFeederTypes[cell.Type]:
+			load.feeders += current
+			load.hasFeeders = true
+		}
+	}
+
+	warning := float64(s.settings.Int(SettingCapacityWarningPercent))
+	critical := float64(s.settings.Int(SettingCapacityCriticalPercent))
+
+	ruByID := make(map[string]models.RUInfo, len(rus))
+	for _, ru := range rus {
+		ruByID[ru.ID] = ru
+	}
+
+	var rows []models.SectionUtilization
+	var events []models.OutboxEvent
+	for key, load := range loads {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ru := ruByID[key.ruID]
+		capacity := ru.MaxCapacityHighA
+		if key.voltageLevel == models.VoltageLevelLow {
+			capacity = ru.MaxCapacityLowA
+		}
+		if capacity == nil || *capacity <= 0 || (!load.hasInputs && !load.hasFeeders) {
+			continue
+		}
+
+		current := load.feeders
+		if load.hasInputs {
+			current = load.inputs
+		}
+		percent := math.Round(current / *capacity * 1000) / 10
+
+		row := models.SectionUtilization{
+			RuID:         key.ruID,
+			VoltageLevel: key.voltageLevel,
+			Section:      key.section,
+			CurrentA:     current,
+			CapacityA:    *capacity,
+			HeadroomA:    *capacity - current,
+			Percent:      percent,
+			Band:         models.UtilizationNormal,
+			BandSince:    now,
+			UpdatedAt:    now,
+		}
+		if prev, ok := prevByKey[key]; ok {
+			row.Band, row.BandSince = prev.Band, prev.BandSince
+		}
+
+		band := utilizationBand(percent, warning, critical, row.Band)
+		if models.UtilizationBandRank[band] > models.UtilizationBandRank[row.Band] {
+			event, err := newEvent(models.EventCapacityOverload, key.ruID, models.CapacityOverloadPayload{
+				RuName:       ru.Name,
+				VoltageLevel: key.voltageLevel,
+				Section:      key.section,
+				CurrentA:     current,
+				CapacityA:    *capacity,
+				Percent:      percent,
+				Band:         band,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		if band != row.Band {
+			row.Band, row.BandSince = band, now
+		}
+		rows = append(rows, row)
+	}
+
+	return s.capacityRepo.ReplaceUtilizations(rows, events)
+}
+
+// utilizationBand - полоса загрузки; возврат в более низкую полосу - только когда загрузка
+// опустилась ниже порога больше чем на utilizationHysteresis
+func utilizationBand(percent, warning, critical float64, prev models.UtilizationBand) models.UtilizationBand {
+	bandFor := func(p float64) models.UtilizationBand {
+		switch {
+		case p >= critical:
+			return models.UtilizationCritical
+		case p >= warning:
+			return models.UtilizationWarning
+		}
+		return models.UtilizationNormal
+	}
+	band := bandFor(percent)
+	if models.UtilizationBandRank[band] < models.UtilizationBandRank[prev] {
+		band = bandFor(percent + utilizationHysteresis)
+	}
+	return band
+}
+
+// latestCurrents - последний минутный ток ячеек, полученный по телеметрии
+func (s *CapacityService) latestCurrents(now time.Time) (map[int]float64, error) {
+	rollups, err := s.measurementRepo.GetRecentRollups(models.Resolution1m, []models.MeasurementMetric{models.MetricCurrent},
+		now.Add(-utilizationFreshness), now)
+	if err != nil {
+		return nil, err
+	}
+	currents := make(map[int]float64, len(rollups))
+	for _, rollup := range rollups {
+		currents[rollup.CellID] = rollup.Avg
+	}
+	return currents, nil
+}
+
+// Ranking - РУ, доступные пользователю, по загрузке самой нагруженной секции
+func (s *CapacityService) Ranking(actor models.Actor, query models.UtilizationQuery) ([]models.RuUtilization, error) {
+	rus, err := s.ruService.GetVisibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	ruIDs := make([]string, len(rus))
+	for i, ru := range rus {
+		ruIDs[i] = ru.ID
+	}
+	rows, err := s.capacityRepo.GetUtilizations(ruIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string][]models.SectionUtilization{}
+	for _, row := range rows {
+		sections[row.RuID] = append(sections[row.RuID], row)
+	}
+
+	ranking := []models.RuUtilization{}
+	for _, ru := range rus {
+		ruSections := sections[ru.ID]
+		if len(ruSections) == 0 {
+			continue
+		}
+		item := models.RuUtilization{
+			RuID:         ru.ID,
+			RuName:       ru.Name,
+			SubstationID: ru.SubstationID,
+			MinHeadroomA: math.Inf(1),
+			Band:         models.UtilizationNormal,
+			Sections:     ruSections,
+		}
+		for _, section := range ruSections {
+			item.MaxPercent = math.Max(item.MaxPercent, section.Percent)
+			item.MinHeadroomA = math.Min(item.MinHeadroomA, section.HeadroomA)
+			if models.UtilizationBandRank[section.Band] > models.UtilizationBandRank[item.Band] {
+				item.Band = section.Band
+			}
+		}
+		ranking = append(ranking, item)
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if query.Order == "asc" {
+			return ranking[i].MaxPercent < ranking[j].MaxPercent
+		}
+		return ranking[i].MaxPercent > ranking[j].MaxPercent
+	})
+	return ranking, nil
+}
+
+// SetCapacity - допустимый ток шин сторон РУ; строковые поля паспорта обновляются вместе с ним
+func (s *CapacityService) SetCapacity(ruID string, req *models.SetCapacityRequest) (*models.RUInfo, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	if req.MaxCapacityHighA != nil {
+		ru.MaxCapacityHighA = req.MaxCapacityHighA
+		ru.MaxCapacityHigh = utils.FormatAmperes(*req.MaxCapacityHighA)
+	}
+	if req.MaxCapacityLowA != nil {
+		ru.MaxCapacityLowA = req.MaxCapacityLowA
+		ru.MaxCapacityLow = utils.FormatAmperes(*req.MaxCapacityLowA)
+	}
+	ru.UpdatedAt = time.Now()
+
+	if err := s.ruRepo.UpdateRu(ru); err != nil {
+		return nil, err
+	}
+	return ru, nil
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+func (s *RuService) proposeCellInfoChange(cell *models.Cell, req *models.UpdateCellInfoRequest, actor models.Actor) (*models.CellInfoChange, error) {
+	now := time.Now()
+	change := &models.CellInfoChange{
+		ID:                  utils.NewID(models.IDPrefixCellChange),
+		CellID:              cell.ID,
+		RuID:                cell.RuID,
+		PreviousName:        cell.Name,
+		PreviousDescription: cell.Description,
+		PreviousVoltage:     cell.Voltage,
+		Name:                req.Name,
+		Description:         req.Description,
+		Voltage:             req.Voltage,
+		State:               models.ChangePending,
+		RequestedBy:         actor.Email,
+		RequestedAt:         now,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	change.BuildDiff()
+	if len(change.Diff) == 0 {
+		return nil, ErrCellChangeEmpty
+	}
+
+	event, err := newEvent(models.EventApprovalRequested, cell.RuID, models.ApprovalRequestedPayload{
+		Kind:        models.ApprovalCellChange,
+		RefID:       change.ID,
+		CellID:      cell.ID,
+		CellNumber:  cell.Number,
+		RequestedBy: actor.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.changeRepo.Create(change, event); err != nil {
+		return nil, fmt.Errorf("failed to propose cell change: %w", err)
+	}
+	return change, nil
+}
+
+// GetCellChanges - очередь изменений паспортных данных ячеек РУ организации пользователя
+func (s *RuService) GetCellChanges(actor models.Actor, state, ruID string) ([]models.CellInfoChange, error) {
+	changes, err := s.changeRepo.GetChanges(state, ruID, actor.OrganizationScope())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell changes: %w", err)
+	}
+	for i := range changes {
+		changes[i].BuildDiff()
+	}
+	return changes, nil
+}
+
+// CellChangeTasks - изменения паспортных данных ячеек РУ организации, ожидающие решения
+// (для инженеров и администраторов, которые их одобряют)
+func (s *RuService) CellChangeTasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	actor := models.UserActor(user)
+	if !actor.IsElevated() {
+		return nil, nil
+	}
+	changes, err := s.changeRepo.GetPending(actor.OrganizationScope())
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.Task, 0, len(changes))
+	for _, change := range changes {
+		tasks = append(tasks, models.Task{
+			Type:  models.TaskTypeApproval,
+			RefID: change.ID,
+			Title: i18n.T(lang, "task.approval.cell_change", change.PreviousName, change.RequestedBy),
+			RuID:  change.RuID,
+		})
+	}
+	return tasks, nil
+}
+
+// ApproveCellChange - одобряет изменение и применяет его к ячейке. Если ячейку успели
+// изменить после запроса, изменение не применяется, чтобы не затереть чужую правку.
+func (s *RuService) ApproveCellChange(changeID string, req *models.ReviewCellChangeRequest, actor models.Actor) (*models.CellInfoChange, error) {
+	change, err := s.getPendingChange(actor, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	cell, err := s.getCell(change.RuID, change.CellID)
+	if err != nil {
+		return nil, err
+	}
+	if cell.Name != change.PreviousName || cell.Description != change.PreviousDescription || cell.Voltage != change.PreviousVoltage {
+		return nil, ErrCellChangeStale
+	}
+
+	now := time.Now()
+	previous := models.NewCellRevision(cell)
+	cell.Name = change.Name
+	cell.Description = change.Description
+	cell.Voltage = change.Voltage
+	cell.UpdatedAt = now
+
+	return s.reviewChange(change, models.ChangeApproved, req, actor, cell, previous, now)
+}
+
+func (s *RuService) RejectCellChange(changeID string, req *models.ReviewCellChangeRequest, actor models.Actor) (*models.CellInfoChange, error) {
+	change, err := s.getPendingChange(actor, changeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.reviewChange(change, models.ChangeRejected, req, actor, nil, models.CellRevision{}, time.Now())
+}
+
+// getPendingChange - ожидающее решения изменение ячейки РУ организации пользователя;
+// чужое не отличается от несуществующего
+func (s *RuService) getPendingChange(actor models.Actor, changeID string) (*models.CellInfoChange, error) {
+	change, err := s.changeRepo.GetByID(utils.NormalizeID(models.IDPrefixCellChange, changeID), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellChangeNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell change: %w", err)
+	}
+	if change.State != models.ChangePending {
+		return nil, ErrCellChangeReviewed
+	}
+	return change, nil
+}
+
+func (s *RuService) reviewChange(change *models.CellInfoChange, state models.ChangeState, req *models.ReviewCellChangeRequest, actor models.Actor, cell *models.Cell, previous models.CellRevision, now time.Time) (*models.CellInfoChange, error) {
+	change.State = state
+	change.ReviewedBy = &actor.Email
+	change.ReviewedAt = &now
+	change.ReviewComment = req.Comment
+	change.UpdatedAt = now
+
+	// Ревизия записывается от имени автора изменения; одобривший виден в самом изменении
+	meta := models.CellRevision{Reason: models.RevisionApproval, ChangedBy: change.RequestedBy}
+	reviewed, err := s.changeRepo.Review(change, cell, previous, meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review cell change: %w", err)
+	}
+	if !reviewed {
+		return nil, ErrCellChangeReviewed
+	}
+
+	change.BuildDiff()
+	return change, nil
+}
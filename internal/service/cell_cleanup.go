@@ -0,0 +1,184 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+// GetCellReport - дубликаты ячеек и ячейки несуществующих РУ без изменений в базе
+func (s *MaintenanceService) GetCellReport(ruID string) (*models.CellCleanupReport, error) {
+	return s.CleanupCells(&models.CellCleanupRequest{RuID: ruID, DryRun: true}, "")
+}
+
+// CleanupCells - находит дубликаты ячеек (одинаковые номер и уровень напряжения в РУ) и
+// ячейки несуществующих РУ и обрабатывает их выбранными действиями. Каждая ячейка
+// сливается или удаляется своей транзакцией: сбой на одной не откатывает остальные.
+// Ячейки с действующим замком или командой телеуправления не трогаются.
+func (s *MaintenanceService) CleanupCells(req *models.CellCleanupRequest, requestedBy string) (*models.CellCleanupReport, error) {
+	duplicates, err := s.maintenanceRepo.FindDuplicateCells(req.RuID)
+	if err != nil {
+		return nil, err
+	}
+	orphans, err := s.maintenanceRepo.FindOrphanCells(req.RuID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(duplicates)+len(orphans))
+	for _, cell := range duplicates {
+		ids = append(ids, cell.ID)
+	}
+	for _, cell := range orphans {
+		ids = append(ids, cell.ID)
+	}
+	references, err := s.maintenanceRepo.CountCellReferences(ids)
+	if err != nil {
+		return nil, err
+	}
+	blockers, err := s.maintenanceRepo.GetCellBlockers(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.CellCleanupReport{
+		CheckedAt:  time.Now(),
+		RuID:       req.RuID,
+		DryRun:     req.DryRun,
+		Duplicates: []models.DuplicateCellGroup{},
+		Orphans:    []models.CellFinding{},
+	}
+	removed := map[int]models.CellCleanupOutcome{}
+
+	for start := 0; start < len(duplicates); {
+		end := start + 1
+		for end < len(duplicates) && sameCellSlot(duplicates[start], duplicates[end]) {
+			end++
+		}
+		group := newDuplicateGroup(duplicates[start:end], references)
+		for i := range group.Cells {
+			finding := &group.Cells[i]
+			if req.Duplicates == "" {
+				continue
+			}
+			if finding.ID == group.KeepID {
+				finding.Outcome = models.CellCleanupKept
+				continue
+			}
+			s.cleanupCell(report, finding, req.Duplicates, group.KeepID, blockers[finding.ID], req.DryRun)
+			if finding.Outcome != models.CellCleanupSkipped {
+				removed[finding.ID] = finding.Outcome
+			}
+		}
+		report.Duplicates = append(report.Duplicates, group)
+		start = end
+	}
+
+	for _, cell := range orphans {
+		finding := newCellFinding(cell, references)
+		if outcome, ok := removed[cell.ID]; ok {
+			finding.Outcome = outcome
+		} else if req.Orphans != "" {
+			s.cleanupCell(report, &finding, req.Orphans, 0, blockers[cell.ID], req.DryRun)
+		}
+		report.Orphans = append(report.Orphans, finding)
+	}
+
+	if !req.DryRun && report.Merged+report.Deleted > 0 {
+		log.Printf("🧹 Cell cleanup by %s: merged %d, deleted %d, skipped %d", requestedBy, report.Merged, report.Deleted, report.Skipped)
+		if err := s.maintenanceRepo.EnsureCellNumberIndex(); err != nil {
+			log.Printf("⚠️ Failed to create unique cell number index: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// cleanupCell - сливает ячейку с keepID или удаляет ее, если на нее ничего не ссылается
+func (s *MaintenanceService) cleanupCell(report *models.CellCleanupReport, finding *models.CellFinding, action models.CellCleanupAction, keepID int, blocker string, dryRun bool) {
+	skip := func(reason string) {
+		finding.Outcome, finding.Reason = models.CellCleanupSkipped, reason
+		report.Skipped++
+	}
+	if blocker != "" {
+		skip(blocker)
+		return
+	}
+
+	switch action {
+	case models.CellCleanupMerge:
+		if !dryRun {
+			if err := s.maintenanceRepo.MergeCell(finding.ID, keepID); err != nil {
+				log.Printf("⚠️ Cell cleanup: %v", err)
+				skip(models.CellSkipFailed)
+				return
+			}
+		}
+		finding.Outcome = models.CellCleanupMerged
+		report.Merged++
+	case models.CellCleanupDelete:
+		for _, count := range finding.References {
+			if count > 0 {
+				skip(models.CellSkipReferenced)
+				return
+			}
+		}
+		if !dryRun {
+			deleted, err := s.maintenanceRepo.DeleteUnreferencedCell(finding.ID)
+			if err != nil {
+				log.Printf("⚠️ Cell cleanup: %v", err)
+				skip(models.CellSkipFailed)
+				return
+			}
+			if !deleted {
+				skip(models.CellSkipReferenced)
+				return
+			}
+		}
+		finding.Outcome = models.CellCleanupDeleted
+		report.Deleted++
+	}
+}
+
+// newDuplicateGroup - группа дубликатов; остается ячейка с наибольшим числом ссылок,
+// при равенстве - самая старая (ячейки группы упорядочены по ID)
+func newDuplicateGroup(cells []models.Cell, references map[int]map[string]int64) models.DuplicateCellGroup {
+	group := models.DuplicateCellGroup{
+		RuID:         cells[0].RuID,
+		Number:       cells[0].Number,
+		VoltageLevel: cells[0].VoltageLevel,
+		Cells:        make([]models.CellFinding, len(cells)),
+	}
+	best := int64(-1)
+	for i, cell := range cells {
+		group.Cells[i] = newCellFinding(cell, references)
+		var total int64
+		for _, count := range references[cell.ID] {
+			total += count
+		}
+		if total > best {
+			best, group.KeepID = total, cell.ID
+		}
+	}
+	return group
+}
+
+func newCellFinding(cell models.Cell, references map[int]map[string]int64) models.CellFinding {
+	refs := references[cell.ID]
+	if refs == nil {
+		refs = map[string]int64{}
+	}
+	return models.CellFinding{
+		ID:           cell.ID,
+		RuID:         cell.RuID,
+		Number:       cell.Number,
+		VoltageLevel: cell.VoltageLevel,
+		Status:       cell.Status,
+		CreatedAt:    cell.CreatedAt,
+		References:   refs,
+	}
+}
+
+// sameCellSlot - одинаковые РУ, номер и уровень напряжения
+func sameCellSlot(a, b models.Cell) bool {
+	return a.RuID == b.RuID && a.Number == b.Number && a.VoltageLevel == b.VoltageLevel
+}
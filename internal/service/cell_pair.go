@@ -0,0 +1,136 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// GetPairedCells - ячейки РУ попарно для двусторонней схемы ТП. Строки идут по секциям
+// шин и порядковым номерам ячеек; ячейка без пары (или с парой, которой нет в РУ)
+// занимает строку одна.
+func (s *RuService) GetPairedCells(ruID string) (*models.PairedCells, error) {
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+
+	byID := make(map[int]*models.Cell, len(cells))
+	for i := range cells {
+		byID[cells[i].ID] = &cells[i]
+	}
+	result := &models.PairedCells{RuID: ruID, Pairs: []models.CellPair{}}
+	placed := map[int]bool{}
+	for i := range cells {
+		cell := &cells[i]
+		if placed[cell.ID] {
+			continue
+		}
+		placed[cell.ID] = true
+		pair := models.CellPair{}
+		setPairSide(&pair, cell)
+		if cell.PairedCellID != nil {
+			if other, ok := byID[*cell.PairedCellID]; ok && !placed[other.ID] && other.VoltageLevel != cell.VoltageLevel {
+				placed[other.ID] = true
+				setPairSide(&pair, other)
+			}
+		}
+		if pair.High == nil || pair.Low == nil {
+			result.Unpaired++
+		}
+		result.Pairs = append(result.Pairs, pair)
+	}
+
+	sort.SliceStable(result.Pairs, func(i, j int) bool {
+		a, b := pairLead(result.Pairs[i]), pairLead(result.Pairs[j])
+		sa, sb := busSectionOrder(a), busSectionOrder(b)
+		if sa != sb {
+			return sa < sb
+		}
+		na, okA := models.CellSequence(a.Number)
+		nb, okB := models.CellSequence(b.Number)
+		if okA != okB {
+			return okA
+		}
+		if okA && na != nb {
+			return na < nb
+		}
+		return a.ID < b.ID
+	})
+	return result, nil
+}
+
+// PairCells - связывает ячейку с ячейкой противоположной стороны того же РУ.
+// Прежние пары обеих ячеек разрываются.
+func (s *RuService) PairCells(ruID string, cellID int, req *models.SetCellPairRequest) (*models.Cell, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+	other, err := s.getCell(ruID, req.PairedCellID)
+	if err != nil {
+		if err == ErrCellNotFound {
+			return nil, ErrCellPairInvalid.WithDetails(map[string]interface{}{"pairedCellId": req.PairedCellID})
+		}
+		return nil, err
+	}
+	if !cell.VoltageLevel.Valid() || other.VoltageLevel != cell.VoltageLevel.Opposite() {
+		return nil, ErrCellPairInvalid.WithDetails(map[string]interface{}{
+			"voltageLevel":       cell.VoltageLevel,
+			"pairedVoltageLevel": other.VoltageLevel,
+		})
+	}
+
+	if err := s.ruRepo.SetCellPair(ruID, cell.ID, other.ID); err != nil {
+		return nil, err
+	}
+	return s.getCell(ruID, cellID)
+}
+
+// UnpairCell - разрывает пару ячейки; ячейка без пары возвращается без изменений
+func (s *RuService) UnpairCell(ruID string, cellID int) (*models.Cell, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+	if cell.PairedCellID == nil {
+		return cell, nil
+	}
+	if err := s.ruRepo.ClearCellPair(ruID, cellID); err != nil {
+		return nil, err
+	}
+	return s.getCell(ruID, cellID)
+}
+
+func setPairSide(pair *models.CellPair, cell *models.Cell) {
+	if cell.VoltageLevel == models.VoltageLevelLow {
+		pair.Low = cell
+		return
+	}
+	pair.High = cell
+}
+
+// pairLead - ячейка, по которой строка занимает место на схеме: сторона ВН, если есть
+func pairLead(pair models.CellPair) *models.Cell {
+	if pair.High != nil {
+		return pair.High
+	}
+	return pair.Low
+}
+
+// busSectionOrder - ячейки без секции шин - после всех секций
+func busSectionOrder(cell *models.Cell) int {
+	if cell.BusSection == nil {
+		return math.MaxInt
+	}
+	return *cell.BusSection
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	// cellTagPath - путь карточки ячейки в веб-интерфейсе: <PUBLIC_URL>/cells/<ruId>/<cellId>
+	cellTagPath = "/cells/"
+
+	cellTagDefaultSize = 256
+	cellTagMinSize     = 64
+	cellTagMaxSize     = 1024
+
+	// cellCardHistoryLimit - последние операции по ячейке в карточке
+	cellCardHistoryLimit = 20
+)
+
+// Форматы QR-кода
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// CellTagService - QR-коды для наклеек на ячейки и поиск ячейки по отсканированному коду.
+// Код содержит постоянную ссылку на карточку ячейки в веб-интерфейсе, поэтому телефон
+// без приложения тоже откроет нужную страницу.
+type CellTagService struct {
+	ruRepo     *repository.RuRepository
+	defectRepo *repository.DefectRepository
+	publicURL  string
+}
+
+func NewCellTagService(ruRepo *repository.RuRepository, defectRepo *repository.DefectRepository, publicURL string) *CellTagService {
+	return &CellTagService{
+		ruRepo:     ruRepo,
+		defectRepo: defectRepo,
+		publicURL:  strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// CellURL - постоянная ссылка на карточку ячейки
+func (s *CellTagService) CellURL(ruID string, cellID int) string {
+	return fmt.Sprintf("%s%s%s/%d", s.publicURL, cellTagPath, url.PathEscape(ruID), cellID)
+}
+
+// QRCode - изображение QR-кода ячейки в формате png или svg; size - сторона в пикселях
+func (s *CellTagService) QRCode(actor models.Actor, ruID string, cellID int, format string, size int) ([]byte, string, error) {
+	if size == 0 {
+		size = cellTagDefaultSize
+	}
+	if size < cellTagMinSize || size > cellTagMaxSize {
+		return nil, "", ErrQRSizeInvalid.WithDetails(map[string]interface{}{"min": cellTagMinSize, "max": cellTagMaxSize})
+	}
+	if _, _, err := s.cell(actor, ruID, cellID); err != nil {
+		return nil, "", err
+	}
+
+	code, err := qrcode.New(s.CellURL(ruID, cellID), qrcode.Medium)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	switch format {
+	case "", QRFormatPNG:
+		data, err := code.PNG(size)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to render QR code: %w", err)
+		}
+		return data, "image/png", nil
+	case QRFormatSVG:
+		return qrSVG(code.Bitmap(), size), "image/svg+xml", nil
+	}
+	return nil, "", ErrQRFormatInvalid.WithDetails(map[string]interface{}{"formats": []string{QRFormatPNG, QRFormatSVG}})
+}
+
+// qrSVG - векторный QR-код: по прямоугольнику на каждый темный модуль
+func qrSVG(bitmap [][]bool, size int) []byte {
+	var b strings.Builder
+	modules := len(bitmap)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return []byte(b.String())
+}
+
+// Lookup - карточка ячейки по отсканированному коду: полной ссылке из QR-кода
+// или ее окончанию "<ruId>/<cellId>"
+func (s *CellTagService) Lookup(actor models.Actor, code string) (*models.CellCard, error) {
+	ruID, cellID, ok := parseCellTag(code)
+	if !ok {
+		return nil, ErrCellTagInvalid
+	}
+
+	ru, cell, err := s.cell(actor, ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.ruRepo.GetHistoryByCell(ru.ID, cell.Number, cellCardHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+
+	defects, err := s.defectRepo.GetDefects(models.DefectFilter{RuID: ru.ID, CellID: &cell.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get defects: %w", err)
+	}
+	open := make([]models.Defect, 0, len(defects))
+	for _, defect := range defects {
+		if defect.Status != models.DefectStatusFixed {
+			open = append(open, defect)
+		}
+	}
+
+	return &models.CellCard{
+		URL:          s.CellURL(ru.ID, cell.ID),
+		RuID:         ru.ID,
+		RuName:       ru.Name,
+		SubstationID: ru.SubstationID,
+		Cell:         *cell,
+		History:      history,
+		Defects:      open,
+	}, nil
+}
+
+// cell - ячейка РУ, доступного пользователю; РУ чужой организации не отличается от несуществующего
+func (s *CellTagService) cell(actor models.Actor, ruID string, cellID int) (*models.RUInfo, *models.Cell, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrRuNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if !actor.CanAccessOrganization(ru.OrganizationID) {
+		return nil, nil, ErrRuNotFound
+	}
+
+	cell, err := s.ruRepo.GetCellByID(cellID, ru.ID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrCellNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	return ru, cell, nil
+}
+
+// parseCellTag - извлекает РУ и ячейку из ссылки .../cells/<ruId>/<cellId> или "<ruId>/<cellId>"
+func parseCellTag(code string) (string, int, bool) {
+	code = strings.TrimSpace(code)
+	if parsed, err := url.Parse(code); err == nil && parsed.Scheme != "" {
+		code = parsed.Path
+	}
+	if i := strings.LastIndex(code, cellTagPath); i >= 0 {
+		code = code[i+len(cellTagPath):]
+	}
+
+	ruPart, cellPart, ok := strings.Cut(strings.Trim(code, "/"), "/")
+	if !ok || ruPart == "" {
+		return "", 0, false
+	}
+	ruID, err := url.PathUnescape(ruPart)
+	if err != nil {
+		return "", 0, false
+	}
+	cellID, err := strconv.Atoi(cellPart)
+	if err != nil || cellID <= 0 {
+		return "", 0, false
+	}
+	return ruID, cellID, true
+}
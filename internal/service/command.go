@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// commandSelectTimeout - срок подтверждения выбора RTU и затем оператором
+	commandSelectTimeout = 30 * time.Second
+	// commandAckTimeout - срок квитанции RTU об исполнении
+	commandAckTimeout = 15 * time.Second
+	// commandFeedbackTimeout - срок, за который статус ячейки должен измениться
+	commandFeedbackTimeout = 60 * time.Second
+
+	commandSweepInterval = 2 * time.Second
+	commandsDefaultLimit = 100
+	commandsMaxLimit     = 1000
+)
+
+// CommandService - телеуправление ячейками по схеме select-before-operate.
+// Сервис ведет состояние и аудит команды; обмен с RTU выполняет шлюз телеметрии,
+// получающий события control.* и присылающий квитанции этапов.
+type CommandService struct {
+	commandRepo *repository.CommandRepository
+	ruRepo      *repository.RuRepository
+	lockRepo    *repository.CellLockRepository
+	deviceRepo  *repository.DeviceRepository
+}
+
+func NewCommandService(commandRepo *repository.CommandRepository, ruRepo *repository.RuRepository, lockRepo *repository.CellLockRepository, deviceRepo *repository.DeviceRepository) *CommandService {
+	return &CommandService{commandRepo: commandRepo, ruRepo: ruRepo, lockRepo: lockRepo, deviceRepo: deviceRepo}
+}
+
+// Select - выбор аппарата ячейки. Команда возможна только для ячейки, подключенной
+// к RTU/IED на связи, без замка LOTO и без другой незавершенной команды.
+func (s *CommandService) Select(ruID string, cellID int, req *models.SelectCommandRequest, actor models.Actor) (*models.ControlCommand, error) {
+	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+
+	lock, err := s.lockRepo.GetActiveLock(cell.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cell lock: %w", err)
+	}
+	if lock != nil {
+		return nil, ErrCellLocked.WithDetails(lock)
+	}
+
+	target := req.Action.TargetStatus()
+	if cell.Status == target {
+		return nil, ErrCommandNoop.WithDetails(map[string]interface{}{"status": cell.Status})
+	}
+
+	device, err := s.controlDevice(cell.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deadline := now.Add(commandSelectTimeout)
+	command := &models.ControlCommand{
+		ID:           utils.NewID(models.IDPrefixCommand),
+		RuID:         ruID,
+		CellID:       cell.ID,
+		CellNumber:   cell.Number,
+		DeviceID:     device.ID,
+		Action:       req.Action,
+		TargetStatus: target,
+		Reason:       req.Reason,
+		State:        models.CommandSelecting,
+		Deadline:     &deadline,
+		SelectedBy:   actor.Email,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Steps: []models.ControlCommandStep{
+			{State: models.CommandSelecting, By: actor.Email, Details: req.Reason, At: now},
+		},
+	}
+
+	event, err := commandEvent(models.EventControlSelect, command)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.commandRepo.Create(command, []models.OutboxEvent{event}); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrCommandInProgress
+		}
+		return nil, err
+	}
+	return command, nil
+}
+
+// controlDevice - устройство на связи, через которое управляется ячейка
+func (s *CommandService) controlDevice(cellID int) (*models.Device, error) {
+	devices, err := s.deviceRepo.GetControlDevices(cellID)
+	if err != nil {
+		return nil, err
+	}
+	if len(devices) == 0 {
+		return nil, ErrCommandNoDevice
+	}
+	for i := range devices {
+		if devices[i].Status == models.DeviceStatusOnline {
+			return &devices[i], nil
+		}
+	}
+	return nil, ErrCommandDeviceOffline.WithDetails(map[string]interface{}{"deviceId": devices[0].ID})
+}
+
+// Operate - подтверждение оператором выбранной команды и отправка на исполнение.
+// Для критичной ячейки, выбранной диспетчером, подтверждает другой сотрудник.
+func (s *CommandService) Operate(ruID, commandID string, actor models.Actor) (*models.ControlCommand, error) {
+	current, err := s.GetCommand(ruID, commandID)
+	if err != nil {
+		return nil, err
+	}
+	cell, err := s.ruRepo.GetCellByID(current.CellID, ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+
+	var lock *models.CellLock
+	command, err := s.commandRepo.Transition(current.ID, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+		now := time.Now()
+		if command.State != models.CommandSelected {
+			return nil, nil, ErrCommandState.WithDetails(map[string]interface{}{"state": command.State})
+		}
+		if command.Deadline != nil && now.After(*command.Deadline) {
+			return expireCommand(command, now, "operator confirmation timed out")
+		}
+		// Замок мог быть установлен после выбора: исполнять команду на заблокированной
+		// ячейке нельзя
+		var err error
+		if lock, err = s.lockRepo.GetActiveLock(command.CellID); err != nil {
+			return nil, nil, fmt.Errorf("failed to check cell lock: %w", err)
+		}
+		if lock != nil {
+			return expireCommand(command, now, "cell locked out")
+		}
+		if cell.IsCritical && command.SelectedBy == actor.Email && !actor.IsElevated() {
+			return nil, nil, ErrConfirmationSamePerson
+		}
+
+		deadline := now.Add(commandAckTimeout)
+		command.State = models.CommandOperating
+		command.OperatedBy = &actor.Email
+		command.Deadline = &deadline
+
+		event, err := commandEvent(models.EventControlOperate, command)
+		if err != nil {
+			return nil, nil, err
+		}
+		step := &models.ControlCommandStep{State: models.CommandOperating, By: actor.Email, At: now}
+		return step, []models.OutboxEvent{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if command.State == models.CommandExpired {
+		if lock != nil {
+			return nil, ErrCellLocked.WithDetails(lock)
+		}
+		return nil, ErrCommandExpired
+	}
+	return command, nil
+}
+
+// Cancel - отмена выбора до исполнения
+func (s *CommandService) Cancel(ruID, commandID string, actor models.Actor) (*models.ControlCommand, error) {
+	current, err := s.GetCommand(ruID, commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commandRepo.Transition(current.ID, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+		if command.State != models.CommandSelecting && command.State != models.CommandSelected {
+			return nil, nil, ErrCommandState.WithDetails(map[string]interface{}{"state": command.State})
+		}
+		return cancelCommand(command, time.Now(), actor.Email, nil)
+	})
+}
+
+// Ack - квитанция шлюза о результате выбора или исполнения в RTU
+func (s *CommandService) Ack(commandID string, req *models.CommandAckRequest, actor models.Actor) (*models.ControlCommand, error) {
+	id := utils.NormalizeID(models.IDPrefixCommand, commandID)
+	// Квитанция принимается только по командам РУ организации шлюза
+	if _, err := s.commandRepo.GetOrganizationCommand(id, actor.OrganizationScope()); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCommandNotFound
+		}
+		return nil, err
+	}
+	command, err := s.commandRepo.Transition(id, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+		expected, next, timeout := models.CommandSelecting, models.CommandSelected, commandSelectTimeout
+		if req.Stage == models.StageOperate {
+			expected, next, timeout = models.CommandOperating, models.CommandExecuted, commandFeedbackTimeout
+		}
+		if command.State != expected {
+			return nil, nil, ErrCommandState.WithDetails(map[string]interface{}{"state": command.State})
+		}
+
+		now := time.Now()
+		step := &models.ControlCommandStep{By: actor.Email, At: now}
+		if *req.Success {
+			deadline := now.Add(timeout)
+			command.State = next
+			command.Deadline = &deadline
+		} else {
+			reason := req.Error
+			if reason == "" {
+				reason = fmt.Sprintf("%s rejected by device", req.Stage)
+			}
+			finishCommand(command, models.CommandFailed, now, &reason)
+			step.Details = reason
+		}
+		step.State = command.State
+		return step, nil, nil
+	})
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCommandNotFound
+		}
+		return nil, err
+	}
+	if command.State == models.CommandExecuted {
+		if err := s.countOperation(command); err != nil {
+			return nil, err
+		}
+	}
+	return command, nil
+}
+
+// countOperation - учитывает коммутационную операцию исполненной команды. Если ячейка
+// уже сообщила целевой статус до квитанции, операция учтена при смене статуса.
+func (s *CommandService) countOperation(command *models.ControlCommand) error {
+	cell, err := s.ruRepo.GetCellByID(command.CellID, command.RuID)
+	if err != nil {
+		return fmt.Errorf("failed to get cell: %w", err)
+	}
+	if cell.Status == command.TargetStatus || !models.IsSwitchingOperation(cell.Status, command.TargetStatus) {
+		return nil
+	}
+	return s.ruRepo.IncrementOperationCount(cell.ID)
+}
+
+// HandleEvent - подписчик шины событий: проверяет обратную связь по статусу ячейки
+// для исполненной команды
+func (s *CommandService) HandleEvent(event *models.OutboxEvent) error {
+	var payload models.CellStatusChangedPayload
+	if err := decodePayload(event, &payload); err != nil {
+		return err
+	}
+
+	current, err := s.commandRepo.GetAwaitingFeedback(payload.CellID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = s.commandRepo.Transition(current.ID, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+		if command.State != models.CommandExecuted {
+			return nil, nil, nil
+		}
+		now := time.Now()
+		step := &models.ControlCommandStep{By: "feedback", Details: string(payload.Status), At: now}
+		if payload.Status == command.TargetStatus {
+			finishCommand(command, models.CommandCompleted, now, nil)
+		} else {
+			reason := fmt.Sprintf("cell reported status %s instead of %s", payload.Status, command.TargetStatus)
+			finishCommand(command, models.CommandFailed, now, &reason)
+		}
+		step.State = command.State
+		return step, nil, nil
+	})
+	return err
+}
+
+// Run - контроль сроков этапов команд; завершается при отмене контекста
+func (s *CommandService) Run(ctx context.Context) {
+	ticker := time.NewTicker(commandSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.expireOverdue(time.Now()); err != nil {
+				log.Printf("⚠️ Failed to expire control commands: %v", err)
+			}
+		}
+	}
+}
+
+func (s *CommandService) expireOverdue(now time.Time) error {
+	overdue, err := s.commandRepo.GetOverdue(now)
+	if err != nil {
+		return err
+	}
+	for _, current := range overdue {
+		_, err := s.commandRepo.Transition(current.ID, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+			if command.State.Final() || command.Deadline == nil || !now.After(*command.Deadline) {
+				return nil, nil, nil
+			}
+			switch command.State {
+			case models.CommandSelecting:
+				return expireCommand(command, now, "no select confirmation from device")
+			case models.CommandSelected:
+				return expireCommand(command, now, "operator confirmation timed out")
+			case models.CommandOperating:
+				reason := "no operate confirmation from device"
+				finishCommand(command, models.CommandFailed, now, &reason)
+			case models.CommandExecuted:
+				reason := "cell status did not change"
+				finishCommand(command, models.CommandFailed, now, &reason)
+			}
+			step := &models.ControlCommandStep{State: command.State, By: "system", Details: *command.Error, At: now}
+			return step, nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CommandService) GetCommand(ruID, commandID string) (*models.ControlCommand, error) {
+	command, err := s.commandRepo.GetByID(ruID, utils.NormalizeID(models.IDPrefixCommand, commandID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCommandNotFound
+		}
+		return nil, fmt.Errorf("failed to get control command: %w", err)
+	}
+	return command, nil
+}
+
+// GetCommands - журнал команд телеуправления РУ
+func (s *CommandService) GetCommands(ruID string, filter models.CommandFilter) ([]models.ControlCommand, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = commandsDefaultLimit
+	}
+	if limit > commandsMaxLimit {
+		limit = commandsMaxLimit
+	}
+	commands, err := s.commandRepo.GetCommands(ruID, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// GetPending - команды РУ организации пользователя, ожидающие обмена с RTU, для опроса шлюзом
+func (s *CommandService) GetPending(actor models.Actor, deviceID string) ([]models.ControlCommand, error) {
+	if deviceID != "" {
+		deviceID = utils.NormalizeID(models.IDPrefixDevice, deviceID)
+	}
+	commands, err := s.commandRepo.GetPending(deviceID, actor.OrganizationScope())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending control commands: %w", err)
+	}
+	return commands, nil
+}
+
+// expireCommand - истек срок выбора; шлюз снимает выбор в RTU по событию control.cancel
+func expireCommand(command *models.ControlCommand, now time.Time, reason string) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+	finishCommand(command, models.CommandExpired, now, &reason)
+	event, err := commandEvent(models.EventControlCancel, command)
+	if err != nil {
+		return nil, nil, err
+	}
+	step := &models.ControlCommandStep{State: models.CommandExpired, By: "system", Details: reason, At: now}
+	return step, []models.OutboxEvent{event}, nil
+}
+
+// cancelCommand - отмена выбора; шлюз снимает выбор в RTU по событию control.cancel
+func cancelCommand(command *models.ControlCommand, now time.Time, by string, reason *string) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+	finishCommand(command, models.CommandCancelled, now, reason)
+	event, err := commandEvent(models.EventControlCancel, command)
+	if err != nil {
+		return nil, nil, err
+	}
+	step := &models.ControlCommandStep{State: models.CommandCancelled, By: by, At: now}
+	if reason != nil {
+		step.Details = *reason
+	}
+	return step, []models.OutboxEvent{event}, nil
+}
+
+func finishCommand(command *models.ControlCommand, state models.CommandState, now time.Time, reason *string) {
+	command.State = state
+	command.Deadline = nil
+	command.CompletedAt = &now
+	command.Error = reason
+}
+
+func commandEvent(eventType models.DomainEventType, command *models.ControlCommand) (models.OutboxEvent, error) {
+	return newEvent(eventType, command.RuID, models.ControlCommandPayload{
+		CommandID:  command.ID,
+		DeviceID:   command.DeviceID,
+		CellID:     command.CellID,
+		CellNumber: command.CellNumber,
+		Action:     command.Action,
+	})
+}
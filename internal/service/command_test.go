@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+func TestCommandLockedAfterSelect(t *testing.T) {
+	operator := models.Actor{UserID: "user-1", Email: "op@example.com", Role: models.RoleEngineer}
+
+	tests := []struct {
+		name string
+		// lock - как ячейка оказалась заблокированной после выбора команды
+		lock      func(t *testing.T, s *RuService, cellID int)
+		wantState models.CommandState
+	}{
+		{
+			// Установка замка отменяет выбор, подтверждать уже нечего
+			name: "placing a lock cancels the selection",
+			lock: func(t *testing.T, s *RuService, cellID int) {
+				if _, err := s.PlaceCellLock("ru-1", cellID, &models.PlaceCellLockRequest{Reason: "repair"}, operator); err != nil {
+					t.Fatalf("place lock: %v", err)
+				}
+			},
+			wantState: models.CommandCancelled,
+		},
+		{
+			// Замок, появившийся в обход отмены, проверяется при подтверждении
+			name: "operate rechecks the lock",
+			lock: func(t *testing.T, s *RuService, cellID int) {
+				lock := models.CellLock{ID: "lock-1", CellID: cellID, RuID: "ru-1", Reason: "repair", PlacedBy: operator.Email, PlacedAt: time.Now()}
+				if err := s.lockRepo.CreateLock(&lock, lockRecord(&models.Cell{RuID: "ru-1"}, "lock", operator, nil, nil, time.Now())); err != nil {
+					t.Fatalf("create lock: %v", err)
+				}
+			},
+			wantState: models.CommandExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.RUInfo{}, &models.Cell{}, &models.OperationRecord{}, &models.CellLock{},
+				&models.ControlCommand{}, &models.ControlCommandStep{}, &models.OutboxEvent{}, &models.HashChain{})
+			cell := models.Cell{RuID: "ru-1", Number: "1", Name: "Feeder", Type: models.CellTypeInput, Status: models.CellStatusOFF}
+			if err := db.Create(&models.RUInfo{ID: "ru-1", Name: "RU"}).Error; err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+			if err := db.Create(&cell).Error; err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+			deadline := time.Now().Add(time.Minute)
+			command := models.ControlCommand{
+				ID: "cmd-1", RuID: "ru-1", CellID: cell.ID, Action: models.CommandClose, TargetStatus: models.CellStatusON,
+				State: models.CommandSelected, Deadline: &deadline, SelectedBy: operator.Email,
+			}
+			if err := db.Create(&command).Error; err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+
+			ruRepo := repository.NewRuRepository(db)
+			lockRepo := repository.NewCellLockRepository(db)
+			commandRepo := repository.NewCommandRepository(db)
+			ruService := NewRuService(ruRepo, lockRepo, nil, nil, nil, commandRepo, nil, nil, nil, nil)
+			commands := NewCommandService(commandRepo, ruRepo, lockRepo, nil)
+
+			tt.lock(t, ruService, cell.ID)
+
+			if _, err := commands.Operate("ru-1", "cmd-1", operator); err == nil {
+				t.Fatal("Operate on a locked cell succeeded")
+			} else if tt.wantState == models.CommandExpired && !errors.Is(err, ErrCellLocked) {
+				t.Errorf("Operate error = %v, want ErrCellLocked", err)
+			}
+			stored, err := commands.GetCommand("ru-1", "cmd-1")
+			if err != nil {
+				t.Fatalf("get command: %v", err)
+			}
+			if stored.State != tt.wantState || stored.Error == nil || *stored.Error != "cell locked out" {
+				t.Errorf("command state = %s, error = %v; want %s with lock reason", stored.State, stored.Error, tt.wantState)
+			}
+			var events int64
+			db.Model(&models.OutboxEvent{}).Where("type = ?", models.EventControlCancel).Count(&events)
+			if events != 1 {
+				t.Errorf("control.cancel events = %d, want 1", events)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// compatFlushInterval - как часто приращения счетчиков сохраняются в базу
+const compatFlushInterval = 30 * time.Second
+
+// CompatService - учет клиентов, которые еще присылают даты строками в старом формате.
+// Запрос только увеличивает счетчик в памяти; приращения периодически прибавляются к
+// счетчикам в базе, общим для всех экземпляров.
+type CompatService struct {
+	compatRepo *repository.CompatRepository
+
+	mu      sync.Mutex
+	pending map[string]*models.DateFormatUsage
+}
+
+func NewCompatService(compatRepo *repository.CompatRepository) *CompatService {
+	return &CompatService{compatRepo: compatRepo, pending: make(map[string]*models.DateFormatUsage)}
+}
+
+// RecordDateFormat - учитывает формат, в котором клиент прислал поле даты
+func (s *CompatService) RecordDateFormat(client, field string, legacy bool) {
+	if client == "" {
+		client = "unknown"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := client + "|" + field
+	entry, ok := s.pending[key]
+	if !ok {
+		entry = &models.DateFormatUsage{Client: client, Field: field}
+		s.pending[key] = entry
+	}
+	if legacy {
+		entry.Legacy++
+	} else {
+		entry.Modern++
+	}
+	entry.LastSeen = time.Now()
+}
+
+// Run - периодически сохраняет приращения; при отмене контекста сохраняет остаток
+func (s *CompatService) Run(ctx context.Context) {
+	ticker := time.NewTicker(compatFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Flush(); err != nil {
+				log.Printf("⚠️ Date format usage flush failed: %v", err)
+			}
+			return
+		case <-ticker.C:
+			if err := s.Flush(); err != nil {
+				log.Printf("⚠️ Date format usage flush failed: %v", err)
+			}
+		}
+	}
+}
+
+// Flush - прибавляет накопленные приращения к счетчикам в базе. При ошибке приращения
+// возвращаются в память и сохраняются следующей попыткой.
+func (s *CompatService) Flush() error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*models.DateFormatUsage)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	usages := make([]models.DateFormatUsage, 0, len(pending))
+	for _, entry := range pending {
+		usages = append(usages, *entry)
+	}
+	if err := s.compatRepo.AddDateFormatUsage(usages); err != nil {
+		s.mu.Lock()
+		for key, entry := range pending {
+			if current, ok := s.pending[key]; ok {
+				entry.Legacy += current.Legacy
+				entry.Modern += current.Modern
+				if current.LastSeen.After(entry.LastSeen) {
+					entry.LastSeen = current.LastSeen
+				}
+			}
+			s.pending[key] = entry
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// DateFormatStats - счетчики всех экземпляров с учетом еще не сохраненных приращений
+// этого экземпляра, клиенты с наибольшим числом старых форматов - первыми
+func (s *CompatService) DateFormatStats() ([]models.DateFormatUsage, error) {
+	if err := s.Flush(); err != nil {
+		return nil, err
+	}
+	return s.compatRepo.GetDateFormatUsage()
+}
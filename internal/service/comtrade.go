@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// MaxComtradeFileSize - предельный размер одного файла осциллограммы
+const MaxComtradeFileSize = 64 << 20
+
+// ComtradeUpload - загруженный файл записи COMTRADE
+type ComtradeUpload struct {
+	Kind     models.ComtradeFileKind
+	FileName string
+	Data     []byte
+}
+
+type ComtradeService struct {
+	comtradeRepo *repository.ComtradeRepository
+	faultRepo    *repository.FaultRepository
+}
+
+func NewComtradeService(comtradeRepo *repository.ComtradeRepository, faultRepo *repository.FaultRepository) *ComtradeService {
+	return &ComtradeService{comtradeRepo: comtradeRepo, faultRepo: faultRepo}
+}
+
+// Upload - прикрепляет осциллограмму к отключению. Принимается пара .cfg + .dat
+// (с необязательными .hdr и .inf) или однофайловая запись .cff.
+func (s *ComtradeService) Upload(ruID, faultID string, uploads []ComtradeUpload, actor models.Actor) (*models.ComtradeRecord, error) {
+	fault, err := s.faultRepo.GetByID(ruID, utils.NormalizeID(models.IDPrefixFault, faultID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrFaultNotFound
+		}
+		return nil, fmt.Errorf("failed to get fault: %w", err)
+	}
+
+	files := make(map[models.ComtradeFileKind]ComtradeUpload, len(uploads))
+	for _, upload := range uploads {
+		if len(upload.Data) == 0 || len(upload.Data) > MaxComtradeFileSize {
+			return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"file": upload.FileName, "reason": "size"})
+		}
+		files[upload.Kind] = upload
+	}
+
+	var cfgData, datData []byte
+	if cff, ok := files[models.ComtradeCFF]; ok {
+		if len(files) > 1 {
+			return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"reason": "cff must be uploaded alone"})
+		}
+		if cfgData, datData, err = utils.SplitComtradeCFF(cff.Data); err != nil {
+			return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"reason": err.Error()})
+		}
+	} else {
+		cfg, hasCfg := files[models.ComtradeCFG]
+		dat, hasDat := files[models.ComtradeDAT]
+		if !hasCfg || !hasDat {
+			return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"reason": "cfg and dat files are required"})
+		}
+		cfgData, datData = cfg.Data, dat.Data
+	}
+	if len(datData) == 0 {
+		return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"reason": "data file is empty"})
+	}
+
+	config, err := utils.ParseComtradeConfig(cfgData)
+	if err != nil {
+		return nil, ErrComtradeInvalid.WithDetails(map[string]interface{}{"reason": err.Error()})
+	}
+
+	record := &models.ComtradeRecord{
+		ID:              utils.NewID(models.IDPrefixComtrade),
+		RuID:            fault.RuID,
+		FaultID:         fault.ID,
+		StationName:     config.StationName,
+		DeviceID:        config.DeviceID,
+		RevisionYear:    config.RevisionYear,
+		AnalogChannels:  len(config.Analog),
+		DigitalChannels: len(config.Digital),
+		LineFrequency:   config.LineFrequency,
+		SamplingRate:    config.SampleRates[0].Rate,
+		SampleCount:     config.SampleCount(),
+		DataFormat:      config.DataFormat,
+		StartTime:       config.StartTime,
+		TriggerTime:     config.TriggerTime,
+		UploadedBy:      actor.Email,
+		CreatedAt:       time.Now(),
+	}
+	for _, channel := range config.Analog {
+		record.Channels = append(record.Channels, comtradeChannel(models.ComtradeAnalog, channel))
+	}
+	for _, channel := range config.Digital {
+		record.Channels = append(record.Channels, comtradeChannel(models.ComtradeDigital, channel))
+	}
+	for kind, upload := range files {
+		record.Files = append(record.Files, models.ComtradeFile{
+			Kind:     kind,
+			FileName: upload.FileName,
+			Size:     int64(len(upload.Data)),
+			Data:     upload.Data,
+		})
+	}
+
+	if err := s.comtradeRepo.Create(record); err != nil {
+		return nil, fmt.Errorf("failed to save COMTRADE record: %w", err)
+	}
+	return record, nil
+}
+
+// GetRecords - каталог осциллограмм РУ
+func (s *ComtradeService) GetRecords(ruID string, filter models.ComtradeFilter) ([]models.ComtradeRecord, error) {
+	if filter.FaultID != "" {
+		filter.FaultID = utils.NormalizeID(models.IDPrefixFault, filter.FaultID)
+	}
+	records, err := s.comtradeRepo.GetRecords(ruID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get COMTRADE records: %w", err)
+	}
+	return records, nil
+}
+
+// GetRecord - осциллограмма с каналами и перечнем файлов
+func (s *ComtradeService) GetRecord(ruID, recordID string) (*models.ComtradeRecord, error) {
+	record, err := s.comtradeRepo.GetByID(ruID, utils.NormalizeID(models.IDPrefixComtrade, recordID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrComtradeNotFound
+		}
+		return nil, fmt.Errorf("failed to get COMTRADE record: %w", err)
+	}
+	return record, nil
+}
+
+// GetFile - содержимое файла осциллограммы для скачивания
+func (s *ComtradeService) GetFile(ruID, recordID string, kind models.ComtradeFileKind) (*models.ComtradeFile, error) {
+	record, err := s.GetRecord(ruID, recordID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := s.comtradeRepo.GetFile(record.ID, kind)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrComtradeNotFound
+		}
+		return nil, fmt.Errorf("failed to get COMTRADE file: %w", err)
+	}
+	return file, nil
+}
+
+func comtradeChannel(channelType models.ComtradeChannelType, channel utils.ComtradeChannel) models.ComtradeChannel {
+	return models.ComtradeChannel{
+		Type:  channelType,
+		Index: channel.Index,
+		Name:  channel.Name,
+		Phase: channel.Phase,
+		Unit:  channel.Unit,
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// confirmationTTL - время, в течение которого второй сотрудник может подтвердить переключение
+const confirmationTTL = 10 * time.Minute
+
+func (s *RuService) requestConfirmation(cell *models.Cell, req *models.UpdateCellStatusRequest, actor models.Actor) (*models.StatusConfirmation, error) {
+	now := time.Now()
+	confirmation := &models.StatusConfirmation{
+		ID:          utils.NewID(models.IDPrefixStatusConfirmation),
+		CellID:      cell.ID,
+		RuID:        cell.RuID,
+		Status:      req.Status,
+		IsGrounded:  req.IsGrounded,
+		State:       models.ConfirmationPending,
+		RequestedBy: actor.Email,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(confirmationTTL),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	event, err := newEvent(models.EventApprovalRequested, cell.RuID, models.ApprovalRequestedPayload{
+		Kind:        models.ApprovalStatusConfirmation,
+		RefID:       confirmation.ID,
+		CellID:      cell.ID,
+		CellNumber:  cell.Number,
+		RequestedBy: actor.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.confirmationRepo.CreateReplacing(confirmation, event); err != nil {
+		return nil, fmt.Errorf("failed to request confirmation: %w", err)
+	}
+	return confirmation, nil
+}
+
+// GetPendingConfirmations - ожидающие подтверждения переключения ячейки
+func (s *RuService) GetPendingConfirmations(ruID string, cellID int) ([]models.StatusConfirmation, error) {
+	confirmations, err := s.confirmationRepo.GetPending(ruID, cellID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get confirmations: %w", err)
+	}
+
+	now := time.Now()
+	pending := make([]models.StatusConfirmation, 0, len(confirmations))
+	for _, confirmation := range confirmations {
+		if !confirmation.Expired(now) {
+			pending = append(pending, confirmation)
+		}
+	}
+	return pending, nil
+}
+
+// ConfirmationTasks - переключения критичных ячеек РУ организации, ожидающие второго
+// сотрудника; свои запросы пользователь подтвердить не может. Срок - истечение запроса.
+func (s *RuService) ConfirmationTasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	actor := models.UserActor(user)
+	confirmations, err := s.confirmationRepo.GetOpen(actor.OrganizationScope(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	for _, confirmation := range confirmations {
+		if confirmation.RequestedBy == user.Email {
+			continue
+		}
+		cellNumber := strconv.Itoa(confirmation.CellID)
+		if cell, err := s.ruRepo.GetCellByID(confirmation.CellID, confirmation.RuID); err == nil {
+			cellNumber = cell.Number
+		}
+		deadline := confirmation.ExpiresAt
+		tasks = append(tasks, models.Task{
+			Type:     models.TaskTypeApproval,
+			RefID:    confirmation.ID,
+			Title:    i18n.T(lang, "task.approval.confirmation", cellNumber, i18n.T(lang, "status.cell."+string(confirmation.Status)), confirmation.RequestedBy),
+			RuID:     confirmation.RuID,
+			Deadline: &deadline,
+		})
+	}
+	return tasks, nil
+}
+
+// ConfirmCellStatus - второй сотрудник подтверждает переключение критичной ячейки.
+// Подтверждающий должен отличаться от запросившего.
+func (s *RuService) ConfirmCellStatus(ruID string, cellID int, confirmationID string, actor models.Actor) (*models.Cell, error) {
+	confirmation, err := s.confirmationRepo.GetByID(utils.NormalizeID(models.IDPrefixStatusConfirmation, confirmationID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrConfirmationNotFound
+		}
+		return nil, fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if confirmation.RuID != ruID || confirmation.CellID != cellID || confirmation.State != models.ConfirmationPending {
+		return nil, ErrConfirmationNotFound
+	}
+
+	now := time.Now()
+	if confirmation.Expired(now) {
+		return nil, ErrConfirmationExpired
+	}
+	if confirmation.RequestedBy == actor.Email {
+		return nil, ErrConfirmationSamePerson
+	}
+
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNotLocked(cellID); err != nil {
+		return nil, err
+	}
+
+	confirmation.ConfirmedBy = &actor.Email
+	confirmation.ConfirmedAt = &now
+	confirmation.UpdatedAt = now
+	claimed, err := s.confirmationRepo.MarkConfirmed(confirmation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to confirm: %w", err)
+	}
+	if !claimed {
+		return nil, ErrConfirmationNotFound
+	}
+
+	req := &models.UpdateCellStatusRequest{Status: confirmation.Status, IsGrounded: confirmation.IsGrounded}
+	if err := s.applyCellStatus(cell, req); err != nil {
+		return nil, err
+	}
+	return cell, nil
+}
+
+// SetCellCritical - изменение признака критичной ячейки (администратор)
+func (s *RuService) SetCellCritical(ruID string, cellID int, critical bool, actor models.Actor) (*models.Cell, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := models.NewCellRevision(cell)
+	cell.IsCritical = critical
+	cell.UpdatedAt = time.Now()
+	meta := models.CellRevision{Reason: models.RevisionCritical, ChangedBy: actor.Email}
+	if err := s.revisionRepo.SaveCell(cell, previous, meta); err != nil {
+		return nil, fmt.Errorf("failed to update cell: %w", err)
+	}
+	return cell, nil
+}
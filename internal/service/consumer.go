@@ -0,0 +1,329 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// ConsumerService - реестр потребителей ОЭЗ и их привязка к отходящим ячейкам;
+// по привязкам определяется, кого затрагивает отключение ячеек РУ
+type ConsumerService struct {
+	consumerRepo *repository.ConsumerRepository
+	ruRepo       *repository.RuRepository
+}
+
+func NewConsumerService(consumerRepo *repository.ConsumerRepository, ruRepo *repository.RuRepository) *ConsumerService {
+	return &ConsumerService{consumerRepo: consumerRepo, ruRepo: ruRepo}
+}
+
+// GetConsumers - потребители организации пользователя; администратор установки видит всех
+func (s *ConsumerService) GetConsumers(actor models.Actor, filter models.ConsumerFilter) ([]models.Consumer, error) {
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() {
+		organizationID = ""
+	}
+	return s.consumerRepo.GetConsumers(organizationID, filter)
+}
+
+// GetConsumer - потребитель, доступный пользователю; чужая организация - как несуществующий
+func (s *ConsumerService) GetConsumer(actor models.Actor, id string) (*models.Consumer, error) {
+	consumer, err := s.consumerRepo.GetByID(utils.NormalizeID(models.IDPrefixConsumer, id))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrConsumerNotFound
+		}
+		return nil, err
+	}
+	if !actor.CanAccessOrganization(consumer.OrganizationID) {
+		return nil, ErrConsumerNotFound
+	}
+	return consumer, nil
+}
+
+func (s *ConsumerService) CreateConsumer(actor models.Actor, req *models.ConsumerRequest) (*models.Consumer, error) {
+	if err := s.checkContract(req.ContractNumber, ""); err != nil {
+		return nil, err
+	}
+
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() && req.OrganizationID != "" {
+		organizationID = req.OrganizationID
+	}
+
+	now := time.Now()
+	consumer := &models.Consumer{
+		ID:             utils.NewID(models.IDPrefixConsumer),
+		OrganizationID: organizationID,
+		Feeders:        []models.ConsumerFeeder{},
+		CreatedAt:      now,
+	}
+	applyConsumerRequest(consumer, req, now)
+
+	if err := s.consumerRepo.Save(consumer); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// UpdateConsumer - изменение реквизитов; организация потребителя не меняется
+func (s *ConsumerService) UpdateConsumer(actor models.Actor, id string, req *models.ConsumerRequest) (*models.Consumer, error) {
+	consumer, err := s.GetConsumer(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.ContractNumber != consumer.ContractNumber {
+		if err := s.checkContract(req.ContractNumber, consumer.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	applyConsumerRequest(consumer, req, time.Now())
+	if err := s.consumerRepo.Save(consumer); err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+func (s *ConsumerService) DeleteConsumer(actor models.Actor, id string) error {
+	consumer, err := s.GetConsumer(actor, id)
+	if err != nil {
+		return err
+	}
+	return s.consumerRepo.Delete(consumer.ID)
+}
+
+// AssignFeeder - подключение потребителя к отходящей ячейке РУ той же организации
+func (s *ConsumerService) AssignFeeder(actor models.Actor, id string, req *models.AssignFeederRequest) (*models.Consumer, error) {
+	consumer, err := s.GetConsumer(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ru, err := s.ruRepo.GetRuByID(req.RuID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if !actor.CanAccessOrganization(ru.OrganizationID) {
+		return nil, ErrRuNotFound
+	}
+	if ru.OrganizationID != consumer.OrganizationID {
+		return nil, ErrConsumerOrganizationMismatch
+	}
+
+	cell, err := s.ruRepo.GetCellByID(req.CellID, ru.ID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	if cell.Type != models.CellTypeOutput {
+		return nil, ErrConsumerCellNotFeeder
+	}
+
+	err = s.consumerRepo.AddFeeder(&models.ConsumerFeeder{
+		ConsumerID: consumer.ID,
+		CellID:     cell.ID,
+		RuID:       ru.ID,
+		CellNumber: cell.Number,
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.consumerRepo.GetByID(consumer.ID)
+}
+
+// RemoveFeeder - отключение потребителя от ячейки
+func (s *ConsumerService) RemoveFeeder(actor models.Actor, id string, cellID int) (*models.Consumer, error) {
+	consumer, err := s.GetConsumer(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := s.consumerRepo.RemoveFeeder(consumer.ID, cellID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrConsumerFeederNotFound
+	}
+	return s.consumerRepo.GetByID(consumer.ID)
+}
+
+// Impact - потребители, теряющие питание при отключении ячеек РУ. Отходящая ячейка
+// обесточивает своих потребителей; ввод и трансформаторная ячейка - все отходящие ячейки
+// своей стороны и секции шин (оценка без учета питания секции через секционный выключатель).
+func (s *ConsumerService) Impact(ruID string, cellIDs []int) (*models.OutageImpact, error) {
+	feeders, consumersByCell, err := s.affectedFeeders(ruID, cellIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &models.OutageImpact{
+		RuID:      ruID,
+		CellIDs:   cellIDs,
+		Feeders:   []models.ImpactedFeeder{},
+		Consumers: []models.ConsumerRef{},
+	}
+	seen := map[string]bool{}
+	for _, cell := range feeders {
+		feeder := models.ImpactedFeeder{
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+			Consumers:  consumerRefs(consumersByCell[cell.ID]),
+		}
+		impact.Feeders = append(impact.Feeders, feeder)
+		for _, consumer := range feeder.Consumers {
+			if seen[consumer.ID] {
+				continue
+			}
+			seen[consumer.ID] = true
+			impact.Consumers = append(impact.Consumers, consumer)
+			if consumer.ContractedPowerKW != nil {
+				impact.ContractedPowerKW += *consumer.ContractedPowerKW
+			}
+		}
+	}
+
+	sort.Slice(impact.Feeders, func(i, j int) bool {
+		return impact.Feeders[i].CellNumber < impact.Feeders[j].CellNumber
+	})
+	sort.Slice(impact.Consumers, func(i, j int) bool {
+		return impact.Consumers[i].Company < impact.Consumers[j].Company
+	})
+	return impact, nil
+}
+
+// AffectedConsumers - потребители из оценки Impact вместе с контактами для уведомлений
+func (s *ConsumerService) AffectedConsumers(ruID string, cellIDs []int) ([]models.Consumer, error) {
+	feeders, consumersByCell, err := s.affectedFeeders(ruID, cellIDs)
+	if err != nil {
+		return nil, err
+	}
+	var consumers []models.Consumer
+	seen := map[string]bool{}
+	for _, cell := range feeders {
+		for _, consumer := range consumersByCell[cell.ID] {
+			if !seen[consumer.ID] {
+				seen[consumer.ID] = true
+				consumers = append(consumers, consumer)
+			}
+		}
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		return consumers[i].Company < consumers[j].Company
+	})
+	return consumers, nil
+}
+
+// affectedFeeders - отходящие ячейки, обесточиваемые отключением cellIDs, и их потребители
+func (s *ConsumerService) affectedFeeders(ruID string, cellIDs []int) ([]models.Cell, map[int][]models.Consumer, error) {
+	if len(cellIDs) == 0 {
+		return nil, nil, ErrImpactCellsRequired
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	byID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		byID[cell.ID] = cell
+	}
+
+	affected := map[int]bool{}
+	for _, cellID := range cellIDs {
+		cell, ok := byID[cellID]
+		if !ok {
+			return nil, nil, ErrCellNotFound.WithDetails(map[string]interface{}{"cellId": cellID})
+		}
+		switch cell.Type {
+		case models.CellTypeOutput:
+			affected[cell.ID] = true
+		case models.CellTypeInput, models.CellTypeTransformer:
+			for _, other := range cells {
+				if other.Type == models.CellTypeOutput && other.VoltageLevel == cell.VoltageLevel && sameSection(cell, other) {
+					affected[other.ID] = true
+				}
+			}
+		}
+	}
+
+	feeders := make([]models.Cell, 0, len(affected))
+	feederIDs := make([]int, 0, len(affected))
+	for _, cell := range cells {
+		if affected[cell.ID] {
+			feeders = append(feeders, cell)
+			feederIDs = append(feederIDs, cell.ID)
+		}
+	}
+	consumersByCell, err := s.consumerRepo.GetByCells(feederIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return feeders, consumersByCell, nil
+}
+
+// ConsumersByCells - потребители отходящих ячеек для ответов других сервисов
+func (s *ConsumerService) ConsumersByCells(cellIDs []int) (map[int][]models.ConsumerRef, error) {
+	consumers, err := s.consumerRepo.GetByCells(cellIDs)
+	if err != nil {
+		return nil, err
+	}
+	refs := make(map[int][]models.ConsumerRef, len(consumers))
+	for cellID, list := range consumers {
+		refs[cellID] = consumerRefs(list)
+	}
+	return refs, nil
+}
+
+func (s *ConsumerService) checkContract(contractNumber, exceptID string) error {
+	exists, err := s.consumerRepo.ExistsByContractNumber(contractNumber, exceptID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrConsumerExists
+	}
+	return nil
+}
+
+func applyConsumerRequest(consumer *models.Consumer, req *models.ConsumerRequest, now time.Time) {
+	consumer.Company = req.Company
+	consumer.ContractNumber = req.ContractNumber
+	consumer.ContractedPowerKW = req.ContractedPowerKW
+	consumer.ContactName = req.ContactName
+	consumer.ContactPhone = req.ContactPhone
+	consumer.ContactEmail = req.ContactEmail
+	consumer.Notes = req.Notes
+	consumer.UpdatedAt = now
+}
+
+// sameSection - ячейки на одной секции шин; ячейка без номера секции питает всю сторону РУ
+func sameSection(source, feeder models.Cell) bool {
+	if source.BusSection == nil || feeder.BusSection == nil {
+		return true
+	}
+	return *source.BusSection == *feeder.BusSection
+}
+
+func consumerRefs(consumers []models.Consumer) []models.ConsumerRef {
+	refs := make([]models.ConsumerRef, len(consumers))
+	for i, consumer := range consumers {
+		refs[i] = models.ConsumerRef{
+			ID:                consumer.ID,
+			Company:           consumer.Company,
+			ContractNumber:    consumer.ContractNumber,
+			ContractedPowerKW: consumer.ContractedPowerKW,
+		}
+	}
+	return refs
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// correctionValueMaxLength - предел длины исправленного значения поля
+const correctionValueMaxLength = 1000
+
+// CorrectHistoryRecord - исправляет ошибочную запись журнала так, как это делают в
+// бумажном журнале: запись остается как есть, к ней добавляется исправление с причиной
+// и тем, кто исправил. Значения, совпадающие с текущими, не записываются.
+func (s *RuService) CorrectHistoryRecord(ruID, recordID string, req *models.CorrectHistoryRecordRequest, actor models.Actor) (*models.OperationRecord, error) {
+	record, err := s.GetHistoryRecord(ruID, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(req.Fields))
+	for name := range req.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	correction := models.OperationCorrection{
+		ID:          utils.NewID(models.IDPrefixCorrection),
+		RecordID:    record.ID,
+		RuID:        record.RuID,
+		Reason:      strings.TrimSpace(req.Reason),
+		CorrectedBy: actor.Email,
+		CreatedAt:   time.Now(),
+	}
+	for _, name := range names {
+		required, ok := models.CorrectableFields[name]
+		if !ok {
+			return nil, ErrCorrectionFieldInvalid.WithDetails(map[string]interface{}{"field": name})
+		}
+		value := req.Fields[name]
+		if value != nil {
+			trimmed := strings.TrimSpace(*value)
+			value = &trimmed
+			if trimmed == "" {
+				value = nil
+			} else if len(trimmed) > correctionValueMaxLength {
+				return nil, ErrCorrectionValueTooLong.WithDetails(map[string]interface{}{"field": name, "max": correctionValueMaxLength})
+			}
+		}
+		if value == nil && required {
+			return nil, ErrCorrectionFieldRequired.WithDetails(map[string]interface{}{"field": name})
+		}
+		if name == "severity" && !models.RecordSeverity(*value).Valid() {
+			return nil, ErrRecordSeverityInvalid.WithDetails(map[string]interface{}{"severity": *value})
+		}
+		switch name {
+		case "documentType":
+			if value, err = s.documentTypes.Resolve(value); err != nil {
+				return nil, err
+			}
+		case "workOrderNumber":
+			if value, err = s.numbering.Check(models.DocumentTypePermit, record.RuID, value); err != nil {
+				return nil, err
+			}
+		case "orderNumber":
+			documentType := record.CurrentValue("documentType")
+			if corrected, ok := req.Fields["documentType"]; ok {
+				if documentType, err = s.documentTypes.Resolve(corrected); err != nil {
+					return nil, err
+				}
+			}
+			if value, err = s.numbering.Check(orderSeries(documentType), record.RuID, value); err != nil {
+				return nil, err
+			}
+		}
+
+		current := record.CurrentValue(name)
+		if sameValue(current, value) {
+			continue
+		}
+		correction.Fields = append(correction.Fields, models.CorrectedField{
+			CorrectionID: correction.ID,
+			Field:        name,
+			From:         current,
+			To:           value,
+		})
+	}
+	if len(correction.Fields) == 0 {
+		return nil, ErrCorrectionNoChanges
+	}
+
+	if err := s.ruRepo.AddCorrection(&correction); err != nil {
+		return nil, err
+	}
+	record.ApplyCorrections(append(record.Corrections, correction))
+	return record, nil
+}
+
+// attachCorrections - прикладывает к записям журнала их исправления
+func (s *RuService) attachCorrections(records []models.OperationRecord) error {
+	ids := make([]string, len(records))
+	index := make(map[string]int, len(records))
+	for i := range records {
+		ids[i] = records[i].ID
+		index[records[i].ID] = i
+	}
+
+	corrections, err := s.ruRepo.GetCorrectionsByRecords(ids)
+	if err != nil {
+		return err
+	}
+	byRecord := map[string][]models.OperationCorrection{}
+	for _, correction := range corrections {
+		byRecord[correction.RecordID] = append(byRecord[correction.RecordID], correction)
+	}
+	for recordID, list := range byRecord {
+		if i, ok := index[recordID]; ok {
+			records[i].ApplyCorrections(list)
+		}
+	}
+	return nil
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
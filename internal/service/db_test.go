@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testDBSeq atomic.Int64
+
+// newTestDB - отдельная база SQLite в памяти для теста с таблицами переданных моделей
+func newTestDB(t *testing.T, tables ...interface{}) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:testdb%d?mode=memory&cache=shared", testDBSeq.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Use(repository.AppendOnly{}); err != nil {
+		t.Fatalf("register append-only plugin: %v", err)
+	}
+	if err := db.AutoMigrate(tables...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+type DefectService struct {
+	defectRepo *repository.DefectRepository
+	photos     *PhotoService
+	ruRepo     *repository.RuRepository
+	userRepo   *repository.UserRepository
+}
+
+func NewDefectService(defectRepo *repository.DefectRepository, photos *PhotoService, ruRepo *repository.RuRepository, userRepo *repository.UserRepository) *DefectService {
+	return &DefectService{defectRepo: defectRepo, photos: photos, ruRepo: ruRepo, userRepo: userRepo}
+}
+
+// GetDefects - дефекты РУ организации пользователя
+func (s *DefectService) GetDefects(actor models.Actor, filter models.DefectFilter) ([]models.Defect, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	defects, err := s.defectRepo.GetDefects(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get defects: %w", err)
+	}
+	if err := s.attachPhotos(defects); err != nil {
+		return nil, err
+	}
+	return defects, nil
+}
+
+func (s *DefectService) GetDefect(actor models.Actor, id string) (*models.Defect, error) {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	defects := []models.Defect{*defect}
+	if err := s.attachPhotos(defects); err != nil {
+		return nil, err
+	}
+	return &defects[0], nil
+}
+
+func (s *DefectService) CreateDefect(req *models.CreateDefectRequest, actor models.Actor) (*models.Defect, error) {
+	if err := checkRuAccess(s.ruRepo, actor, req.RuID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	defect := &models.Defect{
+		ID:                   utils.NewID(models.IDPrefixDefect),
+		RuID:                 req.RuID,
+		Title:                req.Title,
+		Description:          req.Description,
+		Severity:             req.Severity,
+		Status:               models.DefectStatusOpen,
+		DueDate:              req.DueDate,
+		FixDuringMaintenance: req.FixDuringMaintenance,
+		ReportedBy:           actor.Email,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+		Photos:               []models.Photo{},
+	}
+	if req.CellID != nil {
+		cell, err := s.ruRepo.GetCellByID(*req.CellID, req.RuID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrCellNotFound
+			}
+			return nil, fmt.Errorf("failed to get cell: %w", err)
+		}
+		defect.CellID = &cell.ID
+		defect.CellNumber = cell.Number
+	}
+	if err := s.assign(defect, req.AssigneeID); err != nil {
+		return nil, err
+	}
+
+	if err := s.defectRepo.Create(defect); err != nil {
+		return nil, fmt.Errorf("failed to create defect: %w", err)
+	}
+	return defect, nil
+}
+
+func (s *DefectService) UpdateDefect(actor models.Actor, id string, req *models.UpdateDefectRequest) (*models.Defect, error) {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		defect.Title = *req.Title
+	}
+	if req.Description != nil {
+		defect.Description = *req.Description
+	}
+	if req.Severity != nil {
+		defect.Severity = *req.Severity
+	}
+	if req.DueDate != nil {
+		defect.DueDate = req.DueDate
+	}
+	if req.FixDuringMaintenance != nil {
+		defect.FixDuringMaintenance = *req.FixDuringMaintenance
+	}
+	if req.AssigneeID != nil {
+		if err := s.assign(defect, req.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	defect.UpdatedAt = time.Now()
+
+	if err := s.defectRepo.Update(defect); err != nil {
+		return nil, fmt.Errorf("failed to update defect: %w", err)
+	}
+	return s.GetDefect(actor, defect.ID)
+}
+
+// UpdateStatus - перевод дефекта по жизненному циклу open → in_work → fixed
+func (s *DefectService) UpdateStatus(id string, req *models.UpdateDefectStatusRequest, actor models.Actor) (*models.Defect, error) {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(models.DefectTransitions[defect.Status], req.Status) {
+		return nil, ErrDefectTransition.WithDetails(map[string]interface{}{
+			"from":    defect.Status,
+			"to":      req.Status,
+			"allowed": models.DefectTransitions[defect.Status],
+		})
+	}
+
+	now := time.Now()
+	defect.Status = req.Status
+	defect.UpdatedAt = now
+	if req.Status == models.DefectStatusFixed {
+		defect.FixedAt = &now
+		defect.FixedBy = &actor.Email
+		defect.Resolution = req.Resolution
+	} else {
+		defect.FixedAt = nil
+		defect.FixedBy = nil
+		defect.Resolution = nil
+	}
+
+	if err := s.defectRepo.Update(defect); err != nil {
+		return nil, fmt.Errorf("failed to update defect status: %w", err)
+	}
+	return s.GetDefect(actor, defect.ID)
+}
+
+func (s *DefectService) DeleteDefect(actor models.Actor, id string) error {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.defectRepo.Delete(defect.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete defect: %w", err)
+	}
+	if !deleted {
+		return ErrDefectNotFound
+	}
+	return nil
+}
+
+// AddPhoto - прикрепляет фотографию; формат определяется по содержимому
+func (s *DefectService) AddPhoto(id, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.photos.Save(models.PhotoOwnerDefect, defect.ID, fileName, data, actor)
+}
+
+// GetPhoto - оригинал фотографии дефекта или ее миниатюра (thumb)
+func (s *DefectService) GetPhoto(actor models.Actor, id, photoID string, thumb bool) (*models.Photo, error) {
+	defect, err := s.getDefect(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.photos.Get(models.PhotoOwnerDefect, defect.ID, photoID, thumb)
+}
+
+// Tasks - источник задач для входящих: неустраненные дефекты, назначенные пользователю.
+// Дефект, который устраняется при плановом ТО, получает срок ТО его РУ.
+func (s *DefectService) Tasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	defects, err := s.defectRepo.GetOpenByAssignee(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	maintenance := map[string]*time.Time{}
+	var tasks []models.Task
+	for _, defect := range defects {
+		deadline := defect.DueDate
+		if deadline == nil && defect.FixDuringMaintenance {
+			due, ok := maintenance[defect.RuID]
+			if !ok {
+				if ru, err := s.ruRepo.GetRuByID(defect.RuID); err == nil {
+					due = ru.NextMaintenanceAt
+				}
+				maintenance[defect.RuID] = due
+			}
+			deadline = due
+		}
+
+		task := models.Task{
+			Type:     models.TaskTypeDefect,
+			RefID:    defect.ID,
+			Title:    i18n.T(lang, "task.defect", defect.Title),
+			RuID:     defect.RuID,
+			Deadline: deadline,
+		}
+		if deadline != nil {
+			task.Overdue = deadline.Before(now)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// getDefect - дефект РУ организации пользователя; чужой дефект не отличается от несуществующего
+func (s *DefectService) getDefect(actor models.Actor, id string) (*models.Defect, error) {
+	defect, err := s.defectRepo.GetOrganizationDefect(utils.NormalizeID(models.IDPrefixDefect, id), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrDefectNotFound
+		}
+		return nil, fmt.Errorf("failed to get defect: %w", err)
+	}
+	return defect, nil
+}
+
+// assign - назначает исполнителя из организации РУ дефекта; пустая строка снимает назначение
+func (s *DefectService) assign(defect *models.Defect, assigneeID *string) error {
+	if assigneeID == nil || *assigneeID == "" {
+		defect.AssigneeID = nil
+		defect.AssigneeName = nil
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(*assigneeID)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	organizationID, err := s.ruRepo.GetRuOrganization(defect.RuID)
+	if err != nil {
+		return fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	if !models.UserActor(user).CanAccessOrganization(organizationID) {
+		return ErrUserNotFound
+	}
+	defect.AssigneeID = &user.ID
+	defect.AssigneeName = &user.Name
+	return nil
+}
+
+func (s *DefectService) attachPhotos(defects []models.Defect) error {
+	ids := make([]string, len(defects))
+	for i := range defects {
+		ids[i] = defects[i].ID
+		defects[i].Photos = []models.Photo{}
+	}
+
+	photos, err := s.photos.GetByOwners(models.PhotoOwnerDefect, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get photos: %w", err)
+	}
+
+	index := make(map[string]int, len(defects))
+	for i := range defects {
+		index[defects[i].ID] = i
+	}
+	for _, photo := range photos {
+		if i, ok := index[photo.OwnerID]; ok {
+			defects[i].Photos = append(defects[i].Photos, photo)
+		}
+	}
+	return nil
+}
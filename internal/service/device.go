@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// deviceCheckParallelism - сколько устройств проверяется одновременно
+const deviceCheckParallelism = 16
+
+type DeviceService struct {
+	deviceRepo *repository.DeviceRepository
+	ruRepo     *repository.RuRepository
+}
+
+func NewDeviceService(deviceRepo *repository.DeviceRepository, ruRepo *repository.RuRepository) *DeviceService {
+	return &DeviceService{deviceRepo: deviceRepo, ruRepo: ruRepo}
+}
+
+// GetDevices - устройства РУ организации пользователя
+func (s *DeviceService) GetDevices(actor models.Actor, filter models.DeviceFilter) ([]models.Device, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	devices, err := s.deviceRepo.GetDevices(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	return devices, nil
+}
+
+// GetDevice - устройство РУ организации пользователя; чужое не отличается от несуществующего
+func (s *DeviceService) GetDevice(actor models.Actor, id string) (*models.Device, error) {
+	return s.getDevice(id, actor.OrganizationScope())
+}
+
+func (s *DeviceService) getDevice(id, organizationID string) (*models.Device, error) {
+	device, err := s.deviceRepo.GetByID(utils.NormalizeID(models.IDPrefixDevice, id), organizationID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrDeviceNotFound
+		}
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *DeviceService) CreateDevice(req *models.DeviceRequest) (*models.Device, error) {
+	now := time.Now()
+	device := &models.Device{
+		ID:        utils.NewID(models.IDPrefixDevice),
+		Status:    models.DeviceStatusUnknown,
+		CreatedAt: now,
+	}
+	if err := s.apply(device, req); err != nil {
+		return nil, err
+	}
+	device.UpdatedAt = now
+
+	if err := s.deviceRepo.Create(device); err != nil {
+		return nil, fmt.Errorf("failed to create device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *DeviceService) UpdateDevice(id string, req *models.DeviceRequest) (*models.Device, error) {
+	device, err := s.getDevice(id, "")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(device, req); err != nil {
+		return nil, err
+	}
+	device.UpdatedAt = time.Now()
+
+	if err := s.deviceRepo.Update(device); err != nil {
+		return nil, fmt.Errorf("failed to update device: %w", err)
+	}
+	return device, nil
+}
+
+func (s *DeviceService) DeleteDevice(id string) error {
+	deleted, err := s.deviceRepo.Delete(utils.NormalizeID(models.IDPrefixDevice, id))
+	if err != nil {
+		return fmt.Errorf("failed to delete device: %w", err)
+	}
+	if !deleted {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// apply - переносит данные запроса в устройство, проверяя РУ и ячейки
+func (s *DeviceService) apply(device *models.Device, req *models.DeviceRequest) error {
+	if req.CheckMethod != models.CheckHeartbeat && req.Host == "" {
+		return ErrDeviceHostRequired
+	}
+	if _, err := s.ruRepo.GetRuByID(req.RuID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrRuNotFound
+		}
+		return fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	cells := make([]models.DeviceCell, 0, len(req.CellIDs))
+	seen := make(map[int]bool, len(req.CellIDs))
+	for _, cellID := range req.CellIDs {
+		if seen[cellID] {
+			continue
+		}
+		seen[cellID] = true
+		cell, err := s.ruRepo.GetCellByID(cellID, req.RuID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return ErrCellNotFound.WithDetails(map[string]interface{}{"cellId": cellID})
+			}
+			return fmt.Errorf("failed to get cell: %w", err)
+		}
+		cells = append(cells, models.DeviceCell{DeviceID: device.ID, CellID: cell.ID, CellNumber: cell.Number})
+	}
+
+	device.Name = req.Name
+	device.Type = req.Type
+	device.Protocol = req.Protocol
+	device.Host = req.Host
+	device.Port = req.Port
+	device.RuID = req.RuID
+	device.Cells = cells
+	device.CheckMethod = req.CheckMethod
+	device.SilenceTimeout = req.SilenceTimeout
+	if device.SilenceTimeout == 0 {
+		device.SilenceTimeout = models.DefaultDeviceSilenceTimeout
+	}
+	device.Enabled = req.Enabled == nil || *req.Enabled
+	return nil
+}
+
+// Heartbeat - отметка о связи, присланная устройством или шлюзом телеметрии организации
+func (s *DeviceService) Heartbeat(actor models.Actor, id string) error {
+	device, err := s.GetDevice(actor, id)
+	if err != nil {
+		return err
+	}
+	found, err := s.deviceRepo.RecordContact(device.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	if !found {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// CheckDevice - внеочередная проверка связи с устройством
+func (s *DeviceService) CheckDevice(ctx context.Context, actor models.Actor, id string) (*models.Device, error) {
+	device, err := s.GetDevice(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// CheckAll - проверка связи со всеми включенными устройствами (фоновая задача)
+func (s *DeviceService) CheckAll(ctx context.Context) error {
+	devices, err := s.deviceRepo.GetEnabled()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, deviceCheckParallelism)
+	for i := range devices {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(device *models.Device) {
+			defer func() { <-sem; wg.Done() }()
+			if err := s.check(ctx, device); err != nil {
+				log.Printf("⚠️ Device %s health check failed: %v", device.ID, err)
+			}
+		}(&devices[i])
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// check - опрашивает устройство (кроме пассивного heartbeat) и пересчитывает статус:
+// устройство на связи, пока последний контакт был не раньше SilenceTimeout назад.
+// При переходе в offline поднимается событие device.offline.
+func (s *DeviceService) check(ctx context.Context, device *models.Device) error {
+	now := time.Now()
+	prevContact := device.LastContactAt
+
+	if device.CheckMethod != models.CheckHeartbeat {
+		if err := probeDevice(ctx, device); err != nil {
+			msg := err.Error()
+			device.LastError = &msg
+		} else {
+			device.LastContactAt = &now
+			device.LastError = nil
+		}
+	}
+	device.LastCheckAt = &now
+
+	timeout := time.Duration(device.SilenceTimeout) * time.Second
+	status := models.DeviceStatusOffline
+	switch {
+	case device.LastContactAt != nil && now.Sub(*device.LastContactAt) <= timeout:
+		status = models.DeviceStatusOnline
+	case device.LastContactAt == nil && now.Sub(device.CreatedAt) <= timeout:
+		status = models.DeviceStatusUnknown
+	}
+
+	var events []models.OutboxEvent
+	if status != device.Status {
+		if status == models.DeviceStatusOffline {
+			event, err := newEvent(models.EventDeviceOffline, device.RuID, models.DeviceOfflinePayload{
+				DeviceID:      device.ID,
+				Name:          device.Name,
+				Type:          device.Type,
+				Host:          device.Host,
+				LastContactAt: device.LastContactAt,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		device.Status = status
+		device.StatusSince = &now
+	}
+
+	return s.deviceRepo.SaveHealth(device, prevContact, events)
+}
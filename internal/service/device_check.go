@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+)
+
+const deviceProbeTimeout = 3 * time.Second
+
+// defaultDevicePorts - стандартные порты протоколов, если порт устройства не задан
+var defaultDevicePorts = map[models.DeviceProtocol]int{
+	models.ProtocolIEC104:    2404,
+	models.ProtocolIEC61850:  102,
+	models.ProtocolModbusTCP: 502,
+	models.ProtocolDNP3:      20000,
+}
+
+// probeDevice - активная проверка связи с устройством; nil означает, что устройство ответило
+func probeDevice(ctx context.Context, device *models.Device) error {
+	ctx, cancel := context.WithTimeout(ctx, deviceProbeTimeout)
+	defer cancel()
+
+	switch device.CheckMethod {
+	case models.CheckPing:
+		return probePing(ctx, device.Host)
+	case models.CheckPort:
+		conn, err := dialDevice(ctx, device)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case models.CheckProtocol:
+		return probeProtocol(ctx, device)
+	}
+	return fmt.Errorf("check method %q is passive", device.CheckMethod)
+}
+
+func dialDevice(ctx context.Context, device *models.Device) (net.Conn, error) {
+	port := device.Port
+	if port == 0 {
+		port = defaultDevicePorts[device.Protocol]
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("no port configured for protocol %s", device.Protocol)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "tcp", net.JoinHostPort(device.Host, strconv.Itoa(port)))
+}
+
+// probeProtocol - тестовый обмен на уровне протокола: IEC 104 TESTFR act/con,
+// Modbus TCP чтение регистра (ответ с исключением тоже означает, что устройство живо).
+// Для остальных протоколов проверяется только TCP-подключение.
+func probeProtocol(ctx context.Context, device *models.Device) error {
+	conn, err := dialDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	switch device.Protocol {
+	case models.ProtocolIEC104:
+		if _, err := conn.Write([]byte{0x68, 0x04, 0x43, 0x00, 0x00, 0x00}); err != nil {
+			return fmt.Errorf("failed to send TESTFR: %w", err)
+		}
+		// Станция может прислать и другие U/S/I-кадры - достаточно корректного начала APDU
+		reply := make([]byte, 2)
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return fmt.Errorf("no TESTFR confirmation: %w", err)
+		}
+		if reply[0] != 0x68 {
+			return fmt.Errorf("unexpected IEC 104 reply %x", reply)
+		}
+
+	case models.ProtocolModbusTCP:
+		request := []byte{0x53, 0x56, 0x00, 0x00, 0x00, 0x06, 0x01, 0x03, 0x00, 0x00, 0x00, 0x01}
+		if _, err := conn.Write(request); err != nil {
+			return fmt.Errorf("failed to send Modbus request: %w", err)
+		}
+		reply := make([]byte, 7)
+		if _, err := io.ReadFull(conn, reply); err != nil {
+			return fmt.Errorf("no Modbus response: %w", err)
+		}
+		if !bytes.Equal(reply[:4], request[:4]) {
+			return fmt.Errorf("unexpected Modbus reply header %x", reply[:4])
+		}
+	}
+	return nil
+}
+
+// probePing - ICMP echo через непривилегированный сокет (net.ipv4.ping_group_range)
+func probePing(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	var target net.IP
+	for _, addr := range addrs {
+		if ip4 := addr.IP.To4(); ip4 != nil {
+			target = ip4
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("no IPv4 address for %s", host)
+	}
+
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		return fmt.Errorf("failed to open ICMP socket: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	seq := int(time.Now().UnixNano() & 0xffff)
+	request := icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: os.Getpid() & 0xffff, Seq: seq, Data: []byte("sez-vision")},
+	}
+	data, err := request.Marshal(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.WriteTo(data, &net.UDPAddr{IP: target}); err != nil {
+		return fmt.Errorf("failed to send ICMP echo: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err != nil {
+			return fmt.Errorf("no ICMP echo reply: %w", err)
+		}
+		reply, err := icmp.ParseMessage(ipv4.ICMPTypeEcho.Protocol(), buf[:n])
+		if err != nil {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		if reply.Type == ipv4.ICMPTypeEchoReply && ok && echo.Seq == seq && peer.(*net.UDPAddr).IP.Equal(target) {
+			return nil
+		}
+	}
+}
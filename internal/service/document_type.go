@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// documentTypeCodePattern - код вида документа: латиница в нижнем регистре, цифры, подчеркивание
+var documentTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// DocumentTypeService - справочник видов документов, по которым выполняются операции
+type DocumentTypeService struct {
+	documentTypeRepo *repository.DocumentTypeRepository
+}
+
+func NewDocumentTypeService(documentTypeRepo *repository.DocumentTypeRepository) *DocumentTypeService {
+	return &DocumentTypeService{documentTypeRepo: documentTypeRepo}
+}
+
+// GetAll - справочник; activeOnly - только виды, которые можно указать в новой записи
+func (s *DocumentTypeService) GetAll(activeOnly bool) ([]models.DocumentType, error) {
+	return s.documentTypeRepo.GetAll(activeOnly)
+}
+
+func (s *DocumentTypeService) Create(req *models.CreateDocumentTypeRequest) (*models.DocumentType, error) {
+	code := strings.TrimSpace(req.Code)
+	if !documentTypeCodePattern.MatchString(code) || code == models.DocumentTypeUnclassified {
+		return nil, ErrDocumentTypeCodeInvalid.WithDetails(map[string]interface{}{"code": req.Code})
+	}
+
+	now := time.Now()
+	documentType := &models.DocumentType{
+		Code:        code,
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+		Active:      req.Active == nil || *req.Active,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.documentTypeRepo.Create(documentType); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrDocumentTypeExists
+		}
+		return nil, err
+	}
+	return documentType, nil
+}
+
+// Update - название, описание и активность вида. Записи хранят код, поэтому
+// переименование вида не требует правки журнала.
+func (s *DocumentTypeService) Update(code string, req *models.UpdateDocumentTypeRequest) (*models.DocumentType, error) {
+	documentType, err := s.get(code)
+	if err != nil {
+		return nil, err
+	}
+	documentType.Name = strings.TrimSpace(req.Name)
+	documentType.Description = strings.TrimSpace(req.Description)
+	if req.Active != nil {
+		documentType.Active = *req.Active
+	}
+	documentType.UpdatedAt = time.Now()
+
+	if err := s.documentTypeRepo.Save(documentType); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrDocumentTypeExists
+		}
+		return nil, err
+	}
+	return documentType, nil
+}
+
+// Delete - удаляет вид, на который не ссылается ни одна запись журнала. Вид, уже
+// использованный в журнале, можно только отключить.
+func (s *DocumentTypeService) Delete(code string) error {
+	used, err := s.documentTypeRepo.CountRecords(code)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrDocumentTypeInUse.WithDetails(map[string]interface{}{"code": code, "records": used})
+	}
+	deleted, err := s.documentTypeRepo.Delete(code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrDocumentTypeNotFound
+	}
+	return nil
+}
+
+// Resolve - код активного вида документа по коду или названию без учета регистра.
+// Пустое значение - запись без документа.
+func (s *DocumentTypeService) Resolve(value *string) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil, nil
+	}
+	documentType, err := s.documentTypeRepo.Find(strings.TrimSpace(*value))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrDocumentTypeUnknown.WithDetails(map[string]interface{}{"documentType": *value})
+		}
+		return nil, fmt.Errorf("failed to resolve document type: %w", err)
+	}
+	if !documentType.Active {
+		return nil, ErrDocumentTypeUnknown.WithDetails(map[string]interface{}{"documentType": *value})
+	}
+	return &documentType.Code, nil
+}
+
+func (s *DocumentTypeService) get(code string) (*models.DocumentType, error) {
+	documentType, err := s.documentTypeRepo.GetByCode(code)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrDocumentTypeNotFound
+		}
+		return nil, fmt.Errorf("failed to get document type: %w", err)
+	}
+	return documentType, nil
+}
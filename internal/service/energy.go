@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// energyMonthLayout - формат месяца учета в запросах и выгрузке
+	energyMonthLayout = "2006-01"
+	// energyClosingWindow - конечное показание месяца должно быть снято не раньше этого
+	// срока до конца месяца, иначе расход считается неполным
+	energyClosingWindow = 24 * time.Hour
+	// maxMeterReadingsSpan - самый длинный период выборки показаний одной ячейки
+	maxMeterReadingsSpan = 366 * 24 * time.Hour
+)
+
+// EnergyService - учет электроэнергии по отходящим ячейкам: каждая отходящая линия ОЭЗ
+// питает одного промышленного потребителя. Показания счетчиков вносятся вручную или
+// приходят от шлюза телеметрии, расход за месяц считается по разности показаний.
+type EnergyService struct {
+	energyRepo *repository.EnergyRepository
+	ruRepo     *repository.RuRepository
+	ruService  *RuService
+	consumers  *ConsumerService
+}
+
+func NewEnergyService(energyRepo *repository.EnergyRepository, ruRepo *repository.RuRepository, ruService *RuService, consumers *ConsumerService) *EnergyService {
+	return &EnergyService{
+		energyRepo: energyRepo,
+		ruRepo:     ruRepo,
+		ruService:  ruService,
+		consumers:  consumers,
+	}
+}
+
+// feederCell - отходящая ячейка РУ, по которой ведется учет
+func (s *EnergyService) feederCell(ruID string, cellID int) (*models.Cell, error) {
+	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	if cell.Type != models.CellTypeOutput {
+		return nil, ErrMeterCellNotFeeder.WithDetails(map[string]interface{}{"cellId": cellID, "type": cell.Type})
+	}
+	return cell, nil
+}
+
+// RecordManual - показание, снятое с счетчика на месте. Показание меньше предыдущего
+// принимается только с отметкой о замене счетчика.
+func (s *EnergyService) RecordManual(actor models.Actor, ruID string, cellID int, req *models.RecordMeterReadingRequest) (*models.MeterReading, error) {
+	if _, err := s.feederCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+
+	readAt := time.Now()
+	if req.ReadAt != nil {
+		readAt = *req.ReadAt
+	}
+
+	if !req.MeterReplaced {
+		previous, err := s.energyRepo.GetLastReadings([]int{cellID}, readAt)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := previous[cellID]; ok && !prev.ReadAt.Equal(readAt) && *req.EnergyKWh < prev.EnergyKWh {
+			return nil, ErrMeterReadingDecreased.WithDetails(map[string]interface{}{
+				"previousKWh": prev.EnergyKWh,
+				"previousAt":  prev.ReadAt,
+			})
+		}
+	}
+
+	userID := actor.UserID
+	reading := models.MeterReading{
+		CellID:        cellID,
+		ReadAt:        readAt,
+		RuID:          ruID,
+		EnergyKWh:     *req.EnergyKWh,
+		Source:        models.MeterReadingManual,
+		MeterReplaced: req.MeterReplaced,
+		RecordedBy:    &userID,
+	}
+	if err := s.energyRepo.SaveReadings([]models.MeterReading{reading}); err != nil {
+		return nil, err
+	}
+	return &reading, nil
+}
+
+// RecordTelemetry - пакет показаний от шлюза; все ячейки пакета должны быть отходящими
+// ячейками РУ организации пользователя
+func (s *EnergyService) RecordTelemetry(actor models.Actor, req *models.RecordMeterReadingsRequest) (int, error) {
+	refs := make([]cellRef, len(req.Readings))
+	for i, input := range req.Readings {
+		refs[i] = cellRef{ruID: input.RuID, cellID: input.CellID}
+	}
+	if err := checkCellRefs(s.ruRepo, actor, refs); err != nil {
+		return 0, err
+	}
+
+	checked := map[int]bool{}
+	readings := make([]models.MeterReading, len(req.Readings))
+	for i, input := range req.Readings {
+		if !checked[input.CellID] {
+			if _, err := s.feederCell(input.RuID, input.CellID); err != nil {
+				return 0, err
+			}
+			checked[input.CellID] = true
+		}
+		readings[i] = models.MeterReading{
+			CellID:    input.CellID,
+			ReadAt:    input.ReadAt,
+			RuID:      input.RuID,
+			EnergyKWh: *input.EnergyKWh,
+			Source:    models.MeterReadingTelemetry,
+		}
+	}
+	if err := s.energyRepo.SaveReadings(readings); err != nil {
+		return 0, err
+	}
+	return len(readings), nil
+}
+
+// GetReadings - показания счетчика ячейки за период
+func (s *EnergyService) GetReadings(ruID string, cellID int, from, to time.Time) ([]models.MeterReading, error) {
+	if !to.After(from) || to.Sub(from) > maxMeterReadingsSpan {
+		return nil, ErrMeasurementRangeInvalid
+	}
+	if _, err := s.feederCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	return s.energyRepo.GetReadings([]int{cellID}, from, to)
+}
+
+// MonthlyConsumption - расход энергии отходящих ячеек доступных пользователю РУ за месяц
+// вместе с потребителями ячеек (для выгрузки в биллинг). Строки упорядочены по РУ и номеру ячейки.
+func (s *EnergyService) MonthlyConsumption(actor models.Actor, query models.EnergyQuery) ([]models.FeederEnergy, error) {
+	start, err := time.ParseInLocation(energyMonthLayout, query.Month, time.Local)
+	if err != nil {
+		return nil, ErrEnergyMonthInvalid
+	}
+	end := start.AddDate(0, 1, 0)
+
+	rus, err := s.ruService.GetVisibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	ruByID := make(map[string]models.RUInfo, len(rus))
+	ruIDs := make([]string, 0, len(rus))
+	for _, ru := range rus {
+		if query.RuID != "" && ru.ID != query.RuID {
+			continue
+		}
+		ruByID[ru.ID] = ru
+		ruIDs = append(ruIDs, ru.ID)
+	}
+	if query.RuID != "" && len(ruIDs) == 0 {
+		return nil, ErrRuNotFound
+	}
+
+	cells, err := s.ruRepo.GetCellsByRuIDs(ruIDs)
+	if err != nil {
+		return nil, err
+	}
+	var feeders []models.Cell
+	var cellIDs []int
+	for _, cell := range cells {
+		if cell.Type == models.CellTypeOutput {
+			feeders = append(feeders, cell)
+			cellIDs = append(cellIDs, cell.ID)
+		}
+	}
+
+	opening, err := s.energyRepo.GetLastReadings(cellIDs, start)
+	if err != nil {
+		return nil, err
+	}
+	readings, err := s.energyRepo.GetReadings(cellIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := s.consumers.ConsumersByCells(cellIDs)
+	if err != nil {
+		return nil, err
+	}
+	byCell := map[int][]models.MeterReading{}
+	for _, reading := range readings {
+		// Показание ровно на начало месяца уже учтено как начальное
+		if reading.ReadAt.Equal(start) {
+			continue
+		}
+		byCell[reading.CellID] = append(byCell[reading.CellID], reading)
+	}
+
+	result := make([]models.FeederEnergy, 0, len(feeders))
+	for _, cell := range feeders {
+		row := models.FeederEnergy{
+			RuID:       cell.RuID,
+			RuName:     ruByID[cell.RuID].Name,
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+			Consumers:  consumers[cell.ID],
+			Month:      query.Month,
+		}
+		if row.Consumers == nil {
+			row.Consumers = []models.ConsumerRef{}
+		}
+		series := byCell[cell.ID]
+		first, hasOpening := opening[cell.ID]
+		found := hasOpening
+		if !found && len(series) > 0 {
+			first, series, found = series[0], series[1:], true
+		}
+		if found {
+			last := first
+			for _, reading := range series {
+				switch {
+				case reading.MeterReplaced:
+					// Первое показание нового счетчика не дает расхода
+					row.MeterReplaced = true
+				case reading.EnergyKWh < last.EnergyKWh:
+					// Уменьшение без отметки о замене - сбой телеметрии, показание пропускается
+					continue
+				default:
+					row.ConsumptionKWh += reading.EnergyKWh - last.EnergyKWh
+				}
+				last = reading
+			}
+			openingKWh, closingKWh := first.EnergyKWh, last.EnergyKWh
+			row.OpeningKWh, row.ClosingKWh = &openingKWh, &closingKWh
+			row.Readings = 1 + len(series)
+			row.Complete = hasOpening && !last.ReadAt.Before(end.Add(-energyClosingWindow))
+		}
+		result = append(result, row)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].RuName != result[j].RuName {
+			return result[i].RuName < result[j].RuName
+		}
+		return result[i].CellNumber < result[j].CellNumber
+	})
+	return result, nil
+}
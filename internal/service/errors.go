@@ -0,0 +1,261 @@
+package service
+
+import (
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+)
+
+// Ошибки сервисного слоя. Обработчики сравнивают их через errors.Is,
+// HTTP-статус определяется категорией ошибки в пакете apperrors.
+var (
+	ErrUserNotFound         = apperrors.New(apperrors.KindNotFound, "user_not_found", "user not found")
+	ErrUserExists           = apperrors.New(apperrors.KindConflict, "user_exists", "user with this email already exists")
+	ErrEmailTaken           = apperrors.New(apperrors.KindConflict, "email_taken", "email already taken by another user")
+	ErrInvalidRole          = apperrors.New(apperrors.KindValidation, "invalid_role", "invalid role")
+	ErrPasswordTooShort     = apperrors.New(apperrors.KindValidation, "password_too_short", "password is too short")
+	ErrPasswordNoSpecial    = apperrors.New(apperrors.KindValidation, "password_no_special", "password has no special characters")
+	ErrInvalidCredentials   = apperrors.New(apperrors.KindUnauthorized, "invalid_credentials", "invalid email or password")
+	ErrRuNotFound           = apperrors.New(apperrors.KindNotFound, "ru_not_found", "ru not found")
+	ErrCellNotFound         = apperrors.New(apperrors.KindNotFound, "cell_not_found", "cell not found")
+	ErrCellDuplicate        = apperrors.New(apperrors.KindConflict, "cell_duplicate", "cell with this number and voltage level already exists in the RU")
+	ErrSubstationNotFound   = apperrors.New(apperrors.KindNotFound, "substation_not_found", "substation not found")
+	ErrRecordNotFound       = apperrors.New(apperrors.KindNotFound, "record_not_found", "history record not found")
+	ErrRuleNotFound         = apperrors.New(apperrors.KindNotFound, "rule_not_found", "rule not found")
+	ErrNotificationNotFound = apperrors.New(apperrors.KindNotFound, "notification_not_found", "notification not found")
+	ErrSubscriptionNotFound = apperrors.New(apperrors.KindNotFound, "subscription_not_found", "subscription not found")
+	ErrSubscriptionExists   = apperrors.New(apperrors.KindConflict, "subscription_exists", "already subscribed")
+	ErrRuleRecipientInvalid = apperrors.New(apperrors.KindValidation, "rule_recipient_invalid", "either role or userId must be set")
+	ErrInvalidDate          = apperrors.New(apperrors.KindValidation, "invalid_date", "invalid date")
+	ErrCalendarDayNotFound  = apperrors.New(apperrors.KindNotFound, "calendar_day_not_found", "calendar day not found")
+	ErrEventNotFound        = apperrors.New(apperrors.KindNotFound, "event_not_found", "event not found or not failed")
+
+	// Задачи обслуживания БД
+	ErrMaintenanceJobNotFound  = apperrors.New(apperrors.KindNotFound, "maintenance_job_not_found", "maintenance job not found")
+	ErrMaintenanceJobRunning   = apperrors.New(apperrors.KindConflict, "maintenance_job_running", "another maintenance job is running")
+	ErrMaintenanceTableInvalid = apperrors.New(apperrors.KindValidation, "maintenance_table_invalid", "table is not allowed for maintenance")
+
+	// Поиск
+	ErrSearchQueryInvalid = apperrors.New(apperrors.KindValidation, "search_query_invalid", "search query must contain letters or digits")
+
+	// Исправления записей журнала
+	ErrCorrectionFieldInvalid  = apperrors.New(apperrors.KindValidation, "correction_field_invalid", "field cannot be corrected")
+	ErrCorrectionFieldRequired = apperrors.New(apperrors.KindValidation, "correction_field_required", "required field cannot be cleared")
+	ErrCorrectionValueTooLong  = apperrors.New(apperrors.KindValidation, "correction_value_too_long", "corrected value is too long")
+	ErrCorrectionNoChanges     = apperrors.New(apperrors.KindValidation, "correction_no_changes", "correction does not change any field")
+	ErrRecordSeverityInvalid   = apperrors.New(apperrors.KindValidation, "record_severity_invalid", "severity must be info, warning or emergency")
+
+	// Права на журнал операций
+	ErrHistoryWriteForbidden     = apperrors.New(apperrors.KindForbidden, "history_write_forbidden", "role cannot add history records")
+	ErrHistoryBackdated          = apperrors.New(apperrors.KindForbidden, "history_backdated", "record time is too far in the past for this role")
+	ErrHistoryInFuture           = apperrors.New(apperrors.KindValidation, "history_in_future", "record time is in the future")
+	ErrHistoryTimestampInvalid   = apperrors.New(apperrors.KindValidation, "history_timestamp_invalid", "record timestamp cannot be parsed")
+	ErrHistoryExportRange        = apperrors.New(apperrors.KindValidation, "history_export_range_invalid", "export period must be valid dates within one year")
+	ErrHistoryResponsibleInvalid = apperrors.New(apperrors.KindValidation, "history_responsible_invalid", "responsible user not found in RU organization")
+
+	// Справочник видов документов
+	ErrDocumentTypeNotFound    = apperrors.New(apperrors.KindNotFound, "document_type_not_found", "document type not found")
+	ErrDocumentTypeExists      = apperrors.New(apperrors.KindConflict, "document_type_exists", "document type with this code or name already exists")
+	ErrDocumentTypeInUse       = apperrors.New(apperrors.KindConflict, "document_type_in_use", "document type is used in history records, deactivate it instead")
+	ErrDocumentTypeCodeInvalid = apperrors.New(apperrors.KindValidation, "document_type_code_invalid", "code must contain lowercase latin letters, digits and underscores")
+	ErrDocumentTypeUnknown     = apperrors.New(apperrors.KindValidation, "document_type_unknown", "document type is not in the catalog")
+	ErrDocumentNumberUnknown   = apperrors.New(apperrors.KindValidation, "document_number_unknown", "document number was not issued by the server")
+	ErrTransformerNotFound     = apperrors.New(apperrors.KindNotFound, "transformer_not_found", "transformer not found")
+	ErrTapPositionUnchanged    = apperrors.New(apperrors.KindConflict, "tap_position_unchanged", "transformer is already at this tap position")
+	ErrTapChangeInFuture       = apperrors.New(apperrors.KindValidation, "tap_change_in_future", "tap change time cannot be in the future")
+	ErrTapChangeOutOfOrder     = apperrors.New(apperrors.KindConflict, "tap_change_out_of_order", "a later tap change is already recorded for this transformer")
+	ErrSectionNotFound         = apperrors.New(apperrors.KindNotFound, "section_not_found", "bus section not found")
+	ErrSwitchingOrderNotFound  = apperrors.New(apperrors.KindNotFound, "switching_order_not_found", "switching order not found")
+	ErrSectionNothingToSwitch  = apperrors.New(apperrors.KindConflict, "section_nothing_to_switch", "no cells of the section can be switched for this operation")
+
+	// Аварии
+	ErrAlarmFilterEmpty    = apperrors.New(apperrors.KindValidation, "alarm_filter_empty", "filter or alarm ids are required")
+	ErrAlarmFilterNotFound = apperrors.New(apperrors.KindNotFound, "alarm_filter_not_found", "saved alarm filter not found")
+	ErrAlarmNotFound       = apperrors.New(apperrors.KindNotFound, "alarm_not_found", "alarm not found")
+	ErrAlarmNotActive      = apperrors.New(apperrors.KindConflict, "alarm_not_active", "only active alarms can be shelved")
+	ErrAlarmNotShelved     = apperrors.New(apperrors.KindConflict, "alarm_not_shelved", "alarm is not shelved")
+	ErrAlarmShelveTooLong  = apperrors.New(apperrors.KindValidation, "alarm_shelve_too_long", "alarm shelve duration exceeds the allowed maximum")
+	// ErrAlarmStatsRangeInvalid - даты статистики аварий не разобраны или период длиннее года
+	ErrAlarmStatsRangeInvalid = apperrors.New(apperrors.KindValidation, "alarm_stats_range_invalid", "from and to must be YYYY-MM-DD dates, at most 366 days apart")
+
+	// SLA дефектов и нарядов-допусков
+	ErrSLARangeInvalid = apperrors.New(apperrors.KindValidation, "sla_range_invalid", "from and to must be YYYY-MM-DD dates, at most 366 days apart")
+
+	// Замки и плакаты (LOTO)
+	ErrCellLocked    = apperrors.New(apperrors.KindConflict, "cell_locked", "cell is locked out")
+	ErrCellNotLocked = apperrors.New(apperrors.KindConflict, "cell_not_locked", "cell has no active lock")
+
+	// Опрос телеметрии
+	ErrPollingScopeEmpty    = apperrors.New(apperrors.KindValidation, "polling_scope_empty", "adapter or ruId is required")
+	ErrPollingPauseNotFound = apperrors.New(apperrors.KindNotFound, "polling_pause_not_found", "polling is not paused for this scope")
+
+	// Подтверждение переключения критичных ячеек вторым сотрудником
+	ErrConfirmationNotFound   = apperrors.New(apperrors.KindNotFound, "confirmation_not_found", "pending confirmation not found")
+	ErrConfirmationExpired    = apperrors.New(apperrors.KindConflict, "confirmation_expired", "confirmation has expired")
+	ErrConfirmationSamePerson = apperrors.New(apperrors.KindForbidden, "confirmation_same_person", "confirmation must be given by another person")
+
+	// Телеметрия
+	ErrMeasurementRangeInvalid      = apperrors.New(apperrors.KindValidation, "measurement_range_invalid", "invalid measurement time range")
+	ErrMeasurementResolutionInvalid = apperrors.New(apperrors.KindValidation, "measurement_resolution_invalid", "resolution must be auto, raw, 1m, 15m or 1h")
+	ErrMeasurementMetricInvalid     = apperrors.New(apperrors.KindValidation, "measurement_metric_invalid", "metric must be current, temperature or load")
+	ErrForecastNoHistory            = apperrors.New(apperrors.KindConflict, "forecast_no_history", "no load history to forecast from")
+
+	// Одобрение изменений паспортных данных ячеек
+	ErrCellChangeNotFound = apperrors.New(apperrors.KindNotFound, "cell_change_not_found", "cell change not found")
+	ErrCellChangeReviewed = apperrors.New(apperrors.KindConflict, "cell_change_reviewed", "cell change has already been reviewed")
+	ErrCellChangeStale    = apperrors.New(apperrors.KindConflict, "cell_change_stale", "cell was modified after the change was requested")
+	ErrCellChangeEmpty    = apperrors.New(apperrors.KindValidation, "cell_change_empty", "change does not modify any field")
+
+	// Ревизии конфигурации ячеек
+	ErrCellRevisionNotFound = apperrors.New(apperrors.KindNotFound, "cell_revision_not_found", "cell revision not found")
+
+	// Экспорт и импорт РУ
+	ErrSnapshotVersion       = apperrors.New(apperrors.KindValidation, "snapshot_version_unsupported", "unsupported snapshot version")
+	ErrSnapshotInvalid       = apperrors.New(apperrors.KindValidation, "snapshot_invalid", "snapshot does not contain RU id")
+	ErrSnapshotNewIDRequired = apperrors.New(apperrors.KindValidation, "snapshot_new_id_required", "newId is required for new-id strategy")
+	ErrRuExists              = apperrors.New(apperrors.KindConflict, "ru_exists", "RU with this id already exists")
+
+	// Редактирование, архивирование и удаление РУ
+	ErrRuArchived         = apperrors.New(apperrors.KindConflict, "ru_archived", "RU is archived, restore it first")
+	ErrRuNotArchived      = apperrors.New(apperrors.KindConflict, "ru_not_archived", "RU is not archived")
+	ErrRuHasActiveWork    = apperrors.New(apperrors.KindConflict, "ru_has_active_work", "RU has open work permits or locked cells")
+	ErrRuHasHistory       = apperrors.New(apperrors.KindConflict, "ru_has_history", "RU has history records and can only be archived")
+	ErrRuDateInvalid      = apperrors.New(apperrors.KindValidation, "ru_date_invalid", "date must be in YYYY-MM-DD format")
+	ErrRuBusSectionsInUse = apperrors.New(apperrors.KindValidation, "ru_bus_sections_in_use", "cells are installed on a bus section beyond the new number of sections")
+
+	// Пакетное создание ячеек
+	ErrCellBatchSize      = apperrors.New(apperrors.KindValidation, "cell_batch_size", "cell batch must contain from 1 to 500 cells")
+	ErrCellBatchInvalid   = apperrors.New(apperrors.KindValidation, "cell_batch_invalid", "cell batch contains invalid rows, no cells were created")
+	ErrCellBatchConflict  = apperrors.New(apperrors.KindConflict, "cell_batch_conflict", "cells with these numbers were created concurrently, no cells were created")
+	ErrVoltageSideMissing = apperrors.New(apperrors.KindValidation, "voltage_side_missing", "RU has no cells side with this voltage level")
+
+	// Уровень напряжения и пары ячеек ВН/НН
+	ErrVoltageLevelInvalid = apperrors.New(apperrors.KindValidation, "voltage_level_invalid", "voltage level must be HIGH or LOW")
+	ErrCellPairInvalid     = apperrors.New(apperrors.KindValidation, "cell_pair_invalid", "only cells of opposite voltage levels of the same RU can be paired")
+
+	// Планировщик фоновых задач
+	ErrJobNotFound = apperrors.New(apperrors.KindNotFound, "job_not_found", "job not found")
+	ErrJobRunning  = apperrors.New(apperrors.KindConflict, "job_running", "job is already running")
+
+	// Дефекты оборудования
+	ErrDefectNotFound   = apperrors.New(apperrors.KindNotFound, "defect_not_found", "defect not found")
+	ErrDefectTransition = apperrors.New(apperrors.KindConflict, "defect_transition_invalid", "defect status transition is not allowed")
+	ErrPhotoNotFound    = apperrors.New(apperrors.KindNotFound, "photo_not_found", "photo not found")
+	ErrPhotoInvalid     = apperrors.New(apperrors.KindValidation, "photo_invalid", "photo must be a JPEG, PNG or WebP image up to 10 MB")
+
+	// Осмотры по чек-листам
+	ErrChecklistNotFound    = apperrors.New(apperrors.KindNotFound, "checklist_not_found", "checklist template not found")
+	ErrChecklistRuType      = apperrors.New(apperrors.KindValidation, "checklist_ru_type_mismatch", "checklist template is for a different RU type")
+	ErrInspectionNotFound   = apperrors.New(apperrors.KindNotFound, "inspection_not_found", "inspection not found")
+	ErrInspectionIncomplete = apperrors.New(apperrors.KindValidation, "inspection_incomplete", "inspection must answer every checklist item exactly")
+	ErrInspectionInFuture   = apperrors.New(apperrors.KindValidation, "inspection_in_future", "inspection date cannot be in the future")
+
+	// Реестр оборудования
+	ErrAssetNotFound             = apperrors.New(apperrors.KindNotFound, "asset_not_found", "asset not found")
+	ErrAssetSerialExists         = apperrors.New(apperrors.KindConflict, "asset_serial_exists", "asset with this serial number already exists")
+	ErrAssetSlotOccupied         = apperrors.New(apperrors.KindConflict, "asset_slot_occupied", "cell already has installed equipment of this type")
+	ErrAssetDecommissioned       = apperrors.New(apperrors.KindConflict, "asset_decommissioned", "asset is decommissioned")
+	ErrAssetOrganizationMismatch = apperrors.New(apperrors.KindValidation, "asset_organization_mismatch", "asset and RU belong to different organizations")
+
+	// Склад запасных частей
+	ErrWarehouseNotFound             = apperrors.New(apperrors.KindNotFound, "warehouse_not_found", "warehouse not found")
+	ErrWarehouseOrganizationMismatch = apperrors.New(apperrors.KindValidation, "warehouse_organization_mismatch", "warehouse and RU belong to different organizations")
+	ErrInventoryItemNotFound         = apperrors.New(apperrors.KindNotFound, "inventory_item_not_found", "inventory item not found")
+	ErrInventorySKUExists            = apperrors.New(apperrors.KindConflict, "inventory_sku_exists", "inventory item with this SKU already exists")
+	ErrStockInsufficient             = apperrors.New(apperrors.KindConflict, "stock_insufficient", "not enough stock available")
+	ErrReservationNotFound           = apperrors.New(apperrors.KindNotFound, "reservation_not_found", "reservation not found")
+	ErrReservationClosed             = apperrors.New(apperrors.KindConflict, "reservation_closed", "reservation is already consumed or released")
+	ErrReservationTarget             = apperrors.New(apperrors.KindValidation, "reservation_target_invalid", "work permit, RU or defect to reserve against was not found")
+
+	// Журнал отключений
+	ErrFaultNotFound        = apperrors.New(apperrors.KindNotFound, "fault_not_found", "fault event not found")
+	ErrFaultExternalIDTaken = apperrors.New(apperrors.KindConflict, "fault_external_id_taken", "fault with this externalId was recorded for another RU")
+	ErrComtradeNotFound     = apperrors.New(apperrors.KindNotFound, "comtrade_not_found", "oscillography record not found")
+	ErrComtradeInvalid      = apperrors.New(apperrors.KindValidation, "comtrade_invalid", "invalid COMTRADE record")
+
+	// Устройства телемеханики
+	ErrDeviceNotFound     = apperrors.New(apperrors.KindNotFound, "device_not_found", "device not found")
+	ErrDeviceHostRequired = apperrors.New(apperrors.KindValidation, "device_host_required", "host is required unless the device is checked by heartbeat")
+
+	// Телеуправление
+	ErrCommandNotFound      = apperrors.New(apperrors.KindNotFound, "command_not_found", "control command not found")
+	ErrCommandInProgress    = apperrors.New(apperrors.KindConflict, "command_in_progress", "another control command for this cell is in progress")
+	ErrCommandNoop          = apperrors.New(apperrors.KindConflict, "command_noop", "cell is already in the requested state")
+	ErrCommandNoDevice      = apperrors.New(apperrors.KindConflict, "command_no_device", "cell is not connected to an RTU or IED")
+	ErrCommandDeviceOffline = apperrors.New(apperrors.KindConflict, "command_device_offline", "control device is offline")
+	ErrCommandState         = apperrors.New(apperrors.KindConflict, "command_invalid_state", "control command is not in a state that allows this action")
+	ErrCommandExpired       = apperrors.New(apperrors.KindConflict, "command_expired", "control command selection has expired")
+
+	// Режим "только чтение"
+	ErrReadOnlyReasonRequired = apperrors.New(apperrors.KindValidation, "read_only_reason_required", "reason is required to enable read-only mode")
+
+	// Системные настройки
+	ErrSettingUnknown = apperrors.New(apperrors.KindValidation, "setting_unknown", "unknown setting")
+	ErrSettingInvalid = apperrors.New(apperrors.KindValidation, "setting_invalid", "invalid setting value")
+
+	// Организации
+	ErrOrganizationNotFound = apperrors.New(apperrors.KindNotFound, "organization_not_found", "organization not found")
+	ErrOrganizationExists   = apperrors.New(apperrors.KindConflict, "organization_exists", "organization with this name already exists")
+	ErrRoleNotAllowed       = apperrors.New(apperrors.KindForbidden, "role_not_allowed", "this role cannot be assigned by an organization admin")
+
+	// Самостоятельная регистрация
+	ErrAccountNotVerified       = apperrors.New(apperrors.KindForbidden, "account_not_verified", "email address is not verified yet")
+	ErrAccountPendingApproval   = apperrors.New(apperrors.KindForbidden, "account_pending_approval", "account is awaiting administrator approval")
+	ErrAccountRejected          = apperrors.New(apperrors.KindForbidden, "account_rejected", "registration was rejected")
+	ErrVerificationTokenInvalid = apperrors.New(apperrors.KindValidation, "verification_token_invalid", "verification link is invalid or expired")
+	ErrUserNotPendingApproval   = apperrors.New(apperrors.KindConflict, "user_not_pending_approval", "user is not awaiting approval")
+
+	// Работа от имени пользователя
+	ErrImpersonationNotAllowed = apperrors.New(apperrors.KindForbidden, "impersonation_not_allowed", "cannot act as this user")
+	ErrImpersonationNested     = apperrors.New(apperrors.KindForbidden, "impersonation_nested", "cannot start impersonation from an impersonated session")
+
+	// QR-коды ячеек
+	ErrQRFormatInvalid = apperrors.New(apperrors.KindValidation, "qr_format_invalid", "unsupported QR code format")
+	ErrQRSizeInvalid   = apperrors.New(apperrors.KindValidation, "qr_size_invalid", "QR code size out of range")
+	ErrCellTagInvalid  = apperrors.New(apperrors.KindValidation, "cell_tag_invalid", "unrecognized cell code")
+
+	// Учет электроэнергии
+	ErrMeterCellNotFeeder    = apperrors.New(apperrors.KindValidation, "meter_cell_not_feeder", "meter readings are accepted for output cells only")
+	ErrMeterReadingDecreased = apperrors.New(apperrors.KindConflict, "meter_reading_decreased", "reading is lower than the previous one; mark it as a meter replacement")
+	ErrEnergyMonthInvalid    = apperrors.New(apperrors.KindValidation, "energy_month_invalid", "month must be in YYYY-MM format")
+	ErrVoltageCellNotLowSide = apperrors.New(apperrors.KindValidation, "voltage_cell_not_low_side", "voltage measurements are accepted for low-side cells only")
+	// ErrVoltageReportRangeInvalid - даты отчета о качестве напряжения не разобраны или период длиннее 93 суток
+	ErrVoltageReportRangeInvalid = apperrors.New(apperrors.KindValidation, "voltage_report_range_invalid", "from and to must be YYYY-MM-DD dates, at most 93 days apart")
+
+	// Потребители
+	ErrConsumerNotFound             = apperrors.New(apperrors.KindNotFound, "consumer_not_found", "consumer not found")
+	ErrConsumerExists               = apperrors.New(apperrors.KindConflict, "consumer_exists", "consumer with this contract number already exists")
+	ErrConsumerCellNotFeeder        = apperrors.New(apperrors.KindValidation, "consumer_cell_not_feeder", "consumers can be linked to output cells only")
+	ErrConsumerFeederNotFound       = apperrors.New(apperrors.KindNotFound, "consumer_feeder_not_found", "consumer is not linked to this cell")
+	ErrConsumerOrganizationMismatch = apperrors.New(apperrors.KindValidation, "consumer_organization_mismatch", "consumer and RU belong to different organizations")
+	ErrImpactCellsRequired          = apperrors.New(apperrors.KindValidation, "impact_cells_required", "at least one cell is required")
+
+	// Плановые отключения
+	ErrOutageNotFound         = apperrors.New(apperrors.KindNotFound, "outage_not_found", "planned outage not found")
+	ErrOutageState            = apperrors.New(apperrors.KindConflict, "outage_invalid_state", "planned outage is not in a state that allows this action")
+	ErrOutageWindowInvalid    = apperrors.New(apperrors.KindValidation, "outage_window_invalid", "outage must start in the future and end after it starts")
+	ErrOutageSelfReview       = apperrors.New(apperrors.KindForbidden, "outage_self_review", "planned outage must be reviewed by another person")
+	ErrOutageCancelNotAllowed = apperrors.New(apperrors.KindForbidden, "outage_cancel_not_allowed", "only the requester or an engineer can cancel a planned outage")
+
+	// Подписка на календарь iCal
+	ErrCalendarFeedTokenInvalid  = apperrors.New(apperrors.KindUnauthorized, "calendar_feed_token_invalid", "invalid or revoked calendar feed token")
+	ErrCalendarFeedTokenNotFound = apperrors.New(apperrors.KindNotFound, "calendar_feed_token_not_found", "calendar feed subscription not found")
+
+	// Метрики Prometheus
+	ErrMetricsUnauthorized = apperrors.New(apperrors.KindUnauthorized, "metrics_unauthorized", "metrics require a valid bearer token")
+
+	// SMS-уведомления
+	ErrSmsMonthInvalid = apperrors.New(apperrors.KindValidation, "sms_month_invalid", "month must be in YYYY-MM format")
+
+	// Web Push
+	ErrPushDisabled             = apperrors.New(apperrors.KindUnavailable, "push_disabled", "web push is not configured")
+	ErrPushSubscriptionInvalid  = apperrors.New(apperrors.KindValidation, "push_subscription_invalid", "invalid push subscription")
+	ErrPushSubscriptionNotFound = apperrors.New(apperrors.KindNotFound, "push_subscription_not_found", "push subscription not found")
+
+	// Синхронизация планшетов
+	ErrSyncCursorInvalid  = apperrors.New(apperrors.KindValidation, "sync_cursor_invalid", "sync cursor must be an RFC 3339 timestamp")
+	ErrSyncOperationEmpty = apperrors.New(apperrors.KindValidation, "sync_operation_empty", "operation must contain a status change or a history record")
+	ErrSyncCellChanged    = apperrors.New(apperrors.KindConflict, "sync_cell_changed", "cell was changed on the server after the device last synced it")
+
+	// Тепловизионный контроль
+	ErrThermalSnapshotNotFound = apperrors.New(apperrors.KindNotFound, "thermal_snapshot_not_found", "thermal snapshot not found")
+	ErrThermalSnapshotInFuture = apperrors.New(apperrors.KindValidation, "thermal_snapshot_in_future", "thermal snapshot time cannot be in the future")
+)
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// parseEscalationChain - разбирает шаги вида "role:delay" ("engineer:10m") и
+// упорядочивает их по задержке
+func parseEscalationChain(items []string) ([]models.EscalationStep, error) {
+	steps := make([]models.EscalationStep, 0, len(items))
+	for _, item := range items {
+		role, delay, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("step %q: expected \"role:delay\"", item)
+		}
+		switch models.UserRole(role) {
+		case models.RoleDispatcher, models.RoleEngineer, models.RoleOrgAdmin, models.RoleAdmin:
+		default:
+			return nil, fmt.Errorf("step %q: unknown role %q", item, role)
+		}
+		duration, err := time.ParseDuration(delay)
+		if err != nil || duration < 0 {
+			return nil, fmt.Errorf("step %q: expected non-negative duration such as \"10m\"", item)
+		}
+		steps = append(steps, models.EscalationStep{Role: models.UserRole(role), Delay: duration})
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].Delay < steps[j].Delay
+	})
+	return steps, nil
+}
+
+// EscalateAlarms - выполняет наступившие шаги эскалации для активных критичных аварий.
+// Уведомление и запись шага идемпотентны: повторный запуск (или второй экземпляр)
+// не уведомляет роль дважды. Квитированная авария дальше не эскалируется.
+func (s *AlarmService) EscalateAlarms(ctx context.Context, now time.Time) error {
+	steps, err := parseEscalationChain(s.settings.StringList(SettingAlarmEscalationChain))
+	if err != nil {
+		return fmt.Errorf("invalid escalation chain: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil
+	}
+
+	alarms, err := s.alarmRepo.GetAlarms(models.AlarmFilter{
+		Severity: models.AlarmSeverityCritical,
+		Status:   models.AlarmStatusActive,
+	}, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get active alarms: %w", err)
+	}
+	if err := s.attachEscalations(alarms); err != nil {
+		return err
+	}
+
+	for _, alarm := range alarms {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		done := make(map[int]bool, len(alarm.Escalations))
+		for _, escalation := range alarm.Escalations {
+			done[escalation.Step] = true
+		}
+		for i, step := range steps {
+			if now.Sub(alarm.RaisedAt) < step.Delay {
+				break
+			}
+			if done[i] {
+				continue
+			}
+			if err := s.escalate(alarm, i, step, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *AlarmService) escalate(alarm models.Alarm, index int, step models.EscalationStep, now time.Time) error {
+	users, err := s.userRepo.GetUsersByRole(string(step.Role))
+	if err != nil {
+		return fmt.Errorf("failed to get users by role: %w", err)
+	}
+
+	if len(users) > 0 {
+		event := models.NotificationEvent{
+			RuID:     alarm.RuID,
+			Category: models.EventCategoryAlarm,
+			Title:    i18n.T(i18n.Default, "alarm.escalation.title", alarm.CellNumber, int(now.Sub(alarm.RaisedAt).Minutes())),
+			Message:  alarm.Message,
+			SourceID: fmt.Sprintf("%s:%s:%d", JobAlarmEscalation, alarm.ID, index),
+		}
+		if alarm.CellID != nil {
+			event.CellID = *alarm.CellID
+		}
+		for _, user := range users {
+			event.UserIDs = append(event.UserIDs, user.ID)
+		}
+		if err := s.notifications.Dispatch(event); err != nil {
+			return fmt.Errorf("failed to notify %s: %w", step.Role, err)
+		}
+	}
+
+	return s.alarmRepo.CreateEscalation(&models.AlarmEscalation{
+		ID:          utils.NewID(models.IDPrefixAlarmEscalation),
+		AlarmID:     alarm.ID,
+		Step:        index,
+		Role:        step.Role,
+		Delay:       step.Delay.String(),
+		EscalatedAt: now,
+	})
+}
+
+// attachEscalations - заполняет историю эскалации аварий одним запросом
+func (s *AlarmService) attachEscalations(alarms []models.Alarm) error {
+	ids := make([]string, len(alarms))
+	for i, alarm := range alarms {
+		ids[i] = alarm.ID
+	}
+	escalations, err := s.alarmRepo.GetEscalations(ids)
+	if err != nil {
+		return err
+	}
+
+	byAlarm := make(map[string][]models.AlarmEscalation)
+	for _, escalation := range escalations {
+		byAlarm[escalation.AlarmID] = append(byAlarm[escalation.AlarmID], escalation)
+	}
+	for i := range alarms {
+		alarms[i].Escalations = byAlarm[alarms[i].ID]
+	}
+	return nil
+}
+
+// AckTasks - активные критичные аварии РУ организации, которые пользователь должен
+// квитировать: авария попадает во входящие роли на ее шаге цепочки эскалации со сроком
+// до следующего шага. Роль вне цепочки (или при выключенной эскалации) задач не получает.
+func (s *AlarmService) AckTasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	steps, err := parseEscalationChain(s.settings.StringList(SettingAlarmEscalationChain))
+	if err != nil {
+		return nil, fmt.Errorf("invalid escalation chain: %w", err)
+	}
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	actor := models.UserActor(user)
+	alarms, err := s.alarmRepo.GetAlarms(models.AlarmFilter{
+		Severity:       models.AlarmSeverityCritical,
+		Status:         models.AlarmStatusActive,
+		OrganizationID: actor.OrganizationScope(),
+	}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active alarms: %w", err)
+	}
+
+	var tasks []models.Task
+	for _, alarm := range alarms {
+		deadline, assigned := alarmAckDeadline(steps, actor.Role, alarm.RaisedAt, now)
+		if !assigned {
+			continue
+		}
+		task := models.Task{
+			Type:     models.TaskTypeAcknowledgment,
+			RefID:    alarm.ID,
+			Title:    i18n.T(lang, "task.alarm_ack", alarm.Message),
+			RuID:     alarm.RuID,
+			Deadline: deadline,
+		}
+		if deadline != nil {
+			task.Overdue = deadline.Before(now)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// alarmAckDeadline - должна ли роль квитировать аварию к моменту now и до какого срока:
+// роль получает аварию на первом своем шаге цепочки, срок - задержка следующего шага
+// (nil на последнем шаге)
+func alarmAckDeadline(steps []models.EscalationStep, role models.UserRole, raisedAt, now time.Time) (*time.Time, bool) {
+	for i, step := range steps {
+		if step.Role != role {
+			continue
+		}
+		if now.Sub(raisedAt) < step.Delay {
+			return nil, false
+		}
+		if i+1 < len(steps) {
+			deadline := raisedAt.Add(steps[i+1].Delay)
+			return &deadline, true
+		}
+		return nil, true
+	}
+	return nil, false
+}
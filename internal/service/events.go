@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	eventPollInterval = 2 * time.Second
+	eventBatchSize    = 50
+	eventMaxAttempts  = 10
+	eventRetryBackoff = 30 * time.Second
+)
+
+// EventHandler - подписчик на доменные события (уведомления, вебхуки, WebSocket и т.д.)
+type EventHandler func(event *models.OutboxEvent) error
+
+type eventSubscriber struct {
+	name    string
+	handler EventHandler
+}
+
+// EventBus - диспетчер доменных событий из outbox. Доставка "как минимум один раз":
+// при ошибке любого подписчика событие повторяется для всех, поэтому обработчики должны быть идемпотентны.
+type EventBus struct {
+	outboxRepo  *repository.OutboxRepository
+	mu          sync.RWMutex
+	subscribers map[models.DomainEventType][]eventSubscriber
+	all         []eventSubscriber
+}
+
+func NewEventBus(outboxRepo *repository.OutboxRepository) *EventBus {
+	return &EventBus{
+		outboxRepo:  outboxRepo,
+		subscribers: make(map[models.DomainEventType][]eventSubscriber),
+	}
+}
+
+// Subscribe - подписывает обработчик на события указанных типов; без типов - на все события
+func (b *EventBus) Subscribe(name string, handler EventHandler, types ...models.DomainEventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := eventSubscriber{name: name, handler: handler}
+	if len(types) == 0 {
+		b.all = append(b.all, sub)
+		return
+	}
+	for _, t := range types {
+		b.subscribers[t] = append(b.subscribers[t], sub)
+	}
+}
+
+// Run - цикл доставки событий; завершается при отмене контекста
+func (b *EventBus) Run(ctx context.Context) {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				n, err := b.outboxRepo.ProcessPending(eventBatchSize, eventMaxAttempts, eventRetryBackoff, b.deliver)
+				if err != nil {
+					log.Printf("⚠️ Failed to process outbox: %v", err)
+					break
+				}
+				if n < eventBatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (b *EventBus) deliver(event *models.OutboxEvent) error {
+	b.mu.RLock()
+	subs := append(append([]eventSubscriber{}, b.subscribers[event.Type]...), b.all...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, sub := range subs {
+		if err := sub.handler(event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		log.Printf("⚠️ Event %s (%s) delivery failed: %v", event.ID, event.Type, errors.Join(errs...))
+	}
+	return errors.Join(errs...)
+}
+
+// GetEvents - события outbox для администрирования
+func (b *EventBus) GetEvents(status string, limit int) ([]models.OutboxEvent, error) {
+	events, err := b.outboxRepo.GetEvents(status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+	return events, nil
+}
+
+// RequeueEvent - повторная доставка события, исчерпавшего попытки
+func (b *EventBus) RequeueEvent(id string) error {
+	requeued, err := b.outboxRepo.RequeueEvent(utils.NormalizeID(models.IDPrefixEvent, id))
+	if err != nil {
+		return fmt.Errorf("failed to requeue event: %w", err)
+	}
+	if !requeued {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+// newEvent - формирует запись outbox для доменного события
+func newEvent(eventType models.DomainEventType, ruID string, payload interface{}) (models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.OutboxEvent{}, fmt.Errorf("failed to encode event payload: %w", err)
+	}
+	now := time.Now()
+	return models.OutboxEvent{
+		ID:          utils.NewID(models.IDPrefixEvent),
+		Type:        eventType,
+		RuID:        ruID,
+		Payload:     string(data),
+		Status:      models.OutboxStatusPending,
+		AvailableAt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// decodePayload - разбирает данные события в структуру
+func decodePayload(event *models.OutboxEvent, payload interface{}) error {
+	if err := json.Unmarshal([]byte(event.Payload), payload); err != nil {
+		return fmt.Errorf("failed to decode %s payload: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+type FaultService struct {
+	faultRepo *repository.FaultRepository
+	ruRepo    *repository.RuRepository
+}
+
+func NewFaultService(faultRepo *repository.FaultRepository, ruRepo *repository.RuRepository) *FaultService {
+	return &FaultService{faultRepo: faultRepo, ruRepo: ruRepo}
+}
+
+// RecordFault - регистрирует срабатывание защиты и поднимает аварию через событие fault.recorded.
+// Повторная передача с тем же ExternalID возвращает ранее записанное отключение.
+// РУ должно относиться к организации пользователя.
+func (s *FaultService) RecordFault(ruID string, input *models.FaultInput, source models.FaultSource, actor models.Actor) (*models.FaultEvent, bool, error) {
+	if err := checkRuAccess(s.ruRepo, actor, ruID); err != nil {
+		return nil, false, err
+	}
+	if input.ExternalID != nil {
+		existing, err := s.faultRepo.GetByExternalID(*input.ExternalID)
+		if err == nil {
+			// Отключение другого РУ с тем же ExternalID не раскрывается
+			if existing.RuID != ruID {
+				return nil, false, ErrFaultExternalIDTaken
+			}
+			return existing, false, nil
+		}
+		if !repository.IsNotFound(err) {
+			return nil, false, fmt.Errorf("failed to check fault event: %w", err)
+		}
+	}
+
+	cell, err := s.ruRepo.GetCellByID(input.CellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, false, ErrCellNotFound
+		}
+		return nil, false, fmt.Errorf("failed to get cell: %w", err)
+	}
+
+	fault := &models.FaultEvent{
+		ID:           utils.NewID(models.IDPrefixFault),
+		RuID:         ruID,
+		CellID:       cell.ID,
+		CellNumber:   cell.Number,
+		TripType:     input.TripType,
+		FaultCurrent: input.FaultCurrent,
+		AutoReclose:  input.AutoReclose,
+		OccurredAt:   input.OccurredAt,
+		Source:       source,
+		ExternalID:   input.ExternalID,
+		Comment:      input.Comment,
+		RecordedBy:   actor.Email,
+		CreatedAt:    time.Now(),
+	}
+
+	event, err := newEvent(models.EventFaultRecorded, ruID, models.FaultRecordedPayload{
+		FaultID:      fault.ID,
+		CellID:       cell.ID,
+		CellNumber:   cell.Number,
+		TripType:     fault.TripType,
+		FaultCurrent: fault.FaultCurrent,
+		AutoReclose:  fault.AutoReclose,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	fault.EventID = event.ID
+
+	if err := s.faultRepo.Create(fault, []models.OutboxEvent{event}); err != nil {
+		return nil, false, fmt.Errorf("failed to record fault: %w", err)
+	}
+	return fault, true, nil
+}
+
+// GetFaults - журнал отключений РУ для разбора аварий
+func (s *FaultService) GetFaults(ruID string, filter models.FaultFilter) ([]models.FaultEvent, error) {
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	faults, err := s.faultRepo.GetFaults(ruID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get faults: %w", err)
+	}
+	return faults, nil
+}
+
+func (s *FaultService) GetFault(ruID, faultID string) (*models.FaultEvent, error) {
+	fault, err := s.faultRepo.GetByID(ruID, utils.NormalizeID(models.IDPrefixFault, faultID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrFaultNotFound
+		}
+		return nil, fmt.Errorf("failed to get fault: %w", err)
+	}
+	return fault, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// forecastWeeks - сколько прошлых недель усредняет недельная сезонная модель
+	forecastWeeks = 4
+	// forecastDays - сколько прошлых суток усредняет суточная модель
+	forecastDays = 7
+	// forecastRunsLimit - прогнозы в истории точности
+	forecastRunsLimit = 50
+	// forecastEvaluationDelay - запас после окончания горизонта, чтобы часовые агрегаты успели посчитаться
+	forecastEvaluationDelay = time.Hour
+	forecastEvaluationBatch = 100
+)
+
+// forecastCellTypes - ячейки, для которых прогноз строится по умолчанию:
+// вводы секций и трансформаторы
+var forecastCellTypes = map[models.CellType]bool{
+	models.CellTypeInput:       true,
+	models.CellTypeTransformer: true,
+}
+
+// ForecastService - прогноз нагрузки ячеек на сутки/неделю по часовым агрегатам телеметрии.
+// Модель сезонная: значение часа - среднее за тот же час предыдущих недель
+// (или суток, если истории меньше недели). Каждый прогноз сохраняется, и по прошествии
+// горизонта для него считаются MAE и MAPE относительно факта.
+type ForecastService struct {
+	forecastRepo    *repository.ForecastRepository
+	measurementRepo *repository.MeasurementRepository
+	ruRepo          *repository.RuRepository
+}
+
+func NewForecastService(forecastRepo *repository.ForecastRepository, measurementRepo *repository.MeasurementRepository, ruRepo *repository.RuRepository) *ForecastService {
+	return &ForecastService{
+		forecastRepo:    forecastRepo,
+		measurementRepo: measurementRepo,
+		ruRepo:          ruRepo,
+	}
+}
+
+// Forecast - прогноз для ячейки из запроса или для всех вводов и трансформаторов РУ.
+// Прогноз, уже построенный в текущем часе, переиспользуется.
+func (s *ForecastService) Forecast(ruID string, query models.ForecastQuery, now time.Time) (*models.RuForecast, error) {
+	horizon := query.Horizon
+	if horizon == "" {
+		horizon = models.ForecastHorizonDay
+	}
+
+	var cells []models.Cell
+	if query.CellID != nil {
+		cell, err := s.ruRepo.GetCellByID(*query.CellID, ruID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrCellNotFound
+			}
+			return nil, fmt.Errorf("failed to get cell: %w", err)
+		}
+		cells = append(cells, *cell)
+	} else {
+		all, err := s.ruRepo.GetCellsByRuID(ruID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cells: %w", err)
+		}
+		for _, cell := range all {
+			if forecastCellTypes[cell.Type] {
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	result := &models.RuForecast{RuID: ruID, Horizon: horizon, Forecasts: []models.ForecastRun{}}
+	for _, cell := range cells {
+		run, err := s.cellForecast(ruID, cell.ID, horizon, now)
+		if err != nil {
+			return nil, err
+		}
+		if run == nil {
+			// Нет телеметрии нагрузки - прогнозировать не из чего
+			if query.CellID != nil {
+				return nil, ErrForecastNoHistory
+			}
+			continue
+		}
+		result.Forecasts = append(result.Forecasts, *run)
+	}
+	return result, nil
+}
+
+func (s *ForecastService) cellForecast(ruID string, cellID int, horizon string, now time.Time) (*models.ForecastRun, error) {
+	start := now.Truncate(time.Hour)
+
+	existing, err := s.forecastRepo.GetLatestRun(cellID, horizon, start)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.Points, err = s.forecastRepo.GetPoints(existing.ID); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	historyFrom := start.Add(-forecastWeeks * 7 * 24 * time.Hour)
+	history, err := s.measurementRepo.GetRollups(models.Resolution1h, cellID, models.MetricLoad, historyFrom, start)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	hours := models.ForecastHorizons[horizon]
+	model, points := seasonalForecast(history, start, hours)
+	run := &models.ForecastRun{
+		ID:           utils.NewID(models.IDPrefixForecast),
+		RuID:         ruID,
+		CellID:       cellID,
+		Metric:       models.MetricLoad,
+		Model:        model,
+		Horizon:      horizon,
+		StartAt:      start,
+		EndAt:        start.Add(time.Duration(hours) * time.Hour),
+		HistoryHours: len(history),
+		Points:       points,
+	}
+	if err := s.forecastRepo.CreateRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// seasonalForecast - почасовой прогноз на hours часов от start. Недельная модель
+// используется, если история покрывает хотя бы неделю; час без данных в прошлые
+// недели берется из суточной модели, а если нет и ее - среднее по всей истории.
+func seasonalForecast(history []models.MeasurementRollup, start time.Time, hours int) (string, []models.ForecastPoint) {
+	byHour := make(map[int64]float64, len(history))
+	total := 0.0
+	for _, rollup := range history {
+		byHour[rollup.BucketStart.Unix()] = rollup.Avg
+		total += rollup.Avg
+	}
+	overall := total / float64(len(history))
+
+	const week = 7 * 24 * time.Hour
+	model := models.ForecastModelSeasonalDaily
+	if !history[0].BucketStart.After(start.Add(-week)) {
+		model = models.ForecastModelSeasonalWeekly
+	}
+
+	// seasonalMean - среднее значений на period, 2*period, ... назад; сами прогнозные
+	// часы за пределами истории пропускаются
+	seasonalMean := func(at time.Time, period time.Duration, count int) (float64, bool) {
+		sum, n := 0.0, 0
+		for k := 1; k <= count; k++ {
+			past := at.Add(-time.Duration(k) * period)
+			if !past.Before(start) {
+				continue
+			}
+			if value, ok := byHour[past.Unix()]; ok {
+				sum += value
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, false
+		}
+		return sum / float64(n), true
+	}
+
+	points := make([]models.ForecastPoint, hours)
+	for i := range points {
+		at := start.Add(time.Duration(i) * time.Hour)
+		value, ok := 0.0, false
+		if model == models.ForecastModelSeasonalWeekly {
+			value, ok = seasonalMean(at, week, forecastWeeks)
+		}
+		if !ok {
+			value, ok = seasonalMean(at, 24*time.Hour, forecastDays)
+		}
+		if !ok {
+			value = overall
+		}
+		points[i] = models.ForecastPoint{At: at, Value: math.Round(value*100) / 100}
+	}
+	return model, points
+}
+
+// GetRuns - история прогнозов РУ с метриками точности
+func (s *ForecastService) GetRuns(ruID string, cellID *int) ([]models.ForecastRun, error) {
+	return s.forecastRepo.GetRuns(ruID, cellID, forecastRunsLimit)
+}
+
+// EvaluateAccuracy - сравнивает с фактом прогнозы, горизонт которых прошел.
+// Прогноз без фактических данных тоже помечается оцененным (compared = 0).
+func (s *ForecastService) EvaluateAccuracy(ctx context.Context, now time.Time) error {
+	runs, err := s.forecastRepo.GetPendingEvaluation(now.Add(-forecastEvaluationDelay), forecastEvaluationBatch)
+	if err != nil {
+		return err
+	}
+
+	for i := range runs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		run := &runs[i]
+
+		points, err := s.forecastRepo.GetPoints(run.ID)
+		if err != nil {
+			return err
+		}
+		actual, err := s.measurementRepo.GetRollups(models.Resolution1h, run.CellID, run.Metric, run.StartAt, run.EndAt)
+		if err != nil {
+			return err
+		}
+		actualByHour := make(map[int64]float64, len(actual))
+		for _, rollup := range actual {
+			actualByHour[rollup.BucketStart.Unix()] = rollup.Avg
+		}
+
+		var absSum, pctSum float64
+		pctCount := 0
+		for j := range points {
+			value, ok := actualByHour[points[j].At.Unix()]
+			if !ok {
+				continue
+			}
+			points[j].Actual = &value
+			run.Compared++
+			absSum += math.Abs(points[j].Value - value)
+			if value != 0 {
+				pctSum += math.Abs(points[j].Value-value) / math.Abs(value)
+				pctCount++
+			}
+		}
+		if run.Compared > 0 {
+			mae := math.Round(absSum/float64(run.Compared)*1000) / 1000
+			run.MAE = &mae
+		}
+		if pctCount > 0 {
+			mape := math.Round(pctSum/float64(pctCount)*100*100) / 100
+			run.MAPE = &mape
+		}
+		evaluatedAt := now
+		run.EvaluatedAt = &evaluatedAt
+
+		if err := s.forecastRepo.SaveEvaluation(run, points); err != nil {
+			return err
+		}
+	}
+	return nil
+}
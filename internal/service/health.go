@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+// healthCheckTimeout - сколько ждать ответа одной зависимости
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck - проверка зависимости. Check == nil - зависимость не настроена;
+// Details - дополнительные сведения о ней (например, пул соединений базы данных).
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+	Details  func() map[string]interface{}
+}
+
+// HealthService - готовность экземпляра принимать трафик по состоянию зависимостей.
+// Недоступная критичная зависимость делает экземпляр неготовым, некритичная - деградировавшим.
+type HealthService struct {
+	checks []HealthCheck
+}
+
+func NewHealthService() *HealthService {
+	return &HealthService{}
+}
+
+// AddCheck - регистрирует проверку зависимости
+func (s *HealthService) AddCheck(check HealthCheck) {
+	s.checks = append(s.checks, check)
+}
+
+// Readiness - проверяет все зависимости параллельно, каждую с таймаутом
+func (s *HealthService) Readiness(ctx context.Context) models.HealthReport {
+	components := make([]models.ComponentHealth, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		components[i] = models.ComponentHealth{Name: check.Name, Critical: check.Critical, Status: models.ComponentDisabled}
+		if check.Check == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(component *models.ComponentHealth, check HealthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			started := time.Now()
+			err := check.Check(checkCtx)
+			component.LatencyMs = math.Round(float64(time.Since(started).Microseconds())/10) / 100
+			component.Status = models.ComponentUp
+			if err != nil {
+				component.Status = models.ComponentDown
+				component.Error = err.Error()
+			}
+			if check.Details != nil {
+				component.Details = check.Details()
+			}
+		}(&components[i], check)
+	}
+	wg.Wait()
+
+	status := models.HealthOK
+	for _, component := range components {
+		if component.Status != models.ComponentDown {
+			continue
+		}
+		if component.Critical {
+			status = models.HealthUnavailable
+			break
+		}
+		status = models.HealthDegraded
+	}
+	return models.HealthReport{Status: status, Components: components, CheckedAt: time.Now()}
+}
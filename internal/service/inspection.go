@@ -0,0 +1,322 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const inspectionsDefaultLimit = 50
+
+type InspectionService struct {
+	inspectionRepo *repository.InspectionRepository
+	ruRepo         *repository.RuRepository
+	photos         *PhotoService
+}
+
+func NewInspectionService(inspectionRepo *repository.InspectionRepository, ruRepo *repository.RuRepository, photos *PhotoService) *InspectionService {
+	return &InspectionService{inspectionRepo: inspectionRepo, ruRepo: ruRepo, photos: photos}
+}
+
+func (s *InspectionService) GetTemplates(ruType string, activeOnly bool) ([]models.ChecklistTemplate, error) {
+	templates, err := s.inspectionRepo.GetTemplates(ruType, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checklist templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (s *InspectionService) CreateTemplate(req *models.SaveChecklistTemplateRequest) (*models.ChecklistTemplate, error) {
+	now := time.Now()
+	template := &models.ChecklistTemplate{
+		ID:        utils.NewID(models.IDPrefixChecklist),
+		CreatedAt: now,
+	}
+	applyTemplateRequest(template, req, now)
+
+	if err := s.inspectionRepo.CreateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to create checklist template: %w", err)
+	}
+	return template, nil
+}
+
+// UpdateTemplate - полная замена шаблона. Прошлые осмотры хранят копию текста пунктов.
+func (s *InspectionService) UpdateTemplate(id string, req *models.SaveChecklistTemplateRequest) (*models.ChecklistTemplate, error) {
+	template, err := s.getTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+	applyTemplateRequest(template, req, time.Now())
+
+	if err := s.inspectionRepo.ReplaceTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to update checklist template: %w", err)
+	}
+	return template, nil
+}
+
+func (s *InspectionService) DeleteTemplate(id string) error {
+	deleted, err := s.inspectionRepo.DeleteTemplate(utils.NormalizeID(models.IDPrefixChecklist, id))
+	if err != nil {
+		return fmt.Errorf("failed to delete checklist template: %w", err)
+	}
+	if !deleted {
+		return ErrChecklistNotFound
+	}
+	return nil
+}
+
+func (s *InspectionService) GetInspections(ruID string, limit int) ([]models.Inspection, error) {
+	if limit <= 0 {
+		limit = inspectionsDefaultLimit
+	}
+	inspections, err := s.inspectionRepo.GetInspections(ruID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inspections: %w", err)
+	}
+	return inspections, nil
+}
+
+// GetInspection - осмотр РУ организации пользователя; чужой не отличается от несуществующего
+func (s *InspectionService) GetInspection(actor models.Actor, id string) (*models.Inspection, error) {
+	inspection, err := s.inspectionRepo.GetInspection(utils.NormalizeID(models.IDPrefixInspection, id), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrInspectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get inspection: %w", err)
+	}
+
+	ids := make([]string, len(inspection.Results))
+	index := make(map[string]int, len(inspection.Results))
+	for i := range inspection.Results {
+		ids[i] = inspection.Results[i].ID
+		index[ids[i]] = i
+		inspection.Results[i].Photos = []models.Photo{}
+	}
+	photos, err := s.photos.GetByOwners(models.PhotoOwnerInspectionItem, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get photos: %w", err)
+	}
+	for _, photo := range photos {
+		if i, ok := index[photo.OwnerID]; ok {
+			inspection.Results[i].Photos = append(inspection.Results[i].Photos, photo)
+		}
+	}
+	return inspection, nil
+}
+
+// SubmitInspection - регистрирует осмотр РУ: все пункты шаблона должны быть заполнены,
+// по непройденным пунктам заводятся дефекты, дата последнего осмотра РУ обновляется.
+func (s *InspectionService) SubmitInspection(ruID string, req *models.SubmitInspectionRequest, actor models.Actor) (*models.Inspection, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	template, err := s.getTemplate(req.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.RuType != "" && template.RuType != ru.Type {
+		return nil, ErrChecklistRuType
+	}
+
+	answers := make(map[string]models.InspectionResultRequest, len(req.Results))
+	for _, answer := range req.Results {
+		answers[utils.NormalizeID(models.IDPrefixChecklistItem, answer.ItemID)] = answer
+	}
+
+	now := time.Now()
+	inspectedAt := now
+	if req.InspectedAt != nil {
+		inspectedAt = *req.InspectedAt
+	}
+	if inspectedAt.After(now) {
+		return nil, ErrInspectionInFuture
+	}
+
+	inspection := &models.Inspection{
+		ID:           utils.NewID(models.IDPrefixInspection),
+		RuID:         ru.ID,
+		TemplateID:   template.ID,
+		TemplateName: template.Name,
+		InspectedAt:  inspectedAt,
+		InspectedBy:  actor.Email,
+		Remarks:      req.Remarks,
+		CreatedAt:    now,
+	}
+
+	var missing []string
+	var defects []models.Defect
+	for _, item := range template.Items {
+		answer, ok := answers[item.ID]
+		if !ok {
+			missing = append(missing, item.ID)
+			continue
+		}
+		delete(answers, item.ID)
+
+		result := models.InspectionResult{
+			ID:           utils.NewID(models.IDPrefixInspectionResult),
+			InspectionID: inspection.ID,
+			ItemID:       item.ID,
+			Position:     item.Position,
+			Text:         item.Text,
+			Result:       answer.Result,
+			Remark:       answer.Remark,
+		}
+
+		if answer.Result == models.InspectionItemFailed {
+			defect, err := s.inspectionDefect(ru.ID, inspection.ID, item, answer, actor, now)
+			if err != nil {
+				return nil, err
+			}
+			result.CellID = defect.CellID
+			result.DefectID = &defect.ID
+			defects = append(defects, *defect)
+			inspection.FailedCount++
+		}
+		inspection.Results = append(inspection.Results, result)
+	}
+	if len(missing) > 0 || len(answers) > 0 {
+		unknown := make([]string, 0, len(answers))
+		for id := range answers {
+			unknown = append(unknown, id)
+		}
+		return nil, ErrInspectionIncomplete.WithDetails(map[string]interface{}{
+			"missing": missing,
+			"unknown": unknown,
+		})
+	}
+
+	// Дата последнего осмотра только сдвигается вперед: внесение старого осмотра ее не откатывает
+	var updatedRu *models.RUInfo
+	if ru.LastInspectionAt == nil || inspectedAt.After(*ru.LastInspectionAt) {
+		ru.LastInspectionAt = &inspectedAt
+		ru.LastInspection = inspectedAt.Format(utils.LegacyDateLayout)
+		ru.UpdatedAt = now
+		updatedRu = ru
+	}
+
+	if err := s.inspectionRepo.SubmitInspection(inspection, updatedRu, defects); err != nil {
+		return nil, fmt.Errorf("failed to submit inspection: %w", err)
+	}
+	for i := range inspection.Results {
+		inspection.Results[i].Photos = []models.Photo{}
+	}
+	return inspection, nil
+}
+
+// AddResultPhoto - фотография к пункту осмотра
+func (s *InspectionService) AddResultPhoto(inspectionID, resultID, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	result, err := s.getResult(actor, inspectionID, resultID)
+	if err != nil {
+		return nil, err
+	}
+	return s.photos.Save(models.PhotoOwnerInspectionItem, result.ID, fileName, data, actor)
+}
+
+// GetResultPhoto - оригинал фотографии пункта осмотра или ее миниатюра (thumb)
+func (s *InspectionService) GetResultPhoto(actor models.Actor, inspectionID, resultID, photoID string, thumb bool) (*models.Photo, error) {
+	result, err := s.getResult(actor, inspectionID, resultID)
+	if err != nil {
+		return nil, err
+	}
+	return s.photos.Get(models.PhotoOwnerInspectionItem, result.ID, photoID, thumb)
+}
+
+// getResult - пункт осмотра РУ организации пользователя
+func (s *InspectionService) getResult(actor models.Actor, inspectionID, resultID string) (*models.InspectionResult, error) {
+	result, err := s.inspectionRepo.GetResult(
+		utils.NormalizeID(models.IDPrefixInspection, inspectionID),
+		utils.NormalizeID(models.IDPrefixInspectionResult, resultID),
+		actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrInspectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get inspection result: %w", err)
+	}
+	return result, nil
+}
+
+// inspectionDefect - дефект по непройденному пункту осмотра
+func (s *InspectionService) inspectionDefect(ruID, inspectionID string, item models.ChecklistItem, answer models.InspectionResultRequest, actor models.Actor, now time.Time) (*models.Defect, error) {
+	severity := item.DefectSeverity
+	if severity == "" {
+		severity = models.DefectSeverityMinor
+	}
+
+	defect := &models.Defect{
+		ID:           utils.NewID(models.IDPrefixDefect),
+		RuID:         ruID,
+		Title:        item.Text,
+		Description:  answer.Remark,
+		Severity:     severity,
+		Status:       models.DefectStatusOpen,
+		ReportedBy:   actor.Email,
+		InspectionID: &inspectionID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if defect.Description == "" {
+		defect.Description = i18n.T(i18n.Default, "inspection.defect_description")
+	}
+
+	if answer.CellID != nil {
+		cell, err := s.ruRepo.GetCellByID(*answer.CellID, ruID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrCellNotFound
+			}
+			return nil, fmt.Errorf("failed to get cell: %w", err)
+		}
+		defect.CellID = &cell.ID
+		defect.CellNumber = cell.Number
+	}
+	return defect, nil
+}
+
+func (s *InspectionService) getTemplate(id string) (*models.ChecklistTemplate, error) {
+	template, err := s.inspectionRepo.GetTemplate(utils.NormalizeID(models.IDPrefixChecklist, id))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrChecklistNotFound
+		}
+		return nil, fmt.Errorf("failed to get checklist template: %w", err)
+	}
+	return template, nil
+}
+
+// applyTemplateRequest - переносит поля запроса в шаблон; пункты получают новые ID
+func applyTemplateRequest(template *models.ChecklistTemplate, req *models.SaveChecklistTemplateRequest, now time.Time) {
+	template.Name = req.Name
+	template.RuType = req.RuType
+	template.Active = true
+	if req.Active != nil {
+		template.Active = *req.Active
+	}
+	template.UpdatedAt = now
+
+	template.Items = make([]models.ChecklistItem, len(req.Items))
+	for i, item := range req.Items {
+		severity := item.DefectSeverity
+		if severity == "" {
+			severity = models.DefectSeverityMinor
+		}
+		template.Items[i] = models.ChecklistItem{
+			ID:             utils.NewID(models.IDPrefixChecklistItem),
+			TemplateID:     template.ID,
+			Position:       i + 1,
+			Text:           item.Text,
+			DefectSeverity: severity,
+		}
+	}
+}
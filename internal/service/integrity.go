@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// chainVerifyBatch - записей цепочки за одну выборку при проверке
+	chainVerifyBatch = 1000
+	// chainMaxBreaks - предел нарушений в отчете; проверка при этом идет до конца
+	chainMaxBreaks = 100
+)
+
+// IntegrityService - проверка цепочек хешей журнала операций и аудита. Запись журнала
+// хранит хеш предыдущей записи, поэтому правка, удаление или вставка задним числом
+// видны как нарушение цепочки начиная с измененного места.
+type IntegrityService struct {
+	chainRepo *repository.ChainRepository
+}
+
+func NewIntegrityService(chainRepo *repository.ChainRepository) *IntegrityService {
+	return &IntegrityService{chainRepo: chainRepo}
+}
+
+// Verify - проходит цепочку от первой записи: сверяет нумерацию, ссылку на хеш
+// предыдущей записи и хеш содержимого каждой записи, а в конце - голову цепочки.
+// HeadHash в ответе стоит сохранять вне системы: переписанную целиком цепочку можно
+// обнаружить только по нему.
+func (s *IntegrityService) Verify(ctx context.Context, chain string) (*models.ChainVerification, error) {
+	head, err := s.chainRepo.GetHead(chain)
+	if err != nil {
+		return nil, err
+	}
+	// Имена цепочек совпадают с именами таблиц журналов
+	unchained, err := s.chainRepo.CountUnchained(chain)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ChainVerification{
+		Chain:     chain,
+		Valid:     true,
+		Unchained: unchained,
+		HeadSeq:   head.Seq,
+		HeadHash:  head.LastHash,
+		Breaks:    []models.ChainBreak{},
+	}
+	addBreak := func(b models.ChainBreak) {
+		result.Valid = false
+		if len(result.Breaks) < chainMaxBreaks {
+			result.Breaks = append(result.Breaks, b)
+		}
+	}
+
+	var lastSeq int64
+	prevHash := models.ChainGenesis
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		records, err := s.loadChain(chain, lastSeq)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			link := record.Link()
+			seq := *link.ChainSeq
+			switch {
+			case seq != lastSeq+1:
+				// Хеш удаленной записи неизвестен, поэтому ссылка после пропуска не сверяется
+				addBreak(models.ChainBreak{
+					Kind:     models.ChainBreakMissing,
+					Seq:      lastSeq + 1,
+					RecordID: record.ChainID(),
+					Expected: strconv.FormatInt(lastSeq+1, 10),
+					Actual:   strconv.FormatInt(seq, 10),
+				})
+			case link.PrevHash != prevHash:
+				addBreak(models.ChainBreak{
+					Kind:     models.ChainBreakRelinked,
+					Seq:      seq,
+					RecordID: record.ChainID(),
+					Expected: prevHash,
+					Actual:   link.PrevHash,
+				})
+			}
+			if hash := record.ChainHash(); hash != link.Hash {
+				addBreak(models.ChainBreak{
+					Kind:     models.ChainBreakModified,
+					Seq:      seq,
+					RecordID: record.ChainID(),
+					Expected: link.Hash,
+					Actual:   hash,
+				})
+			}
+			prevHash = link.Hash
+			lastSeq = seq
+			result.Verified++
+		}
+		if len(records) < chainVerifyBatch {
+			break
+		}
+	}
+
+	switch {
+	case lastSeq < head.Seq:
+		addBreak(models.ChainBreak{
+			Kind:     models.ChainBreakTruncated,
+			Seq:      lastSeq + 1,
+			Expected: strconv.FormatInt(head.Seq, 10),
+			Actual:   strconv.FormatInt(lastSeq, 10),
+		})
+	case lastSeq > head.Seq || prevHash != head.LastHash:
+		// Записи добавлены в обход головы цепочки или последняя запись переписана вместе с хешем
+		addBreak(models.ChainBreak{
+			Kind:     models.ChainBreakRelinked,
+			Seq:      lastSeq,
+			Expected: head.LastHash,
+			Actual:   prevHash,
+		})
+	}
+
+	result.VerifiedAt = time.Now()
+	return result, nil
+}
+
+func (s *IntegrityService) loadChain(chain string, afterSeq int64) ([]models.Chained, error) {
+	var records []models.Chained
+	switch chain {
+	case models.ChainOperationRecords:
+		rows, err := s.chainRepo.GetOperationRecords(afterSeq, chainVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			records = append(records, &rows[i])
+		}
+	case models.ChainAuditEntries:
+		rows, err := s.chainRepo.GetAuditEntries(afterSeq, chainVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			records = append(records, &rows[i])
+		}
+	case models.ChainCorrections:
+		rows, err := s.chainRepo.GetCorrections(afterSeq, chainVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rows {
+			records = append(records, &rows[i])
+		}
+	default:
+		return nil, fmt.Errorf("unknown chain %q", chain)
+	}
+	return records, nil
+}
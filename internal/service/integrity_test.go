@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+func TestVerifyAuditChain(t *testing.T) {
+	tests := []struct {
+		name string
+		// tamper - правка журнала в обход приложения (прямым SQL)
+		tamper    func(db *gorm.DB) error
+		wantValid bool
+		wantKinds []models.ChainBreakKind
+	}{
+		{name: "untouched chain", tamper: func(db *gorm.DB) error { return nil }, wantValid: true},
+		{
+			name: "modified entry",
+			tamper: func(db *gorm.DB) error {
+				return db.Exec("UPDATE audit_entries SET details = 'forged' WHERE chain_seq = 2").Error
+			},
+			wantKinds: []models.ChainBreakKind{models.ChainBreakModified},
+		},
+		{
+			name:      "deleted entry",
+			tamper:    func(db *gorm.DB) error { return db.Exec("DELETE FROM audit_entries WHERE chain_seq = 2").Error },
+			wantKinds: []models.ChainBreakKind{models.ChainBreakMissing},
+		},
+		{
+			name:      "deleted last entry",
+			tamper:    func(db *gorm.DB) error { return db.Exec("DELETE FROM audit_entries WHERE chain_seq = 3").Error },
+			wantKinds: []models.ChainBreakKind{models.ChainBreakTruncated},
+		},
+		{
+			// Переписанная запись с пересчитанным собственным хешем ломает ссылку следующей
+			name: "rewritten entry with its hash",
+			tamper: func(db *gorm.DB) error {
+				var entry models.AuditEntry
+				if err := db.Where("chain_seq = 2").First(&entry).Error; err != nil {
+					return err
+				}
+				entry.Details = "forged"
+				return db.Exec("UPDATE audit_entries SET details = ?, hash = ? WHERE id = ?", entry.Details, entry.ChainHash(), entry.ID).Error
+			},
+			wantKinds: []models.ChainBreakKind{models.ChainBreakRelinked},
+		},
+		{
+			name: "rewritten last entry with its hash",
+			tamper: func(db *gorm.DB) error {
+				var entry models.AuditEntry
+				if err := db.Where("chain_seq = 3").First(&entry).Error; err != nil {
+					return err
+				}
+				entry.Details = "forged"
+				return db.Exec("UPDATE audit_entries SET details = ?, hash = ? WHERE id = ?", entry.Details, entry.ChainHash(), entry.ID).Error
+			},
+			wantKinds: []models.ChainBreakKind{models.ChainBreakRelinked},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.AuditEntry{}, &models.HashChain{})
+			audit := NewAuditService(repository.NewAuditRepository(db))
+			for i := 1; i <= 3; i++ {
+				audit.Record(models.AuditEntry{Action: "test", UserEmail: "user@example.com", Details: fmt.Sprintf("entry %d", i)})
+			}
+			if err := tt.tamper(db); err != nil {
+				t.Fatalf("tamper: %v", err)
+			}
+
+			result, err := NewIntegrityService(repository.NewChainRepository(db)).Verify(context.Background(), models.ChainAuditEntries)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("valid = %v, want %v (breaks %+v)", result.Valid, tt.wantValid, result.Breaks)
+			}
+			if len(result.Breaks) != len(tt.wantKinds) {
+				t.Fatalf("breaks = %+v, want kinds %v", result.Breaks, tt.wantKinds)
+			}
+			for i, kind := range tt.wantKinds {
+				if result.Breaks[i].Kind != kind {
+					t.Errorf("break %d kind = %s, want %s", i, result.Breaks[i].Kind, kind)
+				}
+			}
+		})
+	}
+}
+
+func TestAuditJournalIsAppendOnly(t *testing.T) {
+	db := newTestDB(t, &models.AuditEntry{}, &models.HashChain{})
+	NewAuditService(repository.NewAuditRepository(db)).Record(models.AuditEntry{Action: "test", Details: "entry"})
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "update", run: func() error {
+			return db.Model(&models.AuditEntry{}).Where("1 = 1").Update("details", "forged").Error
+		}},
+		{name: "delete", run: func() error {
+			return db.Where("1 = 1").Delete(&models.AuditEntry{}).Error
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); !errors.Is(err, repository.ErrAppendOnly) {
+				t.Errorf("error = %v, want ErrAppendOnly", err)
+			}
+		})
+	}
+}
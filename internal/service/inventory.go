@@ -0,0 +1,333 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+type InventoryService struct {
+	inventoryRepo *repository.InventoryRepository
+	ruRepo        *repository.RuRepository
+	defectRepo    *repository.DefectRepository
+}
+
+func NewInventoryService(inventoryRepo *repository.InventoryRepository, ruRepo *repository.RuRepository, defectRepo *repository.DefectRepository) *InventoryService {
+	return &InventoryService{inventoryRepo: inventoryRepo, ruRepo: ruRepo, defectRepo: defectRepo}
+}
+
+// GetWarehouses - склады организации пользователя
+func (s *InventoryService) GetWarehouses(actor models.Actor) ([]models.Warehouse, error) {
+	warehouses, err := s.inventoryRepo.GetWarehouses(actor.OrganizationScope())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get warehouses: %w", err)
+	}
+	return warehouses, nil
+}
+
+func (s *InventoryService) CreateWarehouse(req *models.CreateWarehouseRequest, actor models.Actor) (*models.Warehouse, error) {
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() && req.OrganizationID != "" {
+		organizationID = req.OrganizationID
+	}
+
+	now := time.Now()
+	warehouse := &models.Warehouse{
+		ID:             utils.NewID(models.IDPrefixWarehouse),
+		Name:           req.Name,
+		Location:       req.Location,
+		OrganizationID: organizationID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.inventoryRepo.CreateWarehouse(warehouse); err != nil {
+		return nil, fmt.Errorf("failed to create warehouse: %w", err)
+	}
+	return warehouse, nil
+}
+
+func (s *InventoryService) GetItems(category string) ([]models.InventoryItem, error) {
+	items, err := s.inventoryRepo.GetItems(category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inventory items: %w", err)
+	}
+	return items, nil
+}
+
+func (s *InventoryService) CreateItem(req *models.CreateInventoryItemRequest) (*models.InventoryItem, error) {
+	exists, err := s.inventoryRepo.ExistsBySKU(req.SKU)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check SKU: %w", err)
+	}
+	if exists {
+		return nil, ErrInventorySKUExists
+	}
+
+	now := time.Now()
+	item := &models.InventoryItem{
+		ID:        utils.NewID(models.IDPrefixInventoryItem),
+		SKU:       req.SKU,
+		Name:      req.Name,
+		Category:  req.Category,
+		Unit:      req.Unit,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.inventoryRepo.CreateItem(item); err != nil {
+		return nil, fmt.Errorf("failed to create inventory item: %w", err)
+	}
+	return item, nil
+}
+
+// GetStock - остатки складов организации пользователя
+func (s *InventoryService) GetStock(actor models.Actor, filter models.StockFilter) ([]models.StockLevel, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	levels, err := s.inventoryRepo.GetStock(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock: %w", err)
+	}
+	return levels, nil
+}
+
+// GetReservations - резервы под РУ организации пользователя
+func (s *InventoryService) GetReservations(actor models.Actor, filter models.ReservationFilter) ([]models.Reservation, error) {
+	filter.OrganizationID = actor.OrganizationScope()
+	reservations, err := s.inventoryRepo.GetReservations(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+// Receive - приход запчастей на склад
+func (s *InventoryService) Receive(req *models.ReceiveStockRequest, actor models.Actor) (*models.StockLevel, error) {
+	warehouse, item, err := s.getStockKey(actor, req.WarehouseID, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateStock(warehouse, item, "", func(level *models.StockLevel, _ *models.Reservation) (*repository.StockChange, error) {
+		level.Quantity += req.Quantity
+		return &repository.StockChange{
+			Movement: newStockMovement(level, req.Quantity, models.MovementReceipt, nil, actor),
+		}, nil
+	})
+}
+
+// SetMinQuantity - минимальный остаток, ниже которого поднимается авария
+func (s *InventoryService) SetMinQuantity(req *models.SetMinStockRequest, actor models.Actor) (*models.StockLevel, error) {
+	warehouse, item, err := s.getStockKey(actor, req.WarehouseID, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateStock(warehouse, item, "", func(level *models.StockLevel, _ *models.Reservation) (*repository.StockChange, error) {
+		level.MinQuantity = req.MinQuantity
+		return &repository.StockChange{}, nil
+	})
+}
+
+// Reserve - резервирует запчасти под наряд-допуск, ТО РУ или дефект; склад и РУ
+// должны относиться к одной организации
+func (s *InventoryService) Reserve(req *models.ReserveStockRequest, actor models.Actor) (*models.Reservation, error) {
+	warehouse, item, err := s.getStockKey(actor, req.WarehouseID, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	organizationID, err := ruOrganization(s.ruRepo, actor, req.RuID)
+	if err != nil {
+		return nil, err
+	}
+	if organizationID != warehouse.OrganizationID {
+		return nil, ErrWarehouseOrganizationMismatch
+	}
+	targetID, err := s.checkTarget(req.TargetType, req.TargetID, req.RuID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reservation := &models.Reservation{
+		ID:          utils.NewID(models.IDPrefixReservation),
+		WarehouseID: warehouse.ID,
+		ItemID:      item.ID,
+		Quantity:    req.Quantity,
+		TargetType:  req.TargetType,
+		TargetID:    targetID,
+		RuID:        req.RuID,
+		Status:      models.ReservationActive,
+		ReservedBy:  actor.Email,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	_, err = s.updateStock(warehouse, item, "", func(level *models.StockLevel, _ *models.Reservation) (*repository.StockChange, error) {
+		level.Fill()
+		if level.Available < req.Quantity {
+			return nil, ErrStockInsufficient.WithDetails(map[string]interface{}{
+				"available": level.Available,
+				"requested": req.Quantity,
+			})
+		}
+		level.Reserved += req.Quantity
+		return &repository.StockChange{Reservation: reservation}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+// ConsumeReservation - списание зарезервированных запчастей после выполнения работ
+func (s *InventoryService) ConsumeReservation(id string, actor models.Actor) (*models.Reservation, error) {
+	return s.closeReservation(id, models.ReservationConsumed, actor)
+}
+
+// ReleaseReservation - снятие резерва, запчасти возвращаются в доступный остаток
+func (s *InventoryService) ReleaseReservation(id string, actor models.Actor) (*models.Reservation, error) {
+	return s.closeReservation(id, models.ReservationReleased, actor)
+}
+
+func (s *InventoryService) closeReservation(id string, status models.ReservationStatus, actor models.Actor) (*models.Reservation, error) {
+	current, err := s.inventoryRepo.GetReservation(utils.NormalizeID(models.IDPrefixReservation, id), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+	warehouse, item, err := s.getStockKey(actor, current.WarehouseID, current.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	var closed *models.Reservation
+	_, err = s.updateStock(warehouse, item, current.ID, func(level *models.StockLevel, reservation *models.Reservation) (*repository.StockChange, error) {
+		if reservation.Status != models.ReservationActive {
+			return nil, ErrReservationClosed
+		}
+
+		now := time.Now()
+		reservation.Status = status
+		reservation.ClosedBy = &actor.Email
+		reservation.ClosedAt = &now
+		reservation.UpdatedAt = now
+		level.Reserved -= reservation.Quantity
+
+		change := &repository.StockChange{Reservation: reservation}
+		if status == models.ReservationConsumed {
+			level.Quantity -= reservation.Quantity
+			change.Movement = newStockMovement(level, -reservation.Quantity, models.MovementConsume, &reservation.ID, actor)
+		}
+		closed = reservation
+		return change, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+
+// updateStock - изменение остатка с аварией при переходе доступного остатка ниже минимума
+func (s *InventoryService) updateStock(warehouse *models.Warehouse, item *models.InventoryItem, reservationID string, apply func(level *models.StockLevel, reservation *models.Reservation) (*repository.StockChange, error)) (*models.StockLevel, error) {
+	return s.inventoryRepo.UpdateStock(warehouse.ID, item.ID, reservationID, func(level *models.StockLevel, reservation *models.Reservation) (*repository.StockChange, error) {
+		level.Fill()
+		wasLow := level.Low
+
+		change, err := apply(level, reservation)
+		if err != nil {
+			return nil, err
+		}
+
+		level.Fill()
+		if level.Low && !wasLow {
+			event, err := newEvent(models.EventStockLow, "", models.StockLowPayload{
+				WarehouseID:   warehouse.ID,
+				WarehouseName: warehouse.Name,
+				ItemID:        item.ID,
+				ItemName:      item.Name,
+				Available:     level.Available,
+				MinQuantity:   level.MinQuantity,
+			})
+			if err != nil {
+				return nil, err
+			}
+			change.Events = append(change.Events, event)
+		}
+		return change, nil
+	})
+}
+
+// getStockKey - склад организации пользователя и позиция номенклатуры
+func (s *InventoryService) getStockKey(actor models.Actor, warehouseID, itemID string) (*models.Warehouse, *models.InventoryItem, error) {
+	warehouse, err := s.inventoryRepo.GetWarehouse(utils.NormalizeID(models.IDPrefixWarehouse, warehouseID), actor.OrganizationScope())
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrWarehouseNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+	item, err := s.inventoryRepo.GetItem(utils.NormalizeID(models.IDPrefixInventoryItem, itemID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrInventoryItemNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get inventory item: %w", err)
+	}
+	return warehouse, item, nil
+}
+
+// checkTarget - проверяет, что наряд, РУ или дефект существует и относится к РУ
+// (доступ к РУ проверен заранее). Возвращает нормализованный ID цели.
+func (s *InventoryService) checkTarget(targetType models.ReservationTarget, targetID, ruID string) (string, error) {
+	switch targetType {
+	case models.ReserveForWorkPermit:
+		record, err := s.ruRepo.GetHistoryRecordByID(ruID, utils.NormalizeID(models.IDPrefixOperation, targetID))
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return "", ErrReservationTarget
+			}
+			return "", fmt.Errorf("failed to get work permit: %w", err)
+		}
+		if record.WorkOrderNumber == nil {
+			return "", ErrReservationTarget
+		}
+		return record.ID, nil
+
+	case models.ReserveForMaintenance:
+		if targetID != ruID {
+			return "", ErrReservationTarget
+		}
+		return ruID, nil
+
+	case models.ReserveForDefect:
+		defect, err := s.defectRepo.GetByID(utils.NormalizeID(models.IDPrefixDefect, targetID))
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return "", ErrReservationTarget
+			}
+			return "", fmt.Errorf("failed to get defect: %w", err)
+		}
+		if defect.RuID != ruID {
+			return "", ErrReservationTarget
+		}
+		return defect.ID, nil
+	}
+	return "", ErrReservationTarget
+}
+
+func newStockMovement(level *models.StockLevel, delta int, reason models.StockMovementReason, refID *string, actor models.Actor) *models.StockMovement {
+	return &models.StockMovement{
+		ID:          utils.NewID(models.IDPrefixStockMovement),
+		WarehouseID: level.WarehouseID,
+		ItemID:      level.ItemID,
+		Delta:       delta,
+		Reason:      reason,
+		RefID:       refID,
+		By:          actor.Email,
+		At:          time.Now(),
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+)
+
+// Имена фоновых задач планировщика
+const (
+	JobMaintenanceDue        = "maintenance-due"
+	JobDataRetention         = "data-retention"
+	JobOutboxRetention       = "outbox-retention"
+	JobDeviceHealth          = "device-health"
+	JobAlarmEscalation       = "alarm-escalation"
+	JobAlarmShelving         = "alarm-shelving"
+	JobWeatherPoll           = "weather-poll"
+	JobForecastAccuracy      = "forecast-accuracy"
+	JobAnomalyDetection      = "anomaly-detection"
+	JobCapacityUtilization   = "capacity-utilization"
+	JobConsumerNotifications = "consumer-notifications"
+	JobVisionReconciliation  = "vision-reconciliation"
+	JobRuntimeHours          = "runtime-hours"
+	JobSmsNotifications      = "sms-notifications"
+)
+
+// defaultJobSchedules - расписания по умолчанию (время сервера)
+var defaultJobSchedules = map[string]string{
+	JobMaintenanceDue:        "0 7 * * *",
+	JobDataRetention:         "15 * * * *",
+	JobOutboxRetention:       "30 3 * * *",
+	JobDeviceHealth:          "* * * * *",
+	JobAlarmEscalation:       "* * * * *",
+	JobAlarmShelving:         "* * * * *",
+	JobWeatherPoll:           "*/30 * * * *",
+	JobForecastAccuracy:      "20 * * * *",
+	JobAnomalyDetection:      "* * * * *",
+	JobCapacityUtilization:   "*/5 * * * *",
+	JobConsumerNotifications: "* * * * *",
+	JobVisionReconciliation:  "*/5 * * * *",
+	JobRuntimeHours:          "*/15 * * * *",
+	JobSmsNotifications:      "* * * * *",
+}
+
+// JobSchedule - расписание задачи с учетом переопределения из окружения; "off" отключает задачу
+func JobSchedule(overrides map[string]string, name string) string {
+	spec, ok := overrides[name]
+	if !ok {
+		return defaultJobSchedules[name]
+	}
+	if spec == "off" {
+		return ""
+	}
+	return spec
+}
+
+// MaintenanceDueJob - напоминание о приближающемся и просроченном ТО РУ
+func MaintenanceDueJob(tasks *TaskService, notifications *NotificationService) JobFunc {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		due, err := tasks.MaintenanceReminders(now)
+		if err != nil {
+			return fmt.Errorf("failed to scan maintenance: %w", err)
+		}
+
+		for _, task := range due {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			key := "job.maintenance_due.message"
+			if task.Overdue {
+				key = "job.maintenance_overdue.message"
+			}
+			err := notifications.Dispatch(models.NotificationEvent{
+				RuID:     task.RuID,
+				Category: models.EventCategoryMaintenance,
+				Title:    task.Title,
+				Message:  i18n.T(i18n.Default, key, task.Deadline.Format("02.01.2006")),
+				// Одно напоминание по РУ в день, даже если задачу запустили вручную повторно
+				SourceID: fmt.Sprintf("%s:%s:%s", JobMaintenanceDue, task.RuID, now.Format("2006-01-02")),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// DataRetentionJob - удаление телеметрии старше срока хранения
+func DataRetentionJob(measurements *MeasurementService) JobFunc {
+	return func(ctx context.Context) error {
+		return measurements.Expire(time.Now())
+	}
+}
+
+// OutboxRetentionJob - очистка доставленных событий outbox через задачу обслуживания БД,
+// чтобы ход выполнения был виден в /admin/maintenance/jobs
+func OutboxRetentionJob(maintenance *MaintenanceService) JobFunc {
+	return func(ctx context.Context) error {
+		_, err := maintenance.StartJob(&models.StartMaintenanceJobRequest{Type: models.MaintenancePruneOutbox}, "scheduler")
+		return err
+	}
+}
+
+// DeviceHealthJob - проверка связи с RTU/IED и аварии по замолчавшим устройствам
+func DeviceHealthJob(devices *DeviceService) JobFunc {
+	return func(ctx context.Context) error {
+		return devices.CheckAll(ctx)
+	}
+}
+
+// AlarmEscalationJob - эскалация критичных аварий, которые остаются неквитированными
+func AlarmEscalationJob(alarms *AlarmService) JobFunc {
+	return func(ctx context.Context) error {
+		return alarms.EscalateAlarms(ctx, time.Now())
+	}
+}
+
+// AlarmShelvingJob - возврат в рабочий список аварий с истекшим откладыванием
+// и аварий по ячейкам с закрытым нарядом-допуском
+func AlarmShelvingJob(alarms *AlarmService) JobFunc {
+	return func(ctx context.Context) error {
+		return alarms.ReleaseAlarms(ctx, time.Now())
+	}
+}
+
+// WeatherPollJob - текущая температура у подстанций от провайдера погоды
+func WeatherPollJob(weather *WeatherService) JobFunc {
+	return func(ctx context.Context) error {
+		return weather.Poll(ctx)
+	}
+}
+
+// ForecastAccuracyJob - оценка точности прогнозов нагрузки, горизонт которых прошел
+func ForecastAccuracyJob(forecasts *ForecastService) JobFunc {
+	return func(ctx context.Context) error {
+		return forecasts.EvaluateAccuracy(ctx, time.Now())
+	}
+}
+
+// AnomalyDetectionJob - обновление базовых линий телеметрии и предупреждения о необычных показаниях
+func AnomalyDetectionJob(anomalies *AnomalyService) JobFunc {
+	return func(ctx context.Context) error {
+		return anomalies.Detect(ctx, time.Now())
+	}
+}
+
+// CapacityUtilizationJob - пересчет загрузки секций шин и аварии при перегрузке
+func CapacityUtilizationJob(capacity *CapacityService) JobFunc {
+	return func(ctx context.Context) error {
+		return capacity.Compute(ctx, time.Now())
+	}
+}
+
+// ConsumerNotificationJob - отправка писем потребителям о плановых отключениях
+func ConsumerNotificationJob(outages *OutageService) JobFunc {
+	return func(ctx context.Context) error {
+		return outages.DeliverNotifications(ctx)
+	}
+}
+
+// SmsNotificationJob - SMS о критичных авариях и истекающих нарядах, статусы доставки
+func SmsNotificationJob(sms *SmsService) JobFunc {
+	return func(ctx context.Context) error {
+		return sms.Run(ctx, time.Now())
+	}
+}
+
+// VisionReconciliationJob - сверка положений ячеек по индикаторам на снимках с журналом
+func VisionReconciliationJob(vision *VisionService) JobFunc {
+	return func(ctx context.Context) error {
+		return vision.Reconcile(ctx, time.Now())
+	}
+}
+
+// RuntimeHoursJob - накопление наработки включенных ячеек и РУ под напряжением
+func RuntimeHoursJob(runtime *RuntimeService) JobFunc {
+	return func(ctx context.Context) error {
+		return runtime.Accrue(ctx, time.Now())
+	}
+}
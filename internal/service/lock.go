@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// lockRecord - запись журнала об установке или снятии замка
+func lockRecord(cell *models.Cell, action string, actor models.Actor, reason, comment *string, at time.Time) *models.OperationRecord {
+	return &models.OperationRecord{
+		ID:          utils.NewID(models.IDPrefixOperation),
+		CellNumber:  cell.Number,
+		CellName:    cell.Name,
+		Action:      action,
+		Operator:    actor.DisplayName(),
+		UserID:      &actor.UserID,
+		Timestamp:   at.Format(utils.LegacyDateTimeLayout),
+		TimestampAt: &at,
+		Reason:      reason,
+		Comment:     comment,
+		RuID:        cell.RuID,
+		CreatedAt:   at,
+		UpdatedAt:   at,
+	}
+}
+
+// GetCellLock - активный замок ячейки (nil, если не заблокирована) и история замков
+func (s *RuService) GetCellLock(ruID string, cellID int) (*models.CellLock, []models.CellLock, error) {
+	if _, err := s.getCell(ruID, cellID); err != nil {
+		return nil, nil, err
+	}
+
+	active, err := s.lockRepo.GetActiveLock(cellID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cell lock: %w", err)
+	}
+	history, err := s.lockRepo.GetLocks(cellID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cell locks: %w", err)
+	}
+	return active, history, nil
+}
+
+// PlaceCellLock - устанавливает замок и плакат на ячейку
+func (s *RuService) PlaceCellLock(ruID string, cellID int, req *models.PlaceCellLockRequest, actor models.Actor) (*models.CellLock, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.lockRepo.GetActiveLock(cellID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell lock: %w", err)
+	}
+	if active != nil {
+		return nil, ErrCellLocked
+	}
+	permit, err := s.numbering.Check(models.DocumentTypePermit, ruID, req.PermitReference)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lock := &models.CellLock{
+		ID:              utils.NewID(models.IDPrefixCellLock),
+		CellID:          cellID,
+		RuID:            ruID,
+		Reason:          req.Reason,
+		PermitReference: permit,
+		PlacedBy:        actor.Email,
+		PlacedAt:        now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	record := lockRecord(cell, i18n.T(i18n.Default, "loto.placed_action"), actor, &req.Reason, nil, now)
+	record.WorkOrderNumber = permit
+
+	if err := s.lockRepo.CreateLock(lock, record); err != nil {
+		return nil, fmt.Errorf("failed to place cell lock: %w", err)
+	}
+	// Замок уже установлен; невыполненную отмену страхует проверка замка в Operate
+	if err := s.cancelSelectedCommands(cellID, actor); err != nil {
+		log.Printf("⚠️ Failed to cancel control commands of locked cell %d: %v", cellID, err)
+	}
+	return lock, nil
+}
+
+// cancelSelectedCommands - отменяет выбор телеуправления заблокированной ячейки, чтобы
+// выбранная до замка команда не была исполнена
+func (s *RuService) cancelSelectedCommands(cellID int, actor models.Actor) error {
+	commands, err := s.commandRepo.GetSelected(cellID)
+	if err != nil {
+		return err
+	}
+	reason := "cell locked out"
+	for _, current := range commands {
+		_, err := s.commandRepo.Transition(current.ID, func(command *models.ControlCommand) (*models.ControlCommandStep, []models.OutboxEvent, error) {
+			if command.State != models.CommandSelecting && command.State != models.CommandSelected {
+				return nil, nil, nil
+			}
+			return cancelCommand(command, time.Now(), actor.Email, &reason)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveCellLock - снимает активный замок. Право снятия проверяется на уровне маршрута.
+func (s *RuService) RemoveCellLock(ruID string, cellID int, req *models.RemoveCellLockRequest, actor models.Actor) (*models.CellLock, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := s.lockRepo.GetActiveLock(cellID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell lock: %w", err)
+	}
+	if lock == nil {
+		return nil, ErrCellNotLocked
+	}
+
+	now := time.Now()
+	lock.RemovedBy = &actor.Email
+	lock.RemovedAt = &now
+	lock.RemovalComment = req.Comment
+	lock.UpdatedAt = now
+
+	record := lockRecord(cell, i18n.T(i18n.Default, "loto.removed_action"), actor, &lock.Reason, req.Comment, now)
+	if err := s.lockRepo.SaveRemoval(lock, record); err != nil {
+		return nil, fmt.Errorf("failed to remove cell lock: %w", err)
+	}
+	return lock, nil
+}
+
+// GetCell - ячейка РУ по идентификатору
+func (s *RuService) GetCell(ruID string, cellID int) (*models.Cell, error) {
+	return s.getCell(ruID, cellID)
+}
+
+func (s *RuService) getCell(ruID string, cellID int) (*models.Cell, error) {
+	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	return cell, nil
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	defaultOutboxRetentionDays = 30
+	outboxPruneBatch           = 1000
+	maintenanceJobsKept        = 50
+	// maintenanceStepTimeout - срок одного REINDEX или VACUUM. Общий тайм-аут запросов
+	// (DB_QUERY_TIMEOUT) рассчитан на запросы API и прервал бы обслуживание большой таблицы.
+	maintenanceStepTimeout = 6 * time.Hour
+)
+
+// MaintenanceService - задачи обслуживания БД, запускаемые администратором.
+// Одновременно выполняется не больше одной задачи; история хранится в памяти процесса.
+type MaintenanceService struct {
+	maintenanceRepo *repository.MaintenanceRepository
+	// keepUnpublished - очистка outbox не удаляет события, ждущие публикации в брокер
+	keepUnpublished bool
+
+	mu   sync.Mutex
+	jobs map[string]*models.MaintenanceJob
+}
+
+func NewMaintenanceService(maintenanceRepo *repository.MaintenanceRepository) *MaintenanceService {
+	return &MaintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		jobs:            make(map[string]*models.MaintenanceJob),
+	}
+}
+
+// KeepUnpublishedEvents - включается вместе с внешним брокером, чтобы очистка outbox
+// не удаляла события, которые брокер еще не опубликовал
+func (s *MaintenanceService) KeepUnpublishedEvents() {
+	s.keepUnpublished = true
+}
+
+// StartJob - запускает задачу в фоне и сразу возвращает ее состояние
+func (s *MaintenanceService) StartJob(req *models.StartMaintenanceJobRequest, requestedBy string) (*models.MaintenanceJob, error) {
+	tables, err := maintenanceTables(req.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []string
+	switch req.Type {
+	case models.MaintenanceReindex:
+		targets, err = s.maintenanceRepo.GetIndexes(tables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan reindex: %w", err)
+		}
+	case models.MaintenanceVacuumAnalyze:
+		targets = tables
+	case models.MaintenancePruneOutbox:
+		targets = []string{"outbox_events"}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		if job.Status == models.MaintenanceJobRunning {
+			return nil, ErrMaintenanceJobRunning
+		}
+	}
+
+	job := &models.MaintenanceJob{
+		ID:          utils.NewID(models.IDPrefixMaintenanceJob),
+		Type:        req.Type,
+		Status:      models.MaintenanceJobRunning,
+		RequestedBy: requestedBy,
+		StepsTotal:  len(targets),
+		Steps:       make([]models.MaintenanceStep, len(targets)),
+		StartedAt:   time.Now(),
+	}
+	for i, target := range targets {
+		job.Steps[i] = models.MaintenanceStep{Target: target, Status: "pending"}
+	}
+	s.jobs[job.ID] = job
+	s.trimLocked()
+
+	retention := req.RetentionDays
+	if retention == 0 {
+		retention = defaultOutboxRetentionDays
+	}
+	go s.run(job.ID, req.Type, retention)
+
+	snapshot := copyJob(job)
+	return &snapshot, nil
+}
+
+// GetJobs - задачи, последние первыми
+func (s *MaintenanceService) GetJobs() []models.MaintenanceJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.MaintenanceJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, copyJob(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+	return jobs
+}
+
+// GetJob - состояние задачи; для выполняющейся задачи добавляется ход текущей операции из PostgreSQL
+func (s *MaintenanceService) GetJob(id string) (*models.MaintenanceJob, error) {
+	s.mu.Lock()
+	job, ok := s.jobs[utils.NormalizeID(models.IDPrefixMaintenanceJob, id)]
+	var snapshot models.MaintenanceJob
+	if ok {
+		snapshot = copyJob(job)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, ErrMaintenanceJobNotFound
+	}
+
+	if snapshot.Status == models.MaintenanceJobRunning {
+		progress, err := s.maintenanceRepo.GetProgress(snapshot.Type)
+		if err != nil {
+			log.Printf("⚠️ Failed to read maintenance progress: %v", err)
+		}
+		snapshot.Progress = progress
+	}
+
+	return &snapshot, nil
+}
+
+func (s *MaintenanceService) run(jobID string, jobType models.MaintenanceJobType, retentionDays int) {
+	s.mu.Lock()
+	targets := make([]string, len(s.jobs[jobID].Steps))
+	for i, step := range s.jobs[jobID].Steps {
+		targets[i] = step.Target
+	}
+	s.mu.Unlock()
+
+	failed := 0
+	for i, target := range targets {
+		s.updateStep(jobID, i, func(step *models.MaintenanceStep) {
+			now := time.Now()
+			step.Status = "running"
+			step.StartedAt = &now
+		})
+
+		err := s.runStep(jobID, i, jobType, target, retentionDays)
+
+		if err != nil {
+			failed++
+			log.Printf("⚠️ Maintenance job %s: %s failed: %v", jobID, target, err)
+		}
+		s.updateStep(jobID, i, func(step *models.MaintenanceStep) {
+			now := time.Now()
+			step.FinishedAt = &now
+			step.Status = string(models.MaintenanceJobCompleted)
+			if err != nil {
+				msg := err.Error()
+				step.Status = string(models.MaintenanceJobFailed)
+				step.Error = &msg
+			}
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[jobID]
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = models.MaintenanceJobCompleted
+	if failed > 0 {
+		msg := fmt.Sprintf("%d of %d steps failed", failed, job.StepsTotal)
+		job.Status = models.MaintenanceJobFailed
+		job.Error = &msg
+	}
+	log.Printf("🧹 Maintenance job %s (%s) finished: %s", jobID, jobType, job.Status)
+}
+
+// runStep - выполняет шаг задачи; REINDEX и VACUUM - со сроком maintenanceStepTimeout
+func (s *MaintenanceService) runStep(jobID string, index int, jobType models.MaintenanceJobType, target string, retentionDays int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), maintenanceStepTimeout)
+	defer cancel()
+
+	switch jobType {
+	case models.MaintenanceReindex:
+		return s.maintenanceRepo.ReindexConcurrently(ctx, target)
+	case models.MaintenanceVacuumAnalyze:
+		return s.maintenanceRepo.VacuumAnalyze(ctx, target)
+	case models.MaintenancePruneOutbox:
+		return s.pruneOutbox(jobID, index, retentionDays)
+	}
+	return nil
+}
+
+// pruneOutbox - удаляет обработанные события пачками, чтобы не держать длинных блокировок
+func (s *MaintenanceService) pruneOutbox(jobID string, stepIndex, retentionDays int) error {
+	before := time.Now().AddDate(0, 0, -retentionDays)
+	var total int64
+	for {
+		deleted, err := s.maintenanceRepo.PruneOutboxBatch(before, outboxPruneBatch, s.keepUnpublished)
+		if err != nil {
+			return err
+		}
+		total += deleted
+		s.updateStep(jobID, stepIndex, func(step *models.MaintenanceStep) {
+			step.RowsDeleted = total
+		})
+		if deleted < outboxPruneBatch {
+			return nil
+		}
+	}
+}
+
+func (s *MaintenanceService) updateStep(jobID string, index int, update func(step *models.MaintenanceStep)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[jobID]
+	update(&job.Steps[index])
+
+	done := 0
+	for _, step := range job.Steps {
+		if step.FinishedAt != nil {
+			done++
+		}
+	}
+	job.StepsDone = done
+}
+
+// trimLocked - ограничивает историю задач в памяти; выполняющиеся задачи не удаляются
+func (s *MaintenanceService) trimLocked() {
+	if len(s.jobs) <= maintenanceJobsKept {
+		return
+	}
+	var oldest *models.MaintenanceJob
+	for _, job := range s.jobs {
+		if job.Status == models.MaintenanceJobRunning {
+			continue
+		}
+		if oldest == nil || job.StartedAt.Before(oldest.StartedAt) {
+			oldest = job
+		}
+	}
+	if oldest != nil {
+		delete(s.jobs, oldest.ID)
+	}
+}
+
+func copyJob(job *models.MaintenanceJob) models.MaintenanceJob {
+	snapshot := *job
+	snapshot.Steps = append([]models.MaintenanceStep(nil), job.Steps...)
+	return snapshot
+}
+
+// maintenanceTables - проверяет запрошенные таблицы по списку разрешенных
+func maintenanceTables(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return repository.MaintenanceTables, nil
+	}
+	allowed := make(map[string]bool, len(repository.MaintenanceTables))
+	for _, table := range repository.MaintenanceTables {
+		allowed[table] = true
+	}
+	for _, table := range requested {
+		if !allowed[table] {
+			return nil, ErrMaintenanceTableInvalid.WithDetails(map[string]interface{}{
+				"table":   table,
+				"allowed": repository.MaintenanceTables,
+			})
+		}
+	}
+	return requested, nil
+}
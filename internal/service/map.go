@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// MapService - карта сети промышленной зоны: подстанции и РУ с координатами,
+// окрашенные по текущему состоянию
+type MapService struct {
+	ruService *RuService
+	ruRepo    *repository.RuRepository
+}
+
+func NewMapService(ruService *RuService, ruRepo *repository.RuRepository) *MapService {
+	return &MapService{ruService: ruService, ruRepo: ruRepo}
+}
+
+// GeoJSON - подстанции и РУ, доступные пользователю. Подстанция окрашивается по худшему
+// состоянию своих РУ; объекты без координат перечисляются в unlocated.
+func (s *MapService) GeoJSON(actor models.Actor) (*models.GeoFeatureCollection, error) {
+	rus, err := s.ruService.GetVisibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	organizationID := ""
+	if !actor.IsPlatformAdmin() {
+		organizationID = actor.OrganizationID
+	}
+	substations, err := s.ruRepo.GetSubstations(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.ruRepo.GetRuStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RU stats: %w", err)
+	}
+
+	collection := &models.GeoFeatureCollection{
+		Type:      "FeatureCollection",
+		Features:  []models.GeoFeature{},
+		Unlocated: []string{},
+	}
+
+	substationProps := make(map[string]*models.MapProperties, len(substations))
+	for _, substation := range substations {
+		substationProps[substation.ID] = &models.MapProperties{
+			Kind:          models.MapKindSubstation,
+			Name:          substation.Name,
+			Status:        models.MapStatusNormal,
+			CellsByStatus: map[models.CellStatus]int{},
+		}
+	}
+
+	for _, ru := range rus {
+		ruStats := stats[ru.ID]
+		if ruStats == nil {
+			ruStats = &models.RUStats{CellsByStatus: map[models.CellStatus]int{}}
+		}
+		status := ruMapStatus(ru.Status, ruStats)
+
+		if props := substationProps[ru.SubstationID]; props != nil {
+			props.Status = props.Status.Worse(status)
+			props.RUsCount++
+			props.ActiveAlarms += ruStats.ActiveAlarms
+			props.CriticalAlarms += ruStats.CriticalAlarms
+			for cellStatus, count := range ruStats.CellsByStatus {
+				props.CellsByStatus[cellStatus] += count
+			}
+		}
+
+		if ru.Latitude == nil || ru.Longitude == nil {
+			collection.Unlocated = append(collection.Unlocated, ru.ID)
+			continue
+		}
+		collection.Features = append(collection.Features, models.GeoFeature{
+			Type:     "Feature",
+			ID:       ru.ID,
+			Geometry: models.NewGeoPoint(*ru.Latitude, *ru.Longitude),
+			Properties: models.MapProperties{
+				Kind:           models.MapKindRU,
+				Name:           ru.Name,
+				SubstationID:   ru.SubstationID,
+				Status:         status,
+				Color:          models.MapStatusColors[status],
+				RuStatus:       ru.Status,
+				CellsByStatus:  ruStats.CellsByStatus,
+				ActiveAlarms:   ruStats.ActiveAlarms,
+				CriticalAlarms: ruStats.CriticalAlarms,
+			},
+		})
+	}
+
+	for _, substation := range substations {
+		if substation.Latitude == nil || substation.Longitude == nil {
+			collection.Unlocated = append(collection.Unlocated, substation.ID)
+			continue
+		}
+		props := substationProps[substation.ID]
+		props.Color = models.MapStatusColors[props.Status]
+		collection.Features = append(collection.Features, models.GeoFeature{
+			Type:       "Feature",
+			ID:         substation.ID,
+			Geometry:   models.NewGeoPoint(*substation.Latitude, *substation.Longitude),
+			Properties: *props,
+		})
+	}
+
+	return collection, nil
+}
+
+// ruMapStatus - состояние РУ на карте по его статусу, авариям и неисправным ячейкам
+func ruMapStatus(ruStatus models.RuStatus, stats *models.RUStats) models.MapStatus {
+	switch {
+	case ruStatus == models.RuStatusEmergency, stats.CriticalAlarms > 0, stats.CellsByStatus[models.CellStatusError] > 0:
+		return models.MapStatusAlarm
+	case ruStatus == models.RuStatusOutOfService:
+		return models.MapStatusOff
+	case ruStatus != models.RuStatusNormal, stats.ActiveAlarms > 0:
+		return models.MapStatusWarning
+	}
+	return models.MapStatusNormal
+}
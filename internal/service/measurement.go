@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	compactionInterval = time.Minute
+	// compactionLookback - окно пересчета: поздно пришедшие измерения попадают в агрегаты
+	compactionLookback = 2 * time.Hour
+
+	rawRetention  = 7 * 24 * time.Hour
+	m1Retention   = 30 * 24 * time.Hour
+	m15Retention  = 365 * 24 * time.Hour
+	maxSeriesSpan = 5 * 365 * 24 * time.Hour
+)
+
+// rollupLevel - уровень агрегации и источник, из которого он строится
+type rollupLevel struct {
+	resolution models.RollupResolution
+	step       time.Duration
+	source     models.RollupResolution
+}
+
+var rollupLevels = []rollupLevel{
+	{resolution: models.Resolution1m, step: time.Minute, source: models.ResolutionRaw},
+	{resolution: models.Resolution15m, step: 15 * time.Minute, source: models.Resolution1m},
+	{resolution: models.Resolution1h, step: time.Hour, source: models.Resolution15m},
+}
+
+// MeasurementService - прием телеметрии, прореживание в агрегаты 1m/15m/1h
+// и удаление устаревших сырых данных (задача планировщика data-retention)
+type MeasurementService struct {
+	measurementRepo *repository.MeasurementRepository
+	ruRepo          *repository.RuRepository
+}
+
+func NewMeasurementService(measurementRepo *repository.MeasurementRepository, ruRepo *repository.RuRepository) *MeasurementService {
+	return &MeasurementService{measurementRepo: measurementRepo, ruRepo: ruRepo}
+}
+
+// Record - пакет измерений; ячейки должны принадлежать указанным РУ организации
+// пользователя, иначе пакет отклоняется целиком
+func (s *MeasurementService) Record(actor models.Actor, req *models.RecordMeasurementsRequest) (int, error) {
+	refs := make([]cellRef, len(req.Measurements))
+	for i, m := range req.Measurements {
+		refs[i] = cellRef{ruID: m.RuID, cellID: m.CellID}
+	}
+	if err := checkCellRefs(s.ruRepo, actor, refs); err != nil {
+		return 0, err
+	}
+
+	measurements := make([]models.Measurement, len(req.Measurements))
+	for i, m := range req.Measurements {
+		measurements[i] = models.Measurement{
+			RuID:       m.RuID,
+			CellID:     m.CellID,
+			Metric:     m.Metric,
+			Value:      m.Value,
+			MeasuredAt: m.MeasuredAt,
+		}
+	}
+	if err := s.measurementRepo.CreateMeasurements(measurements); err != nil {
+		return 0, fmt.Errorf("failed to record measurements: %w", err)
+	}
+	return len(measurements), nil
+}
+
+// GetSeries - ряд измерений за период. При resolution=auto разрешение выбирается
+// по длине периода, чтобы длинные графики строились по агрегатам.
+func (s *MeasurementService) GetSeries(cellID int, metric models.MeasurementMetric, from, to time.Time, resolution string) (models.RollupResolution, interface{}, error) {
+	if !to.After(from) || to.Sub(from) > maxSeriesSpan {
+		return "", nil, ErrMeasurementRangeInvalid
+	}
+	switch metric {
+	case models.MetricCurrent, models.MetricTemperature, models.MetricLoad:
+	default:
+		return "", nil, ErrMeasurementMetricInvalid
+	}
+
+	res := models.RollupResolution(resolution)
+	if resolution == "" || resolution == "auto" {
+		res = autoResolution(to.Sub(from))
+	}
+
+	switch res {
+	case models.ResolutionRaw:
+		measurements, err := s.measurementRepo.GetRaw(cellID, metric, from, to)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get measurements: %w", err)
+		}
+		return res, measurements, nil
+	case models.Resolution1m, models.Resolution15m, models.Resolution1h:
+		rollups, err := s.measurementRepo.GetRollups(res, cellID, metric, from, to)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get measurements: %w", err)
+		}
+		return res, rollups, nil
+	default:
+		return "", nil, ErrMeasurementResolutionInvalid
+	}
+}
+
+func autoResolution(span time.Duration) models.RollupResolution {
+	switch {
+	case span <= 6*time.Hour:
+		return models.ResolutionRaw
+	case span <= 3*24*time.Hour:
+		return models.Resolution1m
+	case span <= 30*24*time.Hour:
+		return models.Resolution15m
+	default:
+		return models.Resolution1h
+	}
+}
+
+// Run - периодическое прореживание и очистка; завершается при отмене контекста
+func (s *MeasurementService) Run(ctx context.Context) {
+	ticker := time.NewTicker(compactionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.Compact(now); err != nil {
+				log.Printf("⚠️ Measurement compaction failed: %v", err)
+			}
+		}
+	}
+}
+
+// Compact - пересчитывает закрытые бакеты всех уровней за окно compactionLookback
+func (s *MeasurementService) Compact(now time.Time) error {
+	for _, level := range rollupLevels {
+		to := now.Truncate(level.step)
+		from := to.Add(-compactionLookback).Truncate(level.step)
+		if level.step > compactionLookback {
+			from = to.Add(-2 * level.step)
+		}
+
+		var err error
+		if level.source == models.ResolutionRaw {
+			_, err = s.measurementRepo.RollupRaw(level.resolution, level.step, from, to)
+		} else {
+			_, err = s.measurementRepo.RollupFrom(level.source, level.resolution, level.step, from, to)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expire - удаляет сырые данные и мелкие агрегаты старше срока хранения.
+// Часовые агрегаты хранятся бессрочно.
+func (s *MeasurementService) Expire(now time.Time) error {
+	raw, err := s.measurementRepo.DeleteRawBefore(now.Add(-rawRetention))
+	if err != nil {
+		return err
+	}
+	m1, err := s.measurementRepo.DeleteRollupsBefore(models.Resolution1m, now.Add(-m1Retention))
+	if err != nil {
+		return err
+	}
+	m15, err := s.measurementRepo.DeleteRollupsBefore(models.Resolution15m, now.Add(-m15Retention))
+	if err != nil {
+		return err
+	}
+	if raw+m1+m15 > 0 {
+		log.Printf("🧹 Expired measurements: raw=%d, 1m=%d, 15m=%d", raw, m1, m15)
+	}
+	return nil
+}
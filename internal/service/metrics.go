@@ -0,0 +1,79 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// MetricsService - предметные метрики для оповещений дежурных из Prometheus: ячейки
+// в ERROR, неквитированные аварии, устройства без связи и возраст самого старого
+// ожидающего одобрения. Значения считаются при каждом опросе.
+type MetricsService struct {
+	metricsRepo *repository.MetricsRepository
+}
+
+func NewMetricsService(metricsRepo *repository.MetricsRepository) *MetricsService {
+	return &MetricsService{metricsRepo: metricsRepo}
+}
+
+// approvalKinds - виды одобрений; для вида без ожидающих запросов возраст 0
+var approvalKinds = []string{models.ApprovalStatusConfirmation, models.ApprovalCellChange, models.ApprovalPlannedOutage}
+
+// Collect - текущие значения метрик
+func (s *MetricsService) Collect() ([]models.MetricFamily, error) {
+	errorCells, err := s.metricsRepo.CountErrorCells()
+	if err != nil {
+		return nil, err
+	}
+	alarms, err := s.metricsRepo.CountUnacknowledgedAlarms()
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.metricsRepo.CountOfflineDevices()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	oldest, err := s.metricsRepo.GetOldestPendingApprovals(now)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals := models.MetricFamily{
+		Name: "sez_approval_oldest_pending_age_seconds",
+		Help: "Age of the oldest pending approval request by kind (0 if none is pending).",
+		Type: models.MetricGauge,
+	}
+	for _, kind := range approvalKinds {
+		age := 0.0
+		if requestedAt, ok := oldest[kind]; ok && now.After(requestedAt) {
+			age = now.Sub(requestedAt).Seconds()
+		}
+		approvals.Samples = append(approvals.Samples, models.MetricSample{
+			Labels: []models.MetricLabel{{Name: "kind", Value: kind}},
+			Value:  age,
+		})
+	}
+
+	return []models.MetricFamily{
+		ruGauge("sez_cells_error", "Cells in ERROR status by RU.", "", errorCells),
+		ruGauge("sez_alarms_unacknowledged", "Unacknowledged alarms by RU and severity.", "severity", alarms),
+		ruGauge("sez_devices_offline", "Polled RTU/IED devices without communication by RU.", "", devices),
+		approvals,
+	}, nil
+}
+
+// ruGauge - метрика по РУ; label - имя дополнительной метки из RuCount.Label
+func ruGauge(name, help, label string, counts []repository.RuCount) models.MetricFamily {
+	family := models.MetricFamily{Name: name, Help: help, Type: models.MetricGauge}
+	for _, count := range counts {
+		labels := []models.MetricLabel{{Name: "ru_id", Value: count.RuID}}
+		if label != "" {
+			labels = append(labels, models.MetricLabel{Name: label, Value: count.Label})
+		}
+		family.Samples = append(family.Samples, models.MetricSample{Labels: labels, Value: float64(count.Count)})
+	}
+	return family
+}
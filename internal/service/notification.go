@@ -0,0 +1,406 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	notificationDefaultLimit = 50
+	notificationMaxLimit     = 200
+)
+
+// mentionPattern - упоминание коллеги в комментарии: @ и email (@ivanov@sez.kz)
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// mentionedEmails - адреса упомянутых пользователей без повторов
+func mentionedEmails(text string) []string {
+	seen := make(map[string]bool)
+	var emails []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		email := strings.ToLower(match[1])
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	userRepo         *repository.UserRepository
+	ruRepo           *repository.RuRepository
+	subscriptions    *SubscriptionService
+	push             *PushService
+}
+
+func NewNotificationService(notificationRepo *repository.NotificationRepository, userRepo *repository.UserRepository, ruRepo *repository.RuRepository, subscriptions *SubscriptionService, push *PushService) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		ruRepo:           ruRepo,
+		subscriptions:    subscriptions,
+		push:             push,
+	}
+}
+
+func (s *NotificationService) GetRules(ruID string) ([]models.NotificationRule, error) {
+	rules, err := s.notificationRepo.GetRulesByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *NotificationService) CreateRule(ruID string, req *models.CreateNotificationRuleRequest) (*models.NotificationRule, error) {
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	// Правило должно указывать ровно одного получателя: роль или пользователя
+	if (req.Role == nil) == (req.UserID == nil) {
+		return nil, ErrRuleRecipientInvalid
+	}
+
+	if req.UserID != nil {
+		user, err := s.userRepo.FindByID(*req.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	now := time.Now()
+	rule := &models.NotificationRule{
+		ID:        utils.NewID(models.IDPrefixNotificationRule),
+		RuID:      ruID,
+		Category:  req.Category,
+		Role:      req.Role,
+		UserID:    req.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.notificationRepo.CreateRule(rule); err != nil {
+		return nil, fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+func (s *NotificationService) DeleteRule(ruID, ruleID string) error {
+	deleted, err := s.notificationRepo.DeleteRule(ruID, utils.NormalizeID(models.IDPrefixNotificationRule, ruleID))
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	if !deleted {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+// ResolveRecipients - определяет получателей события по правилам РУ.
+// Если для РУ и категории правил нет, используется общий список (все пользователи;
+// для запросов одобрения - инженеры и администраторы).
+func (s *NotificationService) ResolveRecipients(ruID string, category models.EventCategory) ([]*models.User, error) {
+	rules, err := s.notificationRepo.GetRulesForEvent(ruID, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var recipients []*models.User
+	add := func(users ...*models.User) {
+		for _, user := range users {
+			if user != nil && !seen[user.ID] {
+				seen[user.ID] = true
+				recipients = append(recipients, user)
+			}
+		}
+	}
+
+	if len(rules) == 0 {
+		// Одобрить запрос может только инженер или администратор
+		if category == models.EventCategoryApproval {
+			for _, role := range []models.UserRole{models.RoleEngineer, models.RoleOrgAdmin, models.RoleAdmin} {
+				users, err := s.userRepo.GetUsersByRole(string(role))
+				if err != nil {
+					return nil, fmt.Errorf("failed to get default recipients: %w", err)
+				}
+				add(users...)
+			}
+			return recipients, nil
+		}
+
+		users, err := s.userRepo.GetActive()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default recipients: %w", err)
+		}
+		return users, nil
+	}
+
+	for _, rule := range rules {
+		if rule.Role != nil {
+			users, err := s.userRepo.GetUsersByRole(*rule.Role)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get users by role: %w", err)
+			}
+			add(users...)
+		}
+		if rule.UserID != nil {
+			user, err := s.userRepo.FindByID(*rule.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find user: %w", err)
+			}
+			add(user)
+		}
+	}
+
+	return recipients, nil
+}
+
+// Dispatch - рассылает событие получателям, определенным правилами РУ (или адресным
+// получателям), сохраняет уведомления в центре уведомлений и дублирует их Web Push.
+// Пользователи другой организации уведомления по РУ не получают; пользователи
+// с подписками получают только уведомления по отслеживаемым РУ и ячейкам.
+func (s *NotificationService) Dispatch(event models.NotificationEvent) error {
+	recipients, err := s.Recipients(event)
+	if err != nil {
+		return err
+	}
+
+	if event.SourceID == "" {
+		event.SourceID = utils.NewID(models.IDPrefixNotification)
+	}
+
+	now := time.Now()
+	notifications := make([]models.Notification, 0, len(recipients))
+	for _, user := range recipients {
+		log.Printf("📣 [%s] %s → %s: %s", event.Category, event.RuID, user.Email, event.Title)
+		notifications = append(notifications, models.Notification{
+			ID:        utils.NewID(models.IDPrefixNotification),
+			UserID:    user.ID,
+			SourceID:  event.SourceID,
+			Category:  event.Category,
+			RuID:      event.RuID,
+			Title:     event.Title,
+			Message:   event.Message,
+			CreatedAt: now,
+		})
+	}
+
+	created, err := s.notificationRepo.CreateNotifications(notifications)
+	if err != nil {
+		return err
+	}
+	// В браузер уходят только новые уведомления: повторная доставка события не дублирует push
+	s.push.Notify(created)
+	return nil
+}
+
+// Recipients - получатели события: правила РУ (или адресные получатели), подписки
+// и организация РУ. Используется и для рассылки по другим каналам (SMS).
+func (s *NotificationService) Recipients(event models.NotificationEvent) ([]*models.User, error) {
+	recipients, err := s.dispatchRecipients(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve recipients: %w", err)
+	}
+	if len(event.UserIDs) == 0 {
+		if recipients, err = s.subscriptions.Filter(recipients, event.RuID, event.CellID); err != nil {
+			return nil, fmt.Errorf("failed to apply subscriptions: %w", err)
+		}
+	}
+
+	if event.RuID != "" {
+		organizationID, err := s.ruRepo.GetRuOrganization(event.RuID)
+		if err != nil && !repository.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get RU organization: %w", err)
+		}
+		if err == nil {
+			recipients = inOrganization(recipients, organizationID)
+		}
+	}
+	return recipients, nil
+}
+
+func (s *NotificationService) dispatchRecipients(event models.NotificationEvent) ([]*models.User, error) {
+	if len(event.UserIDs) == 0 {
+		return s.ResolveRecipients(event.RuID, event.Category)
+	}
+
+	var recipients []*models.User
+	for _, id := range event.UserIDs {
+		user, err := s.userRepo.FindByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find user: %w", err)
+		}
+		if user != nil {
+			recipients = append(recipients, user)
+		}
+	}
+	return recipients, nil
+}
+
+// inOrganization - получатели из организации РУ и администраторы установки
+func inOrganization(users []*models.User, organizationID string) []*models.User {
+	var scoped []*models.User
+	for _, user := range users {
+		if user.Role == models.RoleAdmin || user.OrganizationID == organizationID {
+			scoped = append(scoped, user)
+		}
+	}
+	return scoped
+}
+
+// GetNotifications - уведомления пользователя и число непрочитанных
+func (s *NotificationService) GetNotifications(userID string, filter models.NotificationFilter) (*models.NotificationList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = notificationDefaultLimit
+	}
+	filter.Limit = min(filter.Limit, notificationMaxLimit)
+
+	items, err := s.notificationRepo.GetNotifications(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	unread, err := s.UnreadCount(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationList{Items: items, UnreadCount: unread}, nil
+}
+
+func (s *NotificationService) UnreadCount(userID string) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (s *NotificationService) MarkRead(userID, notificationID string) error {
+	found, err := s.notificationRepo.MarkRead(userID, utils.NormalizeID(models.IDPrefixNotification, notificationID), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead - отмечает прочитанными все уведомления пользователя, возвращает их число
+func (s *NotificationService) MarkAllRead(userID string) (int64, error) {
+	count, err := s.notificationRepo.MarkAllRead(userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return count, nil
+}
+
+// HandleEvent - подписчик шины событий: переводит доменное событие в уведомление
+func (s *NotificationService) HandleEvent(event *models.OutboxEvent) error {
+	notification := models.NotificationEvent{RuID: event.RuID, SourceID: event.ID}
+
+	switch event.Type {
+	case models.EventCellStatusChanged:
+		var payload models.CellStatusChangedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		notification.CellID = payload.CellID
+		// Переход в аварию рассылается отдельным событием alarm.raised
+		if payload.Status == models.CellStatusError {
+			return nil
+		}
+		statusName := i18n.T(i18n.Default, "status.cell."+string(payload.Status))
+		notification.Category = models.EventCategoryStatusChange
+		notification.Title = i18n.T(i18n.Default, "alarm.cell_status.title", payload.CellNumber, statusName)
+		notification.Message = i18n.T(i18n.Default, "alarm.cell_status.message", payload.CellNumber, payload.CellName, statusName)
+
+	case models.EventAlarmRaised:
+		var payload models.AlarmRaisedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		notification.CellID = payload.CellID
+		statusName := i18n.T(i18n.Default, "status.cell."+string(payload.Status))
+		notification.Category = models.EventCategoryAlarm
+		notification.Title = i18n.T(i18n.Default, "alarm.cell_status.title", payload.CellNumber, statusName)
+		notification.Message = i18n.T(i18n.Default, "alarm.cell_status.message", payload.CellNumber, payload.CellName, statusName)
+
+	case models.EventPermitIssued:
+		var payload models.PermitIssuedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		notification.Category = models.EventCategoryWorkPermit
+		notification.Title = i18n.T(i18n.Default, "alarm.work_permit.title", payload.WorkOrderNumber, payload.Action)
+		notification.Message = i18n.T(i18n.Default, "alarm.work_permit.message", payload.Action, payload.CellNumber, payload.Operator)
+
+	case models.EventRuStatusChanged:
+		var payload models.RuStatusChangedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		// События, записанные до перехода на коды, содержат текст статуса
+		statusName := i18n.T(i18n.Default, "status.ru."+string(models.ParseLegacyRuStatus(string(payload.Status))))
+		notification.Category = models.EventCategoryStatusChange
+		notification.Title = i18n.T(i18n.Default, "alarm.ru_status.title", payload.Name, statusName)
+		if payload.Reason != "" {
+			notification.Message = i18n.T(i18n.Default, "alarm.ru_status.message_reason", payload.Name, statusName, payload.Reason)
+		} else {
+			notification.Message = i18n.T(i18n.Default, "alarm.ru_status.message", payload.Name, statusName)
+		}
+
+	case models.EventApprovalRequested:
+		var payload models.ApprovalRequestedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		notification.CellID = payload.CellID
+		notification.Category = models.EventCategoryApproval
+		notification.Title = i18n.T(i18n.Default, "notification.approval."+payload.Kind+".title", payload.CellNumber)
+		notification.Message = i18n.T(i18n.Default, "notification.approval.message", payload.RequestedBy, payload.CellNumber)
+
+	case models.EventUserMentioned:
+		var payload models.UserMentionedPayload
+		if err := decodePayload(event, &payload); err != nil {
+			return err
+		}
+		for _, email := range payload.Emails {
+			user, err := s.userRepo.FindByEmail(email)
+			if err != nil {
+				return fmt.Errorf("failed to find user: %w", err)
+			}
+			if user != nil {
+				notification.UserIDs = append(notification.UserIDs, user.ID)
+			}
+		}
+		if len(notification.UserIDs) == 0 {
+			return nil
+		}
+		notification.Category = models.EventCategoryMention
+		notification.Title = i18n.T(i18n.Default, "notification.mention.title", payload.Author, payload.CellNumber)
+		notification.Message = payload.Comment
+
+	default:
+		return nil
+	}
+
+	return s.Dispatch(notification)
+}
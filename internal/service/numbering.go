@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// NumberingService - сквозная нумерация распоряжений и нарядов: номер вида 2025-0147
+// выдает сервер, по своей серии на каждую организацию, вид документа и год. Номер,
+// введенный вручную, принимается, только если он выдан сервером или уже есть в журнале
+// (ссылка на старый наряд) той же организации.
+type NumberingService struct {
+	numberingRepo *repository.NumberingRepository
+	ruRepo        *repository.RuRepository
+	documentTypes *DocumentTypeService
+}
+
+func NewNumberingService(numberingRepo *repository.NumberingRepository, ruRepo *repository.RuRepository, documentTypes *DocumentTypeService) *NumberingService {
+	return &NumberingService{numberingRepo: numberingRepo, ruRepo: ruRepo, documentTypes: documentTypes}
+}
+
+// Issue - следующий номер вида документа (код или название из справочника) в серии
+// организации РУ; номер без РУ выдается в серии организации пользователя
+func (s *NumberingService) Issue(req *models.IssueDocumentNumberRequest, actor models.Actor) (*models.IssuedDocumentNumber, error) {
+	organizationID := actorOrganization(actor, req.OrganizationID)
+	if req.RuID != "" {
+		var err error
+		if organizationID, err = ruOrganization(s.ruRepo, actor, req.RuID); err != nil {
+			return nil, err
+		}
+	}
+	documentType, err := s.documentTypes.Resolve(&req.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(*documentType, organizationID, req.RuID, actor.Email)
+}
+
+// Gaps - выданные, но не использованные в журнале номера и дыры в нумерации организации
+// за год (по умолчанию текущий)
+func (s *NumberingService) Gaps(req *models.DocumentNumberGapsRequest, actor models.Actor) (*models.DocumentNumberGaps, error) {
+	documentType, err := s.documentTypes.Resolve(&req.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+	organizationID := actorOrganization(actor, req.OrganizationID)
+	year := req.Year
+	if year == 0 {
+		year = time.Now().Year()
+	}
+
+	sequence, err := s.numberingRepo.GetSequence(organizationID, *documentType, year)
+	if err != nil {
+		return nil, err
+	}
+	issued, err := s.numberingRepo.GetIssued(organizationID, *documentType, year)
+	if err != nil {
+		return nil, err
+	}
+	numbers := make([]string, len(issued))
+	for i, number := range issued {
+		numbers[i] = number.Number
+	}
+	field, column := numberField(*documentType)
+	recorded, err := s.numberingRepo.GetRecordedNumbers(organizationID, field, column, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	gaps := &models.DocumentNumberGaps{
+		OrganizationID: organizationID,
+		DocumentType:   *documentType,
+		Year:           year,
+		Last:           sequence.Last,
+		Issued:         len(issued),
+		Unused:         []string{},
+		Missing:        []string{},
+	}
+	seen := make(map[int]bool, len(issued))
+	for _, number := range issued {
+		seen[number.Seq] = true
+		if recorded[number.Number] {
+			gaps.Used++
+		} else {
+			gaps.Unused = append(gaps.Unused, number.Number)
+		}
+	}
+	for seq := 1; seq <= sequence.Last; seq++ {
+		if !seen[seq] {
+			gaps.Missing = append(gaps.Missing, models.FormatDocumentNumber(year, seq))
+		}
+	}
+	return gaps, nil
+}
+
+// Check - номер, указанный вручную для РУ, должен быть выдан в серии организации РУ
+// или уже встречаться в ее журнале; номер другой организации неизвестен. Возвращает
+// номер без пробелов по краям; пустой номер - nil.
+func (s *NumberingService) Check(series, ruID string, number *string) (*string, error) {
+	if number == nil || strings.TrimSpace(*number) == "" {
+		return nil, nil
+	}
+	value := strings.TrimSpace(*number)
+	organizationID, err := s.ruRepo.GetRuOrganization(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	issued, err := s.numberingRepo.IsIssued(organizationID, series, value)
+	if err != nil {
+		return nil, err
+	}
+	if !issued {
+		field, column := numberField(series)
+		recorded, err := s.numberingRepo.IsRecorded(organizationID, field, column, value)
+		if err != nil {
+			return nil, err
+		}
+		if !recorded {
+			return nil, ErrDocumentNumberUnknown.WithDetails(map[string]interface{}{"documentType": series, "number": value})
+		}
+	}
+	return &value, nil
+}
+
+// NumberRecord - проверяет номера записи и выдает номер документа записи, если он не
+// указан. Запись без вида документа номер не получает.
+func (s *NumberingService) NumberRecord(record *models.OperationRecord) error {
+	var err error
+	if record.WorkOrderNumber, err = s.Check(models.DocumentTypePermit, record.RuID, record.WorkOrderNumber); err != nil {
+		return err
+	}
+	if record.OrderNumber, err = s.Check(orderSeries(record.DocumentType), record.RuID, record.OrderNumber); err != nil {
+		return err
+	}
+	if record.DocumentType == nil {
+		return nil
+	}
+
+	target := &record.OrderNumber
+	if *record.DocumentType == models.DocumentTypePermit {
+		target = &record.WorkOrderNumber
+	}
+	if *target != nil {
+		return nil
+	}
+	organizationID, err := s.ruRepo.GetRuOrganization(record.RuID)
+	if err != nil {
+		return fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	issued, err := s.issue(*record.DocumentType, organizationID, record.RuID, record.Operator)
+	if err != nil {
+		return err
+	}
+	*target = &issued.Number
+	return nil
+}
+
+func (s *NumberingService) issue(documentType, organizationID, ruID, issuedBy string) (*models.IssuedDocumentNumber, error) {
+	now := time.Now()
+	issued := &models.IssuedDocumentNumber{
+		OrganizationID: organizationID,
+		DocumentType:   documentType,
+		Year:           now.Year(),
+		RuID:           ruID,
+		IssuedBy:       issuedBy,
+		IssuedAt:       now,
+	}
+	if err := s.numberingRepo.Issue(issued); err != nil {
+		return nil, fmt.Errorf("failed to issue document number: %w", err)
+	}
+	return issued, nil
+}
+
+// orderSeries - серия номера распоряжения для вида документа записи
+func orderSeries(documentType *string) string {
+	if documentType == nil || *documentType == models.DocumentTypePermit {
+		return models.DocumentTypeOrder
+	}
+	return *documentType
+}
+
+// numberField - поле записи журнала, в котором хранится номер серии
+func numberField(series string) (field, column string) {
+	if series == models.DocumentTypePermit {
+		return "workOrderNumber", "work_order_number"
+	}
+	return "orderNumber", "order_number"
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// OperationCounterService - ресурс выключателей по числу коммутационных операций.
+// Операции считаются при смене статуса ячейки и при исполнении команды телеуправления;
+// выключатели, выработавшие breaker.wear_warning_percent ресурса, попадают во входящие
+// инженеров как задача на обслуживание.
+type OperationCounterService struct {
+	ruRepo   *repository.RuRepository
+	settings *SettingsService
+}
+
+func NewOperationCounterService(ruRepo *repository.RuRepository, settings *SettingsService) *OperationCounterService {
+	return &OperationCounterService{ruRepo: ruRepo, settings: settings}
+}
+
+// UpdateCounter - ресурс выключателя, начальное показание или сброс счетчика
+func (s *OperationCounterService) UpdateCounter(ruID string, cellID int, req *models.UpdateOperationCounterRequest) (*models.Cell, error) {
+	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+
+	now := time.Now()
+	if req.OperationLimit != nil {
+		cell.OperationLimit = req.OperationLimit
+	}
+	if req.Reset {
+		cell.OperationCount = 0
+		cell.OperationCountResetAt = &now
+	}
+	if req.Count != nil {
+		cell.OperationCount = *req.Count
+	}
+	cell.UpdatedAt = now
+
+	if err := s.ruRepo.UpdateCell(cell); err != nil {
+		return nil, err
+	}
+	return cell, nil
+}
+
+// Tasks - источник задач для входящих: выключатели РУ организации, приблизившиеся к
+// ресурсу (для инженеров и админов). Выработавший ресурс выключатель отмечается как просроченный.
+func (s *OperationCounterService) Tasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	actor := models.UserActor(user)
+	if !actor.IsElevated() {
+		return nil, nil
+	}
+	cells, err := s.ruRepo.GetCellsByOperationWear(s.settings.Int(SettingBreakerWearPercent), actor.OrganizationScope())
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	for _, cell := range cells {
+		tasks = append(tasks, models.Task{
+			Type:    models.TaskTypeBreakerWear,
+			RefID:   strconv.Itoa(cell.ID),
+			Title:   i18n.T(lang, "task.breaker_wear", cell.Number, cell.Name, cell.OperationCount, *cell.OperationLimit),
+			RuID:    cell.RuID,
+			Overdue: cell.OperationCount >= *cell.OperationLimit,
+		})
+	}
+	return tasks, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// OrganizationService - организации-арендаторы. Создавать организации и переносить
+// в них подстанции может только администратор установки.
+type OrganizationService struct {
+	orgRepo   *repository.OrganizationRepository
+	ruService *RuService
+}
+
+func NewOrganizationService(orgRepo *repository.OrganizationRepository, ruService *RuService) *OrganizationService {
+	return &OrganizationService{orgRepo: orgRepo, ruService: ruService}
+}
+
+func (s *OrganizationService) GetAll() ([]models.Organization, error) {
+	return s.orgRepo.GetAll()
+}
+
+// Get - организация по идентификатору
+func (s *OrganizationService) Get(id string) (*models.Organization, error) {
+	org, err := s.orgRepo.GetByID(id)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org, nil
+}
+
+func (s *OrganizationService) Create(req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	now := time.Now()
+	org := &models.Organization{
+		ID:        utils.NewID(models.IDPrefixOrganization),
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.orgRepo.Create(org); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrOrganizationExists
+		}
+		return nil, err
+	}
+	return org, nil
+}
+
+// AssignSubstation - переносит подстанцию со всеми РУ и журналом операций в организацию
+func (s *OrganizationService) AssignSubstation(organizationID, substationID string) (int64, error) {
+	if _, err := s.Get(organizationID); err != nil {
+		return 0, err
+	}
+	if _, err := s.ruService.GetSubstation(substationID); err != nil {
+		return 0, err
+	}
+	return s.orgRepo.AssignSubstation(organizationID, substationID)
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/ical"
+	"github.com/Temoojeen/sez-vision-backend/internal/mailer"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// outageNotificationBatch - уведомлений потребителей за один запуск задачи
+	outageNotificationBatch = 50
+	// outageNotificationAttempts - попыток отправки письма до статуса failed
+	outageNotificationAttempts = 5
+	// outageTimeLayout - формат времени в письмах
+	outageTimeLayout = "02.01.2006 15:04"
+)
+
+// OutageService - плановые отключения: заявка, согласование вторым сотрудником,
+// предупреждения о пересечении с нарядами-допусками, уведомления потребителей и календарь
+type OutageService struct {
+	outageRepo *repository.OutageRepository
+	ruRepo     *repository.RuRepository
+	consumers  *ConsumerService
+	mailer     mailer.Sender
+}
+
+func NewOutageService(outageRepo *repository.OutageRepository, ruRepo *repository.RuRepository, consumers *ConsumerService, sender mailer.Sender) *OutageService {
+	return &OutageService{
+		outageRepo: outageRepo,
+		ruRepo:     ruRepo,
+		consumers:  consumers,
+		mailer:     sender,
+	}
+}
+
+// List - плановые отключения организации пользователя; администратор установки видит все
+func (s *OutageService) List(actor models.Actor, filter models.OutageFilter) ([]models.PlannedOutage, error) {
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() {
+		organizationID = ""
+	}
+	return s.outageRepo.GetOutages(organizationID, filter)
+}
+
+// Get - заявка с актуальными пересечениями с нарядами, оценкой потребителей и уведомлениями
+func (s *OutageService) Get(actor models.Actor, id string) (*models.OutageDetails, error) {
+	outage, err := s.getOutage(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.details(outage)
+}
+
+// Create - заявка на плановое отключение. Пересечения с нарядами-допусками не запрещают
+// заявку, а возвращаются предупреждениями для согласующего.
+func (s *OutageService) Create(actor models.Actor, req *models.CreateOutageRequest) (*models.OutageDetails, error) {
+	now := time.Now()
+	if !req.EndsAt.After(req.StartsAt) || !req.StartsAt.After(now) {
+		return nil, ErrOutageWindowInvalid
+	}
+
+	ru, err := s.ruRepo.GetRuByID(req.RuID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if !actor.CanAccessOrganization(ru.OrganizationID) {
+		return nil, ErrRuNotFound
+	}
+
+	cells, err := s.ruRepo.GetCellsByRuID(ru.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	byID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		byID[cell.ID] = cell
+	}
+
+	outage := &models.PlannedOutage{
+		ID:             utils.NewID(models.IDPrefixPlannedOutage),
+		RuID:           ru.ID,
+		OrganizationID: ru.OrganizationID,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		Reason:         req.Reason,
+		Status:         models.OutageRequested,
+		RequestedBy:    actor.Email,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	seen := map[int]bool{}
+	var numbers []string
+	for _, cellID := range req.CellIDs {
+		cell, ok := byID[cellID]
+		if !ok {
+			return nil, ErrCellNotFound.WithDetails(map[string]interface{}{"cellId": cellID})
+		}
+		if seen[cellID] {
+			continue
+		}
+		seen[cellID] = true
+		outage.Cells = append(outage.Cells, models.OutageCell{
+			OutageID:   outage.ID,
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+		})
+		numbers = append(numbers, cell.Number)
+	}
+
+	event, err := newEvent(models.EventApprovalRequested, ru.ID, models.ApprovalRequestedPayload{
+		Kind:        models.ApprovalPlannedOutage,
+		RefID:       outage.ID,
+		CellID:      outage.Cells[0].CellID,
+		CellNumber:  strings.Join(numbers, ", "),
+		RequestedBy: actor.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.outageRepo.Create(outage, event); err != nil {
+		return nil, err
+	}
+	return s.details(outage)
+}
+
+// Approve - согласование заявки инженером или администратором, но не ее автором.
+// Потребителям отключаемых линий ставятся в очередь письма, отключение попадает в календарь.
+func (s *OutageService) Approve(actor models.Actor, id string, req *models.ReviewOutageRequest) (*models.OutageDetails, error) {
+	outage, err := s.getOutage(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if outage.Status != models.OutageRequested {
+		return nil, ErrOutageState
+	}
+	if outage.RequestedBy == actor.Email {
+		return nil, ErrOutageSelfReview
+	}
+
+	ru, err := s.ruRepo.GetRuByID(outage.RuID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	consumers, err := s.consumers.AffectedConsumers(outage.RuID, outageCellIDs(outage))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s.review(outage, models.OutageApproved, actor, req.Comment, now)
+
+	subject := i18n.T(i18n.Default, "outage.email.planned.subject", outage.StartsAt.Format(outageTimeLayout))
+	var notifications []models.ConsumerNotification
+	for _, consumer := range consumers {
+		body := i18n.T(i18n.Default, "outage.email.planned.body", consumer.Company,
+			outage.StartsAt.Format(outageTimeLayout), outage.EndsAt.Format(outageTimeLayout), ru.Name, outageCellNumbers(outage), outage.Reason)
+		notifications = append(notifications, newConsumerNotification(outage, consumer, subject, body, now))
+	}
+
+	entry := outageCalendarEntry(outage, ru, models.CalendarEntryConfirmed, now)
+	event, err := newEvent(models.EventOutageApproved, outage.RuID, outageChangedPayload(outage, len(consumers)))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.outageRepo.Review(outage, notifications, entry, []models.OutboxEvent{event}); err != nil {
+		return nil, err
+	}
+	return s.details(outage)
+}
+
+// Reject - отклонение заявки; потребители не уведомляются
+func (s *OutageService) Reject(actor models.Actor, id string, req *models.ReviewOutageRequest) (*models.OutageDetails, error) {
+	outage, err := s.getOutage(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if outage.Status != models.OutageRequested {
+		return nil, ErrOutageState
+	}
+	if outage.RequestedBy == actor.Email {
+		return nil, ErrOutageSelfReview
+	}
+
+	s.review(outage, models.OutageRejected, actor, req.Comment, time.Now())
+	if err := s.outageRepo.Review(outage, nil, nil, nil); err != nil {
+		return nil, err
+	}
+	return s.details(outage)
+}
+
+// Cancel - отмена заявки автором или согласующим. Отмена согласованного отключения
+// уведомляет тех же потребителей и помечает событие календаря отмененным.
+func (s *OutageService) Cancel(actor models.Actor, id string, req *models.ReviewOutageRequest) (*models.OutageDetails, error) {
+	outage, err := s.getOutage(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if outage.Status != models.OutageRequested && outage.Status != models.OutageApproved {
+		return nil, ErrOutageState
+	}
+	if outage.RequestedBy != actor.Email && !actor.IsElevated() {
+		return nil, ErrOutageCancelNotAllowed
+	}
+
+	wasApproved := outage.Status == models.OutageApproved
+	now := time.Now()
+	s.review(outage, models.OutageCancelled, actor, req.Comment, now)
+	if !wasApproved {
+		if err := s.outageRepo.Review(outage, nil, nil, nil); err != nil {
+			return nil, err
+		}
+		return s.details(outage)
+	}
+
+	ru, err := s.ruRepo.GetRuByID(outage.RuID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	previous, err := s.outageRepo.GetNotifications(outage.ID)
+	if err != nil {
+		return nil, err
+	}
+	subject := i18n.T(i18n.Default, "outage.email.cancelled.subject", outage.StartsAt.Format(outageTimeLayout))
+	var notifications []models.ConsumerNotification
+	notified := map[string]bool{}
+	for _, sent := range previous {
+		if notified[sent.ConsumerID] {
+			continue
+		}
+		notified[sent.ConsumerID] = true
+		body := i18n.T(i18n.Default, "outage.email.cancelled.body", sent.Company,
+			outage.StartsAt.Format(outageTimeLayout), outage.EndsAt.Format(outageTimeLayout))
+		notifications = append(notifications, newConsumerNotification(outage, models.Consumer{
+			ID:           sent.ConsumerID,
+			Company:      sent.Company,
+			ContactEmail: sent.Recipient,
+		}, subject, body, now))
+	}
+
+	entry := outageCalendarEntry(outage, ru, models.CalendarEntryCancelled, now)
+	event, err := newEvent(models.EventOutageCancelled, outage.RuID, outageChangedPayload(outage, len(notifications)))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.outageRepo.Review(outage, notifications, entry, []models.OutboxEvent{event}); err != nil {
+		return nil, err
+	}
+	return s.details(outage)
+}
+
+// Notifications - уведомления потребителей по заявке
+func (s *OutageService) Notifications(actor models.Actor, id string) ([]models.ConsumerNotification, error) {
+	outage, err := s.getOutage(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.outageRepo.GetNotifications(outage.ID)
+}
+
+// DeliverNotifications - отправка писем из очереди; без настроенного SMTP письма ждут в очереди
+func (s *OutageService) DeliverNotifications(ctx context.Context) error {
+	if s.mailer == nil {
+		return nil
+	}
+	pending, err := s.outageRepo.GetPendingNotifications(outageNotificationBatch)
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		notification := &pending[i]
+		now := time.Now()
+		notification.Attempts++
+		notification.UpdatedAt = now
+		if err := s.mailer.Send(ctx, notification.Recipient, notification.Subject, notification.Body); err != nil {
+			log.Printf("⚠️ Consumer notification %s: %v", notification.ID, err)
+			notification.LastError = err.Error()
+			if notification.Attempts >= outageNotificationAttempts {
+				notification.Status = models.ConsumerNotificationFailed
+			}
+		} else {
+			notification.Status = models.ConsumerNotificationSent
+			notification.LastError = ""
+			notification.SentAt = &now
+		}
+		if err := s.outageRepo.UpdateNotification(notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CalendarFeed - согласованные и отмененные отключения организации пользователя для iCal
+func (s *OutageService) CalendarFeed(actor models.Actor) (ical.Calendar, error) {
+	from, to := calendarFeedWindow(time.Now())
+	events, err := s.CalendarEvents(actor, from, to)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	return ical.Calendar{Name: i18n.T(i18n.Default, "outage.calendar.name"), Events: events}, nil
+}
+
+// CalendarEvents - события календаря отключений организации пользователя за период
+func (s *OutageService) CalendarEvents(actor models.Actor, from, to time.Time) ([]ical.Event, error) {
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() {
+		organizationID = ""
+	}
+	entries, err := s.outageRepo.GetCalendarEntries(organizationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ical.Event, len(entries))
+	for i, entry := range entries {
+		events[i] = calendarEvent(entry)
+	}
+	return events, nil
+}
+
+// calendarEvent - запись календаря в виде события iCal; UID стабилен между выгрузками
+func calendarEvent(entry models.CalendarEntry) ical.Event {
+	status := ical.StatusConfirmed
+	if entry.Status == models.CalendarEntryCancelled {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:         entry.ID + "@sez-vision",
+		Summary:     entry.Title,
+		Description: entry.Description,
+		Location:    entry.Location,
+		Start:       entry.StartsAt,
+		End:         entry.EndsAt,
+		Status:      status,
+		Sequence:    entry.Sequence,
+		Updated:     entry.UpdatedAt,
+	}
+}
+
+func (s *OutageService) getOutage(actor models.Actor, id string) (*models.PlannedOutage, error) {
+	outage, err := s.outageRepo.GetByID(utils.NormalizeID(models.IDPrefixPlannedOutage, id))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrOutageNotFound
+		}
+		return nil, err
+	}
+	if !actor.CanAccessOrganization(outage.OrganizationID) {
+		return nil, ErrOutageNotFound
+	}
+	return outage, nil
+}
+
+func (s *OutageService) review(outage *models.PlannedOutage, status models.OutageStatus, actor models.Actor, comment string, now time.Time) {
+	outage.Status = status
+	outage.ReviewedBy = &actor.Email
+	outage.ReviewedAt = &now
+	outage.ReviewComment = comment
+	outage.UpdatedAt = now
+}
+
+func (s *OutageService) details(outage *models.PlannedOutage) (*models.OutageDetails, error) {
+	numbers := make([]string, len(outage.Cells))
+	for i, cell := range outage.Cells {
+		numbers[i] = cell.CellNumber
+	}
+	records, err := s.outageRepo.GetPermitConflicts(outage.RuID, numbers, outage.StartsAt, outage.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := make([]models.PermitConflict, len(records))
+	for i, record := range records {
+		conflicts[i] = models.PermitConflict{
+			RecordID:          record.ID,
+			WorkOrderNumber:   *record.WorkOrderNumber,
+			CellNumber:        record.CellNumber,
+			Action:            record.Action,
+			ResponsiblePerson: record.ResponsiblePerson,
+			StartsAt:          record.StartDateAt,
+			EndsAt:            record.EndDateAt,
+		}
+	}
+
+	impact, err := s.consumers.Impact(outage.RuID, outageCellIDs(outage))
+	if err != nil {
+		return nil, err
+	}
+	notifications, err := s.outageRepo.GetNotifications(outage.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutageDetails{
+		Outage:        outage,
+		Conflicts:     conflicts,
+		Impact:        impact,
+		Notifications: notifications,
+	}, nil
+}
+
+// outageCalendarEntry - запись календаря по заявке; отмененная запись сохраняет текст
+// согласованной, чтобы клиенты календаря показали то же событие отмененным
+func outageCalendarEntry(outage *models.PlannedOutage, ru *models.RUInfo, status models.CalendarEntryStatus, now time.Time) *models.CalendarEntry {
+	return &models.CalendarEntry{
+		ID:             utils.NewID(models.IDPrefixCalendarEntry),
+		Kind:           models.CalendarEntryPlannedOutage,
+		RefID:          outage.ID,
+		RuID:           outage.RuID,
+		OrganizationID: outage.OrganizationID,
+		Title:          i18n.T(i18n.Default, "outage.calendar.title", ru.Name, outageCellNumbers(outage)),
+		Description:    outage.Reason,
+		Location:       strings.TrimSpace(ru.Name + " " + ru.Location),
+		StartsAt:       outage.StartsAt,
+		EndsAt:         outage.EndsAt,
+		Status:         status,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// newConsumerNotification - письмо в очередь; без email потребителя предупреждает диспетчер по телефону
+func newConsumerNotification(outage *models.PlannedOutage, consumer models.Consumer, subject, body string, now time.Time) models.ConsumerNotification {
+	status := models.ConsumerNotificationPending
+	if consumer.ContactEmail == "" {
+		status = models.ConsumerNotificationNoContact
+	}
+	return models.ConsumerNotification{
+		ID:         utils.NewID(models.IDPrefixConsumerNotification),
+		OutageID:   outage.ID,
+		ConsumerID: consumer.ID,
+		Company:    consumer.Company,
+		Recipient:  consumer.ContactEmail,
+		Subject:    subject,
+		Body:       body,
+		Status:     status,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func outageChangedPayload(outage *models.PlannedOutage, consumers int) models.OutageChangedPayload {
+	return models.OutageChangedPayload{
+		OutageID:  outage.ID,
+		Status:    outage.Status,
+		StartsAt:  outage.StartsAt,
+		EndsAt:    outage.EndsAt,
+		CellIDs:   outageCellIDs(outage),
+		Consumers: consumers,
+	}
+}
+
+func outageCellIDs(outage *models.PlannedOutage) []int {
+	ids := make([]int, len(outage.Cells))
+	for i, cell := range outage.Cells {
+		ids[i] = cell.CellID
+	}
+	return ids
+}
+
+func outageCellNumbers(outage *models.PlannedOutage) string {
+	numbers := make([]string, len(outage.Cells))
+	for i, cell := range outage.Cells {
+		numbers[i] = cell.CellNumber
+	}
+	return strings.Join(numbers, ", ")
+}
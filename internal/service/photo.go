@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/exif"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/internal/storage"
+	"github.com/Temoojeen/sez-vision-backend/internal/thumbnail"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// MaxPhotoSize - предельный размер загружаемой фотографии
+const MaxPhotoSize = 10 << 20
+
+// thumbnailMaxSide - наибольшая сторона миниатюры для списков и карточек
+const thumbnailMaxSide = 320
+
+// photoContentTypes - допустимые форматы фотографий
+var photoContentTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// PhotoService - фотографии оборудования, дефектов и пунктов осмотра: время съемки
+// и координаты из EXIF, миниатюра и хранение оригинала в объектном хранилище.
+type PhotoService struct {
+	photoRepo *repository.PhotoRepository
+	ruRepo    *repository.RuRepository
+	// store - объектное хранилище; nil - оригиналы хранятся в БД
+	store storage.Store
+}
+
+func NewPhotoService(photoRepo *repository.PhotoRepository, ruRepo *repository.RuRepository, store storage.Store) *PhotoService {
+	return &PhotoService{photoRepo: photoRepo, ruRepo: ruRepo, store: store}
+}
+
+// GetByOwners - метаданные фотографий набора записей
+func (s *PhotoService) GetByOwners(ownerType models.PhotoOwnerType, ownerIDs []string) ([]models.Photo, error) {
+	return s.photoRepo.GetByOwners(ownerType, ownerIDs)
+}
+
+// Save - проверяет фотографию, извлекает EXIF, строит миниатюру и сохраняет оригинал
+func (s *PhotoService) Save(ownerType models.PhotoOwnerType, ownerID, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	photo, err := newPhoto(ownerType, ownerID, fileName, data, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	orientation := 0
+	if info, err := exif.Parse(data); err == nil {
+		photo.TakenAt = info.TakenAt
+		photo.Latitude = info.Latitude
+		photo.Longitude = info.Longitude
+		photo.Camera = strings.TrimSpace(info.Make + " " + info.Model)
+		orientation = info.Orientation
+	}
+
+	thumb, err := thumbnail.Make(data, thumbnailMaxSide, orientation)
+	switch {
+	case err == nil:
+		photo.Thumbnail = thumb.Data
+		photo.HasThumbnail = true
+		photo.Width = thumb.Width
+		photo.Height = thumb.Height
+	case errors.Is(err, thumbnail.ErrUnsupported):
+		// WebP стандартная библиотека не декодирует: фотография сохраняется без миниатюры
+	default:
+		return nil, ErrPhotoInvalid.WithDetails(map[string]interface{}{"contentType": photo.ContentType})
+	}
+
+	if s.store != nil {
+		photo.StorageKey = photoStorageKey(photo)
+		if err := s.store.Put(context.Background(), photo.StorageKey, photo.ContentType, data); err != nil {
+			return nil, fmt.Errorf("failed to store photo: %w", err)
+		}
+		photo.Data = nil
+	}
+
+	if err := s.photoRepo.Create(photo); err != nil {
+		if photo.StorageKey != "" {
+			if err := s.store.Delete(context.Background(), photo.StorageKey); err != nil {
+				log.Printf("⚠️ Failed to delete orphaned photo %s: %v", photo.StorageKey, err)
+			}
+		}
+		return nil, fmt.Errorf("failed to save photo: %w", err)
+	}
+	return photo, nil
+}
+
+// Get - фотография с содержимым; thumb - миниатюра вместо оригинала. Если миниатюры
+// нет (WebP), отдается оригинал.
+func (s *PhotoService) Get(ownerType models.PhotoOwnerType, ownerID, photoID string, thumb bool) (*models.Photo, error) {
+	photo, err := s.photoRepo.GetByID(ownerType, ownerID, utils.NormalizeID(models.IDPrefixPhoto, photoID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrPhotoNotFound
+		}
+		return nil, fmt.Errorf("failed to get photo: %w", err)
+	}
+
+	if thumb && photo.HasThumbnail {
+		photo.Data = photo.Thumbnail
+		photo.ContentType = thumbnail.ContentType
+		return photo, nil
+	}
+	if photo.StorageKey != "" {
+		if s.store == nil {
+			return nil, fmt.Errorf("photo %s is in object storage, but storage is not configured", photo.ID)
+		}
+		data, err := s.store.Get(context.Background(), photo.StorageKey)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, ErrPhotoNotFound
+			}
+			return nil, fmt.Errorf("failed to load photo: %w", err)
+		}
+		photo.Data = data
+	}
+	return photo, nil
+}
+
+// AddCellPhoto - снимок оборудования ячейки
+func (s *PhotoService) AddCellPhoto(ruID string, cellID int, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	if err := s.checkCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	return s.Save(models.PhotoOwnerCell, strconv.Itoa(cellID), fileName, data, actor)
+}
+
+// GetCellPhotos - снимки ячейки, новые последними
+func (s *PhotoService) GetCellPhotos(ruID string, cellID int) ([]models.Photo, error) {
+	if err := s.checkCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	photos, err := s.photoRepo.GetByOwners(models.PhotoOwnerCell, []string{strconv.Itoa(cellID)})
+	if err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+func (s *PhotoService) GetCellPhoto(ruID string, cellID int, photoID string, thumb bool) (*models.Photo, error) {
+	if err := s.checkCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	return s.Get(models.PhotoOwnerCell, strconv.Itoa(cellID), photoID, thumb)
+}
+
+func (s *PhotoService) checkCell(ruID string, cellID int) error {
+	if _, err := s.ruRepo.GetCellByID(cellID, ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return ErrCellNotFound
+		}
+		return fmt.Errorf("failed to get cell: %w", err)
+	}
+	return nil
+}
+
+// photoStorageKey - ключ оригинала: photos/<тип записи>/<id фотографии>.<расширение>
+func photoStorageKey(photo *models.Photo) string {
+	return "photos/" + string(photo.OwnerType) + "/" + photo.ID + "." + strings.TrimPrefix(photo.ContentType, "image/")
+}
+
+// newPhoto - проверяет размер и формат фотографии
+func newPhoto(ownerType models.PhotoOwnerType, ownerID, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	if len(data) == 0 || len(data) > MaxPhotoSize {
+		return nil, ErrPhotoInvalid
+	}
+	contentType := http.DetectContentType(data)
+	if !slices.Contains(photoContentTypes, contentType) {
+		return nil, ErrPhotoInvalid.WithDetails(map[string]interface{}{"contentType": contentType})
+	}
+
+	return &models.Photo{
+		ID:          utils.NewID(models.IDPrefixPhoto),
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Data:        data,
+		UploadedBy:  actor.Email,
+		CreatedAt:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// PollingService - управление опросом телеметрии без перезапуска сервиса.
+// Адаптеры протоколов перед каждым циклом опроса проверяют IsPaused; состояние
+// хранится в БД и кэшируется в памяти.
+type PollingService struct {
+	pollingRepo *repository.PollingRepository
+
+	mu       sync.RWMutex
+	adapters map[string]bool
+	pauses   []models.PollingPause
+}
+
+func NewPollingService(pollingRepo *repository.PollingRepository) *PollingService {
+	s := &PollingService{
+		pollingRepo: pollingRepo,
+		adapters:    make(map[string]bool),
+	}
+	if err := s.reload(); err != nil {
+		log.Printf("⚠️ Failed to load polling state: %v", err)
+	}
+	return s
+}
+
+// RegisterAdapter - регистрирует адаптер протокола (Modbus, IEC 104 и т.д.)
+func (s *PollingService) RegisterAdapter(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.adapters[name] = true
+}
+
+func (s *PollingService) reload() error {
+	pauses, err := s.pollingRepo.GetPauses()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pauses = pauses
+	return nil
+}
+
+// PauseFor - действующая приостановка, под которую попадает опрос адаптера на РУ.
+// Пустой adapter означает "любой адаптер".
+func (s *PollingService) PauseFor(adapter, ruID string) *models.PollingPause {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for _, pause := range s.pauses {
+		if !pause.Active(now) {
+			continue
+		}
+		adapterMatch := pause.Adapter == "" || adapter == "" || pause.Adapter == adapter
+		ruMatch := pause.RuID == "" || pause.RuID == ruID
+		if adapterMatch && ruMatch {
+			p := pause
+			return &p
+		}
+	}
+	return nil
+}
+
+// IsPaused - приостановлен ли опрос адаптера на РУ
+func (s *PollingService) IsPaused(adapter, ruID string) bool {
+	return s.PauseFor(adapter, ruID) != nil
+}
+
+// GetState - зарегистрированные адаптеры и действующие приостановки
+func (s *PollingService) GetState() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	adapters := make([]string, 0, len(s.adapters))
+	for name := range s.adapters {
+		adapters = append(adapters, name)
+	}
+	sort.Strings(adapters)
+
+	now := time.Now()
+	pauses := []models.PollingPause{}
+	for _, pause := range s.pauses {
+		if pause.Active(now) {
+			pauses = append(pauses, pause)
+		}
+	}
+
+	return map[string]interface{}{
+		"adapters": adapters,
+		"pauses":   pauses,
+	}
+}
+
+func (s *PollingService) Pause(req *models.PausePollingRequest, pausedBy string) (*models.PollingPause, error) {
+	if req.Adapter == "" && req.RuID == "" {
+		return nil, ErrPollingScopeEmpty
+	}
+
+	now := time.Now()
+	pause := &models.PollingPause{
+		ID:        utils.NewID(models.IDPrefixPollingPause),
+		Adapter:   req.Adapter,
+		RuID:      req.RuID,
+		Reason:    req.Reason,
+		PausedBy:  pausedBy,
+		PausedAt:  now,
+		Until:     req.Until,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.pollingRepo.UpsertPause(pause); err != nil {
+		return nil, fmt.Errorf("failed to pause polling: %w", err)
+	}
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("failed to reload polling state: %w", err)
+	}
+	return pause, nil
+}
+
+func (s *PollingService) Resume(req *models.ResumePollingRequest) error {
+	deleted, err := s.pollingRepo.DeletePause(req.Adapter, req.RuID)
+	if err != nil {
+		return fmt.Errorf("failed to resume polling: %w", err)
+	}
+	if !deleted {
+		return ErrPollingPauseNotFound
+	}
+	if err := s.reload(); err != nil {
+		return fmt.Errorf("failed to reload polling state: %w", err)
+	}
+	return nil
+}
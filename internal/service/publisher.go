@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/broker"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	brokerPublishTimeout = 10 * time.Second
+	// brokerBatchSize - событий outbox, читаемых за один запрос
+	brokerBatchSize = 100
+	// brokerMaxBackoff - наибольшая пауза перед повтором после ошибки брокера
+	brokerMaxBackoff = 5 * time.Minute
+)
+
+// brokerEventSchema - схема Avro доменного события. Данные события (payload) у разных
+// типов разные, поэтому передаются строкой JSON.
+const brokerEventSchema = `{"type":"record","name":"DomainEvent","namespace":"sez.events","fields":[` +
+	`{"name":"id","type":"string"},` +
+	`{"name":"type","type":"string"},` +
+	`{"name":"ruId","type":"string"},` +
+	`{"name":"payload","type":"string"},` +
+	`{"name":"createdAt","type":{"type":"long","logicalType":"timestamp-millis"}}]}`
+
+// brokerEventCanonicalSchema - brokerEventSchema в канонической форме Avro, по которой
+// считается отпечаток схемы в сообщениях NATS
+const brokerEventCanonicalSchema = `{"name":"sez.events.DomainEvent","type":"record","fields":[` +
+	`{"name":"id","type":"string"},` +
+	`{"name":"type","type":"string"},` +
+	`{"name":"ruId","type":"string"},` +
+	`{"name":"payload","type":"string"},` +
+	`{"name":"createdAt","type":"long"}]}`
+
+// BrokerEvent - формат доменного события во внешнем брокере
+type BrokerEvent struct {
+	ID        string                 `json:"id"`
+	Type      models.DomainEventType `json:"type"`
+	RuID      string                 `json:"ruId"`
+	Payload   json.RawMessage        `json:"payload"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// avroValue - событие в JSON-представлении Avro по схеме brokerEventSchema
+func (e BrokerEvent) avroValue() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        e.ID,
+		"type":      string(e.Type),
+		"ruId":      e.RuID,
+		"payload":   string(e.Payload),
+		"createdAt": e.CreatedAt.UnixMilli(),
+	}
+}
+
+// appendAvro - событие в двоичной кодировке Avro по схеме brokerEventSchema
+func (e BrokerEvent) appendAvro(buf []byte) []byte {
+	buf = broker.AppendAvroString(buf, e.ID)
+	buf = broker.AppendAvroString(buf, string(e.Type))
+	buf = broker.AppendAvroString(buf, e.RuID)
+	buf = broker.AppendAvroString(buf, string(e.Payload))
+	return broker.AppendAvroLong(buf, e.CreatedAt.UnixMilli())
+}
+
+// EventPublisher - публикация всех доменных событий во внешний брокер (Kafka или NATS)
+// для хранилища данных и ML. Брокер не подписчик шины событий: он ведет свою отметку
+// доставки в outbox, поэтому его сбой не повторяет доставку внутренним подписчикам,
+// а публикация идет вне транзакций outbox. События публикуются в порядке создания;
+// при ошибке публикация останавливается и повторяется с того же события.
+type EventPublisher struct {
+	publisher   broker.Publisher
+	outboxRepo  *repository.OutboxRepository
+	brokerType  string
+	format      string
+	topicPrefix string
+	fingerprint uint64
+}
+
+// NewEventPublisher - format: broker.FormatAvro (Kafka - через Schema Registry REST
+// Proxy, NATS - Avro single-object encoding) или broker.FormatJSON
+func NewEventPublisher(publisher broker.Publisher, outboxRepo *repository.OutboxRepository, brokerType, format, topicPrefix string) (*EventPublisher, error) {
+	if format != broker.FormatAvro && format != broker.FormatJSON {
+		return nil, fmt.Errorf("unknown broker format %q, expected %s or %s", format, broker.FormatAvro, broker.FormatJSON)
+	}
+	return &EventPublisher{
+		publisher:   publisher,
+		outboxRepo:  outboxRepo,
+		brokerType:  brokerType,
+		format:      format,
+		topicPrefix: topicPrefix,
+		fingerprint: broker.AvroFingerprint(brokerEventCanonicalSchema),
+	}, nil
+}
+
+// Enabled - настроен ли внешний брокер
+func (p *EventPublisher) Enabled() bool {
+	return p.publisher != nil
+}
+
+// Run - цикл публикации до отмены ctx. Запускается на одном экземпляре
+// (Scheduler.RunExclusive), чтобы события не публиковались параллельно и не по порядку.
+// После ошибки брокера пауза перед повтором растет до brokerMaxBackoff.
+func (p *EventPublisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+
+	var backoff time.Duration
+	var retryAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if time.Now().Before(retryAt) {
+			continue
+		}
+		if _, err := p.PublishPending(ctx); err != nil {
+			backoff = min(max(2*backoff, eventPollInterval), brokerMaxBackoff)
+			retryAt = time.Now().Add(backoff)
+			log.Printf("⚠️ Broker publish failed, retrying in %s: %v", backoff, err)
+			continue
+		}
+		backoff = 0
+	}
+}
+
+// PublishPending - публикует все еще не опубликованные события до первой ошибки
+func (p *EventPublisher) PublishPending(ctx context.Context) (int, error) {
+	if p.publisher == nil {
+		return 0, nil
+	}
+
+	published := 0
+	for {
+		events, err := p.outboxRepo.GetUnpublished(brokerBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for i := range events {
+			if ctx.Err() != nil {
+				return published, nil
+			}
+			event := &events[i]
+			if err := p.publish(ctx, event); err != nil {
+				if statsErr := p.outboxRepo.RecordPublishFailure(event.Type, err.Error(), time.Now()); statsErr != nil {
+					log.Printf("⚠️ %v", statsErr)
+				}
+				return published, fmt.Errorf("event %s (%s): %w", event.ID, event.Type, err)
+			}
+			if err := p.outboxRepo.MarkPublished(event, time.Now()); err != nil {
+				return published, err
+			}
+			published++
+		}
+		if len(events) < brokerBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// publish - публикует событие; тема формируется как <prefix>.<тип события>
+func (p *EventPublisher) publish(ctx context.Context, event *models.OutboxEvent) error {
+	msg, err := p.message(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, brokerPublishTimeout)
+	defer cancel()
+	return p.publisher.Publish(ctx, msg)
+}
+
+// message - сообщение брокера с событием в выбранном формате
+func (p *EventPublisher) message(event *models.OutboxEvent) (broker.Message, error) {
+	brokerEvent := BrokerEvent{
+		ID:        event.ID,
+		Type:      event.Type,
+		RuID:      event.RuID,
+		Payload:   json.RawMessage(event.Payload),
+		CreatedAt: event.CreatedAt,
+	}
+	msg := broker.Message{
+		Subject: p.topicPrefix + "." + string(event.Type),
+		Key:     event.RuID,
+	}
+
+	if p.format == broker.FormatAvro {
+		msg.Payload = broker.AvroSingleObject(p.fingerprint, brokerEvent.appendAvro(nil))
+		msg.Avro = &broker.AvroRecord{Schema: brokerEventSchema, Value: brokerEvent.avroValue()}
+		return msg, nil
+	}
+
+	data, err := json.Marshal(brokerEvent)
+	if err != nil {
+		return broker.Message{}, fmt.Errorf("failed to encode broker event: %w", err)
+	}
+	msg.Payload = data
+	return msg, nil
+}
+
+// Stats - статистика публикации по типам событий, накопленная всеми экземплярами,
+// и число событий, ждущих публикации
+func (p *EventPublisher) Stats() (map[string]interface{}, error) {
+	byType, err := p.outboxRepo.GetBrokerStats()
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]interface{}{
+		"enabled":     p.Enabled(),
+		"broker":      p.brokerType,
+		"format":      p.format,
+		"topicPrefix": p.topicPrefix,
+		"events":      byType,
+	}
+	if p.Enabled() {
+		pending, err := p.outboxRepo.CountUnpublished()
+		if err != nil {
+			return nil, err
+		}
+		stats["pending"] = pending
+	}
+	return stats, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/broker"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// fakeBroker - брокер, который принимает сообщения, пока не задана ошибка failOn
+type fakeBroker struct {
+	failOn   string
+	subjects []string
+	messages []broker.Message
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, msg broker.Message) error {
+	if b.failOn != "" && msg.Key == b.failOn {
+		return errors.New("broker unavailable")
+	}
+	b.subjects = append(b.subjects, msg.Subject)
+	b.messages = append(b.messages, msg)
+	return nil
+}
+
+func (b *fakeBroker) Ping(ctx context.Context) error { return nil }
+func (b *fakeBroker) Close() error                   { return nil }
+
+func TestEventPublisherPublishPending(t *testing.T) {
+	db := newTestDB(t, &models.OutboxEvent{}, &models.BrokerDeliveryStats{})
+	outboxRepo := repository.NewOutboxRepository(db)
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []models.OutboxEvent{
+		{ID: "evt-1", Type: models.EventAlarmRaised, RuID: "ru-1", Payload: `{}`, Status: models.OutboxStatusProcessed, CreatedAt: start},
+		{ID: "evt-2", Type: models.EventFaultRecorded, RuID: "ru-2", Payload: `{}`, Status: models.OutboxStatusProcessed, CreatedAt: start.Add(time.Second)},
+		{ID: "evt-3", Type: models.EventAlarmRaised, RuID: "ru-3", Payload: `{}`, Status: models.OutboxStatusPending, CreatedAt: start.Add(2 * time.Second)},
+	}
+	if err := db.Create(&events).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	fake := &fakeBroker{failOn: "ru-2"}
+	publisher, err := NewEventPublisher(fake, outboxRepo, broker.TypeNATS, broker.FormatJSON, "sez.events")
+	if err != nil {
+		t.Fatalf("new publisher: %v", err)
+	}
+
+	// Сбой брокера останавливает публикацию на событии и не трогает доставку подписчикам
+	published, err := publisher.PublishPending(context.Background())
+	if err == nil || published != 1 {
+		t.Fatalf("PublishPending = %d, %v; want 1 and broker error", published, err)
+	}
+	var stored []models.OutboxEvent
+	if err := db.Order("created_at").Find(&stored).Error; err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	for i, event := range stored {
+		if event.Status != events[i].Status || event.Attempts != 0 {
+			t.Errorf("%s: status %s, attempts %d; subscriber delivery must not change", event.ID, event.Status, event.Attempts)
+		}
+		if wantPublished := i == 0; (event.BrokerPublishedAt != nil) != wantPublished {
+			t.Errorf("%s: published = %v, want %v", event.ID, event.BrokerPublishedAt != nil, wantPublished)
+		}
+	}
+
+	// После восстановления брокера публикация продолжается с того же события
+	fake.failOn = ""
+	if published, err := publisher.PublishPending(context.Background()); err != nil || published != 2 {
+		t.Fatalf("PublishPending after recovery = %d, %v; want 2", published, err)
+	}
+	wantSubjects := []string{"sez.events.alarm.raised", "sez.events.fault.recorded", "sez.events.alarm.raised"}
+	if !slices.Equal(fake.subjects, wantSubjects) {
+		t.Errorf("subjects = %v, want %v", fake.subjects, wantSubjects)
+	}
+
+	// Статистика хранится в базе: ее видит и новый экземпляр
+	restarted, err := NewEventPublisher(fake, outboxRepo, broker.TypeNATS, broker.FormatJSON, "sez.events")
+	if err != nil {
+		t.Fatalf("new publisher: %v", err)
+	}
+	stats, err := restarted.Stats()
+	if err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	if pending := stats["pending"]; pending != int64(0) {
+		t.Errorf("pending = %v, want 0", pending)
+	}
+	byType := map[models.DomainEventType]models.BrokerDeliveryStats{}
+	for _, entry := range stats["events"].([]models.BrokerDeliveryStats) {
+		byType[entry.Type] = entry
+	}
+	if got := byType[models.EventAlarmRaised]; got.Published != 2 || got.Failed != 0 {
+		t.Errorf("alarm.raised stats = %+v, want 2 published", got)
+	}
+	fault := byType[models.EventFaultRecorded]
+	if fault.Published != 1 || fault.Failed != 1 || fault.LastError == nil || fault.LastPublishedAt == nil {
+		t.Errorf("fault.recorded stats = %+v, want 1 published, 1 failed with error kept", fault)
+	}
+}
+
+func TestEventPublisherAvroMessage(t *testing.T) {
+	publisher, err := NewEventPublisher(&fakeBroker{}, nil, broker.TypeKafka, broker.FormatAvro, "sez.events")
+	if err != nil {
+		t.Fatalf("new publisher: %v", err)
+	}
+	createdAt := time.UnixMilli(1700000000123)
+	msg, err := publisher.message(&models.OutboxEvent{
+		ID: "evt-1", Type: models.EventAlarmRaised, RuID: "ru-1", Payload: `{"level":"high"}`, CreatedAt: createdAt,
+	})
+	if err != nil {
+		t.Fatalf("message: %v", err)
+	}
+
+	// NATS: single-object encoding с отпечатком канонической схемы
+	payload := msg.Payload
+	if !bytes.HasPrefix(payload, []byte{0xc3, 0x01}) {
+		t.Fatalf("payload has no single-object marker: % x", payload[:2])
+	}
+	if got := binary.LittleEndian.Uint64(payload[2:10]); got != broker.AvroFingerprint(brokerEventCanonicalSchema) {
+		t.Errorf("fingerprint = %x, want fingerprint of the event schema", got)
+	}
+	body := payload[10:]
+	var fields []string
+	for range 4 {
+		var s string
+		s, body = readAvroString(t, body)
+		fields = append(fields, s)
+	}
+	if want := []string{"evt-1", "alarm.raised", "ru-1", `{"level":"high"}`}; !slices.Equal(fields, want) {
+		t.Errorf("fields = %q, want %q", fields, want)
+	}
+	millis, rest := readAvroLong(t, body)
+	if millis != createdAt.UnixMilli() || len(rest) != 0 {
+		t.Errorf("createdAt = %d (%d bytes left), want %d", millis, len(rest), createdAt.UnixMilli())
+	}
+
+	// Kafka: схема и значение для REST Proxy
+	if msg.Avro == nil || msg.Avro.Schema != brokerEventSchema {
+		t.Fatalf("message has no Avro record for Kafka")
+	}
+	if value := msg.Avro.Value.(map[string]interface{}); value["createdAt"] != createdAt.UnixMilli() || value["payload"] != `{"level":"high"}` {
+		t.Errorf("Avro value = %v", value)
+	}
+}
+
+// Каноническая форма должна соответствовать схеме: другой набор полей сменил бы отпечаток
+func TestBrokerEventCanonicalSchema(t *testing.T) {
+	var schema struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+		Fields    []struct {
+			Name string          `json:"name"`
+			Type json.RawMessage `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(brokerEventSchema), &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	canonical := `{"name":"` + schema.Namespace + "." + schema.Name + `","type":"record","fields":[`
+	for i, field := range schema.Fields {
+		fieldType := string(field.Type)
+		var logical struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(field.Type, &logical) == nil {
+			fieldType = `"` + logical.Type + `"`
+		}
+		if i > 0 {
+			canonical += ","
+		}
+		canonical += `{"name":"` + field.Name + `","type":` + fieldType + `}`
+	}
+	canonical += "]}"
+	if canonical != brokerEventCanonicalSchema {
+		t.Errorf("canonical schema = %s, want %s", brokerEventCanonicalSchema, canonical)
+	}
+}
+
+func readAvroLong(t *testing.T, buf []byte) (int64, []byte) {
+	t.Helper()
+	v, n := binary.Uvarint(buf)
+	if n <= 0 {
+		t.Fatalf("bad Avro long: % x", buf)
+	}
+	return int64(v>>1) ^ -int64(v&1), buf[n:]
+}
+
+func readAvroString(t *testing.T, buf []byte) (string, []byte) {
+	t.Helper()
+	length, rest := readAvroLong(t, buf)
+	if int64(len(rest)) < length {
+		t.Fatalf("short Avro string: % x", buf)
+	}
+	return string(rest[:length]), rest[length:]
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/internal/webpush"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// pushTTL - сколько push-сервис хранит уведомление для браузера без связи
+	pushTTL = 24 * time.Hour
+	// pushDeliveryTimeout - время на рассылку одного пакета уведомлений
+	pushDeliveryTimeout = time.Minute
+	// pushMaxFailures - после стольких ошибок подряд подписка удаляется
+	pushMaxFailures = 5
+	// pushMaxBody - длина текста уведомления в символах
+	pushMaxBody = 500
+)
+
+// PushService - Web Push для консоли диспетчера: уведомления центра уведомлений
+// дублируются в браузер, даже если вкладка свернута
+type PushService struct {
+	pushRepo *repository.PushRepository
+	sender   *webpush.Sender
+}
+
+func NewPushService(pushRepo *repository.PushRepository, sender *webpush.Sender) *PushService {
+	return &PushService{pushRepo: pushRepo, sender: sender}
+}
+
+// PublicKey - открытый ключ VAPID для подписки браузера
+func (s *PushService) PublicKey() (*models.PushPublicKey, error) {
+	if s.sender == nil {
+		return nil, ErrPushDisabled
+	}
+	return &models.PushPublicKey{PublicKey: s.sender.PublicKey()}, nil
+}
+
+// GetSubscriptions - подписки браузеров пользователя
+func (s *PushService) GetSubscriptions(userID string) ([]models.PushSubscription, error) {
+	return s.pushRepo.GetSubscriptions([]string{userID})
+}
+
+// Subscribe - сохраняет подписку браузера пользователя
+func (s *PushService) Subscribe(userID string, req *models.CreatePushSubscriptionRequest, userAgent string) (*models.PushSubscription, error) {
+	if s.sender == nil {
+		return nil, ErrPushDisabled
+	}
+	if err := webpush.Validate(webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}); err != nil {
+		return nil, ErrPushSubscriptionInvalid.WithDetails(map[string]interface{}{"reason": err.Error()})
+	}
+	if len(userAgent) > 200 {
+		userAgent = userAgent[:200]
+	}
+	now := time.Now()
+	subscription := &models.PushSubscription{
+		ID:        utils.NewID(models.IDPrefixPushSubscription),
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.pushRepo.SaveSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *PushService) Unsubscribe(userID, id string) error {
+	deleted, err := s.pushRepo.DeleteSubscription(userID, utils.NormalizeID(models.IDPrefixPushSubscription, id))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// Notify - отправляет новые уведомления в браузеры получателей в фоне, не задерживая
+// обработку события; без ключей VAPID ничего не делает
+func (s *PushService) Notify(notifications []models.Notification) {
+	if s == nil || s.sender == nil || len(notifications) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), pushDeliveryTimeout)
+		defer cancel()
+		if err := s.deliver(ctx, notifications); err != nil {
+			log.Printf("⚠️ Web push: %v", err)
+		}
+	}()
+}
+
+func (s *PushService) deliver(ctx context.Context, notifications []models.Notification) error {
+	userIDs := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		userIDs = append(userIDs, notification.UserID)
+	}
+	subscriptions, err := s.pushRepo.GetSubscriptions(userIDs)
+	if err != nil {
+		return err
+	}
+	byUser := make(map[string][]models.PushSubscription)
+	for _, subscription := range subscriptions {
+		byUser[subscription.UserID] = append(byUser[subscription.UserID], subscription)
+	}
+
+	for _, notification := range notifications {
+		if len(byUser[notification.UserID]) == 0 {
+			continue
+		}
+		payload, err := pushPayload(notification)
+		if err != nil {
+			return err
+		}
+		urgency := webpush.UrgencyNormal
+		if notification.Category == models.EventCategoryAlarm {
+			urgency = webpush.UrgencyHigh
+		}
+		for _, subscription := range byUser[notification.UserID] {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			s.send(ctx, subscription, payload, urgency)
+		}
+	}
+	return nil
+}
+
+// send - отправка в одну подписку; отозванная браузером или раз за разом
+// недоступная подписка удаляется
+func (s *PushService) send(ctx context.Context, subscription models.PushSubscription, payload []byte, urgency webpush.Urgency) {
+	target := webpush.Subscription{Endpoint: subscription.Endpoint, P256dh: subscription.P256dh, Auth: subscription.Auth}
+	err := s.sender.Send(ctx, target, payload, pushTTL, urgency)
+	if err == nil {
+		if err := s.pushRepo.MarkDelivered(subscription.ID, time.Now()); err != nil {
+			log.Printf("⚠️ Web push %s: %v", subscription.ID, err)
+		}
+		return
+	}
+
+	if errors.Is(err, webpush.ErrSubscriptionGone) {
+		if err := s.pushRepo.DeleteExpired(subscription.ID); err != nil {
+			log.Printf("⚠️ Web push %s: %v", subscription.ID, err)
+		}
+		return
+	}
+	log.Printf("⚠️ Web push %s: %v", subscription.ID, err)
+	failures, err := s.pushRepo.MarkFailed(subscription.ID)
+	if err != nil {
+		log.Printf("⚠️ Web push %s: %v", subscription.ID, err)
+		return
+	}
+	if failures >= pushMaxFailures {
+		if err := s.pushRepo.DeleteExpired(subscription.ID); err != nil {
+			log.Printf("⚠️ Web push %s: %v", subscription.ID, err)
+		}
+	}
+}
+
+func pushPayload(notification models.Notification) ([]byte, error) {
+	body := notification.Message
+	if runes := []rune(body); len(runes) > pushMaxBody {
+		body = string(runes[:pushMaxBody-1]) + "…"
+	}
+	payload, err := json.Marshal(models.PushMessage{
+		ID:        notification.ID,
+		Category:  notification.Category,
+		RuID:      notification.RuID,
+		Title:     notification.Title,
+		Body:      body,
+		CreatedAt: notification.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode push message: %w", err)
+	}
+	return payload, nil
+}
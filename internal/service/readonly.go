@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// readOnlyRefreshInterval - как часто перечитывается состояние из БД, чтобы переключение
+// на одном экземпляре сервиса подхватили остальные
+const readOnlyRefreshInterval = 5 * time.Second
+
+// ReadOnlyService - аварийный режим "только чтение". Состояние хранится в БД
+// и кэшируется в памяти; middleware проверяет его на каждом изменяющем запросе.
+type ReadOnlyService struct {
+	readOnlyRepo *repository.ReadOnlyRepository
+
+	mu       sync.RWMutex
+	mode     models.ReadOnlyMode
+	loadedAt time.Time
+}
+
+func NewReadOnlyService(readOnlyRepo *repository.ReadOnlyRepository) *ReadOnlyService {
+	s := &ReadOnlyService{readOnlyRepo: readOnlyRepo}
+	if err := s.reload(); err != nil {
+		log.Printf("⚠️ Failed to load read-only mode: %v", err)
+	}
+	return s
+}
+
+func (s *ReadOnlyService) reload() error {
+	mode, err := s.readOnlyRepo.Get()
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mode = *mode
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// State - текущее состояние режима. При ошибке чтения БД используется последнее
+// известное состояние.
+func (s *ReadOnlyService) State() models.ReadOnlyMode {
+	s.mu.RLock()
+	stale := time.Since(s.loadedAt) > readOnlyRefreshInterval
+	mode := s.mode
+	s.mu.RUnlock()
+
+	if stale {
+		if err := s.reload(); err != nil {
+			log.Printf("⚠️ Failed to refresh read-only mode: %v", err)
+			return mode
+		}
+		s.mu.RLock()
+		mode = s.mode
+		s.mu.RUnlock()
+	}
+	return mode
+}
+
+// Set - включает или выключает режим; причина обязательна при включении
+func (s *ReadOnlyService) Set(req *models.SetReadOnlyRequest, setBy string) (*models.ReadOnlyMode, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if *req.Enabled && reason == "" {
+		return nil, ErrReadOnlyReasonRequired
+	}
+
+	now := time.Now()
+	mode := &models.ReadOnlyMode{
+		Enabled:   *req.Enabled,
+		Reason:    reason,
+		SetBy:     setBy,
+		SetAt:     &now,
+		UpdatedAt: now,
+	}
+	if err := s.readOnlyRepo.Save(mode); err != nil {
+		return nil, err
+	}
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("failed to reload read-only mode: %w", err)
+	}
+
+	if mode.Enabled {
+		log.Printf("🔒 Read-only mode enabled by %s: %s", setBy, reason)
+	} else {
+		log.Printf("🔓 Read-only mode disabled by %s", setBy)
+	}
+	return mode, nil
+}
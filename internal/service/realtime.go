@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/realtime"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	realtimePublishTimeout = 5 * time.Second
+	// realtimeClientBuffer - события, ожидающие отправки клиенту; медленный клиент
+	// теряет события сверх буфера, а не задерживает остальных
+	realtimeClientBuffer = 32
+	// realtimeMaxMessage - предел сообщения между экземплярами (NOTIFY - 8000 байт)
+	realtimeMaxMessage = 7900
+)
+
+// realtimeEnvelope - сообщение между экземплярами: событие и организация его РУ,
+// по которой получатель отбирает клиентов без запроса к базе
+type realtimeEnvelope struct {
+	Event          models.RealtimeEvent `json:"event"`
+	OrganizationID string               `json:"organizationId"`
+}
+
+type realtimeClient struct {
+	actor  models.Actor
+	ruID   string
+	events chan models.RealtimeEvent
+}
+
+// RealtimeService - подписчик шины событий, рассылающий события клиентам потока
+// /stream. Событие доставляет тот экземпляр, который выбрал его из outbox, поэтому при
+// нескольких экземплярах оно проходит через мост (REALTIME_BRIDGE) и рассылается
+// каждым экземпляром своим клиентам. Без моста события получают только клиенты
+// экземпляра, обработавшего событие.
+type RealtimeService struct {
+	ruRepo *repository.RuRepository
+	bridge realtime.Bridge
+
+	mu      sync.RWMutex
+	clients map[*realtimeClient]struct{}
+}
+
+func NewRealtimeService(ruRepo *repository.RuRepository, bridge realtime.Bridge) *RealtimeService {
+	return &RealtimeService{
+		ruRepo:  ruRepo,
+		bridge:  bridge,
+		clients: make(map[*realtimeClient]struct{}),
+	}
+}
+
+// Run - слушает мост; без моста сразу завершается
+func (s *RealtimeService) Run(ctx context.Context) {
+	if s.bridge == nil {
+		return
+	}
+	s.bridge.Listen(ctx, s.dispatch)
+}
+
+// Subscribe - подписка клиента на события доступных ему РУ; ruID сужает поток до
+// одного РУ. Возвращает канал событий и функцию отписки.
+func (s *RealtimeService) Subscribe(actor models.Actor, ruID string) (<-chan models.RealtimeEvent, func(), error) {
+	if ruID != "" {
+		organizationID, err := s.ruRepo.GetRuOrganization(ruID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return nil, nil, ErrRuNotFound
+			}
+			return nil, nil, fmt.Errorf("failed to get RU organization: %w", err)
+		}
+		if !actor.CanAccessOrganization(organizationID) {
+			return nil, nil, ErrRuNotFound
+		}
+	}
+
+	client := &realtimeClient{actor: actor, ruID: ruID, events: make(chan models.RealtimeEvent, realtimeClientBuffer)}
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+
+	return client.events, func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}, nil
+}
+
+// Clients - число подключенных к экземпляру клиентов
+func (s *RealtimeService) Clients() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.clients)
+}
+
+// HandleEvent - передает событие в мост или, без моста, клиентам экземпляра. Поток -
+// лучшая попытка: ошибка моста только пишется в лог, чтобы не повторять событие для
+// остальных подписчиков шины.
+func (s *RealtimeService) HandleEvent(event *models.OutboxEvent) error {
+	envelope := realtimeEnvelope{
+		Event: models.RealtimeEvent{
+			ID:        event.ID,
+			Type:      event.Type,
+			RuID:      event.RuID,
+			Payload:   json.RawMessage(event.Payload),
+			CreatedAt: event.CreatedAt,
+		},
+	}
+	if event.RuID != "" {
+		organizationID, err := s.ruRepo.GetRuOrganization(event.RuID)
+		if err != nil && !repository.IsNotFound(err) {
+			return fmt.Errorf("failed to get RU organization: %w", err)
+		}
+		envelope.OrganizationID = organizationID
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode realtime event: %w", err)
+	}
+	if len(data) > realtimeMaxMessage {
+		envelope.Event.Payload = nil
+		if data, err = json.Marshal(envelope); err != nil {
+			return fmt.Errorf("failed to encode realtime event: %w", err)
+		}
+	}
+
+	if s.bridge == nil {
+		s.dispatch(data)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), realtimePublishTimeout)
+	defer cancel()
+	if err := s.bridge.Publish(ctx, data); err != nil {
+		log.Printf("⚠️ Realtime event %s not published: %v", event.ID, err)
+	}
+	return nil
+}
+
+// dispatch - рассылает сообщение моста клиентам экземпляра, которым доступно РУ события
+func (s *RealtimeService) dispatch(data []byte) {
+	var envelope realtimeEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		log.Printf("⚠️ Failed to decode realtime event: %v", err)
+		return
+	}
+	event := envelope.Event
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for client := range s.clients {
+		if client.ruID != "" && client.ruID != event.RuID {
+			continue
+		}
+		if !client.actor.CanAccessOrganization(envelope.OrganizationID) {
+			continue
+		}
+		select {
+		case client.events <- event:
+		default:
+			log.Printf("⚠️ Realtime client %s is too slow, event %s dropped", client.actor.Email, event.ID)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// emailVerificationTTL - срок действия ссылки подтверждения адреса
+	emailVerificationTTL = 24 * time.Hour
+	// verificationResendInterval - повторное письмо не чаще
+	verificationResendInterval = time.Minute
+	// verificationMailTimeout - время на отправку письма во время запроса
+	verificationMailTimeout = 15 * time.Second
+	// verifyEmailPath - страница веб-интерфейса, которая передает ключ в /auth/verify-email
+	verifyEmailPath = "/verify-email"
+)
+
+// inactiveAccountError - почему нельзя войти в учетную запись; nil для активной
+func inactiveAccountError(status models.UserStatus) error {
+	switch status {
+	case models.UserPendingVerification:
+		return ErrAccountNotVerified
+	case models.UserPendingApproval:
+		return ErrAccountPendingApproval
+	case models.UserRejected:
+		return ErrAccountRejected
+	default:
+		return nil
+	}
+}
+
+// sendVerification - новый ключ подтверждения и письмо со ссылкой. Ошибка отправки
+// не отменяет регистрацию: пользователь может запросить письмо повторно.
+func (s *AuthService) sendVerification(user *models.User, loc i18n.Lang) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("⚠️ Email verification for %s: %v", user.ID, err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+	verification := &models.EmailVerification{
+		TokenHash: feedTokenHash(token),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(emailVerificationTTL),
+		CreatedAt: now,
+	}
+	if err := s.userRepo.CreateVerification(verification); err != nil {
+		log.Printf("⚠️ Email verification for %s: %v", user.ID, err)
+		return
+	}
+
+	link := strings.TrimRight(s.publicURL, "/") + verifyEmailPath + "?token=" + token
+	ctx, cancel := context.WithTimeout(context.Background(), verificationMailTimeout)
+	defer cancel()
+	err := s.mailer.Send(ctx, user.Email,
+		i18n.T(loc, "auth.verify_email.subject"),
+		i18n.T(loc, "auth.verify_email.body", user.Name, link, int(emailVerificationTTL.Hours())))
+	if err != nil {
+		log.Printf("⚠️ Email verification for %s: %v", user.ID, err)
+	}
+}
+
+// VerifyEmail - подтверждение адреса по ключу из письма; регистрация переходит
+// в очередь одобрения, администраторы получают уведомление
+func (s *AuthService) VerifyEmail(req *models.VerifyEmailRequest) (*models.UserResponse, error) {
+	verification, err := s.userRepo.FindVerification(feedTokenHash(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if verification == nil || now.After(verification.ExpiresAt) {
+		return nil, ErrVerificationTokenInvalid
+	}
+	user, err := s.userRepo.FindByID(verification.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || user.Status != models.UserPendingVerification {
+		return nil, ErrVerificationTokenInvalid
+	}
+
+	user.Status = models.UserPendingApproval
+	user.EmailVerifiedAt = &now
+	user.UpdatedAt = now
+	if err := s.userRepo.VerifyEmail(user); err != nil {
+		return nil, err
+	}
+	s.notifyRegistration(user)
+
+	return &models.UserResponse{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		OrganizationID: user.OrganizationID,
+		Phone:          user.Phone,
+		Status:         user.Status,
+		CreatedAt:      user.CreatedAt,
+	}, nil
+}
+
+// ResendVerification - повторное письмо с подтверждением. Ответ не зависит от того,
+// есть ли такой адрес, чтобы по нему нельзя было проверять регистрацию.
+func (s *AuthService) ResendVerification(req *models.ResendVerificationRequest, loc i18n.Lang) error {
+	if s.mailer == nil {
+		return nil
+	}
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || user.Status != models.UserPendingVerification {
+		return nil
+	}
+	last, err := s.userRepo.LastVerificationAt(user.ID)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < verificationResendInterval {
+		return nil
+	}
+	s.sendVerification(user, loc)
+	return nil
+}
+
+// notifyRegistration - уведомление администраторам установки и администраторам
+// организации пользователя о регистрации, ожидающей одобрения
+func (s *AuthService) notifyRegistration(user *models.User) {
+	event := models.NotificationEvent{
+		Category: models.EventCategoryApproval,
+		Title:    i18n.T(i18n.Default, "notification.registration.title", user.Name),
+		Message:  i18n.T(i18n.Default, "notification.registration.message", user.Name, user.Email),
+		SourceID: "registration:" + user.ID,
+	}
+	for _, role := range []models.UserRole{models.RoleAdmin, models.RoleOrgAdmin} {
+		admins, err := s.userRepo.GetUsersByRole(string(role))
+		if err != nil {
+			log.Printf("⚠️ Registration notification for %s: %v", user.ID, err)
+			return
+		}
+		for _, admin := range admins {
+			if role == models.RoleAdmin || admin.OrganizationID == user.OrganizationID {
+				event.UserIDs = append(event.UserIDs, admin.ID)
+			}
+		}
+	}
+	if len(event.UserIDs) == 0 {
+		return
+	}
+	if err := s.notifications.Dispatch(event); err != nil {
+		log.Printf("⚠️ Registration notification for %s: %v", user.ID, err)
+	}
+}
+
+// GetPendingUsers - очередь регистраций: администратор организации видит свою организацию
+func (s *AdminService) GetPendingUsers(actor models.Actor) ([]models.PendingUser, error) {
+	organizationID := actor.OrganizationID
+	if actor.IsPlatformAdmin() {
+		organizationID = ""
+	}
+	users, err := s.userRepo.GetPending(organizationID)
+	if err != nil {
+		return nil, err
+	}
+	pending := make([]models.PendingUser, 0, len(users))
+	for _, user := range users {
+		pending = append(pending, models.PendingUser{
+			ID:              user.ID,
+			Name:            user.Name,
+			Email:           user.Email,
+			OrganizationID:  user.OrganizationID,
+			Status:          user.Status,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			CreatedAt:       user.CreatedAt,
+		})
+	}
+	return pending, nil
+}
+
+// ApproveUser - одобрение регистрации с подтвержденным адресом: учетная запись
+// становится активной с назначенной ролью
+func (s *AdminService) ApproveUser(actor models.Actor, userID string, req *models.ApproveUserRequest) (*models.UserResponse, error) {
+	user, err := s.pendingUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = string(models.RoleEngineer)
+	}
+	userRole, err := assignableRole(actor, role)
+	if err != nil {
+		return nil, err
+	}
+	if req.OrganizationID != "" && req.OrganizationID != user.OrganizationID {
+		if !actor.IsPlatformAdmin() {
+			return nil, ErrRoleNotAllowed
+		}
+		if _, err := s.orgRepo.GetByID(req.OrganizationID); err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrOrganizationNotFound
+			}
+			return nil, fmt.Errorf("failed to get organization: %w", err)
+		}
+		user.OrganizationID = req.OrganizationID
+	}
+
+	now := time.Now()
+	user.Role = userRole
+	user.Status = models.UserActive
+	user.ReviewedBy = &actor.Email
+	user.ReviewedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return &models.UserResponse{
+		ID:             user.ID,
+		Name:           user.Name,
+		Email:          user.Email,
+		Role:           string(user.Role),
+		OrganizationID: user.OrganizationID,
+		Phone:          user.Phone,
+		Status:         user.Status,
+		CreatedAt:      user.CreatedAt,
+	}, nil
+}
+
+// RejectUser - отклонение регистрации; учетная запись остается, чтобы адрес нельзя
+// было сразу зарегистрировать снова, и удаляется администратором при необходимости
+func (s *AdminService) RejectUser(actor models.Actor, userID string, req *models.RejectUserRequest) (*models.PendingUser, error) {
+	user, err := s.scopedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != models.UserPendingVerification && user.Status != models.UserPendingApproval {
+		return nil, ErrUserNotPendingApproval.WithDetails(map[string]interface{}{"status": user.Status})
+	}
+
+	now := time.Now()
+	user.Status = models.UserRejected
+	user.RejectionReason = req.Reason
+	user.ReviewedBy = &actor.Email
+	user.ReviewedAt = &now
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return &models.PendingUser{
+		ID:              user.ID,
+		Name:            user.Name,
+		Email:           user.Email,
+		OrganizationID:  user.OrganizationID,
+		Status:          user.Status,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+	}, nil
+}
+
+// pendingUser - регистрация, ожидающая одобрения, в области видимости actor
+func (s *AdminService) pendingUser(actor models.Actor, userID string) (*models.User, error) {
+	user, err := s.scopedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != models.UserPendingApproval {
+		return nil, ErrUserNotPendingApproval.WithDetails(map[string]interface{}{"status": user.Status})
+	}
+	return user, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// GetCellRevisions - история конфигурации ячейки с отличиями от предыдущей ревизии
+func (s *RuService) GetCellRevisions(ruID string, cellID int) ([]models.CellRevision, error) {
+	if _, err := s.getCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.GetByCell(cellID, ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cell revisions: %w", err)
+	}
+	for i := range revisions {
+		var prev *models.CellRevision
+		if i > 0 {
+			prev = &revisions[i-1]
+		}
+		revisions[i].BuildDiff(prev)
+	}
+	return revisions, nil
+}
+
+// RestoreCellRevision - возвращает конфигурацию ячейки к указанной ревизии.
+// Восстановление записывается новой ревизией, история не переписывается.
+func (s *RuService) RestoreCellRevision(ruID string, cellID int, revision int, actor models.Actor) (*models.Cell, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.revisionRepo.GetByRevision(cellID, ruID, revision)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellRevisionNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell revision: %w", err)
+	}
+
+	previous := models.NewCellRevision(cell)
+	if previous.SameConfig(target) {
+		return cell, nil
+	}
+
+	cell.Name = target.Name
+	cell.Description = target.Description
+	cell.Voltage = target.Voltage
+	cell.IsCritical = target.IsCritical
+	cell.UpdatedAt = time.Now()
+
+	meta := models.CellRevision{Reason: models.RevisionRestore, ChangedBy: actor.Email, RestoredFrom: &target.Revision}
+	if err := s.revisionRepo.SaveCell(cell, previous, meta); err != nil {
+		return nil, fmt.Errorf("failed to restore cell revision: %w", err)
+	}
+	return cell, nil
+}
@@ -2,25 +2,38 @@ package service
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/permissions"
 	"github.com/Temoojeen/sez-vision-backend/internal/repository"
-
-	"github.com/google/uuid"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
 )
 
 type RuService struct {
-	ruRepo *repository.RuRepository
+	ruRepo           *repository.RuRepository
+	lockRepo         *repository.CellLockRepository
+	confirmationRepo *repository.ConfirmationRepository
+	changeRepo       *repository.CellChangeRepository
+	revisionRepo     *repository.CellRevisionRepository
+	commandRepo      *repository.CommandRepository
+	documentTypes    *DocumentTypeService
+	numbering        *NumberingService
+	settings         *SettingsService
+	userRepo         *repository.UserRepository
 }
 
-func NewRuService(ruRepo *repository.RuRepository) *RuService {
-	return &RuService{ruRepo: ruRepo}
+func NewRuService(ruRepo *repository.RuRepository, lockRepo *repository.CellLockRepository, confirmationRepo *repository.ConfirmationRepository, changeRepo *repository.CellChangeRepository, revisionRepo *repository.CellRevisionRepository, commandRepo *repository.CommandRepository, documentTypes *DocumentTypeService, numbering *NumberingService, settings *SettingsService, userRepo *repository.UserRepository) *RuService {
+	return &RuService{ruRepo: ruRepo, lockRepo: lockRepo, confirmationRepo: confirmationRepo, changeRepo: changeRepo, revisionRepo: revisionRepo, commandRepo: commandRepo, documentTypes: documentTypes, numbering: numbering, settings: settings, userRepo: userRepo}
 }
 
 func (s *RuService) GetRuByID(ruID string) (*models.GetRuResponse, error) {
 	ruInfo, err := s.ruRepo.GetRuByID(ruID)
 	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
 		return nil, fmt.Errorf("failed to get RU info: %w", err)
 	}
 
@@ -28,6 +41,9 @@ func (s *RuService) GetRuByID(ruID string) (*models.GetRuResponse, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cells: %w", err)
 	}
+	if err := s.applyOperationalState(ruInfo); err != nil {
+		return nil, err
+	}
 
 	return &models.GetRuResponse{
 		RuInfo: *ruInfo,
@@ -35,133 +51,797 @@ func (s *RuService) GetRuByID(ruID string) (*models.GetRuResponse, error) {
 	}, nil
 }
 
-func (s *RuService) UpdateCellStatus(ruID string, cellID int, req *models.UpdateCellStatusRequest) (*models.Cell, error) {
-	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+// UpdateCellStatus - переключение ячейки. Критичную ячейку инженер или администратор
+// переключает сразу; запрос диспетчера создает подтверждение, которое должен принять
+// второй сотрудник (в этом случае возвращается подтверждение, а ячейка не меняется).
+func (s *RuService) UpdateCellStatus(ruID string, cellID int, req *models.UpdateCellStatusRequest, actor models.Actor) (*models.Cell, *models.StatusConfirmation, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkNotLocked(cellID); err != nil {
+		return nil, nil, err
+	}
+
+	if cell.IsCritical && !actor.IsElevated() {
+		confirmation, err := s.requestConfirmation(cell, req, actor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, confirmation, nil
+	}
+
+	if err := s.applyCellStatus(cell, req); err != nil {
+		return nil, nil, err
+	}
+	return cell, nil, nil
+}
+
+// checkNotLocked - ячейка под замком (LOTO) не переключается, пока замок не снят
+func (s *RuService) checkNotLocked(cellID int) error {
+	lock, err := s.lockRepo.GetActiveLock(cellID)
 	if err != nil {
-		return nil, fmt.Errorf("cell not found: %w", err)
+		return fmt.Errorf("failed to check cell lock: %w", err)
+	}
+	if lock != nil {
+		return ErrCellLocked.WithDetails(lock)
 	}
+	return nil
+}
+
+func (s *RuService) applyCellStatus(cell *models.Cell, req *models.UpdateCellStatusRequest) error {
+	now := time.Now()
+	legacyNow := now.Format(utils.LegacyDateTimeLayout)
+	previousStatus := cell.Status
 
+	cell.AccrueRuntime(now)
 	cell.Status = req.Status
 	if req.IsGrounded != nil {
 		cell.IsGrounded = *req.IsGrounded
-		now := time.Now().Format("02.01.2006 15:04:05")
-		cell.LastGroundedOperation = &now
+		cell.LastGroundedOperation = &legacyNow
+		cell.LastGroundedOperationAt = &now
 	}
 
-	now := time.Now().Format("02.01.2006 15:04:05")
-	cell.LastOperation = &now
-	cell.UpdatedAt = time.Now()
+	cell.LastOperation = &legacyNow
+	cell.LastOperationAt = &now
+	cell.UpdatedAt = now
+
+	if models.IsSwitchingOperation(previousStatus, cell.Status) {
+		counted, err := s.countedByCommand(cell.ID)
+		if err != nil {
+			return err
+		}
+		if !counted {
+			cell.OperationCount++
+		}
+	}
 
-	if err := s.ruRepo.UpdateCell(cell); err != nil {
-		return nil, fmt.Errorf("failed to update cell: %w", err)
+	events, err := cellStatusEvents(cell, previousStatus)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ruRepo.UpdateCell(cell, events...); err != nil {
+		return fmt.Errorf("failed to update cell: %w", err)
 	}
+	return nil
+}
 
-	return cell, nil
+// countedByCommand - операция уже учтена при исполнении команды телеуправления: смена
+// статуса - обратная связь по исполненной команде
+func (s *RuService) countedByCommand(cellID int) (bool, error) {
+	if _, err := s.commandRepo.GetAwaitingFeedback(cellID); err != nil {
+		if repository.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
-func (s *RuService) UpdateCellInfo(ruID string, cellID int, req *models.UpdateCellInfoRequest) (*models.Cell, error) {
-	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+// cellStatusEvents - события смены статуса ячейки; переход в ERROR дополнительно порождает аварию
+func cellStatusEvents(cell *models.Cell, previousStatus models.CellStatus) ([]models.OutboxEvent, error) {
+	changed, err := newEvent(models.EventCellStatusChanged, cell.RuID, models.CellStatusChangedPayload{
+		CellID:         cell.ID,
+		CellNumber:     cell.Number,
+		CellName:       cell.Name,
+		Status:         cell.Status,
+		PreviousStatus: previousStatus,
+		IsGrounded:     cell.IsGrounded,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cell not found: %w", err)
+		return nil, err
 	}
+	events := []models.OutboxEvent{changed}
 
+	if cell.Status == models.CellStatusError && previousStatus != models.CellStatusError {
+		alarm, err := newEvent(models.EventAlarmRaised, cell.RuID, models.AlarmRaisedPayload{
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+			Status:     cell.Status,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, alarm)
+	}
+
+	return events, nil
+}
+
+// UpdateCellInfo - изменение паспортных данных ячейки. Инженер и администратор
+// применяют изменение сразу; изменение диспетчера попадает в очередь на одобрение.
+func (s *RuService) UpdateCellInfo(ruID string, cellID int, req *models.UpdateCellInfoRequest, actor models.Actor) (*models.Cell, *models.CellInfoChange, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !actor.IsElevated() {
+		change, err := s.proposeCellInfoChange(cell, req, actor)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, change, nil
+	}
+
+	previous := models.NewCellRevision(cell)
 	cell.Name = req.Name
 	cell.Description = req.Description
 	cell.Voltage = req.Voltage
 	cell.UpdatedAt = time.Now()
 
-	if err := s.ruRepo.UpdateCell(cell); err != nil {
-		return nil, fmt.Errorf("failed to update cell info: %w", err)
+	meta := models.CellRevision{Reason: models.RevisionUpdate, ChangedBy: actor.Email}
+	if err := s.revisionRepo.SaveCell(cell, previous, meta); err != nil {
+		return nil, nil, fmt.Errorf("failed to update cell info: %w", err)
 	}
 
-	return cell, nil
+	return cell, nil, nil
 }
 
-func (s *RuService) GetHistoryByRuID(ruID string, limit int) ([]models.OperationRecord, error) {
-	records, err := s.ruRepo.GetHistoryByRuID(ruID, limit)
+// GetHistoryByRuID - журнал операций РУ; limit <= 0 означает значение по умолчанию из настроек.
+// Отбор по важности учитывает исправления записей.
+func (s *RuService) GetHistoryByRuID(ruID string, filter models.HistoryFilter, limit int) ([]models.OperationRecord, error) {
+	if limit <= 0 {
+		limit = s.settings.Int(SettingHistoryDefaultLimit)
+	}
+	if maxLimit := s.settings.Int(SettingHistoryMaxLimit); limit > maxLimit {
+		limit = maxLimit
+	}
+	records, err := s.ruRepo.GetHistoryByRuID(ruID, filter, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get history: %w", err)
 	}
+	if err := s.attachCorrections(records); err != nil {
+		return nil, err
+	}
 	return records, nil
 }
 
-func (s *RuService) AddHistoryRecord(ruID string, req *models.AddHistoryRecordRequest) (*models.OperationRecord, error) {
+// ExportHistory - журнал РУ за период [from, to] (даты включительно) по времени операции,
+// от старых записей к новым, с учетом исправлений
+func (s *RuService) ExportHistory(ruID string, query models.HistoryExportQuery) ([]models.OperationRecord, error) {
+	from, err := time.ParseInLocation(summaryDateLayout, query.From, time.Local)
+	if err != nil {
+		return nil, ErrHistoryExportRange
+	}
+	to, err := time.ParseInLocation(summaryDateLayout, query.To, time.Local)
+	if err != nil || to.Before(from) || !to.Before(from.AddDate(1, 0, 0)) {
+		return nil, ErrHistoryExportRange
+	}
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	records, err := s.ruRepo.GetHistoryForPeriod(ruID, query.Severity, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachCorrections(records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// AddHistoryRecord - новая запись журнала от имени actor. Время операции не может быть
+// в будущем дальше history.max_future_skew; раньше history.max_backdate запись вносит
+// только роль с правом history:backdate.
+func (s *RuService) AddHistoryRecord(ruID string, req *models.AddHistoryRecordRequest, actor models.Actor) (*models.OperationRecord, error) {
+	return s.addHistoryRecord(ruID, req, actor, s.settings.Duration(SettingHistoryMaxBackdate))
+}
+
+// addHistoryRecord - запись журнала с допуском записи задним числом maxBackdate
+func (s *RuService) addHistoryRecord(ruID string, req *models.AddHistoryRecordRequest, actor models.Actor, maxBackdate time.Duration) (*models.OperationRecord, error) {
+	perms := permissions.ForRole(string(actor.Role))
+	if !perms.Has(permissions.HistoryWrite) {
+		return nil, ErrHistoryWriteForbidden
+	}
+	now := time.Now()
+	operationTime, err := historyOperationTime(req)
+	if err != nil {
+		return nil, err
+	}
+	if operationTime != nil {
+		if maxFuture := s.settings.Duration(SettingHistoryMaxFutureSkew); operationTime.After(now.Add(maxFuture)) {
+			return nil, ErrHistoryInFuture.WithDetails(map[string]interface{}{
+				"timestamp":      operationTime,
+				"serverTime":     now,
+				"maxSkewMinutes": int(maxFuture.Minutes()),
+			})
+		}
+		if operationTime.Before(now.Add(-maxBackdate)) && !perms.Has(permissions.HistoryBackdate) {
+			return nil, ErrHistoryBackdated.WithDetails(map[string]interface{}{
+				"timestamp":          operationTime,
+				"serverTime":         now,
+				"maxBackdateMinutes": int(maxBackdate.Minutes()),
+			})
+		}
+	}
+
+	documentType, err := s.documentTypes.Resolve(req.DocumentType)
+	if err != nil {
+		return nil, err
+	}
+	responsiblePerson, err := s.responsiblePerson(ruID, req)
+	if err != nil {
+		return nil, err
+	}
+
 	record := &models.OperationRecord{
-		ID:                uuid.New().String(),
+		ID:                utils.NewID(models.IDPrefixOperation),
 		CellNumber:        req.CellNumber,
 		CellName:          req.CellName,
 		Action:            req.Action,
-		Operator:          req.Operator,
+		Operator:          actor.DisplayName(),
+		UserID:            &actor.UserID,
 		Timestamp:         req.Timestamp,
 		Reason:            req.Reason,
-		DocumentType:      req.DocumentType,
+		DocumentType:      documentType,
 		OrderNumber:       req.OrderNumber,
 		WorkOrderNumber:   req.WorkOrderNumber,
 		StartDate:         req.StartDate,
 		EndDate:           req.EndDate,
-		ResponsiblePerson: req.ResponsiblePerson,
+		ResponsiblePerson: responsiblePerson,
+		ResponsibleUserID: req.ResponsibleUserID,
 		Comment:           req.Comment,
 		Severity:          req.Severity,
+		TimestampAt:       req.TimestampAt,
+		StartDateAt:       req.StartDateAt,
+		EndDateAt:         req.EndDateAt,
 		RuID:              ruID,
-		CreatedAt:         time.Now(),
-		UpdatedAt:         time.Now(),
+		ClientTime:        req.ClientTime,
+		Device:            req.Device,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	// Расхождение часов устройства с сервером: время отправки по часам устройства
+	// против времени приема
+	if req.ClientTime != nil {
+		skew := req.ClientTime.Sub(now).Milliseconds()
+		record.ClockSkewMs = &skew
+	}
+	if err := s.numbering.NumberRecord(record); err != nil {
+		return nil, err
+	}
+
+	// Запись с номером наряда - это операция по наряду-допуску
+	var events []models.OutboxEvent
+	if record.WorkOrderNumber != nil {
+		event, err := newEvent(models.EventPermitIssued, ruID, models.PermitIssuedPayload{
+			RecordID:          record.ID,
+			WorkOrderNumber:   *record.WorkOrderNumber,
+			Action:            record.Action,
+			CellNumber:        record.CellNumber,
+			Operator:          record.Operator,
+			ResponsiblePerson: record.ResponsiblePerson,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
 	}
 
-	if err := s.ruRepo.AddHistoryRecord(record); err != nil {
+	// Упоминания коллег в комментарии попадают в их центр уведомлений
+	if record.Comment != nil {
+		if emails := mentionedEmails(*record.Comment); len(emails) > 0 {
+			event, err := newEvent(models.EventUserMentioned, ruID, models.UserMentionedPayload{
+				RecordID:   record.ID,
+				CellNumber: record.CellNumber,
+				Author:     record.Operator,
+				Comment:    *record.Comment,
+				Emails:     emails,
+			})
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, event)
+		}
+	}
+
+	if err := s.ruRepo.AddHistoryRecord(record, events...); err != nil {
 		return nil, fmt.Errorf("failed to add history record: %w", err)
 	}
 
 	return record, nil
 }
 
+// GetClockSkew - расхождение часов устройств с сервером по записям журнала за последние дни
+func (s *RuService) GetClockSkew(query models.ClockSkewQuery) (*models.ClockSkewReport, error) {
+	days := query.Days
+	if days == 0 {
+		days = 7
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	devices, err := s.ruRepo.GetClockSkew(since)
+	if err != nil {
+		return nil, err
+	}
+	alert := s.settings.Duration(SettingHistoryClockSkewAlert).Milliseconds()
+	for i := range devices {
+		avg := devices[i].AvgSkewMs
+		devices[i].Drifted = avg > alert || avg < -alert
+	}
+	return &models.ClockSkewReport{Since: since, AlertMs: alert, Devices: devices}, nil
+}
+
+// responsiblePerson - имя ответственного по наряду. Ответственный пользователь должен
+// относиться к организации РУ; без ResponsiblePerson подставляется его имя.
+func (s *RuService) responsiblePerson(ruID string, req *models.AddHistoryRecordRequest) (*string, error) {
+	if req.ResponsibleUserID == nil {
+		return req.ResponsiblePerson, nil
+	}
+	user, err := s.userRepo.FindByID(*req.ResponsibleUserID)
+	if err != nil {
+		return nil, err
+	}
+	organizationID, err := s.ruRepo.GetRuOrganization(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	if user == nil || user.OrganizationID != organizationID {
+		return nil, ErrHistoryResponsibleInvalid
+	}
+	if req.ResponsiblePerson != nil && strings.TrimSpace(*req.ResponsiblePerson) != "" {
+		return req.ResponsiblePerson, nil
+	}
+	return &user.Name, nil
+}
+
+// historyOperationTime - время операции из запроса: timestampAt или строковое timestamp;
+// nil, если время не указано (запись датируется моментом создания)
+func historyOperationTime(req *models.AddHistoryRecordRequest) (*time.Time, error) {
+	if req.TimestampAt != nil {
+		return req.TimestampAt, nil
+	}
+	if strings.TrimSpace(req.Timestamp) == "" {
+		return nil, nil
+	}
+	t, err := utils.ParseDate(req.Timestamp)
+	if err != nil {
+		return nil, ErrHistoryTimestampInvalid.WithDetails(map[string]interface{}{"timestamp": req.Timestamp})
+	}
+	return &t, nil
+}
+
 func (s *RuService) GetAllRUs() ([]models.RUInfo, error) {
 	rus, err := s.ruRepo.GetAllRUs()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all RUs: %w", err)
 	}
+	if _, err := s.applyOperationalStates(rus); err != nil {
+		return nil, err
+	}
 	return rus, nil
 }
-func (s *RuService) UpdateRuStatus(ruID string, status string) (*models.RUInfo, error) {
-	// Получаем РУ
-	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+
+// GetVisibleRUs - РУ организации пользователя; администратор установки видит все РУ
+func (s *RuService) GetVisibleRUs(actor models.Actor) ([]models.RUInfo, error) {
+	rus, err := s.visibleRUs(actor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get RU: %w", err)
+		return nil, err
+	}
+	if _, err := s.applyOperationalStates(rus); err != nil {
+		return nil, err
+	}
+	return rus, nil
+}
+
+func (s *RuService) visibleRUs(actor models.Actor) ([]models.RUInfo, error) {
+	if actor.IsPlatformAdmin() {
+		rus, err := s.ruRepo.GetAllRUs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get all RUs: %w", err)
+		}
+		return rus, nil
+	}
+	rus, err := s.ruRepo.GetRUsByOrganization(actor.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RUs: %w", err)
+	}
+	return rus, nil
+}
+
+// RuOrganization - организация РУ для проверки доступа к маршрутам /rus/:id
+func (s *RuService) RuOrganization(ruID string) (string, error) {
+	organizationID, err := s.ruRepo.GetRuOrganization(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return "", ErrRuNotFound
+		}
+		return "", fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	return organizationID, nil
+}
+
+// GetRUSummaries - список РУ; withStats добавляет агрегаты по ячейкам и авариям,
+// посчитанные для всех РУ сразу, без запроса на каждое РУ
+func (s *RuService) GetRUSummaries(actor models.Actor, withStats bool) ([]models.RUSummary, error) {
+	rus, err := s.visibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.applyOperationalStates(rus)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.RUSummary, 0, len(rus))
+	for _, ru := range rus {
+		summary := models.RUSummary{RUInfo: ru}
+		if withStats {
+			summary.Stats = stats[ru.ID]
+			if summary.Stats == nil {
+				summary.Stats = &models.RUStats{CellsByStatus: map[models.CellStatus]int{}}
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// UpdateRuStatus - ручная установка статуса РУ с причиной. Пока она действует, статус
+// не выводится из состояния ячеек и аварий.
+func (s *RuService) UpdateRuStatus(ruID string, req *models.UpdateRuStatusRequest, actor models.Actor) (*models.RUInfo, error) {
+	ruInfo, err := s.getRuWithState(ruID)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	event, err := newEvent(models.EventRuStatusChanged, ruID, models.RuStatusChangedPayload{
+		Name:           ruInfo.Name,
+		Status:         req.Status,
+		PreviousStatus: ruInfo.Status,
+		Reason:         reason,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Обновляем статус
-	ruInfo.Status = status
-	ruInfo.UpdatedAt = time.Now()
+	now := time.Now()
+	ruInfo.Status = req.Status
+	ruInfo.StatusOverride = true
+	ruInfo.StatusReason = &reason
+	ruInfo.StatusSetBy = &actor.Email
+	ruInfo.StatusSetAt = &now
+	ruInfo.UpdatedAt = now
 
-	// Нужно добавить метод UpdateRu в репозитории
-	if err := s.ruRepo.UpdateRu(ruInfo); err != nil {
+	if err := s.ruRepo.UpdateRu(ruInfo, event); err != nil {
 		return nil, fmt.Errorf("failed to update RU status: %w", err)
 	}
 
 	return ruInfo, nil
 }
 
-// UpdateRUsSubstation - обновление подстанции для списка РУ
-func (s *RuService) UpdateRUsSubstation(ruIDs []string, substationID string) ([]models.RUInfo, error) {
-	var updatedRUs []models.RUInfo
+// ClearRuStatus - снимает ручную установку: статус снова выводится из состояния РУ.
+// Событие смены статуса отправляется, только если статус при этом изменился.
+func (s *RuService) ClearRuStatus(ruID string, actor models.Actor) (*models.RUInfo, error) {
+	ruInfo, err := s.getRuWithState(ruID)
+	if err != nil {
+		return nil, err
+	}
+	if !ruInfo.StatusOverride {
+		return ruInfo, nil
+	}
+
+	previous := ruInfo.Status
+	now := time.Now()
+	ruInfo.Status = models.RuStatusNormal
+	ruInfo.StatusOverride = false
+	ruInfo.StatusReason = nil
+	ruInfo.StatusSetBy = &actor.Email
+	ruInfo.StatusSetAt = &now
+	ruInfo.UpdatedAt = now
+	computed := *ruInfo
+	if err := s.applyOperationalState(&computed); err != nil {
+		return nil, err
+	}
+
+	var events []models.OutboxEvent
+	if computed.Status != previous {
+		event, err := newEvent(models.EventRuStatusChanged, ruID, models.RuStatusChangedPayload{
+			Name:           ruInfo.Name,
+			Status:         computed.Status,
+			PreviousStatus: previous,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.ruRepo.UpdateRu(ruInfo, events...); err != nil {
+		return nil, fmt.Errorf("failed to update RU status: %w", err)
+	}
+	return &computed, nil
+}
 
+// getRuWithState - РУ с вычисленным состоянием и действующим статусом
+func (s *RuService) getRuWithState(ruID string) (*models.RUInfo, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if err := s.applyOperationalState(ruInfo); err != nil {
+		return nil, err
+	}
+	return ruInfo, nil
+}
+
+// applyOperationalState - вычисляет состояние одного РУ по его ячейкам и авариям
+func (s *RuService) applyOperationalState(ruInfo *models.RUInfo) error {
+	stats, err := s.ruRepo.GetRuStatsByIDs([]string{ruInfo.ID})
+	if err != nil {
+		return fmt.Errorf("failed to get RU stats: %w", err)
+	}
+	ruInfo.ApplyOperationalState(stats[ruInfo.ID])
+	return nil
+}
+
+// applyOperationalStates - вычисляет состояние РУ списка двумя сгруппированными
+// запросами; возвращает агрегаты, по которым оно посчитано
+func (s *RuService) applyOperationalStates(rus []models.RUInfo) (map[string]*models.RUStats, error) {
+	ruIDs := make([]string, len(rus))
+	for i := range rus {
+		ruIDs[i] = rus[i].ID
+	}
+	stats, err := s.ruRepo.GetRuStatsByIDs(ruIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RU stats: %w", err)
+	}
+	for i := range rus {
+		rus[i].ApplyOperationalState(stats[rus[i].ID])
+	}
+	return stats, nil
+}
+
+// GetSubstation - подстанция по идентификатору
+func (s *RuService) GetSubstation(substationID string) (*models.Substation, error) {
+	substation, err := s.ruRepo.GetSubstationByID(substationID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrSubstationNotFound.WithDetails(map[string]interface{}{"substationId": substationID})
+		}
+		return nil, err
+	}
+	return substation, nil
+}
+
+// GetPublicSubstation - подстанция и ее РУ для публичной страницы. Читается из реплики,
+// если она подключена: страница допускает отставание на время репликации.
+func (s *RuService) GetPublicSubstation(substationID string) (*models.Substation, []models.RUInfo, error) {
+	repo := s.ruRepo.ReadOnly()
+	substation, err := repo.GetSubstationByID(substationID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrSubstationNotFound.WithDetails(map[string]interface{}{"substationId": substationID})
+		}
+		return nil, nil, err
+	}
+
+	all, err := repo.GetAllRUs()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get all RUs: %w", err)
+	}
+	var rus []models.RUInfo
+	for _, ru := range all {
+		if ru.SubstationID == substationID {
+			rus = append(rus, ru)
+		}
+	}
+	ruIDs := make([]string, len(rus))
+	for i := range rus {
+		ruIDs[i] = rus[i].ID
+	}
+	stats, err := repo.GetRuStatsByIDs(ruIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get RU stats: %w", err)
+	}
+	for i := range rus {
+		rus[i].ApplyOperationalState(stats[rus[i].ID])
+	}
+	return substation, rus, nil
+}
+
+// GetSubstationFor - подстанция, если она относится к организации пользователя;
+// подстанция чужой организации не отличается от несуществующей
+func (s *RuService) GetSubstationFor(actor models.Actor, substationID string) (*models.Substation, error) {
+	substation, err := s.GetSubstation(substationID)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanAccessOrganization(substation.OrganizationID) {
+		return nil, ErrSubstationNotFound.WithDetails(map[string]interface{}{"substationId": substationID})
+	}
+	return substation, nil
+}
+
+// UpdateRUsSubstation - задает полный список РУ подстанции одной транзакцией: перечисленные
+// РУ переносятся на подстанцию и в ее организацию, остальные РУ подстанции отвязываются.
+// Все РУ списка должны существовать, не быть в архиве и быть доступны пользователю.
+func (s *RuService) UpdateRUsSubstation(actor models.Actor, substationID string, ruIDs []string) (*models.SubstationRUsResult, error) {
+	substation, err := s.GetSubstationFor(actor, substationID)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make(map[string]bool, len(ruIDs))
+	var assign []string
 	for _, ruID := range ruIDs {
-		// Получаем РУ
+		if listed[ruID] {
+			continue
+		}
+		listed[ruID] = true
 		ruInfo, err := s.ruRepo.GetRuByID(ruID)
+		if err != nil && !repository.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get RU %s: %w", ruID, err)
+		}
+		if err != nil || ruInfo.ArchivedAt != nil || !actor.CanAccessOrganization(ruInfo.OrganizationID) {
+			return nil, ErrRuNotFound.WithDetails(map[string]interface{}{"ruId": ruID})
+		}
+		assign = append(assign, ruID)
+	}
+
+	current, err := s.ruRepo.GetRUsBySubstationID(substation.ID)
+	if err != nil {
+		return nil, err
+	}
+	unassign := []string{}
+	for _, ruInfo := range current {
+		if !listed[ruInfo.ID] {
+			unassign = append(unassign, ruInfo.ID)
+		}
+	}
+
+	if err := s.ruRepo.SetSubstationRUs(substation, assign, unassign); err != nil {
+		return nil, err
+	}
+	rus, err := s.ruRepo.GetRUsBySubstationID(substation.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SubstationRUsResult{RUs: rus, Unassigned: unassign}, nil
+}
+
+// GetSubstationOverview - РУ подстанции с ячейками и последними операциями.
+// Вложенные данные загружаются пакетно (по одному запросу на уровень), без 1+N.
+func (s *RuService) GetSubstationOverview(substationID string, opts models.OverviewOptions) ([]models.RUOverview, error) {
+	rus, err := s.ruRepo.GetRUsBySubstationID(substationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get RUs: %w", err)
+	}
+
+	if _, err := s.applyOperationalStates(rus); err != nil {
+		return nil, err
+	}
+
+	overview := make([]models.RUOverview, len(rus))
+	index := make(map[string]*models.RUOverview, len(rus))
+	ruIDs := make([]string, len(rus))
+	for i, ru := range rus {
+		overview[i] = models.RUOverview{RUInfo: ru}
+		index[ru.ID] = &overview[i]
+		ruIDs[i] = ru.ID
+	}
+
+	if opts.IncludeCells {
+		cells, err := s.ruRepo.GetCellsByRuIDs(ruIDs)
 		if err != nil {
-			continue // Пропускаем если РУ не найдено
+			return nil, fmt.Errorf("failed to get cells: %w", err)
 		}
+		for _, cell := range cells {
+			if ru, ok := index[cell.RuID]; ok {
+				ru.Cells = append(ru.Cells, cell)
+			}
+		}
+	}
+
+	if opts.IncludeOperations {
+		records, err := s.ruRepo.GetLatestHistoryByRuIDs(ruIDs, opts.OperationsLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history: %w", err)
+		}
+		for _, record := range records {
+			if ru, ok := index[record.RuID]; ok {
+				ru.LatestOperations = append(ru.LatestOperations, record)
+			}
+		}
+	}
+
+	return overview, nil
+}
+
+// GetVisibleSubstations - подстанции организации пользователя; администратор установки
+// видит все подстанции
+func (s *RuService) GetVisibleSubstations(actor models.Actor) ([]models.Substation, error) {
+	return s.ruRepo.GetSubstations(actor.OrganizationScope())
+}
 
-		// Обновляем substationId
-		ruInfo.SubstationID = substationID
-		ruInfo.UpdatedAt = time.Now()
+// GetCellsOfRUs - ячейки нескольких РУ одним запросом
+func (s *RuService) GetCellsOfRUs(ruIDs []string) ([]models.Cell, error) {
+	cells, err := s.ruRepo.GetCellsByRuIDs(ruIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	return cells, nil
+}
 
-		// Сохраняем изменения
-		if err := s.ruRepo.UpdateRu(ruInfo); err != nil {
-			return nil, fmt.Errorf("failed to update RU %s: %w", ruID, err)
+// GetLatestOperationsOfRUs - последние limit операций каждого РУ одним запросом
+func (s *RuService) GetLatestOperationsOfRUs(ruIDs []string, limit int) ([]models.OperationRecord, error) {
+	records, err := s.ruRepo.GetLatestHistoryByRuIDs(ruIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	return records, nil
+}
+
+// GetHistoryRecord - запись журнала по идентификатору. Принимаются как новые
+// идентификаторы (op_<ULID> или голый ULID), так и UUID старых записей.
+func (s *RuService) GetHistoryRecord(ruID, recordID string) (*models.OperationRecord, error) {
+	record, err := s.ruRepo.GetHistoryRecordByID(ruID, utils.NormalizeID(models.IDPrefixOperation, recordID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRecordNotFound
 		}
+		return nil, fmt.Errorf("failed to get history record: %w", err)
+	}
+	records := []models.OperationRecord{*record}
+	if err := s.attachCorrections(records); err != nil {
+		return nil, err
+	}
+	return &records[0], nil
+}
 
-		updatedRUs = append(updatedRUs, *ruInfo)
+// SetRuLocation - координаты РУ для карты
+func (s *RuService) SetRuLocation(ruID string, req *models.SetLocationRequest) error {
+	found, err := s.ruRepo.SetRuLocation(ruID, *req.Latitude, *req.Longitude)
+	if err != nil {
+		return err
 	}
+	if !found {
+		return ErrRuNotFound
+	}
+	return nil
+}
 
-	return updatedRUs, nil
+// SetSubstationLocation - координаты подстанции для карты
+func (s *RuService) SetSubstationLocation(substationID string, req *models.SetLocationRequest) error {
+	found, err := s.ruRepo.SetSubstationLocation(substationID, *req.Latitude, *req.Longitude)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrSubstationNotFound.WithDetails(map[string]interface{}{"substationId": substationID})
+	}
+	return nil
 }
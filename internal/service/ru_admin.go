@@ -0,0 +1,352 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// UpdateRu - полная замена паспортных данных РУ. Число секций шин не может стать меньше
+// секции, на которой уже стоят ячейки; при смене подстанции РУ переходит в ее организацию.
+// Архивированное РУ не редактируется.
+func (s *RuService) UpdateRu(ruID string, req *models.UpdateRuRequest) (*models.RUInfo, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if ruInfo.ArchivedAt != nil {
+		return nil, ErrRuArchived
+	}
+
+	dates := []struct{ field, value string }{
+		{"installationDate", req.InstallationDate},
+		{"lastMaintenance", req.LastMaintenance},
+		{"nextMaintenance", req.NextMaintenance},
+		{"lastInspection", req.LastInspection},
+	}
+	for _, date := range dates {
+		if strings.TrimSpace(date.value) == "" {
+			continue
+		}
+		if _, err := utils.ParseDate(date.value); err != nil {
+			return nil, ErrRuDateInvalid.WithDetails(map[string]interface{}{"field": date.field, "value": date.value})
+		}
+	}
+
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	for _, cell := range cells {
+		if cell.BusSection != nil && *cell.BusSection > req.BusSections {
+			return nil, ErrRuBusSectionsInUse.WithDetails(map[string]interface{}{
+				"busSections": req.BusSections,
+				"cellNumber":  cell.Number,
+				"busSection":  *cell.BusSection,
+			})
+		}
+	}
+
+	if req.SubstationID != ruInfo.SubstationID {
+		substation, err := s.GetSubstation(req.SubstationID)
+		if err != nil {
+			return nil, err
+		}
+		ruInfo.SubstationID = substation.ID
+		ruInfo.OrganizationID = substation.OrganizationID
+	}
+
+	ruInfo.Name = strings.TrimSpace(req.Name)
+	ruInfo.Type = req.Type
+	ruInfo.Voltage = req.Voltage
+	ruInfo.Sections = req.Sections
+	ruInfo.CellsCount = req.CellsCount
+	ruInfo.Transformers = req.Transformers
+	ruInfo.TransformerPower = req.TransformerPower
+	ruInfo.Location = req.Location
+	ruInfo.Manufacturer = req.Manufacturer
+	ruInfo.SchemeType = req.SchemeType
+	ruInfo.TotalLoadHigh, ruInfo.TotalLoadLow = req.TotalLoadHigh, req.TotalLoadLow
+	ruInfo.TotalPowerHigh, ruInfo.TotalPowerLow = req.TotalPowerHigh, req.TotalPowerLow
+	ruInfo.HasHighSide, ruInfo.HasLowSide = req.HasHighSide, req.HasLowSide
+	ruInfo.BusSections = req.BusSections
+	ruInfo.CellsPerSection = req.CellsPerSection
+	ruInfo.Latitude, ruInfo.Longitude = req.Latitude, req.Longitude
+
+	// Типизированные даты и амперы пересчитываются из строк при сохранении
+	ruInfo.InstallationDate, ruInfo.InstallationDateAt = strings.TrimSpace(req.InstallationDate), nil
+	ruInfo.LastMaintenance, ruInfo.LastMaintenanceAt = strings.TrimSpace(req.LastMaintenance), nil
+	ruInfo.NextMaintenance, ruInfo.NextMaintenanceAt = strings.TrimSpace(req.NextMaintenance), nil
+	ruInfo.LastInspection, ruInfo.LastInspectionAt = strings.TrimSpace(req.LastInspection), nil
+	ruInfo.MaxCapacityHigh, ruInfo.MaxCapacityHighA = req.MaxCapacityHigh, nil
+	ruInfo.MaxCapacityLow, ruInfo.MaxCapacityLowA = req.MaxCapacityLow, nil
+	ruInfo.UpdatedAt = time.Now()
+
+	if err := s.ruRepo.UpdateRu(ruInfo); err != nil {
+		return nil, err
+	}
+	if err := s.applyOperationalState(ruInfo); err != nil {
+		return nil, err
+	}
+	return ruInfo, nil
+}
+
+// DeleteRu - выводит РУ из системы. РУ без журнала операций и прочих записей удаляется
+// вместе с ячейками и настройками; РУ с записями (или при archive=true) архивируется:
+// скрывается из списков, а журнал, наряды и записи остаются доступными. Действующие наряды и замки на ячейках не дают сделать ни то,
+// ни другое.
+func (s *RuService) DeleteRu(ruID string, archive bool, actor models.Actor) (*models.DeleteRuResult, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	now := time.Now()
+	deps, err := s.ruRepo.GetRuDependencies(ruID, now)
+	if err != nil {
+		return nil, err
+	}
+	if deps.OpenPermits > 0 || deps.ActiveLocks > 0 {
+		return nil, ErrRuHasActiveWork.WithDetails(map[string]interface{}{
+			"openPermits": deps.OpenPermits,
+			"activeLocks": deps.ActiveLocks,
+		})
+	}
+	result := &models.DeleteRuResult{RuID: ruID, Dependencies: *deps}
+
+	if !archive && deps.HistoryRecords == 0 && len(deps.Records) == 0 {
+		deleted, err := s.ruRepo.DeleteRu(ruID)
+		if err != nil {
+			if errors.Is(err, repository.ErrRuHasRecords) || repository.IsForeignKeyViolation(err) {
+				return nil, ErrRuHasHistory
+			}
+			return nil, err
+		}
+		if !deleted {
+			return nil, ErrRuNotFound
+		}
+		result.Outcome = models.DeleteRuDeleted
+		return result, nil
+	}
+
+	result.Outcome = models.DeleteRuArchived
+	if ruInfo.ArchivedAt != nil {
+		return result, nil
+	}
+	ruInfo.ArchivedAt = &now
+	ruInfo.ArchivedBy = &actor.Email
+	ruInfo.UpdatedAt = now
+	if err := s.ruRepo.UpdateRu(ruInfo); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RestoreRu - возвращает архивированное РУ в списки
+func (s *RuService) RestoreRu(ruID string) (*models.RUInfo, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if ruInfo.ArchivedAt == nil {
+		return nil, ErrRuNotArchived
+	}
+	ruInfo.ArchivedAt = nil
+	ruInfo.ArchivedBy = nil
+	ruInfo.UpdatedAt = time.Now()
+	if err := s.ruRepo.UpdateRu(ruInfo); err != nil {
+		return nil, err
+	}
+	if err := s.applyOperationalState(ruInfo); err != nil {
+		return nil, err
+	}
+	return ruInfo, nil
+}
+
+// GetArchivedRUs - архивированные РУ
+func (s *RuService) GetArchivedRUs() ([]models.RUInfo, error) {
+	return s.ruRepo.GetArchivedRUs()
+}
+
+// cellTypes, cellStatuses - допустимые значения полей ячейки пакета
+var (
+	cellTypes = map[models.CellType]bool{
+		models.CellTypeInput: true, models.CellTypeSR: true, models.CellTypeSV: true,
+		models.CellTypeTransformer: true, models.CellTypeReserve: true, models.CellTypeBus: true,
+		models.CellTypeLowVoltage: true, models.CellTypeOutput: true,
+		models.CellTypeProtection: true, models.CellTypeMeasurement: true,
+	}
+	cellStatuses = map[models.CellStatus]bool{
+		models.CellStatusON: true, models.CellStatusOFF: true, models.CellStatusReserve: true,
+		models.CellStatusError: true, models.CellStatusMaintenance: true,
+	}
+)
+
+// NextCellNumber - следующий свободный номер «яч.N» на стороне РУ
+func (s *RuService) NextCellNumber(ruID string, query models.NextCellNumberQuery) (*models.NextCellNumber, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if (query.VoltageLevel == models.VoltageLevelHigh && !ruInfo.HasHighSide) || (query.VoltageLevel == models.VoltageLevelLow && !ruInfo.HasLowSide) {
+		return nil, ErrVoltageSideMissing.WithDetails(map[string]interface{}{"voltageLevel": query.VoltageLevel})
+	}
+	next, err := s.ruRepo.NextCellSequence(ruID, query.VoltageLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NextCellNumber{
+		RuID:         ruID,
+		VoltageLevel: query.VoltageLevel,
+		Number:       models.CellNumberPrefix + strconv.Itoa(next),
+	}, nil
+}
+
+// CreateCells - создает пакет ячеек РУ одной транзакцией. Сначала проверяется весь пакет
+// (формат номера «яч.N», тип и статус, сторона напряжения, которая есть у РУ, секция шин
+// в пределах BusSections, повторы номеров в пакете и среди ячеек РУ); при любой ошибке не
+// создается ни одна ячейка, а в подробностях возвращаются ошибки всех строк.
+func (s *RuService) CreateCells(ruID string, inputs []models.CreateCellInput) ([]models.Cell, error) {
+	ruInfo, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if ruInfo.ArchivedAt != nil {
+		return nil, ErrRuArchived
+	}
+	if len(inputs) == 0 || len(inputs) > models.MaxCellBatch {
+		return nil, ErrCellBatchSize.WithDetails(map[string]interface{}{"max": models.MaxCellBatch})
+	}
+
+	existing, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	type cellKey struct {
+		number string
+		level  models.VoltageLevel
+	}
+	taken := make(map[cellKey]bool, len(existing))
+	for _, cell := range existing {
+		taken[cellKey{cell.Number, cell.VoltageLevel}] = true
+	}
+
+	now := time.Now()
+	rows := map[cellKey]int{}
+	rowErrors := []models.CellRowError{}
+	cells := make([]models.Cell, len(inputs))
+	for i, input := range inputs {
+		number := strings.TrimSpace(input.Number)
+		fail := func(field, code string) {
+			rowErrors = append(rowErrors, models.CellRowError{Row: i, Number: number, Field: field, Code: code})
+		}
+
+		// Пустой номер назначит репозиторий, остальные приводятся к виду «яч.N»
+		if number != "" {
+			if normalized, ok := models.NormalizeCellNumber(number); ok {
+				number = normalized
+			} else {
+				fail("number", models.CellRowFormat)
+			}
+		}
+		if !cellTypes[input.Type] {
+			fail("type", models.CellRowInvalid)
+		}
+		status := input.Status
+		if status == "" {
+			status = models.CellStatusOFF
+		}
+		if !cellStatuses[status] {
+			fail("status", models.CellRowInvalid)
+		}
+
+		level := input.VoltageLevel
+		if level == "" {
+			switch {
+			case ruInfo.HasHighSide && !ruInfo.HasLowSide:
+				level = models.VoltageLevelHigh
+			case ruInfo.HasLowSide && !ruInfo.HasHighSide:
+				level = models.VoltageLevelLow
+			}
+		}
+		switch level {
+		case "":
+			fail("voltageLevel", models.CellRowRequired)
+		case models.VoltageLevelHigh, models.VoltageLevelLow:
+			if (level == models.VoltageLevelHigh && !ruInfo.HasHighSide) || (level == models.VoltageLevelLow && !ruInfo.HasLowSide) {
+				fail("voltageLevel", models.CellRowSideMissing)
+			}
+		default:
+			fail("voltageLevel", models.CellRowInvalid)
+		}
+
+		if input.BusSection != nil && (*input.BusSection < 1 || *input.BusSection > ruInfo.BusSections) {
+			fail("busSection", models.CellRowSectionOutOfRange)
+		}
+		if input.OperationLimit != nil && *input.OperationLimit < 1 {
+			fail("operationLimit", models.CellRowInvalid)
+		}
+
+		if number != "" && level != "" {
+			key := cellKey{number, level}
+			if taken[key] {
+				fail("number", models.CellRowDuplicateExisting)
+			} else if _, ok := rows[key]; ok {
+				fail("number", models.CellRowDuplicateInBatch)
+			} else {
+				rows[key] = i
+			}
+		}
+
+		cells[i] = models.Cell{
+			Number:             number,
+			Name:               strings.TrimSpace(input.Name),
+			Type:               input.Type,
+			Status:             status,
+			Voltage:            input.Voltage,
+			VoltageLevel:       level,
+			Power:              input.Power,
+			Description:        input.Description,
+			TransformerNumber:  input.TransformerNumber,
+			BusSection:         input.BusSection,
+			IsCritical:         input.IsCritical,
+			OperationLimit:     input.OperationLimit,
+			RuID:               ruID,
+			RuntimeAccountedAt: &now,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+	}
+	if len(rowErrors) > 0 {
+		return nil, ErrCellBatchInvalid.WithDetails(map[string]interface{}{"errors": rowErrors})
+	}
+
+	if err := s.ruRepo.CreateCells(cells); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrCellBatchConflict
+		}
+		return nil, err
+	}
+	return cells, nil
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+func TestCreateCellsValidation(t *testing.T) {
+	section := func(n int) *int { return &n }
+	tests := []struct {
+		name       string
+		inputs     []models.CreateCellInput
+		wantErrors []models.CellRowError
+		wantErr    error
+		// wantNumbers - номера созданных ячеек, если пакет принят
+		wantNumbers []string
+	}{
+		{
+			name: "valid batch with normalized and assigned numbers",
+			inputs: []models.CreateCellInput{
+				{Number: "Яч. 7", Type: models.CellTypeInput},
+				{Type: models.CellTypeOutput, BusSection: section(2)},
+				{Type: models.CellTypeOutput},
+			},
+			wantNumbers: []string{"яч.7", "яч.8", "яч.9"},
+		},
+		{name: "empty batch", inputs: nil, wantErr: ErrCellBatchSize},
+		{
+			name:    "batch too large",
+			inputs:  make([]models.CreateCellInput, models.MaxCellBatch+1),
+			wantErr: ErrCellBatchSize,
+		},
+		{
+			name:    "invalid number format",
+			inputs:  []models.CreateCellInput{{Number: "В10-2", Type: models.CellTypeInput}},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 0, Number: "В10-2", Field: "number", Code: models.CellRowFormat},
+			},
+		},
+		{
+			name:    "invalid type and status",
+			inputs:  []models.CreateCellInput{{Number: "5", Type: "PUMP", Status: "BROKEN"}},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 0, Number: "яч.5", Field: "type", Code: models.CellRowInvalid},
+				{Row: 0, Number: "яч.5", Field: "status", Code: models.CellRowInvalid},
+			},
+		},
+		{
+			name:    "missing and invalid voltage side",
+			inputs:  []models.CreateCellInput{{Number: "5", Type: models.CellTypeInput, VoltageLevel: models.VoltageLevelLow}, {Number: "6", Type: models.CellTypeInput, VoltageLevel: "MID"}},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 0, Number: "яч.5", Field: "voltageLevel", Code: models.CellRowSideMissing},
+				{Row: 1, Number: "яч.6", Field: "voltageLevel", Code: models.CellRowInvalid},
+			},
+		},
+		{
+			name:    "bus section and operation limit out of range",
+			inputs:  []models.CreateCellInput{{Number: "5", Type: models.CellTypeInput, BusSection: section(3), OperationLimit: section(0)}},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 0, Number: "яч.5", Field: "busSection", Code: models.CellRowSectionOutOfRange},
+				{Row: 0, Number: "яч.5", Field: "operationLimit", Code: models.CellRowInvalid},
+			},
+		},
+		{
+			name: "duplicates in batch and among existing cells",
+			inputs: []models.CreateCellInput{
+				{Number: "1", Type: models.CellTypeInput},
+				{Number: "яч.5", Type: models.CellTypeInput},
+				{Number: "5", Type: models.CellTypeInput},
+			},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 0, Number: "яч.1", Field: "number", Code: models.CellRowDuplicateExisting},
+				{Row: 2, Number: "яч.5", Field: "number", Code: models.CellRowDuplicateInBatch},
+			},
+		},
+		{
+			// Одна ошибочная строка отменяет весь пакет
+			name:    "valid rows are not created with an invalid one",
+			inputs:  []models.CreateCellInput{{Number: "5", Type: models.CellTypeInput}, {Number: "6", Type: "PUMP"}},
+			wantErr: ErrCellBatchInvalid,
+			wantErrors: []models.CellRowError{
+				{Row: 1, Number: "яч.6", Field: "type", Code: models.CellRowInvalid},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.RUInfo{}, &models.Cell{})
+			ru := models.RUInfo{ID: "ru-1", Name: "RU 1", HasHighSide: true, BusSections: 2}
+			if err := db.Create(&ru).Error; err != nil {
+				t.Fatalf("seed RU: %v", err)
+			}
+			if err := db.Create(&models.Cell{RuID: "ru-1", Number: "яч.1", VoltageLevel: models.VoltageLevelHigh, Type: models.CellTypeInput}).Error; err != nil {
+				t.Fatalf("seed cell: %v", err)
+			}
+			service := &RuService{ruRepo: repository.NewRuRepository(db)}
+
+			cells, err := service.CreateCells("ru-1", tt.inputs)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateCells error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErrors != nil {
+				var appErr *apperrors.Error
+				if !errors.As(err, &appErr) {
+					t.Fatalf("error %v has no details", err)
+				}
+				got := appErr.Details.(map[string]interface{})["errors"]
+				if !reflect.DeepEqual(got, tt.wantErrors) {
+					t.Errorf("row errors = %+v, want %+v", got, tt.wantErrors)
+				}
+			}
+
+			var count int64
+			db.Model(&models.Cell{}).Count(&count)
+			if want := int64(1 + len(tt.wantNumbers)); count != want {
+				t.Errorf("cells in RU = %d, want %d", count, want)
+			}
+			numbers := make([]string, len(cells))
+			for i, cell := range cells {
+				numbers[i] = cell.Number
+			}
+			if len(tt.wantNumbers) > 0 && !reflect.DeepEqual(numbers, tt.wantNumbers) {
+				t.Errorf("numbers = %v, want %v", numbers, tt.wantNumbers)
+			}
+		})
+	}
+}
+
+func TestDeleteRu(t *testing.T) {
+	cellID := 1
+	tests := []struct {
+		name string
+		// record - запись РУ, с которой оно только архивируется
+		record      interface{}
+		wantOutcome models.DeleteRuOutcome
+		wantRecords map[string]int
+	}{
+		{name: "RU with configuration only is deleted", wantOutcome: models.DeleteRuDeleted},
+		{
+			name:        "RU with an alarm is archived",
+			record:      &models.Alarm{ID: "alm-1", RuID: "ru-1", CellID: &cellID, EventID: "evt-1"},
+			wantOutcome: models.DeleteRuArchived,
+			wantRecords: map[string]int{"alarms": 1},
+		},
+		{
+			name:        "RU with cell readings is archived",
+			record:      &models.VoltageMeasurement{CellID: cellID, RuID: "ru-1", MeasuredAt: time.Now()},
+			wantOutcome: models.DeleteRuArchived,
+			wantRecords: map[string]int{"voltage_measurements": 1},
+		},
+		{
+			// Снимок ячейки ссылается на нее строковым owner_id
+			name:        "RU with a cell photo is archived",
+			record:      &models.Photo{ID: "pht-1", OwnerType: models.PhotoOwnerCell, OwnerID: "1"},
+			wantOutcome: models.DeleteRuArchived,
+			wantRecords: map[string]int{"photos": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t, &models.RUInfo{}, &models.Cell{}, &models.OperationRecord{}, &models.CellLock{},
+				&models.Alarm{}, &models.AssetEvent{}, &models.Asset{}, &models.CalendarEntry{}, &models.CellInfoChange{},
+				&models.ComtradeRecord{}, &models.ControlCommand{}, &models.Defect{}, &models.FaultEvent{},
+				&models.ForecastRun{}, &models.Inspection{}, &models.InspectionResult{}, &models.IssuedDocumentNumber{},
+				&models.MeasurementRollup{}, &models.Measurement{}, &models.MeterReading{}, &models.Notification{},
+				&models.PlannedOutage{}, &models.OutageCell{}, &models.Reservation{}, &models.SmsMessage{},
+				&models.StatusConfirmation{}, &models.SwitchingOrder{}, &models.SwitchingStep{}, &models.ThermalSnapshot{},
+				&models.TapChange{}, &models.VisionIndication{}, &models.VisionReading{}, &models.VoltageMeasurement{},
+				&models.Photo{}, &models.CellBaseline{}, &models.CellRevision{},
+				&models.Section{}, &models.ConsumerFeeder{}, &models.Device{}, &models.DeviceCell{},
+				&models.NotificationRule{}, &models.PollingPause{}, &models.SectionUtilization{},
+				&models.Subscription{}, &models.Transformer{})
+			seed := []interface{}{
+				&models.RUInfo{ID: "ru-1", Name: "RU 1", HasHighSide: true},
+				&models.Cell{ID: cellID, RuID: "ru-1", Number: "яч.1", VoltageLevel: models.VoltageLevelHigh, Type: models.CellTypeInput},
+				&models.Section{RuID: "ru-1", VoltageLevel: models.VoltageLevelHigh, Number: 1},
+				&models.Transformer{ID: "tr-1", RuID: "ru-1", Name: "T1", TapCellID: &cellID},
+				&models.Device{ID: "dev-1", RuID: "ru-1", Name: "RTU"},
+				&models.DeviceCell{DeviceID: "dev-1", CellID: cellID},
+				&models.Subscription{ID: "sub-1", UserID: "usr-1", RuID: "ru-1", CellID: cellID},
+				&models.NotificationRule{ID: "rule-1", RuID: "ru-1"},
+			}
+			if tt.record != nil {
+				seed = append(seed, tt.record)
+			}
+			for _, row := range seed {
+				if err := db.Create(row).Error; err != nil {
+					t.Fatalf("seed %T: %v", row, err)
+				}
+			}
+			service := &RuService{ruRepo: repository.NewRuRepository(db)}
+
+			result, err := service.DeleteRu("ru-1", false, models.Actor{Email: "admin@example.com"})
+			if err != nil {
+				t.Fatalf("DeleteRu: %v", err)
+			}
+			if result.Outcome != tt.wantOutcome {
+				t.Errorf("outcome = %s, want %s", result.Outcome, tt.wantOutcome)
+			}
+			if records := result.Dependencies.Records; len(records)+len(tt.wantRecords) > 0 && !reflect.DeepEqual(records, tt.wantRecords) {
+				t.Errorf("records = %v, want %v", result.Dependencies.Records, tt.wantRecords)
+			}
+
+			// Удаленное РУ не оставляет строк, ссылающихся на него или его ячейки
+			want := int64(1)
+			if tt.wantOutcome == models.DeleteRuDeleted {
+				want = 0
+			}
+			for _, model := range []interface{}{&models.RUInfo{}, &models.Cell{}, &models.Section{}, &models.Transformer{},
+				&models.Device{}, &models.DeviceCell{}, &models.Subscription{}, &models.NotificationRule{}} {
+				var count int64
+				db.Model(model).Count(&count)
+				if count != want {
+					t.Errorf("%T rows = %d, want %d", model, count, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// RuntimeService - наработка оборудования для обслуживания по часам. Наработка ячейки
+// накапливается во включенном состоянии: при смене статуса и задачей планировщика
+// runtime-hours. Наработка трансформатора - наработка его ячейки стороны ВН (без нее - НН).
+// Часы РУ растут, пока РУ под напряжением (включен хотя бы один ввод, а без вводов -
+// хотя бы одна ячейка), с точностью до периода задачи.
+type RuntimeService struct {
+	runtimeRepo     *repository.RuntimeRepository
+	ruRepo          *repository.RuRepository
+	transformerRepo *repository.TransformerRepository
+}
+
+func NewRuntimeService(runtimeRepo *repository.RuntimeRepository, ruRepo *repository.RuRepository, transformerRepo *repository.TransformerRepository) *RuntimeService {
+	return &RuntimeService{runtimeRepo: runtimeRepo, ruRepo: ruRepo, transformerRepo: transformerRepo}
+}
+
+// Accrue - накапливает наработку включенных ячеек и РУ под напряжением до момента now
+func (s *RuntimeService) Accrue(ctx context.Context, now time.Time) error {
+	cells, err := s.runtimeRepo.GetRunningCells()
+	if err != nil {
+		return err
+	}
+	for i := range cells {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		cell := &cells[i]
+		accountedAt := cell.RuntimeAccountedAt
+		cell.AccrueRuntime(now)
+		if _, err := s.runtimeRepo.SaveCellRuntime(cell, accountedAt); err != nil {
+			return err
+		}
+	}
+
+	rus, err := s.ruRepo.GetAllRUs()
+	if err != nil {
+		return err
+	}
+	ids := make([]string, len(rus))
+	for i, ru := range rus {
+		ids[i] = ru.ID
+	}
+	allCells, err := s.ruRepo.GetCellsByRuIDs(ids)
+	if err != nil {
+		return fmt.Errorf("failed to get cells: %w", err)
+	}
+	cellsByRu := map[string][]models.Cell{}
+	for _, cell := range allCells {
+		cellsByRu[cell.RuID] = append(cellsByRu[cell.RuID], cell)
+	}
+
+	for i := range rus {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		ru := &rus[i]
+		if ruEnergized(cellsByRu[ru.ID]) && ru.RuntimeAccountedAt != nil && now.After(*ru.RuntimeAccountedAt) {
+			ru.OperationalHours += now.Sub(*ru.RuntimeAccountedAt).Hours()
+		}
+		ru.RuntimeAccountedAt = &now
+		if err := s.runtimeRepo.SaveRuRuntime(ru); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRuntime - наработка РУ, его трансформаторов и ячеек на текущий момент, по убыванию
+// наработки; minHours отбирает оборудование, подошедшее к обслуживанию по часам
+func (s *RuntimeService) GetRuntime(ruID string, query models.RuntimeQuery) (*models.RuntimeReport, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	transformers, err := s.transformerRepo.GetByRuID(ruID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &models.RuntimeReport{
+		RuID:             ru.ID,
+		OperationalHours: ru.OperationalHours,
+		Energized:        ruEnergized(cells),
+		Transformers:     []models.TransformerRuntime{},
+		Cells:            []models.CellRuntime{},
+		At:               now,
+	}
+	if report.Energized && ru.RuntimeAccountedAt != nil && now.After(*ru.RuntimeAccountedAt) {
+		report.OperationalHours += now.Sub(*ru.RuntimeAccountedAt).Hours()
+	}
+	report.OperationalHours = round1(report.OperationalHours)
+	minHours := 0.0
+	if query.MinHours != nil {
+		minHours = *query.MinHours
+	}
+
+	cellsByID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		cellsByID[cell.ID] = cell
+		hours := round1(cell.LiveRuntimeHours(now))
+		if hours < minHours {
+			continue
+		}
+		report.Cells = append(report.Cells, models.CellRuntime{
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+			Type:       cell.Type,
+			Status:     cell.Status,
+			Hours:      hours,
+			Running:    cell.Status == models.CellStatusON,
+		})
+	}
+	for _, transformer := range transformers {
+		cellID := transformer.HighCellID
+		if cellID == nil {
+			cellID = transformer.LowCellID
+		}
+		item := models.TransformerRuntime{TransformerID: transformer.ID, Name: transformer.Name, CellID: cellID}
+		if cellID != nil {
+			if cell, ok := cellsByID[*cellID]; ok {
+				item.Hours = round1(cell.LiveRuntimeHours(now))
+				item.Running = cell.Status == models.CellStatusON
+			}
+		}
+		if item.Hours < minHours {
+			continue
+		}
+		report.Transformers = append(report.Transformers, item)
+	}
+
+	sort.SliceStable(report.Cells, func(i, j int) bool { return report.Cells[i].Hours > report.Cells[j].Hours })
+	sort.SliceStable(report.Transformers, func(i, j int) bool {
+		return report.Transformers[i].Hours > report.Transformers[j].Hours
+	})
+	return report, nil
+}
+
+// ruEnergized - РУ под напряжением: включен хотя бы один ввод, а без вводов - хотя бы
+// одна ячейка
+func ruEnergized(cells []models.Cell) bool {
+	hasInputs, anyOn := false, false
+	for _, cell := range cells {
+		on := cell.Status == models.CellStatusON
+		if cell.Type == models.CellTypeInput {
+			if on {
+				return true
+			}
+			hasInputs = true
+		}
+		anyOn = anyOn || on
+	}
+	return !hasInputs && anyOn
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// schedulerIdleInterval - как часто планировщик просыпается, если ближайший запуск далеко
+const schedulerIdleInterval = time.Minute
+
+// jobLockTTL - срок блокировки задачи между экземплярами; пока задача выполняется,
+// блокировка продлевается каждую треть срока. Столько же ждет другой экземпляр,
+// прежде чем продолжить непрерывный цикл упавшего.
+const jobLockTTL = 30 * time.Second
+
+// leaseLockPrefix - блокировки непрерывных циклов отличаются от блокировок задач
+const leaseLockPrefix = "lease:"
+
+// JobLocker - блокировки задач между экземплярами API (repository.JobLockRepository)
+type JobLocker interface {
+	TryLock(name, holder string, slot *time.Time, ttl time.Duration) (bool, error)
+	Unlock(name, holder string) error
+}
+
+// JobFunc - тело фоновой задачи; ctx отменяется при остановке сервера
+type JobFunc func(ctx context.Context) error
+
+type scheduledJob struct {
+	state    models.ScheduledJob
+	schedule *utils.CronSchedule
+	run      JobFunc
+}
+
+// Scheduler - встроенный планировщик периодических задач (ТО, хранение данных,
+// отчеты). Одна задача не запускается повторно, пока не завершился предыдущий запуск.
+// С блокировками (UseLocker) каждый запуск по расписанию выполняет ровно один
+// экземпляр API, а остальные его пропускают.
+type Scheduler struct {
+	mu     sync.Mutex
+	jobs   map[string]*scheduledJob
+	wake   chan struct{}
+	ctx    context.Context
+	active sync.WaitGroup
+
+	locker   JobLocker
+	instance string
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		jobs: make(map[string]*scheduledJob),
+		wake: make(chan struct{}, 1),
+		ctx:  context.Background(),
+	}
+}
+
+// UseLocker - включает блокировки между экземплярами; instance - имя этого экземпляра.
+// Вызывается до Run.
+func (s *Scheduler) UseLocker(locker JobLocker, instance string) {
+	s.locker = locker
+	s.instance = instance
+}
+
+// Register - добавляет задачу с расписанием cron. Пустое расписание отключает
+// автоматический запуск; задачу по-прежнему можно запустить вручную.
+func (s *Scheduler) Register(name, description, spec string, run JobFunc) error {
+	job := &scheduledJob{
+		state: models.ScheduledJob{
+			Name:        name,
+			Description: description,
+			Schedule:    spec,
+			Enabled:     spec != "",
+			Status:      models.JobRunNever,
+		},
+		run: run,
+	}
+	if spec != "" {
+		schedule, err := utils.ParseCron(spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", name, err)
+		}
+		job.schedule = schedule
+		job.state.NextRunAt = nextRun(schedule, time.Now())
+	}
+
+	s.mu.Lock()
+	s.jobs[name] = job
+	s.mu.Unlock()
+
+	s.notify()
+	return nil
+}
+
+// Run - цикл планировщика до отмены ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.active.Wait()
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		next := now.Add(schedulerIdleInterval)
+
+		s.mu.Lock()
+		for _, job := range s.jobs {
+			if job.state.NextRunAt == nil {
+				continue
+			}
+			if !job.state.NextRunAt.After(now) {
+				slot := *job.state.NextRunAt
+				s.startLocked(job, now, &slot)
+				job.state.NextRunAt = nextRun(job.schedule, now)
+			}
+			if job.state.NextRunAt != nil && job.state.NextRunAt.Before(next) {
+				next = *job.state.NextRunAt
+			}
+		}
+		s.mu.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(time.Until(next))
+	}
+}
+
+// Trigger - внеплановый запуск задачи
+func (s *Scheduler) Trigger(name string) (*models.ScheduledJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if job.state.Status == models.JobRunRunning {
+		return nil, ErrJobRunning
+	}
+
+	s.startLocked(job, time.Now(), nil)
+	state := job.state
+	return &state, nil
+}
+
+// GetJobs - задачи и результаты последних запусков
+func (s *Scheduler) GetJobs() []models.ScheduledJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]models.ScheduledJob, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job.state)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// startLocked - запускает задачу в отдельной горутине; вызывается под s.mu.
+// Пропускает запуск, если предыдущий еще выполняется. slot - время запуска по
+// расписанию, nil - внеплановый запуск.
+func (s *Scheduler) startLocked(job *scheduledJob, now time.Time, slot *time.Time) {
+	if job.state.Status == models.JobRunRunning {
+		log.Printf("⏭️ Job %s is still running, skipping", job.state.Name)
+		return
+	}
+
+	previous := job.state
+	job.state.Status = models.JobRunRunning
+	job.state.LastStartedAt = &now
+	ctx := s.ctx
+	name := job.state.Name
+
+	s.active.Add(1)
+	go func() {
+		defer s.active.Done()
+		release, err := s.claim(name, slot)
+		if release == nil {
+			if err != nil {
+				log.Printf("⚠️ Job %s not started: %v", name, err)
+			} else {
+				log.Printf("⏭️ Job %s is running or already ran on another instance, skipping", name)
+			}
+			s.mu.Lock()
+			job.state.Status = previous.Status
+			job.state.LastStartedAt = previous.LastStartedAt
+			job.state.LastSkippedAt = &now
+			s.mu.Unlock()
+			return
+		}
+		defer release()
+
+		err = s.execute(ctx, job)
+
+		finished := time.Now()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		job.state.Runs++
+		job.state.LastFinishedAt = &finished
+		job.state.LastDurationMs = finished.Sub(now).Milliseconds()
+		if err != nil {
+			message := err.Error()
+			job.state.Status = models.JobRunFailed
+			job.state.LastError = &message
+			job.state.Failures++
+			log.Printf("⚠️ Job %s failed: %v", job.state.Name, err)
+			return
+		}
+		job.state.Status = models.JobRunSucceeded
+		job.state.LastError = nil
+	}()
+}
+
+// claim - берет блокировку задачи между экземплярами и продлевает ее, пока задача
+// выполняется. Возвращает функцию снятия блокировки; nil - запуск взял другой экземпляр
+// или блокировку получить не удалось.
+func (s *Scheduler) claim(name string, slot *time.Time) (func(), error) {
+	if s.locker == nil {
+		return func() {}, nil
+	}
+	claimed, err := s.locker.TryLock(name, s.instance, slot, jobLockTTL)
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(jobLockTTL / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if held, err := s.locker.TryLock(name, s.instance, nil, jobLockTTL); err != nil || !held {
+					log.Printf("⚠️ Job %s lock not renewed (held: %v): %v", name, held, err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+		if err := s.locker.Unlock(name, s.instance); err != nil {
+			log.Printf("⚠️ Job %s: %v", name, err)
+		}
+	}, nil
+}
+
+// RunExclusive - непрерывный фоновый цикл, который должен идти на одном экземпляре:
+// run выполняется, пока экземпляр удерживает аренду name, и останавливается при ее
+// потере; другой экземпляр подхватывает цикл после истечения аренды. Без блокировок
+// (UseLocker) run просто выполняется до отмены ctx.
+func (s *Scheduler) RunExclusive(ctx context.Context, name string, run func(ctx context.Context)) {
+	if s.locker == nil {
+		run(ctx)
+		return
+	}
+
+	lease := leaseLockPrefix + name
+	ticker := time.NewTicker(jobLockTTL / 3)
+	defer ticker.Stop()
+
+	var cancel context.CancelFunc
+	var done chan struct{}
+	stop := func() {
+		if cancel != nil {
+			cancel()
+			<-done
+			cancel = nil
+		}
+	}
+
+	for {
+		held, err := s.locker.TryLock(lease, s.instance, nil, jobLockTTL)
+		if err != nil {
+			// Без продления аренду через jobLockTTL возьмет другой экземпляр
+			log.Printf("⚠️ Lease %s: %v", name, err)
+			held = false
+		}
+		switch {
+		case held && cancel == nil:
+			log.Printf("👑 %s runs on this instance (%s)", name, s.instance)
+			var runCtx context.Context
+			runCtx, cancel = context.WithCancel(ctx)
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				run(runCtx)
+			}()
+		case !held && cancel != nil:
+			log.Printf("⚠️ %s lease lost, stopping on this instance", name)
+			stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			if cancel != nil {
+				stop()
+				if err := s.locker.Unlock(lease, s.instance); err != nil {
+					log.Printf("⚠️ Lease %s: %v", name, err)
+				}
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// execute - выполняет задачу, превращая панику в ошибку, чтобы не уронить сервер
+func (s *Scheduler) execute(ctx context.Context, job *scheduledJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.run(ctx)
+}
+
+// nextRun - следующий запуск по расписанию; nil, если расписание больше не срабатывает
+func nextRun(schedule *utils.CronSchedule, now time.Time) *time.Time {
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
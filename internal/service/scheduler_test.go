@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryLocker - блокировки в памяти, общие для нескольких планировщиков
+type memoryLocker struct {
+	mu       sync.Mutex
+	holders  map[string]string
+	unlocked []string
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{holders: map[string]string{}}
+}
+
+func (l *memoryLocker) TryLock(name, holder string, slot *time.Time, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if current, ok := l.holders[name]; ok && current != holder {
+		return false, nil
+	}
+	l.holders[name] = holder
+	return true, nil
+}
+
+func (l *memoryLocker) Unlock(name, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holders[name] == holder {
+		delete(l.holders, name)
+		l.unlocked = append(l.unlocked, name+"@"+holder)
+	}
+	return nil
+}
+
+func TestSchedulerClaim(t *testing.T) {
+	locker := newMemoryLocker()
+	a, b := NewScheduler(), NewScheduler()
+	a.UseLocker(locker, "a")
+	b.UseLocker(locker, "b")
+
+	tests := []struct {
+		name      string
+		scheduler *Scheduler
+		wantClaim bool
+	}{
+		{name: "first instance claims", scheduler: a, wantClaim: true},
+		{name: "second instance skips", scheduler: b, wantClaim: false},
+		{name: "holder claims again", scheduler: a, wantClaim: true},
+	}
+	var releases []func()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, err := tt.scheduler.claim("job", nil)
+			if err != nil {
+				t.Fatalf("claim: %v", err)
+			}
+			if (release != nil) != tt.wantClaim {
+				t.Fatalf("claimed = %v, want %v", release != nil, tt.wantClaim)
+			}
+			if release != nil {
+				releases = append(releases, release)
+			}
+		})
+	}
+
+	releases[0]()
+	if release, _ := b.claim("job", nil); release == nil {
+		t.Fatal("lock is not free after release")
+	} else {
+		release()
+	}
+
+	// Без блокировок задача выполняется всегда
+	if release, err := NewScheduler().claim("job", nil); release == nil || err != nil {
+		t.Fatalf("claim without locker = %v, %v", release != nil, err)
+	}
+}
+
+func TestRunExclusive(t *testing.T) {
+	locker := newMemoryLocker()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var running, started atomic.Int32
+	loop := func(ctx context.Context) {
+		started.Add(1)
+		running.Add(1)
+		<-ctx.Done()
+		running.Add(-1)
+	}
+
+	var wg sync.WaitGroup
+	for _, instance := range []string{"a", "b", "c"} {
+		scheduler := NewScheduler()
+		scheduler.UseLocker(locker, instance)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduler.RunExclusive(ctx, "loop", loop)
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for started.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Даем остальным экземплярам попытаться взять аренду
+	time.Sleep(50 * time.Millisecond)
+	if got := started.Load(); got != 1 {
+		t.Fatalf("loop started on %d instances, want 1", got)
+	}
+
+	cancel()
+	wg.Wait()
+	if got := running.Load(); got != 0 {
+		t.Errorf("loop still running on %d instances after stop", got)
+	}
+	if len(locker.unlocked) != 1 || locker.unlocked[0][:len(leaseLockPrefix)] != leaseLockPrefix {
+		t.Errorf("unlocked = %v, want one released lease", locker.unlocked)
+	}
+
+	// Без блокировок цикл просто выполняется до отмены ctx
+	plainCtx, plainCancel := context.WithCancel(context.Background())
+	plainCancel()
+	started.Store(0)
+	NewScheduler().RunExclusive(plainCtx, "loop", loop)
+	if started.Load() != 1 {
+		t.Error("loop without locker did not run")
+	}
+}
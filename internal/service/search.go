@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	searchMaxTerms     = 10
+	searchDefaultLimit = 20
+	searchMaxLimit     = 100
+)
+
+// kazakhFold - казахские буквы, приводимые к русским (как в индексе)
+var kazakhFold = strings.NewReplacer("ә", "а", "ғ", "г", "қ", "к", "ң", "н", "ө", "о", "ұ", "у", "ү", "у", "һ", "х", "і", "и")
+
+// kazakhLatin - казахская латиница (алфавит 2021 г.) в кириллицу; диграфы - первыми
+var kazakhLatin = strings.NewReplacer(
+	"sh", "ш", "ch", "ч", "zh", "ж", "ts", "ц", "ya", "я", "yu", "ю",
+	"ä", "ә", "ğ", "ғ", "ı", "ы", "ñ", "ң", "ö", "ө", "ş", "ш", "ū", "ұ", "ü", "ү", "ç", "ч",
+	"a", "а", "b", "б", "c", "ц", "d", "д", "e", "е", "f", "ф", "g", "г", "h", "х", "i", "і",
+	"j", "ж", "k", "к", "l", "л", "m", "м", "n", "н", "o", "о", "p", "п", "q", "қ", "r", "р",
+	"s", "с", "t", "т", "u", "у", "v", "в", "w", "у", "x", "кс", "y", "й", "z", "з",
+)
+
+type SearchService struct {
+	searchRepo  *repository.SearchRepository
+	kazakhLatin bool
+}
+
+// NewSearchService - kazakhLatin включает поиск латиницей по кириллическим текстам
+func NewSearchService(searchRepo *repository.SearchRepository, kazakhLatin bool) *SearchService {
+	return &SearchService{searchRepo: searchRepo, kazakhLatin: kazakhLatin}
+}
+
+// Search - полнотекстовый поиск по ячейкам, журналу операций и РУ.
+// Каждое слово ищется по префиксу основы, поэтому "заземлен" находит "заземление".
+// Ищется только в РУ организации пользователя.
+func (s *SearchService) Search(actor models.Actor, q string, types []string, ruID string, limit int) ([]models.SearchResult, error) {
+	tsquery := s.buildQuery(q)
+	if tsquery == "" {
+		return nil, ErrSearchQueryInvalid
+	}
+
+	if limit <= 0 {
+		limit = searchDefaultLimit
+	}
+	if limit > searchMaxLimit {
+		limit = searchMaxLimit
+	}
+
+	typeSet := make(map[models.SearchResultType]bool, len(types))
+	for _, t := range types {
+		if t = strings.TrimSpace(t); t != "" {
+			typeSet[models.SearchResultType(t)] = true
+		}
+	}
+
+	results, err := s.searchRepo.Search(tsquery, typeSet, ruID, actor.OrganizationScope(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	return results, nil
+}
+
+// buildQuery - строка для to_tsquery: слова из букв и цифр с префиксным поиском, через AND.
+// Слова, набранные латиницей, дополнительно ищутся в кириллической транслитерации.
+func (s *SearchService) buildQuery(q string) string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if len(terms) == searchMaxTerms {
+			break
+		}
+		word = kazakhFold.Replace(word)
+		term := word + ":*"
+		if s.kazakhLatin && isLatin(word) {
+			if cyrillic := kazakhFold.Replace(kazakhLatin.Replace(word)); cyrillic != word {
+				term = "(" + term + " | " + cyrillic + ":*)"
+			}
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " & ")
+}
+
+func isLatin(word string) bool {
+	hasLetter := false
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			if !unicode.In(r, unicode.Latin) {
+				return false
+			}
+			hasLetter = true
+		}
+	}
+	return hasLetter
+}
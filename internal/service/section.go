@@ -0,0 +1,308 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// switchingOrdersLimit - предел бланков переключений РУ в списке
+const switchingOrdersLimit = 100
+
+// sectionalCellTypes - секционный выключатель и разъединитель: связь с соседней секцией
+var sectionalCellTypes = map[models.CellType]bool{
+	models.CellTypeSV: true,
+	models.CellTypeSR: true,
+}
+
+// SectionService - секции шин РУ: состояние и нагрузка по ячейкам секции и проекты
+// бланков переключений для снятия и подачи напряжения на секцию целиком
+type SectionService struct {
+	sectionRepo  *repository.SectionRepository
+	ruRepo       *repository.RuRepository
+	lockRepo     *repository.CellLockRepository
+	capacityRepo *repository.CapacityRepository
+}
+
+func NewSectionService(sectionRepo *repository.SectionRepository, ruRepo *repository.RuRepository, lockRepo *repository.CellLockRepository, capacityRepo *repository.CapacityRepository) *SectionService {
+	return &SectionService{sectionRepo: sectionRepo, ruRepo: ruRepo, lockRepo: lockRepo, capacityRepo: capacityRepo}
+}
+
+// GetSections - секции РУ с состоянием и нагрузкой
+func (s *SectionService) GetSections(ruID string) ([]models.Section, error) {
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	sections, err := s.sectionRepo.GetByRuID(ruID)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	utilizations, err := s.capacityRepo.GetUtilizations([]string{ruID})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := map[sectionKey][]models.Cell{}
+	for _, cell := range cells {
+		if cell.BusSection != nil {
+			key := sectionKey{cell.RuID, cell.VoltageLevel, *cell.BusSection}
+			byKey[key] = append(byKey[key], cell)
+		}
+	}
+	for i := range sections {
+		section := &sections[i]
+		describeSection(section, byKey[sectionKey{section.RuID, section.VoltageLevel, section.Number}])
+		section.Utilization = findUtilization(utilizations, section)
+	}
+	return sections, nil
+}
+
+// GetSection - секция с ячейками
+func (s *SectionService) GetSection(ruID string, sectionID int) (*models.Section, error) {
+	section, cells, err := s.getSection(ruID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	utilizations, err := s.capacityRepo.GetUtilizations([]string{ruID})
+	if err != nil {
+		return nil, err
+	}
+	section.Cells = cells
+	section.Utilization = findUtilization(utilizations, section)
+	return section, nil
+}
+
+// UpdateSection - название секции
+func (s *SectionService) UpdateSection(ruID string, sectionID int, req *models.UpdateSectionRequest) (*models.Section, error) {
+	section, cells, err := s.getSection(ruID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	section.Name = strings.TrimSpace(req.Name)
+	section.UpdatedAt = time.Now()
+	if err := s.sectionRepo.Save(section); err != nil {
+		return nil, err
+	}
+	section.Cells = cells
+	return section, nil
+}
+
+// Switch - проект бланка переключений для снятия (de_energize) или подачи (energize)
+// напряжения на секцию. При снятии сначала отключаются отходящие присоединения, затем
+// секционные ячейки (чтобы секция не осталась под напряжением от соседней) и последними -
+// вводы. При подаче порядок обратный, а секционные ячейки не включаются: секции в
+// нормальном режиме работают раздельно. Ячейки, которые нельзя или не нужно переключать,
+// перечисляются в бланке с причиной.
+func (s *SectionService) Switch(ruID string, sectionID int, operation models.SwitchingOperation, req *models.SectionSwitchingRequest, actor models.Actor) (*models.SwitchingOrder, error) {
+	section, cells, err := s.getSection(ruID, sectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	locked := map[int]models.CellLock{}
+	if operation == models.SwitchingEnergize {
+		cellIDs := make([]int, len(cells))
+		for i, cell := range cells {
+			cellIDs[i] = cell.ID
+		}
+		if locked, err = s.lockRepo.GetActiveLocks(cellIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	var steps, skipped []models.SwitchingStep
+	for _, cell := range cells {
+		step := models.SwitchingStep{
+			CellID:     cell.ID,
+			CellNumber: cell.Number,
+			CellName:   cell.Name,
+			CellType:   cell.Type,
+			FromStatus: cell.Status,
+			Critical:   cell.IsCritical,
+		}
+		step.SkipReason = switchingSkipReason(cell, operation, locked)
+		if step.SkipReason != "" {
+			skipped = append(skipped, step)
+			continue
+		}
+		step.ToStatus = models.CellStatusOFF
+		if operation == models.SwitchingEnergize {
+			step.ToStatus = models.CellStatusON
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, ErrSectionNothingToSwitch.WithDetails(map[string]interface{}{
+			"sectionId": section.ID,
+			"operation": operation,
+		})
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return switchingRank(steps[i].CellType, operation) < switchingRank(steps[j].CellType, operation)
+	})
+	for i := range steps {
+		steps[i].Seq = i + 1
+	}
+
+	order := &models.SwitchingOrder{
+		ID:        utils.NewID(models.IDPrefixSwitchingOrder),
+		RuID:      ruID,
+		SectionID: section.ID,
+		Operation: operation,
+		Status:    models.SwitchingOrderDraft,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: actor.Email,
+		CreatedAt: time.Now(),
+		Steps:     append(steps, skipped...),
+	}
+	if err := s.sectionRepo.CreateOrder(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetOrders - бланки переключений РУ, последние первыми
+func (s *SectionService) GetOrders(ruID string) ([]models.SwitchingOrder, error) {
+	return s.sectionRepo.GetOrders(ruID, switchingOrdersLimit)
+}
+
+func (s *SectionService) GetOrder(ruID, orderID string) (*models.SwitchingOrder, error) {
+	order, err := s.sectionRepo.GetOrder(ruID, utils.NormalizeID(models.IDPrefixSwitchingOrder, orderID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrSwitchingOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// switchingSkipReason - почему ячейка не входит в бланк; пусто - входит
+func switchingSkipReason(cell models.Cell, operation models.SwitchingOperation, locked map[int]models.CellLock) string {
+	switch {
+	case cell.Type == models.CellTypeReserve, cell.Status == models.CellStatusReserve:
+		return models.SwitchingSkipReserve
+	case cell.Status == models.CellStatusError:
+		return models.SwitchingSkipFault
+	case cell.Status == models.CellStatusMaintenance:
+		return models.SwitchingSkipMaintenance
+	}
+	if operation == models.SwitchingDeEnergize {
+		if cell.Status != models.CellStatusON {
+			return models.SwitchingSkipInState
+		}
+		return ""
+	}
+	switch _, isLocked := locked[cell.ID]; {
+	case sectionalCellTypes[cell.Type]:
+		return models.SwitchingSkipSectional
+	case cell.Status == models.CellStatusON:
+		return models.SwitchingSkipInState
+	case cell.IsGrounded:
+		return models.SwitchingSkipGrounded
+	case isLocked:
+		return models.SwitchingSkipLocked
+	}
+	return ""
+}
+
+// switchingRank - очередь ячейки в бланке: при снятии напряжения присоединения, затем
+// секционные ячейки, затем вводы; при подаче - вводы, затем присоединения
+func switchingRank(cellType models.CellType, operation models.SwitchingOperation) int {
+	rank := 0
+	switch {
+	case cellType == models.CellTypeInput:
+		rank = 2
+	case sectionalCellTypes[cellType]:
+		rank = 1
+	}
+	if operation == models.SwitchingEnergize {
+		return -rank
+	}
+	return rank
+}
+
+// describeSection - состояние и ток секции по ее ячейкам
+func describeSection(section *models.Section, cells []models.Cell) {
+	var working, on, maintenance int
+	var inputs, feeders float64
+	hasInputs := false
+	for _, cell := range cells {
+		if cell.Current != nil {
+			switch {
+			case cell.Type == models.CellTypeInput:
+				inputs += *cell.Current
+				hasInputs = true
+			case sectionFeederTypes[cell.Type]:
+				feeders += *cell.Current
+			}
+		}
+		if cell.Status == models.CellStatusReserve {
+			continue
+		}
+		working++
+		switch cell.Status {
+		case models.CellStatusON:
+			on++
+		case models.CellStatusMaintenance:
+			maintenance++
+		}
+	}
+
+	section.CellsTotal = len(cells)
+	section.CellsOn = on
+	section.CurrentA = feeders
+	if hasInputs {
+		section.CurrentA = inputs
+	}
+	switch {
+	case working > 0 && maintenance == working:
+		section.Status = models.SectionMaintenance
+	case on == 0:
+		section.Status = models.SectionDeEnergized
+	case on < working:
+		section.Status = models.SectionPartial
+	default:
+		section.Status = models.SectionEnergized
+	}
+}
+
+func findUtilization(utilizations []models.SectionUtilization, section *models.Section) *models.SectionUtilization {
+	for i := range utilizations {
+		row := &utilizations[i]
+		if row.RuID == section.RuID && row.VoltageLevel == section.VoltageLevel && row.Section == section.Number {
+			return row
+		}
+	}
+	return nil
+}
+
+// getSection - секция РУ с состоянием по ее ячейкам
+func (s *SectionService) getSection(ruID string, sectionID int) (*models.Section, []models.Cell, error) {
+	section, err := s.sectionRepo.GetByID(ruID, sectionID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, nil, ErrSectionNotFound
+		}
+		return nil, nil, err
+	}
+	cells, err := s.sectionRepo.GetCells(section)
+	if err != nil {
+		return nil, nil, err
+	}
+	describeSection(section, cells)
+	return section, cells, nil
+}
@@ -0,0 +1,553 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// Ключи системных настроек
+const (
+	SettingCORSAllowedOrigins       = "cors.allowed_origins"
+	SettingPasswordMinLength        = "password.min_length"
+	SettingPasswordRequireSpecial   = "password.require_special"
+	SettingHistoryDefaultLimit      = "history.default_limit"
+	SettingHistoryMaxLimit          = "history.max_limit"
+	SettingImpersonationTTL         = "impersonation.ttl"
+	SettingAlarmEscalationChain     = "alarms.escalation_chain"
+	SettingAlarmShelveMax           = "alarms.shelve_max"
+	SettingAlarmSuppressUnderPermit = "alarms.suppress_under_permit"
+	SettingAlarmChatterCount        = "alarms.chatter_count"
+	SettingAlarmChatterWindow       = "alarms.chatter_window"
+	SettingSLADefectTargets         = "sla.defect_targets"
+	SettingSLAPermitTargets         = "sla.permit_targets"
+	SettingAnomalyZThreshold        = "anomaly.z_threshold"
+	SettingAnomalyHalfLife          = "anomaly.half_life"
+	SettingCapacityWarningPercent   = "capacity.warning_percent"
+	SettingCapacityCriticalPercent  = "capacity.critical_percent"
+	SettingInspectionIntervalDays   = "inspection.interval_days"
+	SettingVisionMinConfidence      = "vision.min_confidence_percent"
+	SettingThermalAmbientWarning    = "thermal.ambient_warning_delta"
+	SettingThermalAmbientCritical   = "thermal.ambient_critical_delta"
+	SettingThermalPhaseWarning      = "thermal.phase_warning_delta"
+	SettingThermalPhaseCritical     = "thermal.phase_critical_delta"
+	SettingTapDailyLimit            = "tap.daily_operations_limit"
+	SettingBreakerWearPercent       = "breaker.wear_warning_percent"
+	SettingSmsPermitExpiryLead      = "sms.permit_expiry_lead"
+	SettingHistoryMaxFutureSkew     = "history.max_future_skew"
+	SettingHistoryMaxBackdate       = "history.max_backdate"
+	SettingHistoryOfflineBackdate   = "history.offline_max_backdate"
+	SettingHistoryClockSkewAlert    = "history.clock_skew_alert"
+)
+
+// settingsRefreshInterval - как часто перечитываются настройки, измененные другим экземпляром
+const settingsRefreshInterval = 30 * time.Second
+
+// settingDefinition - описание настройки: тип, значение по умолчанию и проверка
+type settingDefinition struct {
+	key          string
+	typ          models.SettingType
+	defaultValue interface{}
+	description  string
+	min, max     int
+	// validate - дополнительная проверка разобранного значения
+	validate func(value interface{}) error
+}
+
+// decode - разбирает JSON-значение в тип настройки и проверяет границы
+func (d settingDefinition) decode(raw json.RawMessage) (interface{}, error) {
+	value, err := d.decodeType(raw)
+	if err != nil || d.validate == nil {
+		return value, err
+	}
+	if err := d.validate(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func (d settingDefinition) decodeType(raw json.RawMessage) (interface{}, error) {
+	switch d.typ {
+	case models.SettingString:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("expected string")
+		}
+		return strings.TrimSpace(v), nil
+	case models.SettingInt:
+		var v int
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("expected integer")
+		}
+		if v < d.min || (d.max > 0 && v > d.max) {
+			return nil, fmt.Errorf("must be between %d and %d", d.min, d.max)
+		}
+		return v, nil
+	case models.SettingBool:
+		var v bool
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("expected boolean")
+		}
+		return v, nil
+	case models.SettingStringList:
+		var v []string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("expected list of strings")
+		}
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	case models.SettingDuration:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("expected duration string such as \"15m\"")
+		}
+		duration, err := time.ParseDuration(v)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("expected positive duration such as \"15m\"")
+		}
+		return duration, nil
+	}
+	return nil, fmt.Errorf("unsupported setting type %s", d.typ)
+}
+
+// display - значение в виде, пригодном для JSON-ответа
+func (d settingDefinition) display(value interface{}) interface{} {
+	if duration, ok := value.(time.Duration); ok {
+		return duration.String()
+	}
+	return value
+}
+
+// settingDefinitions - все настройки, которые можно менять без перевыпуска
+var settingDefinitions = []settingDefinition{
+	{
+		key:          SettingCORSAllowedOrigins,
+		typ:          models.SettingStringList,
+		defaultValue: []string{"http://localhost:3001", "http://127.0.0.1:3001"},
+		description:  "Origins allowed to call the API from a browser (\"*\" allows any, \"https://*.example.kz\" allows subdomains)",
+	},
+	{
+		key:          SettingPasswordMinLength,
+		typ:          models.SettingInt,
+		defaultValue: 6,
+		description:  "Minimum password length",
+		min:          6,
+		max:          128,
+	},
+	{
+		key:          SettingPasswordRequireSpecial,
+		typ:          models.SettingBool,
+		defaultValue: true,
+		description:  "Require at least one special character in passwords",
+	},
+	{
+		key:          SettingHistoryDefaultLimit,
+		typ:          models.SettingInt,
+		defaultValue: 50,
+		description:  "Operation log records returned when no limit is given",
+		min:          1,
+		max:          10000,
+	},
+	{
+		key:          SettingHistoryMaxLimit,
+		typ:          models.SettingInt,
+		defaultValue: 1000,
+		description:  "Maximum operation log records per request",
+		min:          1,
+		max:          10000,
+	},
+	{
+		key:          SettingImpersonationTTL,
+		typ:          models.SettingDuration,
+		defaultValue: 15 * time.Minute,
+		description:  "Lifetime of tokens issued to admins acting as another user",
+	},
+	{
+		key:          SettingAlarmEscalationChain,
+		typ:          models.SettingStringList,
+		defaultValue: []string{"dispatcher:0m", "engineer:10m", "admin:30m"},
+		description:  "Escalation chain for unacknowledged critical alarms as \"role:delay\" steps (empty list disables escalation)",
+		validate: func(value interface{}) error {
+			_, err := parseEscalationChain(value.([]string))
+			return err
+		},
+	},
+	{
+		key:          SettingAlarmShelveMax,
+		typ:          models.SettingDuration,
+		defaultValue: 8 * time.Hour,
+		description:  "Longest time a dispatcher may shelve a nuisance alarm",
+	},
+	{
+		key:          SettingAlarmSuppressUnderPermit,
+		typ:          models.SettingBool,
+		defaultValue: true,
+		description:  "Suppress new alarms of cells under an open work permit until the permit is closed",
+	},
+	{
+		key:          SettingAlarmChatterCount,
+		typ:          models.SettingInt,
+		defaultValue: 3,
+		description:  "Alarms of one kind from one cell within the chatter window that count as chattering in alarm statistics",
+		min:          2,
+		max:          100,
+	},
+	{
+		key:          SettingAlarmChatterWindow,
+		typ:          models.SettingDuration,
+		defaultValue: time.Minute,
+		description:  "Time window for chattering alarm detection in alarm statistics",
+	},
+	{
+		key:          SettingSLADefectTargets,
+		typ:          models.SettingStringList,
+		defaultValue: []string{"critical:24h", "major:168h", "minor:720h"},
+		description:  "Time to fix a defect by severity as \"severity:duration\" (a defect's due date overrides it; severities without a target are not measured)",
+		validate: func(value interface{}) error {
+			_, err := parseSLATargets(value.([]string), slaDefectSeverities)
+			return err
+		},
+	},
+	{
+		key:          SettingSLAPermitTargets,
+		typ:          models.SettingStringList,
+		defaultValue: []string{"emergency:24h", "warning:72h", "info:168h"},
+		description:  "Time from issuing to closing a work permit by record severity as \"severity:duration\" (permits without severity count as info)",
+		validate: func(value interface{}) error {
+			_, err := parseSLATargets(value.([]string), slaPermitSeverities)
+			return err
+		},
+	},
+	{
+		key:          SettingAnomalyZThreshold,
+		typ:          models.SettingInt,
+		defaultValue: 4,
+		description:  "Deviation from a cell's usual current/temperature, in standard deviations, that raises a warning",
+		min:          2,
+		max:          20,
+	},
+	{
+		key:          SettingAnomalyHalfLife,
+		typ:          models.SettingDuration,
+		defaultValue: 6 * time.Hour,
+		description:  "How quickly a cell's usual current/temperature follows new readings (EWMA half-life)",
+	},
+	{
+		key:          SettingCapacityWarningPercent,
+		typ:          models.SettingInt,
+		defaultValue: 80,
+		description:  "Bus section utilization, in percent of the rated current, that raises a warning",
+		min:          1,
+		max:          200,
+	},
+	{
+		key:          SettingCapacityCriticalPercent,
+		typ:          models.SettingInt,
+		defaultValue: 95,
+		description:  "Bus section utilization, in percent of the rated current, that raises a critical alarm",
+		min:          1,
+		max:          200,
+	},
+	{
+		key:          SettingInspectionIntervalDays,
+		typ:          models.SettingInt,
+		defaultValue: 30,
+		description:  "Days between RU inspections; the next inspection deadline in the calendar feed is counted from the last one",
+		min:          1,
+		max:          366,
+	},
+	{
+		key:          SettingVisionMinConfidence,
+		typ:          models.SettingInt,
+		defaultValue: 80,
+		description:  "Minimum confidence, in percent, for a gauge reading recognized from a photo to be recorded as telemetry",
+		min:          1,
+		max:          100,
+	},
+	{
+		key:          SettingThermalAmbientWarning,
+		typ:          models.SettingInt,
+		defaultValue: 20,
+		description:  "Hotspot temperature rise over ambient, °C, that raises a thermography warning",
+		min:          1,
+		max:          500,
+	},
+	{
+		key:          SettingThermalAmbientCritical,
+		typ:          models.SettingInt,
+		defaultValue: 40,
+		description:  "Hotspot temperature rise over ambient, °C, that raises a critical thermography alarm",
+		min:          1,
+		max:          500,
+	},
+	{
+		key:          SettingThermalPhaseWarning,
+		typ:          models.SettingInt,
+		defaultValue: 4,
+		description:  "Hotspot temperature difference from the same point on a neighbouring phase, °C, that raises a thermography warning",
+		min:          1,
+		max:          500,
+	},
+	{
+		key:          SettingThermalPhaseCritical,
+		typ:          models.SettingInt,
+		defaultValue: 15,
+		description:  "Hotspot temperature difference from the same point on a neighbouring phase, °C, that raises a critical thermography alarm",
+		min:          1,
+		max:          500,
+	},
+	{
+		key:          SettingTapDailyLimit,
+		typ:          models.SettingInt,
+		defaultValue: 20,
+		description:  "Transformer tap changer operations per day above which a warning alarm is raised",
+		min:          1,
+		max:          1000,
+	},
+	{
+		key:          SettingBreakerWearPercent,
+		typ:          models.SettingInt,
+		defaultValue: 90,
+		description:  "Share of the manufacturer operation limit, in percent, at which a breaker maintenance task appears in engineers' inbox",
+		min:          1,
+		max:          100,
+	},
+	{
+		key:          SettingSmsPermitExpiryLead,
+		typ:          models.SettingDuration,
+		defaultValue: time.Hour,
+		description:  "How long before a work permit expires an SMS is sent to recipients of work permit notifications",
+	},
+	{
+		key:          SettingHistoryMaxFutureSkew,
+		typ:          models.SettingDuration,
+		defaultValue: 5 * time.Minute,
+		description:  "How far in the future an operation time may be (clock difference of field devices)",
+	},
+	{
+		key:          SettingHistoryMaxBackdate,
+		typ:          models.SettingDuration,
+		defaultValue: 15 * time.Minute,
+		description:  "How far in the past an operation time may be without the history:backdate permission",
+	},
+	{
+		key:          SettingHistoryOfflineBackdate,
+		typ:          models.SettingDuration,
+		defaultValue: 7 * 24 * time.Hour,
+		description:  "How far in the past an operation recorded offline and uploaded by sync may be without the history:backdate permission",
+	},
+	{
+		key:          SettingHistoryClockSkewAlert,
+		typ:          models.SettingDuration,
+		defaultValue: 2 * time.Minute,
+		description:  "Device clock difference from server time reported as drift",
+	},
+}
+
+// SettingsService - системные настройки, изменяемые администратором. Значения хранятся
+// в таблице settings и кэшируются в памяти; типизированные геттеры возвращают значение
+// по умолчанию, если настройка не переопределена.
+type SettingsService struct {
+	settingRepo *repository.SettingRepository
+	definitions map[string]settingDefinition
+
+	mu       sync.RWMutex
+	values   map[string]interface{}
+	rows     map[string]models.Setting
+	loadedAt time.Time
+}
+
+func NewSettingsService(settingRepo *repository.SettingRepository) *SettingsService {
+	s := &SettingsService{
+		settingRepo: settingRepo,
+		definitions: make(map[string]settingDefinition, len(settingDefinitions)),
+		values:      map[string]interface{}{},
+		rows:        map[string]models.Setting{},
+	}
+	for _, def := range settingDefinitions {
+		s.definitions[def.key] = def
+	}
+	if err := s.reload(); err != nil {
+		log.Printf("⚠️ Failed to load settings: %v", err)
+	}
+	return s
+}
+
+func (s *SettingsService) reload() error {
+	rows, err := s.settingRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{}, len(rows))
+	byKey := make(map[string]models.Setting, len(rows))
+	for _, row := range rows {
+		def, ok := s.definitions[row.Key]
+		if !ok {
+			continue
+		}
+		value, err := def.decode(json.RawMessage(row.Value))
+		if err != nil {
+			log.Printf("⚠️ Ignoring invalid setting %s: %v", row.Key, err)
+			continue
+		}
+		values[row.Key] = value
+		byKey[row.Key] = row
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values = values
+	s.rows = byKey
+	s.loadedAt = time.Now()
+	return nil
+}
+
+// value - текущее значение настройки (переопределенное или по умолчанию)
+func (s *SettingsService) value(key string) interface{} {
+	s.mu.RLock()
+	stale := time.Since(s.loadedAt) > settingsRefreshInterval
+	s.mu.RUnlock()
+	if stale {
+		if err := s.reload(); err != nil {
+			log.Printf("⚠️ Failed to refresh settings: %v", err)
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if value, ok := s.values[key]; ok {
+		return value
+	}
+	return s.definitions[key].defaultValue
+}
+
+func (s *SettingsService) String(key string) string {
+	v, _ := s.value(key).(string)
+	return v
+}
+
+func (s *SettingsService) Int(key string) int {
+	v, _ := s.value(key).(int)
+	return v
+}
+
+func (s *SettingsService) Bool(key string) bool {
+	v, _ := s.value(key).(bool)
+	return v
+}
+
+func (s *SettingsService) StringList(key string) []string {
+	v, _ := s.value(key).([]string)
+	return v
+}
+
+func (s *SettingsService) Duration(key string) time.Duration {
+	v, _ := s.value(key).(time.Duration)
+	return v
+}
+
+// SetDefault - значение по умолчанию из конфигурации окружения вместо зашитого в код.
+// Вызывается при старте до обработки запросов.
+func (s *SettingsService) SetDefault(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if def, ok := s.definitions[key]; ok {
+		def.defaultValue = value
+		s.definitions[key] = def
+	}
+}
+
+// AllowsOrigin - разрешен ли браузерный origin настройкой CORS (с масками поддоменов)
+func (s *SettingsService) AllowsOrigin(origin string) bool {
+	for _, allowed := range s.StringList(SettingCORSAllowedOrigins) {
+		if utils.MatchOrigin(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAll - все настройки с текущими значениями для интерфейса администратора
+func (s *SettingsService) GetAll() []models.SettingView {
+	views := make([]models.SettingView, 0, len(settingDefinitions))
+	for _, known := range settingDefinitions {
+		def := s.definitions[known.key]
+		value := s.value(def.key)
+
+		s.mu.RLock()
+		row, overridden := s.rows[def.key]
+		s.mu.RUnlock()
+
+		view := models.SettingView{
+			Key:         def.key,
+			Type:        def.typ,
+			Description: def.description,
+			Value:       def.display(value),
+			Default:     def.display(def.defaultValue),
+			Overridden:  overridden,
+		}
+		if overridden {
+			updatedAt := row.UpdatedAt
+			view.UpdatedBy = row.UpdatedBy
+			view.UpdatedAt = &updatedAt
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// Update - проверяет и сохраняет новые значения; null сбрасывает настройку к умолчанию.
+// При ошибке в любом значении ничего не сохраняется.
+func (s *SettingsService) Update(req *models.UpdateSettingsRequest, updatedBy string) ([]models.SettingView, error) {
+	keys := make([]string, 0, len(req.Values))
+	for key := range req.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	var upserts []models.Setting
+	var resets []string
+	for _, key := range keys {
+		def, ok := s.definitions[key]
+		if !ok {
+			return nil, ErrSettingUnknown.WithDetails(map[string]interface{}{"key": key})
+		}
+		raw := req.Values[key]
+		if string(raw) == "null" {
+			resets = append(resets, key)
+			continue
+		}
+		value, err := def.decode(raw)
+		if err != nil {
+			return nil, ErrSettingInvalid.WithDetails(map[string]interface{}{"key": key, "error": err.Error()})
+		}
+		encoded, err := json.Marshal(def.display(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode setting %s: %w", key, err)
+		}
+		upserts = append(upserts, models.Setting{Key: key, Value: string(encoded), UpdatedBy: updatedBy, UpdatedAt: now})
+	}
+
+	if err := s.settingRepo.Save(upserts, resets); err != nil {
+		return nil, err
+	}
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("failed to reload settings: %w", err)
+	}
+	return s.GetAll(), nil
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// maxSLADays - самый длинный период отчета SLA
+	maxSLADays = 366
+	// slaBreachesDefaultLimit - нарушений в списке, если limit не задан
+	slaBreachesDefaultLimit = 200
+)
+
+// Важности, для которых задаются нормативы; наряд без важности считается info
+var (
+	slaDefectSeverities = []string{string(models.DefectSeverityCritical), string(models.DefectSeverityMajor), string(models.DefectSeverityMinor)}
+	slaPermitSeverities = []string{string(models.RecordSeverityEmergency), string(models.RecordSeverityWarning), string(models.RecordSeverityInfo)}
+)
+
+// parseSLATargets - разбирает нормативы вида "severity:duration" ("critical:24h");
+// важность без норматива в SLA не учитывается
+func parseSLATargets(items []string, severities []string) (map[string]time.Duration, error) {
+	targets := make(map[string]time.Duration, len(items))
+	for _, item := range items {
+		severity, target, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("target %q: expected \"severity:duration\"", item)
+		}
+		if !slices.Contains(severities, severity) {
+			return nil, fmt.Errorf("target %q: unknown severity %q, expected one of %s", item, severity, strings.Join(severities, ", "))
+		}
+		if _, exists := targets[severity]; exists {
+			return nil, fmt.Errorf("target %q: severity %q is set twice", item, severity)
+		}
+		duration, err := time.ParseDuration(target)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("target %q: expected positive duration such as \"24h\"", item)
+		}
+		targets[severity] = duration
+	}
+	return targets, nil
+}
+
+// SLAService - сроки устранения дефектов (open → fixed) и работ по нарядам-допускам
+// (выдан → закрыт) против нормативов по важности из настроек sla.*
+type SLAService struct {
+	slaRepo  *repository.SLARepository
+	ruRepo   *repository.RuRepository
+	settings *SettingsService
+}
+
+func NewSLAService(slaRepo *repository.SLARepository, ruRepo *repository.RuRepository, settings *SettingsService) *SLAService {
+	return &SLAService{slaRepo: slaRepo, ruRepo: ruRepo, settings: settings}
+}
+
+// GetSummary - показатели SLA дефектов и нарядов РУ организации пользователя за период
+// для панели руководителя
+func (s *SLAService) GetSummary(actor models.Actor, query models.SLAQuery) (*models.SLASummary, error) {
+	defectTargets, permitTargets, err := s.targets()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	defects, permits, err := s.items(actor, query, now, defectTargets, permitTargets)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SLASummary{
+		From:        query.From,
+		To:          query.To,
+		GeneratedAt: now,
+		Defects:     summarizeSLA(defects, slaDefectSeverities, defectTargets),
+		Permits:     summarizeSLA(permits, slaPermitSeverities, permitTargets),
+	}, nil
+}
+
+// GetBreaches - дефекты и наряды РУ организации пользователя с нарушенным сроком,
+// от самых просроченных
+func (s *SLAService) GetBreaches(actor models.Actor, query models.SLAQuery) ([]models.SLAItem, error) {
+	defectTargets, permitTargets, err := s.targets()
+	if err != nil {
+		return nil, err
+	}
+	defects, permits, err := s.items(actor, query, time.Now(), defectTargets, permitTargets)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []models.SLAItem
+	if query.Kind != models.SLAKindPermit {
+		candidates = append(candidates, defects...)
+	}
+	if query.Kind != models.SLAKindDefect {
+		candidates = append(candidates, permits...)
+	}
+
+	breaches := []models.SLAItem{}
+	for _, item := range candidates {
+		if !item.Breached {
+			continue
+		}
+		closed := item.ClosedAt != nil
+		if (query.Status == "open" && closed) || (query.Status == "closed" && !closed) {
+			continue
+		}
+		breaches = append(breaches, item)
+	}
+	sort.SliceStable(breaches, func(i, j int) bool {
+		return breaches[i].OverdueSeconds > breaches[j].OverdueSeconds
+	})
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = slaBreachesDefaultLimit
+	}
+	if len(breaches) > limit {
+		breaches = breaches[:limit]
+	}
+	return breaches, nil
+}
+
+// items - дефекты и наряды периода с рассчитанными сроками на момент now; чужое РУ
+// не отличается от несуществующего
+func (s *SLAService) items(actor models.Actor, query models.SLAQuery, now time.Time, defectTargets, permitTargets map[string]time.Duration) ([]models.SLAItem, []models.SLAItem, error) {
+	from, err := time.ParseInLocation(summaryDateLayout, query.From, time.Local)
+	if err != nil {
+		return nil, nil, ErrSLARangeInvalid
+	}
+	to, err := time.ParseInLocation(summaryDateLayout, query.To, time.Local)
+	if err != nil || to.Before(from) || to.After(from.AddDate(0, 0, maxSLADays-1)) {
+		return nil, nil, ErrSLARangeInvalid
+	}
+	to = to.AddDate(0, 0, 1)
+	if query.RuID != "" {
+		if err := checkRuAccess(s.ruRepo, actor, query.RuID); err != nil {
+			return nil, nil, err
+		}
+	}
+	organizationID := actor.OrganizationScope()
+
+	defects, err := s.slaRepo.GetDefects(query.RuID, organizationID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	defectItems := make([]models.SLAItem, 0, len(defects))
+	for _, defect := range defects {
+		item := models.SLAItem{
+			Kind:       models.SLAKindDefect,
+			ID:         defect.ID,
+			RuID:       defect.RuID,
+			CellNumber: defect.CellNumber,
+			Title:      defect.Title,
+			Severity:   string(defect.Severity),
+			StartedAt:  defect.CreatedAt,
+		}
+		if defect.Status == models.DefectStatusFixed {
+			item.ClosedAt = defect.FixedAt
+		}
+		// Срок, назначенный дефекту, важнее норматива
+		if defect.DueDate != nil {
+			item.Deadline = defect.DueDate
+		} else if target, ok := defectTargets[item.Severity]; ok {
+			deadline := item.StartedAt.Add(target)
+			item.Deadline = &deadline
+		}
+		defectItems = append(defectItems, measureSLA(item, now))
+	}
+
+	permits, err := s.slaRepo.GetPermits(query.RuID, organizationID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	permitItems := make([]models.SLAItem, 0, len(permits))
+	for _, permit := range permits {
+		severity := string(models.RecordSeverityInfo)
+		if permit.Severity != nil && *permit.Severity != "" {
+			severity = string(*permit.Severity)
+		}
+		item := models.SLAItem{
+			Kind:       models.SLAKindPermit,
+			ID:         permit.ID,
+			RuID:       permit.RuID,
+			CellNumber: permit.CellNumber,
+			Title:      *permit.WorkOrderNumber,
+			Severity:   severity,
+			StartedAt:  *permit.StartDateAt,
+		}
+		// Наряд закрыт, когда наступила дата его окончания
+		if permit.EndDateAt != nil && !permit.EndDateAt.After(now) {
+			item.ClosedAt = permit.EndDateAt
+		}
+		if target, ok := permitTargets[severity]; ok {
+			deadline := item.StartedAt.Add(target)
+			item.Deadline = &deadline
+		}
+		permitItems = append(permitItems, measureSLA(item, now))
+	}
+	return defectItems, permitItems, nil
+}
+
+// targets - нормативы дефектов и нарядов по важности
+func (s *SLAService) targets() (map[string]time.Duration, map[string]time.Duration, error) {
+	defectTargets, err := parseSLATargets(s.settings.StringList(SettingSLADefectTargets), slaDefectSeverities)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid defect SLA targets: %w", err)
+	}
+	permitTargets, err := parseSLATargets(s.settings.StringList(SettingSLAPermitTargets), slaPermitSeverities)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid permit SLA targets: %w", err)
+	}
+	return defectTargets, permitTargets, nil
+}
+
+// measureSLA - время в работе и нарушение срока: закрытый - по времени закрытия,
+// открытый - по моменту now
+func measureSLA(item models.SLAItem, now time.Time) models.SLAItem {
+	end := now
+	if item.ClosedAt != nil {
+		end = *item.ClosedAt
+	}
+	if end.After(item.StartedAt) {
+		item.ElapsedSeconds = int64(end.Sub(item.StartedAt) / time.Second)
+	}
+	if item.Deadline != nil && end.After(*item.Deadline) {
+		item.Breached = true
+		item.OverdueSeconds = int64(end.Sub(*item.Deadline) / time.Second)
+	}
+	return item
+}
+
+// summarizeSLA - показатели в целом и по каждой важности (важности без дефектов или
+// нарядов выводятся с нулями)
+func summarizeSLA(items []models.SLAItem, severities []string, targets map[string]time.Duration) models.SLAKindSummary {
+	type accumulator struct {
+		stats            models.SLAStats
+		closedTotal      time.Duration
+		closedWithTarget int
+		closedInTime     int
+	}
+	total := &accumulator{}
+	bySeverity := make(map[string]*accumulator, len(severities))
+	for _, severity := range severities {
+		acc := &accumulator{}
+		if target, ok := targets[severity]; ok {
+			hours := round1(target.Hours())
+			acc.stats.TargetHours = &hours
+		}
+		bySeverity[severity] = acc
+	}
+
+	for _, item := range items {
+		severity, ok := bySeverity[item.Severity]
+		if !ok {
+			severity = &accumulator{}
+			bySeverity[item.Severity] = severity
+		}
+		for _, acc := range []*accumulator{total, severity} {
+			acc.stats.Total++
+			if item.Breached {
+				acc.stats.Breached++
+			}
+			if item.ClosedAt == nil {
+				acc.stats.Open++
+				if item.Breached {
+					acc.stats.OpenBreached++
+				}
+				continue
+			}
+			acc.stats.Closed++
+			acc.closedTotal += time.Duration(item.ElapsedSeconds) * time.Second
+			if item.Deadline != nil {
+				acc.closedWithTarget++
+				if !item.Breached {
+					acc.closedInTime++
+				}
+			}
+		}
+	}
+
+	finish := func(acc *accumulator) models.SLAStats {
+		stats := acc.stats
+		if acc.closedWithTarget > 0 {
+			percent := round1(float64(acc.closedInTime) / float64(acc.closedWithTarget) * 100)
+			stats.CompliancePercent = &percent
+		}
+		if stats.Closed > 0 {
+			hours := round1(acc.closedTotal.Hours() / float64(stats.Closed))
+			stats.MeanTimeToCloseHours = &hours
+		}
+		return stats
+	}
+
+	summary := models.SLAKindSummary{
+		SLAStats:   finish(total),
+		BySeverity: make(map[string]models.SLAStats, len(bySeverity)),
+	}
+	for severity, acc := range bySeverity {
+		summary.BySeverity[severity] = finish(acc)
+	}
+	return summary
+}
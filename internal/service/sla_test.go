@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+func TestParseSLATargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{name: "empty", items: nil, want: map[string]time.Duration{}},
+		{
+			name:  "several severities",
+			items: []string{"critical:24h", "major:72h", "minor:168h"},
+			want:  map[string]time.Duration{"critical": 24 * time.Hour, "major": 72 * time.Hour, "minor": 168 * time.Hour},
+		},
+		{name: "minutes", items: []string{"critical:90m"}, want: map[string]time.Duration{"critical": 90 * time.Minute}},
+		{name: "missing separator", items: []string{"critical24h"}, wantErr: true},
+		{name: "unknown severity", items: []string{"emergency:4h"}, wantErr: true},
+		{name: "duplicate severity", items: []string{"critical:4h", "critical:8h"}, wantErr: true},
+		{name: "invalid duration", items: []string{"critical:day"}, wantErr: true},
+		{name: "zero duration", items: []string{"critical:0h"}, wantErr: true},
+		{name: "negative duration", items: []string{"critical:-1h"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSLATargets(tt.items, slaDefectSeverities)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSLATargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSLATargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasureSLA(t *testing.T) {
+	started := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := started.Add(d)
+		return &t
+	}
+	now := started.Add(10 * time.Hour)
+
+	tests := []struct {
+		name        string
+		closedAt    *time.Time
+		deadline    *time.Time
+		wantElapsed int64
+		wantBreach  bool
+		wantOverdue int64
+	}{
+		{name: "open without target", wantElapsed: 36000},
+		{name: "open within target", deadline: at(24 * time.Hour), wantElapsed: 36000},
+		{name: "open past target", deadline: at(4 * time.Hour), wantElapsed: 36000, wantBreach: true, wantOverdue: 6 * 3600},
+		{name: "closed in time", closedAt: at(2 * time.Hour), deadline: at(4 * time.Hour), wantElapsed: 7200},
+		{name: "closed exactly at deadline", closedAt: at(4 * time.Hour), deadline: at(4 * time.Hour), wantElapsed: 4 * 3600},
+		{name: "closed late", closedAt: at(5 * time.Hour), deadline: at(4 * time.Hour), wantElapsed: 5 * 3600, wantBreach: true, wantOverdue: 3600},
+		{name: "closed before start", closedAt: at(-time.Hour), deadline: at(4 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := measureSLA(models.SLAItem{StartedAt: started, ClosedAt: tt.closedAt, Deadline: tt.deadline}, now)
+			if got.ElapsedSeconds != tt.wantElapsed || got.Breached != tt.wantBreach || got.OverdueSeconds != tt.wantOverdue {
+				t.Errorf("measureSLA() = elapsed %d, breached %v, overdue %d; want %d, %v, %d",
+					got.ElapsedSeconds, got.Breached, got.OverdueSeconds, tt.wantElapsed, tt.wantBreach, tt.wantOverdue)
+			}
+		})
+	}
+}
+
+func TestSummarizeSLA(t *testing.T) {
+	started := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	closed := started.Add(time.Hour)
+	deadline := started.Add(2 * time.Hour)
+	item := func(severity string, isClosed, withDeadline, breached bool, elapsed time.Duration) models.SLAItem {
+		i := models.SLAItem{Severity: severity, StartedAt: started, Breached: breached, ElapsedSeconds: int64(elapsed / time.Second)}
+		if isClosed {
+			i.ClosedAt = &closed
+		}
+		if withDeadline {
+			i.Deadline = &deadline
+		}
+		return i
+	}
+	f := func(v float64) *float64 { return &v }
+
+	items := []models.SLAItem{
+		item("critical", true, true, false, 2*time.Hour),
+		item("critical", true, true, true, 4*time.Hour),
+		item("critical", false, true, true, 5*time.Hour),
+		item("major", true, false, false, 3*time.Hour),
+		item("major", false, true, false, time.Hour),
+	}
+	targets := map[string]time.Duration{"critical": 2 * time.Hour, "major": 90 * time.Minute}
+	got := summarizeSLA(items, slaDefectSeverities, targets)
+
+	tests := []struct {
+		name string
+		got  models.SLAStats
+		want models.SLAStats
+	}{
+		{
+			name: "total",
+			got:  got.SLAStats,
+			want: models.SLAStats{Total: 5, Open: 2, Closed: 3, Breached: 2, OpenBreached: 1, CompliancePercent: f(50), MeanTimeToCloseHours: f(3)},
+		},
+		{
+			name: "critical",
+			got:  got.BySeverity["critical"],
+			want: models.SLAStats{TargetHours: f(2), Total: 3, Open: 1, Closed: 2, Breached: 2, OpenBreached: 1, CompliancePercent: f(50), MeanTimeToCloseHours: f(3)},
+		},
+		{
+			// Закрытый без норматива не входит в долю закрытых в срок
+			name: "major",
+			got:  got.BySeverity["major"],
+			want: models.SLAStats{TargetHours: f(1.5), Total: 2, Open: 1, Closed: 1, MeanTimeToCloseHours: f(3)},
+		},
+		{
+			name: "severity without items",
+			got:  got.BySeverity["minor"],
+			want: models.SLAStats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("stats = %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSLAScopedByOrganization(t *testing.T) {
+	db := newTestDB(t, &models.RUInfo{}, &models.Defect{}, &models.OperationRecord{}, &models.Setting{})
+	seed := []interface{}{
+		&models.RUInfo{ID: "ru-a", OrganizationID: "org-a"},
+		&models.RUInfo{ID: "ru-b", OrganizationID: "org-b"},
+		&models.Defect{ID: "def-a", RuID: "ru-a", Severity: models.DefectSeverityCritical, Status: models.DefectStatusOpen, CreatedAt: time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)},
+		&models.Defect{ID: "def-b", RuID: "ru-b", Severity: models.DefectSeverityCritical, Status: models.DefectStatusOpen, CreatedAt: time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)},
+	}
+	for _, row := range seed {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	service := NewSLAService(repository.NewSLARepository(db), repository.NewRuRepository(db), NewSettingsService(repository.NewSettingRepository(db)))
+	dispatcher := models.Actor{Role: models.RoleDispatcher, OrganizationID: "org-a"}
+
+	tests := []struct {
+		name    string
+		actor   models.Actor
+		ruID    string
+		want    int
+		wantErr error
+	}{
+		{name: "own organization", actor: dispatcher, want: 1},
+		{name: "platform admin sees all", actor: models.Actor{Role: models.RoleAdmin}, want: 2},
+		{name: "own RU", actor: dispatcher, ruID: "ru-a", want: 1},
+		{name: "RU of other organization", actor: dispatcher, ruID: "ru-b", wantErr: ErrRuNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := service.GetSummary(tt.actor, models.SLAQuery{From: "2025-03-01", To: "2025-03-31", RuID: tt.ruID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetSummary error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && summary.Defects.Total != tt.want {
+				t.Errorf("defects = %d, want %d", summary.Defects.Total, tt.want)
+			}
+			_, err = service.GetBreaches(tt.actor, models.SLAQuery{From: "2025-03-01", To: "2025-03-31", RuID: tt.ruID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetBreaches error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
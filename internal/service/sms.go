@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/internal/sms"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// smsAlarmWindow - аварии старше не рассылаются (например, сразу после подключения SMS)
+	smsAlarmWindow = 15 * time.Minute
+	// smsStatusWindow - сколько после отправки запрашивается статус доставки
+	smsStatusWindow = 24 * time.Hour
+	smsBatch        = 100
+	smsMaxAttempts  = 3
+	// smsMaxLength - длина текста в символах (три части SMS в кириллице)
+	smsMaxLength = 200
+
+	smsDefaultLimit = 100
+	smsMaxLimit     = 500
+
+	smsMonthLayout = "2006-01"
+)
+
+// SmsService - SMS о критичных авариях и истекающих нарядах-допусках для сотрудников
+// без смартфонов. Получатели те же, что у уведомлений (правила РУ, подписки,
+// организация), но только с указанным мобильным номером.
+type SmsService struct {
+	smsRepo       *repository.SmsRepository
+	ruRepo        *repository.RuRepository
+	notifications *NotificationService
+	settings      *SettingsService
+	provider      sms.Provider
+}
+
+func NewSmsService(smsRepo *repository.SmsRepository, ruRepo *repository.RuRepository, notifications *NotificationService, settings *SettingsService, provider sms.Provider) *SmsService {
+	return &SmsService{
+		smsRepo:       smsRepo,
+		ruRepo:        ruRepo,
+		notifications: notifications,
+		settings:      settings,
+		provider:      provider,
+	}
+}
+
+// ProviderEnabled - SMS-шлюз настроен (SMS_PROVIDER)
+func (s *SmsService) ProviderEnabled() bool {
+	return s.provider != nil
+}
+
+// Ping - проверка SMS-шлюза для /readyz
+func (s *SmsService) Ping(ctx context.Context) error {
+	return s.provider.Ping(ctx)
+}
+
+// Run - шаг задачи sms-notifications: ставит в очередь SMS о новых критичных авариях
+// и истекающих нарядах, отправляет очередь и обновляет статусы доставки
+func (s *SmsService) Run(ctx context.Context, now time.Time) error {
+	if s.provider == nil {
+		return nil
+	}
+	if err := s.queueAlarms(now); err != nil {
+		return err
+	}
+	if err := s.queuePermitExpiries(now); err != nil {
+		return err
+	}
+	if err := s.deliver(ctx); err != nil {
+		return err
+	}
+	return s.refreshStatuses(ctx, now)
+}
+
+func (s *SmsService) queueAlarms(now time.Time) error {
+	alarms, err := s.smsRepo.GetNewCriticalAlarms(now.Add(-smsAlarmWindow))
+	if err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, alarm := range alarms {
+		name, err := s.ruName(names, alarm.RuID)
+		if err != nil {
+			return err
+		}
+		event := models.NotificationEvent{RuID: alarm.RuID, Category: models.EventCategoryAlarm}
+		if alarm.CellID != nil {
+			event.CellID = *alarm.CellID
+		}
+		text := i18n.T(i18n.Default, "sms.alarm", name, alarm.Message)
+		if err := s.enqueue(models.SmsKindAlarm, alarm.ID, event, text, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SmsService) queuePermitExpiries(now time.Time) error {
+	lead := s.settings.Duration(SettingSmsPermitExpiryLead)
+	if lead <= 0 {
+		return nil
+	}
+	permits, err := s.smsRepo.GetExpiringPermits(now, now.Add(lead))
+	if err != nil {
+		return err
+	}
+	names := map[string]string{}
+	for _, permit := range permits {
+		name, err := s.ruName(names, permit.RuID)
+		if err != nil {
+			return err
+		}
+		event := models.NotificationEvent{RuID: permit.RuID, Category: models.EventCategoryWorkPermit}
+		text := i18n.T(i18n.Default, "sms.permit_expiry", *permit.WorkOrderNumber, name, permit.CellNumber,
+			permit.EndDateAt.In(time.Local).Format("02.01 15:04"))
+		// Продленный наряд с новым сроком - новый повод для SMS
+		sourceID := fmt.Sprintf("%s:%d", permit.ID, permit.EndDateAt.Unix())
+		if err := s.enqueue(models.SmsKindPermitExpiry, sourceID, event, text, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruName - наименование РУ для текста SMS; удаленное РУ подписывается идентификатором
+func (s *SmsService) ruName(cache map[string]string, ruID string) (string, error) {
+	if name, ok := cache[ruID]; ok {
+		return name, nil
+	}
+	name := ruID
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil && !repository.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get RU: %w", err)
+	}
+	if err == nil {
+		name = ru.Name
+	}
+	cache[ruID] = name
+	return name, nil
+}
+
+// enqueue - SMS получателям события с мобильным номером
+func (s *SmsService) enqueue(kind models.SmsKind, sourceID string, event models.NotificationEvent, text string, now time.Time) error {
+	recipients, err := s.notifications.Recipients(event)
+	if err != nil {
+		return err
+	}
+	if runes := []rune(text); len(runes) > smsMaxLength {
+		text = string(runes[:smsMaxLength-1]) + "…"
+	}
+
+	organizationID := ""
+	if event.RuID != "" {
+		if organizationID, err = s.ruRepo.GetRuOrganization(event.RuID); err != nil && !repository.IsNotFound(err) {
+			return fmt.Errorf("failed to get RU organization: %w", err)
+		}
+	}
+
+	var messages []models.SmsMessage
+	for _, user := range recipients {
+		if user.Phone == "" {
+			continue
+		}
+		messageOrganization := organizationID
+		if messageOrganization == "" {
+			messageOrganization = user.OrganizationID
+		}
+		messages = append(messages, models.SmsMessage{
+			ID:             utils.NewID(models.IDPrefixSms),
+			UserID:         user.ID,
+			SourceID:       sourceID,
+			Kind:           kind,
+			RuID:           event.RuID,
+			OrganizationID: messageOrganization,
+			Phone:          user.Phone,
+			Text:           text,
+			Status:         models.SmsQueued,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+	}
+	return s.smsRepo.CreateMessages(messages)
+}
+
+// deliver - отправка очереди; после smsMaxAttempts неудачных попыток сообщение
+// помечается недоставленным
+func (s *SmsService) deliver(ctx context.Context) error {
+	queued, err := s.smsRepo.GetQueued(smsBatch)
+	if err != nil {
+		return err
+	}
+	for i := range queued {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		message := &queued[i]
+		now := time.Now()
+		message.Attempts++
+		message.UpdatedAt = now
+		message.Provider = s.provider.Name()
+		result, err := s.provider.Send(ctx, message.Phone, message.Text)
+		if err != nil {
+			log.Printf("⚠️ SMS %s: %v", message.ID, err)
+			message.LastError = err.Error()
+			if message.Attempts >= smsMaxAttempts {
+				message.Status = models.SmsFailed
+			}
+		} else {
+			message.Status = models.SmsSent
+			message.ProviderMessageID = result.MessageID
+			message.Segments = result.Segments
+			message.LastError = ""
+			message.SentAt = &now
+		}
+		if err := s.smsRepo.UpdateMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshStatuses - статусы доставки сообщений, отправленных за последние сутки
+func (s *SmsService) refreshStatuses(ctx context.Context, now time.Time) error {
+	pending, err := s.smsRepo.GetAwaitingDelivery(now.Add(-smsStatusWindow), smsBatch)
+	if err != nil {
+		return err
+	}
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		message := &pending[i]
+		status, err := s.provider.Status(ctx, message.Phone, message.ProviderMessageID)
+		if err != nil {
+			log.Printf("⚠️ SMS %s status: %v", message.ID, err)
+			continue
+		}
+		switch status {
+		case sms.StatusDelivered:
+			deliveredAt := time.Now()
+			message.Status = models.SmsDelivered
+			message.DeliveredAt = &deliveredAt
+		case sms.StatusFailed:
+			message.Status = models.SmsFailed
+			message.LastError = "undelivered"
+		default:
+			continue
+		}
+		message.UpdatedAt = time.Now()
+		if err := s.smsRepo.UpdateMessage(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMessages - журнал SMS со статусами доставки
+func (s *SmsService) GetMessages(filter models.SmsFilter) ([]models.SmsMessage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = smsDefaultLimit
+	}
+	filter.Limit = min(filter.Limit, smsMaxLimit)
+	return s.smsRepo.GetMessages(filter)
+}
+
+// GetUsage - расход SMS за календарный месяц (YYYY-MM, по умолчанию текущий)
+func (s *SmsService) GetUsage(query models.SmsUsageQuery) (*models.SmsUsageReport, error) {
+	month := query.Month
+	if month == "" {
+		month = time.Now().Format(smsMonthLayout)
+	}
+	from, err := time.ParseInLocation(smsMonthLayout, month, time.Local)
+	if err != nil {
+		return nil, ErrSmsMonthInvalid
+	}
+	rows, err := s.smsRepo.GetUsage(from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SmsUsageReport{
+		Month:          month,
+		ByStatus:       map[models.SmsStatus]int{},
+		ByKind:         map[models.SmsKind]models.SmsUsage{},
+		ByOrganization: []models.SmsOrganizationUsage{},
+	}
+	organizations := map[string]int{}
+	for _, row := range rows {
+		report.Messages += row.Messages
+		report.Segments += row.Segments
+		report.ByStatus[row.Status] += row.Messages
+
+		kind := report.ByKind[row.Kind]
+		kind.Messages += row.Messages
+		kind.Segments += row.Segments
+		report.ByKind[row.Kind] = kind
+
+		index, ok := organizations[row.OrganizationID]
+		if !ok {
+			index = len(report.ByOrganization)
+			organizations[row.OrganizationID] = index
+			report.ByOrganization = append(report.ByOrganization, models.SmsOrganizationUsage{OrganizationID: row.OrganizationID})
+		}
+		report.ByOrganization[index].Messages += row.Messages
+		report.ByOrganization[index].Segments += row.Segments
+	}
+	return report, nil
+}
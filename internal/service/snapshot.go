@@ -0,0 +1,171 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// ExportRu - снимок РУ со всеми ячейками
+func (s *RuService) ExportRu(ruID string) (*models.RuSnapshot, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+
+	return &models.RuSnapshot{
+		Version:    models.RuSnapshotVersion,
+		ExportedAt: time.Now(),
+		RU:         *ru,
+		Cells:      cells,
+	}, nil
+}
+
+// ImportRu - восстановление РУ из снимка с выбранной стратегией конфликта
+func (s *RuService) ImportRu(req *models.ImportRuRequest) (*models.ImportRuResult, error) {
+	snapshot := req.Snapshot
+	if snapshot.Version != models.RuSnapshotVersion {
+		return nil, ErrSnapshotVersion.WithDetails(map[string]interface{}{
+			"version":   snapshot.Version,
+			"supported": models.RuSnapshotVersion,
+		})
+	}
+	if snapshot.RU.ID == "" {
+		return nil, ErrSnapshotInvalid
+	}
+	substation, err := s.GetSubstation(snapshot.RU.SubstationID)
+	if err != nil {
+		return nil, err
+	}
+
+	ruID := snapshot.RU.ID
+	if req.Strategy == models.ImportNewID {
+		if req.NewID == nil {
+			return nil, ErrSnapshotNewIDRequired
+		}
+		ruID = *req.NewID
+	}
+
+	existing, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil && !repository.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+
+	result := &models.ImportRuResult{RuID: ruID, Strategy: req.Strategy, CellsUnmatched: []string{}}
+	if existing != nil {
+		switch req.Strategy {
+		case models.ImportSkip:
+			result.Outcome = models.ImportSkipped
+			return result, nil
+		case models.ImportNewID:
+			return nil, ErrRuExists
+		}
+	}
+
+	now := time.Now()
+	ru := snapshot.RU
+	ru.ID = ruID
+	ru.OrganizationID = substation.OrganizationID
+	ru.UpdatedAt = now
+	ru.CreatedAt = now
+	if existing != nil {
+		ru.CreatedAt = existing.CreatedAt
+	}
+	// Снимки, выгруженные до перехода на коды, содержат текст статуса; нештатный
+	// статус в них был установлен вручную
+	if status := models.ParseLegacyRuStatus(string(ru.Status)); status != ru.Status {
+		if status != models.RuStatusNormal && !ru.StatusOverride {
+			reason := strings.TrimSpace(string(ru.Status))
+			ru.StatusOverride = true
+			ru.StatusReason = &reason
+		}
+		ru.Status = status
+	}
+
+	// Ячейки сопоставляются по номеру и уровню напряжения: ID ячеек в разных
+	// окружениях не совпадают, а номер уникален только в пределах стороны РУ
+	byNumber := map[string]models.Cell{}
+	if existing != nil {
+		current, err := s.ruRepo.GetCellsByRuID(ruID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cells: %w", err)
+		}
+		for _, cell := range current {
+			byNumber[cellKey(cell)] = cell
+		}
+	}
+
+	seen := map[string]bool{}
+	cells := make([]models.Cell, 0, len(snapshot.Cells))
+	sourceIndex := make(map[int]int, len(snapshot.Cells))
+	for i, cell := range snapshot.Cells {
+		if !cell.VoltageLevel.Valid() {
+			return nil, ErrVoltageLevelInvalid.WithDetails(map[string]interface{}{"number": cell.Number, "voltageLevel": cell.VoltageLevel})
+		}
+		key := cellKey(cell)
+		if seen[key] {
+			return nil, ErrCellDuplicate.WithDetails(map[string]interface{}{"number": cell.Number, "voltageLevel": cell.VoltageLevel})
+		}
+		seen[key] = true
+
+		sourceIndex[cell.ID] = i
+		cell.RuID = ruID
+		cell.UpdatedAt = now
+		if match, ok := byNumber[key]; ok {
+			cell.ID = match.ID
+			cell.CreatedAt = match.CreatedAt
+			delete(byNumber, key)
+			result.CellsUpdated++
+		} else {
+			cell.ID = 0
+			cell.CreatedAt = now
+			result.CellsCreated++
+		}
+		cells = append(cells, cell)
+	}
+	for _, cell := range byNumber {
+		result.CellsUnmatched = append(result.CellsUnmatched, cell.Number)
+	}
+
+	// Пары в снимке ссылаются на ID исходного окружения; переводим их в индексы ячеек
+	pairs := map[int]int{}
+	for i, cell := range snapshot.Cells {
+		if cell.PairedCellID == nil {
+			continue
+		}
+		j, ok := sourceIndex[*cell.PairedCellID]
+		if !ok || snapshot.Cells[j].VoltageLevel == cell.VoltageLevel {
+			continue
+		}
+		pairs[i] = j
+	}
+
+	if err := s.ruRepo.ImportRu(&ru, existing == nil, cells, pairs); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrCellDuplicate
+		}
+		return nil, fmt.Errorf("failed to import RU: %w", err)
+	}
+
+	result.Outcome = models.ImportCreated
+	if existing != nil {
+		result.Outcome = models.ImportOverwritten
+	}
+	return result, nil
+}
+
+// cellKey - номер ячейки с уровнем напряжения, уникальные в пределах РУ
+func cellKey(cell models.Cell) string {
+	return string(cell.VoltageLevel) + "/" + cell.Number
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// statsDays - период статистики операций, суток (включая текущие)
+	statsDays = 30
+	// statsTopRUs - число самых активных РУ в статистике
+	statsTopRUs = 10
+)
+
+// statsRoles - роли в порядке вывода; роли без пользователей выводятся с нулем
+var statsRoles = []models.UserRole{models.RoleDispatcher, models.RoleEngineer, models.RoleOrgAdmin, models.RoleAdmin}
+
+// StatsService - статистика использования системы: пользователи по ролям, операции
+// по суткам, самые активные РУ и объем вложений
+type StatsService struct {
+	statsRepo *repository.StatsRepository
+}
+
+func NewStatsService(statsRepo *repository.StatsRepository) *StatsService {
+	return &StatsService{statsRepo: statsRepo}
+}
+
+// GetStats - статистика за последние statsDays суток по местному времени
+func (s *StatsService) GetStats() (*models.SystemStats, error) {
+	now := time.Now()
+	stats := &models.SystemStats{GeneratedAt: now}
+
+	users, err := s.statsRepo.CountUsersByRole()
+	if err != nil {
+		return nil, err
+	}
+	stats.Users.ByRole = make(map[models.UserRole]int, len(statsRoles))
+	for _, role := range statsRoles {
+		stats.Users.ByRole[role] = 0
+	}
+	for role, count := range users {
+		stats.Users.ByRole[role] = count
+		stats.Users.Total += count
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -(statsDays - 1))
+	to := today.AddDate(0, 0, 1)
+	stats.OperationsPerDay = make([]models.DailyOperations, 0, statsDays)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		count, err := s.statsRepo.CountOperations(day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+		stats.OperationsPerDay = append(stats.OperationsPerDay, models.DailyOperations{
+			Date:       day.Format(summaryDateLayout),
+			Operations: count,
+		})
+	}
+
+	stats.MostActiveRUs, err = s.statsRepo.GetMostActiveRUs(from, to, statsTopRUs)
+	if err != nil {
+		return nil, err
+	}
+	if stats.MostActiveRUs == nil {
+		stats.MostActiveRUs = []models.RuActivity{}
+	}
+
+	if stats.Storage.Photos, err = s.statsRepo.GetPhotoUsage(); err != nil {
+		return nil, err
+	}
+	if stats.Storage.Comtrade, err = s.statsRepo.GetComtradeUsage(); err != nil {
+		return nil, err
+	}
+	stats.Storage.TotalBytes = stats.Storage.Photos.Bytes + stats.Storage.Photos.ThumbnailBytes + stats.Storage.Comtrade.Bytes
+	return stats, nil
+}
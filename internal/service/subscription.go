@@ -0,0 +1,224 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// substationSubscriptionRoles - роли, по умолчанию подписанные на РУ закрепленных
+// за пользователем подстанций
+var substationSubscriptionRoles = map[models.UserRole]bool{
+	models.RoleDispatcher: true,
+}
+
+// SubscriptionService - подписки на РУ и ячейки. Пользователь без подписок получает
+// все уведомления по правилам РУ, с подписками - только по отслеживаемым РУ и ячейкам.
+type SubscriptionService struct {
+	subscriptionRepo *repository.SubscriptionRepository
+	userRepo         *repository.UserRepository
+	ruRepo           *repository.RuRepository
+}
+
+func NewSubscriptionService(subscriptionRepo *repository.SubscriptionRepository, userRepo *repository.UserRepository, ruRepo *repository.RuRepository) *SubscriptionService {
+	return &SubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		ruRepo:           ruRepo,
+	}
+}
+
+// GetSubscriptions - собственные подписки пользователя и подписки по умолчанию для его роли
+func (s *SubscriptionService) GetSubscriptions(actor models.Actor) ([]models.Subscription, error) {
+	subscriptions, err := s.subscriptionRepo.GetByUsers([]string{actor.UserID})
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Source = models.SubscriptionManual
+	}
+
+	if !substationSubscriptionRoles[actor.Role] {
+		return subscriptions, nil
+	}
+	assignments, err := s.subscriptionRepo.GetAssignments([]string{actor.UserID})
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		rus, err := s.ruRepo.GetRUsBySubstationID(assignment.SubstationID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ru := range rus {
+			subscriptions = append(subscriptions, models.Subscription{
+				UserID:    actor.UserID,
+				RuID:      ru.ID,
+				Source:    models.SubscriptionSubstation,
+				CreatedAt: assignment.CreatedAt,
+			})
+		}
+	}
+	return subscriptions, nil
+}
+
+func (s *SubscriptionService) Subscribe(actor models.Actor, req *models.CreateSubscriptionRequest) (*models.Subscription, error) {
+	ru, err := s.ruRepo.GetRuByID(req.RuID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if !actor.CanAccessOrganization(ru.OrganizationID) {
+		return nil, ErrRuNotFound
+	}
+	if req.CellID != 0 {
+		if _, err := s.ruRepo.GetCellByID(req.CellID, ru.ID); err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrCellNotFound
+			}
+			return nil, fmt.Errorf("failed to get cell: %w", err)
+		}
+	}
+
+	subscription := &models.Subscription{
+		ID:        utils.NewID(models.IDPrefixSubscription),
+		UserID:    actor.UserID,
+		RuID:      ru.ID,
+		CellID:    req.CellID,
+		Source:    models.SubscriptionManual,
+		CreatedAt: time.Now(),
+	}
+	if err := s.subscriptionRepo.Create(subscription); err != nil {
+		if repository.IsDuplicate(err) {
+			return nil, ErrSubscriptionExists
+		}
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return subscription, nil
+}
+
+func (s *SubscriptionService) Unsubscribe(actor models.Actor, subscriptionID string) error {
+	deleted, err := s.subscriptionRepo.Delete(actor.UserID, utils.NormalizeID(models.IDPrefixSubscription, subscriptionID))
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if !deleted {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// GetUserSubstations - подстанции, закрепленные за пользователем
+func (s *SubscriptionService) GetUserSubstations(actor models.Actor, userID string) ([]models.UserSubstation, error) {
+	if _, err := s.scopedUser(actor, userID); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.GetAssignments([]string{userID})
+}
+
+// SetUserSubstations - закрепляет подстанции за пользователем; подстанции должны
+// принадлежать организации пользователя
+func (s *SubscriptionService) SetUserSubstations(actor models.Actor, userID string, req *models.SetUserSubstationsRequest) ([]models.UserSubstation, error) {
+	user, err := s.scopedUser(actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	substationIDs := make([]string, 0, len(req.SubstationIDs))
+	for _, id := range req.SubstationIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		substation, err := s.ruRepo.GetSubstationByID(id)
+		if err != nil && !repository.IsNotFound(err) {
+			return nil, err
+		}
+		if err != nil || substation.OrganizationID != user.OrganizationID {
+			return nil, ErrSubstationNotFound.WithDetails(map[string]interface{}{"substationId": id})
+		}
+		substationIDs = append(substationIDs, id)
+	}
+
+	if err := s.subscriptionRepo.ReplaceAssignments(user.ID, substationIDs, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.subscriptionRepo.GetAssignments([]string{user.ID})
+}
+
+func (s *SubscriptionService) scopedUser(actor models.Actor, userID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil || !actor.CanAccessOrganization(user.OrganizationID) {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// Filter - оставляет получателей, которые следят за РУ (или ячейкой) события либо не
+// подписаны ни на что. Подписки всех получателей загружаются одним запросом.
+func (s *SubscriptionService) Filter(users []*models.User, ruID string, cellID int) ([]*models.User, error) {
+	if len(users) == 0 || ruID == "" {
+		return users, nil
+	}
+
+	userIDs := make([]string, 0, len(users))
+	roles := make(map[string]models.UserRole, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+		roles[user.ID] = user.Role
+	}
+
+	subscriptions, err := s.subscriptionRepo.GetByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.subscriptionRepo.GetAssignments(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	following := make(map[string]bool)
+	matched := make(map[string]bool)
+	for _, subscription := range subscriptions {
+		following[subscription.UserID] = true
+		if subscription.Matches(ruID, cellID) {
+			matched[subscription.UserID] = true
+		}
+	}
+
+	var ru *models.RUInfo
+	for _, assignment := range assignments {
+		if !substationSubscriptionRoles[roles[assignment.UserID]] {
+			continue
+		}
+		following[assignment.UserID] = true
+		if ru == nil {
+			if ru, err = s.ruRepo.GetRuByID(ruID); err != nil {
+				if repository.IsNotFound(err) {
+					return users, nil
+				}
+				return nil, fmt.Errorf("failed to get RU: %w", err)
+			}
+		}
+		if assignment.SubstationID == ru.SubstationID {
+			matched[assignment.UserID] = true
+		}
+	}
+
+	var filtered []*models.User
+	for _, user := range users {
+		if !following[user.ID] || matched[user.ID] {
+			filtered = append(filtered, user)
+		}
+	}
+	return filtered, nil
+}
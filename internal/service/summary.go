@@ -0,0 +1,141 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// summaryDateLayout - формат даты суточной сводки (?date=2024-03-15)
+const summaryDateLayout = "2006-01-02"
+
+// SummaryService - суточная сводка диспетчера по подстанции
+type SummaryService struct {
+	ruService   *RuService
+	ruRepo      *repository.RuRepository
+	summaryRepo *repository.SummaryRepository
+}
+
+func NewSummaryService(ruService *RuService, ruRepo *repository.RuRepository, summaryRepo *repository.SummaryRepository) *SummaryService {
+	return &SummaryService{
+		ruService:   ruService,
+		ruRepo:      ruRepo,
+		summaryRepo: summaryRepo,
+	}
+}
+
+// DailySummary - операции, переключения, аварии, наряды и пиковые нагрузки РУ
+// подстанции за сутки (по времени сервера). Пустая дата - текущие сутки; важность
+// отбирает записи журнала, учитываемые в разделе операций.
+func (s *SummaryService) DailySummary(actor models.Actor, substationID string, req models.DailySummaryRequest) (*models.DailySummary, error) {
+	from := time.Now()
+	if req.Date != "" {
+		parsed, err := time.ParseInLocation(summaryDateLayout, req.Date, time.Local)
+		if err != nil {
+			return nil, ErrInvalidDate.WithDetails(map[string]interface{}{"date": req.Date, "format": summaryDateLayout})
+		}
+		from = parsed
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 0, 1)
+
+	substation, err := s.ruService.GetSubstationFor(actor, substationID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.DailySummary{
+		SubstationID:   substation.ID,
+		SubstationName: substation.Name,
+		Date:           from.Format(summaryDateLayout),
+		From:           from,
+		To:             to,
+		Operations: models.OperationsSummary{
+			Severity:       req.Severity,
+			ByAction:       map[string]int{},
+			BySeverity:     map[models.RecordSeverity]int{},
+			ByDocumentType: map[string]int{},
+		},
+		Switching: models.SwitchingSummary{ByStatus: map[models.CellStatus]int{}},
+		Alarms:    models.AlarmsSummary{BySeverity: map[models.AlarmSeverity]int{}},
+		PeakLoads: []models.PeakLoad{},
+	}
+
+	rus, err := s.ruRepo.GetRUsBySubstationID(substation.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rus) == 0 {
+		return summary, nil
+	}
+	ruIDs := make([]string, len(rus))
+	for i, ru := range rus {
+		ruIDs[i] = ru.ID
+	}
+
+	if summary.Operations.ByAction, err = s.summaryRepo.CountOperationsByAction(ruIDs, from, to, req.Severity); err != nil {
+		return nil, err
+	}
+	if summary.Operations.BySeverity, err = s.summaryRepo.CountOperationsBySeverity(ruIDs, from, to); err != nil {
+		return nil, err
+	}
+	if summary.Operations.ByDocumentType, err = s.summaryRepo.CountOperationsByDocumentType(ruIDs, from, to, req.Severity); err != nil {
+		return nil, err
+	}
+	for _, count := range summary.Operations.ByAction {
+		summary.Operations.Total += count
+	}
+
+	events, err := s.summaryRepo.GetEvents(models.EventCellStatusChanged, ruIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	switched := make(map[int]bool)
+	for i := range events {
+		var payload models.CellStatusChangedPayload
+		if err := decodePayload(&events[i], &payload); err != nil {
+			return nil, err
+		}
+		summary.Switching.Switches++
+		summary.Switching.ByStatus[payload.Status]++
+		switched[payload.CellID] = true
+	}
+	summary.Switching.Cells = len(switched)
+
+	if summary.Alarms.BySeverity, err = s.summaryRepo.CountAlarmsRaised(ruIDs, from, to); err != nil {
+		return nil, err
+	}
+	for _, count := range summary.Alarms.BySeverity {
+		summary.Alarms.Raised += count
+	}
+	cleared, err := s.summaryRepo.CountAlarmsCleared(ruIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	summary.Alarms.Cleared = int(cleared)
+
+	opened, closed, err := s.summaryRepo.CountPermits(ruIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	summary.Permits = models.PermitsSummary{Opened: int(opened), Closed: int(closed)}
+
+	peaks, err := s.summaryRepo.GetPeakLoads(ruIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, ru := range rus {
+		if peak, ok := peaks[ru.ID]; ok {
+			summary.PeakLoads = append(summary.PeakLoads, models.PeakLoad{
+				RuID:   ru.ID,
+				RuName: ru.Name,
+				CellID: peak.CellID,
+				Value:  peak.Max,
+				Hour:   peak.BucketStart,
+			})
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/apperrors"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// syncPageSize - предел записей каждого вида за один запрос ленты изменений
+	syncPageSize = 500
+	// syncCursorLag - курсор отстает от текущего времени, чтобы не пропустить записи
+	// транзакций, которые еще не зафиксированы на момент запроса
+	syncCursorLag = 5 * time.Second
+)
+
+// SyncService - синхронизация планшетов, работающих без связи: лента изменений
+// с курсора и прием пакетов операций и осмотров с разбором конфликтов.
+type SyncService struct {
+	syncRepo    *repository.SyncRepository
+	ruService   *RuService
+	inspections *InspectionService
+}
+
+func NewSyncService(syncRepo *repository.SyncRepository, ruService *RuService, inspections *InspectionService) *SyncService {
+	return &SyncService{
+		syncRepo:    syncRepo,
+		ruService:   ruService,
+		inspections: inspections,
+	}
+}
+
+// Changes - РУ, ячейки, записи журнала и осмотры, измененные с курсора. Если какой-то вид
+// записей не поместился в страницу, курсор указывает на последнюю отданную запись этого вида.
+func (s *SyncService) Changes(actor models.Actor, query *models.SyncChangesQuery) (*models.SyncChanges, error) {
+	var since time.Time
+	if query.Since != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, query.Since)
+		if err != nil {
+			return nil, ErrSyncCursorInvalid
+		}
+		since = parsed
+	}
+
+	now := time.Now()
+	cursor := now.Add(-syncCursorLag)
+	changes := &models.SyncChanges{
+		ServerTime:  now,
+		RUs:         []models.RUInfo{},
+		Cells:       []models.Cell{},
+		Operations:  []models.OperationRecord{},
+		Inspections: []models.Inspection{},
+	}
+	// truncated - страница заполнена: курсор не дальше последней отданной записи
+	truncated := func(last time.Time) {
+		changes.HasMore = true
+		if last.Before(cursor) {
+			cursor = last
+		}
+	}
+
+	rus, err := s.ruService.GetVisibleRUs(actor)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(query.RuIDs))
+	for _, id := range query.RuIDs {
+		wanted[id] = true
+	}
+	ruIDs := make([]string, 0, len(rus))
+	for _, ru := range rus {
+		if len(wanted) > 0 && !wanted[ru.ID] {
+			continue
+		}
+		ruIDs = append(ruIDs, ru.ID)
+		if !ru.UpdatedAt.Before(since) {
+			changes.RUs = append(changes.RUs, ru)
+		}
+	}
+	if len(ruIDs) == 0 {
+		changes.Cursor = cursor.UTC().Format(time.RFC3339Nano)
+		return changes, nil
+	}
+
+	cells, err := s.syncRepo.GetChangedCells(ruIDs, since, syncPageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) > syncPageSize {
+		cells = cells[:syncPageSize]
+		truncated(cells[syncPageSize-1].UpdatedAt)
+	}
+	changes.Cells = append(changes.Cells, cells...)
+
+	operations, err := s.syncRepo.GetChangedOperations(ruIDs, since, syncPageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(operations) > syncPageSize {
+		operations = operations[:syncPageSize]
+		truncated(operations[syncPageSize-1].UpdatedAt)
+	}
+	changes.Operations = append(changes.Operations, operations...)
+
+	inspections, err := s.syncRepo.GetNewInspections(ruIDs, since, syncPageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(inspections) > syncPageSize {
+		inspections = inspections[:syncPageSize]
+		truncated(inspections[syncPageSize-1].CreatedAt)
+	}
+	changes.Inspections = append(changes.Inspections, inspections...)
+
+	changes.Cursor = cursor.UTC().Format(time.RFC3339Nano)
+	return changes, nil
+}
+
+// Upload - разбор пакета планшета. Элементы обрабатываются независимо: конфликт или отказ
+// по одному элементу не отменяет остальные. Принятые элементы запоминаются по ClientID,
+// поэтому пакет можно безопасно отправить повторно после обрыва связи.
+func (s *SyncService) Upload(actor models.Actor, req *models.SyncUploadRequest) (*models.SyncUploadReport, error) {
+	clientIDs := make([]string, 0, len(req.Operations)+len(req.Inspections))
+	for _, op := range req.Operations {
+		clientIDs = append(clientIDs, op.ClientID)
+	}
+	for _, inspection := range req.Inspections {
+		clientIDs = append(clientIDs, inspection.ClientID)
+	}
+	accepted, err := s.syncRepo.GetItems(clientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SyncUploadReport{Items: make([]models.SyncItemResult, 0, len(clientIDs))}
+
+	// Операции применяются в порядке, в котором их выполнили на планшете
+	for i := range req.Operations {
+		op := &req.Operations[i]
+		if op.Record != nil {
+			// Часы планшета сверяются с сервером по времени отправки пакета
+			op.Record.Device = req.DeviceID
+			if op.Record.ClientTime == nil {
+				op.Record.ClientTime = req.ClientTime
+			}
+		}
+		result := models.SyncItemResult{ClientID: op.ClientID, Kind: models.SyncItemOperation}
+		if item, ok := accepted[op.ClientID]; ok {
+			result.Outcome = models.SyncDuplicate
+			result.ServerID = item.ServerID
+		} else if err := s.applyOperation(actor, req.DeviceID, op, &result); err != nil {
+			return nil, err
+		}
+		countSyncResult(report, result)
+	}
+
+	for i := range req.Inspections {
+		inspection := &req.Inspections[i]
+		result := models.SyncItemResult{ClientID: inspection.ClientID, Kind: models.SyncItemInspection}
+		if item, ok := accepted[inspection.ClientID]; ok {
+			result.Outcome = models.SyncDuplicate
+			result.ServerID = item.ServerID
+		} else if err := s.applyInspection(actor, req.DeviceID, inspection, &result); err != nil {
+			return nil, err
+		}
+		countSyncResult(report, result)
+	}
+
+	return report, nil
+}
+
+// applyOperation - переключение ячейки с проверкой версии, затем запись журнала.
+// Возвращает ошибку только при сбое сервера; итог по элементу пишется в result.
+func (s *SyncService) applyOperation(actor models.Actor, deviceID string, op *models.SyncOperation, result *models.SyncItemResult) error {
+	if op.Status == nil && op.Record == nil {
+		return rejectSyncItem(result, ErrSyncOperationEmpty)
+	}
+	if err := s.checkRuAccess(actor, op.RuID); err != nil {
+		return rejectSyncItem(result, err)
+	}
+
+	if op.Status != nil {
+		cell, err := s.ruService.GetCell(op.RuID, *op.CellID)
+		if err != nil {
+			return rejectSyncItem(result, err)
+		}
+		// Версии сравниваются с точностью базы данных (микросекунды)
+		if cell.UpdatedAt.Truncate(time.Microsecond).After(op.BaseUpdatedAt.Truncate(time.Microsecond)) {
+			result.Outcome = models.SyncConflict
+			result.Error = syncItemError(ErrSyncCellChanged)
+			result.Cell = cell
+			return nil
+		}
+
+		updated, confirmation, err := s.ruService.UpdateCellStatus(op.RuID, *op.CellID, &models.UpdateCellStatusRequest{
+			Status:     *op.Status,
+			IsGrounded: op.IsGrounded,
+		}, actor)
+		if err != nil {
+			return rejectSyncItem(result, err)
+		}
+		if confirmation != nil {
+			// Запись журнала дождется решения по подтверждению: ее отправят повторно
+			result.Outcome = models.SyncPending
+			result.ServerID = confirmation.ID
+			return nil
+		}
+		result.Cell = updated
+	}
+
+	if op.Record != nil {
+		record, err := s.ruService.addHistoryRecord(op.RuID, op.Record, actor, s.ruService.settings.Duration(SettingHistoryOfflineBackdate))
+		if err != nil {
+			return rejectSyncItem(result, err)
+		}
+		result.ServerID = record.ID
+	}
+
+	result.Outcome = models.SyncApplied
+	return s.syncRepo.SaveItem(&models.SyncItem{
+		ClientID:  op.ClientID,
+		Kind:      models.SyncItemOperation,
+		ServerID:  result.ServerID,
+		DeviceID:  deviceID,
+		UserID:    actor.UserID,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (s *SyncService) applyInspection(actor models.Actor, deviceID string, req *models.SyncInspection, result *models.SyncItemResult) error {
+	if err := s.checkRuAccess(actor, req.RuID); err != nil {
+		return rejectSyncItem(result, err)
+	}
+	inspection, err := s.inspections.SubmitInspection(req.RuID, &req.Inspection, actor)
+	if err != nil {
+		return rejectSyncItem(result, err)
+	}
+
+	result.Outcome = models.SyncApplied
+	result.ServerID = inspection.ID
+	return s.syncRepo.SaveItem(&models.SyncItem{
+		ClientID:  req.ClientID,
+		Kind:      models.SyncItemInspection,
+		ServerID:  inspection.ID,
+		DeviceID:  deviceID,
+		UserID:    actor.UserID,
+		CreatedAt: time.Now(),
+	})
+}
+
+// checkRuAccess - РУ чужой организации отклоняется как несуществующее
+func (s *SyncService) checkRuAccess(actor models.Actor, ruID string) error {
+	organizationID, err := s.ruService.RuOrganization(ruID)
+	if err != nil {
+		return err
+	}
+	if !actor.CanAccessOrganization(organizationID) {
+		return ErrRuNotFound
+	}
+	return nil
+}
+
+// rejectSyncItem - ошибки приложения становятся отказом по элементу, прочие прерывают разбор
+func rejectSyncItem(result *models.SyncItemResult, err error) error {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) {
+		return fmt.Errorf("sync item %s: %w", result.ClientID, err)
+	}
+	result.Outcome = models.SyncRejected
+	result.Error = syncItemError(appErr)
+	return nil
+}
+
+func syncItemError(err *apperrors.Error) *models.SyncItemError {
+	return &models.SyncItemError{Code: err.Code, Message: err.Message}
+}
+
+func countSyncResult(report *models.SyncUploadReport, result models.SyncItemResult) {
+	switch result.Outcome {
+	case models.SyncApplied:
+		report.Applied++
+	case models.SyncDuplicate:
+		report.Duplicates++
+	case models.SyncPending:
+		report.Pending++
+	case models.SyncConflict:
+		report.Conflicts++
+	case models.SyncRejected:
+		report.Rejected++
+	}
+	report.Items = append(report.Items, result)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/i18n"
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// maintenanceHorizonDays - за сколько рабочих дней до срока ТО задача попадает во входящие
+	maintenanceHorizonDays = 20
+	// maintenanceReminderDays - за сколько рабочих дней до срока ТО рассылается напоминание
+	maintenanceReminderDays = 5
+	// workPermitRetention - сколько дней просроченный наряд остается во входящих
+	workPermitRetention = 30 * 24 * time.Hour
+)
+
+// TaskSource - источник задач для личной очереди пользователя
+type TaskSource func(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error)
+
+type TaskService struct {
+	userRepo *repository.UserRepository
+	ruRepo   *repository.RuRepository
+	calendar *CalendarService
+	sources  []TaskSource
+}
+
+func NewTaskService(userRepo *repository.UserRepository, ruRepo *repository.RuRepository, calendar *CalendarService) *TaskService {
+	s := &TaskService{
+		userRepo: userRepo,
+		ruRepo:   ruRepo,
+		calendar: calendar,
+	}
+	s.sources = []TaskSource{
+		s.maintenanceTasks,
+		s.workPermitTasks,
+	}
+	return s
+}
+
+// AddSource - подключает дополнительный источник задач
+func (s *TaskService) AddSource(source TaskSource) {
+	s.sources = append(s.sources, source)
+}
+
+// GetTasksForUser - собирает задачи пользователя из всех источников
+// и сортирует их по срочности: по возрастанию срока, задачи без срока - в конце.
+func (s *TaskService) GetTasksForUser(userID string, lang i18n.Lang) ([]models.Task, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now()
+	tasks := []models.Task{}
+	for _, source := range s.sources {
+		items, err := source(user, now, lang)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect tasks: %w", err)
+		}
+		tasks = append(tasks, items...)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].Deadline, tasks[j].Deadline
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Before(*b)
+	})
+
+	return tasks, nil
+}
+
+// maintenanceTasks - предстоящее и просроченное ТО РУ организации пользователя (для
+// инженеров и админов). Срок ТО, выпавший на нерабочий день, переносится на предыдущий
+// рабочий день.
+func (s *TaskService) maintenanceTasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	actor := models.UserActor(user)
+	if !actor.IsElevated() {
+		return nil, nil
+	}
+	return s.dueMaintenance(actor.OrganizationScope(), now, maintenanceHorizonDays, lang)
+}
+
+// MaintenanceReminders - РУ, у которых срок ТО наступает в ближайшие
+// maintenanceReminderDays рабочих дней или уже прошел
+func (s *TaskService) MaintenanceReminders(now time.Time) ([]models.Task, error) {
+	return s.dueMaintenance("", now, maintenanceReminderDays, i18n.Default)
+}
+
+// dueMaintenance - РУ организации (пустая - всех организаций) со сроком ТО в пределах
+// horizonDays рабочих дней
+func (s *TaskService) dueMaintenance(organizationID string, now time.Time, horizonDays int, lang i18n.Lang) ([]models.Task, error) {
+	var rus []models.RUInfo
+	var err error
+	if organizationID == "" {
+		rus, err = s.ruRepo.GetAllRUs()
+	} else {
+		rus, err = s.ruRepo.GetRUsByOrganization(organizationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	calendar, err := s.calendar.LoadAround(now, horizonDays)
+	if err != nil {
+		return nil, err
+	}
+	horizon := calendar.AddWorkingDays(now, horizonDays)
+
+	var tasks []models.Task
+	for _, ru := range rus {
+		deadline, ok := maintenanceDeadline(ru, calendar)
+		if !ok {
+			continue
+		}
+		if deadline.After(horizon) {
+			continue
+		}
+		tasks = append(tasks, models.Task{
+			Type:     models.TaskTypeMaintenance,
+			RefID:    ru.ID,
+			Title:    i18n.T(lang, "task.maintenance", ru.Name),
+			RuID:     ru.ID,
+			Deadline: &deadline,
+			Overdue:  deadline.Before(now),
+		})
+	}
+	return tasks, nil
+}
+
+// maintenanceDeadline - срок ТО РУ с переносом с нерабочего дня на предыдущий рабочий
+func maintenanceDeadline(ru models.RUInfo, calendar *WorkCalendar) (time.Time, bool) {
+	var deadline time.Time
+	if ru.NextMaintenanceAt != nil {
+		deadline = *ru.NextMaintenanceAt
+	} else {
+		var err error
+		if deadline, err = utils.ParseDate(ru.NextMaintenance); err != nil {
+			return time.Time{}, false
+		}
+	}
+	return calendar.PreviousWorkingDay(deadline), true
+}
+
+// workPermitTasks - наряды, где пользователь указан ответственным
+func (s *TaskService) workPermitTasks(user *models.User, now time.Time, lang i18n.Lang) ([]models.Task, error) {
+	records, err := s.ruRepo.GetWorkPermitsByResponsible(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	for _, record := range records {
+		task := models.Task{
+			Type:  models.TaskTypeWorkPermit,
+			RefID: record.ID,
+			Title: i18n.T(lang, "task.work_permit", *record.WorkOrderNumber, record.Action, record.CellNumber),
+			RuID:  record.RuID,
+		}
+		deadline := record.EndDateAt
+		if deadline == nil {
+			deadline = utils.ParseDatePtr(record.EndDate)
+		}
+		if deadline != nil {
+			if deadline.Before(now.Add(-workPermitRetention)) {
+				continue
+			}
+			task.Deadline = deadline
+			task.Overdue = deadline.Before(now)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+// checkRuAccess - РУ существует и относится к организации пользователя; чужое РУ
+// не отличается от несуществующего
+func checkRuAccess(ruRepo *repository.RuRepository, actor models.Actor, ruID string) error {
+	_, err := ruOrganization(ruRepo, actor, ruID)
+	return err
+}
+
+// actorOrganization - организация пользователя; другую организацию может указать только
+// администратор установки
+func actorOrganization(actor models.Actor, requested string) string {
+	if actor.IsPlatformAdmin() && requested != "" {
+		return requested
+	}
+	return actor.OrganizationID
+}
+
+// ruOrganization - организация РУ, доступного пользователю
+func ruOrganization(ruRepo *repository.RuRepository, actor models.Actor, ruID string) (string, error) {
+	organizationID, err := ruRepo.GetRuOrganization(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return "", ErrRuNotFound
+		}
+		return "", fmt.Errorf("failed to get RU organization: %w", err)
+	}
+	if !actor.CanAccessOrganization(organizationID) {
+		return "", ErrRuNotFound
+	}
+	return organizationID, nil
+}
+
+// checkRusAccess - все РУ пакета существуют и относятся к организации пользователя
+func checkRusAccess(ruRepo *repository.RuRepository, actor models.Actor, ruIDs []string) error {
+	organizations, err := ruRepo.GetRuOrganizations(ruIDs)
+	if err != nil {
+		return err
+	}
+	for _, ruID := range ruIDs {
+		organizationID, ok := organizations[ruID]
+		if !ok || !actor.CanAccessOrganization(organizationID) {
+			return ErrRuNotFound.WithDetails(map[string]interface{}{"ruId": ruID})
+		}
+	}
+	return nil
+}
+
+// cellRef - ячейка, указанная в записи пакета телеметрии
+type cellRef struct {
+	ruID   string
+	cellID int
+}
+
+// checkCellRefs - каждая ячейка пакета существует и принадлежит своему РУ, а РУ -
+// организации пользователя
+func checkCellRefs(ruRepo *repository.RuRepository, actor models.Actor, refs []cellRef) error {
+	var ruIDs []string
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if !seen[ref.ruID] {
+			seen[ref.ruID] = true
+			ruIDs = append(ruIDs, ref.ruID)
+		}
+	}
+	if err := checkRusAccess(ruRepo, actor, ruIDs); err != nil {
+		return err
+	}
+	cells, err := ruRepo.GetCellsByRuIDs(ruIDs)
+	if err != nil {
+		return err
+	}
+	owners := make(map[int]string, len(cells))
+	for _, cell := range cells {
+		owners[cell.ID] = cell.RuID
+	}
+	for _, ref := range refs {
+		if owner, ok := owners[ref.cellID]; !ok || owner != ref.ruID {
+			return ErrCellNotFound.WithDetails(map[string]interface{}{
+				"ruId":   ref.ruID,
+				"cellId": ref.cellID,
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+var (
+	tenantActorA        = models.Actor{UserID: "user-a", Email: "a@example.com", Role: models.RoleEngineer, OrganizationID: "org-a"}
+	tenantActorB        = models.Actor{UserID: "user-b", Email: "b@example.com", Role: models.RoleEngineer, OrganizationID: "org-b"}
+	tenantPlatformAdmin = models.Actor{UserID: "admin", Email: "admin@example.com", Role: models.RoleAdmin, OrganizationID: models.DefaultOrganizationID}
+)
+
+// tenantFixture - сервисы поверх базы с данными двух организаций: у каждой свое РУ
+// с ячейкой, дефект, оборудование, устройство, склад и резерв
+type tenantFixture struct {
+	defects      *DefectService
+	assets       *AssetService
+	devices      *DeviceService
+	inventory    *InventoryService
+	search       *SearchService
+	measurements *MeasurementService
+	numbering    *NumberingService
+	cellA, cellB int
+}
+
+func newTenantFixture(t *testing.T) *tenantFixture {
+	t.Helper()
+	db := newTestDB(t,
+		&models.RUInfo{}, &models.Cell{}, &models.OperationRecord{}, &models.User{},
+		&models.Defect{}, &models.Photo{}, &models.Asset{}, &models.AssetEvent{}, &models.Device{}, &models.DeviceCell{},
+		&models.Warehouse{}, &models.InventoryItem{}, &models.StockLevel{}, &models.Reservation{},
+		&models.Measurement{}, &models.OperationCorrection{}, &models.CorrectedField{},
+		&models.DocumentType{}, &models.DocumentNumberSequence{}, &models.IssuedDocumentNumber{},
+	)
+	cellA := models.Cell{RuID: "ru-a", Number: "1", Name: "Feeder A", Type: models.CellTypeInput}
+	cellB := models.Cell{RuID: "ru-b", Number: "1", Name: "Feeder B", Type: models.CellTypeInput}
+	ruA, ruB := "ru-a", "ru-b"
+	rows := []interface{}{
+		&models.RUInfo{ID: "ru-a", Name: "Feeder RU A", OrganizationID: "org-a"},
+		&models.RUInfo{ID: "ru-b", Name: "Feeder RU B", OrganizationID: "org-b"},
+		&cellA, &cellB,
+		&models.Defect{ID: "defect-a", RuID: "ru-a", Title: "Defect A", Status: models.DefectStatusOpen},
+		&models.Defect{ID: "defect-b", RuID: "ru-b", Title: "Defect B", Status: models.DefectStatusOpen},
+		&models.Asset{ID: "asset-a", SerialNumber: "SN-A", RuID: &ruA, OrganizationID: "org-a"},
+		&models.Asset{ID: "asset-b", SerialNumber: "SN-B", RuID: &ruB, OrganizationID: "org-b"},
+		&models.Device{ID: "dev-a", Name: "Device A", RuID: "ru-a"},
+		&models.Device{ID: "dev-b", Name: "Device B", RuID: "ru-b"},
+		&models.Warehouse{ID: "wh-a", Name: "Warehouse A", OrganizationID: "org-a"},
+		&models.Warehouse{ID: "wh-b", Name: "Warehouse B", OrganizationID: "org-b"},
+		&models.InventoryItem{ID: "item-1", SKU: "SKU-1", Name: "Fuse"},
+		&models.DocumentType{Code: models.DocumentTypePermit, Name: "Permit", Active: true},
+		&models.Reservation{ID: "resv-a", WarehouseID: "wh-a", ItemID: "item-1", RuID: "ru-a", Status: models.ReservationActive},
+		&models.Reservation{ID: "resv-b", WarehouseID: "wh-b", ItemID: "item-1", RuID: "ru-b", Status: models.ReservationActive},
+	}
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+
+	ruRepo := repository.NewRuRepository(db)
+	defectRepo := repository.NewDefectRepository(db)
+	photos := NewPhotoService(repository.NewPhotoRepository(db), ruRepo, nil)
+	return &tenantFixture{
+		defects:      NewDefectService(defectRepo, photos, ruRepo, repository.NewUserRepository(db)),
+		assets:       NewAssetService(repository.NewAssetRepository(db), ruRepo),
+		devices:      NewDeviceService(repository.NewDeviceRepository(db), ruRepo),
+		inventory:    NewInventoryService(repository.NewInventoryRepository(db), ruRepo, defectRepo),
+		search:       NewSearchService(repository.NewSearchRepository(db), false),
+		measurements: NewMeasurementService(repository.NewMeasurementRepository(db), ruRepo),
+		numbering:    NewNumberingService(repository.NewNumberingRepository(db), ruRepo, NewDocumentTypeService(repository.NewDocumentTypeRepository(db))),
+		cellA:        cellA.ID,
+		cellB:        cellB.ID,
+	}
+}
+
+func TestTenantIsolationLists(t *testing.T) {
+	f := newTenantFixture(t)
+
+	lists := map[string]func(actor models.Actor) ([]string, error){
+		"defects": func(actor models.Actor) ([]string, error) {
+			defects, err := f.defects.GetDefects(actor, models.DefectFilter{})
+			return collectIDs(defects, func(d models.Defect) string { return d.ID }), err
+		},
+		"assets": func(actor models.Actor) ([]string, error) {
+			assets, err := f.assets.GetAssets(actor, models.AssetFilter{})
+			return collectIDs(assets, func(a models.Asset) string { return a.ID }), err
+		},
+		"devices": func(actor models.Actor) ([]string, error) {
+			devices, err := f.devices.GetDevices(actor, models.DeviceFilter{})
+			return collectIDs(devices, func(d models.Device) string { return d.ID }), err
+		},
+		"warehouses": func(actor models.Actor) ([]string, error) {
+			warehouses, err := f.inventory.GetWarehouses(actor)
+			return collectIDs(warehouses, func(w models.Warehouse) string { return w.ID }), err
+		},
+		"reservations": func(actor models.Actor) ([]string, error) {
+			reservations, err := f.inventory.GetReservations(actor, models.ReservationFilter{})
+			return collectIDs(reservations, func(r models.Reservation) string { return r.ID }), err
+		},
+		"search": func(actor models.Actor) ([]string, error) {
+			results, err := f.search.Search(actor, "feeder", []string{string(models.SearchResultRU)}, "", 0)
+			return collectIDs(results, func(r models.SearchResult) string { return r.RuID }), err
+		},
+	}
+	want := map[string]map[string][]string{
+		"defects":      {"org-a": {"defect-a"}, "org-b": {"defect-b"}, "platform": {"defect-a", "defect-b"}},
+		"assets":       {"org-a": {"asset-a"}, "org-b": {"asset-b"}, "platform": {"asset-a", "asset-b"}},
+		"devices":      {"org-a": {"dev-a"}, "org-b": {"dev-b"}, "platform": {"dev-a", "dev-b"}},
+		"warehouses":   {"org-a": {"wh-a"}, "org-b": {"wh-b"}, "platform": {"wh-a", "wh-b"}},
+		"reservations": {"org-a": {"resv-a"}, "org-b": {"resv-b"}, "platform": {"resv-a", "resv-b"}},
+		"search":       {"org-a": {"ru-a"}, "org-b": {"ru-b"}, "platform": {"ru-a", "ru-b"}},
+	}
+	actors := map[string]models.Actor{"org-a": tenantActorA, "org-b": tenantActorB, "platform": tenantPlatformAdmin}
+
+	for list, get := range lists {
+		for name, actor := range actors {
+			t.Run(list+"/"+name, func(t *testing.T) {
+				got, err := get(actor)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !slices.Equal(got, want[list][name]) {
+					t.Errorf("got %v, want %v", got, want[list][name])
+				}
+			})
+		}
+	}
+}
+
+func TestTenantIsolationForeignEntities(t *testing.T) {
+	f := newTenantFixture(t)
+	title := "Renamed"
+	measurement := func(ruID string, cellID int) *models.RecordMeasurementsRequest {
+		return &models.RecordMeasurementsRequest{Measurements: []models.MeasurementInput{
+			{RuID: ruID, CellID: cellID, Metric: models.MetricCurrent, Value: 10, MeasuredAt: time.Now()},
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		call    func(actor models.Actor) error
+		wantErr error
+	}{
+		{
+			name:    "get defect",
+			call:    func(actor models.Actor) error { _, err := f.defects.GetDefect(actor, "defect-b"); return err },
+			wantErr: ErrDefectNotFound,
+		},
+		{
+			name: "update defect",
+			call: func(actor models.Actor) error {
+				_, err := f.defects.UpdateDefect(actor, "defect-b", &models.UpdateDefectRequest{Title: &title})
+				return err
+			},
+			wantErr: ErrDefectNotFound,
+		},
+		{
+			name:    "delete defect",
+			call:    func(actor models.Actor) error { return f.defects.DeleteDefect(actor, "defect-b") },
+			wantErr: ErrDefectNotFound,
+		},
+		{
+			name:    "get asset",
+			call:    func(actor models.Actor) error { _, err := f.assets.GetAsset(actor, "asset-b"); return err },
+			wantErr: ErrAssetNotFound,
+		},
+		{
+			name:    "asset history",
+			call:    func(actor models.Actor) error { _, err := f.assets.GetHistory(actor, "asset-b"); return err },
+			wantErr: ErrAssetNotFound,
+		},
+		{
+			name:    "get device",
+			call:    func(actor models.Actor) error { _, err := f.devices.GetDevice(actor, "dev-b"); return err },
+			wantErr: ErrDeviceNotFound,
+		},
+		{
+			name:    "device heartbeat",
+			call:    func(actor models.Actor) error { return f.devices.Heartbeat(actor, "dev-b") },
+			wantErr: ErrDeviceNotFound,
+		},
+		{
+			name: "reserve from foreign warehouse",
+			call: func(actor models.Actor) error {
+				_, err := f.inventory.Reserve(&models.ReserveStockRequest{
+					WarehouseID: "wh-b", ItemID: "item-1", Quantity: 1,
+					TargetType: models.ReserveForDefect, TargetID: "defect-b", RuID: "ru-b",
+				}, actor)
+				return err
+			},
+			wantErr: ErrWarehouseNotFound,
+		},
+		{
+			name: "reserve for foreign RU",
+			call: func(actor models.Actor) error {
+				_, err := f.inventory.Reserve(&models.ReserveStockRequest{
+					WarehouseID: "wh-a", ItemID: "item-1", Quantity: 1,
+					TargetType: models.ReserveForDefect, TargetID: "defect-b", RuID: "ru-b",
+				}, actor)
+				return err
+			},
+			wantErr: ErrRuNotFound,
+		},
+		{
+			name: "telemetry for foreign RU",
+			call: func(actor models.Actor) error {
+				_, err := f.measurements.Record(actor, measurement("ru-b", f.cellB))
+				return err
+			},
+			wantErr: ErrRuNotFound,
+		},
+		{
+			name: "telemetry for foreign cell under own RU",
+			call: func(actor models.Actor) error {
+				_, err := f.measurements.Record(actor, measurement("ru-a", f.cellB))
+				return err
+			},
+			wantErr: ErrCellNotFound,
+		},
+		{
+			name: "document number for foreign RU",
+			call: func(actor models.Actor) error {
+				_, err := f.numbering.Issue(&models.IssueDocumentNumberRequest{DocumentType: models.DocumentTypePermit, RuID: "ru-b"}, actor)
+				return err
+			},
+			wantErr: ErrRuNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(tenantActorA); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Отказ не должен ничего изменить: владелец и администратор установки видят дефект
+	for _, actor := range []models.Actor{tenantActorB, tenantPlatformAdmin} {
+		defect, err := f.defects.GetDefect(actor, "defect-b")
+		if err != nil {
+			t.Fatalf("%s: get own defect: %v", actor.UserID, err)
+		}
+		if defect.Title != "Defect B" {
+			t.Errorf("%s: defect title = %q, want unchanged", actor.UserID, defect.Title)
+		}
+	}
+	if _, err := f.measurements.Record(tenantPlatformAdmin, measurement("ru-b", f.cellB)); err != nil {
+		t.Errorf("platform admin telemetry: %v", err)
+	}
+}
+
+// collectIDs - отсортированные идентификаторы для сравнения без учета порядка
+func collectIDs[T any](items []T, id func(T) string) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = id(item)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestTenantNumberingSeries(t *testing.T) {
+	f := newTenantFixture(t)
+	issue := func(actor models.Actor, ruID string) string {
+		t.Helper()
+		issued, err := f.numbering.Issue(&models.IssueDocumentNumberRequest{DocumentType: models.DocumentTypePermit, RuID: ruID}, actor)
+		if err != nil {
+			t.Fatalf("issue: %v", err)
+		}
+		return issued.Number
+	}
+
+	// У каждой организации своя серия: первый номер года у обеих одинаковый
+	numberA := issue(tenantActorA, "ru-a")
+	issue(tenantActorA, "ru-a")
+	if numberB := issue(tenantActorB, "ru-b"); numberB != numberA {
+		t.Errorf("first org-b number = %s, want %s", numberB, numberA)
+	}
+
+	// Номер, выданный только другой организации, в РУ организации неизвестен
+	foreign := models.FormatDocumentNumber(time.Now().Year(), 2)
+	if _, err := f.numbering.Check(models.DocumentTypePermit, "ru-b", &foreign); !errors.Is(err, ErrDocumentNumberUnknown) {
+		t.Errorf("check org-a number in org-b: error = %v, want ErrDocumentNumberUnknown", err)
+	}
+	if _, err := f.numbering.Check(models.DocumentTypePermit, "ru-a", &foreign); err != nil {
+		t.Errorf("check own number: %v", err)
+	}
+
+	gaps, err := f.numbering.Gaps(&models.DocumentNumberGapsRequest{DocumentType: models.DocumentTypePermit}, tenantActorB)
+	if err != nil {
+		t.Fatalf("gaps: %v", err)
+	}
+	if gaps.OrganizationID != "org-b" || gaps.Last != 1 || gaps.Issued != 1 {
+		t.Errorf("org-b gaps = %+v, want one issued number of org-b", gaps)
+	}
+}
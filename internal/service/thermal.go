@@ -0,0 +1,294 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// thermalSnapshotsLimit - предел снимков ячейки в ответе и в расчете тренда
+const thermalSnapshotsLimit = 500
+
+// ThermalService - тепловизионный контроль ячеек: снимки обходов с нагретыми точками,
+// оценка превышения над воздухом и над соседней фазой, тренды нагрева и аварии
+// (событие thermal.hotspot), когда превышение достигает порогов настроек thermal.*.
+type ThermalService struct {
+	thermalRepo *repository.ThermalRepository
+	ruRepo      *repository.RuRepository
+	photos      *PhotoService
+	settings    *SettingsService
+}
+
+func NewThermalService(thermalRepo *repository.ThermalRepository, ruRepo *repository.RuRepository, photos *PhotoService, settings *SettingsService) *ThermalService {
+	return &ThermalService{thermalRepo: thermalRepo, ruRepo: ruRepo, photos: photos, settings: settings}
+}
+
+// CreateSnapshot - записывает результаты съемки ячейки. Каждая точка оценивается по
+// превышению над воздухом и, если измерена соседняя фаза, над ней; снимок с точкой
+// выше порога предупреждения поднимает аварию по самой тяжелой точке.
+func (s *ThermalService) CreateSnapshot(ruID string, cellID int, req *models.CreateThermalSnapshotRequest, actor models.Actor) (*models.ThermalSnapshot, error) {
+	cell, err := s.getCell(ruID, cellID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if req.TakenAt.After(now) {
+		return nil, ErrThermalSnapshotInFuture
+	}
+
+	snapshot := &models.ThermalSnapshot{
+		ID:          utils.NewID(models.IDPrefixThermalSnapshot),
+		RuID:        ruID,
+		CellID:      cell.ID,
+		CellNumber:  cell.Number,
+		AmbientTemp: req.AmbientTemp,
+		Band:        models.ThermalNormal,
+		LoadPercent: req.LoadPercent,
+		Emissivity:  req.Emissivity,
+		Camera:      req.Camera,
+		Notes:       req.Notes,
+		TakenBy:     actor.Email,
+		TakenAt:     req.TakenAt,
+		CreatedAt:   now,
+		Photos:      []models.Photo{},
+	}
+
+	worst := -1
+	for i, input := range req.Hotspots {
+		hotspot := models.ThermalHotspot{
+			SnapshotID:    snapshot.ID,
+			Point:         input.Point,
+			Phase:         input.Phase,
+			Temperature:   input.Temperature,
+			ReferenceTemp: input.ReferenceTemp,
+			DeltaAmbient:  round1(input.Temperature - req.AmbientTemp),
+		}
+		if input.ReferenceTemp != nil {
+			delta := round1(input.Temperature - *input.ReferenceTemp)
+			hotspot.DeltaReference = &delta
+		}
+		hotspot.Band = s.hotspotBand(hotspot)
+		snapshot.Hotspots = append(snapshot.Hotspots, hotspot)
+
+		if i == 0 || hotspot.Temperature > snapshot.MaxTemperature {
+			snapshot.MaxTemperature = hotspot.Temperature
+		}
+		if worst < 0 || models.ThermalBandRank[hotspot.Band] > models.ThermalBandRank[snapshot.Hotspots[worst].Band] {
+			worst = i
+		}
+	}
+	snapshot.Band = snapshot.Hotspots[worst].Band
+
+	var events []models.OutboxEvent
+	if snapshot.Band != models.ThermalNormal {
+		hotspot := snapshot.Hotspots[worst]
+		event, err := newEvent(models.EventThermalHotspot, ruID, models.ThermalHotspotPayload{
+			SnapshotID:     snapshot.ID,
+			CellID:         cell.ID,
+			CellNumber:     cell.Number,
+			Point:          hotspot.Point,
+			Temperature:    hotspot.Temperature,
+			DeltaAmbient:   hotspot.DeltaAmbient,
+			DeltaReference: hotspot.DeltaReference,
+			Band:           hotspot.Band,
+			TakenAt:        snapshot.TakenAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.thermalRepo.Create(snapshot, events); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// GetSnapshots - снимки ячейки за период с точками и термограммами
+func (s *ThermalService) GetSnapshots(ruID string, cellID int, filter models.ThermalFilter) ([]models.ThermalSnapshot, error) {
+	if _, err := s.getCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	snapshots, err := s.thermalRepo.GetByCell(cellID, filter, thermalSnapshotsLimit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachPhotos(snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// GetTrend - динамика нагрева: самая нагретая точка каждого снимка и ряды по точкам.
+// Точки сопоставляются по названию и фазе, поэтому на обходах их нужно называть одинаково.
+func (s *ThermalService) GetTrend(ruID string, cellID int, filter models.ThermalFilter) (*models.ThermalTrend, error) {
+	if _, err := s.getCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	snapshots, err := s.thermalRepo.GetByCell(cellID, filter, thermalSnapshotsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	type pointKey struct{ point, phase string }
+	trend := &models.ThermalTrend{CellID: cellID, Max: []models.ThermalTrendSample{}, Points: []models.ThermalPointTrend{}}
+	index := map[pointKey]int{}
+	for _, snapshot := range snapshots {
+		hottest := -1
+		for i, hotspot := range snapshot.Hotspots {
+			sample := thermalSample(snapshot, hotspot)
+			key := pointKey{hotspot.Point, hotspot.Phase}
+			n, ok := index[key]
+			if !ok {
+				n = len(trend.Points)
+				index[key] = n
+				trend.Points = append(trend.Points, models.ThermalPointTrend{Point: hotspot.Point, Phase: hotspot.Phase})
+			}
+			trend.Points[n].Samples = append(trend.Points[n].Samples, sample)
+
+			if hottest < 0 || hotspot.Temperature > snapshot.Hotspots[hottest].Temperature {
+				hottest = i
+			}
+		}
+		if hottest >= 0 {
+			trend.Max = append(trend.Max, thermalSample(snapshot, snapshot.Hotspots[hottest]))
+		}
+	}
+	for i := range trend.Points {
+		trend.Points[i].RatePerMonth = thermalRate(trend.Points[i].Samples)
+	}
+	return trend, nil
+}
+
+// AddPhoto - термограмма к снимку
+func (s *ThermalService) AddPhoto(ruID string, cellID int, snapshotID, fileName string, data []byte, actor models.Actor) (*models.Photo, error) {
+	snapshot, err := s.getSnapshot(ruID, cellID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return s.photos.Save(models.PhotoOwnerThermalSnapshot, snapshot.ID, fileName, data, actor)
+}
+
+// GetPhoto - термограмма снимка или ее миниатюра (thumb)
+func (s *ThermalService) GetPhoto(ruID string, cellID int, snapshotID, photoID string, thumb bool) (*models.Photo, error) {
+	snapshot, err := s.getSnapshot(ruID, cellID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	return s.photos.Get(models.PhotoOwnerThermalSnapshot, snapshot.ID, photoID, thumb)
+}
+
+// hotspotBand - худшая из оценок по превышению над воздухом и над соседней фазой
+func (s *ThermalService) hotspotBand(hotspot models.ThermalHotspot) models.ThermalBand {
+	band := thermalBand(hotspot.DeltaAmbient,
+		float64(s.settings.Int(SettingThermalAmbientWarning)), float64(s.settings.Int(SettingThermalAmbientCritical)))
+	if hotspot.DeltaReference != nil {
+		phase := thermalBand(*hotspot.DeltaReference,
+			float64(s.settings.Int(SettingThermalPhaseWarning)), float64(s.settings.Int(SettingThermalPhaseCritical)))
+		if models.ThermalBandRank[phase] > models.ThermalBandRank[band] {
+			band = phase
+		}
+	}
+	return band
+}
+
+func thermalBand(delta, warning, critical float64) models.ThermalBand {
+	switch {
+	case delta >= critical:
+		return models.ThermalCritical
+	case delta >= warning:
+		return models.ThermalWarning
+	}
+	return models.ThermalNormal
+}
+
+func thermalSample(snapshot models.ThermalSnapshot, hotspot models.ThermalHotspot) models.ThermalTrendSample {
+	return models.ThermalTrendSample{
+		SnapshotID:     snapshot.ID,
+		TakenAt:        snapshot.TakenAt,
+		Temperature:    hotspot.Temperature,
+		DeltaAmbient:   hotspot.DeltaAmbient,
+		DeltaReference: hotspot.DeltaReference,
+		Band:           hotspot.Band,
+	}
+}
+
+// thermalRate - наклон превышения над воздухом по методу наименьших квадратов, °C в месяц.
+// Превышение, а не температура: так ряд не зависит от погоды в день обхода.
+func thermalRate(samples []models.ThermalTrendSample) *float64 {
+	if len(samples) < 2 {
+		return nil
+	}
+	const month = 30 * 24 * time.Hour
+	origin := samples[0].TakenAt
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := float64(sample.TakenAt.Sub(origin)) / float64(month)
+		sumX += x
+		sumY += sample.DeltaAmbient
+		sumXY += x * sample.DeltaAmbient
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return nil
+	}
+	rate := round1((n*sumXY - sumX*sumY) / denominator)
+	return &rate
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func (s *ThermalService) attachPhotos(snapshots []models.ThermalSnapshot) error {
+	ids := make([]string, len(snapshots))
+	index := make(map[string]int, len(snapshots))
+	for i := range snapshots {
+		ids[i] = snapshots[i].ID
+		index[snapshots[i].ID] = i
+		snapshots[i].Photos = []models.Photo{}
+	}
+
+	photos, err := s.photos.GetByOwners(models.PhotoOwnerThermalSnapshot, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get photos: %w", err)
+	}
+	for _, photo := range photos {
+		if i, ok := index[photo.OwnerID]; ok {
+			snapshots[i].Photos = append(snapshots[i].Photos, photo)
+		}
+	}
+	return nil
+}
+
+func (s *ThermalService) getCell(ruID string, cellID int) (*models.Cell, error) {
+	cell, err := s.ruRepo.GetCellByID(cellID, ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	return cell, nil
+}
+
+func (s *ThermalService) getSnapshot(ruID string, cellID int, snapshotID string) (*models.ThermalSnapshot, error) {
+	if _, err := s.getCell(ruID, cellID); err != nil {
+		return nil, err
+	}
+	snapshot, err := s.thermalRepo.GetByID(cellID, utils.NormalizeID(models.IDPrefixThermalSnapshot, snapshotID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrThermalSnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to get thermal snapshot: %w", err)
+	}
+	return snapshot, nil
+}
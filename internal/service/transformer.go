@@ -0,0 +1,460 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+// Тепловая модель IEC 60076-7 (установившийся режим) с параметрами масляных
+// распределительных трансформаторов с естественным охлаждением ONAN
+const (
+	iecOilExponent     = 0.8  // x
+	iecWindingExponent = 1.6  // y
+	iecLossRatio       = 5.0  // R - отношение нагрузочных потерь к потерям холостого хода
+	iecTopOilRise      = 55.0 // Δθor, K - превышение масла над воздухом при номинальной нагрузке
+	iecHotSpotGradient = 23.0 // Hgr, K - превышение наиболее нагретой точки над маслом
+	// iecReferenceHotSpot - температура наиболее нагретой точки, при которой бумага без
+	// термостабилизации стареет с относительной скоростью 1
+	iecReferenceHotSpot = 98.0
+	// iecNormalLifeHours - нормальный срок службы изоляции
+	iecNormalLifeHours = 180000.0
+	// defaultAmbientTemp - температура воздуха без наблюдений погоды
+	defaultAmbientTemp = 20.0
+)
+
+// defaultAgingDays - период расчета износа изоляции по умолчанию
+const defaultAgingDays = 30
+
+// TransformerService - трансформаторы РУ: загрузка относительно номинального тока,
+// температура наиболее нагретой точки и износ изоляции по IEC 60076-7
+type TransformerService struct {
+	transformerRepo *repository.TransformerRepository
+	ruRepo          *repository.RuRepository
+	measurementRepo *repository.MeasurementRepository
+	weatherRepo     *repository.WeatherRepository
+	settings        *SettingsService
+}
+
+func NewTransformerService(transformerRepo *repository.TransformerRepository, ruRepo *repository.RuRepository, measurementRepo *repository.MeasurementRepository, weatherRepo *repository.WeatherRepository, settings *SettingsService) *TransformerService {
+	return &TransformerService{transformerRepo: transformerRepo, ruRepo: ruRepo, measurementRepo: measurementRepo, weatherRepo: weatherRepo, settings: settings}
+}
+
+// GetTransformers - трансформаторы РУ с текущей загрузкой и износом изоляции за период
+func (s *TransformerService) GetTransformers(ruID string, query models.TransformerQuery) ([]models.Transformer, error) {
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	transformers, err := s.transformerRepo.GetByRuID(ruID)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	cellsByID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		cellsByID[cell.ID] = cell
+	}
+
+	days := query.Days
+	if days == 0 {
+		days = defaultAgingDays
+	}
+	now := time.Now()
+	to := now.Truncate(time.Hour)
+	from := to.AddDate(0, 0, -days)
+
+	var observations []models.WeatherObservation
+	if ru.SubstationID != "" {
+		if observations, err = s.weatherRepo.GetObservations(ru.SubstationID, from, now); err != nil {
+			return nil, err
+		}
+	}
+	ambient := hourlyTemperatures(observations)
+
+	for i := range transformers {
+		transformer := &transformers[i]
+		if transformer.Loading, err = s.loading(transformer, cellsByID, observations, now); err != nil {
+			return nil, err
+		}
+		if transformer.Aging, err = s.aging(transformer, ambient, from, to); err != nil {
+			return nil, err
+		}
+	}
+	return transformers, nil
+}
+
+// UpdateTransformer - паспортные данные и привязка к ячейкам; ячейки должны быть из того же РУ.
+// Новое положение переключателя записывается в журнал переключений без причины.
+func (s *TransformerService) UpdateTransformer(ruID, transformerID string, req *models.UpdateTransformerRequest, actor models.Actor) (*models.Transformer, error) {
+	transformer, err := s.getTransformer(ruID, transformerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cellID := range []*int{req.TapCellID, req.HighCellID, req.LowCellID, req.OilTempCellID} {
+		if cellID == nil {
+			continue
+		}
+		if _, err := s.ruRepo.GetCellByID(*cellID, ruID); err != nil {
+			if repository.IsNotFound(err) {
+				return nil, ErrCellNotFound.WithDetails(map[string]interface{}{"cellId": *cellID})
+			}
+			return nil, fmt.Errorf("failed to get cell: %w", err)
+		}
+	}
+
+	if req.RatedPowerKVA != nil {
+		transformer.RatedPowerKVA = *req.RatedPowerKVA
+	}
+	if req.HighVoltageKV != nil {
+		transformer.HighVoltageKV = *req.HighVoltageKV
+	}
+	if req.LowVoltageKV != nil {
+		transformer.LowVoltageKV = *req.LowVoltageKV
+	}
+	if req.TapCellID != nil {
+		transformer.TapCellID = req.TapCellID
+	}
+	if req.HighCellID != nil {
+		transformer.HighCellID = req.HighCellID
+	}
+	if req.LowCellID != nil {
+		transformer.LowCellID = req.LowCellID
+	}
+	if req.OilTempCellID != nil {
+		transformer.OilTempCellID = req.OilTempCellID
+	}
+	transformer.UpdatedAt = time.Now()
+
+	if req.TapPosition != nil && (transformer.TapPosition == nil || *transformer.TapPosition != *req.TapPosition) {
+		if _, err := s.changeTap(transformer, *req.TapPosition, transformer.UpdatedAt, models.TapChangeManual, actor.Email, nil); err != nil {
+			return nil, err
+		}
+		return transformer, nil
+	}
+	if err := s.transformerRepo.Save(transformer); err != nil {
+		return nil, err
+	}
+	return transformer, nil
+}
+
+// SetTapPosition - ручная запись переключения ответвления с причиной; время
+// переключения по умолчанию - текущее
+func (s *TransformerService) SetTapPosition(ruID, transformerID string, req *models.SetTapPositionRequest, actor models.Actor) (*models.TapChange, error) {
+	transformer, err := s.getTransformer(ruID, transformerID)
+	if err != nil {
+		return nil, err
+	}
+	changedAt := time.Now()
+	if req.ChangedAt != nil {
+		if req.ChangedAt.After(changedAt) {
+			return nil, ErrTapChangeInFuture
+		}
+		changedAt = *req.ChangedAt
+	}
+	if transformer.TapChangedAt != nil && changedAt.Before(*transformer.TapChangedAt) {
+		return nil, ErrTapChangeOutOfOrder.WithDetails(map[string]interface{}{"tapChangedAt": *transformer.TapChangedAt})
+	}
+	if transformer.TapPosition != nil && *transformer.TapPosition == *req.Position {
+		return nil, ErrTapPositionUnchanged
+	}
+	reason := req.Reason
+	return s.changeTap(transformer, *req.Position, changedAt, models.TapChangeManual, actor.Email, &reason)
+}
+
+// RecordTapPositions - прием положений переключателей от шлюза телеметрии. Значения
+// обрабатываются по времени измерения; переключением считается только смена положения,
+// значения старше последнего известного переключения отбрасываются. Все РУ пакета
+// должны относиться к организации пользователя.
+func (s *TransformerService) RecordTapPositions(req *models.RecordTapPositionsRequest, actor models.Actor) (*models.TapIngestReport, error) {
+	var ruIDs []string
+	seen := map[string]bool{}
+	for _, input := range req.Positions {
+		if !seen[input.RuID] {
+			seen[input.RuID] = true
+			ruIDs = append(ruIDs, input.RuID)
+		}
+	}
+	if err := checkRusAccess(s.ruRepo, actor, ruIDs); err != nil {
+		return nil, err
+	}
+
+	positions := make([]models.TapPositionInput, len(req.Positions))
+	copy(positions, req.Positions)
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].MeasuredAt.Before(positions[j].MeasuredAt)
+	})
+
+	type tapCellKey struct {
+		ruID   string
+		cellID int
+	}
+	mapped := map[tapCellKey][]*models.Transformer{}
+	report := &models.TapIngestReport{Changes: []models.TapChange{}}
+	for _, input := range positions {
+		key := tapCellKey{input.RuID, input.CellID}
+		transformers, ok := mapped[key]
+		if !ok {
+			found, err := s.transformerRepo.GetByTapCell(input.RuID, input.CellID)
+			if err != nil {
+				return nil, err
+			}
+			for i := range found {
+				transformers = append(transformers, &found[i])
+			}
+			mapped[key] = transformers
+		}
+		if len(transformers) == 0 {
+			report.Unmapped++
+			continue
+		}
+		for _, transformer := range transformers {
+			switch {
+			case transformer.TapChangedAt != nil && input.MeasuredAt.Before(*transformer.TapChangedAt):
+				report.Stale++
+			case transformer.TapPosition != nil && *transformer.TapPosition == *input.Position:
+				report.Unchanged++
+			default:
+				change, err := s.changeTap(transformer, *input.Position, input.MeasuredAt, models.TapChangeTelemetry, actor.Email, nil)
+				if err != nil {
+					return nil, err
+				}
+				report.Recorded++
+				report.Changes = append(report.Changes, *change)
+			}
+		}
+	}
+	return report, nil
+}
+
+// GetTapChanges - журнал переключений ответвлений трансформатора
+func (s *TransformerService) GetTapChanges(ruID, transformerID string, filter models.TapChangeFilter) ([]models.TapChange, error) {
+	transformer, err := s.getTransformer(ruID, transformerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.transformerRepo.GetTapChanges(transformer.ID, filter)
+}
+
+func (s *TransformerService) getTransformer(ruID, transformerID string) (*models.Transformer, error) {
+	transformer, err := s.transformerRepo.GetByID(ruID, utils.NormalizeID(models.IDPrefixTransformer, transformerID))
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrTransformerNotFound
+		}
+		return nil, err
+	}
+	return transformer, nil
+}
+
+// changeTap - записывает переключение и новое положение трансформатора. Переключение,
+// которым число переключений за сутки впервые превысило tap.daily_operations_limit,
+// поднимает событие transformer.tap_excessive - не больше одного за сутки.
+func (s *TransformerService) changeTap(transformer *models.Transformer, position int, at time.Time, source models.TapChangeSource, changedBy string, reason *string) (*models.TapChange, error) {
+	now := time.Now()
+	change := &models.TapChange{
+		TransformerID: transformer.ID,
+		RuID:          transformer.RuID,
+		FromPosition:  transformer.TapPosition,
+		ToPosition:    position,
+		Source:        source,
+		Reason:        reason,
+		ChangedBy:     changedBy,
+		ChangedAt:     at,
+		CreatedAt:     now,
+	}
+
+	local := at.In(time.Local)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	operations, err := s.transformerRepo.CountTapChanges(transformer.ID, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	operations++
+
+	var events []models.OutboxEvent
+	if limit := s.settings.Int(SettingTapDailyLimit); operations == limit+1 {
+		event, err := newEvent(models.EventTapExcessive, transformer.RuID, models.TapExcessivePayload{
+			TransformerID:   transformer.ID,
+			TransformerName: transformer.Name,
+			CellID:          transformer.TapCellOrHigh(),
+			Day:             day.Format("2006-01-02"),
+			Operations:      operations,
+			Limit:           limit,
+			Position:        position,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	transformer.TapPosition = &position
+	transformer.TapChangedAt = &at
+	transformer.UpdatedAt = now
+	if err := s.transformerRepo.SaveTapChange(transformer, change, events); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// loadSide - ячейка, по которой считается загрузка, ее сторона и номинальный ток.
+// Предпочтительна сторона НН: на ней ток больше и измеряется точнее.
+func loadSide(transformer *models.Transformer) (*int, models.VoltageLevel, float64) {
+	if transformer.RatedPowerKVA <= 0 {
+		return nil, "", 0
+	}
+	if transformer.LowCellID != nil && transformer.LowVoltageKV > 0 {
+		return transformer.LowCellID, models.VoltageLevelLow, ratedCurrent(transformer.RatedPowerKVA, transformer.LowVoltageKV)
+	}
+	if transformer.HighCellID != nil && transformer.HighVoltageKV > 0 {
+		return transformer.HighCellID, models.VoltageLevelHigh, ratedCurrent(transformer.RatedPowerKVA, transformer.HighVoltageKV)
+	}
+	return nil, "", 0
+}
+
+// ratedCurrent - номинальный ток трехфазной обмотки, А
+func ratedCurrent(kva, kv float64) float64 {
+	return kva / (math.Sqrt(3) * kv)
+}
+
+// loading - текущая загрузка по свежей телеметрии, а без нее - по значениям в карточке ячейки
+func (s *TransformerService) loading(transformer *models.Transformer, cells map[int]models.Cell, observations []models.WeatherObservation, now time.Time) (*models.TransformerLoading, error) {
+	cellID, side, rated := loadSide(transformer)
+	if cellID == nil {
+		return nil, nil
+	}
+	cell, ok := cells[*cellID]
+	if !ok {
+		return nil, nil
+	}
+
+	current, measuredAt, err := s.latest(cell.ID, models.MetricCurrent, now)
+	if err != nil {
+		return nil, err
+	}
+	if measuredAt == nil {
+		if cell.Current == nil {
+			return nil, nil
+		}
+		current = *cell.Current
+	}
+
+	k := current / rated
+	loading := &models.TransformerLoading{
+		Side:          side,
+		CurrentA:      round1(current),
+		RatedCurrentA: round1(rated),
+		LoadFactor:    math.Round(k*1000) / 1000,
+		Percent:       round1(k * 100),
+		MeasuredAt:    measuredAt,
+		AmbientTemp:   defaultAmbientTemp,
+	}
+	if n := len(observations); n > 0 && now.Sub(observations[n-1].ObservedAt) <= 3*time.Hour {
+		loading.AmbientTemp = observations[n-1].Temperature
+	} else {
+		loading.AmbientAssumed = true
+	}
+
+	topOil := topOilTemperature(loading.AmbientTemp, k)
+	if transformer.OilTempCellID != nil {
+		oil, oilAt, err := s.latest(*transformer.OilTempCellID, models.MetricTemperature, now)
+		if err != nil {
+			return nil, err
+		}
+		if oilAt != nil {
+			topOil, loading.TopOilMeasured = oil, true
+		}
+	}
+	hotSpot := hotSpotTemperature(topOil, k)
+	loading.TopOilTemp = round1(topOil)
+	loading.HotSpotTemp = round1(hotSpot)
+	loading.AgingRate = math.Round(agingRate(hotSpot)*1000) / 1000
+	return loading, nil
+}
+
+// aging - износ изоляции за [from, to) по часовым агрегатам тока. Температура масла -
+// часовая из телеметрии, а без нее - расчетная по температуре воздуха за тот же час.
+func (s *TransformerService) aging(transformer *models.Transformer, ambient map[int64]float64, from, to time.Time) (*models.TransformerAging, error) {
+	cellID, _, rated := loadSide(transformer)
+	if cellID == nil {
+		return nil, nil
+	}
+	currents, err := s.measurementRepo.GetRollups(models.Resolution1h, *cellID, models.MetricCurrent, from, to)
+	if err != nil {
+		return nil, err
+	}
+	oil := map[int64]float64{}
+	if transformer.OilTempCellID != nil {
+		rollups, err := s.measurementRepo.GetRollups(models.Resolution1h, *transformer.OilTempCellID, models.MetricTemperature, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, rollup := range rollups {
+			oil[rollup.BucketStart.Unix()] = rollup.Avg
+		}
+	}
+
+	aging := &models.TransformerAging{From: from, To: to}
+	var lossOfLife float64
+	for _, rollup := range currents {
+		hour := rollup.BucketStart.Unix()
+		k := rollup.Avg / rated
+		topOil, ok := oil[hour]
+		if !ok {
+			temperature, ok := ambient[hour]
+			if !ok {
+				temperature = defaultAmbientTemp
+			}
+			topOil = topOilTemperature(temperature, k)
+		}
+		lossOfLife += agingRate(hotSpotTemperature(topOil, k))
+		aging.Hours++
+	}
+	if aging.Hours > 0 {
+		aging.EquivalentAging = math.Round(lossOfLife/float64(aging.Hours)*1000) / 1000
+	}
+	aging.LossOfLifeHours = round1(lossOfLife)
+	aging.LossOfLifePercent = math.Round(lossOfLife/iecNormalLifeHours*100*10000) / 10000
+	return aging, nil
+}
+
+// latest - последнее минутное значение метрики ячейки не старше utilizationFreshness
+func (s *TransformerService) latest(cellID int, metric models.MeasurementMetric, now time.Time) (float64, *time.Time, error) {
+	rollups, err := s.measurementRepo.GetRollups(models.Resolution1m, cellID, metric, now.Add(-utilizationFreshness), now)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(rollups) == 0 {
+		return 0, nil, nil
+	}
+	last := rollups[len(rollups)-1]
+	return last.Avg, &last.BucketStart, nil
+}
+
+// topOilTemperature - установившаяся температура верхних слоев масла при загрузке k
+func topOilTemperature(ambient, k float64) float64 {
+	return ambient + iecTopOilRise*math.Pow((1+iecLossRatio*k*k)/(1+iecLossRatio), iecOilExponent)
+}
+
+// hotSpotTemperature - температура наиболее нагретой точки обмотки
+func hotSpotTemperature(topOil, k float64) float64 {
+	return topOil + iecHotSpotGradient*math.Pow(k, iecWindingExponent)
+}
+
+// agingRate - относительная скорость старения бумаги без термостабилизации:
+// удваивается на каждые 6 K выше 98 °C
+func agingRate(hotSpot float64) float64 {
+	return math.Pow(2, (hotSpot-iecReferenceHotSpot)/6)
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/pkg/utils"
+)
+
+const (
+	// visionReadingsLimit - предел показаний и индикаций в ответе для карточки ячейки
+	visionReadingsLimit = 500
+	// visionReconcileDelay - индикация сверяется не раньше, чем через это время после
+	// снимка: переключение, выполненное на панели, успевает попасть в журнал
+	visionReconcileDelay = 10 * time.Minute
+	// visionReconcileBatch - индикаций за одну выборку задачи сверки
+	visionReconcileBatch = 1000
+)
+
+// VisionService - прием показаний приборов, распознанных сервисом компьютерного зрения.
+// Показания с уверенностью не ниже порога становятся измерениями телеметрии и дальше
+// обрабатываются как данные шлюза (агрегаты, аномалии, аварии). Положения ячеек по
+// лампам-индикаторам сверяются со статусами в журнале задачей vision-reconciliation.
+type VisionService struct {
+	visionRepo *repository.VisionRepository
+	ruRepo     *repository.RuRepository
+	settings   *SettingsService
+}
+
+func NewVisionService(visionRepo *repository.VisionRepository, ruRepo *repository.RuRepository, settings *SettingsService) *VisionService {
+	return &VisionService{visionRepo: visionRepo, ruRepo: ruRepo, settings: settings}
+}
+
+// Record - принимает пакет показаний. Ячейка каждого показания должна принадлежать
+// указанному РУ организации пользователя, иначе пакет отклоняется целиком. Повторно переданные показания
+// (тот же ExternalID) не записываются и возвращаются как ранее принятые.
+func (s *VisionService) Record(actor models.Actor, req *models.RecordVisionReadingsRequest) (*models.VisionIngestReport, error) {
+	refs := make([]cellRef, len(req.Readings))
+	for i, input := range req.Readings {
+		refs[i] = cellRef{ruID: input.RuID, cellID: input.CellID}
+	}
+	if err := checkCellRefs(s.ruRepo, actor, refs); err != nil {
+		return nil, err
+	}
+
+	var externalIDs []string
+	for _, input := range req.Readings {
+		if input.ExternalID != nil {
+			externalIDs = append(externalIDs, *input.ExternalID)
+		}
+	}
+	existing, err := s.visionRepo.GetByExternalIDs(externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, reading := range existing {
+		known[*reading.ExternalID] = true
+	}
+
+	minConfidence := float64(s.settings.Int(SettingVisionMinConfidence)) / 100
+	report := &models.VisionIngestReport{MinConfidence: minConfidence, Readings: []models.VisionReading{}}
+
+	now := time.Now()
+	var readings []models.VisionReading
+	var measurements []models.Measurement
+	var accepted []int
+	for _, input := range req.Readings {
+		if input.ExternalID != nil {
+			if known[*input.ExternalID] {
+				report.Duplicates++
+				continue
+			}
+			// Дубликат внутри одного пакета
+			known[*input.ExternalID] = true
+		}
+
+		reading := models.VisionReading{
+			ID:           utils.NewID(models.IDPrefixVisionReading),
+			RuID:         input.RuID,
+			CellID:       input.CellID,
+			Metric:       input.Metric,
+			Gauge:        input.Gauge,
+			Value:        input.Value,
+			Confidence:   input.Confidence,
+			ImageRef:     input.ImageRef,
+			ModelVersion: input.ModelVersion,
+			CapturedAt:   input.CapturedAt,
+			ExternalID:   input.ExternalID,
+			Accepted:     input.Confidence >= minConfidence,
+			CreatedAt:    now,
+		}
+		if reading.Accepted {
+			accepted = append(accepted, len(readings))
+			measurements = append(measurements, models.Measurement{
+				RuID:       reading.RuID,
+				CellID:     reading.CellID,
+				Metric:     reading.Metric,
+				Value:      reading.Value,
+				MeasuredAt: reading.CapturedAt,
+			})
+			report.Accepted++
+		} else {
+			report.BelowThreshold++
+		}
+		readings = append(readings, reading)
+	}
+
+	if len(readings) > 0 {
+		if err := s.visionRepo.Create(readings, measurements, accepted); err != nil {
+			return nil, err
+		}
+		report.Readings = readings
+	}
+	return report, nil
+}
+
+// GetCellReadings - распознанные показания ячейки, включая отсеянные по уверенности
+func (s *VisionService) GetCellReadings(ruID string, cellID int, filter models.VisionReadingFilter) ([]models.VisionReading, error) {
+	if _, err := s.ruRepo.GetCellByID(cellID, ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	return s.visionRepo.GetByCell(cellID, filter, visionReadingsLimit)
+}
+
+// RecordIndications - принимает пакет положений ячеек по индикаторам. Проверка ячеек,
+// порог уверенности и отсев повторов - как у показаний приборов.
+func (s *VisionService) RecordIndications(actor models.Actor, req *models.RecordVisionIndicationsRequest) (*models.VisionIndicationReport, error) {
+	refs := make([]cellRef, len(req.Indications))
+	for i, input := range req.Indications {
+		refs[i] = cellRef{ruID: input.RuID, cellID: input.CellID}
+	}
+	if err := checkCellRefs(s.ruRepo, actor, refs); err != nil {
+		return nil, err
+	}
+
+	var externalIDs []string
+	for _, input := range req.Indications {
+		if input.ExternalID != nil {
+			externalIDs = append(externalIDs, *input.ExternalID)
+		}
+	}
+	existing, err := s.visionRepo.GetIndicationsByExternalIDs(externalIDs)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, indication := range existing {
+		known[*indication.ExternalID] = true
+	}
+
+	minConfidence := float64(s.settings.Int(SettingVisionMinConfidence)) / 100
+	report := &models.VisionIndicationReport{MinConfidence: minConfidence, Indications: []models.VisionIndication{}}
+
+	now := time.Now()
+	var indications []models.VisionIndication
+	for _, input := range req.Indications {
+		if input.ExternalID != nil {
+			if known[*input.ExternalID] {
+				report.Duplicates++
+				continue
+			}
+			known[*input.ExternalID] = true
+		}
+
+		indication := models.VisionIndication{
+			ID:           utils.NewID(models.IDPrefixVisionIndication),
+			RuID:         input.RuID,
+			CellID:       input.CellID,
+			Indication:   input.Indication,
+			Confidence:   input.Confidence,
+			ImageRef:     input.ImageRef,
+			ModelVersion: input.ModelVersion,
+			CapturedAt:   input.CapturedAt,
+			ExternalID:   input.ExternalID,
+			Accepted:     input.Confidence >= minConfidence,
+			CreatedAt:    now,
+		}
+		if indication.Accepted {
+			report.Accepted++
+		} else {
+			report.BelowThreshold++
+		}
+		indications = append(indications, indication)
+	}
+
+	if len(indications) > 0 {
+		if err := s.visionRepo.CreateIndications(indications); err != nil {
+			return nil, err
+		}
+		report.Indications = indications
+	}
+	return report, nil
+}
+
+// GetCellIndications - индикации ячейки с результатами сверки
+func (s *VisionService) GetCellIndications(ruID string, cellID int, filter models.VisionIndicationFilter) ([]models.VisionIndication, error) {
+	if _, err := s.ruRepo.GetCellByID(cellID, ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+	return s.visionRepo.GetIndicationsByCell(cellID, filter, visionReadingsLimit)
+}
+
+// Reconcile - сверяет принятые индикации со статусами ячеек в журнале. Индикация,
+// после которой по ячейке уже была операция, считается устаревшей и не сравнивается.
+// Событие vision.discrepancy поднимается только при переходе ячейки в расхождение:
+// пока журнал и панель расходятся, последующие снимки не дублируют аварию.
+func (s *VisionService) Reconcile(ctx context.Context, now time.Time) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		indications, err := s.visionRepo.GetUnreconciledIndications(now.Add(-visionReconcileDelay), visionReconcileBatch)
+		if err != nil {
+			return err
+		}
+		if len(indications) == 0 {
+			return nil
+		}
+		if err := s.reconcileBatch(indications, now); err != nil {
+			return err
+		}
+		if len(indications) < visionReconcileBatch {
+			return nil
+		}
+	}
+}
+
+func (s *VisionService) reconcileBatch(indications []models.VisionIndication, now time.Time) error {
+	var ruIDs []string
+	var cellIDs []int
+	seenRu := map[string]bool{}
+	seenCell := map[int]bool{}
+	for _, indication := range indications {
+		if !seenRu[indication.RuID] {
+			seenRu[indication.RuID] = true
+			ruIDs = append(ruIDs, indication.RuID)
+		}
+		if !seenCell[indication.CellID] {
+			seenCell[indication.CellID] = true
+			cellIDs = append(cellIDs, indication.CellID)
+		}
+	}
+
+	cells, err := s.ruRepo.GetCellsByRuIDs(ruIDs)
+	if err != nil {
+		return err
+	}
+	cellByID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		cellByID[cell.ID] = cell
+	}
+	compared, err := s.visionRepo.GetLastCompared(cellIDs)
+	if err != nil {
+		return err
+	}
+	lastResult := make(map[int]models.IndicationResult, len(compared))
+	for _, indication := range compared {
+		lastResult[indication.CellID] = indication.Result
+	}
+
+	var events []models.OutboxEvent
+	for i := range indications {
+		indication := &indications[i]
+		indication.ReconciledAt = &now
+
+		cell, ok := cellByID[indication.CellID]
+		if !ok || cell.RuID != indication.RuID {
+			indication.Result = models.IndicationSkipped
+			continue
+		}
+		indication.RecordedStatus = cell.Status
+		if cell.LastOperationAt != nil && cell.LastOperationAt.After(indication.CapturedAt) {
+			indication.Result = models.IndicationStale
+			continue
+		}
+		expected, comparable := expectedIndication(cell.Status)
+		if !comparable {
+			indication.Result = models.IndicationSkipped
+			continue
+		}
+
+		if expected == indication.Indication {
+			indication.Result = models.IndicationMatch
+		} else {
+			indication.Result = models.IndicationMismatch
+			if lastResult[cell.ID] != models.IndicationMismatch {
+				event, err := newEvent(models.EventVisionDiscrepancy, cell.RuID, models.VisionDiscrepancyPayload{
+					IndicationID: indication.ID,
+					CellID:       cell.ID,
+					CellNumber:   cell.Number,
+					CellName:     cell.Name,
+					Observed:     indication.Indication,
+					Recorded:     cell.Status,
+					ImageRef:     indication.ImageRef,
+					CapturedAt:   indication.CapturedAt,
+				})
+				if err != nil {
+					return err
+				}
+				events = append(events, event)
+			}
+		}
+		lastResult[cell.ID] = indication.Result
+	}
+
+	return s.visionRepo.SaveReconciliation(indications, events)
+}
+
+// expectedIndication - положение индикаторов, соответствующее статусу в журнале.
+// Резерв и ремонт - выключатель отключен; статус ERROR не сравнивается.
+func expectedIndication(status models.CellStatus) (models.CellStatus, bool) {
+	switch status {
+	case models.CellStatusON:
+		return models.CellStatusON, true
+	case models.CellStatusOFF, models.CellStatusReserve, models.CellStatusMaintenance:
+		return models.CellStatusOFF, true
+	}
+	return "", false
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+)
+
+const (
+	// voltageCriticalPercent - отклонение, при котором авария по напряжению критическая
+	// (в том числе потеря фазы)
+	voltageCriticalPercent = 20.0
+	// maxVoltageReportDays - самый длинный период отчета о качестве напряжения
+	maxVoltageReportDays = 93
+)
+
+// VoltageService - журнал качества напряжения 0,4 кВ: фазные напряжения ячеек стороны НН
+// от шлюза телеметрии, суточные сводки для отчетности и аварии (событие voltage.deviation)
+// при выходе за ±10 % номинала.
+type VoltageService struct {
+	voltageRepo *repository.VoltageRepository
+	ruRepo      *repository.RuRepository
+}
+
+func NewVoltageService(voltageRepo *repository.VoltageRepository, ruRepo *repository.RuRepository) *VoltageService {
+	return &VoltageService{voltageRepo: voltageRepo, ruRepo: ruRepo}
+}
+
+// Record - пакет фазных напряжений; все ячейки должны быть стороны НН в РУ организации
+// пользователя. Авария
+// поднимается при переходе ячейки из допустимого диапазона за его пределы; значения
+// не новее последнего сохраненного измерения ячейки сохраняются без событий.
+func (s *VoltageService) Record(actor models.Actor, req *models.RecordVoltagesRequest) (int, error) {
+	refs := make([]cellRef, len(req.Measurements))
+	for i, input := range req.Measurements {
+		refs[i] = cellRef{ruID: input.RuID, cellID: input.CellID}
+	}
+	if err := checkCellRefs(s.ruRepo, actor, refs); err != nil {
+		return 0, err
+	}
+
+	cells := map[int]*models.Cell{}
+	var cellIDs []int
+	for _, input := range req.Measurements {
+		if _, ok := cells[input.CellID]; ok {
+			continue
+		}
+		cell, err := s.ruRepo.GetCellByID(input.CellID, input.RuID)
+		if err != nil {
+			if repository.IsNotFound(err) {
+				return 0, ErrCellNotFound.WithDetails(map[string]interface{}{"cellId": input.CellID})
+			}
+			return 0, fmt.Errorf("failed to get cell: %w", err)
+		}
+		if cell.VoltageLevel != models.VoltageLevelLow {
+			return 0, ErrVoltageCellNotLowSide.WithDetails(map[string]interface{}{"cellId": input.CellID, "voltageLevel": cell.VoltageLevel})
+		}
+		cells[input.CellID] = cell
+		cellIDs = append(cellIDs, input.CellID)
+	}
+	last, err := s.voltageRepo.GetLast(cellIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	inputs := make([]models.VoltageInput, len(req.Measurements))
+	copy(inputs, req.Measurements)
+	sort.SliceStable(inputs, func(i, j int) bool {
+		return inputs[i].MeasuredAt.Before(inputs[j].MeasuredAt)
+	})
+
+	now := time.Now()
+	low, high := voltageLimits()
+	measurements := make([]models.VoltageMeasurement, len(inputs))
+	var events []models.OutboxEvent
+	for i, input := range inputs {
+		measurement := models.VoltageMeasurement{
+			CellID:     input.CellID,
+			MeasuredAt: input.MeasuredAt,
+			RuID:       input.RuID,
+			PhaseA:     *input.PhaseA,
+			PhaseB:     *input.PhaseB,
+			PhaseC:     *input.PhaseC,
+			CreatedAt:  now,
+		}
+		phase, deviation := worstVoltagePhase(&measurement)
+		voltage := measurement.Phase(phase)
+		measurement.OutOfRange = voltage < low || voltage > high
+		measurements[i] = measurement
+
+		prev, ok := last[input.CellID]
+		if ok && !input.MeasuredAt.After(prev.MeasuredAt) {
+			continue
+		}
+		last[input.CellID] = measurement
+		if !measurement.OutOfRange || (ok && prev.OutOfRange) {
+			continue
+		}
+		cell := cells[input.CellID]
+		event, err := newEvent(models.EventVoltageDeviation, input.RuID, models.VoltageDeviationPayload{
+			CellID:           cell.ID,
+			CellNumber:       cell.Number,
+			Phase:            phase,
+			Voltage:          voltage,
+			DeviationPercent: deviation,
+			MeasuredAt:       input.MeasuredAt,
+		})
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, event)
+	}
+
+	if err := s.voltageRepo.Save(measurements, events); err != nil {
+		return 0, err
+	}
+	return len(measurements), nil
+}
+
+// GetDailySummaries - суточные сводки напряжения ячеек РУ за период [from, to] (даты
+// включительно) в порядке дат и ячеек. Сутки без измерений в сводку не попадают.
+func (s *VoltageService) GetDailySummaries(ruID string, query models.VoltageQualityQuery) ([]models.VoltageDailySummary, error) {
+	from, err := time.ParseInLocation(summaryDateLayout, query.From, time.Local)
+	if err != nil {
+		return nil, ErrVoltageReportRangeInvalid
+	}
+	to, err := time.ParseInLocation(summaryDateLayout, query.To, time.Local)
+	if err != nil || to.Before(from) || to.After(from.AddDate(0, 0, maxVoltageReportDays-1)) {
+		return nil, ErrVoltageReportRangeInvalid
+	}
+	if _, err := s.ruRepo.GetRuByID(ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	cells, err := s.ruRepo.GetCellsByRuID(ruID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cells: %w", err)
+	}
+	cellsByID := make(map[int]models.Cell, len(cells))
+	for _, cell := range cells {
+		cellsByID[cell.ID] = cell
+	}
+
+	low, high := voltageLimits()
+	summaries := []models.VoltageDailySummary{}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		daily, err := s.voltageRepo.GetStats(ruID, query.CellID, day, day.AddDate(0, 0, 1), low, high)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range daily {
+			summary.Date = day.Format(summaryDateLayout)
+			if cell, ok := cellsByID[summary.CellID]; ok {
+				summary.CellNumber, summary.CellName = cell.Number, cell.Name
+			}
+			summary.Compliant = true
+			for i := range summary.Phases {
+				phase := &summary.Phases[i]
+				phase.Min, phase.Max, phase.Avg = round1(phase.Min), round1(phase.Max), round1(phase.Avg)
+				phase.WithinPercent = math.Round(float64(summary.Samples-phase.OutOfRange)/float64(summary.Samples)*1000) / 10
+				if phase.OutOfRange > 0 {
+					summary.Compliant = false
+				}
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries, nil
+}
+
+// voltageLimits - допустимый диапазон фазного напряжения, В
+func voltageLimits() (float64, float64) {
+	delta := models.NominalPhaseVoltage * models.VoltageTolerancePercent / 100
+	return models.NominalPhaseVoltage - delta, models.NominalPhaseVoltage + delta
+}
+
+// worstVoltagePhase - фаза с наибольшим отклонением от номинала и отклонение, %
+func worstVoltagePhase(measurement *models.VoltageMeasurement) (string, float64) {
+	worst, deviation := "", 0.0
+	for _, phase := range models.VoltagePhases {
+		d := (measurement.Phase(phase) - models.NominalPhaseVoltage) / models.NominalPhaseVoltage * 100
+		if worst == "" || math.Abs(d) > math.Abs(deviation) {
+			worst, deviation = phase, d
+		}
+	}
+	return worst, round1(deviation)
+}
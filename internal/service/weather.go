@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Temoojeen/sez-vision-backend/internal/models"
+	"github.com/Temoojeen/sez-vision-backend/internal/repository"
+	"github.com/Temoojeen/sez-vision-backend/internal/weather"
+)
+
+// maxWeatherSpan - самый длинный период ряда нагрузка/температура (сезон целиком)
+const maxWeatherSpan = 366 * 24 * time.Hour
+
+// WeatherService - температура воздуха у подстанций: опрос провайдера погоды по координатам
+// подстанции (задача weather-poll) или ручной ввод, и сопоставление с нагрузкой ячеек
+type WeatherService struct {
+	weatherRepo     *repository.WeatherRepository
+	ruRepo          *repository.RuRepository
+	measurementRepo *repository.MeasurementRepository
+	ruService       *RuService
+	provider        weather.Provider
+}
+
+func NewWeatherService(weatherRepo *repository.WeatherRepository, ruRepo *repository.RuRepository, measurementRepo *repository.MeasurementRepository, ruService *RuService, provider weather.Provider) *WeatherService {
+	return &WeatherService{
+		weatherRepo:     weatherRepo,
+		ruRepo:          ruRepo,
+		measurementRepo: measurementRepo,
+		ruService:       ruService,
+		provider:        provider,
+	}
+}
+
+// ProviderEnabled - настроен ли внешний провайдер погоды
+func (s *WeatherService) ProviderEnabled() bool {
+	return s.provider != nil
+}
+
+// Record - ручной ввод наблюдений; подстанции должны быть доступны пользователю
+func (s *WeatherService) Record(actor models.Actor, req *models.RecordWeatherRequest) (int, error) {
+	checked := map[string]bool{}
+	observations := make([]models.WeatherObservation, len(req.Observations))
+	for i, input := range req.Observations {
+		if !checked[input.SubstationID] {
+			if _, err := s.ruService.GetSubstationFor(actor, input.SubstationID); err != nil {
+				return 0, err
+			}
+			checked[input.SubstationID] = true
+		}
+		observations[i] = models.WeatherObservation{
+			SubstationID: input.SubstationID,
+			ObservedAt:   input.ObservedAt,
+			Temperature:  *input.Temperature,
+			Humidity:     input.Humidity,
+			Source:       models.WeatherSourceManual,
+		}
+	}
+	if err := s.weatherRepo.SaveObservations(observations); err != nil {
+		return 0, err
+	}
+	return len(observations), nil
+}
+
+// Poll - текущая погода для всех подстанций с координатами. Ошибка по одной подстанции
+// не прерывает опрос остальных.
+func (s *WeatherService) Poll(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	substations, err := s.ruRepo.GetSubstations("")
+	if err != nil {
+		return err
+	}
+
+	var observations []models.WeatherObservation
+	failed := 0
+	for _, substation := range substations {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if substation.Latitude == nil || substation.Longitude == nil {
+			continue
+		}
+		reading, err := s.provider.Current(ctx, *substation.Latitude, *substation.Longitude)
+		if err != nil {
+			log.Printf("⚠️ Weather for substation %s: %v", substation.ID, err)
+			failed++
+			continue
+		}
+		observations = append(observations, models.WeatherObservation{
+			SubstationID: substation.ID,
+			ObservedAt:   reading.ObservedAt,
+			Temperature:  reading.Temperature,
+			Humidity:     reading.Humidity,
+			Source:       s.provider.Name(),
+		})
+	}
+
+	if len(observations) > 0 {
+		if err := s.weatherRepo.SaveObservations(observations); err != nil {
+			return err
+		}
+	}
+	if failed > 0 && len(observations) == 0 {
+		return fmt.Errorf("weather provider failed for %d substations", failed)
+	}
+	return nil
+}
+
+// GetObservations - наблюдения у подстанции за период
+func (s *WeatherService) GetObservations(actor models.Actor, substationID string, from, to time.Time) ([]models.WeatherObservation, error) {
+	if !to.After(from) || to.Sub(from) > maxWeatherSpan {
+		return nil, ErrMeasurementRangeInvalid
+	}
+	if _, err := s.ruService.GetSubstationFor(actor, substationID); err != nil {
+		return nil, err
+	}
+	return s.weatherRepo.GetObservations(substationID, from, to)
+}
+
+// LoadVsTemperature - часовые агрегаты нагрузки (или тока) ячейки рядом со средней
+// температурой воздуха у подстанции РУ за тот же час
+func (s *WeatherService) LoadVsTemperature(ruID string, cellID int, metric models.MeasurementMetric, from, to time.Time) (*models.LoadTemperatureSeries, error) {
+	if !to.After(from) || to.Sub(from) > maxWeatherSpan {
+		return nil, ErrMeasurementRangeInvalid
+	}
+	switch metric {
+	case models.MetricCurrent, models.MetricLoad:
+	default:
+		return nil, ErrMeasurementMetricInvalid
+	}
+
+	ru, err := s.ruRepo.GetRuByID(ruID)
+	if err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrRuNotFound
+		}
+		return nil, fmt.Errorf("failed to get RU: %w", err)
+	}
+	if _, err := s.ruRepo.GetCellByID(cellID, ruID); err != nil {
+		if repository.IsNotFound(err) {
+			return nil, ErrCellNotFound
+		}
+		return nil, fmt.Errorf("failed to get cell: %w", err)
+	}
+
+	from, to = from.Truncate(time.Hour), to.Truncate(time.Hour).Add(time.Hour)
+	rollups, err := s.measurementRepo.GetRollups(models.Resolution1h, cellID, metric, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	temperatures := map[int64]float64{}
+	if ru.SubstationID != "" {
+		observations, err := s.weatherRepo.GetObservations(ru.SubstationID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		temperatures = hourlyTemperatures(observations)
+	}
+
+	points := make([]models.LoadTemperaturePoint, 0, len(rollups))
+	for _, rollup := range rollups {
+		point := models.LoadTemperaturePoint{Hour: rollup.BucketStart, Avg: rollup.Avg, Max: rollup.Max}
+		if temperature, ok := temperatures[rollup.BucketStart.Truncate(time.Hour).Unix()]; ok {
+			point.Temperature = &temperature
+		}
+		points = append(points, point)
+	}
+
+	return &models.LoadTemperatureSeries{
+		RuID:         ruID,
+		CellID:       cellID,
+		SubstationID: ru.SubstationID,
+		Metric:       metric,
+		From:         from,
+		To:           to,
+		Points:       points,
+	}, nil
+}
+
+// hourlyTemperatures - средняя температура по часам (ключ - начало часа, unix)
+func hourlyTemperatures(observations []models.WeatherObservation) map[int64]float64 {
+	sums := map[int64]float64{}
+	counts := map[int64]int{}
+	for _, observation := range observations {
+		hour := observation.ObservedAt.Truncate(time.Hour).Unix()
+		sums[hour] += observation.Temperature
+		counts[hour]++
+	}
+	for hour, sum := range sums {
+		sums[hour] = sum / float64(counts[hour])
+	}
+	return sums
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	TypeNone = ""
+	TypeFS   = "fs"
+	TypeS3   = "s3"
+)
+
+// ErrNotFound - объекта с таким ключом нет в хранилище
+var ErrNotFound = errors.New("storage: object not found")
+
+// Store - объектное хранилище для оригиналов фотографий
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New - хранилище выбранного типа. Для пустого типа возвращает nil:
+// оригиналы фотографий хранятся в базе данных, как раньше.
+func New(storageType, url string) (Store, error) {
+	switch strings.ToLower(storageType) {
+	case TypeNone:
+		return nil, nil
+	case TypeFS:
+		return NewFileStore(url)
+	case TypeS3:
+		return NewS3Store(url)
+	default:
+		return nil, fmt.Errorf("unknown storage type %q", storageType)
+	}
+}
+
+// validKey - ключ из сегментов без "." и ".." (ключи формирует сервер, а не клиент)
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// ContentType - миниатюры всегда кодируются в JPEG
+const ContentType = "image/jpeg"
+
+// ErrUnsupported - формат не декодируется стандартной библиотекой (например, WebP)
+var ErrUnsupported = errors.New("thumbnail: unsupported image format")
+
+// Result - миниатюра и размеры исходного изображения с учетом поворота
+type Result struct {
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// Make - миниатюра со стороной не больше maxSide. orientation - поворот из EXIF (1-8),
+// миниатюра поворачивается так, как кадр должен отображаться.
+func Make(data []byte, maxSide, orientation int) (*Result, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrUnsupported
+		}
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("empty image")
+	}
+	scale := 1.0
+	if side := max(width, height); side > maxSide {
+		scale = float64(maxSide) / float64(side)
+	}
+	dst := downscale(src, max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale)))
+	oriented := orient(dst, orientation)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, oriented, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+
+	result := &Result{Data: buf.Bytes(), Width: width, Height: height}
+	if orientation >= 5 {
+		result.Width, result.Height = height, width
+	}
+	return result, nil
+}
+
+// downscale - усреднение по блокам исходного изображения; из большого блока берется
+// не больше 4x4 точек, чтобы снимок с камеры не обрабатывался слишком долго
+func downscale(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy += max(1, (y1-y0)/4) {
+				for sx := x0; sx < x1; sx += max(1, (x1-x0)/4) {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// orient - поворот и отражение по тегу EXIF Orientation
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.SetRGBA(dx, dy, src.RGBAAt(x, y))
+		}
+	}
+	return dst
+}
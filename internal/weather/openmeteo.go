@@ -0,0 +1,85 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const openMeteoDefaultURL = "https://api.open-meteo.com"
+
+// OpenMeteoProvider - текущая температура и влажность из API Open-Meteo (без ключа)
+type OpenMeteoProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOpenMeteoProvider(baseURL string) *OpenMeteoProvider {
+	if baseURL == "" {
+		baseURL = openMeteoDefaultURL
+	}
+	return &OpenMeteoProvider{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OpenMeteoProvider) Name() string {
+	return TypeOpenMeteo
+}
+
+type openMeteoResponse struct {
+	Current struct {
+		Time               int64    `json:"time"`
+		Temperature2m      *float64 `json:"temperature_2m"`
+		RelativeHumidity2m *float64 `json:"relative_humidity_2m"`
+	} `json:"current"`
+}
+
+func (p *OpenMeteoProvider) Current(ctx context.Context, latitude, longitude float64) (*Reading, error) {
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(latitude, 'f', 5, 64))
+	query.Set("longitude", strconv.FormatFloat(longitude, 'f', 5, 64))
+	query.Set("current", "temperature_2m,relative_humidity_2m")
+	query.Set("timeformat", "unixtime")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v1/forecast?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Open-Meteo request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Open-Meteo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("open-meteo returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var body openMeteoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode Open-Meteo response: %w", err)
+	}
+	if body.Current.Temperature2m == nil {
+		return nil, fmt.Errorf("open-meteo response has no temperature")
+	}
+
+	observedAt := time.Now()
+	if body.Current.Time > 0 {
+		observedAt = time.Unix(body.Current.Time, 0)
+	}
+	return &Reading{
+		Temperature: *body.Current.Temperature2m,
+		Humidity:    body.Current.RelativeHumidity2m,
+		ObservedAt:  observedAt,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TypeNone      = ""
+	TypeOpenMeteo = "open-meteo"
+)
+
+// Reading - текущая погода в точке
+type Reading struct {
+	Temperature float64
+	Humidity    *float64
+	ObservedAt  time.Time
+}
+
+// Provider - источник погодных данных по координатам
+type Provider interface {
+	Name() string
+	Current(ctx context.Context, latitude, longitude float64) (*Reading, error)
+}
+
+// New - создает провайдер выбранного типа. Для пустого типа возвращает nil:
+// погода вносится только вручную.
+func New(providerType, url string) (Provider, error) {
+	switch strings.ToLower(providerType) {
+	case TypeNone:
+		return nil, nil
+	case TypeOpenMeteo:
+		return NewOpenMeteoProvider(url), nil
+	default:
+		return nil, fmt.Errorf("unknown weather provider %q", providerType)
+	}
+}
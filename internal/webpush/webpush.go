@@ -0,0 +1,246 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrSubscriptionGone - подписка отозвана браузером или истекла (404/410 от push-сервиса)
+var ErrSubscriptionGone = errors.New("webpush: subscription is no longer valid")
+
+const (
+	// recordSize - размер записи aes128gcm; сообщение всегда помещается в одну запись
+	recordSize = 4096
+	// MaxPayload - наибольший размер полезной нагрузки до шифрования
+	MaxPayload = 3800
+	// vapidTTL - срок действия подписи VAPID (не более 24 часов по RFC 8292)
+	vapidTTL = 12 * time.Hour
+)
+
+// Urgency - срочность доставки (RFC 8030): push-сервис может откладывать
+// несрочные сообщения на устройствах в режиме экономии энергии
+type Urgency string
+
+const (
+	UrgencyNormal Urgency = "normal"
+	UrgencyHigh   Urgency = "high"
+)
+
+// Subscription - подписка браузера (PushSubscription.toJSON()): адрес push-сервиса
+// и ключи шифрования p256dh и auth в base64url
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Sender - отправка Web Push с подписью VAPID (RFC 8292) и шифрованием aes128gcm (RFC 8291)
+type Sender struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+	client     *http.Client
+}
+
+// New - отправитель с ключами VAPID в base64url (открытый - несжатая точка P-256,
+// закрытый - 32 байта) и контактом subject (mailto: или https:). Без ключей возвращает
+// nil: push-уведомления выключены.
+func New(publicKey, privateKey, subject string) (*Sender, error) {
+	if publicKey == "" && privateKey == "" {
+		return nil, nil
+	}
+	raw, err := decodeKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	public, err := key.PublicKey.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	if expected, err := decodeKey(publicKey); err != nil || !bytes.Equal(expected, public) {
+		return nil, fmt.Errorf("VAPID public key does not match the private key")
+	}
+	if !strings.HasPrefix(subject, "mailto:") && !strings.HasPrefix(subject, "https:") {
+		return nil, fmt.Errorf("VAPID subject must be a mailto: or https: URL")
+	}
+	return &Sender{
+		privateKey: key,
+		publicKey:  base64.RawURLEncoding.EncodeToString(public),
+		subject:    subject,
+		client:     &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// PublicKey - открытый ключ VAPID (applicationServerKey для pushManager.subscribe)
+func (s *Sender) PublicKey() string {
+	return s.publicKey
+}
+
+// Validate - адрес push-сервиса и ключи подписки пригодны для отправки
+func Validate(sub Subscription) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return fmt.Errorf("endpoint must be an https URL")
+	}
+	public, err := decodeKey(sub.P256dh)
+	if err != nil {
+		return fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	if _, err := ecdh.P256().NewPublicKey(public); err != nil {
+		return fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	if auth, err := decodeKey(sub.Auth); err != nil || len(auth) != 16 {
+		return fmt.Errorf("auth secret must be 16 bytes")
+	}
+	return nil
+}
+
+// Send - зашифрованное сообщение в push-сервис подписки. ttl - сколько push-сервис
+// хранит сообщение для браузера без связи.
+func (s *Sender) Send(ctx context.Context, sub Subscription, payload []byte, ttl time.Duration, urgency Urgency) error {
+	if len(payload) > MaxPayload {
+		return fmt.Errorf("payload of %d bytes exceeds %d", len(payload), MaxPayload)
+	}
+	body, err := encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	token, err := s.vapidToken(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build push request: %w", err)
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+s.publicKey)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Urgency", string(urgency))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call push service: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// vapidToken - JWT (ES256) для источника адреса push-сервиса
+func (s *Sender) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint: %w", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidTTL).Unix(),
+		"sub": s.subject,
+	})
+	signed, err := token.SignedString(s.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	return signed, nil
+}
+
+// encrypt - шифрование aes128gcm (RFC 8188) с ключом из ECDH с ключом браузера
+// и секретом auth (RFC 8291)
+func encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	uaPublicRaw, err := decodeKey(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decodeKey(sub.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	shared, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+
+	keyInfo := "WebPush: info\x00" + string(uaPublicRaw) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, shared, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// Разделитель 0x02 - последняя (единственная) запись, без дополнения
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// decodeKey - base64url с дополнением или без (браузеры и генераторы ключей пишут по-разному)
+func decodeKey(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
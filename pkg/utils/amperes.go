@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// amperesPattern - значение тока в паспортных полях РУ: "630 А", "630A", "1250,5 А"
+// (кириллическая или латинская А, единица может быть опущена)
+var amperesPattern = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)\s*(?:А|A|а|a)?\s*$`)
+
+// ParseAmperes - число ампер из строкового поля; false для значений в других единицах
+func ParseAmperes(value string) (float64, bool) {
+	match := amperesPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+	amperes, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return amperes, true
+}
+
+// FormatAmperes - ток в формате строковых полей РУ ("630 А")
+func FormatAmperes(amperes float64) string {
+	return strconv.FormatFloat(amperes, 'f', -1, 64) + " А"
+}
@@ -16,6 +16,17 @@ type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	// Name - отображаемое имя для журнала операций; в старых токенах отсутствует
+	Name string `json:"name,omitempty"`
+	// OrganizationID - организация пользователя; в токенах, выданных до разделения
+	// на организации, отсутствует и означает организацию по умолчанию
+	OrganizationID string `json:"org_id,omitempty"`
+
+	// Работа от имени пользователя: администратор, получивший токен, и текст
+	// баннера, который интерфейс показывает на все время сессии
+	ImpersonatorID    string `json:"imp_by,omitempty"`
+	ImpersonatorEmail string `json:"imp_email,omitempty"`
+	Banner            string `json:"banner,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -40,6 +51,9 @@ func GenerateToken(user *models.User, secret string, ttl time.Duration) (string,
 		UserID: user.ID,
 		Email:  user.Email,
 		Role:   string(user.Role),
+		Name:   user.Name,
+
+		OrganizationID: user.OrganizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -50,6 +64,32 @@ func GenerateToken(user *models.User, secret string, ttl time.Duration) (string,
 	return token.SignedString([]byte(secret))
 }
 
+// GenerateImpersonationToken - короткоживущий токен от имени user, помеченный
+// администратором impersonator и текстом баннера
+func GenerateImpersonationToken(user, impersonator *models.User, banner, secret string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := &Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   string(user.Role),
+		Name:   user.Name,
+
+		OrganizationID:    user.OrganizationID,
+		ImpersonatorID:    impersonator.ID,
+		ImpersonatorEmail: impersonator.Email,
+		Banner:            banner,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secret))
+	return signed, expiresAt, err
+}
+
 // ValidateToken - проверяет и валидирует JWT токен
 func ValidateToken(tokenString, secret string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ComtradeConfig - метаданные осциллограммы из файла конфигурации COMTRADE (.cfg)
+// по IEEE C37.111 редакций 1991, 1999 и 2013.
+type ComtradeConfig struct {
+	StationName   string
+	DeviceID      string
+	RevisionYear  int
+	Analog        []ComtradeChannel
+	Digital       []ComtradeChannel
+	LineFrequency float64
+	SampleRates   []ComtradeSampleRate
+	StartTime     time.Time
+	TriggerTime   time.Time
+	DataFormat    string
+}
+
+// ComtradeChannel - аналоговый или дискретный канал
+type ComtradeChannel struct {
+	Index int
+	Name  string
+	Phase string
+	Unit  string
+}
+
+// ComtradeSampleRate - частота дискретизации до отсчета EndSample включительно
+type ComtradeSampleRate struct {
+	Rate      float64
+	EndSample int64
+}
+
+// SampleCount - общее число отсчетов в записи
+func (c *ComtradeConfig) SampleCount() int64 {
+	if len(c.SampleRates) == 0 {
+		return 0
+	}
+	return c.SampleRates[len(c.SampleRates)-1].EndSample
+}
+
+// comtradeDataFormats - допустимые форматы файла данных
+var comtradeDataFormats = []string{"ASCII", "BINARY", "BINARY32", "FLOAT32"}
+
+// ParseComtradeConfig - разбирает файл конфигурации COMTRADE
+func ParseComtradeConfig(data []byte) (*ComtradeConfig, error) {
+	lines := comtradeLines(data)
+	next := func(what string) ([]string, error) {
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("unexpected end of config: missing %s", what)
+		}
+		fields := strings.Split(lines[0], ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		lines = lines[1:]
+		return fields, nil
+	}
+
+	cfg := &ComtradeConfig{RevisionYear: 1991}
+
+	fields, err := next("station line")
+	if err != nil {
+		return nil, err
+	}
+	cfg.StationName = fields[0]
+	if len(fields) > 1 {
+		cfg.DeviceID = fields[1]
+	}
+	if len(fields) > 2 && fields[2] != "" {
+		if cfg.RevisionYear, err = strconv.Atoi(fields[2]); err != nil {
+			return nil, fmt.Errorf("invalid revision year %q", fields[2])
+		}
+	}
+
+	fields, err = next("channel counts")
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid channel counts line %q", strings.Join(fields, ","))
+	}
+	total, err1 := strconv.Atoi(fields[0])
+	analog, err2 := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(fields[1]), "A"))
+	digital, err3 := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(fields[2]), "D"))
+	if err1 != nil || err2 != nil || err3 != nil || total != analog+digital {
+		return nil, fmt.Errorf("invalid channel counts line %q", strings.Join(fields, ","))
+	}
+
+	for i := 0; i < analog; i++ {
+		if fields, err = next("analog channel"); err != nil {
+			return nil, err
+		}
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("invalid analog channel line %d", i+1)
+		}
+		cfg.Analog = append(cfg.Analog, ComtradeChannel{Index: i + 1, Name: fields[1], Phase: fields[2], Unit: fields[4]})
+	}
+	for i := 0; i < digital; i++ {
+		if fields, err = next("digital channel"); err != nil {
+			return nil, err
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid digital channel line %d", i+1)
+		}
+		channel := ComtradeChannel{Index: i + 1, Name: fields[1]}
+		if len(fields) > 2 && cfg.RevisionYear >= 1999 {
+			channel.Phase = fields[2]
+		}
+		cfg.Digital = append(cfg.Digital, channel)
+	}
+
+	if fields, err = next("line frequency"); err != nil {
+		return nil, err
+	}
+	if cfg.LineFrequency, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return nil, fmt.Errorf("invalid line frequency %q", fields[0])
+	}
+
+	if fields, err = next("sampling rate count"); err != nil {
+		return nil, err
+	}
+	nrates, err := strconv.Atoi(fields[0])
+	if err != nil || nrates < 0 {
+		return nil, fmt.Errorf("invalid sampling rate count %q", fields[0])
+	}
+	// nrates = 0: фиксированной частоты нет, время берется из меток в файле данных
+	for i := 0; i < max(nrates, 1); i++ {
+		if fields, err = next("sampling rate"); err != nil {
+			return nil, err
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid sampling rate line %d", i+1)
+		}
+		rate, err1 := strconv.ParseFloat(fields[0], 64)
+		end, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid sampling rate line %d", i+1)
+		}
+		cfg.SampleRates = append(cfg.SampleRates, ComtradeSampleRate{Rate: rate, EndSample: end})
+	}
+
+	var start, trigger []string
+	if start, err = next("start time"); err != nil {
+		return nil, err
+	}
+	if trigger, err = next("trigger time"); err != nil {
+		return nil, err
+	}
+
+	if fields, err = next("data file type"); err != nil {
+		return nil, err
+	}
+	cfg.DataFormat = strings.ToUpper(fields[0])
+	if !slices.Contains(comtradeDataFormats, cfg.DataFormat) {
+		return nil, fmt.Errorf("unsupported data file type %q", fields[0])
+	}
+
+	// Редакция 2013: после множителя времени идет смещение часового пояса записи
+	loc := time.UTC
+	if cfg.RevisionYear >= 2013 && len(lines) >= 2 {
+		if tz, _ := next("time multiplier"); tz != nil {
+			if tz, _ = next("time code"); tz != nil {
+				loc = comtradeLocation(tz[0])
+			}
+		}
+	}
+
+	if cfg.StartTime, err = parseComtradeTime(start, cfg.RevisionYear, loc); err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+	if cfg.TriggerTime, err = parseComtradeTime(trigger, cfg.RevisionYear, loc); err != nil {
+		return nil, fmt.Errorf("invalid trigger time: %w", err)
+	}
+	return cfg, nil
+}
+
+// SplitComtradeCFF - разделяет однофайловую запись .cff (редакция 2013) на конфигурацию и данные.
+// Секции начинаются заголовками "--- file type: CFG ---", "--- file type: DAT BINARY: <байт> ---" и т.д.
+func SplitComtradeCFF(data []byte) (cfg, dat []byte, err error) {
+	sections := map[string][]byte{}
+	current := ""
+	start := 0
+
+	rest := data
+	offset := 0
+	for len(rest) > 0 {
+		line := rest
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			line = rest[:i+1]
+		}
+		rest = rest[len(line):]
+		lineStart := offset
+		offset += len(line)
+
+		header := strings.ToLower(strings.TrimSpace(string(line)))
+		if !strings.HasPrefix(header, "--- file type:") {
+			continue
+		}
+		if current != "" {
+			sections[current] = data[start:lineStart]
+		}
+
+		kind := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(header, "--- file type:"), "---"))
+		name, format, _ := strings.Cut(kind, " ")
+		current, start = name, offset
+
+		// Двоичная секция данных: длина в байтах указана в заголовке
+		if name == "dat" && !strings.HasPrefix(format, "ascii") {
+			size := len(rest)
+			if _, count, ok := strings.Cut(format, ":"); ok {
+				if n, convErr := strconv.Atoi(strings.TrimSpace(count)); convErr == nil && n <= len(rest) {
+					size = n
+				}
+			}
+			sections["dat"] = rest[:size]
+			rest, offset, current = rest[size:], offset+size, ""
+		}
+	}
+	if current != "" {
+		sections[current] = data[start:]
+	}
+
+	if sections["cfg"] == nil {
+		return nil, nil, fmt.Errorf("CFF file has no CFG section")
+	}
+	if sections["dat"] == nil {
+		return nil, nil, fmt.Errorf("CFF file has no DAT section")
+	}
+	return sections["cfg"], sections["dat"], nil
+}
+
+func comtradeLines(data []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseComtradeTime - дата и время в формате dd/mm/yyyy,hh:mm:ss.ssssss (mm/dd/yy в редакции 1991)
+func parseComtradeTime(fields []string, revision int, loc *time.Location) (time.Time, error) {
+	if len(fields) < 2 {
+		return time.Time{}, fmt.Errorf("expected date and time, got %q", strings.Join(fields, ","))
+	}
+	layout := "02/01/2006"
+	if revision < 1999 {
+		layout = "01/02/06"
+	}
+	return time.ParseInLocation(layout+",15:04:05.999999999", fields[0]+","+fields[1], loc)
+}
+
+// comtradeLocation - смещение часового пояса из time_code: "0", "-5", "+5h30"
+func comtradeLocation(code string) *time.Location {
+	code = strings.TrimSpace(code)
+	if code == "" || strings.EqualFold(code, "x") {
+		return time.UTC
+	}
+	hoursPart, minutesPart, _ := strings.Cut(code, "h")
+	hours, err := strconv.Atoi(hoursPart)
+	if err != nil {
+		return time.UTC
+	}
+	minutes, _ := strconv.Atoi(minutesPart)
+	offset := hours*3600 + minutes*60
+	if strings.HasPrefix(hoursPart, "-") {
+		offset = hours*3600 - minutes*60
+	}
+	return time.FixedZone(code, offset)
+}
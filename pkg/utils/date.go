@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// LegacyDateLayout - формат строковых дат РУ (установка, ТО, осмотр)
+	LegacyDateLayout = "2006-01-02"
+	// LegacyDateTimeLayout - формат строковых отметок времени операций
+	LegacyDateTimeLayout = "02.01.2006 15:04:05"
+)
+
+// dateLayouts - форматы дат, встречающиеся в данных РУ и журнале операций
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"02.01.2006",
+}
+
+// ParseDate - разбирает дату в одном из поддерживаемых форматов
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format: %q", value)
+}
+
+// ParseDatePtr - разбирает необязательную строковую дату; нераспознанные значения дают nil
+func ParseDatePtr(value *string) *time.Time {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	t, err := ParseDate(*value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// FormatPtr - форматирует необязательную дату в строку заданного формата
+func FormatPtr(t *time.Time, layout string) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(layout)
+	return &s
+}
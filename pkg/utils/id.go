@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+)
+
+// crockford - алфавит Crockford Base32, используемый в ULID
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength - длина ULID в символах (48 бит времени + 80 бит случайности)
+const ulidLength = 26
+
+// NewULID - генерирует ULID: лексикографически сортируемый по времени создания идентификатор
+func NewULID(t time.Time) string {
+	var data [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := rand.Read(data[6:]); err != nil {
+		panic("utils: crypto/rand failed: " + err.Error())
+	}
+
+	// 128 бит кодируются 26 символами по 5 бит (первый символ несет 3 бита)
+	var out [ulidLength]byte
+	var acc uint64
+	bits := 0
+	pos := ulidLength - 1
+	for i := len(data) - 1; i >= 0; i-- {
+		acc |= uint64(data[i]) << bits
+		bits += 8
+		for bits >= 5 && pos >= 0 {
+			out[pos] = crockford[acc&31]
+			acc >>= 5
+			bits -= 5
+			pos--
+		}
+	}
+	if pos >= 0 {
+		out[pos] = crockford[acc&31]
+	}
+	return string(out[:])
+}
+
+// NewID - внешний идентификатор вида <prefix>_<ULID>, например op_01HZX3...
+func NewID(prefix string) string {
+	return prefix + "_" + NewULID(time.Now())
+}
+
+// IsULID - является ли строка ULID (без префикса)
+func IsULID(value string) bool {
+	if len(value) != ulidLength {
+		return false
+	}
+	for _, r := range strings.ToUpper(value) {
+		if !strings.ContainsRune(crockford, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeID - приводит идентификатор к хранимому виду: голый ULID дополняется
+// префиксом, а идентификаторы старого формата (UUID) возвращаются без изменений
+func NormalizeID(prefix, value string) string {
+	value = strings.TrimSpace(value)
+	if IsULID(value) {
+		return prefix + "_" + strings.ToUpper(value)
+	}
+	if head, tail, ok := strings.Cut(value, "_"); ok && head == prefix && IsULID(tail) {
+		return prefix + "_" + strings.ToUpper(tail)
+	}
+	return value
+}
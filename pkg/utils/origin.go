@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// MatchOrigin - соответствует ли origin браузера шаблону из настроек CORS.
+// Поддерживаются "*" (любой origin), точное совпадение и поддомены по маске
+// "https://*.example.kz" (сам example.kz маской не покрывается). Порт, если указан
+// в шаблоне, должен совпадать.
+func MatchOrigin(pattern, origin string) bool {
+	pattern = strings.TrimSuffix(strings.TrimSpace(pattern), "/")
+	if pattern == "*" {
+		return true
+	}
+	if strings.EqualFold(pattern, origin) {
+		return true
+	}
+	if !strings.Contains(pattern, "://*.") {
+		return false
+	}
+
+	p, err := url.Parse(strings.Replace(pattern, "://*.", "://wildcard.", 1))
+	if err != nil {
+		return false
+	}
+	o, err := url.Parse(origin)
+	if err != nil || !strings.EqualFold(p.Scheme, o.Scheme) {
+		return false
+	}
+
+	suffix := strings.ToLower(strings.TrimPrefix(p.Host, "wildcard"))
+	host := strings.ToLower(o.Host)
+	return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+}
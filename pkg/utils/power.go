@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// kvaPattern - мощность в паспортных полях: "100 кВА", "1000kVA", "1,6 МВА"
+var kvaPattern = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)\s*(кВА|кВ·А|kVA|МВА|MVA)\s*$`)
+
+// kvPattern - напряжение в паспортных полях: "10 кВ", "0,4 кВ", "6kV"
+var kvPattern = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)\s*(кВ|kV)\s*$`)
+
+// ParseKVA - мощность в кВА из строкового поля; МВА пересчитываются
+func ParseKVA(value string) (float64, bool) {
+	match := kvaPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+	kva, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	if match[2] == "МВА" || match[2] == "MVA" {
+		kva *= 1000
+	}
+	return kva, true
+}
+
+// ParseKilovolts - напряжение в кВ из строкового поля
+func ParseKilovolts(value string) (float64, bool) {
+	match := kvPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, false
+	}
+	kv, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return kv, true
+}